@@ -0,0 +1,18 @@
+package main
+
+import "flag"
+
+// defineAccessFlags defines the flags that limit what the agent may send and
+// see: methods, URLs, request headers and sizes, and response redaction.
+func (o *options) defineAccessFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.readOnly, "read-only", false, "Allow only the safe methods: GET, HEAD, OPTIONS, PROPFIND and REPORT")
+	fs.StringVar(&o.allowMethods, "allow-methods", "", "Comma-separated methods the agent may send (default: all)")
+	fs.StringVar(&o.denyMethods, "deny-methods", "", "Comma-separated methods the agent may never send")
+	fs.StringVar(&o.allowedRequestHeaders, "allowed-request-headers", "", "Comma-separated headers the agent may set, e.g. accept,content-type,x-trace-*; others are dropped with a warning (default: all)")
+	fs.StringVar(&o.policyFile, "policy-file", "", "URL access rules file (lines of \"allow|deny METHOD URL-PATTERN\")")
+	fs.Int64Var(&o.maxRequestSize, "max-request-size", 10*1024*1024, "Maximum request body size in bytes, including multipart files (0 = unlimited)")
+	fs.IntVar(&o.maxRequestHeaders, "max-request-headers", 50, "Maximum number of headers the agent may set per request (0 = unlimited)")
+	fs.IntVar(&o.maxHeaderSize, "max-header-size", 8192, "Maximum size of one request header, name plus value, in bytes (0 = unlimited)")
+	fs.StringVar(&o.redactResponseHeaders, "redact-response-headers", "", "Comma-separated response headers whose values are censored in output, e.g. x-session-token,x-secret-* (Set-Cookie values, challenge tokens and credential headers always are)")
+	fs.BoolVar(&o.showResponseSecrets, "unsafe-show-response-secrets", false, "Show response header values uncensored, Set-Cookie values included (unsafe: the agent sees session secrets)")
+}
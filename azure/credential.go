@@ -0,0 +1,132 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// DefaultAuthorityHost is the Microsoft Entra ID login endpoint of the public cloud.
+	DefaultAuthorityHost = "https://login.microsoftonline.com"
+	// DefaultIMDSEndpoint is the managed identity endpoint of Azure VMs.
+	DefaultIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	// tokenTimeout bounds one token request.
+	tokenTimeout = 30 * time.Second
+)
+
+// Settings selects how tokens are acquired: the client credentials grant
+// with a client secret, or the managed identity of the host.
+type Settings struct {
+	TenantID      string
+	ClientID      string // application ID; with ManagedIdentity, selects a user-assigned identity
+	ClientSecret  string
+	AuthorityHost string // login endpoint, for sovereign clouds; empty means DefaultAuthorityHost
+
+	ManagedIdentity  bool
+	IMDSEndpoint     string // empty means DefaultIMDSEndpoint
+	IdentityEndpoint string // App Service and Functions: IDENTITY_ENDPOINT, used instead of IMDS when set
+	IdentityHeader   string // IDENTITY_HEADER, sent with IdentityEndpoint requests
+}
+
+// TokenSource returns a source of access tokens for scope (a resource such as
+// https://graph.microsoft.com, with or without /.default) that reuses each
+// token until it is about to expire.
+func TokenSource(settings Settings, scope string) oauth2.TokenSource {
+	resource := strings.TrimSuffix(scope, "/.default")
+	if settings.ManagedIdentity {
+		return oauth2.ReuseTokenSource(nil, &managedIdentitySource{settings: settings, resource: resource})
+	}
+	authorityHost := settings.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = DefaultAuthorityHost
+	}
+	config := clientcredentials.Config{
+		ClientID:     settings.ClientID,
+		ClientSecret: settings.ClientSecret,
+		TokenURL:     strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(settings.TenantID) + "/oauth2/v2.0/token",
+		Scopes:       []string{resource + "/.default"},
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: tokenTimeout})
+	return config.TokenSource(ctx)
+}
+
+// managedIdentitySource asks the host's managed identity endpoint for a token.
+type managedIdentitySource struct {
+	settings Settings
+	resource string
+}
+
+func (s *managedIdentitySource) Token() (*oauth2.Token, error) {
+	query := url.Values{"resource": {s.resource}}
+	if s.settings.ClientID != "" {
+		query.Set("client_id", s.settings.ClientID)
+	}
+	endpoint := s.settings.IMDSEndpoint
+	if endpoint == "" {
+		endpoint = DefaultIMDSEndpoint
+	}
+	apiVersion := "2018-02-01"
+	if s.settings.IdentityEndpoint != "" {
+		endpoint, apiVersion = s.settings.IdentityEndpoint, "2019-08-01"
+	}
+	query.Set("api-version", apiVersion)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("managed identity endpoint: %w", err)
+	}
+	if s.settings.IdentityEndpoint != "" {
+		req.Header.Set("X-IDENTITY-HEADER", s.settings.IdentityHeader)
+	} else {
+		req.Header.Set("Metadata", "true")
+	}
+	// The endpoint is link-local or loopback: never route it through a proxy.
+	httpClient := &http.Client{Timeout: tokenTimeout, Transport: &http.Transport{}}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("managed identity endpoint unreachable (not running on Azure?): %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading managed identity token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("managed identity token for %s: %s: %s", s.resource, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string       `json:"access_token"`
+		TokenType   string       `json:"token_type"`
+		ExpiresOn   flexibleTime `json:"expires_on"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return nil, fmt.Errorf("managed identity endpoint returned no access token")
+	}
+	return &oauth2.Token{AccessToken: token.AccessToken, TokenType: token.TokenType, Expiry: time.Time(token.ExpiresOn)}, nil
+}
+
+// flexibleTime reads expires_on, which the endpoints send as Unix seconds in
+// a string or a number.
+type flexibleTime time.Time
+
+func (t *flexibleTime) UnmarshalJSON(data []byte) error {
+	seconds, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("expires_on: %w", err)
+	}
+	*t = flexibleTime(time.Unix(seconds, 0))
+	return nil
+}
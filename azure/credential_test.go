@@ -0,0 +1,103 @@
+package azure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_TokenSource_ClientCredentials(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "app" ||
+			r.Form.Get("client_secret") != "secret" || r.Form.Get("scope") != "https://graph.microsoft.com/.default" {
+			t.Errorf("form = %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "graph-token", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	source := TokenSource(Settings{TenantID: "tenant-1", ClientID: "app", ClientSecret: "secret", AuthorityHost: server.URL + "/"}, "https://graph.microsoft.com")
+	for range 2 {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+		if token.AccessToken != "graph-token" {
+			t.Errorf("AccessToken = %q", token.AccessToken)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("token endpoint called %d times, want 1 (cached)", calls.Load())
+	}
+}
+
+func Test_TokenSource_ManagedIdentity(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name       string
+		settings   Settings
+		wantHeader string
+		wantQuery  map[string]string
+		expiresOn  any
+	}{
+		{"IMDS", Settings{ManagedIdentity: true}, "Metadata",
+			map[string]string{"api-version": "2018-02-01", "resource": "https://vault.azure.net"}, strconv.FormatInt(expiresOn, 10)},
+		{"user-assigned identity", Settings{ManagedIdentity: true, ClientID: "mi-client"}, "Metadata",
+			map[string]string{"client_id": "mi-client"}, expiresOn},
+		{"App Service", Settings{ManagedIdentity: true, IdentityHeader: "secret-header"}, "X-IDENTITY-HEADER",
+			map[string]string{"api-version": "2019-08-01"}, expiresOn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(tt.wantHeader) == "" {
+					t.Errorf("missing %s header", tt.wantHeader)
+				}
+				for key, want := range tt.wantQuery {
+					if got := r.URL.Query().Get(key); got != want {
+						t.Errorf("%s = %q, want %q", key, got, want)
+					}
+				}
+				json.NewEncoder(w).Encode(map[string]any{"access_token": "mi-token", "token_type": "Bearer", "expires_on": tt.expiresOn})
+			}))
+			defer server.Close()
+
+			settings := tt.settings
+			if settings.IdentityHeader != "" {
+				settings.IdentityEndpoint = server.URL
+			} else {
+				settings.IMDSEndpoint = server.URL
+			}
+			token, err := TokenSource(settings, "https://vault.azure.net/.default").Token()
+			if err != nil {
+				t.Fatalf("Token: %v", err)
+			}
+			if token.AccessToken != "mi-token" || token.Expiry.Unix() != expiresOn {
+				t.Errorf("token = %q expiring %v", token.AccessToken, token.Expiry)
+			}
+		})
+	}
+}
+
+func Test_TokenSource_ManagedIdentityError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	_, err := TokenSource(Settings{ManagedIdentity: true, IMDSEndpoint: server.URL}, "https://management.azure.com").Token()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
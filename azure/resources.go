@@ -0,0 +1,54 @@
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Resource maps the hosts matching Match (as in client.HostRule) to the
+// scope of the tokens they accept.
+type Resource struct {
+	Match string
+	Scope string
+}
+
+// DefaultResources cover the common Azure APIs when no resource is configured.
+var DefaultResources = []Resource{
+	{Match: "graph.microsoft.com", Scope: "https://graph.microsoft.com/.default"},
+	{Match: "management.azure.com", Scope: "https://management.azure.com/.default"},
+	{Match: "*.vault.azure.net", Scope: "https://vault.azure.net/.default"},
+	{Match: "*.blob.core.windows.net", Scope: "https://storage.azure.com/.default"},
+	{Match: "*.queue.core.windows.net", Scope: "https://storage.azure.com/.default"},
+}
+
+// ParseResource parses a "HOST-PATTERN=SCOPE" entry such as
+// "*.documents.azure.com=https://cosmos.azure.com/.default".
+func ParseResource(entry string) (Resource, error) {
+	match, scope, found := strings.Cut(entry, "=")
+	match, scope = strings.TrimSpace(match), strings.TrimSpace(scope)
+	if !found || match == "" || scope == "" {
+		return Resource{}, fmt.Errorf("%q is not in HOST-PATTERN=SCOPE format", entry)
+	}
+	return Resource{Match: match, Scope: scope}, nil
+}
+
+// HostTokenSources builds one token source per distinct scope, so every host
+// of a resource shares its cached token, in the order of resources.
+func HostTokenSources(settings Settings, resources []Resource) []client.HostTokenSource {
+	sources := make(map[string]oauth2.TokenSource)
+	hostSources := make([]client.HostTokenSource, 0, len(resources))
+	for _, resource := range resources {
+		scope := strings.TrimSuffix(resource.Scope, "/.default")
+		source, ok := sources[scope]
+		if !ok {
+			source = TokenSource(settings, scope)
+			sources[scope] = source
+		}
+		hostSources = append(hostSources, client.HostTokenSource{Match: resource.Match, Source: source})
+	}
+	return hostSources
+}
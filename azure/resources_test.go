@@ -0,0 +1,43 @@
+package azure
+
+import (
+	"testing"
+)
+
+func Test_ParseResource(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    Resource
+		wantErr bool
+	}{
+		{"*.documents.azure.com=https://cosmos.azure.com/.default", Resource{Match: "*.documents.azure.com", Scope: "https://cosmos.azure.com/.default"}, false},
+		{" api.example.com = api://my-app ", Resource{Match: "api.example.com", Scope: "api://my-app"}, false},
+		{"graph.microsoft.com", Resource{}, true},
+		{"=https://graph.microsoft.com", Resource{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := ParseResource(tt.entry)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseResource(%q) = %+v, %v", tt.entry, got, err)
+			}
+		})
+	}
+}
+
+func Test_HostTokenSources_SharesSourcePerScope(t *testing.T) {
+	sources := HostTokenSources(Settings{ManagedIdentity: true}, DefaultResources)
+	if len(sources) != len(DefaultResources) {
+		t.Fatalf("got %d sources, want %d", len(sources), len(DefaultResources))
+	}
+	bySource := make(map[string]any)
+	for _, source := range sources {
+		bySource[source.Match] = source.Source
+	}
+	if bySource["*.blob.core.windows.net"] != bySource["*.queue.core.windows.net"] {
+		t.Error("storage hosts should share one token source")
+	}
+	if bySource["graph.microsoft.com"] == bySource["management.azure.com"] {
+		t.Error("different scopes should not share a token source")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+
+	"github.com/lexandro/rest-api-mcp/azure"
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// defineAzureFlags defines the --azure-* flags.
+func (o *options) defineAzureFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.azureTenantID, "azure-tenant-id", "", "Microsoft Entra tenant for Azure client credentials (default: AZURE_TENANT_ID)")
+	fs.StringVar(&o.azureClientID, "azure-client-id", "", "Azure application (client) ID, or the user-assigned managed identity (default: AZURE_CLIENT_ID)")
+	fs.StringVar(&o.azureClientSecret, "azure-client-secret", "", "Azure client secret; with the tenant and client ID, requests to Azure hosts get a token (default: AZURE_CLIENT_SECRET)")
+	fs.BoolVar(&o.azureManagedIdentity, "azure-managed-identity", false, "Get Azure tokens from the host's managed identity instead of a client secret")
+	fs.StringVar(&o.azureAuthorityHost, "azure-authority-host", "", "Microsoft Entra login endpoint, for sovereign clouds (default: AZURE_AUTHORITY_HOST, else https://login.microsoftonline.com)")
+	fs.Var(&o.azureResources, "azure-resource", "Host pattern and token scope for Azure tokens (repeatable, format: \"*.example.azure.com=https://example.azure.com/.default\"; default: Graph, Resource Manager, Key Vault and Storage)")
+}
+
+// azureEnabled reports whether Azure tokens are configured: a managed
+// identity, or a client secret with its tenant and client ID.
+func (o *options) azureEnabled() bool {
+	return o.azureManagedIdentity || o.azureClientSecret != ""
+}
+
+// azureTokenSources returns the per-host Azure token sources, or nil when
+// Azure tokens are not configured. Resource errors are reported by problems().
+func (o *options) azureTokenSources() []client.HostTokenSource {
+	if !o.azureEnabled() {
+		return nil
+	}
+	resources := azure.DefaultResources
+	if len(o.azureResources) > 0 {
+		resources = nil
+		for _, entry := range o.azureResources {
+			if resource, err := azure.ParseResource(entry); err == nil {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	settings := azure.Settings{
+		TenantID:         o.azureTenantID,
+		ClientID:         o.azureClientID,
+		ClientSecret:     o.azureClientSecret,
+		AuthorityHost:    o.azureAuthorityHost,
+		ManagedIdentity:  o.azureManagedIdentity,
+		IdentityEndpoint: o.azureIdentityEndpoint,
+		IdentityHeader:   o.azureIdentityHeader,
+	}
+	return azure.HostTokenSources(settings, resources)
+}
+
+// azureProblems checks that the --azure-* flags select one usable credential.
+func (o *options) azureProblems() []string {
+	var problems []string
+	for _, entry := range o.azureResources {
+		if _, err := azure.ParseResource(entry); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid --azure-resource: %s", err))
+		}
+	}
+	if o.azureAuthorityHost != "" {
+		if parsed, err := url.Parse(o.azureAuthorityHost); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("--azure-authority-host %q is not an https:// URL", o.azureAuthorityHost))
+		}
+	}
+	switch {
+	case o.azureManagedIdentity && o.azureClientSecret != "":
+		problems = append(problems, "--azure-managed-identity and --azure-client-secret are mutually exclusive")
+	case o.azureClientSecret != "" && (o.azureTenantID == "" || o.azureClientID == ""):
+		problems = append(problems, "--azure-client-secret needs --azure-tenant-id and --azure-client-id")
+	case !o.azureEnabled() && len(o.azureResources) > 0:
+		problems = append(problems, "--azure-resource needs --azure-client-secret or --azure-managed-identity")
+	}
+	return problems
+}
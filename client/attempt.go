@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// doSingleAttempt sends one request and reads (or saves) the response body.
+// Retries, timeouts and redirect policy are handled by ExecuteRequest, which
+// reads the attempt's connection use from connection once it returns.
+func (c *Client) doSingleAttempt(ctx context.Context, httpClient *http.Client, method, requestURL string, defaultHeaders map[string]string, params RequestParams, connection *connectionTracker) (*Response, error) {
+	if simulated := c.takeSimulatedAuthFailure(); simulated != nil {
+		simulated.RequestURL = requestURL
+		return simulated, nil
+	}
+	if injected, err := c.chaos.injectChaos(ctx, requestURL); injected != nil || err != nil {
+		return injected, err
+	}
+
+	paceWait, err := c.pacer.wait(ctx, requestURL, params.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("pausing for the rate limit of %s (--pace-rate-limits): %w", requestURL, err)
+	}
+	queueWait, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting %s for a free request slot (--max-concurrent-requests): %w", queueWait.Round(time.Millisecond), err)
+	}
+	defer c.limiter.release()
+
+	// Rebuilt on every attempt because the body reader is consumed by the request.
+	req, err := newHTTPRequest(ctx, method, requestURL, defaultHeaders, params)
+	if err != nil {
+		return nil, err
+	}
+	if !removesHeader(params.Headers, "Authorization") {
+		if err := c.authorize(req); err != nil {
+			return nil, err
+		}
+	}
+	if c.dropExpect {
+		req.Header.Del("Expect")
+	}
+	req.Body = c.throttle(ctx, req.Body, params)
+	req = connection.trace(req)
+
+	// Per-attempt client copy: the redirect chain and wire dump belong to this attempt.
+	attemptClient := *httpClient
+	redirects := &redirectRecorder{
+		injected:    defaultHeaders,
+		defaultsFor: func(requestURL string) map[string]string { return c.settingsFor(requestURL).defaultHeaders },
+	}
+	attemptClient.CheckRedirect = redirects.checkRedirect(httpClient.CheckRedirect, params.MaxRedirects)
+	var dump *wireDump
+	if params.Verbose {
+		dump = &wireDump{redactResponse: params.RedactResponse}
+		attemptClient.Transport = dump.transport(httpClient.Transport)
+	}
+	httpClient = &attemptClient
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		return nil, fmt.Errorf("executing %s %s: %w", method, requestURL, err)
+	}
+	// Every path below closes the body, and closing drains it, so the
+	// connection goes back to the pool; the deferred Close covers the rest.
+	resp.Body = c.throttle(ctx, connection.track(resp.Body), params)
+	defer resp.Body.Close()
+
+	response := &Response{
+		RequestURL:  requestURL,
+		StatusCode:  resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		Headers:     resp.Header,
+		Proto:       resp.Proto,
+		ContentType: resp.Header.Get("Content-Type"),
+		Duration:    duration,
+		QueueWait:   queueWait,
+		PaceWait:    paceWait,
+	}
+	c.pacer.observe(resp.Request.URL.Host, resp.Header)
+	response.RequestMethod = req.Method
+	response.RequestHeaders = sentHeaders(req)
+	response.FinalURL = resp.Request.URL.String()
+	response.Redirects, response.RedirectLimitReached = redirects.result()
+	if dump != nil {
+		response.WireLog = dump.String()
+	}
+
+	// net/http fills resp.Trailer only once the body has been read to the end.
+	defer func() { response.Trailers = resp.Trailer }()
+
+	// Error responses (4xx/5xx) are small and informative — return them inline
+	// even when SaveTo is set, so the agent sees what went wrong.
+	if params.SaveTo != "" && resp.StatusCode < 400 {
+		resume := c.newRangeRequester(ctx, httpClient, method, requestURL, defaultHeaders, params)
+		savedSize, savedHash, saveErr := saveResponseBody(resp, params.SaveTo, params.Progress, resume)
+		if saveErr != nil {
+			return nil, saveErr
+		}
+		response.SavedPath = params.SaveTo
+		response.SavedSize = savedSize
+		response.SavedSHA256 = savedHash
+		return response, nil
+	}
+
+	maxResponseSize := c.maxResponseSize
+	if params.MaxResponseSize > 0 {
+		maxResponseSize = params.MaxResponseSize
+	}
+	if c.spillThreshold > 0 {
+		if err := readOrSpillResponseBody(resp, maxResponseSize, c.spillThreshold, c.spillDir, params.Progress, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+	body, truncated, originalSize, readErr := readResponseBody(resp, maxResponseSize)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	response.Body = body
+	response.Truncated = truncated
+	response.OriginalSize = originalSize
+	return response, nil
+}
+
+// newHTTPRequest builds the request as it goes on the wire: body (multipart
+// when files or form fields are given), default headers, then per-request
+// headers, which win; an empty per-request value removes the header. A Host
+// header becomes req.Host.
+func newHTTPRequest(ctx context.Context, method, requestURL string, defaultHeaders map[string]string, params RequestParams) (*http.Request, error) {
+	var bodyReader io.Reader
+	var multipartContentType string
+	if len(params.Files) > 0 || len(params.FormFields) > 0 {
+		body, contentType, err := buildMultipartBody(params.Files, params.FormFields)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = body
+		multipartContentType = contentType
+	} else if params.Body != "" {
+		bodyReader = strings.NewReader(params.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("creating request %s %s: %w", method, requestURL, err)
+	}
+
+	for key, value := range defaultHeaders {
+		req.Header.Set(key, value)
+	}
+	for key, value := range params.Headers {
+		if value == "" {
+			req.Header.Del(key) // an empty value removes a default header for this request
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+	if multipartContentType != "" {
+		req.Header.Set("Content-Type", multipartContentType)
+	}
+	// net/http ignores a Host entry in the header map; only req.Host is sent.
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+	return req, nil
+}
+
+// sentHeaders returns the request's headers including a Host override, as
+// shown in echoes, dry runs and history.
+func sentHeaders(req *http.Request) http.Header {
+	headers := req.Header.Clone()
+	if req.Host != "" && req.Host != req.URL.Host {
+		headers.Set("Host", req.Host)
+	}
+	return headers
+}
+
+// removesHeader reports whether the per-request headers remove name (an empty
+// value), which also keeps the OAuth2 token from being added.
+func removesHeader(headers map[string]string, name string) bool {
+	for key, value := range headers {
+		if value == "" && strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
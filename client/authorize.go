@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// authorize sets "Authorization: Bearer" from the token source for the
+// request's URL, unless the request already carries an Authorization header
+// (from the agent, default headers or a host rule). The token source refreshes
+// the access token when it has expired, so every attempt, retries included,
+// gets a valid one.
+func (c *Client) authorize(req *http.Request) error {
+	source := c.tokenSourceFor(req.URL.String())
+	if source == nil || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+	token, err := source.Token()
+	if err != nil {
+		return fmt.Errorf("obtaining access token: %w", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+// tokenSourceFor returns the first host token source matching requestURL,
+// else the client-wide one (nil without either).
+func (c *Client) tokenSourceFor(requestURL string) oauth2.TokenSource {
+	for _, hostSource := range c.hostTokenSources {
+		if (HostRule{Match: hostSource.Match}).matches(requestURL) {
+			return hostSource.Source
+		}
+	}
+	return c.tokenSource
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type failingTokenSource struct{}
+
+func (failingTokenSource) Token() (*oauth2.Token, error) {
+	return nil, errors.New("token endpoint unavailable")
+}
+
+func Test_ExecuteRequest_TokenSource(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		source  oauth2.TokenSource
+		headers map[string]string
+		want    string
+		wantErr bool
+	}{
+		{"bearer from source", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "abc"}), nil, "Bearer abc", false},
+		{"explicit header wins", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "abc"}), map[string]string{"Authorization": "Basic xyz"}, "Basic xyz", false},
+		{"empty header removes the token", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "abc"}), map[string]string{"authorization": ""}, "", false},
+		{"no source", nil, nil, "", false},
+		{"source error", failingTokenSource{}, nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			c := NewClient(Config{Timeout: 5 * time.Second, TokenSource: tt.source})
+			_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL, Headers: tt.headers})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if received != tt.want {
+				t.Errorf("Authorization = %q, want %q", received, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_EmptyHeaderRemovesDefault(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:        5 * time.Second,
+		DefaultHeaders: map[string]string{"Authorization": "Bearer internal", "X-Tenant": "acme"},
+	})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:  "GET",
+		URL:     server.URL,
+		Headers: map[string]string{"authorization": ""},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if _, sent := received["Authorization"]; sent {
+		t.Errorf("Authorization was sent: %v", received)
+	}
+	if received.Get("X-Tenant") != "acme" {
+		t.Errorf("other default headers must stay, got %v", received)
+	}
+	if _, listed := resp.RequestHeaders["Authorization"]; listed {
+		t.Errorf("RequestHeaders still lists Authorization: %v", resp.RequestHeaders)
+	}
+}
+
+func Test_ExecuteRequest_HostTokenSources(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+	global := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "global"})
+	scoped := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "scoped"})
+
+	tests := []struct {
+		name    string
+		sources []HostTokenSource
+		want    string
+	}{
+		{"matching host source wins", []HostTokenSource{{Match: "127.0.0.1", Source: scoped}}, "Bearer scoped"},
+		{"URL prefix match", []HostTokenSource{{Match: server.URL + "/", Source: scoped}}, "Bearer scoped"},
+		{"other hosts fall back to the client-wide source", []HostTokenSource{{Match: "graph.microsoft.com", Source: scoped}}, "Bearer global"},
+		{"URL prefix of another port", []HostTokenSource{{Match: "http://127.0.0.1:1", Source: scoped}}, "Bearer global"},
+		{"URL prefix of another path", []HostTokenSource{{Match: server.URL + "/v2", Source: scoped}}, "Bearer global"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(Config{Timeout: 5 * time.Second, TokenSource: global, HostTokenSources: tt.sources})
+			if _, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/"}); err != nil {
+				t.Fatalf("ExecuteRequest: %v", err)
+			}
+			if received != tt.want {
+				t.Errorf("Authorization = %q, want %q", received, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// bandwidthBurst is how far a limiter lets a transfer catch up after an
+	// idle spell, so pacing stays smooth without throttling short requests.
+	bandwidthBurst = 100 * time.Millisecond
+	// throttleChunk caps one read so the pacing is fine-grained.
+	throttleChunk = 16 << 10
+)
+
+// bandwidthLimiter paces bytes to a rate. The client's limiter is shared by
+// every request, so concurrent transfers split the bandwidth between them.
+type bandwidthLimiter struct {
+	rate int64 // bytes per second
+
+	mu   sync.Mutex
+	next time.Time // when the bytes granted so far are through at rate
+}
+
+// newBandwidthLimiter returns nil (no limit) when rate is not positive.
+func newBandwidthLimiter(rate int64) *bandwidthLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &bandwidthLimiter{rate: rate}
+}
+
+// wait blocks until n more bytes fit the rate. A nil limiter never waits.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now.Add(-bandwidthBurst)) {
+		l.next = now.Add(-bandwidthBurst)
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttle paces body through the client's limiter and the request's own
+// (RequestParams.MaxBandwidth); the slower one sets the pace. body is
+// returned as is when neither limits it.
+func (c *Client) throttle(ctx context.Context, body io.ReadCloser, params RequestParams) io.ReadCloser {
+	var limiters []*bandwidthLimiter
+	if c.bandwidth != nil {
+		limiters = append(limiters, c.bandwidth)
+	}
+	if params.MaxBandwidth > 0 {
+		limiters = append(limiters, newBandwidthLimiter(params.MaxBandwidth))
+	}
+	if body == nil || body == http.NoBody || len(limiters) == 0 {
+		return body
+	}
+	return &throttledBody{ReadCloser: body, ctx: ctx, limiters: limiters}
+}
+
+type throttledBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*bandwidthLimiter
+}
+
+func (t *throttledBody) Read(buffer []byte) (int, error) {
+	if len(buffer) > throttleChunk {
+		buffer = buffer[:throttleChunk]
+	}
+	n, err := t.ReadCloser.Read(buffer)
+	for _, limiter := range t.limiters {
+		if waitErr := limiter.wait(t.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_bandwidthLimiter_PacesToRate(t *testing.T) {
+	limiter := newBandwidthLimiter(100_000)
+	started := time.Now()
+	for range 10 {
+		if err := limiter.wait(context.Background(), 5_000); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 50 KB at 100 KB/s is 500ms, less the 100ms burst allowance.
+	if elapsed := time.Since(started); elapsed < 350*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("50 KB at 100 KB/s took %s", elapsed)
+	}
+}
+
+func Test_bandwidthLimiter_WaitStopsOnCancel(t *testing.T) {
+	limiter := newBandwidthLimiter(1_000)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, 10_000); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait = %v, want the deadline", err)
+	}
+}
+
+func Test_ExecuteRequest_MaxBandwidthThrottlesBodies(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 40_000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := io.ReadAll(r.Body)
+		w.Write(received)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1 << 20})
+	started := time.Now()
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "POST", URL: server.URL, Body: string(payload), MaxBandwidth: 100_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Body) != len(payload) {
+		t.Fatalf("echoed %d bytes, want %d", len(resp.Body), len(payload))
+	}
+	// 40 KB each way at 100 KB/s: about 300ms per direction after the burst.
+	if elapsed := time.Since(started); elapsed < 500*time.Millisecond {
+		t.Errorf("request took %s, want the bodies throttled", elapsed)
+	}
+
+	unlimited := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1 << 20})
+	started = time.Now()
+	if _, err := unlimited.ExecuteRequest(context.Background(), RequestParams{Method: "POST", URL: server.URL, Body: strings.Repeat("y", 40_000)}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > 300*time.Millisecond {
+		t.Errorf("unthrottled request took %s", elapsed)
+	}
+}
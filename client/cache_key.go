@@ -0,0 +1,53 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// variantHeaders are the request headers that select a different response
+// besides the sensitive ones: the caller's identity and content negotiation.
+var variantHeaders = []string{"Cookie", "Accept", "Accept-Language"}
+
+// path names the cache file of the request's URL and variant, so responses
+// fetched with different credentials or Accept headers never overwrite each
+// other.
+func (rc *responseCache) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(cacheURL(req) + "\x00" + variantKey(req)))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheURL is the request URL plus a Host override, which selects a
+// different virtual host behind the same address.
+func cacheURL(req *http.Request) string {
+	if req.Host != "" && req.Host != req.URL.Host {
+		return req.URL.String() + " (Host: " + req.Host + ")"
+	}
+	return req.URL.String()
+}
+
+// variantKey hashes the sensitive request headers (Authorization, API keys,
+// ...) and the variantHeaders, so the cache file never contains them.
+func variantKey(req *http.Request) string {
+	var names []string
+	for name := range req.Header {
+		if IsSensitiveHeader(name) || slices.Contains(variantHeaders, http.CanonicalHeaderKey(name)) {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name + ": " + strings.Join(req.Header.Values(name), ", ") + "\x00")
+	}
+	sum := sha256.Sum256([]byte(key.String()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNoCassette is returned in replay mode when no recorded interaction matches a request.
+var ErrNoCassette = errors.New("no recorded response matches this request")
+
+// replayMatchFields are the request parts ReplayMatch may name.
+var replayMatchFields = map[string]bool{"method": true, "url": true, "path": true, "body": true}
+
+// maxCassetteBody is the most of a request or response body a cassette keeps;
+// the rest of a larger body streams through unrecorded.
+const maxCassetteBody = 10 << 20
+
+// unsafeFileNameChars are replaced in cassette file names.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// cassette is one recorded request/response pair, stored as a JSON file.
+type cassette struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"` // sensitive query values redacted
+	Headers       http.Header `json:"headers,omitempty"`
+	Body          string      `json:"body,omitempty"`
+	BodyBase64    string      `json:"bodyBase64,omitempty"`
+	BodyTruncated bool        `json:"bodyTruncated,omitempty"` // only the first maxCassetteBody bytes were kept
+}
+
+type cassetteResponse struct {
+	StatusCode    int         `json:"status"`
+	Headers       http.Header `json:"headers,omitempty"`
+	Body          string      `json:"body,omitempty"`
+	BodyBase64    string      `json:"bodyBase64,omitempty"`
+	BodyTruncated bool        `json:"bodyTruncated,omitempty"`
+}
+
+// ParseReplayMatch splits a comma-separated list of request parts (method,
+// url, path, body) that must be equal for a recorded response to be replayed.
+func ParseReplayMatch(list string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !replayMatchFields[field] {
+			return nil, fmt.Errorf("unknown replay match field %q (expected method, url, path or body)", field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// cassetteStore records interactions to recordDir or serves them from
+// replayDir. Replayed interactions with the same match key are served in
+// recording order; the last one repeats once they are used up.
+type cassetteStore struct {
+	recordDir string
+	replayDir string
+	match     []string
+
+	loadOnce  sync.Once
+	loadErr   error
+	mu        sync.Mutex
+	recorded  map[string][]cassette
+	nextIndex map[string]int
+}
+
+func newCassetteStore(recordDir, replayDir string, match []string) *cassetteStore {
+	if recordDir == "" && replayDir == "" {
+		return nil
+	}
+	if len(match) == 0 {
+		match = []string{"method", "url"}
+	}
+	return &cassetteStore{recordDir: recordDir, replayDir: replayDir, match: match, nextIndex: make(map[string]int)}
+}
+
+// transport wraps next so every round trip (retries and redirect hops
+// included) is recorded or replayed.
+func (s *cassetteStore) transport(next http.RoundTripper) http.RoundTripper {
+	return cassetteTransport{store: s, next: next}
+}
+
+type cassetteTransport struct {
+	store *cassetteStore
+	next  http.RoundTripper
+}
+
+func (t cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		if requestBody, req.Body, err = teeCassetteBody(req.Body); err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+
+	if t.store.replayDir != "" {
+		return t.store.replay(req, requestBody)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, body, err := teeCassetteBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body for recording: %w", err)
+	}
+	resp.Body = body
+
+	if err := t.store.record(req, requestBody, resp, responseBody); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// record writes one interaction with its secrets redacted as in wire dumps:
+// credentials, cookies and sensitive query values. The files are private to
+// the user, like the token store.
+func (s *cassetteStore) record(req *http.Request, requestBody []byte, resp *http.Response, responseBody []byte) error {
+	entry := cassette{
+		Request:  cassetteRequest{Method: req.Method, URL: RedactURL(req.URL.String()), Headers: redactCassetteHeaders(req.Header)},
+		Response: cassetteResponse{StatusCode: resp.StatusCode, Headers: redactCassetteHeaders(resp.Header)},
+	}
+	if len(requestBody) > maxCassetteBody {
+		requestBody, entry.Request.BodyTruncated = requestBody[:maxCassetteBody], true
+	}
+	if len(responseBody) > maxCassetteBody {
+		responseBody, entry.Response.BodyTruncated = responseBody[:maxCassetteBody], true
+	}
+	entry.Request.Body, entry.Request.BodyBase64 = encodeCassetteBody(requestBody)
+	entry.Response.Body, entry.Response.BodyBase64 = encodeCassetteBody(responseBody)
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(s.recordDir, 0o700); err != nil {
+		return fmt.Errorf("creating cassette directory: %w", err)
+	}
+
+	// O_EXCL picks the next free sequence number, also when several clients
+	// (profiles, named APIs) record into the same directory.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, _ := filepath.Glob(filepath.Join(s.recordDir, "*.json"))
+	name := unsafeFileNameChars.ReplaceAllString(req.Method+"-"+req.URL.Host+req.URL.Path, "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	for sequence := len(existing) + 1; ; sequence++ {
+		path := filepath.Join(s.recordDir, fmt.Sprintf("%04d-%s.json", sequence, name))
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("writing cassette: %w", err)
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing cassette %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func redactCassetteHeaders(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		for _, value := range values {
+			redacted[name] = append(redacted[name], redactWireHeader(name, value))
+		}
+	}
+	return redacted
+}
+
+func (s *cassetteStore) replay(req *http.Request, requestBody []byte) (*http.Response, error) {
+	s.loadOnce.Do(func() { s.loadErr = s.load() })
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+
+	key := s.matchKey(req.Method, req.URL, requestBody)
+	s.mu.Lock()
+	candidates := s.recorded[key]
+	index := min(s.nextIndex[key], len(candidates)-1)
+	s.nextIndex[key]++
+	s.mu.Unlock()
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w in %s (matching on %s): %s %s", ErrNoCassette, s.replayDir, strings.Join(s.match, ", "), req.Method, req.URL)
+	}
+
+	recorded := candidates[index].Response
+	body, err := decodeCassetteBody(recorded.Body, recorded.BodyBase64)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// load reads every cassette in replayDir, in file name (recording) order.
+func (s *cassetteStore) load() error {
+	paths, err := filepath.Glob(filepath.Join(s.replayDir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("listing cassettes: %w", err)
+	}
+	sort.Strings(paths)
+
+	s.recorded = make(map[string][]cassette)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading cassette: %w", err)
+		}
+		var entry cassette
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		recordedURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		requestBody, err := decodeCassetteBody(entry.Request.Body, entry.Request.BodyBase64)
+		if err != nil {
+			return fmt.Errorf("parsing cassette %s: %w", path, err)
+		}
+		key := s.matchKey(entry.Request.Method, recordedURL, requestBody)
+		s.recorded[key] = append(s.recorded[key], entry)
+	}
+	return nil
+}
+
+// matchKey joins the request parts named by the match fields.
+func (s *cassetteStore) matchKey(method string, requestURL *url.URL, body []byte) string {
+	parts := make([]string, 0, len(s.match))
+	for _, field := range s.match {
+		switch field {
+		case "method":
+			parts = append(parts, method)
+		case "url":
+			parts = append(parts, RedactURL(requestURL.String())) // as recorded
+		case "path":
+			parts = append(parts, requestURL.Scheme+"://"+requestURL.Host+requestURL.Path)
+		case "body":
+			parts = append(parts, string(body[:min(len(body), maxCassetteBody)])) // as recorded
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
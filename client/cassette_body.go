@@ -0,0 +1,47 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// teeCassetteBody reads the part of body a cassette keeps: all of it, or
+// maxCassetteBody+1 bytes of a larger one. The returned body yields everything,
+// so a large upload or download streams on instead of being held in memory.
+func teeCassetteBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	head, err := io.ReadAll(io.LimitReader(body, maxCassetteBody+1))
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+	if len(head) > maxCassetteBody {
+		return head, struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head), body), body}, nil
+	}
+	body.Close()
+	return head, io.NopCloser(bytes.NewReader(head)), nil
+}
+
+// encodeCassetteBody keeps text bodies readable and base64-encodes binary ones.
+func encodeCassetteBody(body []byte) (text, encoded string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(body)
+}
+
+func decodeCassetteBody(text, encoded string) ([]byte, error) {
+	if encoded == "" {
+		return []byte(text), nil
+	}
+	body, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cassette body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ParseReplayMatch(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    string
+		wantErr bool
+	}{
+		{"method,url", "method url", false},
+		{" Method , PATH ,body", "method path body", false},
+		{"", "", false},
+		{"method,headers", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			fields, err := ParseReplayMatch(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReplayMatch(%q) error = %v, wantErr %v", tt.list, err, tt.wantErr)
+			}
+			if got := strings.Join(fields, " "); !tt.wantErr && got != tt.want {
+				t.Errorf("ParseReplayMatch(%q) = %q, want %q", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_RecordThenReplay(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"call":%d}`, count)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewClient(Config{Timeout: 5 * time.Second, RecordDir: dir, DefaultHeaders: map[string]string{"Authorization": "Bearer secret"}})
+	for range 2 {
+		if _, err := recorder.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/items?page=1"}); err != nil {
+			t.Fatalf("recording: %v", err)
+		}
+	}
+
+	cassettes, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(cassettes) != 2 {
+		t.Fatalf("expected 2 cassettes, got %v", cassettes)
+	}
+	data, _ := os.ReadFile(cassettes[0])
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("sensitive header recorded in cassette:\n%s", data)
+	}
+
+	server.Close()
+	player := NewClient(Config{Timeout: 5 * time.Second, ReplayDir: dir})
+	for _, want := range []string{`{"call":1}`, `{"call":2}`, `{"call":2}`} {
+		resp, err := player.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/items?page=1"})
+		if err != nil {
+			t.Fatalf("replaying: %v", err)
+		}
+		if string(resp.Body) != want || resp.ContentType != "application/json" {
+			t.Errorf("expected replayed %s, got %s (%s)", want, resp.Body, resp.ContentType)
+		}
+	}
+
+	_, err := player.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/items?page=2"})
+	if !errors.Is(err, ErrNoCassette) {
+		t.Errorf("expected ErrNoCassette for an unrecorded URL, got %v", err)
+	}
+
+	pathOnly := NewClient(Config{Timeout: 5 * time.Second, ReplayDir: dir, ReplayMatch: []string{"method", "path"}})
+	if _, err := pathOnly.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/items?page=2"}); err != nil {
+		t.Errorf("expected path matching to ignore the query, got %v", err)
+	}
+}
+
+func Test_ExecuteRequest_RecordRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "server-secret"})
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "cassettes")
+	recorder := NewClient(Config{Timeout: 5 * time.Second, RecordDir: dir})
+	target := server.URL + "/items?api_key=query-secret&page=1"
+	if _, err := recorder.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: target, Headers: map[string]string{"Cookie": "session=cookie-secret"}}); err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	cassettes, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(cassettes) != 1 {
+		t.Fatalf("expected 1 cassette, got %v", cassettes)
+	}
+	data, _ := os.ReadFile(cassettes[0])
+	for _, secret := range []string{"query-secret", "cookie-secret", "server-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("%s recorded in cassette:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "api_key=***") {
+		t.Errorf("expected the redacted URL recorded, got:\n%s", data)
+	}
+	if runtime.GOOS != "windows" {
+		if info, _ := os.Stat(dir); info.Mode().Perm() != 0o700 {
+			t.Errorf("expected the cassette directory 0700, got %v", info.Mode().Perm())
+		}
+		if info, _ := os.Stat(cassettes[0]); info.Mode().Perm() != 0o600 {
+			t.Errorf("expected the cassette 0600, got %v", info.Mode().Perm())
+		}
+	}
+
+	server.Close()
+	player := NewClient(Config{Timeout: 5 * time.Second, ReplayDir: dir})
+	resp, err := player.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: target})
+	if err != nil || string(resp.Body) != "ok" {
+		t.Fatalf("expected the redacted URL to match on replay, got %+v, %v", resp, err)
+	}
+}
+
+func Test_ExecuteRequest_RecordTruncatesLargeBody(t *testing.T) {
+	large := strings.Repeat("a", maxCassetteBody+10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, large)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewClient(Config{Timeout: 5 * time.Second, RecordDir: dir, MaxResponseSize: 2 * maxCassetteBody})
+	resp, err := recorder.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Body) != len(large) {
+		t.Errorf("expected the whole body passed through, got %d bytes", len(resp.Body))
+	}
+	cassettes, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(cassettes) != 1 {
+		t.Fatalf("expected 1 cassette, got %v", cassettes)
+	}
+	data, _ := os.ReadFile(cassettes[0])
+	if !strings.Contains(string(data), `"bodyTruncated": true`) || len(data) > maxCassetteBody+1024 {
+		t.Errorf("expected a truncated cassette body, got %d bytes", len(data))
+	}
+}
+
+func Test_ExecuteRequest_ReplayMatchesBody(t *testing.T) {
+	dir := t.TempDir()
+	cassette := `{"request":{"method":"POST","url":"http://api.test/orders","body":"{\"id\":1}"},"response":{"status":201,"body":"created"}}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-POST.json"), []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	player := NewClient(Config{ReplayDir: dir, ReplayMatch: []string{"method", "url", "body"}, RetryCount: 2})
+
+	resp, err := player.ExecuteRequest(context.Background(), RequestParams{Method: "POST", URL: "http://api.test/orders", Body: `{"id":1}`})
+	if err != nil || resp.StatusCode != 201 || string(resp.Body) != "created" {
+		t.Fatalf("expected replayed 201, got %+v, %v", resp, err)
+	}
+	if _, err := player.ExecuteRequest(context.Background(), RequestParams{Method: "POST", URL: "http://api.test/orders", Body: `{"id":2}`}); !errors.Is(err, ErrNoCassette) {
+		t.Errorf("expected a different body to miss, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// ErrChaosInjected is the transport error returned for injected failures when
+// ChaosConfig.ErrorStatus is 0.
+var ErrChaosInjected = errors.New("connection reset (injected by --chaos-error-rate)")
+
+const chaosErrorBody = `{"error":"chaos_injected","error_description":"Failure injected by rest-api-mcp chaos testing"}`
+
+// ChaosConfig injects latency and failures into requests to test how agent
+// workflows cope with slow or failing APIs. The zero value injects nothing.
+type ChaosConfig struct {
+	Latency     time.Duration // added before every matching attempt
+	ErrorRate   float64       // fraction of matching attempts that fail, 0 to 1
+	ErrorStatus int           // status of injected failures; 0 simulates a connection error instead
+	Hosts       []string      // host rule patterns (see HostRule.Match) to scope chaos to; empty means all
+}
+
+func (c ChaosConfig) enabled() bool {
+	return c.Latency > 0 || c.ErrorRate > 0
+}
+
+func (c ChaosConfig) appliesTo(requestURL string) bool {
+	if !c.enabled() {
+		return false
+	}
+	if len(c.Hosts) == 0 {
+		return true
+	}
+	for _, host := range c.Hosts {
+		if (HostRule{Match: host}).matches(requestURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// injectChaos delays the attempt and may fail it. A nil response and nil error
+// mean the attempt proceeds normally.
+func (c ChaosConfig) injectChaos(ctx context.Context, requestURL string) (*Response, error) {
+	if !c.appliesTo(requestURL) {
+		return nil, nil
+	}
+	if c.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("executing %s: %w", requestURL, ctx.Err())
+		case <-time.After(c.Latency):
+		}
+	}
+	if c.ErrorRate <= 0 || rand.Float64() >= c.ErrorRate {
+		return nil, nil
+	}
+	if c.ErrorStatus == 0 {
+		return nil, fmt.Errorf("executing %s: %w", requestURL, ErrChaosInjected)
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("X-Chaos-Injected", "true")
+	return &Response{
+		RequestURL:  requestURL,
+		StatusCode:  c.ErrorStatus,
+		StatusText:  http.StatusText(c.ErrorStatus),
+		Headers:     headers,
+		ContentType: "application/json",
+		Body:        []byte(chaosErrorBody),
+		Duration:    c.Latency,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ChaosConfig_AppliesTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		chaos  ChaosConfig
+		url    string
+		wanted bool
+	}{
+		{"disabled", ChaosConfig{Hosts: []string{"api.example.com"}}, "https://api.example.com/", false},
+		{"all hosts", ChaosConfig{ErrorRate: 1}, "https://api.example.com/", true},
+		{"scoped match", ChaosConfig{Latency: time.Second, Hosts: []string{"*.example.com"}}, "https://api.example.com/", true},
+		{"scoped miss", ChaosConfig{Latency: time.Second, Hosts: []string{"api.example.com"}}, "https://other.example.com/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.chaos.appliesTo(tt.url); got != tt.wanted {
+				t.Errorf("appliesTo(%q) = %v, want %v", tt.url, got, tt.wanted)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_ChaosErrorStatus(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, Chaos: ChaosConfig{ErrorRate: 1, ErrorStatus: 503}})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 503 || resp.Headers.Get("X-Chaos-Injected") != "true" {
+		t.Errorf("expected injected 503, got %d %v", resp.StatusCode, resp.Headers)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("injected failures must not reach the upstream, got %d hits", hits.Load())
+	}
+}
+
+func Test_ExecuteRequest_ChaosConnectionError(t *testing.T) {
+	c := NewClient(Config{Timeout: 5 * time.Second, Chaos: ChaosConfig{ErrorRate: 1}})
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://127.0.0.1:1/"})
+	if !errors.Is(err, ErrChaosInjected) {
+		t.Errorf("expected ErrChaosInjected, got %v", err)
+	}
+}
+
+func Test_ExecuteRequest_ChaosLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, Chaos: ChaosConfig{Latency: 100 * time.Millisecond}})
+	start := time.Now()
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("expected the request to pass through, got %+v, %v", resp, err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected at least 100ms injected latency, took %s", elapsed)
+	}
+
+	_, err = NewClient(Config{Timeout: 20 * time.Millisecond, Chaos: ChaosConfig{Latency: time.Second}}).
+		ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request timeout to cut injected latency short, got %v", err)
+	}
+}
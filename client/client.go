@@ -2,95 +2,59 @@ package client
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/cookiejar"
-	"net/url"
-	"os"
-	"path/filepath"
-	"strings"
+	"slices"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
-type Config struct {
-	BaseURL         string
-	DefaultHeaders  map[string]string
-	Timeout         time.Duration
-	MaxResponseSize int64
-	ProxyURL        string
-	RetryCount      int
-	RetryDelay      time.Duration
-	InsecureTLS     bool
-	EnableCookieJar bool
-}
+// DefaultMaxResponseSize is the response body limit without
+// Config.MaxResponseSize.
+const DefaultMaxResponseSize = 51200
 
 type Client struct {
-	httpClient      *http.Client
-	baseURL         string
-	defaultHeaders  map[string]string
-	maxResponseSize int64
-	retryCount      int
-	retryDelay      time.Duration
-}
-
-type RequestParams struct {
-	Method          string
-	URL             string
-	Headers         map[string]string
-	Body            string
-	QueryParams     map[string]string
-	Timeout         time.Duration
-	FollowRedirects bool
-	SaveTo          string            // write response body to this file instead of returning it
-	MaxResponseSize int64             // per-request override; 0 means use the client default
-	Files           map[string]string // multipart uploads: form field name -> local file path
-	FormFields      map[string]string // multipart text fields, sent alongside Files
-}
-
-type Response struct {
-	StatusCode   int
-	StatusText   string
-	Headers      http.Header
-	ContentType  string
-	Body         []byte
-	Duration     time.Duration
-	Truncated    bool
-	OriginalSize int64
-	SavedPath    string
-	SavedSize    int64
-}
-
-// ParseHeaders splits raw "Key: Value" strings into a map.
-// Values containing colons are handled correctly (split on first ": " only).
-func ParseHeaders(raw []string) map[string]string {
-	headers := make(map[string]string)
-	for _, h := range raw {
-		if idx := strings.Index(h, ": "); idx > 0 {
-			headers[h[:idx]] = h[idx+2:]
-		}
-	}
-	return headers
+	httpClient         *http.Client
+	timeout            time.Duration
+	baseURL            string
+	defaultHeaders     map[string]string
+	defaultQueryParams map[string]string
+	maxResponseSize    int64
+	retryCount         int
+	retryDelay         time.Duration
+	hostRules          []hostRule
+	cassettes          *cassetteStore    // nil unless recording or replaying
+	responseCache      *responseCache    // nil without Config.CacheDir
+	limiter            *requestLimiter   // nil without Config.MaxConcurrentRequests
+	pacer              *rateLimitPacer   // nil without Config.PaceRateLimits
+	coalescer          *requestCoalescer // nil without Config.CoalesceWindow
+	bandwidth          *bandwidthLimiter // nil without Config.MaxBandwidth
+	chaos              ChaosConfig
+	idempotencyHeader  string
+	tokenSource        oauth2.TokenSource
+	hostTokenSources   []HostTokenSource
+	spillThreshold     int64
+	spillDir           string
+	dropExpect         bool
+	strictURLs         bool
+
+	blockPrivateNetworks bool
+	checkHostsByName     bool // BlockPrivateNetworks behind a proxy: the dial guard only sees the proxy
+
+	pendingAuthFailures atomic.Int32 // armed by SimulateAuthExpiry
 }
 
 func NewClient(config Config) *Client {
-	transport := &http.Transport{}
-
-	if config.ProxyURL != "" {
-		proxyURL, err := url.Parse(config.ProxyURL)
-		if err == nil {
-			transport.Proxy = http.ProxyURL(proxyURL)
-		}
-	}
-
-	if config.InsecureTLS {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
+	transport, checkHostsByName := newTransport(config)
 
+	// No http.Client.Timeout: the timeout is applied per call through the request
+	// context so a per-request timeout can be longer than the default, not only shorter.
 	httpClient := &http.Client{
 		Transport: transport,
-		Timeout:   config.Timeout,
 	}
 
 	if config.EnableCookieJar {
@@ -101,196 +65,131 @@ func NewClient(config Config) *Client {
 
 	maxResponseSize := config.MaxResponseSize
 	if maxResponseSize <= 0 {
-		maxResponseSize = 51200
+		maxResponseSize = DefaultMaxResponseSize
 	}
 
 	return &Client{
-		httpClient:      httpClient,
-		baseURL:         config.BaseURL,
-		defaultHeaders:  config.DefaultHeaders,
-		maxResponseSize: maxResponseSize,
-		retryCount:      config.RetryCount,
-		retryDelay:      config.RetryDelay,
+		httpClient:         httpClient,
+		timeout:            config.Timeout,
+		baseURL:            config.BaseURL,
+		defaultHeaders:     config.DefaultHeaders,
+		defaultQueryParams: config.DefaultQueryParams,
+		maxResponseSize:    maxResponseSize,
+		retryCount:         config.RetryCount,
+		retryDelay:         config.RetryDelay,
+		hostRules:          compileHostRules(config.HostRules, transport),
+		cassettes:          newCassetteStore(config.RecordDir, config.ReplayDir, config.ReplayMatch),
+		responseCache:      newResponseCache(config.CacheDir, config.CacheTTL),
+		limiter:            newRequestLimiter(config.MaxConcurrentRequests),
+		pacer:              newRateLimitPacer(config.PaceRateLimits, config.RateLimitReserve, config.MaxPaceWait),
+		coalescer:          newRequestCoalescer(config.CoalesceWindow, config.RequestIDHeader, config.IdempotencyKeyHeader),
+		bandwidth:          newBandwidthLimiter(config.MaxBandwidth),
+		chaos:              config.Chaos,
+		idempotencyHeader:  config.IdempotencyKeyHeader,
+		tokenSource:        config.TokenSource,
+		hostTokenSources:   config.HostTokenSources,
+		spillThreshold:     config.SpillThreshold,
+		spillDir:           config.SpillDir,
+		dropExpect:         config.DisableExpectContinue,
+		strictURLs:         config.StrictURLs,
+
+		blockPrivateNetworks: config.BlockPrivateNetworks,
+		checkHostsByName:     checkHostsByName,
 	}
 }
 
-func buildRequestURL(baseURL string, params RequestParams) (string, error) {
-	requestURL := params.URL
-	if baseURL != "" && !strings.Contains(requestURL, "://") {
-		requestURL = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(requestURL, "/")
-	}
-
-	if len(params.QueryParams) > 0 {
-		parsedURL, err := url.Parse(requestURL)
-		if err != nil {
-			return "", fmt.Errorf("parsing URL %s: %w", requestURL, err)
-		}
-		query := parsedURL.Query()
-		for key, value := range params.QueryParams {
-			query.Set(key, value)
+// ExecuteRequest sends the request, retrying as the host's settings say.
+// With Config.CoalesceWindow, an identical GET may share another call's
+// response instead.
+func (c *Client) ExecuteRequest(ctx context.Context, params RequestParams) (*Response, error) {
+	key := ""
+	if c.coalescer != nil {
+		if requestURL, err := buildRequestURL(c.baseURL, c.defaultQueryParams, c.strictURLs, params); err == nil {
+			key = coalesceKey(requestURL, params, c.coalescer.perCallHeaders)
 		}
-		parsedURL.RawQuery = query.Encode()
-		requestURL = parsedURL.String()
 	}
-
-	return requestURL, nil
+	return c.coalescer.do(ctx, key, func() (*Response, error) { return c.execute(ctx, params) })
 }
 
-func readResponseBody(resp *http.Response, maxResponseSize int64) ([]byte, bool, int64, error) {
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
-	resp.Body.Close()
+func (c *Client) execute(ctx context.Context, params RequestParams) (*Response, error) {
+	requestURL, err := buildRequestURL(c.baseURL, c.defaultQueryParams, c.strictURLs, params)
 	if err != nil {
-		return nil, false, 0, fmt.Errorf("reading response body: %w", err)
-	}
-
-	truncated := int64(len(body)) > maxResponseSize
-	var originalSize int64
-	if truncated {
-		originalSize = resp.ContentLength
-		if originalSize <= 0 {
-			originalSize = int64(len(body))
-		}
-		body = body[:maxResponseSize]
+		return nil, err
 	}
-
-	return body, truncated, originalSize, nil
-}
-
-// saveResponseBody streams the body to a temp file and renames it into place,
-// so a mid-stream failure never leaves a partial file at the target path.
-func saveResponseBody(resp *http.Response, path string) (int64, error) {
-	defer resp.Body.Close()
-	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".rest-api-mcp-*.tmp")
-	if err != nil {
-		return 0, fmt.Errorf("creating temp file for %s: %w", path, err)
+	if params, err = c.prepareJSONBody(requestURL, params); err != nil {
+		return nil, err
 	}
-	tmpPath := tmpFile.Name()
 
-	written, copyErr := io.Copy(tmpFile, resp.Body)
-	closeErr := tmpFile.Close()
-	if copyErr != nil {
-		os.Remove(tmpPath)
-		return 0, fmt.Errorf("writing response to %s: %w", path, copyErr)
-	}
-	if closeErr != nil {
-		os.Remove(tmpPath)
-		return 0, fmt.Errorf("closing %s: %w", tmpPath, closeErr)
+	hostSettings := c.settingsFor(requestURL)
+	timeout := hostSettings.timeout
+	if params.Timeout > 0 {
+		timeout = params.Timeout
 	}
-	if err := os.Rename(tmpPath, path); err != nil {
-		os.Remove(tmpPath)
-		return 0, fmt.Errorf("renaming %s to %s: %w", tmpPath, path, err)
+	requestCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		requestCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	return written, nil
-}
 
-func (c *Client) doSingleAttempt(ctx context.Context, method, requestURL string, params RequestParams) (*Response, error) {
-	var bodyReader io.Reader
-	var multipartContentType string
-	if len(params.Files) > 0 || len(params.FormFields) > 0 {
-		// Rebuilt on every attempt because the reader is consumed by the request.
-		body, contentType, err := buildMultipartBody(params.Files, params.FormFields)
+	// Tool calls may run concurrently (HTTP transport, concurrent MCP clients), so
+	// the redirect policy goes on a per-request shallow copy. The copy shares the
+	// Transport (connection pool) and cookie Jar with the shared client.
+	requestClient := *c.httpClient
+	if hostSettings.transport != nil {
+		requestClient.Transport = hostSettings.transport
+	}
+	if params.InsecureTLS || params.CACert != "" || params.ServerName != "" || params.HTTPVersion != "" {
+		transport, err := overrideTransport(requestClient.Transport, params)
 		if err != nil {
 			return nil, err
 		}
-		bodyReader = body
-		multipartContentType = contentType
-	} else if params.Body != "" {
-		bodyReader = strings.NewReader(params.Body)
+		defer transport.CloseIdleConnections()
+		requestClient.Transport = transport
 	}
-
-	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("creating request %s %s: %w", method, requestURL, err)
-	}
-
-	for key, value := range c.defaultHeaders {
-		req.Header.Set(key, value)
+	if c.cassettes != nil {
+		requestClient.Transport = c.cassettes.transport(requestClient.Transport)
 	}
-	for key, value := range params.Headers {
-		req.Header.Set(key, value)
-	}
-	if multipartContentType != "" {
-		req.Header.Set("Content-Type", multipartContentType)
-	}
-
-	start := time.Now()
-	resp, err := c.httpClient.Do(req)
-	duration := time.Since(start)
-
-	if err != nil {
-		return nil, fmt.Errorf("executing %s %s: %w", method, requestURL, err)
-	}
-
-	response := &Response{
-		StatusCode:  resp.StatusCode,
-		StatusText:  http.StatusText(resp.StatusCode),
-		Headers:     resp.Header,
-		ContentType: resp.Header.Get("Content-Type"),
-		Duration:    duration,
-	}
-
-	// Error responses (4xx/5xx) are small and informative — return them inline
-	// even when SaveTo is set, so the agent sees what went wrong.
-	if params.SaveTo != "" && resp.StatusCode < 400 {
-		savedSize, saveErr := saveResponseBody(resp, params.SaveTo)
-		if saveErr != nil {
-			return nil, saveErr
-		}
-		response.SavedPath = params.SaveTo
-		response.SavedSize = savedSize
-		return response, nil
-	}
-
-	maxResponseSize := c.maxResponseSize
-	if params.MaxResponseSize > 0 {
-		maxResponseSize = params.MaxResponseSize
-	}
-	body, truncated, originalSize, readErr := readResponseBody(resp, maxResponseSize)
-	if readErr != nil {
-		return nil, readErr
-	}
-
-	response.Body = body
-	response.Truncated = truncated
-	response.OriginalSize = originalSize
-	return response, nil
-}
-
-func (c *Client) ExecuteRequest(ctx context.Context, params RequestParams) (*Response, error) {
-	requestURL, err := buildRequestURL(c.baseURL, params)
+	cached, err := c.withResponseCache(&requestClient, params)
 	if err != nil {
 		return nil, err
 	}
-
-	requestCtx := ctx
-	var cancel context.CancelFunc
-	if params.Timeout > 0 {
-		requestCtx, cancel = context.WithTimeout(ctx, params.Timeout)
-		defer cancel()
-	}
-
-	// Mutating the shared httpClient.CheckRedirect is safe here because the MCP
-	// server processes tool calls sequentially (one at a time via stdio transport).
-	// If concurrent dispatch is ever added, this must be replaced with a per-request
-	// derived http.Client to avoid a data race.
-	originalCheckRedirect := c.httpClient.CheckRedirect
 	if !params.FollowRedirects {
-		c.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		requestClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		}
-	} else {
-		c.httpClient.CheckRedirect = nil
 	}
-	defer func() { c.httpClient.CheckRedirect = originalCheckRedirect }()
+	if c.checkHostsByName && (c.cassettes == nil || c.cassettes.replayDir == "") {
+		if err := checkRequestURL(requestCtx, requestURL); err != nil {
+			return nil, err
+		}
+		if params.FollowRedirects {
+			requestClient.CheckRedirect = guardRedirects(requestClient.CheckRedirect)
+		}
+	}
+	if params.FollowRedirects && params.CheckRedirect != nil {
+		requestClient.CheckRedirect = checkRedirectTargets(params.CheckRedirect, requestClient.CheckRedirect)
+	}
 
-	maxAttempts := c.retryCount + 1
+	params = withIdempotencyKey(params, c.idempotencyHeader, hostSettings.defaultHeaders, hostSettings.retryCount)
+	maxAttempts := hostSettings.retryCount + 1
 	var lastErr error
 	var lastResponse *Response
+	var lastFailure string // outcome of the previous attempt, for progress messages
+	var retried []int
+	var connections []ConnectionInfo
+	var backoff time.Duration
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		if attempt > 0 {
+			if params.Progress != nil {
+				params.Progress(fmt.Sprintf("attempt %d/%d failed (%s), retrying in %s", attempt, maxAttempts, lastFailure, hostSettings.retryDelay))
+			}
 			select {
 			case <-requestCtx.Done():
+				if ctx.Err() != nil {
+					return nil, cancelledError(ctx.Err(), attempt, lastResponse)
+				}
 				if lastResponse != nil {
 					return lastResponse, nil
 				}
@@ -298,25 +197,43 @@ func (c *Client) ExecuteRequest(ctx context.Context, params RequestParams) (*Res
 					return nil, lastErr
 				}
 				return nil, requestCtx.Err()
-			case <-time.After(c.retryDelay):
+			case <-time.After(hostSettings.retryDelay):
+				backoff += hostSettings.retryDelay
 			}
 		}
 
-		response, attemptErr := c.doSingleAttempt(requestCtx, params.Method, requestURL, params)
+		connection := &connectionTracker{}
+		response, attemptErr := c.doSingleAttempt(requestCtx, &requestClient, params.Method, requestURL, hostSettings.defaultHeaders, params, connection)
+		connections = append(connections, connection.result())
 		if attemptErr != nil {
+			if ctx.Err() != nil {
+				return nil, cancelledError(ctx.Err(), attempt+1, lastResponse)
+			}
 			lastErr = attemptErr
-			if attempt < maxAttempts-1 {
+			lastFailure = attemptErr.Error()
+			retried = append(retried, 0)
+			if attempt < maxAttempts-1 && !errors.Is(attemptErr, ErrBlockedAddress) && !errors.Is(attemptErr, ErrNoCassette) && !errors.Is(attemptErr, ErrNotCached) {
 				continue
 			}
+			if attempt > 0 {
+				return nil, fmt.Errorf("%w (after %d attempts, %s backoff)", lastErr, attempt+1, backoff)
+			}
 			return nil, lastErr
 		}
 
+		response.Attempts, response.RetriedStatuses, response.Backoff = attempt+1, slices.Clone(retried), backoff
+		response.Connections = slices.Clone(connections)
+		if cached != nil {
+			response.Cached, response.CacheAge = cached.hit, cached.age
+		}
 		if response.StatusCode >= 400 && response.StatusCode < 500 {
 			return response, nil
 		}
 
 		if response.StatusCode >= 500 && attempt < maxAttempts-1 {
 			lastResponse = response
+			lastFailure = fmt.Sprintf("%d %s", response.StatusCode, response.StatusText)
+			retried = append(retried, response.StatusCode)
 			continue
 		}
 
@@ -328,3 +245,12 @@ func (c *Client) ExecuteRequest(ctx context.Context, params RequestParams) (*Res
 	}
 	return nil, lastErr
 }
+
+// cancelledError reports that the caller cancelled the call (e.g. the MCP client
+// sent notifications/cancelled), including what had happened so far.
+func cancelledError(cause error, attemptsMade int, lastResponse *Response) error {
+	if lastResponse != nil {
+		return fmt.Errorf("cancelled after %d attempt(s), last status %d: %w", attemptsMade, lastResponse.StatusCode, cause)
+	}
+	return fmt.Errorf("cancelled after %d attempt(s): %w", attemptsMade, cause)
+}
@@ -2,11 +2,14 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -102,7 +105,7 @@ func Test_ExecuteRequest_QueryParams(t *testing.T) {
 	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
 		Method:          "GET",
 		URL:             server.URL,
-		QueryParams:     map[string]string{"foo": "hello", "bar": "world"},
+		QueryParams:     []QueryParam{{"foo", "hello"}, {"bar", "world"}},
 		FollowRedirects: true,
 	})
 	if err != nil {
@@ -419,6 +422,38 @@ func Test_ExecuteRequest_Retry(t *testing.T) {
 	}
 }
 
+func Test_ExecuteRequest_RetryMetadata(t *testing.T) {
+	var callCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch callCount.Add(1) {
+		case 1:
+			w.WriteHeader(503)
+		case 2:
+			w.WriteHeader(502)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, RetryCount: 3, RetryDelay: 5 * time.Millisecond})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Attempts != 3 || !slices.Equal(resp.RetriedStatuses, []int{503, 502}) || resp.Backoff != 10*time.Millisecond {
+		t.Errorf("Attempts = %d, RetriedStatuses = %v, Backoff = %s", resp.Attempts, resp.RetriedStatuses, resp.Backoff)
+	}
+}
+
+func Test_ExecuteRequest_RetriesExhaustedError(t *testing.T) {
+	c := NewClient(Config{Timeout: time.Second, MaxResponseSize: 1024, RetryCount: 2, RetryDelay: time.Millisecond})
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://127.0.0.1:1/"})
+	if err == nil || !strings.Contains(err.Error(), "(after 3 attempts, 2ms backoff)") {
+		t.Errorf("err = %v, want the attempts", err)
+	}
+}
+
 func Test_ExecuteRequest_NoRetryOn4xx(t *testing.T) {
 	var callCount atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -479,33 +514,213 @@ func Test_ExecuteRequest_NoFollowRedirect(t *testing.T) {
 	}
 }
 
-func Test_NewClient_ParseHeaders(t *testing.T) {
-	tests := []struct {
-		name    string
-		raw     []string
-		wantKey string
-		wantVal string
-	}{
-		{
-			name:    "simple header",
-			raw:     []string{"Content-Type: application/json"},
-			wantKey: "Content-Type",
-			wantVal: "application/json",
-		},
-		{
-			name:    "value with colon",
-			raw:     []string{"Authorization: Bearer token:with:colons"},
-			wantKey: "Authorization",
-			wantVal: "Bearer token:with:colons",
-		},
+func Test_ExecuteRequest_DefaultQueryParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		fmt.Fprintf(w, "version=%s page=%s sort=%s", query.Get("version"), query.Get("page"), query.Get("sort"))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		DefaultQueryParams: map[string]string{"version": "2023-10", "page": "1", "sort": "asc"},
+		Timeout:            5 * time.Second,
+		MaxResponseSize:    1024,
+	})
+
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:          "GET",
+		URL:             server.URL + "?sort=desc",
+		QueryParams:     []QueryParam{{"page", "2"}},
+		FollowRedirects: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			headers := ParseHeaders(tt.raw)
-			if got := headers[tt.wantKey]; got != tt.wantVal {
-				t.Errorf("ParseHeaders(%v)[%s] = %q, want %q", tt.raw, tt.wantKey, got, tt.wantVal)
+	body := string(resp.Body)
+	if !strings.Contains(body, "version=2023-10") {
+		t.Errorf("default query param not applied, got: %s", body)
+	}
+	if !strings.Contains(body, "page=2") {
+		t.Errorf("per-request query param did not override default, got: %s", body)
+	}
+	if !strings.Contains(body, "sort=desc") {
+		t.Errorf("URL-embedded query param did not override default, got: %s", body)
+	}
+}
+
+func Test_ExecuteRequest_PerRequestTimeoutLongerThanDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:         50 * time.Millisecond,
+		MaxResponseSize: 1024,
+	})
+
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:          "GET",
+		URL:             server.URL,
+		Timeout:         2 * time.Second,
+		FollowRedirects: true,
+	})
+	if err != nil {
+		t.Fatalf("per-request timeout should extend the default, got error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func Test_ExecuteRequest_DefaultTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:         100 * time.Millisecond,
+		MaxResponseSize: 1024,
+	})
+
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:          "GET",
+		URL:             server.URL,
+		FollowRedirects: true,
+	})
+	if err == nil {
+		t.Fatal("expected timeout error from client default, got nil")
+	}
+}
+
+func Test_ExecuteRequest_ConcurrentRedirectPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:         5 * time.Second,
+		MaxResponseSize: 1024,
+	})
+
+	var wrongStatus atomic.Int32
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		followRedirects := i%2 == 0
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+				Method:          "GET",
+				URL:             server.URL,
+				FollowRedirects: followRedirects,
+			})
+			wantStatus := 302
+			if followRedirects {
+				wantStatus = 200
 			}
-		})
+			if err != nil || resp.StatusCode != wantStatus {
+				wrongStatus.Add(1)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if wrongStatus.Load() != 0 {
+		t.Errorf("%d concurrent requests used the wrong redirect policy", wrongStatus.Load())
+	}
+}
+
+func Test_ExecuteRequest_CancelDuringRetryBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(503)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:         5 * time.Second,
+		MaxResponseSize: 1024,
+		RetryCount:      3,
+		RetryDelay:      2 * time.Second,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.ExecuteRequest(ctx, RequestParams{
+		Method:          "GET",
+		URL:             server.URL,
+		FollowRedirects: true,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "cancelled after 1 attempt(s), last status 503") {
+		t.Errorf("expected attempt summary in error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("backoff sleep was not interrupted, took %s", elapsed)
+	}
+}
+
+func Test_ExecuteRequest_CancelInFlight(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient(Config{
+		Timeout:         5 * time.Second,
+		MaxResponseSize: 1024,
+		RetryCount:      2,
+		RetryDelay:      10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.ExecuteRequest(ctx, RequestParams{
+		Method:          "GET",
+		URL:             server.URL,
+		FollowRedirects: true,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("in-flight request was not aborted promptly, took %s", elapsed)
+	}
+}
+
+func Test_ExecuteRequest_RecordsSentRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL, Timeout: 5 * time.Second, DefaultHeaders: map[string]string{"Accept": "application/json"}, DefaultQueryParams: map[string]string{"v": "2"}})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "PUT", URL: "/items/1", Headers: map[string]string{"X-Trace": "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RequestMethod != "PUT" || resp.RequestURL != server.URL+"/items/1?v=2" {
+		t.Errorf("unexpected request %s %s", resp.RequestMethod, resp.RequestURL)
+	}
+	if resp.RequestHeaders.Get("Accept") != "application/json" || resp.RequestHeaders.Get("X-Trace") != "1" {
+		t.Errorf("expected default and per-request headers, got %v", resp.RequestHeaders)
 	}
 }
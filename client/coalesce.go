@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestCoalescer lets identical GET and HEAD requests share one upstream
+// call: one that arrives while another is in flight waits for its response,
+// and one that arrives within window of its completion gets it right away.
+type requestCoalescer struct {
+	window         time.Duration
+	perCallHeaders []string // canonical names of headers whose value is unique to each call
+
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done     chan struct{}
+	resp     *Response // a snapshot, so the caller that sent it may change its own
+	err      error
+	finished time.Time // zero while in flight
+}
+
+// newRequestCoalescer returns nil (no coalescing) for a window of 0.
+// perCallHeaders, such as a request ID, are left out of the comparison.
+func newRequestCoalescer(window time.Duration, perCallHeaders ...string) *requestCoalescer {
+	if window <= 0 {
+		return nil
+	}
+	var canonical []string
+	for _, name := range perCallHeaders {
+		if name != "" {
+			canonical = append(canonical, http.CanonicalHeaderKey(name))
+		}
+	}
+	return &requestCoalescer{window: window, perCallHeaders: canonical, calls: make(map[string]*coalescedCall)}
+}
+
+// coalesceKey identifies requests that may share a response: the method,
+// resolved URL, headers other than perCallHeaders and every option that
+// changes what comes back. It is "" for requests with a body or a side effect
+// on disk, and for those that bypass or refresh the cache to get a fresh
+// response, which never share.
+func coalesceKey(requestURL string, params RequestParams, perCallHeaders []string) string {
+	if (params.Method != "GET" && params.Method != "HEAD") || params.Body != "" || len(params.Files) > 0 || params.SaveTo != "" ||
+		params.Cache == CacheBypass || params.Cache == CacheRefresh {
+		return ""
+	}
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s %s\n", params.Method, requestURL)
+	for _, name := range slices.Sorted(maps.Keys(params.Headers)) {
+		if slices.Contains(perCallHeaders, http.CanonicalHeaderKey(name)) {
+			continue
+		}
+		fmt.Fprintf(&key, "%s: %s\n", strings.ToLower(name), params.Headers[name])
+	}
+	fmt.Fprintf(&key, "%t %d %d %q %t %q %q %q %t", params.FollowRedirects, params.MaxRedirects, params.MaxResponseSize, params.Cache,
+		params.InsecureTLS, params.CACert, params.ServerName, params.HTTPVersion, params.Verbose)
+	return key.String()
+}
+
+// do runs execute for key, or shares the response of an identical call in
+// flight or finished within the window. A shared response is a copy marked
+// Coalesced. When the call it waited for was cancelled by its own caller,
+// a waiter whose context is still live sends the request itself.
+func (c *requestCoalescer) do(ctx context.Context, key string, execute func() (*Response, error)) (*Response, error) {
+	if c == nil || key == "" {
+		return execute()
+	}
+	now := time.Now()
+	c.mu.Lock()
+	for other, call := range c.calls {
+		if !call.finished.IsZero() && now.Sub(call.finished) >= c.window {
+			delete(c.calls, other)
+		}
+	}
+	if call, found := c.calls[key]; found {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil {
+			if errors.Is(call.err, context.Canceled) && ctx.Err() == nil {
+				return execute()
+			}
+			return nil, call.err
+		}
+		shared := *call.resp
+		shared.Coalesced = true
+		return &shared, nil
+	}
+	call := &coalescedCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	resp, err := execute()
+	c.mu.Lock()
+	if err != nil {
+		call.err = err
+		delete(c.calls, key) // only waiters already in line share a failure
+	} else {
+		snapshot := *resp
+		call.resp = &snapshot
+	}
+	call.finished = time.Now()
+	c.mu.Unlock()
+	close(call.done)
+	return resp, err
+}
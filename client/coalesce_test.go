@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_coalesceKey_OnlyPlainReads(t *testing.T) {
+	get := RequestParams{Method: "GET", Headers: map[string]string{"Accept": "application/json"}}
+	tests := []struct {
+		name   string
+		params RequestParams
+		shared bool
+	}{
+		{"same GET", get, true},
+		{"header name case", RequestParams{Method: "GET", Headers: map[string]string{"accept": "application/json"}}, true},
+		{"other header value", RequestParams{Method: "GET", Headers: map[string]string{"Accept": "text/plain"}}, false},
+		{"other option", RequestParams{Method: "GET", Headers: get.Headers, MaxResponseSize: 10}, false},
+		{"HEAD", RequestParams{Method: "HEAD", Headers: get.Headers}, false},
+	}
+	base := coalesceKey("https://api.example.com/items", get, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if shared := coalesceKey("https://api.example.com/items", tt.params, nil) == base; shared != tt.shared {
+				t.Errorf("shares the key = %t, want %t", shared, tt.shared)
+			}
+		})
+	}
+	perCall := []string{"X-Request-Id"}
+	first := RequestParams{Method: "GET", Headers: map[string]string{"Accept": "application/json", "x-request-id": "1"}}
+	second := RequestParams{Method: "GET", Headers: map[string]string{"Accept": "application/json", "X-Request-Id": "2"}}
+	if coalesceKey("https://api.example.com/items", first, perCall) != coalesceKey("https://api.example.com/items", second, perCall) {
+		t.Error("expected requests that differ only in a per-call header to share the key")
+	}
+	for _, params := range []RequestParams{{Method: "POST"}, {Method: "GET", Body: "x"}, {Method: "GET", SaveTo: "/tmp/out"}, {Method: "GET", Cache: CacheBypass}} {
+		if key := coalesceKey("https://api.example.com/items", params, nil); key != "" {
+			t.Errorf("expected %+v never to be coalesced, got key %q", params, key)
+		}
+	}
+}
+
+func Test_ExecuteRequest_CoalescesConcurrentGETs(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, CoalesceWindow: time.Minute})
+	var wg sync.WaitGroup
+	var coalesced atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+			if err != nil || string(resp.Body) != `{"ok":true}` {
+				t.Errorf("ExecuteRequest = %v, %v", resp, err)
+				return
+			}
+			if resp.Coalesced {
+				coalesced.Add(1)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 || coalesced.Load() != 4 {
+		t.Errorf("upstream calls = %d, coalesced = %d; want 1 and 4", calls.Load(), coalesced.Load())
+	}
+	if resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL}); err != nil || !resp.Coalesced || calls.Load() != 1 {
+		t.Errorf("expected a repeat within the window to share the response, got %+v, %v, %d calls", resp, err, calls.Load())
+	}
+}
+
+func Test_requestCoalescer_WindowAndFailures(t *testing.T) {
+	coalescer := newRequestCoalescer(20 * time.Millisecond)
+	var sent int
+	execute := func() (*Response, error) {
+		sent++
+		return &Response{StatusCode: 200}, nil
+	}
+	coalescer.do(context.Background(), "key", execute)
+	time.Sleep(30 * time.Millisecond)
+	if resp, _ := coalescer.do(context.Background(), "key", execute); resp.Coalesced || sent != 2 {
+		t.Errorf("expected a request after the window to be sent, coalesced = %t, sent = %d", resp.Coalesced, sent)
+	}
+
+	failing := func() (*Response, error) { sent++; return nil, errors.New("connection refused") }
+	coalescer.do(context.Background(), "failing", failing)
+	coalescer.do(context.Background(), "failing", failing)
+	if sent != 4 {
+		t.Errorf("expected a failed request not to be reused, sent = %d", sent)
+	}
+
+	if newRequestCoalescer(0) != nil {
+		t.Error("expected no coalescer for a zero window")
+	}
+}
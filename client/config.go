@@ -0,0 +1,110 @@
+package client
+
+import (
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type Config struct {
+	BaseURL            string
+	DefaultHeaders     map[string]string
+	DefaultQueryParams map[string]string
+	Timeout            time.Duration
+	MaxResponseSize    int64
+	ProxyURL           string
+	NoProxy            string // hosts that bypass the proxy, in NO_PROXY syntax
+	ProxyFromEnv       bool   // without ProxyURL, use HTTP_PROXY and HTTPS_PROXY (http.ProxyFromEnvironment)
+	RetryCount         int
+	RetryDelay         time.Duration
+	InsecureTLS        bool
+	EnableCookieJar    bool
+
+	// HostRules scope default headers, timeout, retries and TLS verification to
+	// hosts or URL prefixes. The first matching rule applies.
+	HostRules []HostRule
+
+	// BlockPrivateNetworks refuses loopback, private, link-local (cloud metadata)
+	// and carrier-grade NAT destinations, including ones reached via redirects.
+	BlockPrivateNetworks bool
+
+	// RecordDir saves every request/response pair as a JSON cassette; ReplayDir
+	// serves responses from cassettes without touching the network. ReplayMatch
+	// lists the request parts that must match (see ParseReplayMatch); empty
+	// means method and URL.
+	RecordDir   string
+	ReplayDir   string
+	ReplayMatch []string
+
+	Chaos ChaosConfig
+
+	// IdempotencyKeyHeader names the header that carries a generated key on
+	// POST and PATCH requests that may be retried; empty disables it.
+	IdempotencyKeyHeader string
+
+	// SpillThreshold streams bodies larger than this many bytes to a temp file
+	// in SpillDir (default: the system temp dir) instead of buffering them;
+	// 0 disables spilling.
+	SpillThreshold int64
+	SpillDir       string
+
+	// DNSCacheTTL keeps resolved addresses of up to DNSCacheSize hosts (default
+	// 1000) for this long, and past it while the resolver fails; 0 disables the cache.
+	DNSCacheTTL  time.Duration
+	DNSCacheSize int
+
+	// HTTPVersion limits connections to HTTP/1.1 or HTTP/2 (see HTTPVersion1);
+	// empty negotiates. DisableExpectContinue strips "Expect: 100-continue"
+	// from requests, for servers and load balancers that mishandle it.
+	HTTPVersion           string
+	DisableExpectContinue bool
+
+	// StrictURLs rejects URLs with a unicode host or unencoded characters in
+	// the path or query instead of encoding them.
+	StrictURLs bool
+
+	// MaxConcurrentRequests caps the attempts in flight; further calls queue in
+	// arrival order. 0 means no limit.
+	MaxConcurrentRequests int
+
+	// PaceRateLimits delays a request to a host whose latest rate-limit
+	// headers (see ParseRateLimit) leave at most RateLimitReserve requests,
+	// until the window resets, or until its Retry-After; a pause lasts at most
+	// MaxPaceWait (default one minute).
+	PaceRateLimits   bool
+	RateLimitReserve int
+	MaxPaceWait      time.Duration
+
+	// CoalesceWindow lets identical GET and HEAD requests (method, URL,
+	// headers and options) share one upstream call: while it is in flight and
+	// for this long after it completes. 0 means every request is sent.
+	// RequestIDHeader names the header callers put a unique ID per call in;
+	// it and IdempotencyKeyHeader do not count when comparing requests.
+	CoalesceWindow  time.Duration
+	RequestIDHeader string
+
+	// MaxBandwidth limits request and response body throughput to this many
+	// bytes per second, shared by all requests. 0 means no limit.
+	MaxBandwidth int64
+
+	// CacheDir stores GET responses on disk and serves them while fresh (see
+	// RequestParams.Cache); CacheTTL, when set, replaces the lifetime the
+	// response headers give. Empty CacheDir disables the cache.
+	CacheDir string
+	CacheTTL time.Duration
+
+	// TokenSource supplies "Authorization: Bearer" unless a request sets Authorization itself.
+	TokenSource oauth2.TokenSource
+
+	// HostTokenSources supply the bearer token for the hosts they match
+	// instead of TokenSource, e.g. one Azure AD token per resource. The first
+	// match wins.
+	HostTokenSources []HostTokenSource
+}
+
+// HostTokenSource scopes a token source to the URLs Match selects: a host
+// ("graph.microsoft.com", "*.vault.azure.net") or a URL prefix, as in HostRule.
+type HostTokenSource struct {
+	Match  string
+	Source oauth2.TokenSource
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+)
+
+// maxDrainBytes bounds how much of an unread body is discarded on close so
+// its keep-alive connection can be reused; a longer rest closes the
+// connection instead.
+const maxDrainBytes = 256 << 10
+
+// ConnectionInfo tells how one attempt used its connection.
+type ConnectionInfo struct {
+	Connected bool // the request reached a connection; false for cassettes, chaos and attempts that failed earlier
+	Reused    bool // the connection was kept alive from an earlier request
+	Drained   bool // the response body was read to the end, so the connection could go back to the pool
+}
+
+// connectionTracker records an attempt's connection: GotConn of the final
+// request of a redirect chain, and whether its body was drained on close.
+type connectionTracker struct {
+	mu   sync.Mutex
+	info ConnectionInfo
+}
+
+// trace adds the tracker's hook to req; earlier traces, such as the wire
+// dump's, still run.
+func (t *connectionTracker) trace(req *http.Request) *http.Request {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.info = ConnectionInfo{Connected: true, Reused: info.Reused}
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+func (t *connectionTracker) result() ConnectionInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.info
+}
+
+// track wraps a response body so closing it drains what is left, up to
+// maxDrainBytes, and records whether the body was read to the end.
+func (t *connectionTracker) track(body io.ReadCloser) io.ReadCloser {
+	return &drainingBody{body: body, tracker: t}
+}
+
+type drainingBody struct {
+	body    io.ReadCloser
+	tracker *connectionTracker
+	eof     bool
+	closed  bool
+}
+
+func (d *drainingBody) Read(buffer []byte) (int, error) {
+	n, err := d.body.Read(buffer)
+	if errors.Is(err, io.EOF) {
+		d.eof = true
+	}
+	return n, err
+}
+
+// Close may be called more than once; only the first drains.
+func (d *drainingBody) Close() error {
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	if !d.eof {
+		if n, err := io.CopyN(io.Discard, d.body, maxDrainBytes+1); n <= maxDrainBytes && errors.Is(err, io.EOF) {
+			d.eof = true
+		}
+	}
+	d.tracker.mu.Lock()
+	d.tracker.info.Drained = d.eof
+	d.tracker.mu.Unlock()
+	return d.body.Close()
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_TruncatedBodyIsDrainedForReuse(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), 10_000))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 100})
+	first, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Truncated {
+		t.Fatal("expected a truncated body")
+	}
+	if got := first.Connections; len(got) != 1 || !got[0].Connected || got[0].Reused || !got[0].Drained {
+		t.Errorf("first connections = %+v, want one new, drained connection", got)
+	}
+	if got := second.Connections; len(got) != 1 || !got[0].Reused {
+		t.Errorf("second connections = %+v, want a reused connection", got)
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("server saw %d connections, want 1", n)
+	}
+}
+
+func Test_ExecuteRequest_LongUnreadBodyIsNotDrained(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("x"), maxDrainBytes*2))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 100})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Connections; len(got) != 1 || got[0].Drained {
+		t.Errorf("connections = %+v, want one undrained connection", got)
+	}
+}
+
+func Test_ExecuteRequest_ConnectionsPerAttempt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, RetryCount: 1, RetryDelay: time.Millisecond})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := resp.Connections
+	if len(got) != 2 || got[0].Reused || !got[1].Reused || !got[0].Drained || !got[1].Drained {
+		t.Errorf("connections = %+v, want a new then a reused connection, both drained", got)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// lookupFunc resolves a host name to its addresses.
+type lookupFunc func(ctx context.Context, host string) ([]netip.Addr, error)
+
+type dnsEntry struct {
+	addrs   []netip.Addr
+	expires time.Time
+}
+
+// dnsCache keeps resolved addresses for ttl so repeated calls to the same host
+// skip the resolver. When a lookup fails, the last known addresses are served
+// even if they expired, so a resolver blip does not fail the request.
+type dnsCache struct {
+	ttl        time.Duration
+	maxEntries int
+	lookup     lookupFunc
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+// defaultDNSCacheSize applies when Config.DNSCacheSize is not set.
+const defaultDNSCacheSize = 1000
+
+func newDNSCache(ttl time.Duration, maxEntries int) *dnsCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultDNSCacheSize
+	}
+	return &dnsCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// resolve returns the addresses of host, from the cache when they are fresh.
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+
+	d.mu.Lock()
+	entry, found := d.entries[host]
+	d.mu.Unlock()
+	if found && d.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if found {
+			return entry.addrs, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no addresses for %s", host)
+		}
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, cached := d.entries[host]; !cached && len(d.entries) >= d.maxEntries {
+		d.evictOldest()
+	}
+	d.entries[host] = dnsEntry{addrs: addrs, expires: d.now().Add(d.ttl)}
+	return addrs, nil
+}
+
+// evictOldest drops the entry closest to expiry. Callers hold d.mu.
+func (d *dnsCache) evictOldest() {
+	var oldestHost string
+	var oldest time.Time
+	for host, entry := range d.entries {
+		if oldestHost == "" || entry.expires.Before(oldest) {
+			oldestHost, oldest = host, entry.expires
+		}
+	}
+	delete(d.entries, oldestHost)
+}
+
+// dialContext resolves through the cache and dials the addresses in turn with
+// dialer, whose Control (the private network guard) still sees every address.
+func (d *dnsCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := d.resolve(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", host, err)
+		}
+
+		var dialErrs []error
+		for _, addr := range addrs {
+			if (network == "tcp4" && !addr.Unmap().Is4()) || (network == "tcp6" && addr.Unmap().Is4()) {
+				continue
+			}
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErrs = append(dialErrs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if len(dialErrs) == 0 {
+			return nil, fmt.Errorf("no %s address for %s", network, host)
+		}
+		return nil, errors.Join(dialErrs...)
+	}
+}
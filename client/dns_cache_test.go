@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_dnsCache_resolve(t *testing.T) {
+	first := []netip.Addr{netip.MustParseAddr("203.0.113.1")}
+	second := []netip.Addr{netip.MustParseAddr("203.0.113.2")}
+	errResolver := errors.New("resolver unavailable")
+
+	tests := []struct {
+		name        string
+		elapsed     time.Duration
+		lookupAddrs []netip.Addr
+		lookupErr   error
+		wantAddrs   []netip.Addr
+		wantLookups int
+	}{
+		{"fresh entry skips the resolver", 10 * time.Second, second, nil, first, 1},
+		{"expired entry is resolved again", 2 * time.Minute, second, nil, second, 2},
+		{"expired entry is served while the resolver fails", 2 * time.Minute, nil, errResolver, first, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(1_000_000, 0)
+			lookups := 0
+			cache := newDNSCache(time.Minute, 10)
+			cache.now = func() time.Time { return now }
+			cache.lookup = func(context.Context, string) ([]netip.Addr, error) {
+				lookups++
+				if lookups == 1 {
+					return first, nil
+				}
+				return tt.lookupAddrs, tt.lookupErr
+			}
+
+			if _, err := cache.resolve(context.Background(), "api.example.com"); err != nil {
+				t.Fatalf("first resolve: %v", err)
+			}
+			now = now.Add(tt.elapsed)
+			addrs, err := cache.resolve(context.Background(), "api.example.com")
+			if err != nil {
+				t.Fatalf("second resolve: %v", err)
+			}
+			if len(addrs) != 1 || addrs[0] != tt.wantAddrs[0] {
+				t.Errorf("addrs = %v, want %v", addrs, tt.wantAddrs)
+			}
+			if lookups != tt.wantLookups {
+				t.Errorf("lookups = %d, want %d", lookups, tt.wantLookups)
+			}
+		})
+	}
+}
+
+func Test_dnsCache_resolve_FailsWithoutEntry(t *testing.T) {
+	cache := newDNSCache(time.Minute, 10)
+	cache.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		return nil, errors.New("no such host")
+	}
+	if _, err := cache.resolve(context.Background(), "missing.example.com"); err == nil {
+		t.Fatal("expected an error for a host that never resolved")
+	}
+}
+
+func Test_dnsCache_resolve_EvictsOldest(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	cache := newDNSCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+	cache.lookup = func(context.Context, string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("203.0.113.1")}, nil
+	}
+
+	for _, host := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		if _, err := cache.resolve(context.Background(), host); err != nil {
+			t.Fatalf("resolve %s: %v", host, err)
+		}
+		now = now.Add(time.Second)
+	}
+	if len(cache.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(cache.entries))
+	}
+	if _, found := cache.entries["a.example.com"]; found {
+		t.Error("the oldest entry was not evicted")
+	}
+}
+
+func Test_ExecuteRequest_DNSCacheResolvesHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	_, port, _ := net.SplitHostPort(serverURL.Host)
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, DNSCacheTTL: time.Minute})
+	cache := newDNSCache(time.Minute, 10)
+	cache.lookup = func(_ context.Context, host string) ([]netip.Addr, error) {
+		if host != "api.test" {
+			t.Errorf("lookup host = %q, want api.test", host)
+		}
+		return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+	}
+	c.httpClient.Transport.(*http.Transport).DialContext = cache.dialContext(&net.Dialer{Timeout: 5 * time.Second})
+
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://api.test:" + port + "/"})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if string(resp.Body) != "ok" {
+		t.Errorf("body = %q, want ok", resp.Body)
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxDownloadResumes bounds how often one saveTo download is resumed after
+// its connection broke mid-stream.
+const maxDownloadResumes = 5
+
+// rangeRequester asks for the rest of a download from offset, sending ifRange
+// so a changed resource comes back whole (200) instead of spliced (206).
+type rangeRequester func(offset int64, ifRange string) (*http.Response, error)
+
+// newRangeRequester re-sends a GET the way doSingleAttempt sent it, with a
+// Range header. Other methods are not resumed: repeating them may not be safe.
+func (c *Client) newRangeRequester(ctx context.Context, httpClient *http.Client, method, requestURL string, defaultHeaders map[string]string, params RequestParams) rangeRequester {
+	if method != http.MethodGet {
+		return nil
+	}
+	return func(offset int64, ifRange string) (*http.Response, error) {
+		req, err := newHTTPRequest(ctx, method, requestURL, defaultHeaders, params)
+		if err != nil {
+			return nil, err
+		}
+		if !removesHeader(params.Headers, "Authorization") {
+			if err := c.authorize(req); err != nil {
+				return nil, err
+			}
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", ifRange)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = c.throttle(ctx, resp.Body, params)
+		return resp, nil
+	}
+}
+
+// resumeValidator returns the If-Range value that lets resp's body be resumed:
+// its strong ETag, else its Last-Modified. "" means a resumed body could not be
+// verified to belong to the same resource, or the byte offsets are not the
+// server's (a body net/http decompressed).
+func resumeValidator(resp *http.Response) string {
+	if resp.StatusCode != http.StatusOK || resp.Uncompressed || resp.Header.Get("Accept-Ranges") == "none" {
+		return ""
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// contentRangeStart returns the first byte of "Content-Range: bytes a-b/n",
+// or -1 when the header is missing or malformed.
+func contentRangeStart(header string) int64 {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	first, _, found := strings.Cut(spec, "-")
+	start, err := strconv.ParseInt(first, 10, 64)
+	if !ok || !found || err != nil {
+		return -1
+	}
+	return start
+}
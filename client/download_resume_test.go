@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyDownload serves body, cutting the connection after cut bytes of every
+// full response; Range requests are honored when If-Range matches etag.
+type flakyDownload struct {
+	mu       sync.Mutex
+	body     []byte
+	etag     string
+	cut      int
+	requests []string // Range header of each request
+}
+
+func (d *flakyDownload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.requests = append(d.requests, r.Header.Get("Range"))
+	w.Header().Set("ETag", d.etag)
+	w.Header().Set("Accept-Ranges", "bytes")
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && r.Header.Get("If-Range") == d.etag {
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-"))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(d.body)-1, len(d.body)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(d.body)-start))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(d.body[start:])
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(d.body)))
+	if d.cut > 0 && d.cut < len(d.body) {
+		w.Write(d.body[:d.cut])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.Write(d.body)
+}
+
+func Test_ExecuteRequest_SaveToResumesWithRange(t *testing.T) {
+	download := &flakyDownload{body: bytes.Repeat([]byte("artifact-"), 10000), etag: `"v1"`, cut: 30000}
+	server := httptest.NewServer(download)
+	defer server.Close()
+	target := filepath.Join(t.TempDir(), "artifact.bin")
+
+	resp, err := NewClient(Config{Timeout: 5 * time.Second}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL, SaveTo: target})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	saved, _ := os.ReadFile(target)
+	if !bytes.Equal(saved, download.body) {
+		t.Fatalf("saved %d bytes, want the %d-byte body", len(saved), len(download.body))
+	}
+	sum := sha256.Sum256(download.body)
+	if resp.SavedSize != int64(len(download.body)) || resp.SavedSHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("saved size %d, sha256 %s", resp.SavedSize, resp.SavedSHA256)
+	}
+	if len(download.requests) != 2 || download.requests[1] != "bytes=30000-" {
+		t.Errorf("requests = %q, want one resume from byte 30000", download.requests)
+	}
+}
+
+func Test_ExecuteRequest_SaveToRestartsWhenResourceChanged(t *testing.T) {
+	download := &flakyDownload{body: bytes.Repeat([]byte("old-"), 10000), etag: `"v1"`, cut: 20000}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			download.mu.Lock()
+			download.body, download.etag, download.cut = bytes.Repeat([]byte("new-"), 10000), `"v2"`, 0
+			download.mu.Unlock()
+		}
+		download.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	target := filepath.Join(t.TempDir(), "artifact.bin")
+
+	if _, err := NewClient(Config{Timeout: 5 * time.Second}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL, SaveTo: target}); err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if saved, _ := os.ReadFile(target); !bytes.Equal(saved, download.body) {
+		t.Errorf("saved %q..., want only the new body", saved[:12])
+	}
+}
+
+func Test_ExecuteRequest_SaveToWithoutValidatorFails(t *testing.T) {
+	download := &flakyDownload{body: bytes.Repeat([]byte("x"), 50000), cut: 10000}
+	server := httptest.NewServer(download)
+	defer server.Close()
+	target := filepath.Join(t.TempDir(), "artifact.bin")
+
+	_, err := NewClient(Config{Timeout: 5 * time.Second}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL, SaveTo: target})
+	if err == nil {
+		t.Fatal("expected an error for an unverifiable partial download")
+	}
+	if _, statErr := os.Stat(target); !os.IsNotExist(statErr) {
+		t.Errorf("a partial file was left at the target: %v", statErr)
+	}
+	if len(download.requests) != 1 {
+		t.Errorf("requests = %q, want no Range request without a validator", download.requests)
+	}
+}
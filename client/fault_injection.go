@@ -0,0 +1,44 @@
+package client
+
+import (
+	"net/http"
+)
+
+const simulatedAuthExpiryBody = `{"error":"invalid_token","error_description":"The access token expired (simulated by rest-api-mcp fault injection)"}`
+
+// SimulateAuthExpiry makes the next count request attempts fail with a
+// synthetic 401 Unauthorized without contacting the upstream, so re-auth and
+// retry behavior can be verified on demand. A count of 0 disarms it.
+func (c *Client) SimulateAuthExpiry(count int) {
+	c.pendingAuthFailures.Store(int32(count))
+}
+
+// PendingSimulatedAuthFailures reports how many simulated 401s are still armed.
+func (c *Client) PendingSimulatedAuthFailures() int {
+	return int(c.pendingAuthFailures.Load())
+}
+
+// takeSimulatedAuthFailure consumes one armed failure and returns the synthetic
+// response, or nil when nothing is armed.
+func (c *Client) takeSimulatedAuthFailure() *Response {
+	for {
+		pending := c.pendingAuthFailures.Load()
+		if pending <= 0 {
+			return nil
+		}
+		if c.pendingAuthFailures.CompareAndSwap(pending, pending-1) {
+			break
+		}
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("Www-Authenticate", `Bearer error="invalid_token", error_description="simulated token expiry"`)
+	return &Response{
+		StatusCode:  http.StatusUnauthorized,
+		StatusText:  http.StatusText(http.StatusUnauthorized),
+		Headers:     headers,
+		ContentType: "application/json",
+		Body:        []byte(simulatedAuthExpiryBody),
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_SimulateAuthExpiry_FailsNextRequestOnly(t *testing.T) {
+	var callCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount.Add(1)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024})
+	c.SimulateAuthExpiry(1)
+
+	params := RequestParams{Method: "GET", URL: server.URL, FollowRedirects: true}
+	resp, err := c.ExecuteRequest(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 401 {
+		t.Errorf("expected simulated 401, got %d", resp.StatusCode)
+	}
+	if resp.Headers.Get("WWW-Authenticate") == "" {
+		t.Error("expected WWW-Authenticate header on simulated 401")
+	}
+	if callCount.Load() != 0 {
+		t.Errorf("simulated failure must not reach upstream, got %d calls", callCount.Load())
+	}
+
+	resp, err = c.ExecuteRequest(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected real 200 after simulated failure was consumed, got %d", resp.StatusCode)
+	}
+	if c.PendingSimulatedAuthFailures() != 0 {
+		t.Errorf("expected no pending failures, got %d", c.PendingSimulatedAuthFailures())
+	}
+}
+
+func Test_SimulateAuthExpiry_Disarm(t *testing.T) {
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	c.SimulateAuthExpiry(3)
+	c.SimulateAuthExpiry(0)
+
+	if c.PendingSimulatedAuthFailures() != 0 {
+		t.Errorf("expected disarmed, got %d pending", c.PendingSimulatedAuthFailures())
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HostRule scopes default headers and request settings to a host or URL prefix,
+// so that e.g. an Authorization header is only sent to the API it belongs to.
+// Zero fields keep the client-wide value.
+type HostRule struct {
+	Match          string            // host ("api.example.com", "*.example.com") or URL prefix ("https://api.example.com/v2/")
+	DefaultHeaders map[string]string // added after the client-wide default headers, overriding them
+	Timeout        time.Duration
+	RetryCount     *int // nil keeps the client default; 0 disables retries for the host
+	RetryDelay     time.Duration
+	InsecureTLS    bool
+}
+
+// hostRule is a HostRule with the transport it needs, if it differs from the client's.
+type hostRule struct {
+	HostRule
+	transport http.RoundTripper
+}
+
+// compileHostRules builds a dedicated transport for rules that change TLS
+// verification. The clone shares the proxy and dial settings of base.
+func compileHostRules(rules []HostRule, base *http.Transport) []hostRule {
+	compiled := make([]hostRule, 0, len(rules))
+	for _, rule := range rules {
+		compiledRule := hostRule{HostRule: rule}
+		if rule.InsecureTLS && (base.TLSClientConfig == nil || !base.TLSClientConfig.InsecureSkipVerify) {
+			insecureTransport := base.Clone()
+			insecureTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			compiledRule.transport = insecureTransport
+		}
+		compiled = append(compiled, compiledRule)
+	}
+	return compiled
+}
+
+// matches reports whether the rule applies to requestURL. A URL prefix must
+// have the same scheme, host and port, and its path must end at a "/"
+// boundary of the request's path, so "https://api.example.com" matches neither
+// api.example.com.evil.com nor a URL whose user info spells the host.
+func (r HostRule) matches(requestURL string) bool {
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+	if strings.Contains(r.Match, "://") {
+		prefix, err := url.Parse(r.Match)
+		if err != nil || !sameOrigin(prefix, parsedURL) {
+			return false
+		}
+		path := strings.TrimSuffix(prefix.Path, "/")
+		return path == "" || parsedURL.Path == path || strings.HasPrefix(parsedURL.Path, path+"/")
+	}
+	host := strings.ToLower(parsedURL.Hostname())
+	match := strings.ToLower(r.Match)
+	if suffix, ok := strings.CutPrefix(match, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == match
+}
+
+// effectiveSettings are the client-wide settings with the matching host rule applied.
+type effectiveSettings struct {
+	defaultHeaders map[string]string
+	timeout        time.Duration
+	retryCount     int
+	retryDelay     time.Duration
+	transport      http.RoundTripper // nil keeps the client's transport
+}
+
+func (c *Client) settingsFor(requestURL string) effectiveSettings {
+	settings := effectiveSettings{
+		defaultHeaders: c.defaultHeaders,
+		timeout:        c.timeout,
+		retryCount:     c.retryCount,
+		retryDelay:     c.retryDelay,
+	}
+	rule := c.matchHostRule(requestURL)
+	if rule == nil {
+		return settings
+	}
+	settings.defaultHeaders = c.defaultHeadersFor(rule)
+	if rule.Timeout > 0 {
+		settings.timeout = rule.Timeout
+	}
+	if rule.RetryCount != nil {
+		settings.retryCount = *rule.RetryCount
+	}
+	if rule.RetryDelay > 0 {
+		settings.retryDelay = rule.RetryDelay
+	}
+	settings.transport = rule.transport
+	return settings
+}
+
+// matchHostRule returns the first rule that applies to requestURL, or nil.
+func (c *Client) matchHostRule(requestURL string) *hostRule {
+	for i := range c.hostRules {
+		if c.hostRules[i].matches(requestURL) {
+			return &c.hostRules[i]
+		}
+	}
+	return nil
+}
+
+// defaultHeadersFor merges the client-wide default headers with the rule's.
+func (c *Client) defaultHeadersFor(rule *hostRule) map[string]string {
+	if rule == nil || len(rule.DefaultHeaders) == 0 {
+		return c.defaultHeaders
+	}
+	merged := make(map[string]string, len(c.defaultHeaders)+len(rule.DefaultHeaders))
+	for key, value := range c.defaultHeaders {
+		merged[key] = value
+	}
+	for key, value := range rule.DefaultHeaders {
+		merged[key] = value
+	}
+	return merged
+}
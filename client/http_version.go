@@ -0,0 +1,39 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTP versions for Config.HTTPVersion and RequestParams.HTTPVersion. Empty
+// negotiates: HTTP/2 via ALPN where the server offers it, else HTTP/1.1.
+const (
+	HTTPVersion1 = "1.1"
+	HTTPVersion2 = "2"
+)
+
+// ParseHTTPVersion checks an HTTP version setting.
+func ParseHTTPVersion(version string) error {
+	switch version {
+	case "", HTTPVersion1, HTTPVersion2:
+		return nil
+	}
+	return fmt.Errorf("unsupported HTTP version %q (expected %s or %s)", version, HTTPVersion1, HTTPVersion2)
+}
+
+// httpProtocols returns the protocols a transport may use for version: only
+// HTTP/1.1, or only HTTP/2 (h2c with prior knowledge for http:// URLs). nil
+// keeps the transport's default.
+func httpProtocols(version string) *http.Protocols {
+	var protocols http.Protocols
+	switch version {
+	case HTTPVersion1:
+		protocols.SetHTTP1(true)
+	case HTTPVersion2:
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		return nil
+	}
+	return &protocols
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_HTTPVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	plainServer := httptest.NewUnstartedServer(handler)
+	plainServer.Config.Protocols = &http.Protocols{}
+	plainServer.Config.Protocols.SetHTTP1(true)
+	plainServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	plainServer.Start()
+	defer plainServer.Close()
+
+	tests := []struct {
+		name          string
+		url           string
+		clientVersion string
+		version       string
+		want          string
+	}{
+		{"forced HTTP/2 over TLS", tlsServer.URL, "", HTTPVersion2, "HTTP/2.0"},
+		{"forced HTTP/1.1 over TLS", tlsServer.URL, HTTPVersion2, HTTPVersion1, "HTTP/1.1"},
+		{"client-wide HTTP/2", tlsServer.URL, HTTPVersion2, "", "HTTP/2.0"},
+		{"h2c over plain HTTP", plainServer.URL, "", HTTPVersion2, "HTTP/2.0"},
+		{"plain HTTP default", plainServer.URL, "", "", "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, InsecureTLS: true, HTTPVersion: tt.clientVersion})
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: tt.url, HTTPVersion: tt.version})
+			if err != nil {
+				t.Fatalf("ExecuteRequest: %v", err)
+			}
+			if string(resp.Body) != tt.want || resp.Proto != tt.want {
+				t.Errorf("server saw %s, response proto %s, want %s", resp.Body, resp.Proto, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_Trailers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Write([]byte("payload"))
+		w.Header().Set("X-Checksum", "abc123")
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if got := resp.Trailers.Get("X-Checksum"); got != "abc123" {
+		t.Errorf("trailer X-Checksum = %q, want abc123", got)
+	}
+}
+
+func Test_ExecuteRequest_DisableExpectContinue(t *testing.T) {
+	var gotExpect string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotExpect = r.Header.Get("Expect")
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	for _, disable := range []bool{false, true} {
+		c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, DisableExpectContinue: disable})
+		_, err := c.ExecuteRequest(context.Background(), RequestParams{
+			Method:  "POST",
+			URL:     server.URL,
+			Headers: map[string]string{"Expect": "100-continue"},
+			Body:    strings.Repeat("x", 100),
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest: %v", err)
+		}
+		if wantSent := !disable; (gotExpect != "") != wantSent {
+			t.Errorf("DisableExpectContinue=%v: server saw Expect %q", disable, gotExpect)
+		}
+	}
+}
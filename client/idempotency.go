@@ -0,0 +1,42 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// NewUUID returns a random (version 4) UUID.
+func NewUUID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	id[6] = id[6]&0x0f | 0x40 // version 4
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
+}
+
+// withIdempotencyKey returns params with headerName set to a fresh key when a
+// POST or PATCH may be retried, so every attempt carries the same key and an
+// API that honors it (Stripe-style) does not apply the request twice. A key
+// the caller or the default headers already set is kept.
+func withIdempotencyKey(params RequestParams, headerName string, defaultHeaders map[string]string, retryCount int) RequestParams {
+	if headerName == "" || retryCount <= 0 || (params.Method != http.MethodPost && params.Method != http.MethodPatch) {
+		return params
+	}
+	canonical := http.CanonicalHeaderKey(headerName)
+	for _, headers := range []map[string]string{defaultHeaders, params.Headers} {
+		for name := range headers {
+			if http.CanonicalHeaderKey(name) == canonical {
+				return params
+			}
+		}
+	}
+
+	headers := make(map[string]string, len(params.Headers)+1)
+	for name, value := range params.Headers {
+		headers[name] = value
+	}
+	headers[headerName] = NewUUID()
+	params.Headers = headers
+	return params
+}
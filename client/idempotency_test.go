@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func Test_NewUUID_IsVersion4(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := NewUUID(), NewUUID()
+	if !pattern.MatchString(first) {
+		t.Errorf("expected a version 4 UUID, got %q", first)
+	}
+	if first == second {
+		t.Errorf("expected distinct UUIDs, got %q twice", first)
+	}
+}
+
+func Test_WithIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		defaultHeaders map[string]string
+		retryCount     int
+		wantGenerated  bool
+	}{
+		{"retried POST", "POST", nil, nil, 2, true},
+		{"retried PATCH", "PATCH", map[string]string{"Accept": "x"}, nil, 1, true},
+		{"no retries", "POST", nil, nil, 0, false},
+		{"GET", "GET", nil, nil, 2, false},
+		{"PUT is idempotent", "PUT", nil, nil, 2, false},
+		{"caller key kept", "POST", map[string]string{"idempotency-key": "mine"}, nil, 2, false},
+		{"default header key kept", "POST", nil, map[string]string{"Idempotency-Key": "fixed"}, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := withIdempotencyKey(RequestParams{Method: tt.method, Headers: tt.headers}, "Idempotency-Key", tt.defaultHeaders, tt.retryCount)
+			_, generated := params.Headers["Idempotency-Key"]
+			if generated != tt.wantGenerated {
+				t.Errorf("generated = %v, want %v (headers %v)", generated, tt.wantGenerated, params.Headers)
+			}
+			if generated && len(tt.headers) > 0 && len(tt.headers) == len(params.Headers) {
+				t.Errorf("caller's headers map must not be modified")
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_IdempotencyKeyReusedAcrossRetries(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, RetryCount: 2, RetryDelay: time.Millisecond, IdempotencyKeyHeader: "Idempotency-Key"})
+	if _, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "POST", URL: server.URL, Body: "{}"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("expected one key reused by all 3 attempts, got %q", keys)
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// normalizeURL makes a URL the agent wrote by hand safe to send: a unicode
+// host becomes its punycode (IDNA) form, and characters not allowed in the
+// path or query are percent-encoded. Valid encodings are kept as written. With
+// strict, it returns an error naming the problem instead of rewriting.
+func normalizeURL(rawURL string, strict bool) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL %s: %w", rawURL, err)
+	}
+	if host := parsed.Hostname(); !isASCII(host) {
+		asciiHost := idnaToASCII(host)
+		if strict {
+			return "", fmt.Errorf("host %q is not ASCII; use its IDNA form %s (--strict-urls)", host, asciiHost)
+		}
+		if port := parsed.Port(); port != "" {
+			asciiHost = net.JoinHostPort(asciiHost, port)
+		}
+		parsed.Host = asciiHost
+	}
+	// url.Parse keeps a hand-written path in RawPath; EscapedPath drops it when
+	// it is not validly encoded and encodes Path instead.
+	if strict && parsed.RawPath != "" && parsed.RawPath != parsed.EscapedPath() {
+		return "", fmt.Errorf("path %q has characters that must be percent-encoded (--strict-urls)", parsed.RawPath)
+	}
+	if query := escapeQuery(parsed.RawQuery); query != parsed.RawQuery {
+		if strict {
+			return "", fmt.Errorf("query %q has characters that must be percent-encoded (--strict-urls)", parsed.RawQuery)
+		}
+		parsed.RawQuery = query
+	}
+	return parsed.String(), nil
+}
+
+// escapeQuery percent-encodes the bytes of a raw query that may not appear in
+// one (spaces, unicode, a "%" not starting an escape), leaving the rest as is.
+func escapeQuery(query string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '%' && i+2 < len(query) && isHex(query[i+1]) && isHex(query[i+2]):
+			escaped.WriteByte(c)
+		case c != '%' && c > ' ' && c < 0x7f && !strings.ContainsRune(`"<>\^`+"`{|}", rune(c)):
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// idnaToASCII converts a unicode host name to its ASCII form: lower-cased,
+// split on ideographic full stops too, each non-ASCII label punycode-encoded
+// with the "xn--" prefix. It skips the full UTS #46 mapping, which covers
+// what agents write in practice.
+func idnaToASCII(host string) string {
+	host = strings.NewReplacer("。", ".", "．", ".", "｡", ".").Replace(strings.ToLower(host))
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if !isASCII(label) {
+			labels[i] = "xn--" + punycode(label)
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// Punycode parameters from RFC 3492.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycode encodes one label as described in RFC 3492, section 6.3.
+func punycode(label string) string {
+	runes := []rune(label)
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	if basic > 0 {
+		out = append(out, '-')
+	}
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for handled := basic; handled < len(runes); {
+		next := rune(utf8.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < next {
+				next = r
+			}
+		}
+		delta += int(next-n) * (handled + 1)
+		n = next
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := min(max(k-bias, punyTMin), punyTMax)
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return string(out)
+}
+
+func punyAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_idnaToASCII(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"MÜNCHEN.de", "xn--mnchen-3ya.de"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"bücher。example", "xn--bcher-kva.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := idnaToASCII(tt.host); got != tt.want {
+				t.Errorf("idnaToASCII(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalizeURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		want      string
+		strictErr string
+	}{
+		{"ASCII URL unchanged", "https://api.example.com/a%2Fb?sig=ab%2Bcd&x=1", "https://api.example.com/a%2Fb?sig=ab%2Bcd&x=1", ""},
+		{"IDN host with port", "https://bücher.example:8443/", "https://xn--bcher-kva.example:8443/", "not ASCII"},
+		{"unicode path", "https://api.example.com/café/a b", "https://api.example.com/caf%C3%A9/a%20b", "path"},
+		{"unicode query", "https://api.example.com/search?q=héllo wörld&x=1", "https://api.example.com/search?q=h%C3%A9llo%20w%C3%B6rld&x=1", "query"},
+		{"stray percent", "https://api.example.com/?discount=100%", "https://api.example.com/?discount=100%25", "query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeURL(tt.url, false)
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeURL = %q, %v, want %q", got, err, tt.want)
+			}
+			_, err = normalizeURL(tt.url, true)
+			if tt.strictErr == "" && err != nil {
+				t.Errorf("strict: unexpected error %v", err)
+			}
+			if tt.strictErr != "" && (err == nil || !strings.Contains(err.Error(), tt.strictErr)) {
+				t.Errorf("strict: err = %v, want one mentioning %q", err, tt.strictErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrInvalidJSONBody marks a request body that failed JSON validation; the
+// request was not sent.
+var ErrInvalidJSONBody = errors.New("request body is not valid JSON")
+
+// prepareJSONBody validates params.Body as JSON when RequestParams.ValidateJSON
+// asks for it — by default when the request's Content-Type is JSON — and
+// minifies it with RequestParams.MinifyJSON. Multipart requests are left alone.
+func (c *Client) prepareJSONBody(requestURL string, params RequestParams) (RequestParams, error) {
+	if params.Body == "" || len(params.Files) > 0 || len(params.FormFields) > 0 {
+		return params, nil
+	}
+	validate := isJSONMediaType(c.requestContentType(requestURL, params))
+	if params.ValidateJSON != nil {
+		validate = *params.ValidateJSON
+	}
+	if !validate && !params.MinifyJSON {
+		return params, nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(params.Body)); err != nil {
+		return params, jsonBodyError(params.Body, err)
+	}
+	if params.MinifyJSON {
+		params.Body = compacted.String()
+	}
+	return params, nil
+}
+
+// requestContentType returns the Content-Type the request will carry: the
+// per-request header, else the default (or host-scoped default) header.
+func (c *Client) requestContentType(requestURL string, params RequestParams) string {
+	for name, value := range params.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return value
+		}
+	}
+	for name, value := range c.settingsFor(requestURL).defaultHeaders {
+		if strings.EqualFold(name, "Content-Type") {
+			return value
+		}
+	}
+	return ""
+}
+
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// jsonBodyError locates a syntax error by line and column and quotes the text
+// around it, so the agent can fix the body without another round trip.
+func jsonBodyError(body string, err error) error {
+	position := len(body)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		position = max(int(syntaxErr.Offset)-1, 0) // Offset counts the offending byte
+	}
+	before := body[:position]
+	line := strings.Count(before, "\n") + 1
+	column := position - strings.LastIndex(before, "\n")
+	start := max(position-20, 0)
+	end := min(position+20, len(body))
+	return fmt.Errorf("%w: %s at line %d, column %d, near %q (fix the body, or pass validateJsonBody: false to send it as is)",
+		ErrInvalidJSONBody, err, line, column, body[start:end])
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_JSONBody(t *testing.T) {
+	var requests int
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+	}))
+	defer server.Close()
+
+	off := false
+	on := true
+	tests := []struct {
+		name        string
+		defaults    map[string]string
+		params      RequestParams
+		wantErr     string
+		wantBody    string
+		wantRequest bool
+	}{
+		{"invalid JSON content type rejected", nil, RequestParams{Headers: map[string]string{"Content-Type": "application/json"}, Body: "{\n  \"a\": 1,\n}"}, "line 3, column 1", "", false},
+		{"default header counts", map[string]string{"Content-Type": "application/vnd.api+json"}, RequestParams{Body: "{oops}"}, "line 1, column 2", "", false},
+		{"other content types pass", nil, RequestParams{Headers: map[string]string{"Content-Type": "text/plain"}, Body: "{oops}"}, "", "{oops}", true},
+		{"validation turned off", nil, RequestParams{Headers: map[string]string{"Content-Type": "application/json"}, Body: "{oops}", ValidateJSON: &off}, "", "{oops}", true},
+		{"validation forced", nil, RequestParams{Body: "[1,", ValidateJSON: &on}, "line 1, column 3", "", false},
+		{"forced without content type", nil, RequestParams{Body: `{"ok": true}`, ValidateJSON: &on}, "", `{"ok": true}`, true},
+		{"minified", nil, RequestParams{Body: "{\n  \"a\": [1, 2]\n}", MinifyJSON: true}, "", `{"a":[1,2]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, gotBody = 0, ""
+			c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, DefaultHeaders: tt.defaults})
+			tt.params.Method, tt.params.URL = "POST", server.URL
+			_, err := c.ExecuteRequest(context.Background(), tt.params)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidJSONBody) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want ErrInvalidJSONBody mentioning %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ExecuteRequest: %v", err)
+			}
+			if (requests > 0) != tt.wantRequest || gotBody != tt.wantBody {
+				t.Errorf("server saw %d requests with body %q, want request %v with %q", requests, gotBody, tt.wantRequest, tt.wantBody)
+			}
+		})
+	}
+}
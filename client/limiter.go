@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// requestLimiter caps the attempts in flight. Callers beyond the limit wait in
+// a FIFO queue, so a burst of calls is served in arrival order.
+type requestLimiter struct {
+	limit int
+
+	mu      sync.Mutex
+	active  int
+	waiters []chan struct{}
+}
+
+// newRequestLimiter returns nil (no limit) when limit is not positive.
+func newRequestLimiter(limit int) *requestLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &requestLimiter{limit: limit}
+}
+
+// acquire waits for a slot and returns how long it queued. A nil limiter
+// never waits.
+func (l *requestLimiter) acquire(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	l.mu.Lock()
+	if l.active < l.limit && len(l.waiters) == 0 {
+		l.active++
+		l.mu.Unlock()
+		return 0, nil
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-ready:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		index := slices.Index(l.waiters, ready)
+		if index >= 0 {
+			l.waiters = slices.Delete(l.waiters, index, index+1)
+		}
+		l.mu.Unlock()
+		if index < 0 {
+			l.release() // the slot was handed over while ctx was cancelled
+		}
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// release hands the slot to the longest waiter, or frees it.
+func (l *requestLimiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.waiters) > 0 {
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+		return
+	}
+	l.active--
+}
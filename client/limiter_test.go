@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_requestLimiter_ServesInArrivalOrder(t *testing.T) {
+	limiter := newRequestLimiter(1)
+	if _, err := limiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limiter.acquire(context.Background()); err != nil {
+				t.Errorf("acquire %d: %v", i, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			limiter.release()
+		}()
+		// Wait until the goroutine is queued so arrival order is known.
+		for queued := 0; queued != i+1; {
+			time.Sleep(time.Millisecond)
+			limiter.mu.Lock()
+			queued = len(limiter.waiters)
+			limiter.mu.Unlock()
+		}
+	}
+	limiter.release()
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("order = %v, want [0 1 2]", order)
+	}
+}
+
+func Test_requestLimiter_CancelledWaiterLeavesQueue(t *testing.T) {
+	limiter := newRequestLimiter(1)
+	limiter.acquire(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	limiter.release()
+
+	if limiter.active != 0 || len(limiter.waiters) != 0 {
+		t.Errorf("active = %d, waiters = %d, want both 0", limiter.active, len(limiter.waiters))
+	}
+}
+
+func Test_ExecuteRequest_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, MaxConcurrentRequests: 2})
+	var wg sync.WaitGroup
+	var queued atomic.Int32
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+			if err != nil {
+				t.Errorf("ExecuteRequest: %v", err)
+				return
+			}
+			if resp.QueueWait > 0 {
+				queued.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+	if queued.Load() == 0 {
+		t.Error("expected some requests to report a queue wait")
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned when BlockPrivateNetworks refuses a destination.
+var ErrBlockedAddress = errors.New("destination address is blocked")
+
+// carrierGradeNAT (RFC 6598) is not covered by netip.Addr.IsPrivate but hosts
+// internal services, including some cloud metadata endpoints (100.100.100.200).
+var carrierGradeNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// blockedAddressReason classifies addresses that must not be reachable when
+// BlockPrivateNetworks is on. An empty result means the address is public.
+func blockedAddressReason(addr netip.Addr) string {
+	addr = addr.Unmap()
+	switch {
+	case addr.IsLoopback():
+		return "loopback"
+	case addr.IsUnspecified():
+		return "unspecified"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		// 169.254.0.0/16 contains the AWS/GCP/Azure metadata service (169.254.169.254).
+		return "link-local (cloud metadata range)"
+	case addr.IsPrivate():
+		return "private network"
+	case carrierGradeNAT.Contains(addr):
+		return "carrier-grade NAT range"
+	}
+	return ""
+}
+
+func checkAddress(addr netip.Addr) error {
+	if reason := blockedAddressReason(addr); reason != "" {
+		return fmt.Errorf("%w: %s is %s", ErrBlockedAddress, addr.Unmap(), reason)
+	}
+	return nil
+}
+
+// guardedDialControl runs after DNS resolution and before connecting, so it
+// sees the address actually dialed: redirects and DNS rebinding cannot bypass it.
+func guardedDialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: cannot parse dial address %s: %v", ErrBlockedAddress, address, err)
+	}
+	return checkAddress(addrPort.Addr())
+}
+
+// checkDestinationHost resolves host and rejects it if any address is blocked.
+// Used when a proxy is configured: the dial then goes to the proxy, so the
+// target has to be checked by name before the request is handed over.
+func checkDestinationHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddress(addr)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := checkAddress(addr); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// guardRedirects wraps a CheckRedirect policy so every redirect target is
+// checked by name before it is followed. next may be nil (the http default).
+func guardRedirects(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := checkDestinationHost(req.Context(), req.URL.Hostname()); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return ErrTooManyRedirects
+		}
+		return nil
+	}
+}
+
+// checkRequestURL checks the host of a request URL by name.
+func checkRequestURL(ctx context.Context, requestURL string) error {
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return fmt.Errorf("parsing URL %s: %w", requestURL, err)
+	}
+	return checkDestinationHost(ctx, parsedURL.Hostname())
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_BlockedAddressReason(t *testing.T) {
+	tests := []struct {
+		address string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.100.100.200", true},
+		{"0.0.0.0", true},
+		{"fd00:ec2::254", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2606:4700::1111", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			reason := blockedAddressReason(netip.MustParseAddr(tt.address))
+			if (reason != "") != tt.blocked {
+				t.Errorf("blockedAddressReason(%s) = %q, want blocked=%v", tt.address, reason, tt.blocked)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_BlockPrivateNetworks(t *testing.T) {
+	var requestCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{
+		Timeout:              5 * time.Second,
+		RetryCount:           2,
+		RetryDelay:           time.Second,
+		BlockPrivateNetworks: true,
+	})
+	start := time.Now()
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "loopback") {
+		t.Errorf("expected reason in error, got: %v", err)
+	}
+	if requestCount.Load() != 0 {
+		t.Errorf("expected no request to reach the server, got %d", requestCount.Load())
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("blocked destinations must not be retried")
+	}
+}
+
+func Test_ExecuteRequest_BlockPrivateNetworksBehindProxy(t *testing.T) {
+	var proxyCount atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyCount.Add(1)
+		w.WriteHeader(200)
+	}))
+	defer proxy.Close()
+
+	c := NewClient(Config{
+		Timeout:              5 * time.Second,
+		ProxyURL:             proxy.URL,
+		BlockPrivateNetworks: true,
+	})
+
+	// The proxy itself is on loopback and stays reachable; the target is checked by name.
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://169.254.169.254/latest/meta-data/"})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got: %v", err)
+	}
+	if proxyCount.Load() != 0 {
+		t.Errorf("expected blocked request not to reach the proxy, got %d", proxyCount.Load())
+	}
+
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://93.184.215.14/"})
+	if err != nil {
+		t.Fatalf("expected public target to go through the proxy: %v", err)
+	}
+	if resp.StatusCode != 200 || proxyCount.Load() != 1 {
+		t.Errorf("expected proxied 200, got %d (proxy hits %d)", resp.StatusCode, proxyCount.Load())
+	}
+}
+
+func Test_GuardRedirects_BlocksPrivateTarget(t *testing.T) {
+	policy := guardRedirects(nil)
+	req := httptest.NewRequest("GET", "http://127.0.0.1/admin", nil)
+	if err := policy(req, nil); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected redirect to loopback to be blocked, got: %v", err)
+	}
+	req = httptest.NewRequest("GET", "http://8.8.8.8/", nil)
+	if err := policy(req, make([]*http.Request, 10)); err == nil {
+		t.Error("expected redirect limit to apply")
+	}
+}
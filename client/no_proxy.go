@@ -0,0 +1,103 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// noProxyRule is one NO_PROXY entry: an IP, a CIDR range or a domain, with an
+// optional port.
+type noProxyRule struct {
+	prefix     netip.Prefix // valid for IP and CIDR entries
+	domain     string
+	subdomains bool // ".example.com" or "*.example.com": subdomains only
+	port       string
+}
+
+// noProxyRules follows the common NO_PROXY conventions (curl, Go): entries
+// are comma- or space-separated; "*" bypasses the proxy for every host;
+// "example.com" matches the domain and its subdomains, ".example.com" and
+// "*.example.com" only the subdomains; IPs and CIDR ranges such as
+// 10.0.0.0/8 match addresses literally, without DNS.
+type noProxyRules struct {
+	all   bool
+	rules []noProxyRule
+}
+
+func parseNoProxy(list string) noProxyRules {
+	var parsed noProxyRules
+	for _, entry := range strings.FieldsFunc(strings.ToLower(list), func(r rune) bool { return r == ',' || r == ' ' }) {
+		if entry == "*" {
+			parsed.all = true
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			parsed.rules = append(parsed.rules, noProxyRule{prefix: prefix.Masked()})
+			continue
+		}
+		var rule noProxyRule
+		if host, port, err := net.SplitHostPort(entry); err == nil {
+			entry, rule.port = host, port
+		}
+		if addr, err := netip.ParseAddr(strings.Trim(entry, "[]")); err == nil {
+			rule.prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+			parsed.rules = append(parsed.rules, rule)
+			continue
+		}
+		entry = strings.TrimPrefix(entry, "*")
+		if trimmed, ok := strings.CutPrefix(entry, "."); ok {
+			entry, rule.subdomains = trimmed, true
+		}
+		if entry != "" {
+			rule.domain = entry
+			parsed.rules = append(parsed.rules, rule)
+		}
+	}
+	return parsed
+}
+
+// bypass reports whether requests to target skip the proxy.
+func (n noProxyRules) bypass(target *url.URL) bool {
+	if n.all {
+		return true
+	}
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" && target.Scheme == "http" {
+		port = "80"
+	} else if port == "" && target.Scheme == "https" {
+		port = "443"
+	}
+	addr, addrErr := netip.ParseAddr(host)
+	for _, rule := range n.rules {
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+		switch {
+		case rule.prefix.IsValid():
+			if addrErr == nil && rule.prefix.Contains(addr.Unmap()) {
+				return true
+			}
+		case host == rule.domain:
+			if !rule.subdomains {
+				return true
+			}
+		case strings.HasSuffix(host, "."+rule.domain):
+			return true
+		}
+	}
+	return false
+}
+
+// proxyFunc asks proxy for the proxy of a request unless noProxy exempts the host.
+func proxyFunc(proxy func(*http.Request) (*url.URL, error), noProxy noProxyRules) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if noProxy.bypass(req.URL) {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_noProxyRules_bypass(t *testing.T) {
+	tests := []struct {
+		name    string
+		noProxy string
+		target  string
+		want    bool
+	}{
+		{"empty list", "", "http://api.example.com", false},
+		{"wildcard", "*", "http://api.example.com", true},
+		{"domain matches itself", "example.com", "http://example.com/x", true},
+		{"domain matches subdomains", "example.com", "http://api.example.com", true},
+		{"domain does not match suffix lookalikes", "example.com", "http://badexample.com", false},
+		{"leading dot matches subdomains", ".example.com", "http://api.example.com", true},
+		{"leading dot skips the domain itself", ".example.com", "http://example.com", false},
+		{"star dot works like leading dot", "*.example.com", "http://api.example.com", true},
+		{"case insensitive", "Internal.Example.COM", "http://svc.internal.example.com", true},
+		{"second entry", "localhost, internal.example.com", "https://internal.example.com", true},
+		{"port must match", "example.com:8080", "http://example.com:9090", false},
+		{"port matches", "example.com:8080", "http://example.com:8080", true},
+		{"default port", "example.com:443", "https://example.com", true},
+		{"IP", "10.1.2.3", "http://10.1.2.3:8080", true},
+		{"CIDR", "10.0.0.0/8", "http://10.20.30.40", true},
+		{"CIDR miss", "10.0.0.0/8", "http://192.168.1.1", false},
+		{"CIDR does not resolve names", "10.0.0.0/8", "http://internal.example.com", false},
+		{"IPv6", "::1", "http://[::1]:8080", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			if got := parseNoProxy(tt.noProxy).bypass(target); got != tt.want {
+				t.Errorf("bypass(%s) with %q = %v, want %v", tt.target, tt.noProxy, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_NoProxyBypassesProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.Host)
+		w.Write([]byte("via proxy"))
+	}))
+	defer proxy.Close()
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("direct"))
+	}))
+	defer target.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, ProxyURL: proxy.URL, NoProxy: "127.0.0.1"})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: target.URL})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if string(resp.Body) != "direct" {
+		t.Errorf("body = %q, want direct", resp.Body)
+	}
+	resp, err = c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: "http://api.example.com/"})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if string(resp.Body) != "via proxy" || len(proxied) != 1 || proxied[0] != "api.example.com" {
+		t.Errorf("body = %q, proxied %v, want api.example.com via the proxy", resp.Body, proxied)
+	}
+}
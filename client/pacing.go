@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultMaxPaceWait bounds a pause without Config.MaxPaceWait.
+const defaultMaxPaceWait = time.Minute
+
+// rateLimitPacer delays requests to a host whose latest rate-limit headers
+// say its quota is used up, down to reserve requests, until the window
+// resets or a Retry-After passes; a pause never exceeds maxWait.
+type rateLimitPacer struct {
+	reserve int
+	maxWait time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]RateLimit
+}
+
+// newRateLimitPacer returns nil (no pacing) unless enabled.
+func newRateLimitPacer(enabled bool, reserve int, maxWait time.Duration) *rateLimitPacer {
+	if !enabled {
+		return nil
+	}
+	if maxWait <= 0 {
+		maxWait = defaultMaxPaceWait
+	}
+	return &rateLimitPacer{reserve: max(reserve, 0), maxWait: maxWait, hosts: make(map[string]RateLimit)}
+}
+
+// observe keeps the rate-limit headers of a response from host.
+func (p *rateLimitPacer) observe(host string, header http.Header) {
+	if p == nil {
+		return
+	}
+	limit, ok := ParseRateLimit(header, time.Now())
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hosts[host] = limit
+}
+
+// pause is how long a request to host should wait at now, and why. Sending
+// counts against the remaining quota, so a burst of calls paces itself before
+// the next response updates it.
+func (p *rateLimitPacer) pause(host string, now time.Time) (time.Duration, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	limit, known := p.hosts[host]
+	if !known {
+		return 0, ""
+	}
+	var until time.Time
+	var reason string
+	switch {
+	case limit.RetryAfter.After(now):
+		until, reason = limit.RetryAfter, "Retry-After"
+	case limit.Remaining >= 0 && limit.Remaining <= p.reserve && limit.Reset.After(now):
+		until, reason = limit.Reset, fmt.Sprintf("%d requests left until the reset", limit.Remaining)
+	}
+	if limit.Remaining > 0 {
+		limit.Remaining--
+		p.hosts[host] = limit
+	}
+	if until.IsZero() {
+		return 0, ""
+	}
+	return min(until.Sub(now), p.maxWait), reason
+}
+
+// wait pauses a request to requestURL as the host's rate limit asks, logging
+// the pause and reporting it through progress. A nil pacer never waits.
+func (p *rateLimitPacer) wait(ctx context.Context, requestURL string, progress ProgressFunc) (time.Duration, error) {
+	if p == nil {
+		return 0, nil
+	}
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return 0, nil
+	}
+	delay, reason := p.pause(parsed.Host, time.Now())
+	if delay <= 0 {
+		return 0, nil
+	}
+	message := fmt.Sprintf("pacing %s: waiting %s for its rate limit (%s)", parsed.Host, delay.Round(time.Millisecond), reason)
+	log.Print(message)
+	if progress != nil {
+		progress(message)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
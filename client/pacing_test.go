@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_rateLimitPacer_WaitsForResetWhenSpent(t *testing.T) {
+	now := time.Now()
+	pacer := newRateLimitPacer(true, 0, time.Hour)
+	pacer.hosts["api.example.com"] = RateLimit{Limit: 10, Remaining: 0, Reset: now.Add(30 * time.Second)}
+
+	delay, reason := pacer.pause("api.example.com", now)
+
+	if delay != 30*time.Second || reason == "" {
+		t.Errorf("pause = %s (%q), want 30s with a reason", delay, reason)
+	}
+	if delay, _ := pacer.pause("other.example.com", now); delay != 0 {
+		t.Errorf("expected no pause for an unseen host, got %s", delay)
+	}
+}
+
+func Test_rateLimitPacer_ReserveCountsSentRequests(t *testing.T) {
+	now := time.Now()
+	pacer := newRateLimitPacer(true, 1, time.Hour)
+	pacer.hosts["api.example.com"] = RateLimit{Limit: 10, Remaining: 3, Reset: now.Add(time.Minute)}
+
+	var delays []time.Duration
+	for range 4 {
+		delay, _ := pacer.pause("api.example.com", now)
+		delays = append(delays, delay)
+	}
+
+	want := []time.Duration{0, 0, time.Minute, time.Minute}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("pause %d = %s, want %s (all: %v)", i, delays[i], want[i], delays)
+		}
+	}
+}
+
+func Test_rateLimitPacer_RetryAfterAndMaxWait(t *testing.T) {
+	now := time.Now()
+	pacer := newRateLimitPacer(true, 0, 5*time.Second)
+	pacer.hosts["api.example.com"] = RateLimit{Limit: -1, Remaining: -1, RetryAfter: now.Add(2 * time.Second)}
+	pacer.hosts["slow.example.com"] = RateLimit{Limit: 10, Remaining: 0, Reset: now.Add(time.Hour)}
+
+	if delay, reason := pacer.pause("api.example.com", now); delay != 2*time.Second || reason != "Retry-After" {
+		t.Errorf("pause = %s (%q), want 2s for Retry-After", delay, reason)
+	}
+	if delay, _ := pacer.pause("slow.example.com", now); delay != 5*time.Second {
+		t.Errorf("pause = %s, want the 5s max wait", delay)
+	}
+	if delay, _ := pacer.pause("api.example.com", now.Add(3*time.Second)); delay != 0 {
+		t.Errorf("expected no pause after Retry-After passed, got %s", delay)
+	}
+}
+
+func Test_newRateLimitPacer_Disabled(t *testing.T) {
+	pacer := newRateLimitPacer(false, 5, time.Minute)
+
+	pacer.observe("api.example.com", http.Header{"X-Ratelimit-Remaining": {"0"}})
+	if delay, err := pacer.wait(context.Background(), "https://api.example.com/", nil); delay != 0 || err != nil {
+		t.Errorf("wait = %s, %v; want no pause without pacing", delay, err)
+	}
+}
+
+func Test_ExecuteRequest_PaceRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, PaceRateLimits: true, MaxPaceWait: 50 * time.Millisecond})
+	var progress []string
+	params := RequestParams{Method: "GET", URL: server.URL, Progress: func(message string) { progress = append(progress, message) }}
+	first, err := c.ExecuteRequest(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.ExecuteRequest(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.PaceWait != 0 {
+		t.Errorf("first request paused %s, want no pause before any headers", first.PaceWait)
+	}
+	if second.PaceWait != 50*time.Millisecond {
+		t.Errorf("second request paused %s, want the 50ms max wait", second.PaceWait)
+	}
+	if len(progress) == 0 {
+		t.Error("expected the pause to be reported as progress")
+	}
+}
+
+func Test_ExecuteRequest_PaceRateLimitsCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, PaceRateLimits: true})
+	if _, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.ExecuteRequest(ctx, RequestParams{Method: "GET", URL: server.URL}); err == nil {
+		t.Error("expected a cancelled pause to fail the request")
+	}
+}
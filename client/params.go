@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ParseHeaders splits raw "Key: Value" strings into a map keyed by canonical
+// header name. Values containing colons are handled correctly (split on first
+// ": " only). A repeated name is combined into one value: "; " for Cookie, a
+// comma-separated list otherwise.
+func ParseHeaders(raw []string) map[string]string {
+	headers := make(map[string]string)
+	for _, h := range raw {
+		idx := strings.Index(h, ": ")
+		if idx <= 0 {
+			continue
+		}
+		key, value := http.CanonicalHeaderKey(h[:idx]), h[idx+2:]
+		if previous, ok := headers[key]; ok && key == "Cookie" {
+			value = previous + "; " + value
+		} else if ok {
+			value = previous + ", " + value
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+// ParseQueryParams splits raw "key=value" strings into a map.
+// Values containing "=" are handled correctly (split on the first "=" only).
+func ParseQueryParams(raw []string) map[string]string {
+	queryParams := make(map[string]string)
+	for _, q := range raw {
+		if idx := strings.Index(q, "="); idx > 0 {
+			queryParams[q[:idx]] = q[idx+1:]
+		}
+	}
+	return queryParams
+}
+
+// Query merge modes for RequestParams.QueryMerge: what happens when a
+// per-request query parameter is already in the URL. The default ("") is
+// QueryOverride.
+const (
+	QueryOverride = "override" // the per-request parameter replaces the URL's
+	QueryAppend   = "append"   // both are sent, the URL's first
+	QueryError    = "error"    // the request fails instead of guessing
+)
+
+// buildRequestURL joins the base URL and merges query parameters. The URL's own
+// query is kept byte for byte — signed URLs stay valid — except for pairs that
+// per-request parameters replace (see QueryMerge) or RequestParams.ReplaceQuery
+// drops. Default query parameters have the lowest priority: a key already
+// present in the URL or in the per-request query params wins, mirroring how
+// default headers behave. Per-request parameters are appended last in their
+// given order, so repeated names and a deliberate order reach the server.
+// Finally the URL is normalized (see normalizeURL), strictly with strictURLs.
+func buildRequestURL(baseURL string, defaultQueryParams map[string]string, strictURLs bool, params RequestParams) (string, error) {
+	requestURL := params.URL
+	if baseURL != "" && !strings.Contains(requestURL, "://") {
+		requestURL = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(requestURL, "/")
+	}
+	if len(params.QueryParams) == 0 && len(defaultQueryParams) == 0 && !params.ReplaceQuery {
+		return normalizeURL(requestURL, strictURLs)
+	}
+
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL %s: %w", requestURL, err)
+	}
+	perRequest := make(map[string]bool, len(params.QueryParams))
+	for _, param := range params.QueryParams {
+		perRequest[param.Name] = true
+	}
+
+	var pairs []string
+	present := make(map[string]bool)
+	if !params.ReplaceQuery {
+		for pair := range strings.SplitSeq(parsedURL.RawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			name, _, _ := strings.Cut(pair, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if perRequest[name] {
+				switch params.QueryMerge {
+				case QueryAppend:
+				case QueryError:
+					return "", fmt.Errorf("query parameter %q is already in the URL (pass queryMerge override or append, or replaceQuery to drop the URL's query)", name)
+				default:
+					continue
+				}
+			}
+			pairs = append(pairs, pair)
+			present[name] = true
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaultQueryParams)) {
+		if !present[key] && !perRequest[key] {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(defaultQueryParams[key]))
+		}
+	}
+	for _, param := range params.QueryParams {
+		pairs = append(pairs, url.QueryEscape(param.Name)+"="+url.QueryEscape(param.Value))
+	}
+	parsedURL.RawQuery = strings.Join(pairs, "&")
+	return normalizeURL(parsedURL.String(), strictURLs)
+}
+
+// ParseQueryMerge checks a query merge mode.
+func ParseQueryMerge(mode string) error {
+	switch mode {
+	case "", QueryOverride, QueryAppend, QueryError:
+		return nil
+	}
+	return fmt.Errorf("unsupported queryMerge %q (expected %s, %s or %s)", mode, QueryOverride, QueryAppend, QueryError)
+}
+
+// ResolveURL returns the URL ExecuteRequest would send params to: the base URL
+// joined and query parameters merged. Callers use it to apply URL policies.
+func (c *Client) ResolveURL(params RequestParams) (string, error) {
+	return buildRequestURL(c.baseURL, c.defaultQueryParams, c.strictURLs, params)
+}
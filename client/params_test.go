@@ -0,0 +1,150 @@
+package client
+
+import (
+	"testing"
+)
+
+func Test_NewClient_ParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []string
+		wantKey string
+		wantVal string
+	}{
+		{
+			name:    "simple header",
+			raw:     []string{"Content-Type: application/json"},
+			wantKey: "Content-Type",
+			wantVal: "application/json",
+		},
+		{
+			name:    "value with colon",
+			raw:     []string{"Authorization: Bearer token:with:colons"},
+			wantKey: "Authorization",
+			wantVal: "Bearer token:with:colons",
+		},
+		{
+			name:    "name canonicalized",
+			raw:     []string{"x-api-key: secret"},
+			wantKey: "X-Api-Key",
+			wantVal: "secret",
+		},
+		{
+			name:    "repeated name combined",
+			raw:     []string{"Accept: application/json", "accept: text/plain"},
+			wantKey: "Accept",
+			wantVal: "application/json, text/plain",
+		},
+		{
+			name:    "repeated cookie combined",
+			raw:     []string{"Cookie: a=1", "Cookie: b=2"},
+			wantKey: "Cookie",
+			wantVal: "a=1; b=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := ParseHeaders(tt.raw)
+			if got := headers[tt.wantKey]; got != tt.wantVal {
+				t.Errorf("ParseHeaders(%v)[%s] = %q, want %q", tt.raw, tt.wantKey, got, tt.wantVal)
+			}
+		})
+	}
+}
+
+func Test_NewClient_ParseQueryParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []string
+		wantKey string
+		wantVal string
+	}{
+		{
+			name:    "simple param",
+			raw:     []string{"api_version=2023-10"},
+			wantKey: "api_version",
+			wantVal: "2023-10",
+		},
+		{
+			name:    "value with equals sign",
+			raw:     []string{"token=abc==def"},
+			wantKey: "token",
+			wantVal: "abc==def",
+		},
+		{
+			name:    "empty value",
+			raw:     []string{"debug="},
+			wantKey: "debug",
+			wantVal: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryParams := ParseQueryParams(tt.raw)
+			got, ok := queryParams[tt.wantKey]
+			if !ok || got != tt.wantVal {
+				t.Errorf("ParseQueryParams(%v)[%s] = %q, want %q", tt.raw, tt.wantKey, got, tt.wantVal)
+			}
+		})
+	}
+}
+
+func Test_buildRequestURL_QueryParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		defaults map[string]string
+		params   []QueryParam
+		want     string
+	}{
+		{"repeated names", "https://api.example.com/items", nil, []QueryParam{{"id", "1"}, {"id", "2"}}, "https://api.example.com/items?id=1&id=2"},
+		{"given order kept", "https://api.example.com/items", nil, []QueryParam{{"z", "1"}, {"a", "2"}}, "https://api.example.com/items?z=1&a=2"},
+		{"replaces URL values", "https://api.example.com/items?id=0&page=1", nil, []QueryParam{{"id", "1"}, {"id", "2"}}, "https://api.example.com/items?page=1&id=1&id=2"},
+		{"overrides defaults", "https://api.example.com/items", map[string]string{"v": "1", "page": "1"}, []QueryParam{{"page", "2"}}, "https://api.example.com/items?v=1&page=2"},
+		{"escaped", "https://api.example.com/search", nil, []QueryParam{{"q", "a b&c"}}, "https://api.example.com/search?q=a+b%26c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildRequestURL("", tt.defaults, false, RequestParams{URL: tt.url, QueryParams: tt.params})
+			if err != nil {
+				t.Fatalf("buildRequestURL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildRequestURL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildRequestURL_QueryMerge(t *testing.T) {
+	signed := "https://bucket.example.com/key?X-Amz-Signature=ab%2Fcd&X-Amz-Date=20260101T000000Z"
+	tests := []struct {
+		name    string
+		url     string
+		params  RequestParams
+		want    string
+		wantErr bool
+	}{
+		{"signed URL kept byte for byte", signed, RequestParams{QueryParams: []QueryParam{{"part", "1"}}}, signed + "&part=1", false},
+		{"override", "https://api.example.com/?b=1&a=2", RequestParams{QueryParams: []QueryParam{{"b", "3"}}}, "https://api.example.com/?a=2&b=3", false},
+		{"append", "https://api.example.com/?b=1", RequestParams{QueryParams: []QueryParam{{"b", "3"}}, QueryMerge: QueryAppend}, "https://api.example.com/?b=1&b=3", false},
+		{"error on conflict", "https://api.example.com/?b=1", RequestParams{QueryParams: []QueryParam{{"b", "3"}}, QueryMerge: QueryError}, "", true},
+		{"error mode without conflict", "https://api.example.com/?b=1", RequestParams{QueryParams: []QueryParam{{"c", "3"}}, QueryMerge: QueryError}, "https://api.example.com/?b=1&c=3", false},
+		{"replace query", "https://api.example.com/?b=1&a=2", RequestParams{QueryParams: []QueryParam{{"c", "3"}}, ReplaceQuery: true}, "https://api.example.com/?c=3", false},
+		{"replace query without params", "https://api.example.com/x?b=1", RequestParams{ReplaceQuery: true}, "https://api.example.com/x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.URL = tt.url
+			got, err := buildRequestURL("", nil, false, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildRequestURL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
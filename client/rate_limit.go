@@ -0,0 +1,111 @@
+package client
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit is what a response's rate-limit headers tell about the quota of
+// its host: the X-RateLimit-* family (GitHub, GitLab, Twitter's
+// X-Rate-Limit-*), the IETF RateLimit-* and RateLimit/RateLimit-Policy
+// headers, and Retry-After.
+type RateLimit struct {
+	Limit      int       // requests per window; -1 when not sent
+	Remaining  int       // requests left in the window; -1 when not sent
+	Reset      time.Time // when the window resets; zero when not sent
+	RetryAfter time.Time // when to retry after a 429 or 503; zero without Retry-After
+}
+
+// ParseRateLimit reads the rate-limit headers of a response received at now;
+// ok is false when it has none.
+func ParseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	limit := RateLimit{Limit: -1, Remaining: -1}
+	if value, ok := firstHeader(header, "X-RateLimit-Limit", "X-Rate-Limit-Limit", "RateLimit-Limit"); ok {
+		limit.Limit = leadingInt(value)
+	}
+	if value, ok := firstHeader(header, "X-RateLimit-Remaining", "X-Rate-Limit-Remaining", "RateLimit-Remaining"); ok {
+		limit.Remaining = leadingInt(value)
+	}
+	if value, ok := firstHeader(header, "X-RateLimit-Reset", "X-Rate-Limit-Reset", "RateLimit-Reset"); ok {
+		limit.Reset = resetTime(value, now)
+	}
+	// RateLimit: "default";r=50;t=30 and RateLimit-Policy: "default";q=100;w=60
+	if value := header.Get("RateLimit"); value != "" {
+		if remaining, ok := structuredParam(value, "r"); ok {
+			limit.Remaining = remaining
+		}
+		if seconds, ok := structuredParam(value, "t"); ok {
+			limit.Reset = now.Add(time.Duration(seconds) * time.Second)
+		}
+	}
+	if quota, ok := structuredParam(header.Get("RateLimit-Policy"), "q"); ok && limit.Limit < 0 {
+		limit.Limit = quota
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds >= 0 {
+			limit.RetryAfter = now.Add(time.Duration(seconds) * time.Second)
+		} else if date, err := http.ParseTime(value); err == nil {
+			limit.RetryAfter = date
+		}
+	}
+	known := limit.Limit >= 0 || limit.Remaining >= 0 || !limit.Reset.IsZero() || !limit.RetryAfter.IsZero()
+	return limit, known
+}
+
+func firstHeader(header http.Header, names ...string) (string, bool) {
+	for _, name := range names {
+		if value := header.Get(name); value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// leadingInt parses the number a header starts with, such as 100 in
+// "100, 100;w=60"; -1 when there is none.
+func leadingInt(value string) int {
+	value = strings.TrimSpace(value)
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	number, err := strconv.Atoi(value[:end])
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// resetTime reads a reset header: seconds until the reset, or a Unix time in
+// seconds (GitHub) or milliseconds when the number is too large to be a delay.
+func resetTime(value string, now time.Time) time.Time {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 || math.IsInf(number, 0) {
+		return time.Time{}
+	}
+	switch {
+	case number > 1e12:
+		return time.UnixMilli(int64(number))
+	case number > 1e9:
+		return time.Unix(int64(number), 0)
+	}
+	return now.Add(time.Duration(number * float64(time.Second)))
+}
+
+// structuredParam reads an integer parameter such as r=50 from a structured
+// RateLimit header.
+func structuredParam(value, name string) (int, bool) {
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == ',' }) {
+		key, number, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || key != name {
+			continue
+		}
+		if parsed, err := strconv.Atoi(number); err == nil {
+			return parsed, true
+		}
+	}
+	return 0, false
+}
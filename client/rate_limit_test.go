@@ -0,0 +1,50 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_ParseRateLimit(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   RateLimit
+	}{
+		{"github", map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4987", "X-RateLimit-Reset": "1767369600"},
+			RateLimit{Limit: 5000, Remaining: 4987, Reset: time.Unix(1767369600, 0)}},
+		{"reset in seconds", map[string]string{"X-Rate-Limit-Remaining": "0", "X-Rate-Limit-Reset": "30"},
+			RateLimit{Limit: -1, Remaining: 0, Reset: now.Add(30 * time.Second)}},
+		{"reset in milliseconds", map[string]string{"X-RateLimit-Reset": "1767369600000"},
+			RateLimit{Limit: -1, Remaining: -1, Reset: time.UnixMilli(1767369600000)}},
+		{"ietf fields", map[string]string{"RateLimit-Limit": "100, 100;w=60", "RateLimit-Remaining": "42", "RateLimit-Reset": "12"},
+			RateLimit{Limit: 100, Remaining: 42, Reset: now.Add(12 * time.Second)}},
+		{"ietf structured", map[string]string{"RateLimit": `"default";r=50;t=30`, "RateLimit-Policy": `"default";q=100;w=60`},
+			RateLimit{Limit: 100, Remaining: 50, Reset: now.Add(30 * time.Second)}},
+		{"retry after seconds", map[string]string{"Retry-After": "120"},
+			RateLimit{Limit: -1, Remaining: -1, RetryAfter: now.Add(2 * time.Minute)}},
+		{"retry after date", map[string]string{"Retry-After": "Fri, 02 Jan 2026 15:05:00 GMT"},
+			RateLimit{Limit: -1, Remaining: -1, RetryAfter: now.Add(5 * time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range tt.header {
+				header.Set(name, value)
+			}
+			got, ok := ParseRateLimit(header, now)
+			if !ok {
+				t.Fatal("expected rate-limit headers to be found")
+			}
+			if got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining || !got.Reset.Equal(tt.want.Reset) || !got.RetryAfter.Equal(tt.want.RetryAfter) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := ParseRateLimit(http.Header{"Content-Type": {"application/json"}, "Retry-After": {"soon"}}, now); ok {
+		t.Error("expected no rate limit without usable headers")
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+)
+
+// sensitiveHeaderNames contains lowercase header names whose values are secrets.
+var sensitiveHeaderNames = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+}
+
+// sensitiveQueryParamNames contains lowercase query parameter names whose values are secrets.
+var sensitiveQueryParamNames = map[string]bool{
+	"api_key":      true,
+	"apikey":       true,
+	"key":          true,
+	"token":        true,
+	"access_token": true,
+	"secret":       true,
+}
+
+// IsSensitiveHeader reports whether a header's value must be redacted wherever it is shown.
+func IsSensitiveHeader(name string) bool {
+	return sensitiveHeaderNames[strings.ToLower(name)]
+}
+
+// IsSensitiveQueryParam reports whether a query parameter's value must be redacted wherever it is shown.
+func IsSensitiveQueryParam(name string) bool {
+	return sensitiveQueryParamNames[strings.ToLower(name)]
+}
+
+// RedactURL censors the values of sensitive query parameters as *** and hides
+// a user info password, keeping the parameters in order. It is applied
+// wherever a sent URL is shown: echoes, dry runs, history, logs and wire dumps.
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	parsed.RawQuery = redactQuery(parsed.RawQuery)
+	return parsed.Redacted()
+}
+
+// redactQuery censors the values of sensitive parameters in a raw query.
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		rawName, value, found := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+		if found && value != "" && IsSensitiveQueryParam(name) {
+			pairs[i] = rawName + "=***"
+		}
+	}
+	return strings.Join(pairs, "&")
+}
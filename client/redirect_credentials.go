@@ -0,0 +1,74 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// crossOriginHeaders are never sent on to another origin: credentials and
+// cookies (the cookie jar adds the target's own cookies). IsSensitiveHeader
+// names are stripped too.
+var crossOriginHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Cookie2"}
+
+// stripCrossOriginHeaders removes the headers that must not follow a redirect
+// from the original request to another origin (scheme, host or port):
+// credentials, cookies, and default headers scoped to the original host that
+// the target's defaults do not set to the same value. It returns the names the
+// original request carried, sorted, including those net/http already left
+// out; nil when the redirect stays on origin.
+func stripCrossOriginHeaders(req, original *http.Request, injected, targetDefaults map[string]string) []string {
+	if sameOrigin(original.URL, req.URL) {
+		return nil
+	}
+	var dropped []string
+	drop := func(name string) {
+		name = http.CanonicalHeaderKey(name)
+		if _, sent := original.Header[name]; sent && !slices.Contains(dropped, name) {
+			req.Header.Del(name)
+			dropped = append(dropped, name)
+		}
+	}
+	for _, name := range crossOriginHeaders {
+		drop(name)
+	}
+	for name := range original.Header {
+		if IsSensitiveHeader(name) {
+			drop(name)
+		}
+	}
+	for name, value := range injected {
+		if target, found := lookupHeader(targetDefaults, name); !found || target != value {
+			drop(name)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// sameOrigin compares scheme, host and port, the port defaulting by scheme.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Hostname(), b.Hostname()) && originPort(a) == originPort(b)
+}
+
+func originPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+// lookupHeader finds a header in a name-to-value map regardless of the name's case.
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
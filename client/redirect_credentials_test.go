@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_StripsCredentialsOnCrossOriginRedirect(t *testing.T) {
+	var received http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer target.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/same" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, target.URL+"/landing", http.StatusFound)
+	}))
+	defer origin.Close()
+	originURL := strings.Replace(origin.URL, "127.0.0.1", "localhost", 1)
+
+	c := NewClient(Config{
+		Timeout:        5 * time.Second,
+		DefaultHeaders: map[string]string{"Authorization": "Bearer secret", "X-Client": "mcp"},
+		HostRules:      []HostRule{{Match: "localhost", DefaultHeaders: map[string]string{"X-Tenant-Secret": "t1"}}},
+	})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: originURL + "/same", FollowRedirects: true,
+		Headers: map[string]string{"X-Api-Key": "k", "X-Trace": "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Authorization", "X-Api-Key", "X-Tenant-Secret"} {
+		if received.Get(name) != "" {
+			t.Errorf("%s was sent to another origin", name)
+		}
+	}
+	if received.Get("X-Client") != "mcp" || received.Get("X-Trace") != "1" {
+		t.Errorf("expected other headers to follow the redirect, got %v", received)
+	}
+	if len(resp.Redirects) != 2 || resp.Redirects[0].DroppedHeaders != nil {
+		t.Fatalf("expected the same-origin hop to keep its headers, got %+v", resp.Redirects)
+	}
+	if want := []string{"Authorization", "X-Api-Key", "X-Tenant-Secret"}; !slices.Equal(resp.Redirects[1].DroppedHeaders, want) {
+		t.Errorf("DroppedHeaders = %v, want %v", resp.Redirects[1].DroppedHeaders, want)
+	}
+}
+
+func Test_sameOrigin(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"https://api.example.com/a", "https://API.example.com:443/b", true},
+		{"https://api.example.com", "http://api.example.com", false},
+		{"https://api.example.com", "https://api.example.com:8443", false},
+		{"https://api.example.com", "https://cdn.example.com", false},
+	}
+	for _, tt := range tests {
+		a, _ := url.Parse(tt.a)
+		b, _ := url.Parse(tt.b)
+		if got := sameOrigin(a, b); got != tt.same {
+			t.Errorf("sameOrigin(%s, %s) = %t, want %t", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
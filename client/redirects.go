@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrTooManyRedirects is returned when a redirect chain exceeds the default
+// limit of 10 without RequestParams.MaxRedirects set.
+var ErrTooManyRedirects = errors.New("stopped after 10 redirects")
+
+// RedirectHop is one followed redirect: the URL that answered with a redirect
+// status, that status, and the cookies it set.
+type RedirectHop struct {
+	URL            string
+	StatusCode     int
+	SetCookies     []string // raw Set-Cookie header values
+	DroppedHeaders []string // credentials not sent on because the redirect leaves the original origin
+}
+
+// redirectRecorder wraps a CheckRedirect policy to record followed hops and
+// stop at maxRedirects (0 means the default limit of 10). At the limit the
+// last redirect response is returned instead of an error, so the caller still
+// sees the chain and the Location it stopped at. A hop to another origin than
+// the original request's loses its credentials (see stripCrossOriginHeaders).
+type redirectRecorder struct {
+	injected    map[string]string                         // the default headers sent with the original request
+	defaultsFor func(requestURL string) map[string]string // the default headers a request to a URL gets
+
+	mu           sync.Mutex
+	hops         []RedirectHop
+	limitReached bool
+}
+
+func (r *redirectRecorder) checkRedirect(next func(*http.Request, []*http.Request) error, maxRedirects int) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if next != nil {
+			if err := next(req, via); err != nil {
+				return err
+			}
+		} else if maxRedirects == 0 && len(via) >= 10 {
+			return ErrTooManyRedirects
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if maxRedirects > 0 && len(via) > maxRedirects {
+			r.limitReached = true
+			return http.ErrUseLastResponse
+		}
+		var targetDefaults map[string]string
+		if r.defaultsFor != nil {
+			targetDefaults = r.defaultsFor(req.URL.String())
+		}
+		r.hops = append(r.hops, RedirectHop{
+			URL:            via[len(via)-1].URL.String(),
+			StatusCode:     req.Response.StatusCode,
+			SetCookies:     req.Response.Header.Values("Set-Cookie"),
+			DroppedHeaders: stripCrossOriginHeaders(req, via[0], r.injected, targetDefaults),
+		})
+		return nil
+	}
+}
+
+func (r *redirectRecorder) result() ([]RedirectHop, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hops, r.limitReached
+}
+
+// checkRedirectTargets wraps a CheckRedirect policy so check can refuse each
+// redirect target, with the method it would be sent with, before it is
+// followed. next may be nil (the http default).
+func checkRedirectTargets(check func(method, url string) error, next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := check(req.Method, req.URL.String()); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return ErrTooManyRedirects
+		}
+		return nil
+	}
+}
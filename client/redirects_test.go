@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newRedirectChainServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.Redirect(w, r, "/sso", http.StatusFound)
+		case "/sso":
+			http.SetCookie(w, &http.Cookie{Name: "sso", Value: "token"})
+			http.Redirect(w, r, "/callback", http.StatusSeeOther)
+		case "/callback":
+			http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+		default:
+			w.Write([]byte("home"))
+		}
+	}))
+}
+
+func Test_ExecuteRequest_RedirectChain(t *testing.T) {
+	server := newRedirectChainServer()
+	defer server.Close()
+	c := NewClient(Config{Timeout: 5 * time.Second})
+
+	tests := []struct {
+		name         string
+		maxRedirects int
+		follow       bool
+		wantStatus   int
+		wantFinal    string
+		wantHops     []RedirectHop
+		wantLimit    bool
+	}{
+		{"full chain", 0, true, 200, "/home", []RedirectHop{{URL: "/login", StatusCode: 302}, {URL: "/sso", StatusCode: 303}, {URL: "/callback", StatusCode: 301}}, false},
+		{"limit", 2, true, 301, "/callback", []RedirectHop{{URL: "/login", StatusCode: 302}, {URL: "/sso", StatusCode: 303}}, true},
+		{"not following", 0, false, 302, "/login", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+				Method: "GET", URL: server.URL + "/login", FollowRedirects: tt.follow, MaxRedirects: tt.maxRedirects,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus || resp.FinalURL != server.URL+tt.wantFinal || resp.RedirectLimitReached != tt.wantLimit {
+				t.Errorf("got status %d, final %s, limit %v", resp.StatusCode, resp.FinalURL, resp.RedirectLimitReached)
+			}
+			if len(resp.Redirects) != len(tt.wantHops) {
+				t.Fatalf("expected %d hops, got %+v", len(tt.wantHops), resp.Redirects)
+			}
+			if tt.follow && len(resp.Redirects) > 1 && (len(resp.Redirects[1].SetCookies) != 1 || resp.Redirects[1].SetCookies[0] != "sso=token") {
+				t.Errorf("expected the /sso hop's cookie recorded, got %+v", resp.Redirects[1])
+			}
+			for i, hop := range tt.wantHops {
+				if resp.Redirects[i].URL != server.URL+hop.URL || resp.Redirects[i].StatusCode != hop.StatusCode {
+					t.Errorf("hop %d: got %+v, want %s (%d)", i, resp.Redirects[i], hop.URL, hop.StatusCode)
+				}
+			}
+		})
+	}
+}
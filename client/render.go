@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// RenderedRequest is a request as ExecuteRequest would send it, for dry runs.
+type RenderedRequest struct {
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    []byte // nil without a body; the full multipart encoding for uploads
+}
+
+// RenderRequest resolves params exactly as ExecuteRequest does — base URL,
+// default and host-scoped headers, query merging, multipart encoding — without
+// sending anything.
+func (c *Client) RenderRequest(ctx context.Context, params RequestParams) (*RenderedRequest, error) {
+	requestURL, err := buildRequestURL(c.baseURL, c.defaultQueryParams, c.strictURLs, params)
+	if err != nil {
+		return nil, err
+	}
+	if params, err = c.prepareJSONBody(requestURL, params); err != nil {
+		return nil, err
+	}
+	req, err := newHTTPRequest(ctx, params.Method, requestURL, c.settingsFor(requestURL).defaultHeaders, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.dropExpect {
+		req.Header.Del("Expect")
+	}
+	if c.tokenSourceFor(requestURL) != nil && req.Header.Get("Authorization") == "" && !removesHeader(params.Headers, "Authorization") {
+		req.Header.Set("Authorization", "Bearer (OAuth2 access token, fetched when sent)")
+	}
+
+	rendered := &RenderedRequest{Method: req.Method, URL: requestURL, Headers: sentHeaders(req)}
+	if req.Body != nil {
+		rendered.Body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rendered, nil
+}
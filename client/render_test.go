@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_RenderRequest_ResolvesWithoutSending(t *testing.T) {
+	c := NewClient(Config{
+		BaseURL:            "http://127.0.0.1:1", // nothing listens here; a send would fail
+		DefaultHeaders:     map[string]string{"Accept": "application/json", "X-Env": "dev"},
+		DefaultQueryParams: map[string]string{"api_version": "2"},
+		HostRules:          []HostRule{{Match: "127.0.0.1", DefaultHeaders: map[string]string{"X-Scoped": "yes"}}},
+	})
+
+	rendered, err := c.RenderRequest(context.Background(), RequestParams{
+		Method:  "POST",
+		URL:     "/orders",
+		Headers: map[string]string{"X-Env": "override"},
+		Body:    `{"id":1}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rendered.URL != "http://127.0.0.1:1/orders?api_version=2" {
+		t.Errorf("unexpected URL %s", rendered.URL)
+	}
+	if rendered.Headers.Get("X-Env") != "override" || rendered.Headers.Get("X-Scoped") != "yes" || rendered.Headers.Get("Accept") != "application/json" {
+		t.Errorf("unexpected headers %v", rendered.Headers)
+	}
+	if string(rendered.Body) != `{"id":1}` {
+		t.Errorf("unexpected body %q", rendered.Body)
+	}
+}
+
+func Test_RenderRequest_Multipart(t *testing.T) {
+	rendered, err := NewClient(Config{}).RenderRequest(context.Background(), RequestParams{
+		Method:     "POST",
+		URL:        "http://example.com/upload",
+		FormFields: map[string]string{"note": "hello"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(rendered.Headers.Get("Content-Type"), "multipart/form-data; boundary=") {
+		t.Errorf("expected multipart content type, got %v", rendered.Headers)
+	}
+	if !strings.Contains(string(rendered.Body), "hello") {
+		t.Errorf("expected encoded field in body, got %q", rendered.Body)
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func readResponseBody(resp *http.Response, maxResponseSize int64) ([]byte, bool, int64, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("reading response body: %w", err)
+	}
+
+	truncated := int64(len(body)) > maxResponseSize
+	var originalSize int64
+	if truncated {
+		originalSize = resp.ContentLength
+		if originalSize <= 0 {
+			originalSize = int64(len(body))
+		}
+		body = body[:maxResponseSize]
+	}
+
+	return body, truncated, originalSize, nil
+}
+
+// saveResponseBody streams the body to a temp file and renames it into place,
+// so a mid-stream failure never leaves a partial file at the target path.
+// When the connection breaks and resume is set, the rest is requested with a
+// Range header; a resource that changed meanwhile is downloaded again whole.
+// Returns the size and hex SHA-256 of the body, computed while streaming.
+func saveResponseBody(resp *http.Response, path string, progress ProgressFunc, resume rangeRequester) (int64, string, error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".rest-api-mcp-*.tmp")
+	if err != nil {
+		resp.Body.Close()
+		return 0, "", fmt.Errorf("creating temp file for %s: %w", path, err)
+	}
+	tmpPath := tmpFile.Name()
+
+	hash := sha256.New()
+	var written int64
+	validator := resumeValidator(resp)
+	for resumes := 0; ; resumes++ {
+		n, copyErr := io.Copy(io.MultiWriter(tmpFile, hash), withProgress(resp, progress, written))
+		resp.Body.Close()
+		written += n
+		if copyErr == nil {
+			break
+		}
+		if resume == nil || validator == "" || resumes == maxDownloadResumes {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+			return 0, "", fmt.Errorf("writing response to %s: %w", path, copyErr)
+		}
+		if progress != nil {
+			progress(fmt.Sprintf("download interrupted at %s (%s), resuming", formatByteCount(written), copyErr))
+		}
+		next, err := resume(written, validator)
+		if err == nil && next.StatusCode == http.StatusOK {
+			// If-Range did not match: the resource changed, start over.
+			if _, err = tmpFile.Seek(0, io.SeekStart); err == nil {
+				err = tmpFile.Truncate(0)
+			}
+			hash.Reset()
+			written, validator = 0, resumeValidator(next)
+		} else if err == nil && (next.StatusCode != http.StatusPartialContent || contentRangeStart(next.Header.Get("Content-Range")) != written) {
+			next.Body.Close()
+			err = fmt.Errorf("resuming got %s", next.Status)
+		}
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+			return 0, "", fmt.Errorf("writing response to %s: %w (resuming at byte %d: %w)", path, copyErr, written, err)
+		}
+		resp = next
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, "", fmt.Errorf("closing %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return 0, "", fmt.Errorf("renaming %s to %s: %w", tmpPath, path, err)
+	}
+	return written, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// withProgress wraps the body so progress hears about the download; progress
+// may be nil. offset is what an earlier, resumed response already delivered.
+func withProgress(resp *http.Response, progress ProgressFunc, offset int64) io.Reader {
+	if progress == nil {
+		return resp.Body
+	}
+	total := resp.ContentLength
+	if total > 0 {
+		total += offset
+	}
+	return &progressReader{reader: resp.Body, total: total, read: offset, progress: progress, lastReport: time.Now()}
+}
+
+// progressReportInterval throttles download progress to one update per interval.
+const progressReportInterval = time.Second
+
+// progressReader reports how much of a streamed body has been read so far.
+type progressReader struct {
+	reader     io.Reader
+	total      int64 // Content-Length; <= 0 when unknown
+	read       int64
+	progress   ProgressFunc
+	lastReport time.Time
+}
+
+func (p *progressReader) Read(buffer []byte) (int, error) {
+	n, err := p.reader.Read(buffer)
+	p.read += int64(n)
+	if time.Since(p.lastReport) >= progressReportInterval {
+		p.lastReport = time.Now()
+		if p.total > 0 {
+			p.progress(fmt.Sprintf("downloaded %s of %s", formatByteCount(p.read), formatByteCount(p.total)))
+		} else {
+			p.progress(fmt.Sprintf("downloaded %s", formatByteCount(p.read)))
+		}
+	}
+	return n, err
+}
+
+func formatByteCount(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d bytes", bytes)
+	}
+}
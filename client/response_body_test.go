@@ -0,0 +1,46 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_ProgressReader_ReportsBytes(t *testing.T) {
+	var messages []string
+	reader := &progressReader{
+		reader:     bytes.NewReader(make([]byte, 3*1024*1024)),
+		total:      3 * 1024 * 1024,
+		progress:   func(message string) { messages = append(messages, message) },
+		lastReport: time.Now().Add(-time.Hour),
+	}
+
+	buffer := make([]byte, 2*1024*1024)
+	reader.Read(buffer)
+
+	if len(messages) != 1 || messages[0] != "downloaded 2.0 MB of 3.0 MB" {
+		t.Errorf("unexpected progress messages: %v", messages)
+	}
+
+	reader.Read(buffer)
+	if len(messages) != 1 {
+		t.Errorf("expected throttled progress, got: %v", messages)
+	}
+}
+
+func Test_FormatByteCount(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{512, "512 bytes"},
+		{2048, "2.0 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := formatByteCount(tt.bytes); got != tt.want {
+			t.Errorf("formatByteCount(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
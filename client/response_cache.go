@@ -0,0 +1,271 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotCached is returned for CacheOnly requests without a cached response.
+var ErrNotCached = errors.New("no cached response for this request")
+
+// Cache modes for RequestParams.Cache. The default ("") serves fresh cached
+// GET responses and stores new ones.
+const (
+	CacheBypass  = "bypass"  // neither read nor write the cache
+	CacheRefresh = "refresh" // always fetch, then store the response
+	CacheOnly    = "only"    // answer from the cache, stale or not, never the network
+)
+
+// responseCache stores 200 responses to GET requests in dir, one JSON file per
+// URL and set of key headers (see variantKey). Entries are fresh for the response's Cache-Control max-age (or Expires),
+// or for ttl when it is set; responses without a lifetime are not stored.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+func newResponseCache(dir string, ttl time.Duration) *responseCache {
+	if dir == "" {
+		return nil
+	}
+	return &responseCache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// cachedResponse is one cache file. Vary holds the request header values the
+// response varies on; Variant identifies the credentials and content
+// negotiation headers it was fetched with, so responses are never shared
+// across identities or representations.
+type cachedResponse struct {
+	URL        string            `json:"url"`
+	Vary       map[string]string `json:"vary,omitempty"`
+	Variant    string            `json:"variant,omitempty"`
+	StoredAt   time.Time         `json:"storedAt"`
+	Expires    time.Time         `json:"expires"`
+	StatusCode int               `json:"status"`
+	Headers    http.Header       `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodyBase64 string            `json:"bodyBase64,omitempty"`
+}
+
+// cacheLookup reports whether the last round trip was answered from the cache.
+type cacheLookup struct {
+	hit bool
+	age time.Duration
+}
+
+// withResponseCache routes requestClient through the cache unless the request
+// bypasses it. The returned lookup is nil when the cache is not involved.
+func (c *Client) withResponseCache(requestClient *http.Client, params RequestParams) (*cacheLookup, error) {
+	if c.responseCache == nil {
+		if params.Cache == CacheOnly {
+			return nil, fmt.Errorf("cache %q needs a response cache (--cache-dir)", CacheOnly)
+		}
+		return nil, nil
+	}
+	if params.Cache == CacheBypass {
+		return nil, nil
+	}
+	maxBodySize := c.maxResponseSize
+	if params.MaxResponseSize > 0 {
+		maxBodySize = params.MaxResponseSize
+	}
+	lookup := &cacheLookup{}
+	requestClient.Transport = cacheTransport{cache: c.responseCache, next: requestClient.Transport, mode: params.Cache, maxBodySize: maxBodySize, lookup: lookup}
+	return lookup, nil
+}
+
+type cacheTransport struct {
+	cache       *responseCache
+	next        http.RoundTripper
+	mode        string
+	maxBodySize int64
+	lookup      *cacheLookup
+}
+
+func (t cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lookup.hit = false
+	if req.Method != http.MethodGet {
+		if t.mode == CacheOnly {
+			return nil, fmt.Errorf("%w: only GET responses are cached", ErrNotCached)
+		}
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.cache.path(req)
+	if t.mode != CacheRefresh {
+		if entry, ok := t.cache.load(path, req); ok && (t.mode == CacheOnly || t.cache.now().Before(entry.Expires)) {
+			return t.cache.serve(entry, req, t.lookup)
+		}
+	}
+	if t.mode == CacheOnly {
+		return nil, fmt.Errorf("%w: %s", ErrNotCached, req.URL)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	lifetime := t.cache.lifetime(resp.Header)
+	if lifetime <= 0 || resp.Header.Get("Vary") == "*" || resp.ContentLength > t.maxBodySize {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("reading response body for the cache: %w", err)
+	}
+	if int64(len(body)) > t.maxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The cache is an optimization: a full disk must not fail the request.
+	_ = t.cache.store(path, req, resp, body, lifetime)
+	return resp, nil
+}
+
+// load returns the cached response for req when one matches its URL, Vary
+// headers and variant. Unreadable files count as misses.
+func (rc *responseCache) load(path string, req *http.Request) (cachedResponse, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != cacheURL(req) {
+		return cachedResponse{}, false
+	}
+	if entry.Variant != variantKey(req) {
+		return cachedResponse{}, false
+	}
+	for name, value := range entry.Vary {
+		if req.Header.Get(name) != value {
+			return cachedResponse{}, false
+		}
+	}
+	return entry, true
+}
+
+func (rc *responseCache) serve(entry cachedResponse, req *http.Request, lookup *cacheLookup) (*http.Response, error) {
+	body, err := decodeCassetteBody(entry.Body, entry.BodyBase64)
+	if err != nil {
+		return nil, err
+	}
+	age := rc.now().Sub(entry.StoredAt)
+	lookup.hit, lookup.age = true, age
+	headers := entry.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set("Age", strconv.Itoa(int(age.Seconds())))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (rc *responseCache) store(path string, req *http.Request, resp *http.Response, body []byte, lifetime time.Duration) error {
+	now := rc.now()
+	entry := cachedResponse{
+		URL:        cacheURL(req),
+		Variant:    variantKey(req),
+		StoredAt:   now,
+		Expires:    now.Add(lifetime),
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header.Clone(),
+	}
+	entry.Headers.Del("Set-Cookie") // replaying it would reset cookies the session has since changed
+	for _, name := range resp.Header.Values("Vary") {
+		for _, field := range strings.Split(name, ",") {
+			if field = http.CanonicalHeaderKey(strings.TrimSpace(field)); field != "" {
+				if entry.Vary == nil {
+					entry.Vary = make(map[string]string)
+				}
+				entry.Vary[field] = req.Header.Get(field)
+			}
+		}
+	}
+	entry.Body, entry.BodyBase64 = encodeCassetteBody(body)
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	if err := os.MkdirAll(rc.dir, 0o700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(rc.dir, ".entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return fmt.Errorf("writing cache entry %s: %w", path, err)
+	}
+	return nil
+}
+
+// lifetime is how long a response stays fresh: the forced ttl, else
+// Cache-Control max-age, else Expires. no-store and no-cache mean zero.
+func (rc *responseCache) lifetime(header http.Header) time.Duration {
+	maxAge := time.Duration(-1)
+	for _, directive := range strings.Split(strings.ToLower(strings.Join(header.Values("Cache-Control"), ",")), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch name {
+		case "no-store":
+			return 0
+		case "no-cache":
+			if rc.ttl == 0 {
+				return 0
+			}
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	switch {
+	case rc.ttl > 0:
+		return rc.ttl
+	case maxAge >= 0:
+		return maxAge
+	}
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		return 0
+	}
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = rc.now()
+	}
+	return expires.Sub(date)
+}
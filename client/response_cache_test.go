@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_ResponseCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		vary         string
+		ttl          time.Duration
+		first        RequestParams
+		second       RequestParams
+		wantCached   bool
+		wantRequests int32
+	}{
+		{"max-age response is reused", "max-age=60", "", 0, RequestParams{}, RequestParams{}, true, 1},
+		{"no-store response is not stored", "no-store", "", 0, RequestParams{}, RequestParams{}, false, 2},
+		{"no lifetime is not stored", "", "", 0, RequestParams{}, RequestParams{}, false, 2},
+		{"forced ttl overrides no-cache", "no-cache", "", time.Minute, RequestParams{}, RequestParams{}, true, 1},
+		{"refresh fetches again", "max-age=60", "", 0, RequestParams{}, RequestParams{Cache: CacheRefresh}, false, 2},
+		{"bypass skips the cache", "max-age=60", "", 0, RequestParams{}, RequestParams{Cache: CacheBypass}, false, 2},
+		{"other credentials miss", "max-age=60", "", 0,
+			RequestParams{Headers: map[string]string{"Authorization": "Bearer a"}},
+			RequestParams{Headers: map[string]string{"Authorization": "Bearer b"}}, false, 2},
+		{"other API key misses", "max-age=60", "", 0,
+			RequestParams{Headers: map[string]string{"X-Api-Key": "a"}},
+			RequestParams{Headers: map[string]string{"X-Api-Key": "b"}}, false, 2},
+		{"other Accept misses without Vary", "max-age=60", "", 0,
+			RequestParams{Headers: map[string]string{"Accept": "application/json"}},
+			RequestParams{Headers: map[string]string{"Accept": "text/csv"}}, false, 2},
+		{"varied header must match", "max-age=60", "Accept-Language", 0,
+			RequestParams{Headers: map[string]string{"Accept-Language": "en"}},
+			RequestParams{Headers: map[string]string{"Accept-Language": "de"}}, false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count := requests.Add(1)
+				if tt.cacheControl != "" {
+					w.Header().Set("Cache-Control", tt.cacheControl)
+				}
+				if tt.vary != "" {
+					w.Header().Set("Vary", tt.vary)
+				}
+				w.Write([]byte("response " + strconv.Itoa(int(count))))
+			}))
+			defer server.Close()
+
+			c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, CacheDir: t.TempDir(), CacheTTL: tt.ttl})
+			for _, params := range []*RequestParams{&tt.first, &tt.second} {
+				params.Method, params.URL = "GET", server.URL+"/reference"
+			}
+			if _, err := c.ExecuteRequest(context.Background(), tt.first); err != nil {
+				t.Fatalf("first request: %v", err)
+			}
+			resp, err := c.ExecuteRequest(context.Background(), tt.second)
+			if err != nil {
+				t.Fatalf("second request: %v", err)
+			}
+
+			if resp.Cached != tt.wantCached {
+				t.Errorf("Cached = %v, want %v", resp.Cached, tt.wantCached)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("server saw %d requests, want %d", got, tt.wantRequests)
+			}
+			if tt.wantCached && string(resp.Body) != "response 1" {
+				t.Errorf("body = %q, want the cached response 1", resp.Body)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_CacheKeepsVariantsApart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("for " + r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, CacheDir: t.TempDir()})
+	request := func(token string) *Response {
+		resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/me", Headers: map[string]string{"Authorization": token}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	request("a")
+	request("b")
+	if resp := request("a"); !resp.Cached || string(resp.Body) != "for a" {
+		t.Errorf("expected a's response still cached after b's, got cached=%v body=%q", resp.Cached, resp.Body)
+	}
+}
+
+func Test_ExecuteRequest_CacheOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=1")
+		w.Write([]byte("cached"))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, CacheDir: t.TempDir()})
+	c.responseCache.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	_, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/missing", Cache: CacheOnly})
+	if !errors.Is(err, ErrNotCached) {
+		t.Fatalf("err = %v, want ErrNotCached", err)
+	}
+
+	if _, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/stale"}); err != nil {
+		t.Fatalf("filling the cache: %v", err)
+	}
+	c.responseCache.now = time.Now
+	server.Close()
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + "/stale", Cache: CacheOnly})
+	if err != nil {
+		t.Fatalf("stale entry with cache only: %v", err)
+	}
+	if !resp.Cached || string(resp.Body) != "cached" || resp.CacheAge < time.Hour {
+		t.Errorf("got cached=%v body=%q age=%s, want the stale entry", resp.Cached, resp.Body, resp.CacheAge)
+	}
+}
+
+func Test_responseCache_lifetime(t *testing.T) {
+	date := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		ttl    time.Duration
+		want   time.Duration
+	}{
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=300"}}, 0, 5 * time.Minute},
+		{"expires relative to date", http.Header{"Expires": {date.Add(time.Hour).Format(http.TimeFormat)}, "Date": {date.Format(http.TimeFormat)}}, 0, time.Hour},
+		{"max-age wins over expires", http.Header{"Cache-Control": {"max-age=10"}, "Expires": {date.Add(time.Hour).Format(http.TimeFormat)}, "Date": {date.Format(http.TimeFormat)}}, 0, 10 * time.Second},
+		{"no-store beats forced ttl", http.Header{"Cache-Control": {"no-store"}}, time.Minute, 0},
+		{"forced ttl without headers", http.Header{}, time.Minute, time.Minute},
+		{"nothing", http.Header{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newResponseCache(t.TempDir(), tt.ttl)
+			if got := cache.lifetime(tt.header); got != tt.want {
+				t.Errorf("lifetime = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// readOrSpillResponseBody buffers at most threshold bytes of the body. A
+// larger body is streamed, buffered part first, to a temp file in dir while
+// its size and SHA-256 are computed; response.Body then holds its first
+// maxResponseSize bytes as a preview. Nothing is discarded either way.
+func readOrSpillResponseBody(resp *http.Response, maxResponseSize, threshold int64, dir string, progress ProgressFunc, response *Response) error {
+	defer resp.Body.Close()
+	body := withProgress(resp, progress, 0)
+	head, err := io.ReadAll(io.LimitReader(body, threshold+1))
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if int64(len(head)) <= threshold {
+		response.Body = head
+		if int64(len(head)) > maxResponseSize {
+			response.Body = head[:maxResponseSize]
+			response.Truncated = true
+			response.OriginalSize = int64(len(head))
+		}
+		return nil
+	}
+
+	file, err := os.CreateTemp(dir, "rest-api-mcp-response-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file for a large response: %w", err)
+	}
+	hash := sha256.New()
+	writer := io.MultiWriter(file, hash)
+	_, err = writer.Write(head)
+	var rest int64
+	if err == nil {
+		rest, err = io.Copy(writer, body)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("streaming response to %s: %w", file.Name(), err)
+	}
+
+	response.Body = head[:min(maxResponseSize, int64(len(head)))]
+	response.Truncated = true
+	response.OriginalSize = int64(len(head)) + rest
+	response.SavedPath = file.Name()
+	response.SavedSize = response.OriginalSize
+	response.SavedSHA256 = hex.EncodeToString(hash.Sum(nil))
+	response.Spilled = true
+	return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_SpillsLargeBody(t *testing.T) {
+	small := strings.Repeat("s", 100)
+	large := strings.Repeat("0123456789", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.Write([]byte(large))
+			return
+		}
+		w.Write([]byte(small))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := NewClient(Config{Timeout: 5 * time.Second, MaxResponseSize: 50, SpillThreshold: 1000, SpillDir: dir})
+
+	tests := []struct {
+		name        string
+		path        string
+		wantSpilled bool
+		wantBody    string
+		wantSize    int64
+	}{
+		{"below threshold is only truncated", "/small", false, small[:50], 100},
+		{"above threshold goes to disk", "/large", true, large[:50], int64(len(large))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := c.ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL + tt.path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Spilled != tt.wantSpilled || string(resp.Body) != tt.wantBody || resp.OriginalSize != tt.wantSize || !resp.Truncated {
+				t.Fatalf("got spilled=%v body=%q size=%d truncated=%v", resp.Spilled, resp.Body, resp.OriginalSize, resp.Truncated)
+			}
+			if !tt.wantSpilled {
+				return
+			}
+			saved, err := os.ReadFile(resp.SavedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum := sha256.Sum256([]byte(large))
+			if string(saved) != large || resp.SavedSHA256 != hex.EncodeToString(sum[:]) || filepath.Dir(resp.SavedPath) != dir {
+				t.Errorf("saved %d bytes to %s with hash %s", len(saved), resp.SavedPath, resp.SavedSHA256)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_SaveToReportsHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test"))
+	}))
+	defer server.Close()
+
+	target := filepath.Join(t.TempDir(), "out.txt")
+	resp, err := NewClient(Config{Timeout: 5 * time.Second}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL, SaveTo: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.SavedSHA256 != "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" || resp.SavedSize != 4 {
+		t.Errorf("saved %d bytes, sha256 %s", resp.SavedSize, resp.SavedSHA256)
+	}
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Stream protocols Subscribe speaks.
+const (
+	StreamSSE       = "sse"       // Server-Sent Events: a GET answered with text/event-stream
+	StreamWebSocket = "websocket" // RFC 6455 WebSocket, text and binary messages
+)
+
+// maxSSELineSize bounds one line of an event stream; longer lines end the stream.
+const maxSSELineSize = 1 << 20
+
+// StreamParams describes a subscription to an event stream.
+type StreamParams struct {
+	Protocol     string // StreamSSE or StreamWebSocket
+	URL          string // http(s) or ws(s); relative to the base URL like RequestParams.URL
+	Headers      map[string]string
+	QueryParams  []QueryParam
+	Send         []string      // WebSocket text messages sent once connected, e.g. a subscribe request
+	MaxEvents    int           // stop after this many events; 0 means no limit
+	Duration     time.Duration // stop after this long, handshake included; 0 means until the stream ends
+	MaxEventSize int64         // data kept per event; 0 means the client's response size limit
+	Progress     ProgressFunc  // optional; hears about every event
+	// Stop ends the subscription after the event it returns true for; nil never stops early.
+	Stop func(StreamEvent) bool
+}
+
+// StreamEvent is one Server-Sent Event or WebSocket message.
+type StreamEvent struct {
+	Offset    time.Duration // since the stream opened
+	Type      string        // the SSE event field ("message" when unset), or text or binary for WebSocket
+	ID        string        // the SSE id field
+	Data      string        // binary WebSocket messages are base64-encoded
+	Size      int           // bytes received, before truncation
+	Truncated bool
+}
+
+// StreamResult is what a subscription received.
+type StreamResult struct {
+	URL        string // the resolved handshake URL
+	StatusCode int
+	Headers    http.Header
+	Events     []StreamEvent
+	Duration   time.Duration
+	Stopped    string // why the subscription ended
+	Body       []byte // the response body when the server did not open the stream
+}
+
+// HandshakeURL returns the http(s) URL a ws(s) URL is opened with; other URLs
+// are returned unchanged. Policies see the URL the handshake is sent to.
+func HandshakeURL(rawURL string) string {
+	lower := strings.ToLower(rawURL)
+	switch {
+	case strings.HasPrefix(lower, "ws://"):
+		return "http://" + rawURL[len("ws://"):]
+	case strings.HasPrefix(lower, "wss://"):
+		return "https://" + rawURL[len("wss://"):]
+	}
+	return rawURL
+}
+
+// Subscribe opens an event stream and collects its events until MaxEvents,
+// Duration, Stop or the server ends it. The handshake carries the default,
+// host-scoped and OAuth2 headers like any request. Streams are not retried,
+// cached, recorded or coalesced, and redirects are not followed.
+func (c *Client) Subscribe(ctx context.Context, params StreamParams) (*StreamResult, error) {
+	requestURL, err := buildRequestURL(c.baseURL, c.defaultQueryParams, c.strictURLs, RequestParams{URL: HandshakeURL(params.URL), QueryParams: params.QueryParams})
+	if err != nil {
+		return nil, err
+	}
+	if c.cassettes != nil && c.cassettes.replayDir != "" {
+		return nil, fmt.Errorf("%w: event streams are not recorded", ErrNoCassette)
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	if params.Duration > 0 {
+		streamCtx, cancel = context.WithTimeout(ctx, params.Duration)
+	}
+	defer cancel()
+
+	hostSettings := c.settingsFor(requestURL)
+	requestClient := *c.httpClient
+	if hostSettings.transport != nil {
+		requestClient.Transport = hostSettings.transport
+	}
+	requestClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if c.checkHostsByName {
+		if err := checkRequestURL(streamCtx, requestURL); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := newHTTPRequest(streamCtx, http.MethodGet, requestURL, hostSettings.defaultHeaders, RequestParams{Method: http.MethodGet, Headers: params.Headers})
+	if err != nil {
+		return nil, err
+	}
+	if !removesHeader(params.Headers, "Authorization") {
+		if err := c.authorize(req); err != nil {
+			return nil, err
+		}
+	}
+	var key string
+	if params.Protocol == StreamWebSocket {
+		if key, err = newWebSocketKey(); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", key)
+	} else if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	start := time.Now()
+	resp, err := requestClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s stream %s: %w", params.Protocol, requestURL, err)
+	}
+	defer resp.Body.Close()
+	// An upgraded connection ignores the request context, so closing the body
+	// is what ends a read blocked past the duration.
+	defer context.AfterFunc(streamCtx, func() { resp.Body.Close() })()
+
+	result := &StreamResult{URL: requestURL, StatusCode: resp.StatusCode, Headers: resp.Header}
+	maxEventSize := params.MaxEventSize
+	if maxEventSize <= 0 {
+		maxEventSize = c.maxResponseSize
+	}
+	if refusal := streamRefusal(params.Protocol, resp, key); refusal != "" {
+		result.Body, _ = io.ReadAll(io.LimitReader(resp.Body, maxEventSize))
+		result.Stopped, result.Duration = refusal, time.Since(start)
+		return result, nil
+	}
+
+	collector := &eventCollector{params: params, started: time.Now(), maxSize: maxEventSize}
+	var readErr error
+	if params.Protocol == StreamWebSocket {
+		readErr = readWebSocket(resp.Body.(io.ReadWriter), params.Send, collector)
+	} else {
+		readErr = readSSE(resp.Body, collector)
+	}
+	result.Events, result.Duration = collector.events, time.Since(start)
+	var closed *webSocketClosed
+	switch {
+	case collector.stopped != "":
+		result.Stopped = collector.stopped
+	case ctx.Err() != nil:
+		result.Stopped = "cancelled"
+	case streamCtx.Err() != nil:
+		result.Stopped = "duration elapsed"
+	case errors.As(readErr, &closed):
+		result.Stopped = closed.Error()
+	case readErr == nil || errors.Is(readErr, io.EOF):
+		result.Stopped = "the server ended the stream"
+	default:
+		result.Stopped = fmt.Sprintf("reading the stream failed: %s", readErr)
+	}
+	return result, nil
+}
+
+// streamRefusal explains why a response does not open the stream; "" when it does.
+func streamRefusal(protocol string, resp *http.Response, key string) string {
+	if protocol == StreamWebSocket {
+		switch {
+		case resp.StatusCode != http.StatusSwitchingProtocols:
+			return fmt.Sprintf("the server did not upgrade to WebSocket: %s", resp.Status)
+		case resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key):
+			return "the server's Sec-WebSocket-Accept does not match the handshake key"
+		}
+		if _, ok := resp.Body.(io.ReadWriter); !ok {
+			return "the upgraded connection is not writable"
+		}
+		return ""
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf("the server did not open the event stream: %s", resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		return fmt.Sprintf("the response is %s, not text/event-stream", resp.Header.Get("Content-Type"))
+	}
+	return ""
+}
+
+// eventCollector keeps the events of a subscription and decides when it is done.
+type eventCollector struct {
+	params  StreamParams
+	started time.Time
+	maxSize int64
+	events  []StreamEvent
+	stopped string
+}
+
+// add records an event and reports whether the subscription is done.
+func (e *eventCollector) add(kind, id string, data []byte, size int, binary bool) bool {
+	event := StreamEvent{Offset: time.Since(e.started), Type: kind, ID: id, Size: size, Truncated: size > len(data)}
+	if int64(len(data)) > e.maxSize {
+		data, event.Truncated = data[:e.maxSize], true
+	}
+	if binary {
+		event.Data = base64.StdEncoding.EncodeToString(data)
+	} else {
+		for event.Truncated && len(data) > 0 && !utf8.Valid(data) {
+			data = data[:len(data)-1] // a rune cut at the limit
+		}
+		event.Data = string(data)
+	}
+	e.events = append(e.events, event)
+	if e.params.Progress != nil {
+		e.params.Progress(fmt.Sprintf("event %d: %s, %d bytes", len(e.events), kind, size))
+	}
+	switch {
+	case e.params.Stop != nil && e.params.Stop(event):
+		e.stopped = "stop condition met"
+	case e.params.MaxEvents > 0 && len(e.events) >= e.params.MaxEvents:
+		e.stopped = fmt.Sprintf("%d events received", len(e.events))
+	}
+	return e.stopped != ""
+}
+
+// readSSE parses a text/event-stream body, dispatching an event at every blank
+// line that follows data. Comments and retry fields are ignored.
+func readSSE(body io.Reader, collector *eventCollector) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), max(maxSSELineSize, int(collector.maxSize)))
+	var kind, id string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != nil {
+				payload := strings.Join(data, "\n")
+				if kind == "" {
+					kind = "message"
+				}
+				if collector.add(kind, id, []byte(payload), len(payload), false) {
+					return nil
+				}
+			}
+			kind, data = "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			kind = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			id = value // the last event ID carries over to later events
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
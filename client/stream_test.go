@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Subscribe_ServerSentEvents(t *testing.T) {
+	var accept, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept, auth = r.Header.Get("Accept"), r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\nid: 1\nevent: update\ndata: {\"n\":1}\n\ndata: line one\ndata: line two\n\ndata: third\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, DefaultHeaders: map[string]string{"Authorization": "Bearer t"}})
+	result, err := c.Subscribe(context.Background(), StreamParams{Protocol: StreamSSE, URL: server.URL + "/events", MaxEvents: 2, Duration: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if accept != "text/event-stream" || auth != "Bearer t" {
+		t.Errorf("handshake sent Accept %q, Authorization %q", accept, auth)
+	}
+	if result.StatusCode != 200 || len(result.Events) != 2 || result.Stopped != "2 events received" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if event := result.Events[0]; event.Type != "update" || event.ID != "1" || event.Data != `{"n":1}` {
+		t.Errorf("first event = %+v", event)
+	}
+	if event := result.Events[1]; event.Type != "message" || event.ID != "1" || event.Data != "line one\nline two" {
+		t.Errorf("second event = %+v", event)
+	}
+}
+
+func Test_Subscribe_StopsAfterDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	result, err := NewClient(Config{}).Subscribe(context.Background(), StreamParams{Protocol: StreamSSE, URL: server.URL, Duration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stopped != "duration elapsed" || len(result.Events) != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func Test_Subscribe_RefusedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such topic", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	for _, protocol := range []string{StreamSSE, StreamWebSocket} {
+		result, err := NewClient(Config{}).Subscribe(context.Background(), StreamParams{Protocol: protocol, URL: server.URL, Duration: time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.StatusCode != 404 || !strings.Contains(result.Stopped, "404") || !strings.Contains(string(result.Body), "no such topic") {
+			t.Errorf("%s: unexpected result %+v", protocol, result)
+		}
+	}
+}
+
+func Test_Subscribe_WebSocket(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buffered, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(buffered, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", webSocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+		buffered.Flush()
+
+		_, _, payload := readTestFrame(t, buffered.Reader)
+		received <- string(payload)
+		writeTestFrame(conn, 0x80|opPing, []byte("are you there"))
+		if opcode, _, payload := readTestFrame(t, buffered.Reader); opcode != opPong || string(payload) != "are you there" {
+			t.Errorf("expected the ping answered, got opcode %x %q", opcode, payload)
+		}
+		writeTestFrame(conn, opText, []byte(`{"price":`))
+		conn.Write([]byte{0x80 | opContinuation, 3})
+		conn.Write([]byte("42}"))
+		writeTestFrame(conn, 0x80|opBinary, []byte{0xFF, 0x00})
+		writeTestFrame(conn, 0x80|opClose, append(binary.BigEndian.AppendUint16(nil, 1001), "going away"...))
+		readTestFrame(t, buffered.Reader)
+	}))
+	defer server.Close()
+
+	wsURL := "ws://" + strings.TrimPrefix(server.URL, "http://")
+	result, err := NewClient(Config{}).Subscribe(context.Background(), StreamParams{Protocol: StreamWebSocket, URL: wsURL, Send: []string{`{"subscribe":"ticker"}`}, Duration: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message := <-received; message != `{"subscribe":"ticker"}` {
+		t.Errorf("server received %q", message)
+	}
+	if len(result.Events) != 2 || result.Events[0].Data != `{"price":42}` || result.Events[1].Type != "binary" || result.Events[1].Data != "/wA=" {
+		t.Fatalf("unexpected events %+v", result.Events)
+	}
+	if result.StatusCode != 101 || result.Stopped != "the server closed the WebSocket (1001 going away)" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func Test_Subscribe_TruncatesLargeEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: %s\n\n", strings.Repeat("é", 10))
+	}))
+	defer server.Close()
+
+	result, err := NewClient(Config{}).Subscribe(context.Background(), StreamParams{Protocol: StreamSSE, URL: server.URL, MaxEventSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Events) != 1 || result.Events[0].Data != "éé" || !result.Events[0].Truncated || result.Events[0].Size != 20 {
+		t.Errorf("unexpected events %+v", result.Events)
+	}
+	if result.Stopped != "the server ended the stream" {
+		t.Errorf("Stopped = %q", result.Stopped)
+	}
+}
+
+func Test_HandshakeURL(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"ws://api.example.com/feed":   "http://api.example.com/feed",
+		"WSS://api.example.com/feed":  "https://api.example.com/feed",
+		"https://api.example.com/sse": "https://api.example.com/sse",
+		"/relative":                   "/relative",
+	} {
+		if got := HandshakeURL(rawURL); got != want {
+			t.Errorf("HandshakeURL(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+// writeTestFrame writes an unmasked server frame; the first byte carries FIN and the opcode.
+func writeTestFrame(w io.Writer, first byte, payload []byte) {
+	w.Write(append([]byte{first, byte(len(payload))}, payload...))
+}
+
+// readTestFrame reads a masked client frame.
+func readTestFrame(t *testing.T, reader *bufio.Reader) (byte, bool, []byte) {
+	t.Helper()
+	fin, opcode, length, mask, err := readFrameHeader(reader)
+	if err != nil {
+		t.Errorf("reading a client frame: %v", err)
+		return 0, false, nil
+	}
+	if mask == nil {
+		t.Error("client frames must be masked")
+	}
+	payload := make([]byte, length)
+	io.ReadFull(reader, payload)
+	unmask(payload, mask)
+	return opcode, fin, payload
+}
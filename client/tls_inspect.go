@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TLSInspection describes the outcome of a TLS handshake with a server.
+// The handshake itself never fails on certificate problems; those are reported
+// in VerificationError so the caller can still see the chain that was presented.
+type TLSInspection struct {
+	Address           string
+	ServerName        string
+	Protocol          string
+	CipherSuite       string
+	Certificates      []CertificateInfo
+	VerificationError string // empty when the chain verifies against the system roots
+}
+
+type CertificateInfo struct {
+	Subject     string
+	Issuer      string
+	DNSNames    []string
+	IPAddresses []string
+	NotBefore   time.Time
+	NotAfter    time.Time
+	IsCA        bool
+}
+
+// InspectTLS performs a TLS handshake with address (host:port) and reports the
+// negotiated parameters and the presented certificate chain. serverName
+// overrides the SNI and verification host name; empty means the address host.
+func (c *Client) InspectTLS(ctx context.Context, address, serverName string) (*TLSInspection, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("parsing address %s: %w", address, err)
+	}
+	if serverName == "" {
+		serverName = host
+	}
+
+	netDialer := &net.Dialer{Timeout: c.timeout}
+	if c.blockPrivateNetworks {
+		netDialer.Control = guardedDialControl
+	}
+	dialer := &tls.Dialer{
+		NetDialer: netDialer,
+		// Verification is done manually below so that a bad chain is reported
+		// rather than aborting the handshake before the certificates are seen.
+		Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake with %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	inspection := &TLSInspection{
+		Address:     address,
+		ServerName:  serverName,
+		Protocol:    tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+	for _, cert := range state.PeerCertificates {
+		inspection.Certificates = append(inspection.Certificates, describeCertificate(cert))
+	}
+	if err := verifyPeerChain(state.PeerCertificates, serverName); err != nil {
+		inspection.VerificationError = err.Error()
+	}
+	return inspection, nil
+}
+
+func describeCertificate(cert *x509.Certificate) CertificateInfo {
+	ipAddresses := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
+	}
+	return CertificateInfo{
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		DNSNames:    cert.DNSNames,
+		IPAddresses: ipAddresses,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		IsCA:        cert.IsCA,
+	}
+}
+
+func verifyPeerChain(certs []*x509.Certificate, serverName string) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("server presented no certificates")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
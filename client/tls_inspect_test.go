@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_InspectTLS_SelfSignedServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	address := strings.TrimPrefix(server.URL, "https://")
+
+	inspection, err := c.InspectTLS(context.Background(), address, "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inspection.Protocol == "" || inspection.CipherSuite == "" {
+		t.Errorf("expected negotiated protocol and cipher, got %q / %q", inspection.Protocol, inspection.CipherSuite)
+	}
+	if inspection.ServerName != "example.com" {
+		t.Errorf("expected SNI override example.com, got %q", inspection.ServerName)
+	}
+	if len(inspection.Certificates) == 0 {
+		t.Fatal("expected at least one certificate in the chain")
+	}
+	if !strings.Contains(strings.Join(inspection.Certificates[0].DNSNames, ","), "example.com") {
+		t.Errorf("expected example.com in SANs, got %v", inspection.Certificates[0].DNSNames)
+	}
+	if inspection.VerificationError == "" {
+		t.Error("expected verification error for self-signed test certificate")
+	}
+}
+
+func Test_InspectTLS_InvalidAddress(t *testing.T) {
+	c := NewClient(Config{Timeout: 5 * time.Second})
+
+	_, err := c.InspectTLS(context.Background(), "missing-port", "")
+	if err == nil {
+		t.Fatal("expected error for address without port")
+	}
+}
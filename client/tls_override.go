@@ -0,0 +1,58 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// overrideTransport returns a copy of base for a single request that skips
+// certificate verification, additionally trusts CACert (a PEM block or a path
+// to a PEM file), sends ServerName as SNI and verifies the certificate against
+// it, or is limited to one HTTP version. The caller closes its idle connections.
+func overrideTransport(base http.RoundTripper, params RequestParams) (*http.Transport, error) {
+	baseTransport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("per-request TLS and HTTP version settings are not supported by this transport")
+	}
+	transport := baseTransport.Clone()
+	tlsConfig := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+
+	if params.CACert != "" {
+		pemData := []byte(params.CACert)
+		if !strings.Contains(params.CACert, "-----BEGIN") {
+			var err error
+			if pemData, err = os.ReadFile(params.CACert); err != nil {
+				return nil, fmt.Errorf("reading caCert: %w", err)
+			}
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("caCert contains no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if params.InsecureTLS {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if params.ServerName != "" {
+		tlsConfig.ServerName = params.ServerName
+	}
+	transport.TLSClientConfig = tlsConfig
+	if protocols := httpProtocols(params.HTTPVersion); protocols != nil {
+		// A base that has spoken HTTP/2 advertises "h2" in its ALPN list; the
+		// transport sets the list again for the protocols allowed here.
+		tlsConfig.NextProtos = nil
+		transport.Protocols = protocols
+	}
+	return transport, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_PerRequestTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	defer server.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	tests := []struct {
+		name    string
+		params  RequestParams
+		wantErr string
+	}{
+		{"untrusted by default", RequestParams{}, "x509"},
+		{"insecure", RequestParams{InsecureTLS: true}, ""},
+		{"CA as PEM", RequestParams{CACert: caPEM}, ""},
+		{"CA as file", RequestParams{CACert: caFile}, ""},
+		{"missing CA file", RequestParams{CACert: filepath.Join(t.TempDir(), "missing.pem")}, "reading caCert"},
+		{"no certificates", RequestParams{CACert: "-----BEGIN nothing"}, "no PEM certificates"},
+		{"server name in the certificate", RequestParams{CACert: caPEM, ServerName: "example.com"}, ""},
+		{"server name not in the certificate", RequestParams{CACert: caPEM, ServerName: "other.test"}, "other.test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Method = "GET"
+			tt.params.URL = server.URL
+			resp, err := c.ExecuteRequest(context.Background(), tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || string(resp.Body) != "secure" {
+				t.Errorf("expected success, got %+v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func Test_ExecuteRequest_HostOverride(t *testing.T) {
+	var gotHost, gotServerName string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost, gotServerName = r.Host, r.TLS.ServerName
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, InsecureTLS: true})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:     "GET",
+		URL:        server.URL,
+		Headers:    map[string]string{"host": "www.example.com"},
+		ServerName: "www.example.com",
+	})
+	if err != nil {
+		t.Fatalf("ExecuteRequest: %v", err)
+	}
+	if gotHost != "www.example.com" || gotServerName != "www.example.com" {
+		t.Errorf("server saw Host %q and SNI %q, want www.example.com for both", gotHost, gotServerName)
+	}
+	if resp.RequestHeaders.Get("Host") != "www.example.com" {
+		t.Errorf("RequestHeaders = %v, want the Host override", resp.RequestHeaders)
+	}
+}
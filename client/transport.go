@@ -0,0 +1,52 @@
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewTransport returns the transport NewClient sends requests through:
+// proxy, TLS verification, HTTP version, DNS cache and private-network guard,
+// for other clients of the same network, such as token endpoints.
+func NewTransport(config Config) *http.Transport {
+	transport, _ := newTransport(config)
+	return transport
+}
+
+// newTransport also reports whether the private-network guard must check
+// host names per request, because a proxy makes the connections.
+func newTransport(config Config) (*http.Transport, bool) {
+	transport := &http.Transport{}
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err == nil {
+			transport.Proxy = proxyFunc(http.ProxyURL(proxyURL), parseNoProxy(config.NoProxy))
+		}
+	} else if config.ProxyFromEnv {
+		transport.Proxy = proxyFunc(http.ProxyFromEnvironment, parseNoProxy(config.NoProxy))
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	checkHostsByName := false
+	if config.BlockPrivateNetworks {
+		if transport.Proxy != nil {
+			checkHostsByName = true
+		} else {
+			dialer.Control = guardedDialControl
+			transport.DialContext = dialer.DialContext
+		}
+	}
+	if config.DNSCacheTTL > 0 {
+		transport.DialContext = newDNSCache(config.DNSCacheTTL, config.DNSCacheSize).dialContext(dialer)
+	}
+
+	if config.InsecureTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	transport.Protocols = httpProtocols(config.HTTPVersion)
+	return transport, checkHostsByName
+}
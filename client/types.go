@@ -0,0 +1,80 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// ProgressFunc receives human-readable progress updates for long-running calls
+// (retry backoff, streaming downloads). Calls happen on the request goroutine.
+type ProgressFunc func(message string)
+
+// QueryParam is one per-request query parameter.
+type QueryParam struct {
+	Name  string
+	Value string
+}
+
+type RequestParams struct {
+	Method          string
+	URL             string
+	Headers         map[string]string
+	Body            string
+	QueryParams     []QueryParam // in send order; a name may repeat
+	QueryMerge      string       // QueryOverride, QueryAppend or QueryError for names already in the URL; empty overrides
+	ReplaceQuery    bool         // drop the URL's own query string, keeping only QueryParams and defaults
+	Timeout         time.Duration
+	FollowRedirects bool
+	SaveTo          string                          // write response body to this file instead of returning it
+	MaxResponseSize int64                           // per-request override; 0 means use the client default
+	Files           map[string]string               // multipart uploads: form field name -> local file path
+	FormFields      map[string]string               // multipart text fields, sent alongside Files
+	Progress        ProgressFunc                    // optional; nil disables progress reporting
+	Verbose         bool                            // record a redacted wire transcript in Response.WireLog
+	RedactResponse  func(name, value string) string // censors response header values in WireLog; nil hides credentials and cookies
+	InsecureTLS     bool                            // skip certificate verification for this request only
+	CACert          string                          // extra trusted CA for this request: PEM text or a PEM file path
+	HTTPVersion     string                          // HTTPVersion1 or HTTPVersion2 for this request; empty uses Config.HTTPVersion
+	ServerName      string                          // TLS SNI and certificate name for this request, e.g. the Host header's value when the URL has an IP
+	MaxRedirects    int                             // redirects to follow before returning the redirect response; 0 means 10
+	CheckRedirect   func(method, url string) error  // refuses a redirect target before it is followed; nil follows any
+	Cache           string                          // CacheBypass, CacheRefresh or CacheOnly; empty uses the cache normally
+	ValidateJSON    *bool                           // check Body is JSON before sending; nil checks when the Content-Type is JSON
+	MinifyJSON      bool                            // send Body compacted (validating it)
+	MaxBandwidth    int64                           // bytes per second for this request's bodies, on top of Config.MaxBandwidth; 0 means none
+}
+
+type Response struct {
+	RequestURL      string // resolved URL the request was sent to (base URL joined, query merged)
+	StatusCode      int
+	StatusText      string
+	Headers         http.Header
+	Trailers        http.Header // sent after the body; only complete when the body was read to the end
+	Proto           string      // protocol of the response, e.g. HTTP/2.0
+	ContentType     string
+	Body            []byte
+	Duration        time.Duration
+	QueueWait       time.Duration    // time the final attempt waited for Config.MaxConcurrentRequests
+	PaceWait        time.Duration    // time the final attempt paused for its host's rate limit (Config.PaceRateLimits)
+	Attempts        int              // attempts made, the last of which produced the response; retries are Attempts-1
+	RetriedStatuses []int            // status of each retried attempt, in order; 0 when it got no response
+	Backoff         time.Duration    // total time spent waiting between attempts
+	Connections     []ConnectionInfo // how each attempt used its connection, in order
+	Truncated       bool
+	OriginalSize    int64
+	SavedPath       string
+	SavedSize       int64
+	SavedSHA256     string        // hex SHA-256 of the saved file, computed while streaming
+	Spilled         bool          // the body exceeded Config.SpillThreshold: SavedPath holds all of it, Body its start
+	WireLog         string        // curl -v style transcript of the final attempt, with RequestParams.Verbose
+	Cached          bool          // served from Config.CacheDir without a network round trip
+	CacheAge        time.Duration // how long ago the cached response was stored
+	Coalesced       bool          // shared with an identical request, see Config.CoalesceWindow
+
+	RequestMethod  string
+	RequestHeaders http.Header // headers set on the request (defaults, host rules, per-request), before transport additions
+
+	FinalURL             string        // URL of the response after following redirects
+	Redirects            []RedirectHop // followed redirects, in order
+	RedirectLimitReached bool          // MaxRedirects stopped the chain; the response is the last redirect
+}
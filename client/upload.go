@@ -0,0 +1,294 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Resumable upload protocols.
+const (
+	UploadTus          = "tus"           // tus 1.0: POST creates the upload, PATCH appends at Upload-Offset
+	UploadContentRange = "content-range" // PUT chunks with Content-Range; 308 reports the received Range
+)
+
+const (
+	DefaultUploadChunkSize = 8 << 20
+	tusVersion             = "1.0.0"
+	// maxUploadStalls is how many requests in a row may fail to advance the
+	// upload before it is given up.
+	maxUploadStalls = 5
+)
+
+// errUploadRejected marks a chunk the server refused for good (e.g. 403 or
+// 413), which asking for the offset again would not fix.
+var errUploadRejected = errors.New("the server rejected the upload")
+
+// UploadParams describes a resumable upload of one local file.
+type UploadParams struct {
+	Protocol  string            // UploadTus or UploadContentRange
+	URL       string            // tus creation endpoint, or the upload URL itself with Resume or UploadContentRange
+	File      string            // local file to upload
+	ChunkSize int64             // bytes per request; 0 means DefaultUploadChunkSize
+	Resume    bool              // URL is an upload started earlier: ask the server for its offset first
+	Headers   map[string]string // sent with every request, e.g. Authorization
+	Metadata  map[string]string // tus Upload-Metadata; filename defaults to the file's name
+	Progress  ProgressFunc      // optional; hears about every chunk
+	// CheckRequest vets every request before it is sent, e.g. against the URL
+	// policy; the tus upload URL comes from the server. nil allows all.
+	CheckRequest func(method, requestURL string) error
+}
+
+// UploadResult reports how far an upload got. UploadURL and Offset are set
+// even when Upload fails, so the upload can be resumed.
+type UploadResult struct {
+	UploadURL string
+	Size      int64
+	Offset    int64 // bytes the server has confirmed
+	Requests  int
+	Resumes   int       // times the offset was asked for again after a failed chunk
+	Response  *Response // the last response
+}
+
+// Upload sends a file in chunks with a resumable protocol. A chunk that fails
+// (after the client's own retries) does not restart the upload: the server is
+// asked how much it has and the upload continues from there.
+func (c *Client) Upload(ctx context.Context, params UploadParams) (*UploadResult, error) {
+	file, err := os.Open(params.File)
+	if err != nil {
+		return nil, fmt.Errorf("opening upload file: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("opening upload file: %w", err)
+	}
+	upload := &uploader{client: c, params: params, file: file, result: &UploadResult{UploadURL: params.URL, Size: info.Size()}}
+	if upload.params.ChunkSize <= 0 {
+		upload.params.ChunkSize = DefaultUploadChunkSize
+	}
+
+	switch {
+	case params.Protocol == UploadTus && !params.Resume:
+		err = upload.tusCreate(ctx)
+	case params.Resume:
+		err = upload.queryOffset(ctx)
+	}
+	if err != nil {
+		return upload.result, err
+	}
+	stalls := 0
+	for upload.result.Offset < upload.result.Size || upload.result.Requests == 0 {
+		before := upload.result.Offset
+		done, err := upload.sendChunk(ctx)
+		advanced := err == nil && upload.result.Offset > before
+		if advanced {
+			stalls = 0
+			upload.reportProgress()
+		}
+		if done {
+			return upload.result, nil
+		}
+		if advanced {
+			continue
+		}
+		if ctx.Err() != nil {
+			return upload.result, ctx.Err()
+		}
+		if errors.Is(err, errUploadRejected) {
+			return upload.result, fmt.Errorf("upload stopped at %d/%d bytes: %w", upload.result.Offset, upload.result.Size, err)
+		}
+		if stalls++; stalls >= maxUploadStalls {
+			if err == nil {
+				err = fmt.Errorf("the server accepted no data in %d requests in a row", stalls)
+			}
+			return upload.result, fmt.Errorf("upload stopped at %d/%d bytes: %w", upload.result.Offset, upload.result.Size, err)
+		}
+		upload.result.Resumes++
+		if queryErr := upload.queryOffset(ctx); queryErr != nil && err == nil {
+			err = queryErr
+		}
+	}
+	return upload.result, nil
+}
+
+type uploader struct {
+	client *Client
+	params UploadParams
+	file   *os.File
+	result *UploadResult
+}
+
+// send makes one request and keeps its response as the last one.
+func (u *uploader) send(ctx context.Context, method, requestURL string, headers map[string]string, body string) (*Response, error) {
+	if u.params.CheckRequest != nil {
+		if err := u.params.CheckRequest(method, requestURL); err != nil {
+			return nil, err
+		}
+	}
+	merged := make(map[string]string, len(u.params.Headers)+len(headers))
+	for name, value := range u.params.Headers {
+		merged[name] = value
+	}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	u.result.Requests++
+	response, err := u.client.ExecuteRequest(ctx, RequestParams{Method: method, URL: requestURL, Headers: merged, Body: body})
+	if response != nil {
+		u.result.Response = response
+	}
+	return response, err
+}
+
+// tusCreate creates the upload; its URL is the Location of the 201 response.
+func (u *uploader) tusCreate(ctx context.Context) error {
+	headers := map[string]string{"Tus-Resumable": tusVersion, "Upload-Length": strconv.FormatInt(u.result.Size, 10)}
+	if metadata := tusMetadata(u.params.Metadata, filepath.Base(u.params.File)); metadata != "" {
+		headers["Upload-Metadata"] = metadata
+	}
+	response, err := u.send(ctx, http.MethodPost, u.params.URL, headers, "")
+	if err != nil {
+		return fmt.Errorf("creating the upload: %w", err)
+	}
+	location := response.Headers.Get("Location")
+	if response.StatusCode != http.StatusCreated || location == "" {
+		return fmt.Errorf("creating the upload: %d %s without a Location", response.StatusCode, response.StatusText)
+	}
+	base, err := url.Parse(response.RequestURL)
+	if err != nil {
+		return fmt.Errorf("creating the upload: %w", err)
+	}
+	reference, err := url.Parse(location)
+	if err != nil {
+		return fmt.Errorf("creating the upload: invalid Location %q", location)
+	}
+	u.result.UploadURL = base.ResolveReference(reference).String()
+	return nil
+}
+
+// tusMetadata encodes Upload-Metadata: sorted "key base64(value)" pairs.
+func tusMetadata(metadata map[string]string, filename string) string {
+	pairs := make([]string, 0, len(metadata)+1)
+	if _, ok := metadata["filename"]; !ok {
+		pairs = append(pairs, "filename "+base64.StdEncoding.EncodeToString([]byte(filename)))
+	}
+	for key, value := range metadata {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// queryOffset asks the server how many bytes it has: HEAD for tus, an empty
+// PUT with "Content-Range: bytes */size" otherwise.
+func (u *uploader) queryOffset(ctx context.Context) error {
+	if u.params.Protocol == UploadTus {
+		response, err := u.send(ctx, http.MethodHead, u.result.UploadURL, map[string]string{"Tus-Resumable": tusVersion}, "")
+		if err != nil {
+			return fmt.Errorf("asking for the upload offset: %w", err)
+		}
+		offset, err := strconv.ParseInt(response.Headers.Get("Upload-Offset"), 10, 64)
+		if response.StatusCode >= 300 || err != nil {
+			return fmt.Errorf("asking for the upload offset: %d %s without an Upload-Offset", response.StatusCode, response.StatusText)
+		}
+		u.result.Offset = offset
+		return nil
+	}
+	response, err := u.send(ctx, http.MethodPut, u.result.UploadURL, map[string]string{"Content-Range": fmt.Sprintf("bytes */%d", u.result.Size)}, "")
+	if err != nil {
+		return fmt.Errorf("asking for the upload offset: %w", err)
+	}
+	switch {
+	case response.StatusCode == http.StatusPermanentRedirect:
+		u.result.Offset = receivedRange(response.Headers.Get("Range"))
+	case response.StatusCode == http.StatusOK || response.StatusCode == http.StatusCreated:
+		u.result.Offset = u.result.Size // already complete
+	default:
+		return fmt.Errorf("asking for the upload offset: %d %s", response.StatusCode, response.StatusText)
+	}
+	return nil
+}
+
+// receivedRange reads the bytes a server has from "Range: bytes=0-N"; no
+// Range means none.
+func receivedRange(header string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(header, "bytes="), "-")
+	end, err := strconv.ParseInt(last, 10, 64)
+	if !ok || err != nil {
+		return 0
+	}
+	return end + 1
+}
+
+// sendChunk sends the chunk at the current offset and advances the offset to
+// what the server confirmed. done reports that the upload is complete.
+func (u *uploader) sendChunk(ctx context.Context) (done bool, err error) {
+	offset, size := u.result.Offset, u.result.Size
+	if offset >= size && u.params.Protocol == UploadTus {
+		return true, nil
+	}
+	chunk := make([]byte, min(u.params.ChunkSize, size-offset))
+	if _, err := u.file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+		return false, fmt.Errorf("reading upload file: %w", err)
+	}
+
+	if u.params.Protocol == UploadTus {
+		headers := map[string]string{"Tus-Resumable": tusVersion, "Upload-Offset": strconv.FormatInt(offset, 10), "Content-Type": "application/offset+octet-stream"}
+		response, err := u.send(ctx, http.MethodPatch, u.result.UploadURL, headers, string(chunk))
+		if err != nil {
+			return false, err
+		}
+		if response.StatusCode >= 300 {
+			return false, chunkFailure(response)
+		}
+		if confirmed, parseErr := strconv.ParseInt(response.Headers.Get("Upload-Offset"), 10, 64); parseErr == nil {
+			u.result.Offset = confirmed
+		}
+		return u.result.Offset >= size, nil
+	}
+
+	contentRange := fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, size)
+	if size == 0 {
+		contentRange = "bytes */0"
+	}
+	response, err := u.send(ctx, http.MethodPut, u.result.UploadURL, map[string]string{"Content-Range": contentRange}, string(chunk))
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case response.StatusCode == http.StatusPermanentRedirect:
+		u.result.Offset = receivedRange(response.Headers.Get("Range"))
+		return false, nil
+	case response.StatusCode < 300:
+		u.result.Offset = offset + int64(len(chunk))
+		return u.result.Offset >= size, nil
+	}
+	return false, chunkFailure(response)
+}
+
+// chunkFailure explains a failed chunk. Conflicts (an offset mismatch),
+// timeouts, throttling and server errors are worth resuming; other client
+// errors are not.
+func chunkFailure(response *Response) error {
+	switch status := response.StatusCode; {
+	case status >= 500, status == http.StatusConflict, status == http.StatusRequestTimeout, status == http.StatusTooManyRequests, status == http.StatusLocked:
+		return fmt.Errorf("%d %s", status, response.StatusText)
+	}
+	return fmt.Errorf("%w: %d %s", errUploadRejected, response.StatusCode, response.StatusText)
+}
+
+func (u *uploader) reportProgress() {
+	if u.params.Progress != nil {
+		u.params.Progress(fmt.Sprintf("uploaded %d/%d bytes (%d%%)", u.result.Offset, u.result.Size, u.result.Offset*100/max(u.result.Size, 1)))
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeUploadFile(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+	data := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+// tusServer accepts one upload and fails the PATCH numbered failPatch with a
+// 500 after storing only half of its chunk.
+type tusServer struct {
+	mu        sync.Mutex
+	data      []byte
+	length    int64
+	patches   int
+	failPatch int
+	metadata  string
+}
+
+func (s *tusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Tus-Resumable", "1.0.0")
+	switch r.Method {
+	case http.MethodPost:
+		s.length, _ = strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		s.metadata = r.Header.Get("Upload-Metadata")
+		w.Header().Set("Location", "/files/abc")
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead:
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+	case http.MethodPatch:
+		s.patches++
+		if r.Header.Get("Upload-Offset") != strconv.Itoa(len(s.data)) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		chunk, _ := io.ReadAll(r.Body)
+		if s.patches == s.failPatch {
+			s.data = append(s.data, chunk[:len(chunk)/2]...)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.data = append(s.data, chunk...)
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func Test_Upload_TusResumesAfterFailedChunk(t *testing.T) {
+	tus := &tusServer{failPatch: 2}
+	server := httptest.NewServer(tus)
+	defer server.Close()
+	path, data := writeUploadFile(t, 2500)
+
+	var progress []string
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	result, err := c.Upload(context.Background(), UploadParams{
+		Protocol: UploadTus, URL: server.URL + "/files", File: path, ChunkSize: 1000,
+		Progress: func(message string) { progress = append(progress, message) },
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if !bytes.Equal(tus.data, data) || tus.length != 2500 {
+		t.Errorf("server has %d bytes of %d, want the file", len(tus.data), tus.length)
+	}
+	if result.UploadURL != server.URL+"/files/abc" || result.Offset != 2500 || result.Resumes != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if !strings.HasPrefix(tus.metadata, "filename ") {
+		t.Errorf("Upload-Metadata = %q", tus.metadata)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != "uploaded 2500/2500 bytes (100%)" {
+		t.Errorf("progress = %q", progress)
+	}
+}
+
+func Test_Upload_TusResumeContinuesAtServerOffset(t *testing.T) {
+	path, data := writeUploadFile(t, 1500)
+	tus := &tusServer{data: append([]byte(nil), data[:700]...), length: 1500}
+	server := httptest.NewServer(tus)
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	result, err := c.Upload(context.Background(), UploadParams{Protocol: UploadTus, URL: server.URL + "/files/abc", File: path, Resume: true})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if !bytes.Equal(tus.data, data) || tus.patches != 1 || result.Requests != 2 {
+		t.Errorf("server has %d bytes after %d patches; result %+v", len(tus.data), tus.patches, result)
+	}
+}
+
+func Test_Upload_ContentRange(t *testing.T) {
+	var mu sync.Mutex
+	var received []byte
+	puts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		contentRange := r.Header.Get("Content-Range")
+		if strings.HasPrefix(contentRange, "bytes */") {
+			if len(received) > 0 {
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(received)-1))
+			}
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		puts++
+		if puts == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var start, end, total int
+		fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total)
+		chunk, _ := io.ReadAll(r.Body)
+		if start != len(received) || end-start+1 != len(chunk) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, chunk...)
+		if len(received) < total {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(received)-1))
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"file-1"}`)
+	}))
+	defer server.Close()
+	path, data := writeUploadFile(t, 2100)
+
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	result, err := c.Upload(context.Background(), UploadParams{Protocol: UploadContentRange, URL: server.URL + "/upload/session", File: path, ChunkSize: 1000})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if !bytes.Equal(received, data) || result.Resumes != 1 || result.Response.StatusCode != http.StatusCreated {
+		t.Errorf("server has %d bytes; result %+v", len(received), result)
+	}
+}
+
+func Test_Upload_StopsOnRejectedChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+	path, _ := writeUploadFile(t, 100)
+
+	c := NewClient(Config{Timeout: 5 * time.Second})
+	result, err := c.Upload(context.Background(), UploadParams{Protocol: UploadContentRange, URL: server.URL, File: path})
+	if err == nil || !strings.Contains(err.Error(), "upload stopped at 0/100 bytes: the server rejected the upload: 413") {
+		t.Fatalf("err = %v", err)
+	}
+	if result.Requests != 1 {
+		t.Errorf("requests = %d, want no resume after a rejection", result.Requests)
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// webSocketGUID is appended to the handshake key to compute the accept value (RFC 6455 §4.2.2).
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes (RFC 6455 §5.2).
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// maxControlPayload is the largest payload a control frame may carry.
+const maxControlPayload = 125
+
+// webSocketClosed reports the close frame the server ended the stream with.
+type webSocketClosed struct {
+	code   int // 0 when the frame carried none
+	reason string
+}
+
+func (e *webSocketClosed) Error() string {
+	switch {
+	case e.code == 0:
+		return "the server closed the WebSocket"
+	case e.reason == "":
+		return fmt.Sprintf("the server closed the WebSocket (%d)", e.code)
+	}
+	return fmt.Sprintf("the server closed the WebSocket (%d %s)", e.code, e.reason)
+}
+
+func newWebSocketKey() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating WebSocket key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readWebSocket sends the messages, then reads frames: data messages go to
+// the collector (reassembled from fragments, kept up to its size limit),
+// pings are answered, and a close frame is echoed and ends the stream. The
+// connection is closed normally once the collector is done.
+func readWebSocket(conn io.ReadWriter, send []string, collector *eventCollector) error {
+	for _, message := range send {
+		if err := writeWebSocketFrame(conn, opText, []byte(message)); err != nil {
+			return fmt.Errorf("sending a message: %w", err)
+		}
+	}
+	reader := bufio.NewReader(conn)
+	var message []byte
+	var opcode byte
+	size := 0
+	for {
+		fin, frameOpcode, length, mask, err := readFrameHeader(reader)
+		if err != nil {
+			return err
+		}
+		if frameOpcode >= opClose {
+			if length > maxControlPayload {
+				return fmt.Errorf("control frame of %d bytes exceeds %d", length, maxControlPayload)
+			}
+			payload := make([]byte, length)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return err
+			}
+			unmask(payload, mask)
+			switch frameOpcode {
+			case opPing:
+				if err := writeWebSocketFrame(conn, opPong, payload); err != nil {
+					return err
+				}
+			case opClose:
+				writeWebSocketFrame(conn, opClose, payload)
+				closed := &webSocketClosed{}
+				if len(payload) >= 2 {
+					closed.code, closed.reason = int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+				}
+				return closed
+			}
+			continue
+		}
+		if frameOpcode != opContinuation {
+			opcode, message, size = frameOpcode, message[:0], 0
+		}
+		keep := min(length, max(collector.maxSize-int64(len(message)), 0))
+		start := len(message)
+		message = append(message, make([]byte, keep)...)
+		if _, err := io.ReadFull(reader, message[start:]); err != nil {
+			return err
+		}
+		unmask(message[start:], mask)
+		if _, err := io.CopyN(io.Discard, reader, length-keep); err != nil {
+			return err
+		}
+		size += int(length)
+		if !fin {
+			continue
+		}
+		kind := "text"
+		if opcode == opBinary {
+			kind = "binary"
+		}
+		if collector.add(kind, "", message, size, opcode == opBinary) {
+			closeCode := []byte{0x03, 0xE8} // 1000: normal closure
+			writeWebSocketFrame(conn, opClose, closeCode)
+			return nil
+		}
+	}
+}
+
+// readFrameHeader reads a frame's FIN bit, opcode, payload length and masking key (nil when unmasked).
+func readFrameHeader(reader *bufio.Reader) (bool, byte, int64, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return false, 0, 0, nil, err
+	}
+	fin, opcode := header[0]&0x80 != 0, header[0]&0x0F
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(reader, extended[:]); err != nil {
+			return false, 0, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(reader, extended[:]); err != nil {
+			return false, 0, 0, nil, err
+		}
+		if length = int64(binary.BigEndian.Uint64(extended[:])); length < 0 {
+			return false, 0, 0, nil, fmt.Errorf("invalid frame length")
+		}
+	}
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(reader, mask); err != nil {
+			return false, 0, 0, nil, err
+		}
+	}
+	return fin, opcode, length, mask, nil
+}
+
+// writeWebSocketFrame writes one final frame, masked as clients must (RFC 6455 §5.3).
+func writeWebSocketFrame(writer io.Writer, opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length <= maxControlPayload:
+		frame = append(frame, 0x80|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	frame = append(frame, mask...)
+	start := len(frame)
+	frame = append(frame, payload...)
+	unmask(frame[start:], mask)
+	_, err := writer.Write(frame)
+	return err
+}
+
+// unmask XORs the start of a frame's payload with its masking key; the same
+// operation masks it.
+func unmask(data, mask []byte) {
+	if mask == nil {
+		return
+	}
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
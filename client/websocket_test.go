@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_webSocketAccept_RFCExample(t *testing.T) {
+	// RFC 6455 §1.3
+	if got := webSocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("webSocketAccept = %q", got)
+	}
+}
+
+func Test_writeWebSocketFrame_LengthEncodings(t *testing.T) {
+	for _, length := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		var frame bytes.Buffer
+		payload := bytes.Repeat([]byte("x"), length)
+		if err := writeWebSocketFrame(&frame, opBinary, payload); err != nil {
+			t.Fatal(err)
+		}
+		reader := bufio.NewReader(&frame)
+		fin, opcode, got, mask, err := readFrameHeader(reader)
+		if err != nil || !fin || opcode != opBinary || got != int64(length) || mask == nil {
+			t.Errorf("length %d: read fin=%v opcode=%x length=%d mask=%v err=%v", length, fin, opcode, got, mask, err)
+			continue
+		}
+		masked, _ := io.ReadAll(reader)
+		unmask(masked, mask)
+		if !bytes.Equal(masked, payload) {
+			t.Errorf("length %d: payload did not round-trip", length)
+		}
+	}
+}
+
+func Test_readWebSocket_Frames(t *testing.T) {
+	closeFrame := func(payload []byte) []byte { return append([]byte{0x80 | opClose, byte(len(payload))}, payload...) }
+	tests := []struct {
+		name      string
+		frames    []byte
+		wantErr   string
+		wantReply byte // opcode of the client's last frame, 0 for none
+	}{
+		{"close with code and reason", closeFrame(append(binary.BigEndian.AppendUint16(nil, 1008), "policy"...)), "the server closed the WebSocket (1008 policy)", opClose},
+		{"close without a code", closeFrame(nil), "the server closed the WebSocket", opClose},
+		{"oversized control frame", append([]byte{0x80 | opPing, 126}, binary.BigEndian.AppendUint16(nil, 200)...), "control frame of 200 bytes exceeds 125", 0},
+		{"connection ends mid-frame", []byte{0x80 | opText, 10, 'a'}, "unexpected EOF", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written bytes.Buffer
+			conn := struct {
+				io.Reader
+				io.Writer
+			}{bytes.NewReader(tt.frames), &written}
+			err := readWebSocket(conn, nil, &eventCollector{maxSize: 1024})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+			if tt.wantReply == 0 {
+				if written.Len() != 0 {
+					t.Errorf("expected no reply, got %d bytes", written.Len())
+				}
+				return
+			}
+			if _, opcode, _, _, err := readFrameHeader(bufio.NewReader(&written)); err != nil || opcode != tt.wantReply {
+				t.Errorf("reply opcode = %x (%v), want %x", opcode, err, tt.wantReply)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// wireDump collects a curl -v style transcript of one attempt: every
+// request/response exchange, redirect hops included, with secrets redacted.
+// redactResponse, when set, censors the response headers instead of
+// redactWireHeader.
+type wireDump struct {
+	redactResponse func(name, value string) string
+
+	mu    sync.Mutex
+	lines []string
+}
+
+// transport wraps next so each round trip is added to the dump.
+func (d *wireDump) transport(next http.RoundTripper) http.RoundTripper {
+	return wireDumpTransport{dump: d, next: next}
+}
+
+func (d *wireDump) String() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return strings.Join(d.lines, "\n")
+}
+
+type wireDumpTransport struct {
+	dump *wireDump
+	next http.RoundTripper
+}
+
+func (t wireDumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// WroteHeaderField reports the headers as written, including the ones the
+	// transport adds itself (Host, User-Agent, Accept-Encoding, Content-Length).
+	var mu sync.Mutex
+	var written []string
+	var connection string
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			connection = fmt.Sprintf("* connected to %s (reused: %t)", info.Conn.RemoteAddr(), info.Reused)
+		},
+		WroteHeaderField: func(key string, values []string) {
+			if strings.HasPrefix(key, ":") { // HTTP/2 pseudo-headers, covered by the request line
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, value := range values {
+				written = append(written, fmt.Sprintf("> %s: %s", key, redactWireHeader(key, value)))
+			}
+		},
+	}
+	resp, err := t.next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(written) == 0 { // nothing reached the wire, e.g. a replayed cassette
+		written = formatWireHeaders(">", req.Header, redactWireHeader)
+	}
+	proto := "HTTP/1.1"
+	if resp != nil {
+		proto = resp.Proto
+	}
+
+	var exchange []string
+	if connection != "" {
+		exchange = append(exchange, connection)
+	}
+	target := *req.URL
+	target.RawQuery = redactQuery(target.RawQuery)
+	exchange = append(exchange, fmt.Sprintf("> %s %s %s", req.Method, target.RequestURI(), proto))
+	exchange = append(exchange, written...)
+	exchange = append(exchange, ">")
+	if err != nil {
+		exchange = append(exchange, fmt.Sprintf("* error: %s", err))
+	} else {
+		exchange = append(exchange, fmt.Sprintf("< %s %s", resp.Proto, resp.Status))
+		redact := t.dump.redactResponse
+		if redact == nil {
+			redact = redactWireHeader
+		}
+		exchange = append(exchange, formatWireHeaders("<", resp.Header, redact)...)
+		exchange = append(exchange, "<")
+	}
+
+	t.dump.mu.Lock()
+	t.dump.lines = append(t.dump.lines, exchange...)
+	t.dump.mu.Unlock()
+	return resp, err
+}
+
+func formatWireHeaders(prefix string, headers http.Header, redact func(name, value string) string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		for _, value := range headers[name] {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", prefix, name, redact(name, value)))
+		}
+	}
+	return lines
+}
+
+// redactWireHeader hides credentials and cookies, which are session secrets
+// too, and sensitive query values in the URLs that Referer and Location repeat.
+func redactWireHeader(name, value string) string {
+	switch strings.ToLower(name) {
+	case "cookie", "set-cookie":
+		return "***"
+	case "referer", "location":
+		return RedactURL(value)
+	}
+	if IsSensitiveHeader(name) {
+		return "***"
+	}
+	return value
+}
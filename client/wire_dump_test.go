@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ExecuteRequest_VerboseWireLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "cookie-secret"})
+		w.Header().Set("X-Trace", "abc")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Timeout: 5 * time.Second, DefaultHeaders: map[string]string{"Authorization": "Bearer token-secret"}})
+	resp, err := c.ExecuteRequest(context.Background(), RequestParams{
+		Method:          "POST",
+		URL:             server.URL + "/old?api_key=key-secret&page=2",
+		Body:            "x",
+		FollowRedirects: true,
+		Verbose:         true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"* connected to 127.0.0.1:",
+		"> POST /old?api_key=***&page=2 HTTP/1.1",
+		"> Host: 127.0.0.1:",
+		"> User-Agent: Go-http-client/1.1",
+		"> Authorization: ***",
+		"< HTTP/1.1 301 Moved Permanently",
+		"> GET /new HTTP/1.1",
+		"< HTTP/1.1 200 OK",
+		"< X-Trace: abc",
+		"< Set-Cookie: ***",
+	} {
+		if !strings.Contains(resp.WireLog, want) {
+			t.Errorf("expected %q in wire log, got:\n%s", want, resp.WireLog)
+		}
+	}
+	if strings.Contains(resp.WireLog, "secret") {
+		t.Errorf("expected secrets redacted, got:\n%s", resp.WireLog)
+	}
+}
+
+func Test_ExecuteRequest_NoWireLogByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, err := NewClient(Config{Timeout: 5 * time.Second}).ExecuteRequest(context.Background(), RequestParams{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.WireLog != "" {
+		t.Errorf("expected no wire log, got:\n%s", resp.WireLog)
+	}
+}
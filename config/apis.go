@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// apisKey holds named upstream APIs in a configuration file. Each API is a map
+// of flag names (base-url, default-header, ...) plus an optional description.
+const apisKey = "apis"
+
+// apiNamePattern keeps API names usable as MCP tool name prefixes.
+var apiNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// API is one named upstream API from the configuration file.
+type API struct {
+	Name        string
+	Description string         // what the API is for, shown in its tool description
+	Options     map[string]any // flag values that override the resolved options for this API
+}
+
+// takeAPIs removes the apis section from values and returns it sorted by name.
+func takeAPIs(values map[string]any, source string) ([]API, error) {
+	raw, ok := values[apisKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(values, apisKey)
+
+	section, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must be a map of API name to options", source, apisKey)
+	}
+	apis := make([]API, 0, len(section))
+	for name, rawAPI := range section {
+		if !apiNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s: API name %q must be lowercase letters, digits and underscores", source, name)
+		}
+		if name == "http" {
+			return nil, fmt.Errorf("%s: API name %q is reserved (its tool would clash with http_request)", source, name)
+		}
+		options, ok := rawAPI.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: API %q must be a map of options", source, name)
+		}
+		api := API{Name: name, Options: make(map[string]any, len(options))}
+		for key, value := range options {
+			if key != "description" {
+				api.Options[key] = value
+				continue
+			}
+			description, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: API %q: description must be a string", source, name)
+			}
+			api.Description = description
+		}
+		apis = append(apis, api)
+	}
+	sort.Slice(apis, func(i, j int) bool { return apis[i].Name < apis[j].Name })
+	return apis, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Resolve_APIs(t *testing.T) {
+	f, _, _ := newProfileTestFlags(t, `
+base-url: http://base
+apis:
+  jira:
+    base-url: https://example.atlassian.net/rest/api/3
+  github:
+    description: GitHub REST API for the acme org
+    base-url: https://api.github.com
+    default-header:
+      Accept: application/vnd.github+json
+`)
+	sections, err := Resolve(f.fs, nil)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if f.baseURL != "http://base" {
+		t.Errorf("API options must not leak into the top level, base-url = %s", f.baseURL)
+	}
+	want := []API{
+		{
+			Name:        "github",
+			Description: "GitHub REST API for the acme org",
+			Options: map[string]any{
+				"base-url":       "https://api.github.com",
+				"default-header": map[string]any{"Accept": "application/vnd.github+json"},
+			},
+		},
+		{Name: "jira", Options: map[string]any{"base-url": "https://example.atlassian.net/rest/api/3"}},
+	}
+	if !reflect.DeepEqual(sections.APIs, want) {
+		t.Errorf("APIs = %#v, want %#v", sections.APIs, want)
+	}
+}
+
+func Test_TakeAPIs_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   string
+	}{
+		{"not a map", map[string]any{"apis": []any{"github"}}, "must be a map of API name"},
+		{"bad name", map[string]any{"apis": map[string]any{"Git-Hub": map[string]any{}}}, "lowercase letters"},
+		{"reserved name", map[string]any{"apis": map[string]any{"http": map[string]any{}}}, "reserved"},
+		{"api not a map", map[string]any{"apis": map[string]any{"github": "x"}}, `API "github" must be a map`},
+		{"bad description", map[string]any{"apis": map[string]any{"github": map[string]any{"description": 1}}}, "description must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := takeAPIs(tt.values, "config.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// mapEntryFormats turns map values of repeatable flags into the flag's string
+// syntax, so a file can say `default-header: {Accept: application/json}`.
+var mapEntryFormats = map[string]string{
+	"default-header": "%s: %s",
+	"default-query":  "%s=%s",
+}
+
+// SetFlags returns the names of flags given explicitly on the command line.
+func SetFlags(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// Apply sets every flag named in values that is not in skip, and adds it to
+// skip so a lower-precedence source applied afterwards leaves it alone.
+// Unknown keys are an error, naming source, to catch typos.
+func Apply(fs *flag.FlagSet, values map[string]any, skip map[string]bool, source string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown option %q", source, name)
+		}
+		if skip[name] {
+			continue
+		}
+		flagValues, err := flagStrings(name, values[name])
+		if err != nil {
+			return fmt.Errorf("%s: option %q: %w", source, name, err)
+		}
+		for _, value := range flagValues {
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("%s: option %q: %w", source, name, err)
+			}
+		}
+		skip[name] = true
+	}
+	return nil
+}
+
+// flagStrings converts a decoded file value into one or more flag.Set arguments.
+// Lists set a repeatable flag once per item.
+func flagStrings(name string, value any) ([]string, error) {
+	switch typed := value.(type) {
+	case []any:
+		result := make([]string, 0, len(typed))
+		for _, item := range typed {
+			scalar, err := scalarString(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, scalar)
+		}
+		return result, nil
+	case map[string]any:
+		format, ok := mapEntryFormats[name]
+		if !ok {
+			return nil, fmt.Errorf("a map is not valid here")
+		}
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]string, 0, len(keys))
+		for _, key := range keys {
+			scalar, err := scalarString(typed[key])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, fmt.Sprintf(format, key, scalar))
+		}
+		return result, nil
+	}
+	scalar, err := scalarString(value)
+	if err != nil {
+		return nil, err
+	}
+	return []string{scalar}, nil
+}
+
+func scalarString(value any) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case int:
+		return strconv.Itoa(typed), nil
+	case int64:
+		return strconv.FormatInt(typed, 10), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value %v (%T)", value, value)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+type listFlag []string
+
+func (f *listFlag) String() string { return strings.Join(*f, ", ") }
+func (f *listFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+type testFlags struct {
+	fs       *flag.FlagSet
+	baseURL  string
+	timeout  time.Duration
+	maxSize  int64
+	insecure bool
+	headers  listFlag
+}
+
+func newTestFlags(args ...string) *testFlags {
+	f := &testFlags{fs: flag.NewFlagSet("test", flag.ContinueOnError)}
+	f.fs.StringVar(&f.baseURL, "base-url", "", "")
+	f.fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "")
+	f.fs.Int64Var(&f.maxSize, "max-response-size", 51200, "")
+	f.fs.BoolVar(&f.insecure, "insecure", false, "")
+	f.fs.Var(&f.headers, "default-header", "")
+	f.fs.Parse(args)
+	return f
+}
+
+func Test_Apply_FlagsOverrideFile(t *testing.T) {
+	f := newTestFlags("--base-url", "http://cli")
+	values := map[string]any{
+		"base-url":          "http://file",
+		"timeout":           "5s",
+		"max-response-size": float64(10485760),
+		"insecure":          true,
+		"default-header":    map[string]any{"X-Api-Key": "secret", "Accept": "application/json"},
+	}
+
+	if err := Apply(f.fs, values, SetFlags(f.fs), "config.json"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if f.baseURL != "http://cli" {
+		t.Errorf("expected command-line base-url to win, got %s", f.baseURL)
+	}
+	if f.timeout != 5*time.Second || f.maxSize != 10485760 || !f.insecure {
+		t.Errorf("file values not applied: timeout=%s maxSize=%d insecure=%v", f.timeout, f.maxSize, f.insecure)
+	}
+	if strings.Join(f.headers, "|") != "Accept: application/json|X-Api-Key: secret" {
+		t.Errorf("unexpected headers: %v", f.headers)
+	}
+}
+
+func Test_Apply_LaterSourceDoesNotOverride(t *testing.T) {
+	f := newTestFlags()
+	applied := SetFlags(f.fs)
+	if err := Apply(f.fs, map[string]any{"base-url": "http://first"}, applied, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(f.fs, map[string]any{"base-url": "http://second"}, applied, "second"); err != nil {
+		t.Fatal(err)
+	}
+	if f.baseURL != "http://first" {
+		t.Errorf("expected first source to win, got %s", f.baseURL)
+	}
+}
+
+func Test_Apply_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   string
+	}{
+		{"unknown option", map[string]any{"base_url": "x"}, `unknown option "base_url"`},
+		{"invalid value", map[string]any{"timeout": "soon"}, `option "timeout"`},
+		{"map for scalar flag", map[string]any{"base-url": map[string]any{"a": "b"}}, "map is not valid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFlags()
+			err := Apply(f.fs, tt.values, SetFlags(f.fs), "config.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
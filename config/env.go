@@ -0,0 +1,74 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts every environment variable that configures a flag:
+// --base-url is REST_API_MCP_BASE_URL.
+const EnvPrefix = "REST_API_MCP_"
+
+// EnvName returns the environment variable for a flag name.
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+type numberedValue struct {
+	index int
+	value string
+}
+
+// EnvValues collects flag values from environ ("KEY=value" entries, as from
+// os.Environ) into the map shape Apply takes. Repeatable flags can be given
+// several times with a numeric suffix: REST_API_MCP_DEFAULT_HEADER_1, _2, ...
+// Variables with the prefix that match no flag are an error.
+func EnvValues(fs *flag.FlagSet, environ []string) (map[string]any, error) {
+	flagNames := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) { flagNames[EnvName(f.Name)] = f.Name })
+
+	collected := make(map[string][]numberedValue)
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, EnvPrefix) {
+			continue
+		}
+		if name, known := flagNames[key]; known {
+			collected[name] = append(collected[name], numberedValue{index: 0, value: value})
+			continue
+		}
+		base, suffix, hasSuffix := cutLast(key, "_")
+		index, err := strconv.Atoi(suffix)
+		name, known := flagNames[base]
+		if !hasSuffix || err != nil || !known {
+			return nil, fmt.Errorf("environment: %s does not match any option", key)
+		}
+		collected[name] = append(collected[name], numberedValue{index: index, value: value})
+	}
+
+	values := make(map[string]any, len(collected))
+	for name, entries := range collected {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].index < entries[j].index })
+		if len(entries) == 1 {
+			values[name] = entries[0].value
+			continue
+		}
+		list := make([]any, len(entries))
+		for i, entry := range entries {
+			list[i] = entry.value
+		}
+		values[name] = list
+	}
+	return values, nil
+}
+
+func cutLast(s, separator string) (string, string, bool) {
+	idx := strings.LastIndex(s, separator)
+	if idx < 0 {
+		return s, "", false
+	}
+	return s[:idx], s[idx+len(separator):], true
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_EnvName(t *testing.T) {
+	if got := EnvName("max-response-size"); got != "REST_API_MCP_MAX_RESPONSE_SIZE" {
+		t.Errorf("EnvName = %s", got)
+	}
+}
+
+func Test_EnvValues(t *testing.T) {
+	f := newTestFlags()
+	values, err := EnvValues(f.fs, []string{
+		"PATH=/usr/bin",
+		"REST_API_MCP_BASE_URL=http://env",
+		"REST_API_MCP_DEFAULT_HEADER_2=Accept: application/json",
+		"REST_API_MCP_DEFAULT_HEADER_1=Authorization: Bearer secret",
+		"REST_API_MCP_INSECURE=true",
+	})
+	if err != nil {
+		t.Fatalf("EnvValues: %v", err)
+	}
+	want := map[string]any{
+		"base-url":       "http://env",
+		"default-header": []any{"Authorization: Bearer secret", "Accept: application/json"},
+		"insecure":       "true",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("EnvValues = %#v, want %#v", values, want)
+	}
+}
+
+func Test_EnvValues_UnknownVariable(t *testing.T) {
+	f := newTestFlags()
+	for _, entry := range []string{"REST_API_MCP_BASEURL=x", "REST_API_MCP_TIMEOUT_X=1s"} {
+		_, err := EnvValues(f.fs, []string{entry})
+		if err == nil || !strings.Contains(err.Error(), strings.SplitN(entry, "=", 2)[0]) {
+			t.Errorf("expected error naming the variable for %s, got: %v", entry, err)
+		}
+	}
+}
+
+func Test_Apply_PrecedenceEnvFileFlags(t *testing.T) {
+	f := newTestFlags("--base-url", "http://cli")
+	applied := SetFlags(f.fs)
+	if err := Apply(f.fs, map[string]any{"base-url": "http://file", "timeout": "5s"}, applied, "config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	envValues, err := EnvValues(f.fs, []string{
+		"REST_API_MCP_BASE_URL=http://env",
+		"REST_API_MCP_TIMEOUT=1s",
+		"REST_API_MCP_MAX_RESPONSE_SIZE=100",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(f.fs, envValues, applied, "environment"); err != nil {
+		t.Fatal(err)
+	}
+	if f.baseURL != "http://cli" || f.timeout.String() != "5s" || f.maxSize != 100 {
+		t.Errorf("unexpected precedence: base-url=%s timeout=%s max-response-size=%d", f.baseURL, f.timeout, f.maxSize)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_LoadFile_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"json", "config.json", `{"base-url": "http://localhost:8080", "retry": 2, "default-header": ["Accept: application/json"]}`},
+		{"yaml", "config.yaml", "base-url: http://localhost:8080\nretry: 2\ndefault-header:\n  - \"Accept: application/json\"\n"},
+		{"toml", "config.toml", "base-url = \"http://localhost:8080\"\nretry = 2\ndefault-header = [\"Accept: application/json\"]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			values, err := LoadFile(path)
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if values["base-url"] != "http://localhost:8080" {
+				t.Errorf("base-url = %v", values["base-url"])
+			}
+			retry, err := scalarString(values["retry"])
+			if err != nil || retry != "2" {
+				t.Errorf("retry = %v (%T)", values["retry"], values["retry"])
+			}
+			headers, err := flagStrings("default-header", values["default-header"])
+			if err != nil || !reflect.DeepEqual(headers, []string{"Accept: application/json"}) {
+				t.Errorf("default-header = %v, %v", headers, err)
+			}
+		})
+	}
+}
+
+func Test_LoadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	unsupported := filepath.Join(dir, "config.ini")
+	invalid := filepath.Join(dir, "config.json")
+	os.WriteFile(unsupported, []byte("a=b"), 0o600)
+	os.WriteFile(invalid, []byte("{not json"), 0o600)
+
+	for _, path := range []string{unsupported, invalid, filepath.Join(dir, "missing.yaml")} {
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for %s", filepath.Base(path))
+		}
+	}
+}
+
+func loadTestFile(t *testing.T, name, content string) (map[string]any, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadFile(path)
+}
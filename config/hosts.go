@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// hostsKey holds per-host rules in a configuration file: a list of maps with a
+// "match" key and the options scoped to that host or URL prefix.
+const hostsKey = "hosts"
+
+// takeHosts removes the hosts section from values and returns it as client
+// host rules, in file order (the first matching rule applies).
+func takeHosts(values map[string]any, source string) ([]client.HostRule, error) {
+	raw, ok := values[hostsKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(values, hostsKey)
+
+	var entries []map[string]any
+	switch typed := raw.(type) {
+	case []map[string]any: // TOML array of tables
+		entries = typed
+	case []any:
+		for _, item := range typed {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: each %q entry must be a map", source, hostsKey)
+			}
+			entries = append(entries, entry)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %q must be a list of host rules", source, hostsKey)
+	}
+
+	rules := make([]client.HostRule, 0, len(entries))
+	for i, entry := range entries {
+		rule, err := parseHostRule(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s entry %d: %w", source, hostsKey, i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseHostRule(entry map[string]any) (client.HostRule, error) {
+	var rule client.HostRule
+	for key, value := range entry {
+		if key == "default-header" {
+			headers, err := flagStrings(key, value)
+			if err != nil {
+				return rule, fmt.Errorf("%s: %w", key, err)
+			}
+			rule.DefaultHeaders = client.ParseHeaders(headers)
+			continue
+		}
+
+		scalar, err := scalarString(value)
+		if err != nil {
+			return rule, fmt.Errorf("%s: %w", key, err)
+		}
+		switch key {
+		case "match":
+			rule.Match = scalar
+		case "timeout":
+			rule.Timeout, err = time.ParseDuration(scalar)
+		case "retry-delay":
+			rule.RetryDelay, err = time.ParseDuration(scalar)
+		case "retry":
+			var retryCount int
+			retryCount, err = strconv.Atoi(scalar)
+			rule.RetryCount = &retryCount
+		case "insecure":
+			rule.InsecureTLS, err = strconv.ParseBool(scalar)
+		default:
+			return rule, fmt.Errorf("unknown option %q (host rules support match, default-header, timeout, retry, retry-delay, insecure)", key)
+		}
+		if err != nil {
+			return rule, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if rule.Match == "" {
+		return rule, fmt.Errorf("match is required")
+	}
+	return rule, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Resolve_Hosts(t *testing.T) {
+	f, _, _ := newProfileTestFlags(t, `
+hosts:
+  - match: api.internal.example.com
+    default-header:
+      Authorization: Bearer internal
+    timeout: 60s
+    retry: 0
+  - match: "*.staging.example.com"
+    insecure: true
+    retry-delay: 2s
+`)
+	sections, err := Resolve(f.fs, nil)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(sections.Hosts) != 2 {
+		t.Fatalf("expected 2 host rules, got %d", len(sections.Hosts))
+	}
+	first, second := sections.Hosts[0], sections.Hosts[1]
+	if first.Match != "api.internal.example.com" || first.DefaultHeaders["Authorization"] != "Bearer internal" || first.Timeout != 60*time.Second {
+		t.Errorf("unexpected first rule: %+v", first)
+	}
+	if first.RetryCount == nil || *first.RetryCount != 0 {
+		t.Errorf("expected explicit retry 0, got %v", first.RetryCount)
+	}
+	if second.Match != "*.staging.example.com" || !second.InsecureTLS || second.RetryDelay != 2*time.Second || second.RetryCount != nil {
+		t.Errorf("unexpected second rule: %+v", second)
+	}
+}
+
+func Test_TakeHosts_TOML(t *testing.T) {
+	values, err := loadTestFile(t, "config.toml", "[[hosts]]\nmatch = \"api.example.com\"\ntimeout = \"5s\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := takeHosts(values, "config.toml")
+	if err != nil || len(rules) != 1 || rules[0].Timeout != 5*time.Second {
+		t.Errorf("unexpected rules %+v, %v", rules, err)
+	}
+}
+
+func Test_TakeHosts_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		hosts any
+		want  string
+	}{
+		{"not a list", map[string]any{"match": "x"}, "must be a list"},
+		{"missing match", []any{map[string]any{"timeout": "5s"}}, "match is required"},
+		{"unknown option", []any{map[string]any{"match": "x", "proxy": "http://p"}}, `unknown option "proxy"`},
+		{"bad duration", []any{map[string]any{"match": "x", "timeout": "soon"}}, "entry 1: timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := takeHosts(map[string]any{"hosts": tt.hosts}, "config.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Sections are the parts of a configuration file that are not flag values.
+type Sections struct {
+	Profiles []string // names of the defined profiles, sorted
+	APIs     []API    // named upstream APIs, sorted by name
+	Hosts    []client.HostRule
+}
+
+// profilesKey holds named profiles in a configuration file. Each profile is a
+// map of flag names, like the top level, applied over the top-level values.
+const profilesKey = "profiles"
+
+// Resolve fills flags not given on the command line from the selected profile,
+// the rest of the configuration file (--config, or REST_API_MCP_CONFIG) and the
+// environment, so the precedence is environment < file < profile < command line.
+// The profile comes from --profile, a top-level "profile" key, or
+// REST_API_MCP_PROFILE, in that order. Call after fs.Parse. Returns the
+// structured sections of the file: profile names and named APIs.
+func Resolve(fs *flag.FlagSet, environ []string) (*Sections, error) {
+	applied := SetFlags(fs)
+	envValues, err := EnvValues(fs, environ)
+	if err != nil {
+		return nil, err
+	}
+
+	configFile := flagValue(fs, "config")
+	if envConfigFile, ok := envValues["config"].(string); ok && configFile == "" {
+		configFile = envConfigFile
+	}
+	values := make(map[string]any)
+	if configFile != "" {
+		values, err = LoadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	profiles, err := takeProfiles(values, configFile)
+	if err != nil {
+		return nil, err
+	}
+	apis, err := takeAPIs(values, configFile)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := takeHosts(values, configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := flagValue(fs, "profile")
+	if fileProfile, ok := values["profile"].(string); ok && profileName == "" {
+		profileName = fileProfile
+	}
+	if envProfile, ok := envValues["profile"].(string); ok && profileName == "" {
+		profileName = envProfile
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if profileName != "" {
+		profile, ok := profiles[profileName]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q (defined: %s)", profileName, strings.Join(names, ", "))
+		}
+		if err := Apply(fs, profile, applied, fmt.Sprintf("%s profile %q", configFile, profileName)); err != nil {
+			return nil, err
+		}
+		// Record the resolved name so callers can read it back from the flag.
+		if fs.Lookup("profile") != nil && !applied["profile"] {
+			if err := fs.Set("profile", profileName); err != nil {
+				return nil, err
+			}
+			applied["profile"] = true
+		}
+	}
+
+	if err := Apply(fs, values, applied, configFile); err != nil {
+		return nil, err
+	}
+	if err := Apply(fs, envValues, applied, "environment"); err != nil {
+		return nil, err
+	}
+	return &Sections{Profiles: names, APIs: apis, Hosts: hosts}, nil
+}
+
+// takeProfiles removes the profiles section from values and returns it.
+func takeProfiles(values map[string]any, source string) (map[string]map[string]any, error) {
+	raw, ok := values[profilesKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(values, profilesKey)
+
+	section, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: %q must be a map of profile name to options", source, profilesKey)
+	}
+	profiles := make(map[string]map[string]any, len(section))
+	for name, rawProfile := range section {
+		profile, ok := rawProfile.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: profile %q must be a map of options", source, name)
+		}
+		if _, nested := profile[profilesKey]; nested {
+			return nil, fmt.Errorf("%s: profile %q cannot define profiles", source, name)
+		}
+		profiles[name] = profile
+	}
+	return profiles, nil
+}
+
+func flagValue(fs *flag.FlagSet, name string) string {
+	if f := fs.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
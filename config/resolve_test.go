@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const profilesYAML = `
+base-url: http://base
+timeout: 5s
+profiles:
+  dev:
+    base-url: http://localhost:8080
+  prod:
+    base-url: https://api.example.com
+    read-only: true
+`
+
+func newProfileTestFlags(t *testing.T, content string, args ...string) (*testFlags, *string, *bool) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := newTestFlags()
+	var configFile, profile string
+	var readOnly bool
+	f.fs.StringVar(&configFile, "config", "", "")
+	f.fs.StringVar(&profile, "profile", "", "")
+	f.fs.BoolVar(&readOnly, "read-only", false, "")
+	if err := f.fs.Parse(append([]string{"--config", path}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return f, &profile, &readOnly
+}
+
+func Test_Resolve_ConfigFileFromEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("base-url: http://file\ntimeout: 5s\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f := newTestFlags()
+	var configFile string
+	f.fs.StringVar(&configFile, "config", "", "")
+
+	_, err := Resolve(f.fs, []string{
+		"REST_API_MCP_CONFIG=" + path,
+		"REST_API_MCP_BASE_URL=http://env",
+		"REST_API_MCP_INSECURE=true",
+	})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if f.baseURL != "http://file" || f.timeout != 5*time.Second || !f.insecure {
+		t.Errorf("unexpected values: base-url=%s timeout=%s insecure=%v", f.baseURL, f.timeout, f.insecure)
+	}
+}
+
+func Test_Resolve_ProfileOverridesFile(t *testing.T) {
+	f, profile, readOnly := newProfileTestFlags(t, profilesYAML, "--profile", "prod")
+	sections, err := Resolve(f.fs, nil)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if !reflect.DeepEqual(sections.Profiles, []string{"dev", "prod"}) {
+		t.Errorf("profile names = %v", sections.Profiles)
+	}
+	if *profile != "prod" || f.baseURL != "https://api.example.com" || !*readOnly || f.timeout != 5*time.Second {
+		t.Errorf("unexpected values: profile=%s base-url=%s read-only=%v timeout=%s", *profile, f.baseURL, *readOnly, f.timeout)
+	}
+}
+
+func Test_Resolve_CommandLineOverridesProfile(t *testing.T) {
+	f, _, _ := newProfileTestFlags(t, profilesYAML, "--profile", "dev", "--base-url", "http://cli")
+	if _, err := Resolve(f.fs, nil); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if f.baseURL != "http://cli" {
+		t.Errorf("expected command line to win, got %s", f.baseURL)
+	}
+}
+
+func Test_Resolve_ProfileFromEnvironment(t *testing.T) {
+	f, profile, _ := newProfileTestFlags(t, profilesYAML)
+	if _, err := Resolve(f.fs, []string{"REST_API_MCP_PROFILE=dev"}); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if *profile != "dev" || f.baseURL != "http://localhost:8080" {
+		t.Errorf("unexpected values: profile=%s base-url=%s", *profile, f.baseURL)
+	}
+}
+
+func Test_Resolve_NoProfileUsesTopLevel(t *testing.T) {
+	f, profile, _ := newProfileTestFlags(t, profilesYAML)
+	if _, err := Resolve(f.fs, nil); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if *profile != "" || f.baseURL != "http://base" {
+		t.Errorf("unexpected values: profile=%s base-url=%s", *profile, f.baseURL)
+	}
+}
+
+func Test_Resolve_ProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		want    string
+	}{
+		{"unknown profile", profilesYAML, []string{"--profile", "qa"}, `unknown profile "qa" (defined: dev, prod)`},
+		{"profiles not a map", "profiles: [dev]\n", nil, "must be a map"},
+		{"unknown option in profile", "profiles:\n  dev:\n    base_url: x\n", []string{"--profile", "dev"}, `profile "dev": unknown option "base_url"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _, _ := newProfileTestFlags(t, tt.content, tt.args...)
+			_, err := Resolve(f.fs, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/config"
+)
+
+// runConfigCommand implements "config validate" and "config print". args is
+// os.Args[2:]; everything after the action is parsed like the server's flags.
+// Returns the process exit code.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "validate" && args[0] != "print") {
+		fmt.Fprintf(os.Stderr, "Usage:\n  rest-api-mcp config validate [flags]   # check flags, config file and environment\n  rest-api-mcp config print [flags]      # show the effective configuration, secrets redacted\n")
+		return 2
+	}
+	action, flagArgs := args[0], args[1:]
+
+	opts, sections, err := loadOptions(flagArgs, os.Environ(), "", nil, flag.ContinueOnError)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	problems := opts.problems()
+	for _, name := range sections.Profiles {
+		profileOpts, _, err := loadOptions(flagArgs, os.Environ(), name, nil, flag.ContinueOnError)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("profile %s: %s", name, err))
+			continue
+		}
+		for _, problem := range profileOpts.problems() {
+			problems = append(problems, fmt.Sprintf("profile %s: %s", name, problem))
+		}
+	}
+	for _, api := range sections.APIs {
+		apiOpts, _, err := loadOptions(flagArgs, os.Environ(), opts.profile, &api, flag.ContinueOnError)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("API %s: %s", api.Name, err))
+			continue
+		}
+		for _, problem := range apiOpts.problems() {
+			problems = append(problems, fmt.Sprintf("API %s: %s", api.Name, problem))
+		}
+	}
+
+	if action == "print" {
+		output, err := yaml.Marshal(effectiveConfig(opts, sections.Profiles, sections.APIs))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: rendering configuration: %s\n", err)
+			return 1
+		}
+		fmt.Print(string(output))
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "problem: %s\n", problem)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	if action == "validate" {
+		fmt.Println("Configuration OK")
+	}
+	return 0
+}
+
+// effectiveConfig renders the resolved options in config file form, keyed by
+// flag name, with secrets redacted.
+func effectiveConfig(opts *options, profileNames []string, apis []config.API) map[string]any {
+	effective := make(map[string]any)
+	opts.flags.VisitAll(func(f *flag.Flag) {
+		getter, ok := f.Value.(flag.Getter)
+		if !ok {
+			return
+		}
+		effective[f.Name] = redactFlagValue(f.Name, getter.Get())
+	})
+
+	if len(profileNames) > 0 {
+		effective["profiles"] = profileNames
+	}
+	if len(apis) > 0 {
+		apiNames := make([]string, 0, len(apis))
+		for _, api := range apis {
+			apiNames = append(apiNames, api.Name)
+		}
+		effective["apis"] = apiNames
+	}
+	if len(opts.hostRules) > 0 {
+		hosts := make([]map[string]any, 0, len(opts.hostRules))
+		for _, rule := range opts.hostRules {
+			hosts = append(hosts, redactHostRule(rule))
+		}
+		effective["hosts"] = hosts
+	}
+	return effective
+}
+
+func redactFlagValue(name string, value any) any {
+	switch typed := value.(type) {
+	case time.Duration:
+		return typed.String()
+	case []string:
+		redacted := make([]string, len(typed))
+		for i, entry := range typed {
+			redacted[i] = redactEntry(name, entry)
+		}
+		return redacted
+	case string:
+		switch {
+		case typed == "":
+			return typed
+		case name == "auth-token" || name == "oauth-client-secret" || name == "oauth-refresh-token" || name == "oauth-store-key" ||
+			name == "s3-secret-access-key" || name == "s3-session-token" || name == "azure-client-secret":
+			return "***"
+		case name == "proxy" || name == "base-url":
+			return redactURL(typed)
+		}
+		return typed
+	}
+	return value
+}
+
+// redactEntry hides the value of a sensitive "Key: Value" header or "key=value" query entry.
+func redactEntry(flagName, entry string) string {
+	switch flagName {
+	case "default-header":
+		if name, _, ok := strings.Cut(entry, ": "); ok && client.IsSensitiveHeader(name) {
+			return name + ": ***"
+		}
+	case "default-query":
+		if name, _, ok := strings.Cut(entry, "="); ok && client.IsSensitiveQueryParam(name) {
+			return name + "=***"
+		}
+	}
+	return entry
+}
+
+func redactHostRule(rule client.HostRule) map[string]any {
+	host := map[string]any{"match": rule.Match}
+	if len(rule.DefaultHeaders) > 0 {
+		headers := make(map[string]string, len(rule.DefaultHeaders))
+		for name, value := range rule.DefaultHeaders {
+			headers[name] = value
+			if client.IsSensitiveHeader(name) {
+				headers[name] = "***"
+			}
+		}
+		host["default-header"] = headers
+	}
+	if rule.Timeout > 0 {
+		host["timeout"] = rule.Timeout.String()
+	}
+	if rule.RetryCount != nil {
+		host["retry"] = *rule.RetryCount
+	}
+	if rule.RetryDelay > 0 {
+		host["retry-delay"] = rule.RetryDelay.String()
+	}
+	if rule.InsecureTLS {
+		host["insecure"] = true
+	}
+	return host
+}
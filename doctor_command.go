@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/register"
+)
+
+// doctorTimeout bounds each network check.
+const doctorTimeout = 10 * time.Second
+
+// doctorResult is one line of the doctor report.
+type doctorResult struct {
+	status string // ok, warn or FAIL
+	check  string
+	detail string
+	fix    string // what to do about a warn or FAIL
+}
+
+// runDoctorCommand implements "doctor": it checks the installation and the
+// resolved configuration and prints a fix for every problem found. args is
+// os.Args[2:], parsed like the server's flags. Returns the process exit code.
+func runDoctorCommand(args []string) int {
+	opts, _, err := loadOptions(args, os.Environ(), "", nil, flag.ContinueOnError)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	var results []doctorResult
+	results = append(results, checkConfiguration(opts)...)
+	results = append(results, checkBinaryOnPath())
+	results = append(results, checkRegistrations()...)
+	results = append(results, checkProxy(opts)...)
+	results = append(results, checkBaseURL(opts)...)
+	return printDoctorReport(os.Stdout, results)
+}
+
+func printDoctorReport(out io.Writer, results []doctorResult) int {
+	failed := false
+	for _, result := range results {
+		fmt.Fprintf(out, "[%-4s] %s: %s\n", result.status, result.check, result.detail)
+		if result.fix != "" {
+			fmt.Fprintf(out, "       fix: %s\n", result.fix)
+		}
+		failed = failed || result.status == "FAIL"
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func checkConfiguration(opts *options) []doctorResult {
+	problems := opts.problems()
+	if len(problems) == 0 {
+		return []doctorResult{{status: "ok", check: "configuration", detail: "flags, config file and environment are consistent"}}
+	}
+	var results []doctorResult
+	for _, problem := range problems {
+		results = append(results, doctorResult{status: "FAIL", check: "configuration", detail: problem, fix: "correct the flag, config file key or REST_API_MCP_* variable; \"config print\" shows the effective values"})
+	}
+	return results
+}
+
+// checkBinaryOnPath reports whether the binary's name resolves to this binary
+// on PATH. Registered entries use absolute paths, so this is only a warning.
+func checkBinaryOnPath() doctorResult {
+	self, err := executablePath()
+	if err != nil {
+		return doctorResult{status: "warn", check: "binary", detail: err.Error()}
+	}
+	onPath, err := exec.LookPath(filepath.Base(self))
+	if err != nil {
+		return doctorResult{status: "warn", check: "binary", detail: fmt.Sprintf("%s is not on PATH", filepath.Base(self)), fix: fmt.Sprintf("add %s to PATH to run it by name", filepath.Dir(self))}
+	}
+	if !sameFile(onPath, self) {
+		return doctorResult{status: "warn", check: "binary", detail: fmt.Sprintf("%s on PATH is %s, not this binary (%s)", filepath.Base(self), onPath, self), fix: "remove or update the other copy so both are the same version"}
+	}
+	return doctorResult{status: "ok", check: "binary", detail: self}
+}
+
+// checkRegistrations looks for client config entries that run this binary,
+// or an older copy of it that no longer exists.
+func checkRegistrations() []doctorResult {
+	self, err := executablePath()
+	if err != nil {
+		return nil
+	}
+	registrations, errs := register.FindRegistrations(".")
+
+	var results []doctorResult
+	for _, err := range errs {
+		results = append(results, doctorResult{status: "warn", check: "registration", detail: err.Error(), fix: "fix the file by hand so the client can load it"})
+	}
+	found := false
+	for _, registration := range registrations {
+		where := fmt.Sprintf("%s %q in %s", registration.Client, registration.Name, registration.ConfigPath)
+		switch {
+		case registration.Command == "":
+			continue
+		case sameFile(registration.Command, self):
+			found = true
+			results = append(results, doctorResult{status: "ok", check: "registration", detail: where})
+		case strings.HasPrefix(filepath.Base(registration.Command), strings.TrimSuffix(filepath.Base(self), ".exe")):
+			if _, err := os.Stat(registration.Command); err != nil {
+				found = true
+				results = append(results, doctorResult{status: "FAIL", check: "registration", detail: fmt.Sprintf("%s runs %s, which does not exist", where, registration.Command), fix: fmt.Sprintf("run \"%s register ... --client %s --name %s\" again", filepath.Base(self), registration.Client, registration.Name)})
+			} else {
+				results = append(results, doctorResult{status: "warn", check: "registration", detail: fmt.Sprintf("%s runs another copy, %s", where, registration.Command), fix: "register again to switch it to this binary, if intended"})
+			}
+		}
+	}
+	if !found {
+		results = append(results, doctorResult{status: "warn", check: "registration", detail: "no MCP client configuration runs this binary", fix: fmt.Sprintf("run \"%s register user --client all\"", filepath.Base(self))})
+	}
+	return results
+}
+
+func checkProxy(opts *options) []doctorResult {
+	proxy, source := opts.proxy, "--proxy"
+	if proxy == "" {
+		proxy, source = opts.envProxy, "HTTPS_PROXY/HTTP_PROXY (or --proxy-from-env=false)"
+		if proxy != "" && !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy // curl and Go accept host:port
+		}
+	}
+	if proxy == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		if source == "--proxy" {
+			return nil // reported by checkConfiguration
+		}
+		return []doctorResult{{status: "FAIL", check: "proxy", detail: fmt.Sprintf("environment proxy %q is not a URL", redactURL(proxy)), fix: "fix " + source}}
+	}
+	address := proxyURL.Host
+	if proxyURL.Port() == "" {
+		address = net.JoinHostPort(proxyURL.Hostname(), defaultPort(proxyURL.Scheme))
+	}
+	conn, err := net.DialTimeout("tcp", address, doctorTimeout)
+	if err != nil {
+		return []doctorResult{{status: "FAIL", check: "proxy", detail: fmt.Sprintf("cannot connect to %s: %s", address, err), fix: "check " + source + ", and that the proxy is running and reachable from this machine"}}
+	}
+	conn.Close()
+	return []doctorResult{{status: "ok", check: "proxy", detail: "connected to " + address}}
+}
+
+// checkBaseURL sends a GET to the base URL (any HTTP response counts as
+// reachable) and, for https, verifies the certificate chain.
+func checkBaseURL(opts *options) []doctorResult {
+	if opts.baseURL == "" {
+		return []doctorResult{{status: "ok", check: "base URL", detail: "not set; the agent passes absolute URLs"}}
+	}
+	if opts.replayDir != "" {
+		return []doctorResult{{status: "ok", check: "base URL", detail: "not checked: responses are replayed from " + opts.replayDir}}
+	}
+	baseURL, err := url.Parse(opts.baseURL)
+	if err != nil || baseURL.Host == "" {
+		return nil // reported by checkConfiguration
+	}
+
+	cfg := opts.clientConfig()
+	cfg.RetryCount = 0
+	cfg.Chaos = client.ChaosConfig{}
+	cfg.TokenSource = nil // credentials are not needed to prove reachability
+	cfg.RecordDir = ""
+	httpClient := client.NewClient(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	var results []doctorResult
+	resp, err := httpClient.ExecuteRequest(ctx, client.RequestParams{Method: "GET", URL: opts.baseURL, MaxResponseSize: 1024})
+	if err != nil {
+		results = append(results, doctorResult{status: "FAIL", check: "base URL", detail: err.Error(), fix: reachabilityFix(err)})
+	} else {
+		results = append(results, doctorResult{status: "ok", check: "base URL", detail: fmt.Sprintf("GET %s answered %d %s", redactURL(opts.baseURL), resp.StatusCode, resp.StatusText)})
+	}
+
+	if baseURL.Scheme != "https" || opts.proxy != "" {
+		return results
+	}
+	port := baseURL.Port()
+	if port == "" {
+		port = defaultPort(baseURL.Scheme)
+	}
+	inspection, err := httpClient.InspectTLS(ctx, net.JoinHostPort(baseURL.Hostname(), port), "")
+	switch {
+	case err != nil:
+		// the GET above already reported the connection failure
+	case inspection.VerificationError != "" && opts.insecure:
+		results = append(results, doctorResult{status: "warn", check: "TLS", detail: inspection.VerificationError + " (ignored because of --insecure)", fix: "install the issuing CA in the system trust store and drop --insecure"})
+	case inspection.VerificationError != "":
+		results = append(results, doctorResult{status: "FAIL", check: "TLS", detail: inspection.VerificationError, fix: "install the issuing CA in the system trust store, pass caCert per request, or --insecure for local testing only"})
+	default:
+		results = append(results, doctorResult{status: "ok", check: "TLS", detail: fmt.Sprintf("%s, certificate chain verifies", inspection.Protocol)})
+	}
+	return results
+}
+
+// reachabilityFix suggests what to check for a failed base URL request.
+func reachabilityFix(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case strings.Contains(err.Error(), "proxyconnect"):
+		return "the proxy could not be reached; see the proxy check"
+	case errors.As(err, &dnsErr):
+		return "the host name does not resolve; check --base-url and DNS (or --proxy on networks without direct DNS)"
+	case errors.Is(err, client.ErrBlockedAddress):
+		return "--block-private-networks refuses this address; drop the flag for local APIs"
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timeout"):
+		return "no answer in time; check firewalls and whether a --proxy is required"
+	case strings.Contains(err.Error(), "connection refused"):
+		return "nothing listens on that port; start the API or correct the port in --base-url"
+	}
+	return "check --base-url, --proxy and network access from this machine"
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "https":
+		return "443"
+	case "socks5":
+		return "1080"
+	}
+	return "80"
+}
+
+func executablePath() (string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locating this binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(self); err == nil {
+		self = resolved
+	}
+	return self, nil
+}
+
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -0,0 +1,119 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// CloudPlatformScope grants access to all Google Cloud APIs the identity may use.
+const CloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Settings selects the credentials: a service account key file, or, when
+// CredentialsFile is empty, Application Default Credentials
+// (GOOGLE_APPLICATION_CREDENTIALS, the gcloud login, or the metadata server).
+type Settings struct {
+	CredentialsFile string
+}
+
+// Resource maps the hosts matching Match (as in client.HostRule) to the OAuth
+// scopes of the tokens they get.
+type Resource struct {
+	Match  string
+	Scopes []string
+}
+
+// DefaultResources cover the Google APIs when no resource is configured.
+var DefaultResources = []Resource{
+	{Match: "*.googleapis.com", Scopes: []string{CloudPlatformScope}},
+}
+
+// ParseResource parses a "HOST-PATTERN=SCOPE[,SCOPE...]" entry such as
+// "sheets.googleapis.com=https://www.googleapis.com/auth/spreadsheets".
+func ParseResource(entry string) (Resource, error) {
+	match, scopeList, found := strings.Cut(entry, "=")
+	match = strings.TrimSpace(match)
+	var scopes []string
+	for _, scope := range strings.Split(scopeList, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	if !found || match == "" || len(scopes) == 0 {
+		return Resource{}, fmt.Errorf("%q is not in HOST-PATTERN=SCOPE[,SCOPE...] format", entry)
+	}
+	return Resource{Match: match, Scopes: scopes}, nil
+}
+
+// CheckCredentialsFile reports whether path holds a service account key.
+func CheckCredentialsFile(path string) error {
+	_, err := credentials(context.Background(), Settings{CredentialsFile: path}, []string{CloudPlatformScope})
+	return err
+}
+
+// HostTokenSources builds one token source per distinct scope set, so every
+// host of a resource shares its cached token, in the order of resources.
+// Credentials are looked up on the first request that needs a token.
+func HostTokenSources(settings Settings, resources []Resource) []client.HostTokenSource {
+	sources := make(map[string]oauth2.TokenSource)
+	hostSources := make([]client.HostTokenSource, 0, len(resources))
+	for _, resource := range resources {
+		key := strings.Join(resource.Scopes, " ")
+		source, ok := sources[key]
+		if !ok {
+			source = &lazySource{settings: settings, scopes: resource.Scopes}
+			sources[key] = source
+		}
+		hostSources = append(hostSources, client.HostTokenSource{Match: resource.Match, Source: source})
+	}
+	return hostSources
+}
+
+// lazySource finds the credentials when the first token is needed, so a
+// missing gcloud login only fails the requests to Google hosts.
+type lazySource struct {
+	settings Settings
+	scopes   []string
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+func (s *lazySource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.source == nil {
+		creds, err := credentials(context.Background(), s.settings, s.scopes)
+		if err != nil {
+			return nil, err
+		}
+		s.source = creds.TokenSource
+	}
+	return s.source.Token()
+}
+
+func credentials(ctx context.Context, settings Settings, scopes []string) (*google.Credentials, error) {
+	if settings.CredentialsFile == "" {
+		creds, err := google.FindDefaultCredentials(ctx, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("no Google Application Default Credentials (run \"gcloud auth application-default login\" or set GOOGLE_APPLICATION_CREDENTIALS): %w", err)
+		}
+		return creds, nil
+	}
+	data, err := os.ReadFile(settings.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading Google credentials: %w", err)
+	}
+	creds, err := google.CredentialsFromJSONWithType(ctx, data, google.ServiceAccount, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("Google credentials %s: %w", settings.CredentialsFile, err)
+	}
+	return creds, nil
+}
@@ -0,0 +1,120 @@
+package gcp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// writeServiceAccount writes a service account key whose token endpoint is tokenURL.
+func writeServiceAccount(t *testing.T, tokenURL string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "agent@project.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      tokenURL,
+	})
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_HostTokenSources_ServiceAccount(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.Form.Get("assertion") == "" {
+			t.Errorf("form = %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "google-token", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	settings := Settings{CredentialsFile: writeServiceAccount(t, server.URL)}
+	resources := []Resource{
+		{Match: "storage.googleapis.com", Scopes: []string{CloudPlatformScope}},
+		{Match: "*.googleapis.com", Scopes: []string{CloudPlatformScope}},
+	}
+	sources := HostTokenSources(settings, resources)
+	if sources[0].Source != sources[1].Source {
+		t.Error("resources with the same scopes should share a token source")
+	}
+	for _, source := range sources {
+		token, err := source.Source.Token()
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+		if token.AccessToken != "google-token" {
+			t.Errorf("AccessToken = %q", token.AccessToken)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("token endpoint called %d times, want 1 (cached)", calls.Load())
+	}
+}
+
+func Test_CheckCredentialsFile(t *testing.T) {
+	notServiceAccount := filepath.Join(t.TempDir(), "user.json")
+	os.WriteFile(notServiceAccount, []byte(`{"type":"authorized_user"}`), 0o600)
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"service account", writeServiceAccount(t, "https://oauth2.googleapis.com/token"), ""},
+		{"missing file", filepath.Join(t.TempDir(), "missing.json"), "reading Google credentials"},
+		{"wrong type", notServiceAccount, "expected credential type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCredentialsFile(tt.path)
+			if (tt.wantErr == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ParseResource(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    []string
+		wantErr bool
+	}{
+		{"sheets.googleapis.com=https://www.googleapis.com/auth/spreadsheets", []string{"https://www.googleapis.com/auth/spreadsheets"}, false},
+		{"*.googleapis.com=a, b", []string{"a", "b"}, false},
+		{"*.googleapis.com=", nil, true},
+		{"*.googleapis.com", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := ParseResource(tt.entry)
+			if (err != nil) != tt.wantErr || strings.Join(got.Scopes, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("ParseResource(%q) = %+v, %v", tt.entry, got, err)
+			}
+		})
+	}
+}
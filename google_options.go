@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/gcp"
+)
+
+// defineGoogleFlags defines the --google-* flags.
+func (o *options) defineGoogleFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.googleCredentials, "google-credentials", "", "Google service account key file; requests to Google APIs get its tokens")
+	fs.BoolVar(&o.googleADC, "google-adc", false, "Get Google API tokens from Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS, gcloud login or metadata server)")
+	fs.Var(&o.googleScopes, "google-scope", "Host pattern and OAuth scopes for Google tokens (repeatable, format: \"sheets.googleapis.com=https://www.googleapis.com/auth/spreadsheets\"; default: *.googleapis.com with cloud-platform)")
+}
+
+// googleTokenSources returns the per-host Google token sources, or nil unless
+// --google-credentials or --google-adc is set. Scope errors are reported by
+// problems().
+func (o *options) googleTokenSources() []client.HostTokenSource {
+	if o.googleCredentials == "" && !o.googleADC {
+		return nil
+	}
+	resources := gcp.DefaultResources
+	if len(o.googleScopes) > 0 {
+		resources = nil
+		for _, entry := range o.googleScopes {
+			if resource, err := gcp.ParseResource(entry); err == nil {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	return gcp.HostTokenSources(gcp.Settings{CredentialsFile: o.googleCredentials}, resources)
+}
+
+// googleProblems checks the --google-* flags. Application Default Credentials
+// are only looked up by the first request, so a later gcloud login still works.
+func (o *options) googleProblems() []string {
+	var problems []string
+	for _, entry := range o.googleScopes {
+		if _, err := gcp.ParseResource(entry); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid --google-scope: %s", err))
+		}
+	}
+	if o.googleCredentials != "" {
+		if err := gcp.CheckCredentialsFile(o.googleCredentials); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid --google-credentials: %s", err))
+		}
+		if o.googleADC {
+			problems = append(problems, "--google-credentials and --google-adc are mutually exclusive")
+		}
+	}
+	if o.googleCredentials == "" && !o.googleADC && len(o.googleScopes) > 0 {
+		problems = append(problems, "--google-scope needs --google-credentials or --google-adc")
+	}
+	return problems
+}
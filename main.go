@@ -2,72 +2,168 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/config"
 	"github.com/lexandro/rest-api-mcp/register"
 	"github.com/lexandro/rest-api-mcp/server"
 	"github.com/lexandro/rest-api-mcp/tools"
 )
 
-type repeatedFlag []string
-
-func (f *repeatedFlag) String() string { return strings.Join(*f, ", ") }
-func (f *repeatedFlag) Set(value string) error {
-	*f = append(*f, value)
-	return nil
-}
+// defaultProfileName names the top-level configuration in use_profile when no
+// --profile is selected at startup.
+const defaultProfileName = "default"
 
 func main() {
 	if len(os.Args) > 1 && os.Args[1] == "register" {
 		register.Run(register.ServerInfo{Name: "rest-api"}, os.Args[2:])
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctorCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "oauth" {
+		os.Exit(runOAuthCommand(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		os.Exit(runVersionCommand())
+	}
+	if len(os.Args) > 1 && os.Args[1] == "update" {
+		os.Exit(runUpdateCommand(os.Args[2:]))
+	}
 
-	var (
-		baseURL         string
-		defaultHeaders  repeatedFlag
-		timeout         time.Duration
-		maxResponseSize int64
-		proxy           string
-		retry           int
-		retryDelay      time.Duration
-		insecure        bool
-		cookieJar       bool
-	)
+	opts, sections, err := loadOptions(os.Args[1:], os.Environ(), "", nil, flag.ExitOnError)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if problems := opts.problems(); len(problems) > 0 {
+		log.Fatalf("invalid configuration (run \"%s config validate\" for details): %s", filepath.Base(os.Args[0]), strings.Join(problems, "; "))
+	}
+	apis, err := buildAPIs(opts, sections.APIs)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	flag.StringVar(&baseURL, "base-url", "", "Base URL prepended to relative URLs")
-	flag.Var(&defaultHeaders, "default-header", "Default header (repeatable, format: \"Key: Value\")")
-	flag.DurationVar(&timeout, "timeout", 30*time.Second, "Request timeout")
-	flag.Int64Var(&maxResponseSize, "max-response-size", 51200, "Maximum response body size in bytes")
-	flag.StringVar(&proxy, "proxy", "", "HTTP/HTTPS proxy URL")
-	flag.IntVar(&retry, "retry", 0, "Number of retries for failed requests")
-	flag.DurationVar(&retryDelay, "retry-delay", 1000*time.Millisecond, "Delay between retries")
-	flag.BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification")
-	flag.BoolVar(&cookieJar, "cookie-jar", false, "Enable in-memory cookie jar (persists cookies across requests for session flows)")
+	mcpServer := server.New()
+	var profiles []tools.Profile
+	var active string
+	if len(sections.Profiles) > 0 {
+		if profiles, active, err = buildProfiles(opts, sections.Profiles); err != nil {
+			log.Fatal(err)
+		}
+	}
+	toolsets, err := tools.ParseToolsets(opts.toolsets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.allowProfileSwitch && len(profiles) > 0 && toolsets.Enables("use_profile") {
+		if err := tools.RegisterProfiles(mcpServer, profiles, active, apis); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		settings, err := opts.toolSettings()
+		if err != nil {
+			log.Fatal(err)
+		}
+		settings.Profile = opts.profile
+		clientConfig := opts.clientConfig()
+		if err := tools.Register(mcpServer, client.NewClient(clientConfig), clientConfig, settings, apis); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(profiles) > 1 && toolsets.Enables("http_compare_envs") {
+		tools.RegisterCompareEnvs(mcpServer, profiles)
+	}
 
-	flag.Parse()
+	if opts.chaosLatency > 0 || opts.chaosErrorRate > 0 {
+		log.Printf("chaos testing enabled: latency %s, error rate %.2f, hosts %q", opts.chaosLatency, opts.chaosErrorRate, opts.chaosHosts)
+	}
+	if opts.transport != "stdio" {
+		log.Printf("serving MCP over %s on %s", opts.transport, opts.listenAddr)
+	}
+	if err := server.Run(mcpServer, opts.transportConfig()); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	config := client.Config{
-		BaseURL:         baseURL,
-		DefaultHeaders:  client.ParseHeaders(defaultHeaders),
-		Timeout:         timeout,
-		MaxResponseSize: maxResponseSize,
-		ProxyURL:        proxy,
-		RetryCount:      retry,
-		RetryDelay:      retryDelay,
-		InsecureTLS:     insecure,
-		EnableCookieJar: cookieJar,
+// buildProfiles resolves the options once per profile so each gets its own
+// client, for use_profile and http_compare_envs. Without --profile, the
+// top-level configuration comes first as the active profile "default".
+// Returns the profiles and the active one's name.
+func buildProfiles(opts *options, profileNames []string) ([]tools.Profile, string, error) {
+	active := opts.profile
+	candidates := profileNames
+	if active == "" {
+		switch {
+		case !slices.Contains(profileNames, defaultProfileName):
+			active = defaultProfileName
+			candidates = append([]string{""}, profileNames...)
+		case opts.allowProfileSwitch:
+			return nil, "", fmt.Errorf("profile %q clashes with the top-level configuration's name in use_profile; select a profile with --profile", defaultProfileName)
+		}
 	}
 
-	httpClient := client.NewClient(config)
-	mcpServer := server.New()
-	tools.Register(mcpServer, httpClient, config)
+	var profiles []tools.Profile
+	for _, name := range candidates {
+		profileOpts := opts
+		if name != "" {
+			var err error
+			profileOpts, _, err = loadOptions(os.Args[1:], os.Environ(), name, nil, flag.ContinueOnError)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		settings, err := profileOpts.toolSettings()
+		if err != nil {
+			return nil, "", fmt.Errorf("profile %s: %w", name, err)
+		}
+		if name == "" {
+			name = defaultProfileName
+		}
+		clientConfig := profileOpts.clientConfig()
+		profiles = append(profiles, tools.Profile{
+			Name:     name,
+			Client:   client.NewClient(clientConfig),
+			Config:   clientConfig,
+			Settings: settings,
+		})
+	}
+	return profiles, active, nil
+}
 
-	if err := server.Run(mcpServer); err != nil {
-		log.Fatal(err)
+// buildAPIs resolves each named API's options on top of the startup options
+// (profile included) and gives it its own client.
+func buildAPIs(opts *options, configAPIs []config.API) ([]tools.API, error) {
+	var apis []tools.API
+	for _, configAPI := range configAPIs {
+		apiOpts, _, err := loadOptions(os.Args[1:], os.Environ(), opts.profile, &configAPI, flag.ContinueOnError)
+		if err != nil {
+			return nil, err
+		}
+		if problems := apiOpts.problems(); len(problems) > 0 {
+			return nil, fmt.Errorf("API %s: %s", configAPI.Name, strings.Join(problems, "; "))
+		}
+		settings, err := apiOpts.toolSettings()
+		if err != nil {
+			return nil, fmt.Errorf("API %s: %w", configAPI.Name, err)
+		}
+		clientConfig := apiOpts.clientConfig()
+		apis = append(apis, tools.API{
+			Name:        configAPI.Name,
+			Description: configAPI.Description,
+			Client:      client.NewClient(clientConfig),
+			Config:      clientConfig,
+			Settings:    settings,
+		})
 	}
+	return apis, nil
 }
@@ -0,0 +1,37 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"golang.org/x/oauth2"
+)
+
+// DeviceLogin runs the device authorization flow: it prints the verification
+// URL and user code to out, waits until the user approves, and returns the
+// token (including the refresh token to store).
+func DeviceLogin(ctx context.Context, settings Settings, deviceAuthURL string, out io.Writer) (*oauth2.Token, error) {
+	config := settings.config()
+	config.Endpoint.DeviceAuthURL = deviceAuthURL
+	ctx = settings.withHTTPClient(ctx)
+
+	auth, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting device authorization: %w", err)
+	}
+	verificationURL := auth.VerificationURIComplete
+	if verificationURL == "" {
+		verificationURL = auth.VerificationURI
+	}
+	fmt.Fprintf(out, "Open %s and enter the code %s\nWaiting for approval...\n", verificationURL, auth.UserCode)
+
+	token, err := config.DeviceAccessToken(ctx, auth)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for device approval: %w", err)
+	}
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("the provider returned no refresh token (request the offline_access scope?)")
+	}
+	return token, nil
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_DeviceLogin_ReturnsRefreshToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"device_code":"dev","user_code":"ABCD-EFGH","verification_uri":"https://example.com/activate","interval":1,"expires_in":60}`)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("device_code") != "dev" {
+			http.Error(w, "bad device code", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","token_type":"Bearer","refresh_token":"refresh","expires_in":3600}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var out bytes.Buffer
+	token, err := DeviceLogin(context.Background(), Settings{TokenURL: server.URL + "/token", ClientID: "id"}, server.URL+"/device", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.RefreshToken != "refresh" {
+		t.Errorf("refresh token = %q, want refresh", token.RefreshToken)
+	}
+	if !strings.Contains(out.String(), "ABCD-EFGH") || !strings.Contains(out.String(), "https://example.com/activate") {
+		t.Errorf("instructions %q should show the user code and verification URL", out.String())
+	}
+}
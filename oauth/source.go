@@ -0,0 +1,117 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshTimeout bounds one token endpoint call.
+const refreshTimeout = 30 * time.Second
+
+// ErrNoRefreshToken is returned when neither the settings nor the store hold a refresh token.
+var ErrNoRefreshToken = errors.New("no OAuth2 refresh token: set --oauth-refresh-token or run \"rest-api-mcp oauth login\"")
+
+// Settings configures the refresh-token grant.
+type Settings struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RefreshToken string // used until the store holds a token
+	Store        *Store // optional; persists rotated refresh tokens and the current access token
+
+	// Transport reaches the token and device endpoints, normally the one API
+	// requests use (client.NewTransport), so proxy and TLS settings apply;
+	// nil means http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+func (s Settings) config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		Scopes:       s.Scopes,
+		Endpoint:     oauth2.Endpoint{TokenURL: s.TokenURL},
+	}
+}
+
+// withHTTPClient makes the oauth2 calls made with ctx use the Transport.
+func (s Settings) withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: s.Transport})
+}
+
+// TokenSource exchanges the refresh token for access tokens on demand and
+// reuses each access token until it is about to expire. Every new token —
+// including a refresh token rotated by the provider — is saved to the store.
+//
+// The store is re-read before each exchange, so several sources sharing one
+// store file (profiles, named APIs) pick up each other's rotated tokens.
+type TokenSource struct {
+	settings Settings
+
+	mu      sync.Mutex
+	current *oauth2.Token
+}
+
+func NewTokenSource(settings Settings) *TokenSource {
+	return &TokenSource{settings: settings}
+}
+
+func (s *TokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current.Valid() {
+		return s.current, nil
+	}
+
+	refreshToken, err := s.latest()
+	if err != nil {
+		return nil, err
+	}
+	if refreshToken.Valid() { // another source already refreshed
+		s.current = refreshToken
+		return s.current, nil
+	}
+	if refreshToken.RefreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	ctx = s.settings.withHTTPClient(ctx)
+	token, err := s.settings.config().TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken.RefreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("refreshing OAuth2 access token: %w", err)
+	}
+	if s.settings.Store != nil {
+		if err := s.settings.Store.Save(token); err != nil {
+			return nil, err
+		}
+	}
+	s.current = token
+	return token, nil
+}
+
+// latest returns the freshest known token: the stored one when it exists,
+// otherwise the current or configured refresh token.
+func (s *TokenSource) latest() (*oauth2.Token, error) {
+	if s.settings.Store != nil {
+		stored, err := s.settings.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		if stored != nil && stored.RefreshToken != "" {
+			return stored, nil
+		}
+	}
+	if s.current != nil && s.current.RefreshToken != "" {
+		return &oauth2.Token{RefreshToken: s.current.RefreshToken}, nil
+	}
+	return &oauth2.Token{RefreshToken: s.settings.RefreshToken}, nil
+}
@@ -0,0 +1,127 @@
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// newTokenServer serves the refresh-token grant. Each exchange returns a new
+// access token; rotate also issues a new refresh token.
+func newTokenServer(t *testing.T, rotate bool) (*httptest.Server, *atomic.Int32) {
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "refresh_token" {
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		n := exchanges.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if rotate {
+			fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":3600,"refresh_token":"refresh-%d"}`, n, n)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &exchanges
+}
+
+func Test_TokenSource_ReusesAccessToken(t *testing.T) {
+	server, exchanges := newTokenServer(t, false)
+	source := NewTokenSource(Settings{TokenURL: server.URL, ClientID: "id", RefreshToken: "initial"})
+
+	for range 3 {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.AccessToken != "access-1" {
+			t.Errorf("access token = %q, want access-1", token.AccessToken)
+		}
+	}
+	if exchanges.Load() != 1 {
+		t.Errorf("token endpoint called %d times, want 1", exchanges.Load())
+	}
+}
+
+func Test_TokenSource_PersistsRotatedRefreshToken(t *testing.T) {
+	server, _ := newTokenServer(t, true)
+	store, _ := NewStore(filepath.Join(t.TempDir(), "store.enc"), "key")
+
+	if _, err := NewTokenSource(Settings{TokenURL: server.URL, RefreshToken: "initial", Store: store}).Token(); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.RefreshToken != "refresh-1" || stored.AccessToken != "access-1" {
+		t.Fatalf("stored %+v, want the rotated tokens", stored)
+	}
+
+	// A new source (e.g. after a restart) prefers the stored token over the
+	// configured, now stale, refresh token and reuses the stored access token.
+	token, err := NewTokenSource(Settings{TokenURL: server.URL, RefreshToken: "revoked", Store: store}).Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-1" {
+		t.Errorf("access token = %q, want the stored access-1", token.AccessToken)
+	}
+}
+
+func Test_TokenSource_Errors(t *testing.T) {
+	server, _ := newTokenServer(t, false)
+	tests := []struct {
+		name         string
+		refreshToken string
+		wantErr      error
+	}{
+		{"no refresh token", "", ErrNoRefreshToken},
+		{"rejected refresh token", "revoked", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTokenSource(Settings{TokenURL: server.URL, RefreshToken: tt.refreshToken}).Token()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func Test_TokenSource_UsesTransport(t *testing.T) {
+	server, _ := newTokenServer(t, false)
+	var routed atomic.Int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		routed.Add(1)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	source := NewTokenSource(Settings{TokenURL: server.URL, ClientID: "id", RefreshToken: "initial", Transport: transport})
+
+	if _, err := source.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if routed.Load() != 1 {
+		t.Errorf("token request went through the transport %d times, want 1", routed.Load())
+	}
+}
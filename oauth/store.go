@@ -0,0 +1,156 @@
+// Package oauth obtains OAuth2 access tokens from a refresh token, keeps the
+// (possibly rotated) refresh token in an encrypted file, and runs the device
+// authorization flow that produces the first refresh token.
+package oauth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/oauth2"
+)
+
+// ErrWrongStoreKey is returned when the token store cannot be decrypted with the given key.
+var ErrWrongStoreKey = errors.New("token store cannot be decrypted (wrong --oauth-store-key?)")
+
+// storeMagic starts a store file; the random salt the key is derived with
+// follows it.
+var storeMagic = []byte("RAMCP-SCRYPT1\n")
+
+// Scrypt cost parameters (N, r, p) for the store key, and the salt length.
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	storeSaltSize = 16
+)
+
+// Store is an AES-GCM encrypted token file. The key is derived from a
+// passphrase with scrypt and a random salt stored in the file header, so a
+// stolen file cannot be brute-forced at hash speed.
+type Store struct {
+	path       string
+	passphrase string
+}
+
+// storedToken is the persisted form of an oauth2.Token.
+type storedToken struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Expiry       string `json:"expiry,omitempty"`
+}
+
+func NewStore(path, passphrase string) (*Store, error) {
+	if passphrase == "" {
+		return nil, errors.New("a token store needs an encryption key (--oauth-store-key)")
+	}
+	return &Store{path: path, passphrase: passphrase}, nil
+}
+
+// cipherFor returns the AES-GCM cipher for the passphrase and salt.
+func (s *Store) cipherFor(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(s.passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving store key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// Load returns the stored token, or nil when the file does not exist yet.
+func (s *Store) Load() (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading token store: %w", err)
+	}
+	rest, found := bytes.CutPrefix(data, storeMagic)
+	if !found {
+		return nil, fmt.Errorf("token store %s has no key salt header; delete it and authorize again", s.path)
+	}
+	if len(rest) < storeSaltSize {
+		return nil, ErrWrongStoreKey
+	}
+	salt, data := rest[:storeSaltSize], rest[storeSaltSize:]
+	aead, err := s.cipherFor(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, ErrWrongStoreKey
+	}
+	plaintext, err := aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrWrongStoreKey
+	}
+
+	var stored storedToken
+	if err := json.Unmarshal(plaintext, &stored); err != nil {
+		return nil, fmt.Errorf("parsing token store: %w", err)
+	}
+	token := &oauth2.Token{AccessToken: stored.AccessToken, TokenType: stored.TokenType, RefreshToken: stored.RefreshToken}
+	if stored.Expiry != "" {
+		if err := token.Expiry.UnmarshalText([]byte(stored.Expiry)); err != nil {
+			return nil, fmt.Errorf("parsing token store: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// Save encrypts token into the store file, replacing it atomically.
+func (s *Store) Save(token *oauth2.Token) error {
+	stored := storedToken{AccessToken: token.AccessToken, TokenType: token.TokenType, RefreshToken: token.RefreshToken}
+	if !token.Expiry.IsZero() {
+		expiry, _ := token.Expiry.MarshalText()
+		stored.Expiry = string(expiry)
+	}
+	plaintext, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encoding token: %w", err)
+	}
+	salt := make([]byte, storeSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+	aead, err := s.cipherFor(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	header := append(append([]byte{}, storeMagic...), salt...)
+	ciphertext := aead.Seal(append(header, nonce...), nonce, plaintext, nil)
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating token store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, ciphertext, 0o600); err != nil {
+		return fmt.Errorf("writing token store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("writing token store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func Test_Store_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "store.enc")
+	store, err := NewStore(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.Save(&oauth2.Token{AccessToken: "access", TokenType: "Bearer", RefreshToken: "refresh-secret", Expiry: expiry}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "refresh-secret") {
+		t.Errorf("store file must be encrypted, found the refresh token in plain text")
+	}
+	if !bytes.HasPrefix(raw, storeMagic) || len(raw) < len(storeMagic)+storeSaltSize {
+		t.Errorf("store file lacks the scrypt header and salt")
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("store file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.AccessToken != "access" || loaded.RefreshToken != "refresh-secret" || !loaded.Expiry.Equal(expiry) {
+		t.Errorf("loaded %+v, want the saved token", loaded)
+	}
+}
+
+func Test_Store_Load(t *testing.T) {
+	dir := t.TempDir()
+	saved, _ := NewStore(filepath.Join(dir, "store.enc"), "right")
+	if err := saved.Save(&oauth2.Token{RefreshToken: "r"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		passphrase string
+		wantErr    error
+	}{
+		{"missing file", filepath.Join(dir, "missing.enc"), "right", nil},
+		{"wrong key", filepath.Join(dir, "store.enc"), "wrong", ErrWrongStoreKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := NewStore(tt.path, tt.passphrase)
+			token, err := store.Load()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if token != nil {
+				t.Errorf("token = %+v, want nil", token)
+			}
+		})
+	}
+}
+
+func Test_Store_RejectsUnsaltedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.enc")
+	key := sha256.Sum256([]byte("passphrase"))
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	if err := os.WriteFile(path, aead.Seal(nonce, nonce, []byte(`{"refresh_token":"legacy"}`), nil), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, _ := NewStore(path, "passphrase")
+	if token, err := store.Load(); err == nil || !strings.Contains(err.Error(), "no key salt header") {
+		t.Errorf("expected a file without a salt refused, got %+v, %v", token, err)
+	}
+}
+
+func Test_NewStore_RequiresPassphrase(t *testing.T) {
+	if _, err := NewStore("store.enc", ""); err == nil {
+		t.Error("expected an error for an empty passphrase")
+	}
+}
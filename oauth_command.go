@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+
+	"golang.org/x/oauth2"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/oauth"
+)
+
+// oauthSettings builds the refresh-token grant settings from the --oauth-*
+// flags; the token endpoint is reached through transport, the API's.
+func (o *options) oauthSettings(transport http.RoundTripper) (oauth.Settings, error) {
+	settings := oauth.Settings{
+		TokenURL:     o.oauthTokenURL,
+		ClientID:     o.oauthClientID,
+		ClientSecret: o.oauthClientSecret,
+		Scopes:       splitList(o.oauthScopes),
+		RefreshToken: o.oauthRefreshToken,
+		Transport:    transport,
+	}
+	if o.oauthTokenStore != "" {
+		store, err := oauth.NewStore(o.oauthTokenStore, o.oauthStoreKey)
+		if err != nil {
+			return oauth.Settings{}, err
+		}
+		settings.Store = store
+	}
+	return settings, nil
+}
+
+// tokenSource returns nil unless --oauth-token-url is set. Setting errors are
+// reported by problems().
+func (o *options) tokenSource(transport http.RoundTripper) oauth2.TokenSource {
+	if o.oauthTokenURL == "" {
+		return nil
+	}
+	settings, err := o.oauthSettings(transport)
+	if err != nil {
+		return nil
+	}
+	return oauth.NewTokenSource(settings)
+}
+
+// oauthProblems checks that the --oauth-* flags form a usable refresh-token grant.
+func (o *options) oauthProblems() []string {
+	var problems []string
+	for _, endpoint := range [][2]string{{"--oauth-token-url", o.oauthTokenURL}, {"--oauth-device-url", o.oauthDeviceURL}} {
+		name, value := endpoint[0], endpoint[1]
+		if parsed, err := url.Parse(value); value != "" && (err != nil || parsed.Scheme == "" || parsed.Host == "") {
+			problems = append(problems, fmt.Sprintf("%s %q is not an absolute URL", name, redactURL(value)))
+		}
+	}
+	if o.oauthTokenURL == "" {
+		if o.oauthRefreshToken != "" || o.oauthTokenStore != "" || o.oauthDeviceURL != "" {
+			problems = append(problems, "--oauth-refresh-token, --oauth-token-store and --oauth-device-url need --oauth-token-url")
+		}
+		return problems
+	}
+	if o.oauthRefreshToken == "" && o.oauthTokenStore == "" {
+		problems = append(problems, "--oauth-token-url needs --oauth-refresh-token or --oauth-token-store (filled by \"rest-api-mcp oauth login\")")
+	}
+	if o.oauthTokenStore != "" && o.oauthStoreKey == "" {
+		problems = append(problems, "--oauth-token-store needs --oauth-store-key to encrypt it")
+	}
+	return problems
+}
+
+// runOAuthCommand implements "oauth login": the device authorization flow,
+// which saves the resulting refresh token in --oauth-token-store. args is
+// os.Args[2:]. Returns the process exit code.
+func runOAuthCommand(args []string) int {
+	if len(args) == 0 || args[0] != "login" {
+		fmt.Fprintf(os.Stderr, "Usage:\n  rest-api-mcp oauth login [flags]   # device login; needs --oauth-token-url, --oauth-device-url, --oauth-client-id, --oauth-token-store\n")
+		return 2
+	}
+
+	opts, _, err := loadOptions(args[1:], os.Environ(), "", nil, flag.ContinueOnError)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	if opts.oauthDeviceURL == "" || opts.oauthTokenStore == "" {
+		fmt.Fprintf(os.Stderr, "Error: oauth login needs --oauth-token-url, --oauth-device-url and --oauth-token-store\n")
+		return 1
+	}
+	if problems := opts.oauthProblems(); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "problem: %s\n", problem)
+		}
+		return 1
+	}
+	settings, err := opts.oauthSettings(client.NewTransport(opts.clientConfig()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	token, err := oauth.DeviceLogin(ctx, settings, opts.oauthDeviceURL, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	if err := settings.Store.Save(token); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	fmt.Printf("Logged in; tokens saved to %s\n", opts.oauthTokenStore)
+	return 0
+}
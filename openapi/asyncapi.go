@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Event stream protocols of AsyncAPI channels; the names match client.StreamSSE
+// and client.StreamWebSocket.
+const (
+	ProtocolSSE       = "sse"
+	ProtocolWebSocket = "websocket"
+)
+
+// serverVariable matches a {variable} in an AsyncAPI server URL or host.
+var serverVariable = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// AsyncSpec is a parsed AsyncAPI description, reduced to the channels a client
+// can receive events from over Server-Sent Events or WebSocket.
+type AsyncSpec struct {
+	Title    string
+	Version  string // of the API, from info.version
+	Channels []Channel
+}
+
+// Channel is an AsyncAPI channel on which the described application sends
+// events: a 2.x channel with a subscribe operation, or a 3.x send operation.
+type Channel struct {
+	ID         string // operationId (2.x) or operation key (3.x); may be empty
+	Name       string // channel key
+	Address    string // path appended to the server URL, e.g. /orders/{orderId}
+	Summary    string
+	Protocol   string // ProtocolSSE or ProtocolWebSocket
+	ServerURL  string // ws(s) or http(s) URL without a trailing slash
+	Parameters []ChannelParameter
+	Messages   []string // message names, for tool descriptions
+}
+
+// ChannelParameter is a {parameter} of a channel address.
+type ChannelParameter struct {
+	Name        string
+	Description string
+}
+
+// LoadAsyncAPI reads an AsyncAPI description from a file or an http(s) URL.
+func LoadAsyncAPI(ctx context.Context, location string) (*AsyncSpec, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		data, err = fetchAsyncAPI(ctx, location)
+	} else {
+		data, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading AsyncAPI description: %w", err)
+	}
+	spec, err := ParseAsyncAPI(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", location, err)
+	}
+	return spec, nil
+}
+
+func fetchAsyncAPI(ctx context.Context, location string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.5")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", location, response.Status)
+	}
+	return readLimited(response.Body)
+}
+
+// ParseAsyncAPI reads an AsyncAPI 2.x or 3.x description in JSON or YAML.
+// Channels served only over other protocols (Kafka, MQTT, AMQP, ...) are left out.
+func ParseAsyncAPI(data []byte) (*AsyncSpec, error) {
+	document := data
+	if !gjson.ValidBytes(data) {
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		document = converted
+	}
+	root := gjson.ParseBytes(document)
+	version := root.Get("asyncapi").String()
+	if version == "" {
+		return nil, fmt.Errorf("not an AsyncAPI description (no asyncapi field)")
+	}
+	refs := &Spec{document: document}
+	spec := &AsyncSpec{Title: root.Get("info.title").String(), Version: root.Get("info.version").String()}
+	servers := asyncServers(root)
+	if strings.HasPrefix(version, "2.") {
+		root.Get("channels").ForEach(func(name, channel gjson.Result) bool {
+			operation := channel.Get("subscribe")
+			if !operation.Exists() {
+				return true
+			}
+			summary := operation.Get("summary").String()
+			if summary == "" {
+				summary = channel.Get("description").String()
+			}
+			spec.addChannel(refs, servers, Channel{ID: operation.Get("operationId").String(), Name: name.String(), Address: name.String(), Summary: summary},
+				channel, messageNames(refs, operation.Get("message")))
+			return true
+		})
+		return spec, nil
+	}
+	root.Get("operations").ForEach(func(id, operation gjson.Result) bool {
+		operation = refs.resolve(operation)
+		channelRef := operation.Get("channel.$ref").String()
+		channel := refs.resolve(operation.Get("channel"))
+		if operation.Get("action").String() != "send" || !channel.Get("address").Exists() || channel.Get("address").Type == gjson.Null {
+			return true
+		}
+		summary := operation.Get("summary").String()
+		if summary == "" {
+			summary = channel.Get("summary").String()
+		}
+		var names []string
+		channel.Get("messages").ForEach(func(key, message gjson.Result) bool {
+			names = append(names, messageName(refs, key.String(), message))
+			return true
+		})
+		spec.addChannel(refs, servers, Channel{ID: id.String(), Name: channelRef[strings.LastIndex(channelRef, "/")+1:], Address: channel.Get("address").String(), Summary: summary},
+			channel, names)
+		return true
+	})
+	return spec, nil
+}
+
+// addChannel completes a channel with its parameters and the first server it
+// is available on over SSE or WebSocket, and keeps it when there is one.
+func (s *AsyncSpec) addChannel(refs *Spec, servers []asyncServer, channel Channel, node gjson.Result, messages []string) {
+	var allowed []string
+	for _, server := range node.Get("servers").Array() {
+		name := server.String()
+		if ref := server.Get("$ref").String(); ref != "" {
+			name = ref[strings.LastIndex(ref, "/")+1:]
+		}
+		allowed = append(allowed, name)
+	}
+	for _, server := range servers {
+		if len(allowed) > 0 && !slices.Contains(allowed, server.name) {
+			continue
+		}
+		channel.Protocol, channel.ServerURL = server.protocol, server.url
+		break
+	}
+	if channel.Protocol == "" {
+		return
+	}
+	node.Get("parameters").ForEach(func(name, parameter gjson.Result) bool {
+		channel.Parameters = append(channel.Parameters, ChannelParameter{Name: name.String(), Description: refs.resolve(parameter).Get("description").String()})
+		return true
+	})
+	channel.Messages = messages
+	s.Channels = append(s.Channels, channel)
+}
+
+// asyncServer is a server of an AsyncAPI description reachable over SSE or WebSocket.
+type asyncServer struct {
+	name     string
+	protocol string
+	url      string
+}
+
+// asyncServers returns the SSE and WebSocket servers in declaration order,
+// their variables replaced by their defaults. 2.x servers have a url; 3.x ones
+// a host and pathname.
+func asyncServers(root gjson.Result) []asyncServer {
+	var servers []asyncServer
+	root.Get("servers").ForEach(func(name, server gjson.Result) bool {
+		protocol, scheme := streamProtocol(server.Get("protocol").String())
+		if protocol == "" {
+			return true
+		}
+		address := server.Get("url").String()
+		if address == "" {
+			address = server.Get("host").String() + server.Get("pathname").String()
+		}
+		address = serverVariable.ReplaceAllStringFunc(address, func(variable string) string {
+			return server.Get("variables." + gjson.Escape(variable[1:len(variable)-1]) + ".default").String()
+		})
+		if !strings.Contains(address, "://") {
+			address = scheme + "://" + address
+		}
+		servers = append(servers, asyncServer{name: name.String(), protocol: protocol, url: strings.TrimRight(address, "/")})
+		return true
+	})
+	return servers
+}
+
+// streamProtocol maps an AsyncAPI server protocol to the stream protocol and
+// URL scheme it is reached with; "" for protocols other than SSE and WebSocket.
+func streamProtocol(protocol string) (string, string) {
+	switch strings.ToLower(protocol) {
+	case "ws":
+		return ProtocolWebSocket, "ws"
+	case "wss":
+		return ProtocolWebSocket, "wss"
+	case "http", "sse":
+		return ProtocolSSE, "http"
+	case "https":
+		return ProtocolSSE, "https"
+	}
+	return "", ""
+}
+
+// messageNames lists the messages of a 2.x operation: one message, or oneOf several.
+func messageNames(refs *Spec, message gjson.Result) []string {
+	if !message.Exists() {
+		return nil
+	}
+	if variants := refs.resolve(message).Get("oneOf"); variants.Exists() {
+		var names []string
+		for _, variant := range variants.Array() {
+			names = append(names, messageName(refs, "", variant))
+		}
+		return names
+	}
+	return []string{messageName(refs, "", message)}
+}
+
+// messageName is a message's name, title or $ref name, or key when it has none.
+func messageName(refs *Spec, key string, message gjson.Result) string {
+	if ref := message.Get("$ref").String(); ref != "" {
+		key = ref[strings.LastIndex(ref, "/")+1:]
+	}
+	resolved := refs.resolve(message)
+	for _, field := range []string{"name", "title"} {
+		if name := resolved.Get(field).String(); name != "" {
+			return name
+		}
+	}
+	if key == "" {
+		return "message"
+	}
+	return key
+}
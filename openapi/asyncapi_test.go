@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+)
+
+const asyncAPI2 = `
+asyncapi: 2.6.0
+info: {title: Market data, version: 1.2.0}
+servers:
+  broker: {url: "broker.example.com:9092", protocol: kafka}
+  feed:
+    url: "{region}.feed.example.com/v1"
+    protocol: wss
+    variables:
+      region: {default: eu}
+channels:
+  prices/{symbol}:
+    description: Price ticks for one symbol.
+    parameters:
+      symbol: {description: "Ticker symbol, e.g. ACME"}
+    subscribe:
+      operationId: streamPrices
+      message:
+        oneOf:
+          - $ref: '#/components/messages/Tick'
+          - {name: Halt}
+  orders:
+    publish:
+      operationId: placeOrder
+      message: {name: Order}
+  audit:
+    servers: [broker]
+    subscribe:
+      operationId: auditLog
+      message: {name: AuditEntry}
+components:
+  messages:
+    Tick: {payload: {type: object}}
+`
+
+const asyncAPI3 = `{
+  "asyncapi": "3.0.0",
+  "info": {"title": "Orders", "version": "3"},
+  "servers": {"api": {"host": "api.example.com", "pathname": "/events", "protocol": "https"}},
+  "channels": {
+    "orderUpdates": {
+      "address": "/orders/{orderId}",
+      "parameters": {"orderId": {"$ref": "#/components/parameters/orderId"}},
+      "messages": {"updated": {"$ref": "#/components/messages/OrderUpdated"}}
+    },
+    "commands": {"address": "/commands"}
+  },
+  "operations": {
+    "receiveOrderUpdates": {"action": "send", "summary": "Order status changes.", "channel": {"$ref": "#/channels/orderUpdates"}},
+    "sendCommand": {"action": "receive", "channel": {"$ref": "#/channels/commands"}}
+  },
+  "components": {
+    "parameters": {"orderId": {"description": "Order ID"}},
+    "messages": {"OrderUpdated": {"name": "orderUpdated"}}
+  }
+}`
+
+func Test_ParseAsyncAPI_Version2(t *testing.T) {
+	spec, err := ParseAsyncAPI([]byte(asyncAPI2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Title != "Market data" || len(spec.Channels) != 1 {
+		t.Fatalf("expected only the WebSocket subscribe channel, got %+v", spec)
+	}
+	channel := spec.Channels[0]
+	if channel.ID != "streamPrices" || channel.Address != "prices/{symbol}" || channel.Summary != "Price ticks for one symbol." {
+		t.Errorf("unexpected channel %+v", channel)
+	}
+	if channel.Protocol != ProtocolWebSocket || channel.ServerURL != "wss://eu.feed.example.com/v1" {
+		t.Errorf("server = %s %s, want websocket wss://eu.feed.example.com/v1", channel.Protocol, channel.ServerURL)
+	}
+	if len(channel.Parameters) != 1 || channel.Parameters[0] != (ChannelParameter{Name: "symbol", Description: "Ticker symbol, e.g. ACME"}) {
+		t.Errorf("parameters = %+v", channel.Parameters)
+	}
+	if !slices.Equal(channel.Messages, []string{"Tick", "Halt"}) {
+		t.Errorf("messages = %q", channel.Messages)
+	}
+}
+
+func Test_ParseAsyncAPI_Version3(t *testing.T) {
+	spec, err := ParseAsyncAPI([]byte(asyncAPI3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Channels) != 1 {
+		t.Fatalf("expected only the send operation, got %+v", spec.Channels)
+	}
+	channel := spec.Channels[0]
+	if channel.ID != "receiveOrderUpdates" || channel.Name != "orderUpdates" || channel.Address != "/orders/{orderId}" || channel.Summary != "Order status changes." {
+		t.Errorf("unexpected channel %+v", channel)
+	}
+	if channel.Protocol != ProtocolSSE || channel.ServerURL != "https://api.example.com/events" {
+		t.Errorf("server = %s %s, want sse https://api.example.com/events", channel.Protocol, channel.ServerURL)
+	}
+	if len(channel.Parameters) != 1 || channel.Parameters[0].Description != "Order ID" || !slices.Equal(channel.Messages, []string{"orderUpdated"}) {
+		t.Errorf("parameters %+v, messages %q", channel.Parameters, channel.Messages)
+	}
+}
+
+func Test_ParseAsyncAPI_RejectsOtherDocuments(t *testing.T) {
+	if _, err := ParseAsyncAPI([]byte(`{"openapi": "3.0.0"}`)); err == nil {
+		t.Error("expected an OpenAPI description to be rejected")
+	}
+}
@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Mismatch kinds reported by Check.
+const (
+	UndeclaredStatus = "undeclared_status"
+	UndeclaredField  = "undeclared_field"
+	MissingRequired  = "missing_required"
+	TypeMismatch     = "type_mismatch"
+)
+
+// maxCheckDepth bounds the nesting Check descends into.
+const maxCheckDepth = 64
+
+// Mismatch is one way a response departs from its declared schema. Array
+// indexes in Path are generalized to #, so the same drift in every element of
+// a list is one Mismatch with a Count.
+type Mismatch struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path,omitempty"` // GJSON path, e.g. data.#.id; empty for the whole body
+	Detail string `json:"detail"`
+	Count  int    `json:"count"` // occurrences, e.g. array elements with it
+}
+
+// Check compares a response with the schema the operation declares for its
+// status: undeclared fields (unless additionalProperties allows them),
+// missing required fields and type mismatches. The response key the status
+// matched is returned too; non-JSON bodies are only checked for their status.
+func (s *Spec) Check(operation Operation, status int, body []byte) ([]Mismatch, string) {
+	schema, key, declared := s.ResponseSchema(operation, status)
+	if !declared {
+		return []Mismatch{{Kind: UndeclaredStatus, Detail: fmt.Sprintf("status %d is not declared (declared: %s)", status, strings.Join(s.DeclaredStatuses(operation), ", ")), Count: 1}}, ""
+	}
+	if !schema.Exists() || !gjson.ValidBytes(body) {
+		return nil, key
+	}
+	checker := checker{counts: make(map[Mismatch]int)}
+	checker.check(schema, gjson.ParseBytes(body), "", 0)
+	mismatches := make([]Mismatch, 0, len(checker.order))
+	for _, mismatch := range checker.order {
+		mismatch.Count = checker.counts[mismatch]
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches, key
+}
+
+type checker struct {
+	counts map[Mismatch]int // keyed without Count
+	order  []Mismatch
+}
+
+func (c *checker) report(kind, path, detail string) {
+	mismatch := Mismatch{Kind: kind, Path: path, Detail: detail}
+	if c.counts[mismatch] == 0 {
+		c.order = append(c.order, mismatch)
+	}
+	c.counts[mismatch]++
+}
+
+func (c *checker) check(schema Schema, value gjson.Result, path string, depth int) {
+	if !schema.Exists() || depth > maxCheckDepth {
+		return
+	}
+	if value.Type == gjson.Null {
+		if !schema.Nullable() && schema.Type() != "" {
+			c.report(TypeMismatch, path, fmt.Sprintf("is null, declared %s (not nullable)", schema.Type()))
+		}
+		return
+	}
+	if variants := schema.Variants(); len(variants) > 0 {
+		matched := slices.ContainsFunc(variants, func(variant Schema) bool {
+			trial := checker{counts: make(map[Mismatch]int)}
+			trial.check(variant, value, path, depth+1)
+			return len(trial.order) == 0
+		})
+		if !matched {
+			c.report(TypeMismatch, path, fmt.Sprintf("%s matches none of the %d declared alternatives", jsonType(value), len(variants)))
+		}
+	}
+
+	declared, actual := schema.Type(), jsonType(value)
+	if declared != "" && declared != actual && !(declared == "number" && actual == "integer") {
+		c.report(TypeMismatch, path, fmt.Sprintf("is %s, declared %s", actual, declared))
+		return
+	}
+	switch actual {
+	case "object":
+		c.checkObject(schema, value, path, depth)
+	case "array":
+		items := schema.Items()
+		value.ForEach(func(_, element gjson.Result) bool {
+			c.check(items, element, joinPath(path, "#"), depth+1)
+			return true
+		})
+	}
+}
+
+func (c *checker) checkObject(schema Schema, value gjson.Result, path string, depth int) {
+	properties := schema.Properties()
+	for _, name := range schema.Required() {
+		if !value.Get(gjson.Escape(name)).Exists() {
+			c.report(MissingRequired, joinPath(path, gjson.Escape(name)), "required field is missing")
+		}
+	}
+	additional, allowed := schema.AdditionalProperties()
+	value.ForEach(func(key, field gjson.Result) bool {
+		fieldPath := joinPath(path, gjson.Escape(key.String()))
+		index := slices.IndexFunc(properties, func(property Property) bool { return property.Name == key.String() })
+		switch {
+		case index >= 0:
+			c.check(properties[index].Schema, field, fieldPath, depth+1)
+		case allowed:
+			c.check(additional, field, fieldPath, depth+1)
+		case len(properties) > 0:
+			c.report(UndeclaredField, fieldPath, fmt.Sprintf("%s field is not in the schema", jsonType(field)))
+		}
+		return true
+	})
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonType names the JSON schema type of value; whole numbers are integers.
+func jsonType(value gjson.Result) string {
+	switch value.Type {
+	case gjson.String:
+		return "string"
+	case gjson.Number:
+		if number := value.Float(); number == math.Trunc(number) && !strings.ContainsAny(value.Raw, ".eE") {
+			return "integer"
+		}
+		return "number"
+	case gjson.True, gjson.False:
+		return "boolean"
+	case gjson.Null:
+		return "null"
+	}
+	if value.IsArray() {
+		return "array"
+	}
+	return "object"
+}
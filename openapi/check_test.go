@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Check(t *testing.T) {
+	spec := loadPetstore(t)
+	tests := []struct {
+		name      string
+		operation int
+		status    int
+		body      string
+		want      []Mismatch
+	}{
+		{"matches", 2, 200, `{"id":1,"name":"Rex","tag":null,"status":"sold"}`, []Mismatch{}},
+		{"drift", 2, 200, `{"id":"1","nickname":"R","status":null}`, []Mismatch{
+			{Kind: MissingRequired, Path: "name", Detail: "required field is missing", Count: 1},
+			{Kind: TypeMismatch, Path: "id", Detail: "is string, declared integer", Count: 1},
+			{Kind: UndeclaredField, Path: "nickname", Detail: "string field is not in the schema", Count: 1},
+			{Kind: TypeMismatch, Path: "status", Detail: "is null, declared string (not nullable)", Count: 1},
+		}},
+		{"array elements", 0, 200, `[{"id":1,"name":"a","extra":1},{"id":2.5,"name":"b","extra":2}]`, []Mismatch{
+			{Kind: UndeclaredField, Path: "#.extra", Detail: "integer field is not in the schema", Count: 2},
+			{Kind: TypeMismatch, Path: "#.id", Detail: "is number, declared integer", Count: 1},
+		}},
+		{"wrong top-level type", 0, 200, `{"items":[]}`, []Mismatch{
+			{Kind: TypeMismatch, Detail: "is object, declared array", Count: 1},
+		}},
+		{"undeclared status", 2, 500, `{}`, []Mismatch{
+			{Kind: UndeclaredStatus, Detail: "status 500 is not declared (declared: 200, 404)", Count: 1},
+		}},
+		{"no schema", 2, 404, `{"anything":1}`, nil},
+		{"not JSON", 2, 200, `<html>`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := spec.Check(spec.Operations[tt.operation], tt.status, []byte(tt.body))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Check_VariantsAndAdditionalProperties(t *testing.T) {
+	spec, err := Parse([]byte(`{"openapi": "3.1.0", "paths": {"/things": {"get": {"responses": {"200": {"content": {"application/json": {"schema": {
+		"type": "object",
+		"properties": {"value": {"oneOf": [{"type": "string"}, {"type": "integer"}]}},
+		"additionalProperties": {"type": "boolean"}}}}}}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, key := spec.Check(spec.Operations[0], 200, []byte(`{"value":1.5,"flag":true,"other":"x"}`))
+	want := []Mismatch{
+		{Kind: TypeMismatch, Path: "value", Detail: "number matches none of the 2 declared alternatives", Count: 1},
+		{Kind: TypeMismatch, Path: "other", Detail: "is string, declared boolean", Count: 1},
+	}
+	if key != "200" || !reflect.DeepEqual(got, want) {
+		t.Errorf("Check = %+v, %q", got, key)
+	}
+}
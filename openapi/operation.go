@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Parameter is a path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string
+	In          string // path, query or header
+	Description string
+	Required    bool
+	Schema      Schema
+}
+
+// Parameters returns the operation's path, query and header parameters,
+// including those declared on its path item unless the operation overrides
+// them. Cookie and form parameters are left out; a Swagger 2 body parameter
+// is the RequestBody.
+func (s *Spec) Parameters(operation Operation) []Parameter {
+	var parameters []Parameter
+	for _, list := range []gjson.Result{operation.node.Get("parameters"), operation.item.Get("parameters")} {
+		for _, node := range list.Array() {
+			node = s.resolve(node)
+			parameter := Parameter{
+				Name:        node.Get("name").String(),
+				In:          node.Get("in").String(),
+				Description: node.Get("description").String(),
+				Required:    node.Get("required").Bool() || node.Get("in").String() == "path",
+				Schema:      s.schema(node.Get("schema")),
+			}
+			if parameter.In != "path" && parameter.In != "query" && parameter.In != "header" {
+				continue
+			}
+			if !node.Get("schema").Exists() {
+				parameter.Schema = Schema{node: swaggerParameterSchema(node), spec: s}
+			}
+			declared := false
+			for _, existing := range parameters {
+				declared = declared || existing.Name == parameter.Name && existing.In == parameter.In
+			}
+			if !declared {
+				parameters = append(parameters, parameter)
+			}
+		}
+	}
+	return parameters
+}
+
+// swaggerParameterSchema extracts the schema a Swagger 2 parameter declares
+// inline (type, format, items, enum, ...).
+func swaggerParameterSchema(node gjson.Result) gjson.Result {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(node.Raw), &fields); err != nil {
+		return gjson.Result{}
+	}
+	for _, key := range []string{"name", "in", "required", "description", "collectionFormat", "allowEmptyValue"} {
+		delete(fields, key)
+	}
+	encoded, _ := json.Marshal(fields)
+	return gjson.ParseBytes(encoded)
+}
+
+// RequestBody returns the schema and media type of the operation's JSON
+// request body, and whether the body is required. ok is false when the
+// operation takes no JSON body.
+func (s *Spec) RequestBody(operation Operation) (schema Schema, mediaType string, required, ok bool) {
+	for _, node := range operation.node.Get("parameters").Array() {
+		if node = s.resolve(node); node.Get("in").String() == "body" {
+			return s.schema(node.Get("schema")), "application/json", node.Get("required").Bool(), true
+		}
+	}
+	body := s.resolve(operation.node.Get("requestBody"))
+	body.Get("content").ForEach(func(key, value gjson.Result) bool {
+		if strings.Contains(key.String(), "json") {
+			schema, mediaType, ok = s.schema(value.Get("schema")), key.String(), true
+			return false
+		}
+		return true
+	})
+	return schema, mediaType, body.Get("required").Bool(), ok
+}
+
+// Inline returns the schema as a self-contained JSON schema, for tool inputs:
+// $refs are expanded up to depth levels deep and accept anything below that.
+func (s Schema) Inline(depth int) map[string]any {
+	if !s.Exists() || depth <= 0 {
+		return map[string]any{}
+	}
+	var value map[string]any
+	if err := json.Unmarshal([]byte(s.node.Raw), &value); err != nil {
+		return map[string]any{}
+	}
+	inlined, _ := s.spec.inlineRefs(value, depth).(map[string]any)
+	if inlined == nil {
+		return map[string]any{}
+	}
+	return inlined
+}
+
+func (s *Spec) inlineRefs(value any, depth int) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		if ref, ok := typed["$ref"].(string); ok {
+			return s.schema(s.lookup(ref)).Inline(depth - 1)
+		}
+		for key, child := range typed {
+			typed[key] = s.inlineRefs(child, depth)
+		}
+	case []any:
+		for i, child := range typed {
+			typed[i] = s.inlineRefs(child, depth)
+		}
+	}
+	return value
+}
+
+// ServerURL returns the first absolute server URL (servers in OpenAPI 3,
+// host, basePath and schemes in Swagger 2), or "" when none is declared.
+func (s *Spec) ServerURL() string {
+	root := gjson.ParseBytes(s.document)
+	for _, server := range root.Get("servers").Array() {
+		if serverURL := server.Get("url").String(); strings.HasPrefix(serverURL, "http://") || strings.HasPrefix(serverURL, "https://") {
+			return strings.TrimRight(serverURL, "/")
+		}
+	}
+	if host := root.Get("host").String(); host != "" {
+		scheme := "https"
+		if schemes := root.Get("schemes").Array(); len(schemes) > 0 {
+			scheme = schemes[0].String()
+		}
+		return scheme + "://" + host + strings.TrimRight(root.Get("basePath").String(), "/")
+	}
+	return ""
+}
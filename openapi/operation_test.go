@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const swaggerJSON = `{"swagger": "2.0", "host": "api.example.com", "basePath": "/v2/", "schemes": ["http"],
+	"paths": {"/items/{id}": {
+		"parameters": [{"name": "id", "in": "path", "type": "integer"}, {"name": "trace", "in": "header", "type": "string"}],
+		"put": {"operationId": "putItem", "parameters": [
+			{"name": "trace", "in": "header", "type": "boolean", "required": true},
+			{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "csv"},
+			{"name": "session", "in": "cookie", "type": "string"},
+			{"name": "item", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Item"}}]}}},
+	"definitions": {"Item": {"type": "object", "properties": {"name": {"type": "string"}, "child": {"$ref": "#/definitions/Item"}}}}}`
+
+func Test_Parameters(t *testing.T) {
+	spec, err := Parse([]byte(swaggerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parameters := spec.Parameters(spec.Operations[0])
+	var got []string
+	for _, parameter := range parameters {
+		schema, _ := json.Marshal(parameter.Schema.Inline(2))
+		got = append(got, parameter.In+" "+parameter.Name+" "+string(schema)+" "+map[bool]string{true: "required", false: "optional"}[parameter.Required])
+	}
+	want := []string{
+		`header trace {"type":"boolean"} required`,
+		`query tags {"items":{"type":"string"},"type":"array"} optional`,
+		`path id {"type":"integer"} required`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parameters = %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parameter %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_RequestBody(t *testing.T) {
+	swagger, err := Parse([]byte(swaggerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, mediaType, required, ok := swagger.RequestBody(swagger.Operations[0])
+	if !ok || !required || mediaType != "application/json" || schema.Name != "Item" {
+		t.Errorf("Swagger 2 body = %q, %q, %v, %v", schema.Name, mediaType, required, ok)
+	}
+
+	spec := loadPetstore(t)
+	if _, _, _, ok := spec.RequestBody(spec.Operations[0]); ok {
+		t.Error("listPets has a request body")
+	}
+	withBody, err := Parse([]byte(`{"openapi": "3.0.0", "paths": {"/pets": {"post": {"requestBody": {"content": {
+		"text/plain": {"schema": {"type": "string"}}, "application/merge-patch+json": {"schema": {"type": "object"}}}}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, mediaType, required, ok = withBody.RequestBody(withBody.Operations[0])
+	if !ok || required || mediaType != "application/merge-patch+json" || schema.Type() != "object" {
+		t.Errorf("OpenAPI 3 body = %q, %q, %v, %v", schema.Type(), mediaType, required, ok)
+	}
+}
+
+func Test_Schema_Inline(t *testing.T) {
+	spec, err := Parse([]byte(swaggerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _, _, _ := spec.RequestBody(spec.Operations[0])
+	inlined, _ := json.Marshal(schema.Inline(2))
+	want := `{"properties":{"child":{"properties":{"child":{},"name":{"type":"string"}},"type":"object"},"name":{"type":"string"}},"type":"object"}`
+	if string(inlined) != want {
+		t.Errorf("Inline = %s, want %s", inlined, want)
+	}
+}
+
+func Test_ServerURL(t *testing.T) {
+	swagger, err := Parse([]byte(swaggerJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := swagger.ServerURL(); got != "http://api.example.com/v2" {
+		t.Errorf("Swagger 2 ServerURL = %q", got)
+	}
+	if got := loadPetstore(t).ServerURL(); got != "https://api.example.com/v1" {
+		t.Errorf("OpenAPI 3 ServerURL = %q", got)
+	}
+	relative, err := Parse([]byte(`{"openapi": "3.0.0", "servers": [{"url": "/api"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := relative.ServerURL(); got != "" {
+		t.Errorf("relative ServerURL = %q", got)
+	}
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxRefDepth bounds $ref chains, which may be cyclic.
+const maxRefDepth = 32
+
+// Schema is a JSON schema within a Spec, with $refs resolved. The zero Schema
+// declares nothing.
+type Schema struct {
+	Name string // component name when it was a $ref, e.g. Pet
+
+	node gjson.Result
+	spec *Spec
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema Schema
+}
+
+// schema resolves node's $ref chain into a Schema.
+func (s *Spec) schema(node gjson.Result) Schema {
+	var name string
+	for depth := 0; depth < maxRefDepth && node.Get("$ref").Exists(); depth++ {
+		ref := node.Get("$ref").String()
+		name = ref[strings.LastIndex(ref, "/")+1:]
+		node = s.lookup(ref)
+	}
+	return Schema{Name: name, node: node, spec: s}
+}
+
+// resolve follows the $ref chain of a non-schema object, e.g. a response.
+func (s *Spec) resolve(node gjson.Result) gjson.Result {
+	for depth := 0; depth < maxRefDepth && node.Get("$ref").Exists(); depth++ {
+		node = s.lookup(node.Get("$ref").String())
+	}
+	return node
+}
+
+// lookup finds a local JSON pointer reference such as
+// #/components/schemas/Pet; references to other documents resolve to nothing.
+func (s *Spec) lookup(ref string) gjson.Result {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return gjson.Result{}
+	}
+	parts := strings.Split(pointer, "/")
+	for i, part := range parts {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		parts[i] = gjson.Escape(part)
+	}
+	return gjson.GetBytes(s.document, strings.Join(parts, "."))
+}
+
+// Exists reports whether the schema declares anything.
+func (s Schema) Exists() bool {
+	return s.node.Exists() && s.node.IsObject()
+}
+
+// Type returns the declared JSON type (object, array, string, integer,
+// number, boolean), inferred from properties or items when unset; empty
+// when the schema accepts any type. OpenAPI 3.1 type lists yield their first
+// non-null type.
+func (s Schema) Type() string {
+	typeNode := s.node.Get("type")
+	if typeNode.IsArray() {
+		for _, candidate := range typeNode.Array() {
+			if candidate.String() != "null" {
+				return candidate.String()
+			}
+		}
+	}
+	if typeNode.Exists() && !typeNode.IsArray() {
+		return typeNode.String()
+	}
+	switch {
+	case s.node.Get("properties").Exists() || s.node.Get("allOf").Exists():
+		return "object"
+	case s.node.Get("items").Exists():
+		return "array"
+	}
+	return ""
+}
+
+// Items returns the schema of an array's elements.
+func (s Schema) Items() Schema {
+	return s.spec.schema(s.node.Get("items"))
+}
+
+// Properties returns the declared properties in declaration order, including
+// those of allOf parts.
+func (s Schema) Properties() []Property {
+	var properties []Property
+	for _, part := range s.allOf() {
+		part.node.Get("properties").ForEach(func(name, value gjson.Result) bool {
+			properties = append(properties, Property{Name: name.String(), Schema: s.spec.schema(value)})
+			return true
+		})
+	}
+	return properties
+}
+
+// Required returns the required property names, including those of allOf parts.
+func (s Schema) Required() []string {
+	var required []string
+	for _, part := range s.allOf() {
+		for _, name := range part.node.Get("required").Array() {
+			required = append(required, name.String())
+		}
+	}
+	return required
+}
+
+// allOf returns the schema itself followed by its allOf parts, recursively.
+func (s Schema) allOf() []Schema {
+	parts := []Schema{s}
+	for _, part := range s.node.Get("allOf").Array() {
+		parts = append(parts, s.spec.schema(part).allOf()...)
+	}
+	return parts
+}
+
+// Nullable reports whether null is allowed: nullable: true (OpenAPI 3.0),
+// x-nullable (Swagger 2) or a type list with null (OpenAPI 3.1).
+func (s Schema) Nullable() bool {
+	if s.node.Get("nullable").Bool() || s.node.Get("x-nullable").Bool() {
+		return true
+	}
+	for _, candidate := range s.node.Get("type").Array() {
+		if candidate.String() == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// Variants returns the oneOf and anyOf alternatives; a value must match one.
+func (s Schema) Variants() []Schema {
+	var variants []Schema
+	for _, key := range []string{"oneOf", "anyOf"} {
+		for _, variant := range s.node.Get(key).Array() {
+			variants = append(variants, s.spec.schema(variant))
+		}
+	}
+	return variants
+}
+
+// AdditionalProperties returns the schema of properties not declared in
+// properties, and whether the schema allows them explicitly
+// (additionalProperties: true or a schema).
+func (s Schema) AdditionalProperties() (Schema, bool) {
+	for _, part := range s.allOf() {
+		additional := part.node.Get("additionalProperties")
+		if additional.IsObject() {
+			return s.spec.schema(additional), true
+		}
+		if additional.Type == gjson.True {
+			return Schema{}, true
+		}
+	}
+	return Schema{}, false
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_Schema_RefsAndAllOf(t *testing.T) {
+	spec := loadPetstore(t)
+	schema, _, _ := spec.ResponseSchema(spec.Operations[2], 200)
+	if schema.Name != "Pet" || schema.Type() != "object" {
+		t.Fatalf("schema = %q of type %q", schema.Name, schema.Type())
+	}
+	var names, types []string
+	for _, property := range schema.Properties() {
+		names = append(names, property.Name)
+		types = append(types, property.Schema.Type())
+	}
+	if !slices.Equal(names, []string{"name", "tag", "id", "status"}) || !slices.Equal(types, []string{"string", "string", "integer", "string"}) {
+		t.Errorf("properties = %q of types %q", names, types)
+	}
+	if required := schema.Required(); !slices.Equal(required, []string{"name", "id"}) {
+		t.Errorf("required = %q", required)
+	}
+}
+
+func Test_Schema_Items(t *testing.T) {
+	spec := loadPetstore(t)
+	schema, _, _ := spec.ResponseSchema(spec.Operations[0], 200)
+	if items := schema.Items(); items.Name != "Pet" || len(items.Properties()) != 4 {
+		t.Errorf("items = %q with %d properties", items.Name, len(items.Properties()))
+	}
+}
+
+func Test_Schema_CyclicRef(t *testing.T) {
+	spec, err := Parse([]byte(`{"openapi": "3.0.0", "components": {"schemas": {"A": {"$ref": "#/components/schemas/B"}, "B": {"$ref": "#/components/schemas/A"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := spec.schema(spec.lookup("#/components/schemas/A"))
+	if schema.Type() != "" || len(schema.Properties()) != 0 {
+		t.Errorf("cyclic schema = %+v", schema)
+	}
+}
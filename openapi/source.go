@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source is a description loaded from a file or URL that can be refreshed
+// while the server runs. A remote description is cached on disk with its
+// validators (ETag, Last-Modified): later starts use the cached copy at once
+// and revalidate it in the background. Safe for concurrent use.
+type Source struct {
+	Location string // file path or http(s) URL
+	cacheDir string
+
+	mu           sync.Mutex
+	spec         *Spec
+	etag         string
+	lastModified string
+	stale        bool // served from the cache, not yet revalidated
+}
+
+// cacheEntry is the on-disk form of a cached remote description.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+	Body         string    `json:"body"`
+}
+
+// Open loads the description at location: a file, or an http(s) URL cached
+// in cacheDir (no caching when empty). A cached copy is used without a
+// download; Stale reports it until Refresh revalidates it.
+func Open(ctx context.Context, location, cacheDir string) (*Source, error) {
+	source := &Source{Location: location, cacheDir: cacheDir}
+	if source.remote() && cacheDir != "" {
+		if entry, err := source.readCache(); err == nil {
+			if spec, err := Parse([]byte(entry.Body)); err == nil {
+				source.spec, source.etag, source.lastModified, source.stale = spec, entry.ETag, entry.LastModified, true
+				return source, nil
+			}
+		}
+	}
+	if _, err := source.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// Spec returns the current description.
+func (s *Source) Spec() *Spec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spec
+}
+
+// Stale reports whether the description came from the disk cache and has not
+// been revalidated since.
+func (s *Source) Stale() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stale
+}
+
+// Refresh reloads the description: a file is read again, a URL is fetched
+// conditionally so an unchanged description costs a 304. Reports whether the
+// description changed; on error the current one stays in use.
+func (s *Source) Refresh(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data []byte
+	var etag, lastModified string
+	var err error
+	if !s.remote() {
+		data, err = os.ReadFile(s.Location)
+	} else {
+		data, etag, lastModified, err = s.fetch(ctx)
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading OpenAPI description: %w", err)
+	}
+	s.stale = false
+	if data == nil { // not modified
+		return false, nil
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", s.Location, err)
+	}
+	changed := s.spec == nil || !bytes.Equal(s.spec.document, spec.document)
+	s.spec, s.etag, s.lastModified = spec, etag, lastModified
+	if s.remote() && s.cacheDir != "" {
+		if err := s.writeCache(data); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func (s *Source) remote() bool {
+	return strings.HasPrefix(s.Location, "http://") || strings.HasPrefix(s.Location, "https://")
+}
+
+// fetch downloads the description with its validators, or returns nil data
+// when the server answers the conditional request with 304 Not Modified.
+func (s *Source) fetch(ctx context.Context) (data []byte, etag, lastModified string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Location, nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	request.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.5")
+	if s.spec != nil && s.etag != "" {
+		request.Header.Set("If-None-Match", s.etag)
+	}
+	if s.spec != nil && s.lastModified != "" {
+		request.Header.Set("If-Modified-Since", s.lastModified)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotModified && s.spec != nil {
+		return nil, "", "", nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("GET %s: %s", s.Location, response.Status)
+	}
+	data, err = readLimited(response.Body)
+	return data, response.Header.Get("ETag"), response.Header.Get("Last-Modified"), err
+}
+
+func (s *Source) cachePath() string {
+	sum := sha256.Sum256([]byte(s.Location))
+	return filepath.Join(s.cacheDir, "openapi-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func (s *Source) readCache() (cacheEntry, error) {
+	var entry cacheEntry
+	data, err := os.ReadFile(s.cachePath())
+	if err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, err
+	}
+	if entry.URL != s.Location {
+		return entry, fmt.Errorf("cache entry is for %s", entry.URL)
+	}
+	return entry, nil
+}
+
+// writeCache replaces the cache entry atomically, so a concurrent start never
+// reads half of it.
+func (s *Source) writeCache(body []byte) error {
+	entry, err := json.Marshal(cacheEntry{URL: s.Location, ETag: s.etag, LastModified: s.lastModified, Fetched: time.Now().UTC(), Body: string(body)})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.cacheDir, 0o700); err != nil {
+		return fmt.Errorf("caching OpenAPI description: %w", err)
+	}
+	temporary, err := os.CreateTemp(s.cacheDir, "openapi-*.tmp")
+	if err != nil {
+		return fmt.Errorf("caching OpenAPI description: %w", err)
+	}
+	defer os.Remove(temporary.Name())
+	if _, err := temporary.Write(entry); err != nil {
+		temporary.Close()
+		return fmt.Errorf("caching OpenAPI description: %w", err)
+	}
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("caching OpenAPI description: %w", err)
+	}
+	if err := os.Rename(temporary.Name(), s.cachePath()); err != nil {
+		return fmt.Errorf("caching OpenAPI description: %w", err)
+	}
+	return nil
+}
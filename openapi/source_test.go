@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Open_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(petstoreYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := Open(context.Background(), path, "")
+	if err != nil || len(source.Spec().Operations) != 4 || source.Stale() {
+		t.Fatalf("Open = %v, %v", source, err)
+	}
+	if changed, err := source.Refresh(context.Background()); changed || err != nil {
+		t.Errorf("Refresh of an unchanged file = %v, %v", changed, err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(petstoreYAML, "operationId: getPet", "operationId: fetchPet", 1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := source.Refresh(context.Background()); !changed || err != nil || source.Spec().Operations[2].ID != "fetchPet" {
+		t.Errorf("Refresh of a changed file = %v, %v", changed, err)
+	}
+	if _, err := Open(context.Background(), filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Error("Open of a missing file succeeded")
+	}
+}
+
+func Test_Open_CachesRemoteDescription(t *testing.T) {
+	var document atomic.Value
+	document.Store(petstoreYAML)
+	var requests, revalidations atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		etag := fmt.Sprintf(`"%d"`, len(document.Load().(string)))
+		if r.Header.Get("If-None-Match") == etag {
+			revalidations.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(document.Load().(string)))
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+
+	first, err := Open(context.Background(), server.URL+"/openapi.yaml", cacheDir)
+	if err != nil || first.Stale() || requests.Load() != 1 {
+		t.Fatalf("first Open = %v, stale %v, %d requests", err, first.Stale(), requests.Load())
+	}
+
+	second, err := Open(context.Background(), server.URL+"/openapi.yaml", cacheDir)
+	if err != nil || !second.Stale() || requests.Load() != 1 || len(second.Spec().Operations) != 4 {
+		t.Fatalf("cached Open = %v, stale %v, %d requests", err, second.Stale(), requests.Load())
+	}
+	if changed, err := second.Refresh(context.Background()); changed || err != nil || revalidations.Load() != 1 || second.Stale() {
+		t.Errorf("revalidation = %v, %v, %d revalidations", changed, err, revalidations.Load())
+	}
+
+	document.Store(petstoreYAML + "    Extra:\n      type: string\n")
+	if changed, err := second.Refresh(context.Background()); !changed || err != nil {
+		t.Errorf("Refresh after a change = %v, %v", changed, err)
+	}
+	third, err := Open(context.Background(), server.URL+"/openapi.yaml", cacheDir)
+	if err != nil || !strings.Contains(string(third.Spec().document), "Extra") {
+		t.Errorf("the cache was not updated: %v", err)
+	}
+}
+
+func Test_Open_RemoteErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/html" {
+			w.Write([]byte("<html>not a spec</html>"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	for _, path := range []string{"/missing", "/html"} {
+		if _, err := Open(context.Background(), server.URL+path, t.TempDir()); err == nil {
+			t.Errorf("Open(%s) succeeded", path)
+		}
+	}
+}
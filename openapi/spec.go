@@ -0,0 +1,198 @@
+// Package openapi loads OpenAPI 3 and Swagger 2 documents and matches
+// requests to their operations and response schemas. It also reads the event
+// channels of AsyncAPI documents.
+package openapi
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	loadTimeout  = 30 * time.Second
+	maxSpecBytes = 20 * 1024 * 1024
+)
+
+// methods are the operation keys of a path item, in the order operations
+// are listed.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is a parsed API description. It is immutable after Parse.
+type Spec struct {
+	Title      string
+	Version    string // of the API, from info.version
+	Operations []Operation
+
+	document []byte // the description as JSON
+}
+
+// Operation is one method on one path of a Spec.
+type Operation struct {
+	Method  string // upper case, e.g. GET
+	Path    string // template, e.g. /users/{id}
+	ID      string // operationId; may be empty
+	Summary string
+	Tags    []string
+
+	node     gjson.Result
+	item     gjson.Result // the path item, for parameters shared by its operations
+	segments []string
+}
+
+// Name identifies the operation in notes: its operationId, or method and path.
+func (o Operation) Name() string {
+	if o.ID != "" {
+		return o.ID
+	}
+	return o.Method + " " + o.Path
+}
+
+// readLimited reads a downloaded description, refusing huge ones.
+func readLimited(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxSpecBytes+1))
+	if err == nil && len(data) > maxSpecBytes {
+		err = fmt.Errorf("larger than %d bytes", maxSpecBytes)
+	}
+	return data, err
+}
+
+// Parse reads an OpenAPI 3 or Swagger 2 description in JSON or YAML.
+func Parse(data []byte) (*Spec, error) {
+	document := data
+	if !gjson.ValidBytes(data) {
+		converted, err := yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		document = converted
+	}
+	root := gjson.ParseBytes(document)
+	if !root.Get("openapi").Exists() && !root.Get("swagger").Exists() {
+		return nil, fmt.Errorf("not an OpenAPI or Swagger description (no openapi or swagger field)")
+	}
+	spec := &Spec{Title: root.Get("info.title").String(), Version: root.Get("info.version").String(), document: document}
+	root.Get("paths").ForEach(func(path, item gjson.Result) bool {
+		for _, method := range methods {
+			node := item.Get(method)
+			if !node.Exists() {
+				continue
+			}
+			operation := Operation{
+				Method:   strings.ToUpper(method),
+				Path:     path.String(),
+				ID:       node.Get("operationId").String(),
+				Summary:  node.Get("summary").String(),
+				node:     node,
+				item:     item,
+				segments: splitPath(path.String()),
+			}
+			for _, tag := range node.Get("tags").Array() {
+				operation.Tags = append(operation.Tags, tag.String())
+			}
+			spec.Operations = append(spec.Operations, operation)
+		}
+		return true
+	})
+	return spec, nil
+}
+
+// Document returns the description as JSON, converted from YAML if need be.
+func (s *Spec) Document() []byte {
+	return s.document
+}
+
+// Find returns the operation a request matches: the path template matching
+// the end of the URL path (so server base paths need no configuration), with
+// the most literal segments when several do.
+func (s *Spec) Find(method, requestURL string) (Operation, bool) {
+	path := requestURL
+	if parsed, err := url.Parse(requestURL); err == nil {
+		path = parsed.Path
+	}
+	segments := splitPath(path)
+	best, bestScore := -1, -1
+	for i, operation := range s.Operations {
+		if operation.Method != method || len(operation.segments) > len(segments) {
+			continue
+		}
+		score, tail := 0, segments[len(segments)-len(operation.segments):]
+		for j, segment := range operation.segments {
+			if isParameter(segment) {
+				continue
+			}
+			if segment != tail[j] {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > bestScore || score == bestScore && best >= 0 && len(operation.segments) > len(s.Operations[best].segments) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return Operation{}, false
+	}
+	return s.Operations[best], true
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// isParameter reports whether a template segment is a path parameter; a
+// segment mixing literal text and a parameter ({id}.json) matches anything too.
+func isParameter(segment string) bool {
+	return strings.Contains(segment, "{")
+}
+
+// ResponseSchema returns the schema of the operation's response with status,
+// the response key it came from (e.g. 200, 2XX or default), and whether the
+// status is declared at all. The schema is empty when the response declares
+// no JSON body.
+func (s *Spec) ResponseSchema(operation Operation, status int) (Schema, string, bool) {
+	responses := operation.node.Get("responses")
+	code := fmt.Sprint(status)
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		response := responses.Get(gjson.Escape(key))
+		if !response.Exists() {
+			continue
+		}
+		response = s.resolve(response)
+		if schema := response.Get("schema"); schema.Exists() {
+			return s.schema(schema), key, true
+		}
+		content := response.Get("content")
+		var node gjson.Result
+		content.ForEach(func(mediaType, value gjson.Result) bool {
+			if strings.Contains(mediaType.String(), "json") || mediaType.String() == "*/*" {
+				node = value.Get("schema")
+				return false
+			}
+			return true
+		})
+		return s.schema(node), key, true
+	}
+	return Schema{}, "", false
+}
+
+// DeclaredStatuses lists the response keys of the operation, as declared.
+func (s *Spec) DeclaredStatuses(operation Operation) []string {
+	var keys []string
+	operation.node.Get("responses").ForEach(func(key, _ gjson.Result) bool {
+		keys = append(keys, key.String())
+		return true
+	})
+	return keys
+}
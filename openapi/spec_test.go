@@ -0,0 +1,173 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+)
+
+const petstoreYAML = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: A page of pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        default:
+          $ref: '#/components/responses/Error'
+    post:
+      operationId: createPet
+      tags: [pets]
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+  /pets/{id}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: One pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "404":
+          description: Not found
+  /pets/mine:
+    get:
+      responses:
+        2XX:
+          description: My pets
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/PetPage'
+components:
+  responses:
+    Error:
+      description: Error
+      content:
+        application/problem+json:
+          schema:
+            type: object
+            properties:
+              title: {type: string}
+  schemas:
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          required: [id]
+          properties:
+            id: {type: integer}
+            status: {type: string}
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: [string, "null"]}
+    PetPage:
+      type: object
+      properties:
+        data:
+          type: array
+          items: {$ref: '#/components/schemas/Pet'}
+        next: {type: string}
+`
+
+func loadPetstore(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Parse([]byte(petstoreYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func Test_Parse_Operations(t *testing.T) {
+	spec := loadPetstore(t)
+	if spec.Title != "Pet Store" || spec.Version != "1.2.0" {
+		t.Errorf("title, version = %q, %q", spec.Title, spec.Version)
+	}
+	var names []string
+	for _, operation := range spec.Operations {
+		names = append(names, operation.Name())
+	}
+	want := []string{"listPets", "createPet", "getPet", "GET /pets/mine"}
+	if !slices.Equal(names, want) {
+		t.Errorf("operations = %q, want %q", names, want)
+	}
+	if !slices.Equal(spec.Operations[0].Tags, []string{"pets"}) {
+		t.Errorf("tags = %q", spec.Operations[0].Tags)
+	}
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	for _, document := range []string{`{"info": {}}`, "a: [unclosed"} {
+		if _, err := Parse([]byte(document)); err == nil {
+			t.Errorf("Parse(%q) succeeded", document)
+		}
+	}
+}
+
+func Test_Find(t *testing.T) {
+	spec := loadPetstore(t)
+	tests := []struct {
+		method, url, want string
+	}{
+		{"GET", "https://api.example.com/v1/pets", "listPets"},
+		{"GET", "https://api.example.com/v1/pets?limit=5", "listPets"},
+		{"POST", "https://api.example.com/v1/pets", "createPet"},
+		{"GET", "https://api.example.com/v1/pets/42", "getPet"},
+		{"GET", "https://api.example.com/v1/pets/mine", "GET /pets/mine"},
+		{"DELETE", "https://api.example.com/v1/pets/42", ""},
+		{"GET", "https://api.example.com/v1/owners", ""},
+	}
+	for _, tt := range tests {
+		operation, ok := spec.Find(tt.method, tt.url)
+		if got := operation.Name(); ok && got != tt.want || !ok && tt.want != "" {
+			t.Errorf("Find(%s %s) = %q, %v; want %q", tt.method, tt.url, got, ok, tt.want)
+		}
+	}
+}
+
+func Test_ResponseSchema(t *testing.T) {
+	spec := loadPetstore(t)
+	tests := []struct {
+		operation int
+		status    int
+		key       string
+		declared  bool
+		schema    string
+	}{
+		{0, 200, "200", true, "array"},
+		{0, 500, "default", true, "object"},
+		{1, 201, "201", true, "object"},
+		{2, 404, "404", true, ""},
+		{2, 500, "", false, ""},
+		{3, 204, "2XX", true, "object"},
+	}
+	for _, tt := range tests {
+		schema, key, declared := spec.ResponseSchema(spec.Operations[tt.operation], tt.status)
+		if key != tt.key || declared != tt.declared || schema.Type() != tt.schema {
+			t.Errorf("ResponseSchema(%s, %d) = %q, %q, %v", spec.Operations[tt.operation].Name(), tt.status, schema.Type(), key, declared)
+		}
+	}
+}
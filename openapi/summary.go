@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxSummaryFields caps the fields a summary lists.
+const maxSummaryFields = 12
+
+// Summarize describes a response to operation in one line from its declared
+// schema, e.g. "200 OK; 37 items of Pet; fields: id, name, status". Counts
+// come from body when it is complete JSON; a truncated body still gets the
+// declared structure.
+func (s *Spec) Summarize(operation Operation, status int, statusText string, body []byte) string {
+	summary := strings.TrimSpace(fmt.Sprintf("%d %s", status, statusText))
+	schema, _, declared := s.ResponseSchema(operation, status)
+	if !declared {
+		return fmt.Sprintf("%s; not a declared response (declared: %s)", summary, strings.Join(s.DeclaredStatuses(operation), ", "))
+	}
+	if !schema.Exists() {
+		return summary + "; no JSON body declared"
+	}
+	var value gjson.Result
+	if gjson.ValidBytes(body) {
+		value = gjson.ParseBytes(body)
+	}
+
+	switch schema.Type() {
+	case "array":
+		items := schema.Items()
+		shape := "array"
+		if value.IsArray() {
+			shape = fmt.Sprintf("%d items", len(value.Array()))
+		}
+		if items.Name != "" {
+			shape += " of " + items.Name
+		}
+		summary += "; " + shape
+		if fields := listFields(items.Properties(), gjson.Result{}); fields != "" {
+			summary += "; fields: " + fields
+		}
+	case "object", "":
+		if schema.Name != "" {
+			summary += "; " + schema.Name
+		}
+		if fields := listFields(schema.Properties(), value); fields != "" {
+			summary += "; fields: " + fields
+		}
+	default:
+		summary += "; " + schema.Type()
+	}
+	return summary
+}
+
+// listFields names the properties, with the item count of arrays present in
+// value, e.g. "data (37 items), next, total".
+func listFields(properties []Property, value gjson.Result) string {
+	var names []string
+	for _, property := range properties {
+		name := property.Name
+		if field := value.Get(gjson.Escape(property.Name)); field.IsArray() {
+			name += fmt.Sprintf(" (%d items)", len(field.Array()))
+		}
+		names = append(names, name)
+	}
+	if len(names) > maxSummaryFields {
+		names = append(names[:maxSummaryFields], fmt.Sprintf("+%d more", len(names)-maxSummaryFields))
+	}
+	return strings.Join(names, ", ")
+}
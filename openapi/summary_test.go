@@ -0,0 +1,30 @@
+package openapi
+
+import "testing"
+
+func Test_Summarize(t *testing.T) {
+	spec := loadPetstore(t)
+	tests := []struct {
+		name      string
+		operation int
+		status    int
+		body      string
+		want      string
+	}{
+		{"array", 0, 200, `[{"id":1},{"id":2},{"id":3}]`, "200 OK; 3 items of Pet; fields: name, tag, id, status"},
+		{"truncated array", 0, 200, `[{"id":1},{"id`, "200 OK; array of Pet; fields: name, tag, id, status"},
+		{"object with array", 3, 200, `{"data":[{"id":1},{"id":2}],"next":"abc"}`, "200 OK; PetPage; fields: data (2 items), next"},
+		{"inline default", 0, 500, `{"title":"boom"}`, "500 Internal Server Error; fields: title"},
+		{"no body", 2, 404, ``, "404 Not Found; no JSON body declared"},
+		{"undeclared", 2, 500, ``, "500 Internal Server Error; not a declared response (declared: 200, 404)"},
+	}
+	statusTexts := map[int]string{200: "OK", 404: "Not Found", 500: "Internal Server Error"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spec.Summarize(spec.Operations[tt.operation], tt.status, statusTexts[tt.status], []byte(tt.body))
+			if got != tt.want {
+				t.Errorf("Summarize = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package openapi
+
+import "testing"
+
+func Test_YAMLToJSON(t *testing.T) {
+	document := "b: 1\na:\n  - true\n  - null\n  - x\n200: &anchor {c: 1.5}\nalias: *anchor\n"
+	got, err := yamlToJSON([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"b":1,"a":[true,null,"x"],"200":{"c":1.5},"alias":{"c":1.5}}`
+	if string(got) != want {
+		t.Errorf("yamlToJSON = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+	"github.com/lexandro/rest-api-mcp/tools"
+)
+
+// defineOpenAPIFlags defines the --openapi* and --asyncapi flags.
+func (o *options) defineOpenAPIFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.openAPI, "openapi", "", "OpenAPI/Swagger description (file or URL, JSON or YAML) whose response schemas summarize matching responses")
+	fs.StringVar(&o.openAPICacheDir, "openapi-cache-dir", "", "Cache a remote --openapi description here and revalidate it in the background (default: --cache-dir)")
+	fs.BoolVar(&o.openAPITools, "openapi-tools", false, "Register one tool per --openapi operation, named by its operationId")
+	fs.StringVar(&o.openAPIIncludeTags, "openapi-include-tags", "", "Comma-separated tags; --openapi-tools registers only operations with one of them")
+	fs.StringVar(&o.openAPIExcludeOps, "openapi-exclude-ops", "", "Comma-separated operationIds or \"METHOD /path\" patterns --openapi-tools leaves out (\"*\" within a segment, \"**\" across)")
+	fs.IntVar(&o.openAPIMaxTools, "openapi-max-tools", 40, "Above this many operations, --openapi-tools registers one tool per tag instead (0 = no cap)")
+	fs.StringVar(&o.asyncAPI, "asyncapi", "", "AsyncAPI description (file or URL, JSON or YAML); registers one subscribe tool per SSE or WebSocket channel")
+}
+
+// openAPISource loads --openapi, or returns nil without it. A remote
+// description is cached in --openapi-cache-dir, or --cache-dir.
+func (o *options) openAPISource() (*openapi.Source, error) {
+	if o.openAPI == "" {
+		return nil, nil
+	}
+	cacheDir := o.openAPICacheDir
+	if cacheDir == "" {
+		cacheDir = o.cacheDir
+	}
+	source, err := openapi.Open(context.Background(), o.openAPI, cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --openapi: %w", err)
+	}
+	return source, nil
+}
+
+// asyncAPISpec loads --asyncapi, or returns nil without it.
+func (o *options) asyncAPISpec() (*openapi.AsyncSpec, error) {
+	if o.asyncAPI == "" {
+		return nil, nil
+	}
+	spec, err := openapi.LoadAsyncAPI(context.Background(), o.asyncAPI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --asyncapi: %w", err)
+	}
+	return spec, nil
+}
+
+// openAPIFilter selects the operations --openapi-tools registers.
+func (o *options) openAPIFilter() tools.OperationFilter {
+	return tools.OperationFilter{
+		IncludeTags: splitList(o.openAPIIncludeTags),
+		ExcludeOps:  splitList(o.openAPIExcludeOps),
+		MaxTools:    o.openAPIMaxTools,
+	}
+}
+
+// openAPIProblems checks the --openapi-* flags that only apply with --openapi
+// and --openapi-tools.
+func (o *options) openAPIProblems() []string {
+	var problems []string
+	if o.openAPIMaxTools < 0 {
+		problems = append(problems, "--openapi-max-tools must not be negative")
+	}
+	if o.openAPITools && o.openAPI == "" {
+		problems = append(problems, "--openapi-tools has no effect without --openapi")
+	}
+	if !o.openAPITools && (o.openAPIIncludeTags != "" || o.openAPIExcludeOps != "") {
+		problems = append(problems, "--openapi-include-tags and --openapi-exclude-ops have no effect without --openapi-tools")
+	}
+	return problems
+}
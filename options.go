@@ -0,0 +1,296 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/config"
+)
+
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string { return strings.Join(*f, ", ") }
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+func (f *repeatedFlag) Get() any { return []string(*f) }
+
+// options holds every command-line flag. The flags are defined on a FlagSet
+// passed in, so they can be resolved again for each configuration profile.
+type options struct {
+	configFile         string
+	profile            string
+	allowProfileSwitch bool
+
+	baseURL          string
+	defaultHeaders   repeatedFlag
+	defaultQuery     repeatedFlag
+	timeout          time.Duration
+	maxResponseSize  int64
+	proxy            string
+	noProxy          string
+	proxyFromEnv     bool
+	envProxy         string // HTTPS_PROXY or HTTP_PROXY from the environment, for reports
+	retry            int
+	retryDelay       time.Duration
+	insecure         bool
+	cookieJar        bool
+	blockPrivate     bool
+	recordDir        string
+	replayDir        string
+	replayMatch      string
+	chaosLatency     time.Duration
+	chaosErrorRate   float64
+	chaosStatus      int
+	chaosHosts       string
+	idempotencyKey   string
+	spillThreshold   int64
+	spillDir         string
+	dnsCacheTTL      time.Duration
+	dnsCacheSize     int
+	maxConcurrent    int
+	maxBandwidth     int64
+	paceRateLimits   bool
+	rateLimitReserve int
+	maxPaceWait      time.Duration
+	coalesceWindow   time.Duration
+	httpVersion      string
+	noExpect         bool
+	strictURLs       bool
+	cacheDir         string
+	cacheTTL         time.Duration
+
+	oauthTokenURL     string
+	oauthClientID     string
+	oauthClientSecret string
+	oauthRefreshToken string
+	oauthScopes       string
+	oauthTokenStore   string
+	oauthStoreKey     string
+	oauthDeviceURL    string
+
+	s3Endpoint        string
+	s3Region          string
+	s3AccessKeyID     string
+	s3SecretAccessKey string
+	s3SessionToken    string
+	s3PathStyle       bool
+
+	azureTenantID         string
+	azureClientID         string
+	azureClientSecret     string
+	azureManagedIdentity  bool
+	azureAuthorityHost    string
+	azureResources        repeatedFlag
+	azureIdentityEndpoint string // IDENTITY_ENDPOINT and IDENTITY_HEADER, set on App Service
+	azureIdentityHeader   string
+
+	googleCredentials string
+	googleADC         bool
+	googleScopes      repeatedFlag
+
+	followRedirects        bool
+	includeResponseHeaders bool
+	includeTiming          bool
+	tokenBudget            int
+	decodeBinaryBodies     bool
+	faultInjection         bool
+	toolsets               string
+	readOnly               bool
+	allowMethods           string
+	denyMethods            string
+	policyFile             string
+	allowedRequestHeaders  string
+	maxRequestSize         int64
+	maxRequestHeaders      int
+	maxHeaderSize          int
+	redactResponseHeaders  string
+	showResponseSecrets    bool
+	requestIDHeader        string
+	templateEnv            string
+	templateEnvValues      map[string]string // the --template-env variables' values from the environment
+	protoDescriptors       string
+	openAPI                string
+	openAPICacheDir        string
+	openAPITools           bool
+	openAPIIncludeTags     string
+	openAPIExcludeOps      string
+	openAPIMaxTools        int
+	asyncAPI               string
+	dryRun                 bool
+	defaultFormat          string
+
+	transport      string
+	listenAddr     string
+	authToken      string
+	allowedOrigins string
+	tlsCertFile    string
+	tlsKeyFile     string
+
+	hostRules []client.HostRule // from the config file's hosts section
+	flags     *flag.FlagSet     // the resolved flag set, for config print
+}
+
+func defineFlags(fs *flag.FlagSet) *options {
+	o := &options{}
+	fs.StringVar(&o.configFile, "config", "", "Configuration file (.json, .yaml or .toml) with flag names as keys; command-line flags override it. Every flag can also be set as REST_API_MCP_<FLAG_NAME>")
+	fs.StringVar(&o.profile, "profile", "", "Configuration file profile to start with (e.g. dev, staging, prod)")
+	fs.BoolVar(&o.allowProfileSwitch, "allow-profile-switch", false, "Register the use_profile tool so the agent can switch profiles at runtime")
+	fs.StringVar(&o.baseURL, "base-url", "", "Base URL prepended to relative URLs")
+	fs.Var(&o.defaultHeaders, "default-header", "Default header (repeatable, format: \"Key: Value\")")
+	fs.Var(&o.defaultQuery, "default-query", "Default query parameter (repeatable, format: \"key=value\")")
+	fs.DurationVar(&o.timeout, "timeout", 30*time.Second, "Default request timeout (per-request timeout overrides it)")
+	fs.Int64Var(&o.maxResponseSize, "max-response-size", 51200, "Maximum response body size in bytes")
+	fs.Int64Var(&o.spillThreshold, "spill-threshold", 0, "Stream response bodies larger than this many bytes to a temp file (path and SHA-256 in the output) instead of discarding the part past max-response-size (0 = off)")
+	fs.StringVar(&o.spillDir, "spill-dir", "", "Directory for --spill-threshold temp files (default: the system temp directory)")
+	fs.StringVar(&o.httpVersion, "http-version", "", "Use only this HTTP version: 1.1 or 2 (default: negotiated)")
+	fs.BoolVar(&o.strictURLs, "strict-urls", false, "Reject URLs with a unicode host or unencoded characters instead of encoding them")
+	fs.BoolVar(&o.noExpect, "disable-expect-continue", false, "Strip \"Expect: 100-continue\" from requests, for servers and load balancers that mishandle it")
+	fs.IntVar(&o.maxConcurrent, "max-concurrent-requests", 0, "Maximum requests in flight at once; further calls wait in arrival order (0 = unlimited)")
+	fs.Int64Var(&o.maxBandwidth, "max-bandwidth", 0, "Limit request and response body throughput to this many bytes per second, shared by all requests (0 = unlimited)")
+	fs.BoolVar(&o.paceRateLimits, "pace-rate-limits", false, "When a host's rate-limit headers say its quota is spent, hold further requests to it until the reset time")
+	fs.IntVar(&o.rateLimitReserve, "rate-limit-reserve", 0, "With --pace-rate-limits, start holding requests once this many remain in the host's quota")
+	fs.DurationVar(&o.maxPaceWait, "max-pace-wait", time.Minute, "With --pace-rate-limits, the longest a request is held; a later reset is waited for only this long")
+	fs.DurationVar(&o.coalesceWindow, "coalesce-window", 0, "Let identical GETs (same URL, headers and options) share one upstream call while it is in flight and for this long after (0 = off)")
+	fs.StringVar(&o.cacheDir, "cache-dir", "", "Store GET responses in this directory and reuse them while fresh (Cache-Control max-age or Expires); agents can pass cache: bypass, refresh or only")
+	fs.DurationVar(&o.cacheTTL, "cache-ttl", 0, "Freshness of --cache-dir entries, overriding the response's Cache-Control and Expires (0 = use them)")
+	fs.DurationVar(&o.dnsCacheTTL, "dns-cache-ttl", 0, "Cache resolved host addresses for this long, and keep using them while the resolver fails (0 = off)")
+	fs.IntVar(&o.dnsCacheSize, "dns-cache-size", 1000, "Maximum number of hosts in the --dns-cache-ttl cache")
+	fs.StringVar(&o.proxy, "proxy", "", "HTTP/HTTPS proxy URL")
+	fs.BoolVar(&o.proxyFromEnv, "proxy-from-env", true, "Without --proxy, use the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables like curl does")
+	fs.StringVar(&o.noProxy, "no-proxy", "", "Comma-separated hosts, domains (.internal.example.com) and CIDR ranges that bypass --proxy (default: the NO_PROXY environment variable)")
+	fs.IntVar(&o.retry, "retry", 0, "Number of retries for failed requests")
+	fs.DurationVar(&o.retryDelay, "retry-delay", 1000*time.Millisecond, "Delay between retries")
+	fs.BoolVar(&o.insecure, "insecure", false, "Skip TLS certificate verification")
+	fs.BoolVar(&o.cookieJar, "cookie-jar", false, "Enable in-memory cookie jar (persists cookies across requests for session flows)")
+	fs.BoolVar(&o.blockPrivate, "block-private-networks", false, "Refuse requests to loopback, private, link-local and cloud metadata addresses (SSRF guard)")
+	fs.StringVar(&o.recordDir, "record", "", "Record every request/response pair as a JSON cassette in this directory")
+	fs.StringVar(&o.replayDir, "replay", "", "Serve responses from the cassettes in this directory instead of the network")
+	fs.StringVar(&o.replayMatch, "replay-match", "method,url", "Request parts a cassette must match in replay mode: method, url, path (url without query), body")
+	fs.StringVar(&o.idempotencyKey, "idempotency-key-header", "Idempotency-Key", "Header that carries a generated key, reused across retries, on POST and PATCH requests when --retry is set (empty disables)")
+	fs.DurationVar(&o.chaosLatency, "chaos-latency", 0, "Chaos testing: add this delay before every request attempt")
+	fs.Float64Var(&o.chaosErrorRate, "chaos-error-rate", 0, "Chaos testing: fraction of request attempts (0 to 1) that fail")
+	fs.IntVar(&o.chaosStatus, "chaos-error-status", 503, "Chaos testing: status of injected failures; 0 simulates a connection reset")
+	fs.StringVar(&o.chaosHosts, "chaos-hosts", "", "Chaos testing: comma-separated hosts (api.example.com, *.example.com) to limit injection to (default: all)")
+	fs.StringVar(&o.oauthTokenURL, "oauth-token-url", "", "OAuth2 token endpoint: requests get \"Authorization: Bearer\" from the refresh-token grant")
+	fs.StringVar(&o.oauthClientID, "oauth-client-id", "", "OAuth2 client ID")
+	fs.StringVar(&o.oauthClientSecret, "oauth-client-secret", "", "OAuth2 client secret (empty for public clients)")
+	fs.StringVar(&o.oauthRefreshToken, "oauth-refresh-token", "", "OAuth2 refresh token, used until --oauth-token-store holds a newer one")
+	fs.StringVar(&o.oauthScopes, "oauth-scopes", "", "Comma-separated OAuth2 scopes to request")
+	fs.StringVar(&o.oauthTokenStore, "oauth-token-store", "", "Encrypted file that keeps the OAuth2 tokens, including rotated refresh tokens")
+	fs.StringVar(&o.oauthStoreKey, "oauth-store-key", "", "Passphrase that encrypts --oauth-token-store (set it via REST_API_MCP_OAUTH_STORE_KEY)")
+	fs.StringVar(&o.oauthDeviceURL, "oauth-device-url", "", "OAuth2 device authorization endpoint for \"rest-api-mcp oauth login\"")
+	fs.StringVar(&o.s3Endpoint, "s3-endpoint", "", "S3-compatible endpoint for the presign tool, e.g. http://localhost:9000 (default: AWS S3 in --s3-region)")
+	fs.StringVar(&o.s3Region, "s3-region", "", "S3 region for presigned URLs (default: AWS_REGION, AWS_DEFAULT_REGION, else us-east-1)")
+	fs.StringVar(&o.s3AccessKeyID, "s3-access-key-id", "", "Access key ID that signs presigned URLs; registers the presign tool (default: AWS_ACCESS_KEY_ID)")
+	fs.StringVar(&o.s3SecretAccessKey, "s3-secret-access-key", "", "Secret access key for presigned URLs (default: AWS_SECRET_ACCESS_KEY)")
+	fs.StringVar(&o.s3SessionToken, "s3-session-token", "", "Session token of temporary S3 credentials (default: AWS_SESSION_TOKEN)")
+	fs.BoolVar(&o.s3PathStyle, "s3-path-style", false, "Address buckets as endpoint/bucket/key instead of bucket.endpoint/key (always on with --s3-endpoint)")
+	o.defineAzureFlags(fs)
+	o.defineGoogleFlags(fs)
+	fs.BoolVar(&o.followRedirects, "follow-redirects", true, "Default for followRedirects when the agent does not set it")
+	fs.BoolVar(&o.includeResponseHeaders, "include-response-headers", false, "Default for includeResponseHeaders when the agent does not set it")
+	fs.BoolVar(&o.includeTiming, "include-timing", false, "Default for includeTiming when the agent does not set it")
+	fs.IntVar(&o.tokenBudget, "token-budget", 10000, "Warn when an http_request output is estimated above this many tokens (0 = never)")
+	fs.BoolVar(&o.decodeBinaryBodies, "decode-binary-bodies", true, "Show MessagePack and CBOR response bodies as JSON; false shows them as binary")
+	fs.StringVar(&o.defaultFormat, "default-format", "text", "Default for format when the agent does not set it: text, raw or table")
+	fs.BoolVar(&o.faultInjection, "enable-fault-injection", false, "Register test-mode tools (simulate_auth_expiry) for verifying re-auth/retry behavior")
+	fs.StringVar(&o.toolsets, "toolset", "all", "Comma-separated toolsets whose tools are registered: core, testing, streaming, utilities, or all")
+	o.defineAccessFlags(fs)
+	fs.StringVar(&o.templateEnv, "template-env", "", "Comma-separated environment variables request templates may read with {{env \"NAME\"}}")
+	fs.StringVar(&o.protoDescriptors, "proto-descriptors", "", "FileDescriptorSet (protoc --descriptor_set_out --include_imports) for protoRequestType and protoResponseType")
+	o.defineOpenAPIFlags(fs)
+	fs.StringVar(&o.requestIDHeader, "request-id-header", "", "Send a generated UUID in this header on every request (e.g. X-Request-Id) and show it in output and logs")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Never send requests: http_request returns the fully resolved request instead")
+	fs.StringVar(&o.transport, "transport", "stdio", "MCP transport: stdio, http (streamable HTTP at /mcp), or sse (legacy SSE at /sse)")
+	fs.StringVar(&o.listenAddr, "listen", "127.0.0.1:8808", "Listen address for the http/sse transports")
+	fs.StringVar(&o.authToken, "auth-token", "", "Require \"Authorization: Bearer <token>\" on the http/sse transports")
+	fs.StringVar(&o.allowedOrigins, "allowed-origins", "", "Comma-separated browser origins (e.g. https://app.example.com) the http/sse transports accept; requests from other origins are rejected")
+	fs.StringVar(&o.tlsCertFile, "tls-cert", "", "TLS certificate file for the http/sse transports")
+	fs.StringVar(&o.tlsKeyFile, "tls-key", "", "TLS private key file for the http/sse transports")
+	return o
+}
+
+// loadOptions parses args and fills the remaining flags from the config file
+// and environment. A non-empty profile replaces --profile. A non-nil api
+// applies that API's options last; base-url, default-header, default-query and
+// the OAuth2 token endpoint are not inherited, so one API's credentials never
+// reach another.
+// Returns the options and the config file's profiles and APIs.
+func loadOptions(args []string, environ []string, profile string, api *config.API, errorHandling flag.ErrorHandling) (*options, *config.Sections, error) {
+	fs := flag.NewFlagSet("rest-api-mcp", errorHandling)
+	o := defineFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if profile != "" {
+		if err := fs.Set("profile", profile); err != nil {
+			return nil, nil, err
+		}
+	}
+	sections, err := config.Resolve(fs, environ)
+	if err != nil {
+		return nil, nil, err
+	}
+	o.hostRules = sections.Hosts
+	o.flags = fs
+	if o.noProxy == "" {
+		o.noProxy = environValue(environ, "NO_PROXY", "no_proxy")
+	}
+	o.templateEnvValues = make(map[string]string)
+	for _, name := range splitList(o.templateEnv) {
+		if value := environValue(environ, name); value != "" {
+			o.templateEnvValues[name] = value
+		}
+	}
+	if o.s3AccessKeyID == "" && o.s3SecretAccessKey == "" {
+		o.s3AccessKeyID = environValue(environ, "AWS_ACCESS_KEY_ID")
+		o.s3SecretAccessKey = environValue(environ, "AWS_SECRET_ACCESS_KEY")
+		o.s3SessionToken = environValue(environ, "AWS_SESSION_TOKEN")
+	}
+	if o.s3Region == "" {
+		o.s3Region = environValue(environ, "AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if o.azureTenantID == "" && o.azureClientID == "" && o.azureClientSecret == "" {
+		o.azureTenantID = environValue(environ, "AZURE_TENANT_ID")
+		o.azureClientID = environValue(environ, "AZURE_CLIENT_ID")
+		o.azureClientSecret = environValue(environ, "AZURE_CLIENT_SECRET")
+	}
+	if o.azureAuthorityHost == "" {
+		o.azureAuthorityHost = environValue(environ, "AZURE_AUTHORITY_HOST")
+	}
+	o.azureIdentityEndpoint = environValue(environ, "IDENTITY_ENDPOINT")
+	o.azureIdentityHeader = environValue(environ, "IDENTITY_HEADER")
+	if o.proxy == "" && o.proxyFromEnv {
+		o.envProxy = environValue(environ, "HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy")
+	}
+	if api != nil {
+		o.baseURL = ""
+		o.defaultHeaders = nil
+		o.defaultQuery = nil
+		o.oauthTokenURL = ""
+		o.oauthClientSecret = ""
+		o.oauthRefreshToken = ""
+		o.oauthTokenStore = ""
+		if err := config.Apply(fs, api.Options, map[string]bool{}, fmt.Sprintf("API %q", api.Name)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return o, sections, nil
+}
+
+// environValue returns the first of names set in environ, or "".
+func environValue(environ []string, names ...string) string {
+	for _, name := range names {
+		for _, entry := range environ {
+			if value, ok := strings.CutPrefix(entry, name+"="); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/s3"
+	"github.com/lexandro/rest-api-mcp/server"
+	"github.com/lexandro/rest-api-mcp/tools"
+)
+
+// clientConfig, toolSettings and transportConfig hand the resolved options to
+// the client, tools and server packages.
+func (o *options) clientConfig() client.Config {
+	replayMatch, _ := client.ParseReplayMatch(o.replayMatch) // reported by problems()
+	config := client.Config{
+		BaseURL:            o.baseURL,
+		DefaultHeaders:     client.ParseHeaders(o.defaultHeaders),
+		DefaultQueryParams: client.ParseQueryParams(o.defaultQuery),
+		Timeout:            o.timeout,
+		MaxResponseSize:    o.maxResponseSize,
+		ProxyURL:           o.proxy,
+		NoProxy:            o.noProxy,
+		ProxyFromEnv:       o.envProxy != "",
+		RetryCount:         o.retry,
+		RetryDelay:         o.retryDelay,
+		InsecureTLS:        o.insecure,
+		EnableCookieJar:    o.cookieJar,
+		HostRules:          o.hostRules,
+
+		BlockPrivateNetworks: o.blockPrivate,
+
+		RecordDir:   o.recordDir,
+		ReplayDir:   o.replayDir,
+		ReplayMatch: replayMatch,
+
+		IdempotencyKeyHeader: o.idempotencyKey,
+
+		Chaos: client.ChaosConfig{
+			Latency:     o.chaosLatency,
+			ErrorRate:   o.chaosErrorRate,
+			ErrorStatus: o.chaosStatus,
+			Hosts:       splitList(o.chaosHosts),
+		},
+
+		SpillThreshold: o.spillThreshold,
+		SpillDir:       o.spillDir,
+
+		HTTPVersion:           o.httpVersion,
+		DisableExpectContinue: o.noExpect,
+		StrictURLs:            o.strictURLs,
+		MaxConcurrentRequests: o.maxConcurrent,
+		MaxBandwidth:          o.maxBandwidth,
+		PaceRateLimits:        o.paceRateLimits,
+		RateLimitReserve:      o.rateLimitReserve,
+		MaxPaceWait:           o.maxPaceWait,
+		CoalesceWindow:        o.coalesceWindow,
+		RequestIDHeader:       o.requestIDHeader,
+
+		CacheDir: o.cacheDir,
+		CacheTTL: o.cacheTTL,
+
+		DNSCacheTTL:  o.dnsCacheTTL,
+		DNSCacheSize: o.dnsCacheSize,
+
+		HostTokenSources: append(o.azureTokenSources(), o.googleTokenSources()...),
+	}
+	config.TokenSource = o.tokenSource(client.NewTransport(config))
+	return config
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (o *options) toolSettings() (tools.Settings, error) {
+	allowed, err := tools.ParseMethodList(o.allowMethods)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --allow-methods: %w", err)
+	}
+	denied, err := tools.ParseMethodList(o.denyMethods)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --deny-methods: %w", err)
+	}
+	methodPolicy := tools.MethodPolicy{ReadOnly: o.readOnly, Allow: allowed, Deny: denied}
+	if err := methodPolicy.Validate(); err != nil {
+		return tools.Settings{}, err
+	}
+
+	allowedHeaders, err := tools.ParseHeaderList(o.allowedRequestHeaders)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --allowed-request-headers: %w", err)
+	}
+	redactedHeaders, err := tools.ParseHeaderList(o.redactResponseHeaders)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --redact-response-headers: %w", err)
+	}
+
+	toolsets, err := tools.ParseToolsets(o.toolsets)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --toolset: %w", err)
+	}
+
+	if err := tools.ValidateFormat(o.defaultFormat); err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --default-format: %w", err)
+	}
+
+	urlPolicy, err := tools.LoadURLPolicy(o.policyFile)
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("invalid --policy-file: %w", err)
+	}
+
+	var protoTypes *tools.ProtoTypes
+	if o.protoDescriptors != "" {
+		if protoTypes, err = tools.LoadProtoTypes(o.protoDescriptors); err != nil {
+			return tools.Settings{}, fmt.Errorf("invalid --proto-descriptors: %w", err)
+		}
+	}
+
+	openAPISource, err := o.openAPISource()
+	if err != nil {
+		return tools.Settings{}, err
+	}
+	asyncAPISpec, err := o.asyncAPISpec()
+	if err != nil {
+		return tools.Settings{}, err
+	}
+
+	effectiveConfig, err := yaml.Marshal(effectiveConfig(o, nil, nil))
+	if err != nil {
+		return tools.Settings{}, fmt.Errorf("rendering configuration: %w", err)
+	}
+
+	return tools.Settings{
+		FollowRedirects:        o.followRedirects,
+		IncludeResponseHeaders: o.includeResponseHeaders,
+		IncludeTiming:          o.includeTiming,
+		MaxResponseSize:        o.maxResponseSize,
+		TokenBudget:            o.tokenBudget,
+		DecodeBinaryBodies:     o.decodeBinaryBodies,
+		EnableFaultInjection:   o.faultInjection,
+		Methods:                methodPolicy,
+		URLs:                   urlPolicy,
+		Headers:                tools.HeaderPolicy{Allowed: allowedHeaders},
+		Redaction:              tools.ResponseRedaction{Headers: redactedHeaders, Disabled: o.showResponseSecrets},
+		RequestIDHeader:        o.requestIDHeader,
+		TemplateEnv:            o.templateEnvValues,
+		Proto:                  protoTypes,
+		OpenAPI:                openAPISource,
+		OpenAPITools:           o.openAPITools,
+		OpenAPIFilter:          o.openAPIFilter(),
+		AsyncAPI:               asyncAPISpec,
+		S3:                     o.s3Config(),
+		Toolsets:               toolsets,
+		EffectiveConfig:        string(effectiveConfig),
+		DryRun:                 o.dryRun,
+		DefaultFormat:          o.defaultFormat,
+		Limits: tools.RequestLimits{
+			MaxBodyBytes:   o.maxRequestSize,
+			MaxHeaders:     o.maxRequestHeaders,
+			MaxHeaderBytes: o.maxHeaderSize,
+		},
+	}, nil
+}
+
+// s3Config returns nil unless both S3 keys are set.
+func (o *options) s3Config() *s3.Config {
+	if o.s3AccessKeyID == "" || o.s3SecretAccessKey == "" {
+		return nil
+	}
+	region := o.s3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3.Config{
+		Endpoint:        o.s3Endpoint,
+		Region:          region,
+		AccessKeyID:     o.s3AccessKeyID,
+		SecretAccessKey: o.s3SecretAccessKey,
+		SessionToken:    o.s3SessionToken,
+		PathStyle:       o.s3PathStyle,
+	}
+}
+
+func (o *options) transportConfig() server.TransportConfig {
+	return server.TransportConfig{
+		Transport:      o.transport,
+		ListenAddr:     o.listenAddr,
+		AuthToken:      o.authToken,
+		AllowedOrigins: splitList(o.allowedOrigins),
+		TLSCertFile:    o.tlsCertFile,
+		TLSKeyFile:     o.tlsKeyFile,
+	}
+}
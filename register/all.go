@@ -0,0 +1,54 @@
+package register
+
+import (
+	"fmt"
+	"io"
+)
+
+// registerAll registers the server with every installed client that supports
+// scope and prints one summary line per client; with dryRun it prints what
+// would be written instead. Returns false if any client failed.
+func registerAll(out io.Writer, serverName, scope, directory string, entry mcpServerEntry, dryRun bool) bool {
+	ok := true
+	for _, target := range clientTargets() {
+		if scope == "project" && target.projectFile == "" {
+			fmt.Fprintf(out, "  %-9s skipped: no per-project configuration\n", target.name)
+			continue
+		}
+		configPath, err := target.configPath(scope, directory)
+		if err != nil {
+			fmt.Fprintf(out, "  %-9s failed: %s\n", target.name, err)
+			ok = false
+			continue
+		}
+		if !target.installed(scope, configPath) {
+			fmt.Fprintf(out, "  %-9s skipped: not installed\n", target.name)
+			continue
+		}
+		if dryRun {
+			output, err := target.render(configPath, serverName, entry)
+			if err != nil {
+				fmt.Fprintf(out, "  %-9s failed: %s\n", target.name, err)
+				ok = false
+				continue
+			}
+			printDryRun(out, configPath, output)
+			continue
+		}
+		if err := target.write(configPath, serverName, entry); err != nil {
+			fmt.Fprintf(out, "  %-9s failed: %s\n", target.name, err)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(out, "  %-9s registered %q in %s\n", target.name, serverName, configPath)
+	}
+	return ok
+}
+
+// printDryRun shows the file register would write.
+func printDryRun(out io.Writer, configPath string, content []byte) {
+	fmt.Fprintf(out, "Would write %s:\n%s", configPath, content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		fmt.Fprintln(out)
+	}
+}
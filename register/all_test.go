@@ -0,0 +1,73 @@
+package register
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_registerAll_OnlyInstalledClients(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("APPDATA", filepath.Join(home, "AppData"))
+	t.Setenv("CODEX_HOME", "")
+	// Installed: Claude Code and Codex. Not installed: everything else.
+	os.MkdirAll(filepath.Join(home, ".claude"), 0o755)
+	os.MkdirAll(filepath.Join(home, ".codex"), 0o755)
+
+	var out bytes.Buffer
+	if !registerAll(&out, "rest-api", "user", "", buildEntry("/bin/rest-api-mcp", nil, nil), false) {
+		t.Fatalf("registerAll reported a failure:\n%s", out.String())
+	}
+
+	for _, path := range []string{filepath.Join(home, ".claude.json"), filepath.Join(home, ".codex", "config.toml")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %s", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ".codeium")); err == nil {
+		t.Error("windsurf is not installed and must not get a config")
+	}
+	summary := out.String()
+	for _, want := range []string{"claude    registered", "codex     registered", "windsurf  skipped: not installed", "cline     skipped: not installed"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func Test_registerAll_ProjectScopeSkipsUserOnlyClients(t *testing.T) {
+	project := t.TempDir()
+	var out bytes.Buffer
+	registerAll(&out, "rest-api", "project", project, buildEntry("/bin/rest-api-mcp", nil, nil), false)
+
+	if _, err := os.Stat(filepath.Join(project, ".mcp.json")); err != nil {
+		t.Errorf("expected .mcp.json: %s", err)
+	}
+	if !strings.Contains(out.String(), "codex     skipped: no per-project configuration") {
+		t.Errorf("summary:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "zed       skipped: not installed") {
+		t.Errorf("zed without a .zed directory must be skipped:\n%s", out.String())
+	}
+}
+
+func Test_registerAll_DryRunWritesNothing(t *testing.T) {
+	project := t.TempDir()
+	var out bytes.Buffer
+	if !registerAll(&out, "rest-api", "project", project, buildEntry("/bin/rest-api-mcp", []string{"--read-only"}, nil), true) {
+		t.Fatalf("registerAll reported a failure:\n%s", out.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(project, ".mcp.json")); err == nil {
+		t.Error("dry run must not write .mcp.json")
+	}
+	want := "Would write " + filepath.Join(project, ".mcp.json") + ":\n{\n  \"mcpServers\": {\n    \"rest-api\": {"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+}
@@ -0,0 +1,130 @@
+package register
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// clientTarget is an MCP client whose configuration register can update.
+type clientTarget struct {
+	name        string
+	userPath    func(platform) string
+	projectFile string                // relative to the project directory; empty when the client has no project config
+	installDir  func(platform) string // its existence means the client is installed; default: userPath's directory
+	serversKey  string                // top-level key holding the server entries
+	render      renderFunc
+}
+
+func clientTargets() []clientTarget {
+	return []clientTarget{
+		{
+			name:        "claude",
+			userPath:    claudePath,
+			projectFile: ".mcp.json",
+			installDir:  claudeDir,
+			serversKey:  "mcpServers",
+			render:      renderConfig,
+		},
+		{name: "codex", userPath: codexPath, serversKey: "mcp_servers", render: renderCodexConfig},
+		{name: "windsurf", userPath: windsurfPath, serversKey: "mcpServers", render: renderConfig},
+		{
+			name:        "zed",
+			userPath:    zedPath,
+			projectFile: filepath.Join(".zed", "settings.json"),
+			serversKey:  "context_servers",
+			render:      renderZedConfig,
+		},
+		{
+			name: "cline",
+			userPath: func(p platform) string {
+				return vscodeExtensionSettingsPath(p, clineExtension, "cline_mcp_settings.json")
+			},
+			installDir: func(p platform) string { return vscodeExtensionDir(p, clineExtension) },
+			serversKey: "mcpServers",
+			render:     renderConfig,
+		},
+		{
+			name:        "roo",
+			userPath:    func(p platform) string { return vscodeExtensionSettingsPath(p, rooExtension, "mcp_settings.json") },
+			projectFile: filepath.Join(".roo", "mcp.json"),
+			installDir:  func(p platform) string { return vscodeExtensionDir(p, rooExtension) },
+			serversKey:  "mcpServers",
+			render:      renderConfig,
+		},
+	}
+}
+
+// configPath resolves the config file for scope on this machine.
+func (t clientTarget) configPath(scope, directory string) (string, error) {
+	p, err := currentPlatform()
+	if err != nil && scope != "project" {
+		return "", err
+	}
+	return resolveConfigPath(p, t, scope, directory)
+}
+
+// resolveConfigPath resolves target's config file for scope on p: its
+// projectFile inside directory, or its user config for p's OS and
+// environment.
+func resolveConfigPath(p platform, target clientTarget, scope, directory string) (string, error) {
+	if scope == "project" {
+		if target.projectFile == "" {
+			return "", fmt.Errorf("%s has no per-project MCP configuration; use \"register user --client %s\"", target.name, target.name)
+		}
+		absDir, err := filepath.Abs(directory)
+		if err != nil {
+			return "", fmt.Errorf("Abs(%s): %w", directory, err)
+		}
+		return filepath.Join(absDir, target.projectFile), nil
+	}
+	return target.userPath(p), nil
+}
+
+// write sets the server's entry in configPath.
+func (t clientTarget) write(configPath, serverName string, entry mcpServerEntry) error {
+	return writeRendered(t.render, configPath, serverName, entry)
+}
+
+// installed reports whether the client appears to be installed: its install
+// directory exists, or for the project scope, the directory of configPath.
+func (t clientTarget) installed(scope, configPath string) bool {
+	dir := filepath.Dir(configPath)
+	if scope == "user" && t.installDir != nil {
+		p, err := currentPlatform()
+		if err != nil {
+			return false
+		}
+		dir = t.installDir(p)
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// zedServerEntry is a context_servers entry in Zed's settings.json.
+type zedServerEntry struct {
+	Source  string            `json:"source"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+func writeZedConfig(configPath, serverName string, entry mcpServerEntry) error {
+	return writeRendered(renderZedConfig, configPath, serverName, entry)
+}
+
+func renderZedConfig(configPath, serverName string, entry mcpServerEntry) ([]byte, error) {
+	return renderJSONConfig(configPath, "context_servers", serverName, zedServerEntry{Source: "custom", Command: entry.Command, Args: entry.Args, Env: entry.Env})
+}
+
+func findClient(name string) (clientTarget, error) {
+	var names []string
+	for _, target := range clientTargets() {
+		if target.name == name {
+			return target, nil
+		}
+		names = append(names, target.name)
+	}
+	return clientTarget{}, fmt.Errorf("unknown client %q (expected one of %s, all)", name, strings.Join(names, ", "))
+}
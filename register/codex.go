@@ -0,0 +1,141 @@
+package register
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// bareTOMLKey matches keys that need no quoting.
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlTableHeader captures the dotted key of a "[table]" line; array tables
+// ("[[table]]") and trailing comments are allowed.
+var tomlTableHeader = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+
+// writeCodexConfig sets [mcp_servers.<serverName>] in Codex's config.toml.
+func writeCodexConfig(configPath string, serverName string, entry mcpServerEntry) error {
+	return writeRendered(renderCodexConfig, configPath, serverName, entry)
+}
+
+// renderCodexConfig edits config.toml as text so the other tables and all
+// comments survive: an existing section for the server (sub-tables included)
+// is replaced in place, otherwise the section is appended.
+func renderCodexConfig(configPath string, serverName string, entry mcpServerEntry) ([]byte, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", configPath, err)
+	}
+	var existing map[string]any
+	if _, err := toml.Decode(string(data), &existing); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+
+	output := replaceTOMLSection(string(data), "mcp_servers."+tomlKey(serverName), codexSection(serverName, entry))
+	if _, err := toml.Decode(output, &existing); err != nil {
+		return nil, fmt.Errorf("updating %s would leave invalid TOML (edit it by hand): %w", configPath, err)
+	}
+	return []byte(output), nil
+}
+
+func codexSection(serverName string, entry mcpServerEntry) string {
+	var section strings.Builder
+	fmt.Fprintf(&section, "[mcp_servers.%s]\n", tomlKey(serverName))
+	fmt.Fprintf(&section, "command = %s\n", tomlString(entry.Command))
+	quotedArgs := make([]string, len(entry.Args))
+	for i, arg := range entry.Args {
+		quotedArgs[i] = tomlString(arg)
+	}
+	fmt.Fprintf(&section, "args = [%s]\n", strings.Join(quotedArgs, ", "))
+	if len(entry.Env) > 0 {
+		keys := make([]string, 0, len(entry.Env))
+		for key := range entry.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = tomlKey(key) + " = " + tomlString(entry.Env[key])
+		}
+		fmt.Fprintf(&section, "env = { %s }\n", strings.Join(pairs, ", "))
+	}
+	return section.String()
+}
+
+// replaceTOMLSection swaps the lines of the table named key, and of its
+// sub-tables, for section; it appends section when the table is absent.
+func replaceTOMLSection(content, key, section string) string {
+	lines := strings.SplitAfter(content, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		match := tomlTableHeader.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name := normalizeTOMLKey(match[1])
+		if start == -1 && name == key {
+			start = i
+			continue
+		}
+		if start != -1 && !strings.HasPrefix(name, key+".") {
+			end = i
+			break
+		}
+	}
+
+	if start == -1 {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return content + section
+	}
+	// Keep the blank lines and comments that precede the next table.
+	for end > start+1 && isTOMLTrivia(lines[end-1]) {
+		end--
+	}
+	return strings.Join(lines[:start], "") + section + strings.Join(lines[end:], "")
+}
+
+// normalizeTOMLKey removes whitespace around dots and the quotes around key
+// parts that do not need them, so `mcp_servers . "rest-api"` matches mcp_servers.rest-api.
+func normalizeTOMLKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if unquoted := strings.Trim(part, `"'`); len(part) >= 2 && part != unquoted && bareTOMLKey.MatchString(unquoted) {
+			part = unquoted
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ".")
+}
+
+func isTOMLTrivia(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+func tomlKey(key string) string {
+	if bareTOMLKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString quotes s as a TOML basic string; JSON string escapes are valid TOML.
+func tomlString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
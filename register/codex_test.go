@@ -0,0 +1,87 @@
+package register
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func Test_writeCodexConfig_CreatesNewFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".codex", "config.toml")
+	entry := buildEntry(`C:\bin\rest-api-mcp.exe`, []string{"--base-url", "http://localhost:8080"}, map[string]string{"REST_API_MCP_AUTH_TOKEN": "a \"quoted\" secret", "B": "2"})
+
+	if err := writeCodexConfig(configPath, "rest-api", entry); err != nil {
+		t.Fatalf("writeCodexConfig: %s", err)
+	}
+
+	var config struct {
+		MCPServers map[string]mcpServerEntry `toml:"mcp_servers"`
+	}
+	if _, err := toml.DecodeFile(configPath, &config); err != nil {
+		t.Fatalf("decoding: %s", err)
+	}
+	got := config.MCPServers["rest-api"]
+	if got.Command != entry.Command || !sliceEqual(got.Args, entry.Args) || !reflect.DeepEqual(got.Env, entry.Env) {
+		t.Errorf("entry = %+v, want %+v", got, entry)
+	}
+}
+
+func Test_writeCodexConfig_PreservesOtherContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "appends after other tables",
+			existing: "# my settings\nmodel = \"o3\"\n\n[mcp_servers.other]\ncommand = \"other\" # keep me\n",
+			want:     "# my settings\nmodel = \"o3\"\n\n[mcp_servers.other]\ncommand = \"other\" # keep me\n\n[mcp_servers.rest-api]\ncommand = \"/bin/new\"\nargs = [\"--read-only\"]\n",
+		},
+		{
+			name:     "replaces existing section and sub-tables in place",
+			existing: "[mcp_servers.\"rest-api\"]\ncommand = \"/bin/old\"\nargs = []\n\n[mcp_servers.rest-api.env]\nTOKEN = \"x\"\n\n# next server\n[mcp_servers.other]\ncommand = \"other\"\n",
+			want:     "[mcp_servers.rest-api]\ncommand = \"/bin/new\"\nargs = [\"--read-only\"]\n\n# next server\n[mcp_servers.other]\ncommand = \"other\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(configPath, []byte(tt.existing), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := writeCodexConfig(configPath, "rest-api", buildEntry("/bin/new", []string{"--read-only"}, nil)); err != nil {
+				t.Fatalf("writeCodexConfig: %s", err)
+			}
+			got, _ := os.ReadFile(configPath)
+			if string(got) != tt.want {
+				t.Errorf("config =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeCodexConfig_InvalidTOML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(configPath, []byte("[broken"), 0o644)
+
+	err := writeCodexConfig(configPath, "rest-api", buildEntry("/bin/x", nil, nil))
+	if err == nil || !strings.Contains(err.Error(), "parsing") {
+		t.Errorf("expected a parsing error, got %v", err)
+	}
+}
+
+func Test_clientTarget_configPath_Codex(t *testing.T) {
+	t.Setenv("CODEX_HOME", "/tmp/codex-home")
+	target, _ := findClient("codex")
+	got, err := target.configPath("user", "")
+	if err != nil || got != filepath.Join("/tmp/codex-home", "config.toml") {
+		t.Errorf("configPath = %q, %v", got, err)
+	}
+	if _, err := target.configPath("project", "."); err == nil {
+		t.Error("expected an error for the project scope")
+	}
+}
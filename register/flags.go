@@ -0,0 +1,90 @@
+package register
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// envNamePattern is a portable environment variable name.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// serverNamePattern limits --name to characters every client accepts as a key.
+var serverNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// registerFlags are the register subcommand's own options, given before "--".
+type registerFlags struct {
+	client string
+	name   string            // overrides ServerInfo.Name; empty keeps it
+	env    map[string]string // written to the entry's env block
+	dryRun bool              // print what would be written instead of writing
+}
+
+// parseRegisterFlags takes the register options out of args (everything after
+// the scope) and returns the rest — directory, "--" and server args — unchanged.
+func parseRegisterFlags(args []string) (registerFlags, []string, error) {
+	flags := registerFlags{client: "claude"}
+	var remaining []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			remaining = append(remaining, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			remaining = append(remaining, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "dry-run" {
+			if !hasValue {
+				value = "true"
+			}
+			dryRun, err := strconv.ParseBool(value)
+			if err != nil {
+				return registerFlags{}, nil, fmt.Errorf("--dry-run=%s: expected true or false", value)
+			}
+			flags.dryRun = dryRun
+			continue
+		}
+		if name != "client" && name != "name" && name != "env" {
+			return registerFlags{}, nil, fmt.Errorf("unknown register option %q (server flags go after \"--\")", arg)
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return registerFlags{}, nil, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "client":
+			flags.client = value
+		case "name":
+			if !serverNamePattern.MatchString(value) {
+				return registerFlags{}, nil, fmt.Errorf("--name %q may only contain letters, digits, '.', '_' and '-'", value)
+			}
+			flags.name = value
+		case "env":
+			key, envValue, ok := strings.Cut(value, "=")
+			if !ok || !envNamePattern.MatchString(key) {
+				return registerFlags{}, nil, fmt.Errorf("--env %q is not in KEY=VALUE format", redactEnvValue(value))
+			}
+			if flags.env == nil {
+				flags.env = make(map[string]string)
+			}
+			flags.env[key] = envValue
+		}
+	}
+	return flags, remaining, nil
+}
+
+// redactEnvValue keeps a malformed --env value's secret out of the error message.
+func redactEnvValue(value string) string {
+	if key, _, ok := strings.Cut(value, "="); ok {
+		return key + "=***"
+	}
+	return value
+}
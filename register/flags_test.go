@@ -0,0 +1,39 @@
+package register
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseRegisterFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantFlags     registerFlags
+		wantRemaining []string
+		wantErr       bool
+	}{
+		{"defaults to claude", []string{"."}, registerFlags{client: "claude"}, []string{"."}, false},
+		{"client before directory", []string{"--client", "codex", "."}, registerFlags{client: "codex"}, []string{"."}, false},
+		{"client after directory", []string{".", "--client=codex", "--", "--client", "x"}, registerFlags{client: "codex"}, []string{".", "--", "--client", "x"}, false},
+		{"name", []string{"--name", "billing-api", "--", "--base-url", "x"}, registerFlags{client: "claude", name: "billing-api"}, []string{"--", "--base-url", "x"}, false},
+		{"env", []string{"--env", "REST_API_MCP_AUTH_TOKEN=s3cr=t", "--env=A=1"}, registerFlags{client: "claude", env: map[string]string{"REST_API_MCP_AUTH_TOKEN": "s3cr=t", "A": "1"}}, nil, false},
+		{"env without value", []string{"--env", "TOKEN"}, registerFlags{}, nil, true},
+		{"dry run", []string{"--dry-run", "."}, registerFlags{client: "claude", dryRun: true}, []string{"."}, false},
+		{"dry run false", []string{"--dry-run=false"}, registerFlags{client: "claude"}, nil, false},
+		{"invalid name", []string{"--name", "billing api"}, registerFlags{}, nil, true},
+		{"missing value", []string{"--client"}, registerFlags{}, nil, true},
+		{"unknown option", []string{"--base-url", "x"}, registerFlags{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, remaining, err := parseRegisterFlags(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(flags, tt.wantFlags) || !sliceEqual(remaining, tt.wantRemaining) {
+				t.Errorf("got %+v, remaining %v; want %+v, %v", flags, remaining, tt.wantFlags, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package register
+
+// stripJSONC blanks out // and /* */ comments and drops trailing commas
+// before } or ], turning JSONC into plain JSON. String contents are kept as is.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ',' && closesAfterWhitespace(data, i+1):
+			// trailing comma
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// closesAfterWhitespace reports whether the next significant character from
+// data[start:] closes an object or array. Comments count as whitespace.
+func closesAfterWhitespace(data []byte, start int) bool {
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+		case '}', ']':
+			return true
+		case '/':
+			if i+1 < len(data) && data[i+1] == '/' {
+				for i < len(data) && data[i] != '\n' {
+					i++
+				}
+				continue
+			}
+			if i+1 < len(data) && data[i+1] == '*' {
+				i += 2
+				for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+					i++
+				}
+				i++
+				continue
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
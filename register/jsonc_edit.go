@@ -0,0 +1,243 @@
+package register
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errJSONCSyntax reports input the JSONC scanner cannot follow.
+var errJSONCSyntax = errors.New("invalid JSON")
+
+// jsonMember is one "key": value pair of an object; offsets index the document.
+type jsonMember struct {
+	key        string
+	keyStart   int
+	valueStart int
+	valueEnd   int
+}
+
+// jsonObject is an object's members and the offset of its closing brace.
+type jsonObject struct {
+	members []jsonMember
+	end     int // offset of '}'
+}
+
+// setJSONCMember sets data[path[0]][path[1]]... to value and leaves every other
+// byte — key order, comments, formatting — as it was. Missing objects on the
+// path are created; a member that exists is replaced in place.
+func setJSONCMember(data []byte, path []string, value any) ([]byte, error) {
+	start := skipTrivia(data, 0)
+	if start >= len(data) || data[start] != '{' {
+		return nil, fmt.Errorf("%w: the top level is not an object", errJSONCSyntax)
+	}
+	unit := detectIndentUnit(data)
+
+	for depth, key := range path {
+		object, err := scanObject(data, start)
+		if err != nil {
+			return nil, err
+		}
+		var member *jsonMember
+		for i := range object.members {
+			if object.members[i].key == key {
+				member = &object.members[i]
+			}
+		}
+
+		remaining := value
+		for i := len(path) - 1; i > depth; i-- {
+			remaining = map[string]any{path[i]: remaining}
+		}
+		if member == nil {
+			return insertMember(data, object, start, key, remaining, unit)
+		}
+		if depth == len(path)-1 || data[member.valueStart] != '{' {
+			rendered, err := json.MarshalIndent(remaining, lineIndent(data, member.keyStart), unit)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling config: %w", err)
+			}
+			return splice(data, member.valueStart, member.valueEnd, rendered), nil
+		}
+		start = member.valueStart
+	}
+	return data, nil
+}
+
+// insertMember adds "key": value as the object's last member.
+func insertMember(data []byte, object jsonObject, objectStart int, key string, value any, unit string) ([]byte, error) {
+	indent := lineIndent(data, object.end) + unit
+	if len(object.members) > 0 {
+		indent = lineIndent(data, object.members[0].keyStart)
+	}
+	rendered, err := json.MarshalIndent(value, indent, unit)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	quotedKey, _ := json.Marshal(key)
+	member := string(quotedKey) + ": " + string(rendered)
+
+	if len(object.members) == 0 {
+		insertion := "\n" + indent + member + "\n" + lineIndent(data, object.end)
+		return splice(data, skipWhitespaceBackward(data, object.end, objectStart+1), object.end, []byte(insertion)), nil
+	}
+	last := object.members[len(object.members)-1]
+	after := skipTrivia(data, last.valueEnd)
+	if data[after] == ',' { // JSONC trailing comma: keep it, the new member follows it
+		return splice(data, after+1, after+1, []byte("\n"+indent+member+",")), nil
+	}
+	return splice(data, last.valueEnd, last.valueEnd, []byte(",\n"+indent+member)), nil
+}
+
+// scanObject reads the object starting at data[start] == '{'.
+func scanObject(data []byte, start int) (jsonObject, error) {
+	var object jsonObject
+	i := skipTrivia(data, start+1)
+	for i < len(data) && data[i] != '}' {
+		if data[i] != '"' {
+			return jsonObject{}, fmt.Errorf("%w: expected a key at offset %d", errJSONCSyntax, i)
+		}
+		keyEnd, err := scanString(data, i)
+		if err != nil {
+			return jsonObject{}, err
+		}
+		var key string
+		if err := json.Unmarshal(data[i:keyEnd], &key); err != nil {
+			return jsonObject{}, fmt.Errorf("%w: bad key at offset %d", errJSONCSyntax, i)
+		}
+		colon := skipTrivia(data, keyEnd)
+		if colon >= len(data) || data[colon] != ':' {
+			return jsonObject{}, fmt.Errorf("%w: expected ':' at offset %d", errJSONCSyntax, colon)
+		}
+		valueStart := skipTrivia(data, colon+1)
+		valueEnd, err := scanValue(data, valueStart)
+		if err != nil {
+			return jsonObject{}, err
+		}
+		object.members = append(object.members, jsonMember{key: key, keyStart: i, valueStart: valueStart, valueEnd: valueEnd})
+
+		i = skipTrivia(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			i = skipTrivia(data, i+1)
+		}
+	}
+	if i >= len(data) {
+		return jsonObject{}, fmt.Errorf("%w: unterminated object", errJSONCSyntax)
+	}
+	object.end = i
+	return object, nil
+}
+
+// scanValue returns the offset just past the value starting at data[start].
+func scanValue(data []byte, start int) (int, error) {
+	if start >= len(data) {
+		return 0, fmt.Errorf("%w: unexpected end", errJSONCSyntax)
+	}
+	switch data[start] {
+	case '"':
+		return scanString(data, start)
+	case '{':
+		object, err := scanObject(data, start)
+		if err != nil {
+			return 0, err
+		}
+		return object.end + 1, nil
+	case '[':
+		i := skipTrivia(data, start+1)
+		for i < len(data) && data[i] != ']' {
+			end, err := scanValue(data, i)
+			if err != nil {
+				return 0, err
+			}
+			i = skipTrivia(data, end)
+			if i < len(data) && data[i] == ',' {
+				i = skipTrivia(data, i+1)
+			}
+		}
+		if i >= len(data) {
+			return 0, fmt.Errorf("%w: unterminated array", errJSONCSyntax)
+		}
+		return i + 1, nil
+	}
+	i := start // number, true, false, null
+	for i < len(data) && bytes.IndexByte([]byte(",}] \t\r\n/"), data[i]) < 0 {
+		i++
+	}
+	if i == start {
+		return 0, fmt.Errorf("%w: unexpected %q at offset %d", errJSONCSyntax, data[start], start)
+	}
+	return i, nil
+}
+
+func scanString(data []byte, start int) (int, error) {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated string", errJSONCSyntax)
+}
+
+// skipTrivia skips whitespace and comments.
+func skipTrivia(data []byte, i int) int {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n':
+			i++
+		case bytes.HasPrefix(data[i:], []byte("//")):
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(data[i:], []byte("/*")):
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return len(data)
+			}
+			i += end + 4
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipWhitespaceBackward moves from end back over whitespace, but not
+// comments, to no further than floor.
+func skipWhitespaceBackward(data []byte, end, floor int) int {
+	for end > floor && bytes.IndexByte([]byte(" \t\r\n"), data[end-1]) >= 0 {
+		end--
+	}
+	return end
+}
+
+// lineIndent is the whitespace that starts the line containing offset.
+func lineIndent(data []byte, offset int) string {
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := lineStart
+	for end < offset && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[lineStart:end])
+}
+
+// detectIndentUnit returns the indentation of the first indented line, or two spaces.
+func detectIndentUnit(data []byte) string {
+	for _, line := range bytes.Split(data, []byte("\n"))[1:] {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && len(trimmed) < len(line) {
+			return string(line[:len(line)-len(trimmed)])
+		}
+	}
+	return "  "
+}
+
+func splice(data []byte, start, end int, insertion []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(insertion))
+	out = append(out, data[:start]...)
+	out = append(out, insertion...)
+	return append(out, data[end:]...)
+}
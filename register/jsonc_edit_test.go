@@ -0,0 +1,57 @@
+package register
+
+import "testing"
+
+func Test_setJSONCMember(t *testing.T) {
+	entry := map[string]any{"command": "new"}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "replaces an existing entry in place",
+			input: "{\n  // servers\n  \"mcpServers\": {\n    \"b\": {\"command\": \"x\"},\n    \"rest-api\": {\"command\": \"old\", \"args\": []}, // mine\n    \"a\": {}\n  },\n  \"z\": 1\n}\n",
+			want:  "{\n  // servers\n  \"mcpServers\": {\n    \"b\": {\"command\": \"x\"},\n    \"rest-api\": {\n      \"command\": \"new\"\n    }, // mine\n    \"a\": {}\n  },\n  \"z\": 1\n}\n",
+		},
+		{
+			name:  "appends to the servers object",
+			input: "{\n\t\"mcpServers\": {\n\t\t\"b\": {}\n\t}\n}",
+			want:  "{\n\t\"mcpServers\": {\n\t\t\"b\": {},\n\t\t\"rest-api\": {\n\t\t\t\"command\": \"new\"\n\t\t}\n\t}\n}",
+		},
+		{
+			name:  "keeps a trailing comma",
+			input: "{\n  \"mcpServers\": {\n    \"b\": {},\n  },\n}",
+			want:  "{\n  \"mcpServers\": {\n    \"b\": {},\n    \"rest-api\": {\n      \"command\": \"new\"\n    },\n  },\n}",
+		},
+		{
+			name:  "creates the servers object",
+			input: "{\n  \"theme\": \"dark\" // comment\n}\n",
+			want:  "{\n  \"theme\": \"dark\",\n  \"mcpServers\": {\n    \"rest-api\": {\n      \"command\": \"new\"\n    }\n  } // comment\n}\n",
+		},
+		{
+			name:  "fills an empty object",
+			input: "{}",
+			want:  "{\n  \"mcpServers\": {\n    \"rest-api\": {\n      \"command\": \"new\"\n    }\n  }\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setJSONCMember([]byte(tt.input), []string{"mcpServers", "rest-api"}, entry)
+			if err != nil {
+				t.Fatalf("setJSONCMember: %s", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_setJSONCMember_RejectsNonObject(t *testing.T) {
+	for _, input := range []string{"[]", `{"a": `, `{"a" 1}`} {
+		if _, err := setJSONCMember([]byte(input), []string{"mcpServers", "x"}, 1); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package register
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_stripJSONC(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"line comment", "{\"a\": 1 // note\n}", "{\"a\": 1 \n}"},
+		{"block comment", `{/* x */"a": 1}`, `{"a": 1}`},
+		{"trailing commas", "{\"a\": [1, 2,],\n}", "{\"a\": [1, 2]\n}"},
+		{"comment markers in strings", `{"url": "http://x/*y*/", "c": "a,}"}`, `{"url": "http://x/*y*/", "c": "a,}"}`},
+		{"escaped quote", `{"a": "say \"//\""}`, `{"a": "say \"//\""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripJSONC([]byte(tt.input))); got != tt.want {
+				t.Errorf("stripJSONC = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeZedConfig_KeepsJSONCComments(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "zed", "settings.json")
+	os.MkdirAll(filepath.Dir(configPath), 0o755)
+	os.WriteFile(configPath, []byte("// Zed settings\n{\n  \"theme\": \"One Dark\", // mine\n}\n"), 0o644)
+
+	if err := writeZedConfig(configPath, "rest-api", buildEntry("/bin/rest-api-mcp", []string{"--read-only"}, nil)); err != nil {
+		t.Fatalf("writeZedConfig: %s", err)
+	}
+
+	var settings struct {
+		Theme          string                    `json:"theme"`
+		ContextServers map[string]zedServerEntry `json:"context_servers"`
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "// Zed settings\n") || !strings.Contains(string(data), "// mine") {
+		t.Errorf("comments were dropped:\n%s", data)
+	}
+	if err := json.Unmarshal(stripJSONC(data), &settings); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	got := settings.ContextServers["rest-api"]
+	if settings.Theme != "One Dark" || got.Source != "custom" || got.Command != "/bin/rest-api-mcp" || !sliceEqual(got.Args, []string{"--read-only"}) {
+		t.Errorf("settings = %+v", settings)
+	}
+}
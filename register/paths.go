@@ -0,0 +1,101 @@
+package register
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// platform is what per-OS config paths are resolved against.
+type platform struct {
+	goos   string
+	home   string
+	getenv func(string) string
+}
+
+func currentPlatform() (platform, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return platform{}, fmt.Errorf("UserHomeDir: %w", err)
+	}
+	return platform{goos: runtime.GOOS, home: homeDir, getenv: os.Getenv}, nil
+}
+
+// appConfigDir is where desktop apps such as VS Code keep their settings:
+// %APPDATA% on Windows, ~/Library/Application Support on macOS and
+// $XDG_CONFIG_HOME (~/.config) elsewhere.
+func (p platform) appConfigDir() string {
+	switch p.goos {
+	case "windows":
+		if appData := p.getenv("APPDATA"); appData != "" {
+			return appData
+		}
+		return filepath.Join(p.home, "AppData", "Roaming")
+	case "darwin":
+		return filepath.Join(p.home, "Library", "Application Support")
+	}
+	return p.xdgConfigHome()
+}
+
+func (p platform) xdgConfigHome() string {
+	if configHome := p.getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return configHome
+	}
+	return filepath.Join(p.home, ".config")
+}
+
+// claudeDir is Claude Code's data directory: $CLAUDE_CONFIG_DIR, or ~/.claude
+// on every OS (%USERPROFILE%\.claude on Windows).
+func claudeDir(p platform) string {
+	if configDir := p.getenv("CLAUDE_CONFIG_DIR"); configDir != "" {
+		return configDir
+	}
+	return filepath.Join(p.home, ".claude")
+}
+
+// claudePath is Claude Code's user config: ~/.claude.json, or .claude.json in
+// $CLAUDE_CONFIG_DIR when that is set.
+func claudePath(p platform) string {
+	if configDir := p.getenv("CLAUDE_CONFIG_DIR"); configDir != "" {
+		return filepath.Join(configDir, ".claude.json")
+	}
+	return filepath.Join(p.home, ".claude.json")
+}
+
+// windsurfPath is ~/.codeium/windsurf/mcp_config.json on every OS.
+func windsurfPath(p platform) string {
+	return filepath.Join(p.home, ".codeium", "windsurf", "mcp_config.json")
+}
+
+// zedPath is Zed's user settings.json; Zed uses ~/.config/zed on macOS too.
+func zedPath(p platform) string {
+	if p.goos == "windows" {
+		return filepath.Join(p.appConfigDir(), "Zed", "settings.json")
+	}
+	return filepath.Join(p.xdgConfigHome(), "zed", "settings.json")
+}
+
+// codexPath is $CODEX_HOME/config.toml, ~/.codex/config.toml by default.
+func codexPath(p platform) string {
+	if codexHome := p.getenv("CODEX_HOME"); codexHome != "" {
+		return filepath.Join(codexHome, "config.toml")
+	}
+	return filepath.Join(p.home, ".codex", "config.toml")
+}
+
+// VS Code extension IDs of the clients that keep MCP settings in global storage.
+const (
+	clineExtension = "saoudrizwan.claude-dev"
+	rooExtension   = "rooveterinaryinc.roo-cline"
+)
+
+// vscodeExtensionDir is a VS Code extension's global storage directory.
+func vscodeExtensionDir(p platform, extensionID string) string {
+	return filepath.Join(p.appConfigDir(), "Code", "User", "globalStorage", extensionID)
+}
+
+// vscodeExtensionSettingsPath is a settings file in an extension's global storage.
+func vscodeExtensionSettingsPath(p platform, extensionID, fileName string) string {
+	return filepath.Join(vscodeExtensionDir(p, extensionID), "settings", fileName)
+}
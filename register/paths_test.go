@@ -0,0 +1,98 @@
+package register
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_platformPaths(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	home := filepath.FromSlash("/home/u")
+	tests := []struct {
+		name     string
+		platform platform
+		path     func(platform) string
+		want     string
+	}{
+		{"windsurf", platform{"linux", home, env(nil)}, windsurfPath, "/home/u/.codeium/windsurf/mcp_config.json"},
+		{"zed linux XDG", platform{"linux", home, env(map[string]string{"XDG_CONFIG_HOME": "/xdg"})}, zedPath, "/xdg/zed/settings.json"},
+		{"zed macOS", platform{"darwin", home, env(nil)}, zedPath, "/home/u/.config/zed/settings.json"},
+		{"zed windows", platform{"windows", home, env(map[string]string{"APPDATA": "/appdata"})}, zedPath, "/appdata/Zed/settings.json"},
+		{"vscode macOS", platform{"darwin", home, env(nil)}, func(p platform) string {
+			return vscodeExtensionSettingsPath(p, "ext", "mcp.json")
+		}, "/home/u/Library/Application Support/Code/User/globalStorage/ext/settings/mcp.json"},
+		{"vscode linux", platform{"linux", home, env(nil)}, func(p platform) string {
+			return vscodeExtensionSettingsPath(p, "ext", "mcp.json")
+		}, "/home/u/.config/Code/User/globalStorage/ext/settings/mcp.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.path(tt.platform); got != filepath.FromSlash(tt.want) {
+				t.Errorf("path = %q, want %q", got, filepath.FromSlash(tt.want))
+			}
+		})
+	}
+}
+
+func Test_clientTargets_UserPathPerOS(t *testing.T) {
+	home := filepath.FromSlash("/home/u")
+	platforms := map[string]platform{
+		"windows": {"windows", home, func(name string) string {
+			return map[string]string{"APPDATA": filepath.FromSlash("/home/u/AppData/Roaming")}[name]
+		}},
+		"darwin": {"darwin", home, func(string) string { return "" }},
+		"linux":  {"linux", home, func(string) string { return "" }},
+	}
+	want := map[string]map[string]string{
+		"claude":   {"windows": "/home/u/.claude.json", "darwin": "/home/u/.claude.json", "linux": "/home/u/.claude.json"},
+		"codex":    {"windows": "/home/u/.codex/config.toml", "darwin": "/home/u/.codex/config.toml", "linux": "/home/u/.codex/config.toml"},
+		"windsurf": {"windows": "/home/u/.codeium/windsurf/mcp_config.json", "darwin": "/home/u/.codeium/windsurf/mcp_config.json", "linux": "/home/u/.codeium/windsurf/mcp_config.json"},
+		"zed":      {"windows": "/home/u/AppData/Roaming/Zed/settings.json", "darwin": "/home/u/.config/zed/settings.json", "linux": "/home/u/.config/zed/settings.json"},
+		"cline": {
+			"windows": "/home/u/AppData/Roaming/Code/User/globalStorage/saoudrizwan.claude-dev/settings/cline_mcp_settings.json",
+			"darwin":  "/home/u/Library/Application Support/Code/User/globalStorage/saoudrizwan.claude-dev/settings/cline_mcp_settings.json",
+			"linux":   "/home/u/.config/Code/User/globalStorage/saoudrizwan.claude-dev/settings/cline_mcp_settings.json",
+		},
+		"roo": {
+			"windows": "/home/u/AppData/Roaming/Code/User/globalStorage/rooveterinaryinc.roo-cline/settings/mcp_settings.json",
+			"darwin":  "/home/u/Library/Application Support/Code/User/globalStorage/rooveterinaryinc.roo-cline/settings/mcp_settings.json",
+			"linux":   "/home/u/.config/Code/User/globalStorage/rooveterinaryinc.roo-cline/settings/mcp_settings.json",
+		},
+	}
+	for _, target := range clientTargets() {
+		for goos, p := range platforms {
+			got, err := resolveConfigPath(p, target, "user", "")
+			if err != nil {
+				t.Fatalf("%s on %s: %s", target.name, goos, err)
+			}
+			if expected := filepath.FromSlash(want[target.name][goos]); got != expected {
+				t.Errorf("%s on %s = %q, want %q", target.name, goos, got, expected)
+			}
+		}
+	}
+}
+
+func Test_claudePath_ConfigDir(t *testing.T) {
+	p := platform{"linux", filepath.FromSlash("/home/u"), func(name string) string {
+		return map[string]string{"CLAUDE_CONFIG_DIR": filepath.FromSlash("/work/claude")}[name]
+	}}
+	if got := claudePath(p); got != filepath.FromSlash("/work/claude/.claude.json") {
+		t.Errorf("claudePath = %q", got)
+	}
+	if got := claudeDir(p); got != filepath.FromSlash("/work/claude") {
+		t.Errorf("claudeDir = %q", got)
+	}
+}
+
+func Test_findClient(t *testing.T) {
+	for _, name := range []string{"claude", "codex", "windsurf", "zed", "cline", "roo"} {
+		if _, err := findClient(name); err != nil {
+			t.Errorf("findClient(%q): %s", name, err)
+		}
+	}
+	if _, err := findClient("vim"); err == nil {
+		t.Error("expected an error for an unknown client")
+	}
+}
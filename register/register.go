@@ -1,6 +1,7 @@
 package register
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -26,10 +27,13 @@ func Run(info ServerInfo, args []string) {
 		os.Exit(1)
 	}
 
+	flags, remaining, err := parseRegisterFlags(args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
 	directory := "."
 	var serverArgs []string
-
-	remaining := args[1:]
 	if scope == "project" {
 		directory, serverArgs = parseProjectArgs(remaining)
 	} else {
@@ -42,20 +46,45 @@ func Run(info ServerInfo, args []string) {
 		os.Exit(1)
 	}
 
-	configPath, err := resolveConfigPath(scope, directory)
+	serverName := info.Name
+	if flags.name != "" {
+		serverName = flags.name
+	}
+
+	entry := buildEntry(binaryPath, serverArgs, flags.env)
+	if flags.client == "all" {
+		if !registerAll(os.Stdout, serverName, scope, directory, entry, flags.dryRun) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	target, err := findClient(flags.client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	configPath, err := target.configPath(scope, directory)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: resolving config path: %s\n", err)
 		os.Exit(1)
 	}
 
-	entry := buildEntry(binaryPath, serverArgs)
-
-	if err := writeConfig(configPath, info.Name, entry); err != nil {
+	if flags.dryRun {
+		output, err := target.render(configPath, serverName, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		printDryRun(os.Stdout, configPath, output)
+		return
+	}
+	if err := target.write(configPath, serverName, entry); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: writing config: %s\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Registered %q in %s\n", info.Name, configPath)
+	fmt.Printf("Registered %q in %s\n", serverName, configPath)
 }
 
 // parseProjectArgs splits remaining args into directory and server args.
@@ -117,68 +146,95 @@ func DeriveServerName(binaryPath string) string {
 	return name
 }
 
-func resolveConfigPath(scope string, directory string) (string, error) {
-	if scope == "user" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("UserHomeDir: %w", err)
-		}
-		return filepath.Join(homeDir, ".claude.json"), nil
-	}
-	absDir, err := filepath.Abs(directory)
-	if err != nil {
-		return "", fmt.Errorf("Abs(%s): %w", directory, err)
-	}
-	return filepath.Join(absDir, ".mcp.json"), nil
-}
-
 type mcpServerEntry struct {
-	Command string   `json:"command"`
-	Args    []string `json:"args"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
 }
 
-func buildEntry(binaryPath string, serverArgs []string) mcpServerEntry {
+// buildEntry copies serverArgs and env, so the entry never aliases the caller's data.
+func buildEntry(binaryPath string, serverArgs []string, env map[string]string) mcpServerEntry {
 	args := make([]string, len(serverArgs))
 	copy(args, serverArgs)
 
-	return mcpServerEntry{
+	entry := mcpServerEntry{
 		Command: binaryPath,
 		Args:    args,
 	}
+	if len(env) > 0 {
+		entry.Env = make(map[string]string, len(env))
+		for key, value := range env {
+			entry.Env[key] = value
+		}
+	}
+	return entry
+}
+
+// renderFunc returns the content of configPath with the server's entry set.
+type renderFunc func(configPath, serverName string, entry mcpServerEntry) ([]byte, error)
+
+// writeRendered writes what render produces to configPath.
+func writeRendered(render renderFunc, configPath, serverName string, entry mcpServerEntry) error {
+	output, err := render(configPath, serverName, entry)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(configPath, output)
 }
 
+// writeConfig sets mcpServers.<serverName> in a Claude Code config file.
 func writeConfig(configPath string, serverName string, entry mcpServerEntry) error {
-	config := make(map[string]interface{})
+	return writeRendered(renderConfig, configPath, serverName, entry)
+}
+
+func renderConfig(configPath, serverName string, entry mcpServerEntry) ([]byte, error) {
+	return renderJSONConfig(configPath, "mcpServers", serverName, entry)
+}
 
+// renderJSONConfig sets <serversKey>.<serverName> in a JSON config file. Only
+// that entry's text changes: key order, formatting and the comments and
+// trailing commas some clients accept (JSONC, as in Zed's and VS Code's
+// settings) are kept.
+func renderJSONConfig(configPath, serversKey, serverName string, entry any) ([]byte, error) {
 	data, err := os.ReadFile(configPath)
-	if err == nil {
-		if err := json.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("parsing %s: %w", configPath, err)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", configPath, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		output, err := json.MarshalIndent(map[string]any{serversKey: map[string]any{serverName: entry}}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshaling config: %w", err)
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("reading %s: %w", configPath, err)
+		return append(output, '\n'), nil
 	}
 
-	servers, ok := config["mcpServers"].(map[string]interface{})
-	if !ok {
-		servers = make(map[string]interface{})
+	var config map[string]any
+	if err := json.Unmarshal(stripJSONC(data), &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
 	}
-	servers[serverName] = entry
-	config["mcpServers"] = servers
-
-	output, err := json.MarshalIndent(config, "", "  ")
+	output, err := setJSONCMember(data, []string{serversKey, serverName}, entry)
 	if err != nil {
-		return fmt.Errorf("marshaling config: %w", err)
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+	if err := json.Unmarshal(stripJSONC(output), &config); err != nil {
+		return nil, fmt.Errorf("updating %s would leave invalid JSON (edit it by hand): %w", configPath, err)
 	}
+	return output, nil
+}
 
-	// Atomic write: write to temp file then rename to avoid data loss on crash
+// writeFileAtomic writes to a temp file then renames it, so a crash never
+// leaves a half-written config behind. Missing parent directories are created.
+func writeFileAtomic(configPath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(configPath), err)
+	}
 	tmpFile, err := os.CreateTemp(filepath.Dir(configPath), ".mcp-register-*.tmp")
 	if err != nil {
 		return fmt.Errorf("creating temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
 
-	if _, err := tmpFile.Write(append(output, '\n')); err != nil {
+	if _, err := tmpFile.Write(data); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpPath)
 		return fmt.Errorf("writing temp file: %w", err)
@@ -200,6 +256,19 @@ func printUsageAndExit() {
   %s register project [directory]                          # → <directory>/.mcp.json
   %s register user                                         # → ~/.claude.json
   %s register project . -- --base-url http://localhost:8080 # with forwarded args
-`, bin, bin, bin)
+  %s register user --client codex                          # → ~/.codex/config.toml
+  %s register user --client all                            # every detected client
+  %s register project --name billing-api -- --base-url https://billing.example.com
+
+Options (before --):
+  --client NAME   MCP client to register with: claude (default), codex, windsurf, zed,
+                  cline, roo, or all (every installed client)
+  --name NAME     Server name in the client config (default: rest-api); register
+                  several instances with different names and flags
+  --dry-run       Print the config path and the content that would be written,
+                  without changing anything
+  --env KEY=VALUE Environment variable for the server (repeatable); keeps secrets
+                  such as REST_API_MCP_AUTH_TOKEN out of the args
+`, bin, bin, bin, bin, bin, bin)
 	os.Exit(1)
 }
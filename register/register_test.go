@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -163,7 +164,7 @@ func Test_writeConfig_InvalidJSON(t *testing.T) {
 }
 
 func Test_buildEntry_DirectBinaryCommand(t *testing.T) {
-	entry := buildEntry("/usr/bin/rest-api-mcp", []string{"--base-url", "http://localhost"})
+	entry := buildEntry("/usr/bin/rest-api-mcp", []string{"--base-url", "http://localhost"}, nil)
 
 	if entry.Command != "/usr/bin/rest-api-mcp" {
 		t.Errorf("command = %q, want binary path", entry.Command)
@@ -175,7 +176,7 @@ func Test_buildEntry_DirectBinaryCommand(t *testing.T) {
 }
 
 func Test_buildEntry_EmptyArgs(t *testing.T) {
-	entry := buildEntry("/bin/myserver", nil)
+	entry := buildEntry("/bin/myserver", nil, nil)
 
 	if entry.Command != "/bin/myserver" {
 		t.Errorf("command = %q, want /bin/myserver", entry.Command)
@@ -190,7 +191,7 @@ func Test_buildEntry_DoesNotMutateInput(t *testing.T) {
 	originalCopy := make([]string, len(original))
 	copy(originalCopy, original)
 
-	entry := buildEntry("/bin/server", original)
+	entry := buildEntry("/bin/server", original, nil)
 	entry.Args[0] = "mutated"
 
 	if !sliceEqual(original, originalCopy) {
@@ -200,7 +201,8 @@ func Test_buildEntry_DoesNotMutateInput(t *testing.T) {
 
 func Test_resolveConfigPath_Project(t *testing.T) {
 	tmpDir := t.TempDir()
-	got, err := resolveConfigPath("project", tmpDir)
+	claude, _ := findClient("claude")
+	got, err := resolveConfigPath(platform{}, claude, "project", tmpDir)
 	if err != nil {
 		t.Fatalf("resolveConfigPath: %s", err)
 	}
@@ -211,17 +213,25 @@ func Test_resolveConfigPath_Project(t *testing.T) {
 }
 
 func Test_resolveConfigPath_User(t *testing.T) {
-	got, err := resolveConfigPath("user", "")
+	homeDir, _ := os.UserHomeDir()
+	claude, _ := findClient("claude")
+	got, err := resolveConfigPath(platform{goos: "linux", home: homeDir, getenv: func(string) string { return "" }}, claude, "user", "")
 	if err != nil {
 		t.Fatalf("resolveConfigPath: %s", err)
 	}
-	homeDir, _ := os.UserHomeDir()
 	want := filepath.Join(homeDir, ".claude.json")
 	if got != want {
 		t.Errorf("got %q, want %q", got, want)
 	}
 }
 
+func Test_resolveConfigPath_NoProjectConfig(t *testing.T) {
+	codex, _ := findClient("codex")
+	if _, err := resolveConfigPath(platform{}, codex, "project", "."); err == nil || !strings.Contains(err.Error(), "no per-project MCP configuration") {
+		t.Errorf("expected a no project config error, got %v", err)
+	}
+}
+
 func sliceEqual(a, b []string) bool {
 	if a == nil && b == nil {
 		return true
@@ -239,3 +249,26 @@ func sliceEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func Test_writeConfig_RoundTripsEnv(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".mcp.json")
+	entry := buildEntry("/bin/server", []string{"--base-url", "http://localhost"}, map[string]string{"REST_API_MCP_AUTH_TOKEN": "secret"})
+	if err := writeConfig(configPath, "test-server", entry); err != nil {
+		t.Fatalf("writeConfig: %s", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	var config struct {
+		MCPServers map[string]mcpServerEntry `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	got := config.MCPServers["test-server"]
+	if got.Env["REST_API_MCP_AUTH_TOKEN"] != "secret" || len(got.Env) != 1 {
+		t.Errorf("env = %v, want the token", got.Env)
+	}
+}
@@ -0,0 +1,85 @@
+package register
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Registration is one MCP server entry found in a client's configuration.
+type Registration struct {
+	Client     string
+	ConfigPath string
+	Name       string
+	Command    string // empty for entries without a local command (e.g. remote URLs)
+}
+
+// FindRegistrations lists the server entries in every client's user config
+// and in the project configs of directory. Config files that exist but cannot
+// be read are reported as errors; missing files are skipped.
+func FindRegistrations(directory string) ([]Registration, []error) {
+	var registrations []Registration
+	var errs []error
+	for _, target := range clientTargets() {
+		for _, scope := range []string{"user", "project"} {
+			if scope == "project" && target.projectFile == "" {
+				continue
+			}
+			configPath, err := target.configPath(scope, directory)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.name, err))
+				continue
+			}
+			servers, err := readServers(configPath, target.serversKey)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target.name, err))
+				continue
+			}
+			for _, name := range sortedKeys(servers) {
+				registration := Registration{Client: target.name, ConfigPath: configPath, Name: name}
+				if server, ok := servers[name].(map[string]any); ok {
+					registration.Command, _ = server["command"].(string)
+				}
+				registrations = append(registrations, registration)
+			}
+		}
+	}
+	return registrations, errs
+}
+
+// readServers returns the serversKey table of a JSON(C) or TOML config file;
+// nil when the file does not exist.
+func readServers(configPath, serversKey string) (map[string]any, error) {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", configPath, err)
+	}
+
+	config := make(map[string]any)
+	if strings.HasSuffix(configPath, ".toml") {
+		_, err = toml.Decode(string(data), &config)
+	} else if len(strings.TrimSpace(string(data))) > 0 {
+		err = json.Unmarshal(stripJSONC(data), &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+	servers, _ := config[serversKey].(map[string]any)
+	return servers, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package register
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_FindRegistrations(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("APPDATA", filepath.Join(home, "AppData"))
+	t.Setenv("CODEX_HOME", "")
+
+	if err := writeConfig(filepath.Join(project, ".mcp.json"), "billing-api", buildEntry("/bin/rest-api-mcp", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeCodexConfig(filepath.Join(home, ".codex", "config.toml"), "rest-api", buildEntry("/opt/rest-api-mcp", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(home, ".codeium", "windsurf"), 0o755)
+	os.WriteFile(windsurfPath(platform{home: home}), []byte("{broken"), 0o644)
+
+	registrations, errs := FindRegistrations(project)
+
+	want := map[string]Registration{
+		"claude": {Client: "claude", ConfigPath: filepath.Join(project, ".mcp.json"), Name: "billing-api", Command: "/bin/rest-api-mcp"},
+		"codex":  {Client: "codex", ConfigPath: filepath.Join(home, ".codex", "config.toml"), Name: "rest-api", Command: "/opt/rest-api-mcp"},
+	}
+	if len(registrations) != len(want) {
+		t.Fatalf("registrations = %+v, want %d", registrations, len(want))
+	}
+	for _, registration := range registrations {
+		if registration != want[registration.Client] {
+			t.Errorf("registration = %+v, want %+v", registration, want[registration.Client])
+		}
+	}
+	if len(errs) != 1 {
+		t.Errorf("errs = %v, want one for the broken windsurf config", errs)
+	}
+}
@@ -0,0 +1,143 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxExpiry is the longest validity SigV4 allows for a presigned URL.
+const MaxExpiry = 7 * 24 * time.Hour
+
+// Config holds the credentials and endpoint of an S3-compatible store.
+type Config struct {
+	Endpoint        string // e.g. http://localhost:9000 for MinIO; empty means AWS S3 in Region
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // temporary credentials only
+	PathStyle       bool   // address buckets as /bucket/key instead of bucket.host/key
+}
+
+// Presign returns a SigV4 query-signed URL that allows method (GET or PUT) on
+// bucket/key until now+expiry, without any further credentials. Only the Host
+// header is signed, so a PUT may carry any Content-Type.
+func Presign(cfg Config, method, bucket, key string, expiry time.Duration, now time.Time) (string, error) {
+	if method != "GET" && method != "PUT" {
+		return "", fmt.Errorf("unsupported method %q (expected GET or PUT)", method)
+	}
+	if bucket == "" || key == "" {
+		return "", fmt.Errorf("bucket and key are required")
+	}
+	if expiry < time.Second || expiry > MaxExpiry {
+		return "", fmt.Errorf("expiry must be between 1s and %s", MaxExpiry)
+	}
+	endpoint, err := objectURL(cfg, bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	now = now.UTC()
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    cfg.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       fmt.Sprint(int64(expiry / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if cfg.SessionToken != "" {
+		query["X-Amz-Security-Token"] = cfg.SessionToken
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		endpoint.EscapedPath(),
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := []byte("AWS4" + cfg.SecretAccessKey)
+	for _, part := range []string{date, cfg.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	endpoint.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return endpoint.String(), nil
+}
+
+// objectURL addresses the object virtual-hosted style on AWS, or path style
+// with a custom endpoint, PathStyle, or a bucket name containing dots (which
+// would not match the wildcard TLS certificate).
+func objectURL(cfg Config, bucket, key string) (*url.URL, error) {
+	endpoint := cfg.Endpoint
+	pathStyle := cfg.PathStyle || endpoint != "" || strings.Contains(bucket, ".")
+	if endpoint == "" {
+		host := "s3." + cfg.Region + ".amazonaws.com"
+		if cfg.Region == "us-east-1" {
+			host = "s3.amazonaws.com"
+		}
+		endpoint = "https://" + host
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	path := "/" + key
+	if pathStyle {
+		path = "/" + bucket + path
+	} else {
+		base.Host = bucket + "." + base.Host
+	}
+	path = strings.TrimSuffix(base.Path, "/") + path
+	return &url.URL{Scheme: base.Scheme, Host: base.Host, Path: path, RawPath: uriEncode(path, false)}, nil
+}
+
+func canonicalQueryString(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = uriEncode(name, true) + "=" + uriEncode(query[name], true)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, the
+// way SigV4 canonicalizes; slashes are kept in paths.
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,84 @@
+// Package selfupdate reports the running build and replaces the binary with
+// the latest GitHub release, for the version and update subcommands.
+package selfupdate
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// develVersion is the module version of a binary built from a checkout
+// without a tag.
+const develVersion = "(devel)"
+
+// Build describes the running binary.
+type Build struct {
+	Version   string // module version, e.g. v0.4.0; (devel) for an untagged build
+	Commit    string // VCS revision; empty when the build has no VCS stamp
+	Time      string // commit time, RFC 3339
+	Modified  bool   // built from a checkout with uncommitted changes
+	GoVersion string
+	Platform  string // GOOS/GOARCH
+}
+
+// CurrentBuild reads the build information the Go toolchain embedded in the
+// binary: the module version go install or a tagged build sets, and the VCS
+// stamp of a build from a checkout.
+func CurrentBuild() Build {
+	return buildFrom(debug.ReadBuildInfo())
+}
+
+// buildFrom reads info, as debug.ReadBuildInfo returns it; without build
+// information or a module version the version is develVersion.
+func buildFrom(info *debug.BuildInfo, ok bool) Build {
+	build := Build{Version: develVersion, GoVersion: runtime.Version(), Platform: runtime.GOOS + "/" + runtime.GOARCH}
+	if !ok || info == nil {
+		return build
+	}
+	if info.Main.Version != "" {
+		build.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Commit = setting.Value
+		case "vcs.time":
+			build.Time = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// String renders the build for the version subcommand.
+func (b Build) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "rest-api-mcp %s\n", b.Version)
+	if b.Commit != "" {
+		commit := b.Commit
+		if b.Modified {
+			commit += " (modified)"
+		}
+		fmt.Fprintf(&builder, "commit:   %s\n", commit)
+	}
+	if b.Time != "" {
+		fmt.Fprintf(&builder, "built:    %s\n", b.Time)
+	}
+	fmt.Fprintf(&builder, "go:       %s\n", b.GoVersion)
+	fmt.Fprintf(&builder, "platform: %s\n", b.Platform)
+	return builder.String()
+}
+
+// Released reports whether the build carries a release version rather than
+// a development or pseudo-version, so it can be compared with releases.
+func (b Build) Released() bool {
+	if strings.Contains(b.Version, "+dirty") {
+		return false
+	}
+	version, _, _ := strings.Cut(b.Version, "+")
+	_, ok := parseVersion(version)
+	return ok && !pseudoVersion.MatchString(version)
+}
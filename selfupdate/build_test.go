@@ -0,0 +1,68 @@
+package selfupdate
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func Test_Build_Released(t *testing.T) {
+	for version, want := range map[string]bool{
+		"v0.4.0":                               true,
+		"v1.0.0-rc.1":                          true,
+		"(devel)":                              false,
+		"v0.4.1-0.20260102150405-abcdef123456": false,
+		"v0.0.0-20261016151402-911c3d56f8c7+dirty": false,
+		"v0.4.0+dirty": false,
+	} {
+		if got := (Build{Version: version}).Released(); got != want {
+			t.Errorf("Released(%q) = %t, want %t", version, got, want)
+		}
+	}
+}
+
+func Test_Build_String(t *testing.T) {
+	text := Build{Version: "v0.4.0", Commit: "abc123", Modified: true, Time: "2026-01-02T15:04:05Z", GoVersion: "go1.25.0", Platform: "linux/amd64"}.String()
+	for _, want := range []string{"rest-api-mcp v0.4.0\n", "commit:   abc123 (modified)\n", "built:    2026-01-02T15:04:05Z\n", "go:       go1.25.0\n", "platform: linux/amd64\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if text := (Build{Version: "(devel)", GoVersion: "go1.25.0", Platform: "linux/amd64"}).String(); strings.Contains(text, "commit") {
+		t.Errorf("expected no commit line without a VCS stamp, got:\n%s", text)
+	}
+}
+
+func Test_CurrentBuild(t *testing.T) {
+	build := CurrentBuild()
+	if build.Version == "" || build.GoVersion == "" || !strings.Contains(build.Platform, "/") {
+		t.Errorf("expected version, Go version and platform, got %+v", build)
+	}
+}
+
+func Test_buildFrom_VersionFallback(t *testing.T) {
+	stamp := []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}, {Key: "vcs.modified", Value: "true"}}
+	tests := []struct {
+		name       string
+		info       *debug.BuildInfo
+		ok         bool
+		want       string
+		wantCommit string
+	}{
+		{"tagged module", &debug.BuildInfo{Main: debug.Module{Version: "v0.4.0"}}, true, "v0.4.0", ""},
+		{"checkout build", &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}, Settings: stamp}, true, "(devel)", "abc123"},
+		{"no module version", &debug.BuildInfo{}, true, "(devel)", ""},
+		{"no build information", nil, false, "(devel)", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := buildFrom(tt.info, tt.ok)
+			if build.Version != tt.want || build.Commit != tt.wantCommit {
+				t.Errorf("buildFrom = %+v, want version %q and commit %q", build, tt.want, tt.wantCommit)
+			}
+			if build.GoVersion == "" || build.Platform == "" {
+				t.Errorf("expected the Go version and platform without build information, got %+v", build)
+			}
+		})
+	}
+}
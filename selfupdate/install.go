@@ -0,0 +1,178 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxArchiveBytes bounds a downloaded release archive.
+const maxArchiveBytes = 200 << 20
+
+// checksumsAsset lists the SHA-256 of every archive of a release.
+const checksumsAsset = "checksums.txt"
+
+// Download fetches the release archive for goos/goarch, checks it against the
+// release's checksums and returns the binary inside it.
+func (u Updater) Download(ctx context.Context, release *Release, goos, goarch string) ([]byte, error) {
+	archive, sums := release.archive(goos, goarch), release.asset(checksumsAsset)
+	if archive == nil {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s", release.Tag, goos, goarch)
+	}
+	if sums == nil {
+		return nil, fmt.Errorf("release %s has no %s to verify the download with", release.Tag, checksumsAsset)
+	}
+	data, err := u.fetch(ctx, archive.URL, "application/octet-stream", maxArchiveBytes)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", archive.Name, err)
+	}
+	checksums, err := u.fetch(ctx, sums.URL, "text/plain", 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", checksumsAsset, err)
+	}
+	if err := verifyChecksum(archive.Name, data, checksums); err != nil {
+		return nil, err
+	}
+	return extractBinary(archive.Name, data, binaryName(goos))
+}
+
+// archive finds the archive built for goos/goarch, e.g.
+// rest-api-mcp_0.4.0_linux_amd64.tar.gz.
+func (r *Release) archive(goos, goarch string) *Asset {
+	platform := "_" + goos + "_" + goarch
+	for i, asset := range r.Assets {
+		if strings.HasSuffix(asset.Name, platform+".tar.gz") || strings.HasSuffix(asset.Name, platform+".zip") {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+func (r *Release) asset(name string) *Asset {
+	for i, asset := range r.Assets {
+		if asset.Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// verifyChecksum checks data against its line in a sha256sum-style list.
+func verifyChecksum(name string, data, checksums []byte) error {
+	sum := sha256.Sum256(data)
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("%s does not match its checksum in %s; the download is corrupt or was tampered with", name, checksumsAsset)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not listed in %s", name, checksumsAsset)
+}
+
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "rest-api-mcp.exe"
+	}
+	return "rest-api-mcp"
+}
+
+// extractBinary returns the file called binary from a .tar.gz or .zip archive.
+func extractBinary(archiveName string, data []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+		}
+		for _, file := range reader.File {
+			if path.Base(file.Name) != binary || file.FileInfo().IsDir() {
+				continue
+			}
+			opened, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("reading %s from %s: %w", binary, archiveName, err)
+			}
+			defer opened.Close()
+			return io.ReadAll(io.LimitReader(opened, maxArchiveBytes))
+		}
+		return nil, fmt.Errorf("%s has no %s", archiveName, binary)
+	}
+
+	gzipped, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+	}
+	archive := tar.NewReader(gzipped)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s has no %s", archiveName, binary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archiveName, err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == binary {
+			return io.ReadAll(io.LimitReader(archive, maxArchiveBytes))
+		}
+	}
+}
+
+// Replace swaps the binary at executable for binary, keeping its file mode.
+// The new file is written next to it and renamed over it, so a failure
+// leaves the old binary in place. Windows cannot overwrite a running binary,
+// so there the old one is moved aside to executable.old first.
+func Replace(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(executable), ".rest-api-mcp-update-*")
+	if err != nil {
+		return fmt.Errorf("writing next to %s: %w", executable, err)
+	}
+	_, err = file.Write(binary)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), info.Mode().Perm())
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("writing the new binary: %w", err)
+	}
+
+	old := ""
+	if filepath.Ext(executable) == ".exe" {
+		old = executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			os.Remove(file.Name())
+			return fmt.Errorf("moving the running binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(file.Name(), executable); err != nil {
+		os.Remove(file.Name())
+		if old != "" {
+			os.Rename(old, executable)
+		}
+		return fmt.Errorf("replacing %s: %w", executable, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	gzipped := gzip.NewWriter(&buffer)
+	archive := tar.NewWriter(gzipped)
+	for name, content := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		archive.Write([]byte(content))
+	}
+	archive.Close()
+	gzipped.Close()
+	return buffer.Bytes()
+}
+
+func releaseServer(t *testing.T, archiveName string, archive []byte, checksums string) (*httptest.Server, *Release) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + archiveName:
+			w.Write(archive)
+		case "/checksums.txt":
+			w.Write([]byte(checksums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &Release{Tag: "v0.5.0", Assets: []Asset{
+		{Name: "rest-api-mcp_0.5.0_darwin_arm64.tar.gz", URL: server.URL + "/other"},
+		{Name: archiveName, URL: server.URL + "/" + archiveName},
+		{Name: "checksums.txt", URL: server.URL + "/checksums.txt"},
+	}}
+}
+
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+}
+
+func Test_Updater_Download(t *testing.T) {
+	name := "rest-api-mcp_0.5.0_linux_amd64.tar.gz"
+	archive := tarGz(t, map[string]string{"README.md": "docs", "rest-api-mcp": "new binary"})
+	_, release := releaseServer(t, name, archive, checksumLine("other.zip", nil)+checksumLine(name, archive))
+
+	binary, err := Updater{}.Download(context.Background(), release, "linux", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(binary) != "new binary" {
+		t.Errorf("expected the binary from the archive, got %q", binary)
+	}
+}
+
+func Test_Updater_DownloadChecksumMismatch(t *testing.T) {
+	name := "rest-api-mcp_0.5.0_linux_amd64.tar.gz"
+	archive := tarGz(t, map[string]string{"rest-api-mcp": "new binary"})
+	_, release := releaseServer(t, name, archive, checksumLine(name, []byte("something else")))
+
+	if _, err := (Updater{}).Download(context.Background(), release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "does not match its checksum") {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+}
+
+func Test_Updater_DownloadMissingAssets(t *testing.T) {
+	release := &Release{Tag: "v0.5.0", Assets: []Asset{{Name: "rest-api-mcp_0.5.0_linux_amd64.tar.gz"}}}
+	if _, err := (Updater{}).Download(context.Background(), release, "freebsd", "amd64"); err == nil || !strings.Contains(err.Error(), "no archive for freebsd/amd64") {
+		t.Errorf("expected a missing archive error, got %v", err)
+	}
+	if _, err := (Updater{}).Download(context.Background(), release, "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "checksums.txt") {
+		t.Errorf("expected a missing checksums error, got %v", err)
+	}
+}
+
+func Test_extractBinary_Zip(t *testing.T) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	file, _ := archive.Create("rest-api-mcp.exe")
+	file.Write([]byte("windows binary"))
+	archive.Close()
+
+	binary, err := extractBinary("rest-api-mcp_0.5.0_windows_amd64.zip", buffer.Bytes(), binaryName("windows"))
+	if err != nil || string(binary) != "windows binary" {
+		t.Errorf("extractBinary = %q, %v", binary, err)
+	}
+	if _, err := extractBinary("a.tar.gz", tarGz(t, map[string]string{"LICENSE": "x"}), "rest-api-mcp"); err == nil || !strings.Contains(err.Error(), "has no rest-api-mcp") {
+		t.Errorf("expected a missing binary error, got %v", err)
+	}
+}
+
+func Test_Replace(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "rest-api-mcp")
+	if err := os.WriteFile(executable, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(executable, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(executable)
+	if err != nil || string(data) != "new" {
+		t.Errorf("expected the new binary, got %q, %v", data, err)
+	}
+	if info, err := os.Stat(executable); err != nil || runtime.GOOS != "windows" && info.Mode().Perm() != 0o755 {
+		t.Errorf("expected the mode kept, got %v, %v", info.Mode(), err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(executable))
+	if len(entries) != 1 {
+		t.Errorf("expected no leftover files, got %d entries", len(entries))
+	}
+}
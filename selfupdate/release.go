@@ -0,0 +1,149 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latestReleaseURL is the GitHub API endpoint of this project's latest release.
+const latestReleaseURL = "https://api.github.com/repos/lexandro/rest-api-mcp/releases/latest"
+
+// requestTimeout bounds the release lookup and each download.
+const requestTimeout = 5 * time.Minute
+
+// Release is a published GitHub release.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Updater looks up and downloads releases.
+type Updater struct {
+	LatestURL  string       // latest release API URL; empty means this project's on GitHub
+	HTTPClient *http.Client // nil uses a client with a five-minute timeout
+}
+
+func (u Updater) client() *http.Client {
+	if u.HTTPClient != nil {
+		return u.HTTPClient
+	}
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// Latest fetches the latest published release.
+func (u Updater) Latest(ctx context.Context) (*Release, error) {
+	latestURL := u.LatestURL
+	if latestURL == "" {
+		latestURL = latestReleaseURL
+	}
+	body, err := u.fetch(ctx, latestURL, "application/vnd.github+json", 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("looking up the latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("reading the latest release: %w", err)
+	}
+	if _, ok := parseVersion(release.Tag); !ok {
+		return nil, fmt.Errorf("the latest release has no version tag (got %q)", release.Tag)
+	}
+	return &release, nil
+}
+
+// fetch GETs url and returns at most limit bytes of a 200 response.
+func (u Updater) fetch(ctx context.Context, url, accept string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "rest-api-mcp-update")
+	resp, err := u.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, limit)
+	}
+	return body, nil
+}
+
+// Newer reports whether the release tag is a later version than current.
+// A pre-release sorts before its release; pre-releases of one version sort
+// by name.
+func Newer(tag, current string) bool {
+	latest, ok := parseVersion(tag)
+	if !ok {
+		return false
+	}
+	running, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+	for i := range 3 {
+		if latest.numbers[i] != running.numbers[i] {
+			return latest.numbers[i] > running.numbers[i]
+		}
+	}
+	switch {
+	case latest.prerelease == running.prerelease:
+		return false
+	case latest.prerelease == "":
+		return true
+	case running.prerelease == "":
+		return false
+	}
+	return latest.prerelease > running.prerelease
+}
+
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+// parseVersion parses a vMAJOR.MINOR.PATCH[-prerelease][+build] tag; the
+// leading v is optional.
+func parseVersion(text string) (version, bool) {
+	text = strings.TrimPrefix(text, "v")
+	text, _, _ = strings.Cut(text, "+")
+	core, prerelease, _ := strings.Cut(text, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	parsed := version{prerelease: prerelease}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return version{}, false
+		}
+		parsed.numbers[i] = number
+	}
+	return parsed, true
+}
+
+// pseudoVersion matches the suffix the Go toolchain gives untagged commits,
+// e.g. v0.4.1-0.20260102150405-abcdef123456.
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}$`)
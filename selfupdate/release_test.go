@@ -0,0 +1,67 @@
+package selfupdate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_Newer(t *testing.T) {
+	tests := []struct {
+		tag, current string
+		want         bool
+	}{
+		{"v0.5.0", "v0.4.9", true},
+		{"v0.4.10", "v0.4.9", true},
+		{"v1.0.0", "v0.9.0", true},
+		{"v0.4.0", "v0.4.0", false},
+		{"v0.3.9", "v0.4.0", false},
+		{"v0.4.0", "v0.4.0-rc.1", true},
+		{"v0.4.0-rc.1", "v0.4.0", false},
+		{"v0.4.0-rc.2", "v0.4.0-rc.1", true},
+		{"0.5.0", "v0.4.0", true},
+		{"v0.5.0", "(devel)", true},
+		{"nightly", "v0.4.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.tag, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %t, want %t", tt.tag, tt.current, got, tt.want)
+		}
+	}
+}
+
+func Test_Updater_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("unexpected Accept %q", r.Header.Get("Accept"))
+		}
+		w.Write([]byte(`{"tag_name":"v0.5.0","html_url":"https://example.com/r","assets":[{"name":"checksums.txt","browser_download_url":"https://example.com/c"}]}`))
+	}))
+	defer server.Close()
+
+	release, err := Updater{LatestURL: server.URL}.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Tag != "v0.5.0" || release.URL != "https://example.com/r" || len(release.Assets) != 1 || release.Assets[0].URL != "https://example.com/c" {
+		t.Errorf("unexpected release: %+v", release)
+	}
+}
+
+func Test_Updater_LatestFailures(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"status":  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusForbidden) },
+		"no tag":  func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"tag_name":"latest"}`)) },
+		"garbage": func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`<html>`)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			if _, err := (Updater{LatestURL: server.URL}).Latest(context.Background()); err == nil || !strings.Contains(err.Error(), "latest release") {
+				t.Errorf("expected a latest release error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// runHTTP serves the MCP server over HTTP so several agents can share one
+// process. Every session is bound to the same *mcp.Server, so tool handlers
+// may run concurrently and the sessions share the active profile, request
+// history and variables.
+func runHTTP(server *mcp.Server, transport TransportConfig) error {
+	if transport.ListenAddr == "" {
+		return fmt.Errorf("--listen is required for the %s transport", transport.Transport)
+	}
+	if (transport.TLSCertFile == "") != (transport.TLSKeyFile == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+
+	getServer := func(*http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	if transport.Transport == "sse" {
+		mux.Handle("/sse", mcp.NewSSEHandler(getServer, nil))
+	} else {
+		mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
+	}
+
+	var handler http.Handler = mux
+	if transport.AuthToken != "" {
+		handler = requireBearerToken(transport.AuthToken, mux)
+	}
+	handler = checkOrigin(transport.AllowedOrigins, isLoopback(transport.ListenAddr), handler)
+
+	httpServer := &http.Server{
+		Addr:              transport.ListenAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	var err error
+	if transport.TLSCertFile != "" {
+		err = httpServer.ListenAndServeTLS(transport.TLSCertFile, transport.TLSKeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return fmt.Errorf("serving %s transport on %s: %w", transport.Transport, transport.ListenAddr, err)
+}
+
+// requireBearerToken rejects requests that do not carry the expected bearer
+// token. The comparison is constant-time to avoid leaking the token length/prefix.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(provided, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="rest-api-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkOrigin rejects requests from browser pages outside allowed, so a web
+// page cannot drive the tools through the user's browser. Clients that are not
+// browsers send no Origin. On a loopback listener, a Host other than a
+// loopback name is refused too: a DNS-rebound page is same-origin with its own
+// host name and may send no Origin.
+func checkOrigin(allowed []string, loopbackOnly bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && !slices.ContainsFunc(allowed, func(candidate string) bool {
+			return strings.EqualFold(strings.TrimRight(candidate, "/"), origin)
+		}) {
+			http.Error(w, fmt.Sprintf("origin %s is not allowed (see --allowed-origins)", origin), http.StatusForbidden)
+			return
+		}
+		if loopbackOnly && !isLoopback(r.Host) {
+			http.Error(w, fmt.Sprintf("host %s is not allowed on a loopback listener", r.Host), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopback reports whether the host of a host[:port] address is localhost
+// or a loopback IP.
+func isLoopback(address string) bool {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
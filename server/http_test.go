@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/selfupdate"
+)
+
+func Test_RequireBearerToken(t *testing.T) {
+	handler := requireBearerToken("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"valid token", "Bearer s3cret", 200},
+		{"missing header", "", 401},
+		{"wrong token", "Bearer nope", 401},
+		{"wrong scheme", "Basic s3cret", 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/mcp", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_Run_UnknownTransport(t *testing.T) {
+	err := Run(New(), TransportConfig{Transport: "websocket"})
+	if err == nil {
+		t.Fatal("expected error for unknown transport")
+	}
+}
+
+func Test_Run_HTTPRequiresListenAddress(t *testing.T) {
+	err := Run(New(), TransportConfig{Transport: "http"})
+	if err == nil {
+		t.Fatal("expected error when --listen is missing")
+	}
+}
+
+func Test_CheckOrigin(t *testing.T) {
+	handler := checkOrigin([]string{"https://app.example.com/"}, true, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+
+	tests := []struct {
+		name       string
+		host       string
+		origin     string
+		wantStatus int
+	}{
+		{"no origin", "127.0.0.1:8808", "", 200},
+		{"allowed origin", "localhost:8808", "https://APP.example.com", 200},
+		{"other origin", "127.0.0.1:8808", "https://evil.example", 403},
+		{"null origin", "127.0.0.1:8808", "null", 403},
+		{"rebound host", "evil.example:8808", "", 403},
+		{"ipv6 loopback", "[::1]:8808", "", 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/mcp", nil)
+			request.Host = tt.host
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_New_ReportsBuildVersion(t *testing.T) {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := New().Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if got, want := session.InitializeResult().ServerInfo.Version, selfupdate.CurrentBuild().Version; got != want {
+		t.Errorf("server version = %q, want the build version %q", got, want)
+	}
+}
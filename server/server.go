@@ -2,20 +2,48 @@ package server
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/selfupdate"
 )
 
+// TransportConfig selects how the MCP server is exposed to clients.
+type TransportConfig struct {
+	Transport  string // "stdio" (default), "http" (streamable HTTP at /mcp), or "sse" (legacy SSE at /sse)
+	ListenAddr string // address for the HTTP transports, e.g. 127.0.0.1:8808
+	AuthToken  string // when set, HTTP clients must send "Authorization: Bearer <token>"
+	// AllowedOrigins lists the browser origins (e.g. https://app.example.com)
+	// whose requests are served; requests with any other Origin are rejected.
+	AllowedOrigins []string
+	TLSCertFile    string // serve HTTPS when both cert and key are set
+	TLSKeyFile     string
+}
+
+// New creates the MCP server, reporting the same build version as the version
+// subcommand. Clients may subscribe to any resource; the tools package
+// notifies subscribers when one changes.
 func New() *mcp.Server {
 	return mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "rest-api-mcp",
-			Version: "0.3.0",
+			Version: selfupdate.CurrentBuild().Version,
+		},
+		&mcp.ServerOptions{
+			SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+			UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
 		},
-		nil,
 	)
 }
 
-func Run(server *mcp.Server) error {
-	return server.Run(context.Background(), &mcp.StdioTransport{})
+func Run(server *mcp.Server, transport TransportConfig) error {
+	switch transport.Transport {
+	case "", "stdio":
+		return server.Run(context.Background(), &mcp.StdioTransport{})
+	case "http", "sse":
+		return runHTTP(server, transport)
+	default:
+		return fmt.Errorf("unknown transport %q (expected stdio, http, or sse)", transport.Transport)
+	}
 }
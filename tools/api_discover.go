@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type APIDiscoverInput struct {
+	BaseURL string `json:"baseUrl,omitempty" jsonschema:"Base URL to probe (default: the configured base URL)"`
+}
+
+const apiDiscoverDescription = "Bootstrap knowledge of an unfamiliar API: probe well-known locations for OpenAPI/Swagger documents " +
+	"and OAuth/OIDC metadata under a base URL and report what exists (title, version, path count, token endpoints)."
+
+// discoveryPaths are probed in order; the list favors the locations common
+// frameworks (FastAPI, Spring, ASP.NET, Express) serve by default.
+var discoveryPaths = []string{
+	"/.well-known/openapi.json",
+	"/openapi.json",
+	"/openapi.yaml",
+	"/swagger.json",
+	"/swagger/v1/swagger.json",
+	"/v3/api-docs",
+	"/api-docs",
+	"/.well-known/oauth-authorization-server",
+	"/.well-known/openid-configuration",
+}
+
+const (
+	discoveryProbeTimeout = 5 * time.Second
+	discoveryMaxBodyBytes = 2 * 1024 * 1024
+)
+
+func makeAPIDiscoverHandler(httpClient *client.Client, settings Settings, configuredBaseURL string) func(context.Context, *mcp.CallToolRequest, APIDiscoverInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input APIDiscoverInput) (*mcp.CallToolResult, any, error) {
+		baseURL := input.BaseURL
+		if baseURL == "" {
+			baseURL = configuredBaseURL
+		}
+		if baseURL == "" {
+			return errorResult("baseUrl is required (no --base-url configured)"), nil, nil
+		}
+		baseURL = strings.TrimRight(baseURL, "/")
+		if policyError := settings.Methods.check("GET"); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		headers, headerNote := settings.Headers.filter(map[string]string{"Accept": "application/json, application/yaml;q=0.9, */*;q=0.5"})
+
+		progress := newProgressReporter(ctx, req)
+		var found, missing, rendered []string
+		for i, path := range discoveryPaths {
+			if progress != nil {
+				progress(fmt.Sprintf("probing %s (%d/%d)", path, i+1, len(discoveryPaths)))
+			}
+			params := client.RequestParams{
+				Method:          "GET",
+				URL:             baseURL + path,
+				Headers:         headers,
+				Timeout:         discoveryProbeTimeout,
+				FollowRedirects: true,
+				CheckRedirect:   settings.URLs.redirectCheck(),
+				MaxResponseSize: discoveryMaxBodyBytes,
+			}
+			requestURL, err := httpClient.ResolveURL(params)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Invalid URL: %s", err)), nil, nil
+			}
+			if policyError := settings.URLs.check("GET", requestURL); policyError != "" {
+				missing = append(missing, fmt.Sprintf("%s (denied by policy)", path))
+				continue
+			}
+			if settings.DryRun {
+				request, err := httpClient.RenderRequest(ctx, params)
+				if err != nil {
+					return errorResult(fmt.Sprintf("Dry run failed: %s", err)), nil, nil
+				}
+				rendered = append(rendered, fmt.Sprintf("%s %s", request.Method, client.RedactURL(request.URL)))
+				continue
+			}
+			resp, err := httpClient.ExecuteRequest(ctx, params)
+			if err != nil {
+				if ctx.Err() != nil {
+					return errorResult(fmt.Sprintf("Discovery cancelled: %s", ctx.Err())), nil, nil
+				}
+				missing = append(missing, fmt.Sprintf("%s (error)", path))
+				continue
+			}
+			summary, isDocument := summarizeDiscoveryResponse(path, resp)
+			if !isDocument {
+				missing = append(missing, fmt.Sprintf("%s (%d)", path, resp.StatusCode))
+				continue
+			}
+			found = append(found, fmt.Sprintf("%s → %d %s, %d bytes\n  %s", path, resp.StatusCode, displayContentType(resp.ContentType), len(resp.Body), summary))
+		}
+
+		var builder strings.Builder
+		builder.WriteString(headerNote)
+		if settings.DryRun {
+			fmt.Fprintf(&builder, "[dry run — probes not sent]\n%s", strings.Join(rendered, "\n"))
+			if len(missing) > 0 {
+				fmt.Fprintf(&builder, "\n\nNot probed: %s", strings.Join(missing, ", "))
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
+			}, nil, nil
+		}
+		if len(found) == 0 {
+			fmt.Fprintf(&builder, "Nothing discovered at %s.", baseURL)
+		} else {
+			fmt.Fprintf(&builder, "Discovered at %s:\n\n%s", baseURL, strings.Join(found, "\n"))
+		}
+		if len(missing) > 0 {
+			fmt.Fprintf(&builder, "\n\nNot found: %s", strings.Join(missing, ", "))
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
+		}, nil, nil
+	}
+}
+
+// summarizeDiscoveryResponse describes a probed document in one line. The
+// second result is false when the response is not a usable document (non-2xx,
+// or an HTML catch-all page that many SPAs return for every path).
+func summarizeDiscoveryResponse(path string, resp *client.Response) (string, bool) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || mediaTypeOf(resp.ContentType) == "text/html" {
+		return "", false
+	}
+	body := resp.Body
+
+	if strings.Contains(path, "oauth-authorization-server") || strings.Contains(path, "openid-configuration") {
+		if !gjson.ValidBytes(body) {
+			return "", false
+		}
+		metadata := gjson.GetManyBytes(body, "issuer", "token_endpoint", "authorization_endpoint", "grant_types_supported")
+		summary := fmt.Sprintf("OAuth/OIDC issuer %s; token endpoint: %s", valueOrNone(metadata[0].String()), valueOrNone(metadata[1].String()))
+		if metadata[2].Exists() {
+			summary += "; authorization endpoint: " + metadata[2].String()
+		}
+		if metadata[3].Exists() {
+			var grants []string
+			for _, grant := range metadata[3].Array() {
+				grants = append(grants, grant.String())
+			}
+			summary += "; grants: " + strings.Join(grants, ", ")
+		}
+		return summary, true
+	}
+
+	if gjson.ValidBytes(body) {
+		spec := gjson.GetManyBytes(body, "openapi", "swagger", "info.title", "info.version", "paths")
+		specVersion := "OpenAPI " + spec[0].String()
+		if !spec[0].Exists() {
+			if !spec[1].Exists() {
+				return "JSON document (not an OpenAPI description)", true
+			}
+			specVersion = "Swagger " + spec[1].String()
+		}
+		pathCount := 0
+		spec[4].ForEach(func(key, value gjson.Result) bool {
+			pathCount++
+			return true
+		})
+		return fmt.Sprintf("%s: %q v%s, %d paths", specVersion, spec[2].String(), spec[3].String(), pathCount), true
+	}
+
+	return summarizeYAMLSpec(string(body)), true
+}
+
+// summarizeYAMLSpec extracts the spec version and title from a YAML OpenAPI
+// document with a line scan; enough for discovery without a YAML parser.
+func summarizeYAMLSpec(body string) string {
+	var specVersion, title string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case specVersion == "" && (strings.HasPrefix(line, "openapi:") || strings.HasPrefix(line, "swagger:")):
+			specVersion = strings.Trim(strings.TrimSpace(strings.SplitN(line, ":", 2)[1]), `"'`)
+			if strings.HasPrefix(line, "openapi:") {
+				specVersion = "OpenAPI " + specVersion
+			} else {
+				specVersion = "Swagger " + specVersion
+			}
+		case title == "" && strings.HasPrefix(trimmed, "title:") && line != trimmed:
+			title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "title:")), `"'`)
+		}
+	}
+	if specVersion == "" {
+		return "non-JSON document (not recognized as OpenAPI)"
+	}
+	return fmt.Sprintf("%s (YAML): %q", specVersion, title)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
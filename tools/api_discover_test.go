@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_APIDiscoverHandler_FindsSpecAndOAuthMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"openapi":"3.0.3","info":{"title":"Pet Store","version":"1.2.0"},"paths":{"/pets":{},"/pets/{id}":{}}}`))
+		case "/.well-known/openid-configuration":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"issuer":"https://auth.test","token_endpoint":"https://auth.test/token","grant_types_supported":["client_credentials"]}`))
+		case "/swagger.json":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>app shell</html>"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	handler := makeAPIDiscoverHandler(newTestClient(server.URL), Settings{}, server.URL+"/")
+	result, _, _ := handler(context.Background(), nil, APIDiscoverInput{})
+	text := extractText(result)
+
+	for _, want := range []string{
+		`OpenAPI 3.0.3: "Pet Store" v1.2.0, 2 paths`,
+		"OAuth/OIDC issuer https://auth.test; token endpoint: https://auth.test/token; grants: client_credentials",
+		"/swagger.json (200)",
+		"/openapi.yaml (404)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got: %s", want, text)
+		}
+	}
+}
+
+func Test_APIDiscoverHandler_RequiresBaseURL(t *testing.T) {
+	handler := makeAPIDiscoverHandler(newTestClient(""), Settings{}, "")
+
+	result, _, _ := handler(context.Background(), nil, APIDiscoverInput{})
+	if !result.IsError || !strings.Contains(extractText(result), "baseUrl is required") {
+		t.Errorf("expected baseUrl validation error, got: %s", extractText(result))
+	}
+}
+
+func Test_APIDiscoverHandler_AppliesPolicyAndDryRun(t *testing.T) {
+	var probes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+	policy, err := ParseURLPolicy("deny * */.well-known/**\ndefault allow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := makeAPIDiscoverHandler(newTestClient(""), Settings{URLs: policy, DryRun: true}, server.URL)
+	result, _, _ := handler(context.Background(), nil, APIDiscoverInput{})
+	text := extractText(result)
+
+	if probes != 0 {
+		t.Errorf("dry run sent %d probes", probes)
+	}
+	if !strings.Contains(text, "GET "+server.URL+"/openapi.json") || !strings.Contains(text, "/.well-known/openapi.json (denied by policy)") {
+		t.Errorf("unexpected dry run output: %s", text)
+	}
+}
+
+func Test_SummarizeDiscoveryResponse_YAMLSpec(t *testing.T) {
+	resp := &client.Response{
+		StatusCode:  200,
+		ContentType: "application/yaml",
+		Body:        []byte("openapi: 3.1.0\ninfo:\n  title: 'Billing API'\n  version: 2.0.0\npaths: {}\n"),
+	}
+
+	summary, isDocument := summarizeDiscoveryResponse("/openapi.yaml", resp)
+	if !isDocument {
+		t.Fatal("expected YAML spec to count as a document")
+	}
+	if summary != `OpenAPI 3.1.0 (YAML): "Billing API"` {
+		t.Errorf("unexpected summary: %s", summary)
+	}
+}
@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// API is a named upstream API that gets its own request tool, e.g. github_request.
+type API struct {
+	Name        string
+	Description string
+	Client      *client.Client
+	Config      client.Config
+	Settings    Settings
+}
+
+// ToolName returns the name of the API's request tool.
+func (a API) ToolName() string {
+	return a.Name + "_request"
+}
+
+func (a API) toolDescription() string {
+	desc := fmt.Sprintf("Call the %s API.", a.Name)
+	if a.Description != "" {
+		desc = fmt.Sprintf("Call the %s API: %s.", a.Name, strings.TrimRight(a.Description, "."))
+	}
+	return desc + " " + buildToolDescription(a.Config, a.Settings)
+}
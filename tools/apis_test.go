@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_API_ToolDescription(t *testing.T) {
+	api := API{
+		Name:        "github",
+		Description: "GitHub REST API for the acme org.",
+		Config:      client.Config{BaseURL: "https://api.github.com"},
+	}
+	if api.ToolName() != "github_request" {
+		t.Errorf("ToolName = %s", api.ToolName())
+	}
+	desc := api.toolDescription()
+	if !strings.HasPrefix(desc, "Call the github API: GitHub REST API for the acme org. Make HTTP requests.") {
+		t.Errorf("unexpected description prefix: %s", desc)
+	}
+	if !strings.Contains(desc, "Base URL: https://api.github.com") {
+		t.Errorf("expected the API's base URL in description, got: %s", desc)
+	}
+}
+
+func Test_Register_APIToolsUseTheirOwnClient(t *testing.T) {
+	var gotAuth string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte("from github " + r.URL.Path))
+	}))
+	defer github.Close()
+
+	githubConfig := client.Config{
+		BaseURL:        github.URL,
+		DefaultHeaders: map[string]string{"Authorization": "Bearer gh-token"},
+		Timeout:        5 * time.Second,
+	}
+	apis := []API{{
+		Name:     "github",
+		Client:   client.NewClient(githubConfig),
+		Config:   githubConfig,
+		Settings: Settings{FollowRedirects: true},
+	}}
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, newTestClient(""), client.Config{}, Settings{}, apis)
+	session := connectTestSession(t, mcpServer)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "github_request",
+		Arguments: map[string]any{"method": "GET", "url": "/repos/acme/app"},
+	})
+	if err != nil {
+		t.Fatalf("calling github_request: %v", err)
+	}
+	if !strings.Contains(extractText(result), "from github /repos/acme/app") {
+		t.Errorf("expected response from the github API, got: %s", extractText(result))
+	}
+	if gotAuth != "Bearer gh-token" {
+		t.Errorf("expected the API's default header, got %q", gotAuth)
+	}
+}
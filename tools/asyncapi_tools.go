@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+type subscribeHandler func(context.Context, *mcp.CallToolRequest, SubscribeInput) (*mcp.CallToolResult, any, error)
+
+// syncChannels registers one subscribe tool per channel of spec, or none when
+// spec is nil, and removes previously generated channel tools that no longer
+// exist. Tools whose name is taken are skipped, as in sync.
+func (g *generatedTools) syncChannels(mcpServer *mcp.Server, spec *openapi.AsyncSpec, taken []string, subscribe subscribeHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var names []string
+	if spec != nil {
+		for _, channel := range spec.Channels {
+			binding := channelBinding{channel: channel}
+			tool := binding.tool()
+			if slices.Contains(builtinToolNames, tool.Name) || slices.Contains(taken, tool.Name) || slices.Contains(g.names, tool.Name) || slices.Contains(names, tool.Name) {
+				continue
+			}
+			mcpServer.AddTool(tool, makeChannelHandler(binding, subscribe))
+			names = append(names, tool.Name)
+		}
+	}
+	var gone []string
+	for _, name := range g.channels {
+		if !slices.Contains(names, name) {
+			gone = append(gone, name)
+		}
+	}
+	if len(gone) > 0 {
+		mcpServer.RemoveTools(gone...)
+	}
+	g.channels = names
+}
+
+// channelBinding maps the input properties of a channel tool to its address
+// parameters and the subscribe options.
+type channelBinding struct {
+	channel openapi.Channel
+}
+
+// channelOptions are the subscribe inputs every channel tool takes, unless a
+// channel parameter has the same name.
+var channelOptions = map[string]map[string]any{
+	"maxEvents":  {"type": "integer", "description": "Stop after this many events (default 10, at most 100)"},
+	"duration":   {"type": "string", "description": "How long to listen, e.g. 1m (default 30s, at most 5m)"},
+	"jsonFilter": {"type": "string", "description": "GJSON path to extract from each JSON event"},
+	"until":      {"type": "string", "description": "Stop early once an event (its jsonFilter value with jsonFilter) contains this text"},
+}
+
+func (b channelBinding) toolName() string {
+	name := b.channel.ID
+	if name == "" {
+		name = strings.NewReplacer("{", "", "}", "").Replace(strings.Trim(b.channel.Name, "/"))
+	}
+	return sanitizeToolName("subscribe_" + name)
+}
+
+func (b channelBinding) address() string {
+	return b.channel.ServerURL + "/" + strings.TrimLeft(b.channel.Address, "/")
+}
+
+func (b channelBinding) tool() *mcp.Tool {
+	description := fmt.Sprintf("Subscribe to %s (%s)", b.address(), b.channel.Protocol)
+	if b.channel.Summary != "" {
+		description += ": " + strings.TrimRight(b.channel.Summary, ".")
+	}
+	description += "."
+	if len(b.channel.Messages) > 0 {
+		description += fmt.Sprintf(" Messages: %s.", strings.Join(b.channel.Messages, ", "))
+	}
+	openWorld := true
+	return &mcp.Tool{
+		Name:        b.toolName(),
+		Description: description + " Generated from the AsyncAPI description; events are returned like subscribe's.",
+		InputSchema: b.inputSchema(),
+		Annotations: &mcp.ToolAnnotations{OpenWorldHint: &openWorld},
+	}
+}
+
+func (b channelBinding) inputSchema() map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for _, parameter := range b.channel.Parameters {
+		property := map[string]any{"type": "string"}
+		if parameter.Description != "" {
+			property["description"] = parameter.Description
+		}
+		properties[parameter.Name] = property
+		required = append(required, parameter.Name)
+	}
+	options := maps.Clone(channelOptions)
+	if b.channel.Protocol == openapi.ProtocolWebSocket {
+		options["send"] = map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Text messages to send once connected, e.g. a subscribe request"}
+	}
+	for name, property := range options {
+		if _, taken := properties[name]; !taken {
+			properties[name] = property
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// request builds the subscribe input for the tool's arguments.
+func (b channelBinding) request(arguments map[string]json.RawMessage) (SubscribeInput, error) {
+	address := b.address()
+	isParameter := make(map[string]bool)
+	for _, parameter := range b.channel.Parameters {
+		isParameter[parameter.Name] = true
+		value, ok := arguments[parameter.Name]
+		if !ok {
+			return SubscribeInput{}, fmt.Errorf("channel parameter %s is required", parameter.Name)
+		}
+		address = strings.ReplaceAll(address, "{"+parameter.Name+"}", url.PathEscape(gjson.ParseBytes(value).String()))
+	}
+	input := SubscribeInput{URL: address, Protocol: b.channel.Protocol}
+	option := func(name string) (gjson.Result, bool) {
+		value, ok := arguments[name]
+		return gjson.ParseBytes(value), ok && !isParameter[name]
+	}
+	if value, ok := option("maxEvents"); ok {
+		input.MaxEvents = int(value.Int())
+	}
+	if value, ok := option("duration"); ok {
+		input.Duration = value.String()
+	}
+	if value, ok := option("jsonFilter"); ok {
+		input.JSONFilter = value.String()
+	}
+	if value, ok := option("until"); ok {
+		input.Until = value.String()
+	}
+	if value, ok := option("send"); ok && b.channel.Protocol == openapi.ProtocolWebSocket {
+		for _, message := range value.Array() {
+			input.Send = append(input.Send, message.String())
+		}
+	}
+	return input, nil
+}
+
+func makeChannelHandler(binding channelBinding, subscribe subscribeHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var arguments map[string]json.RawMessage
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &arguments); err != nil {
+				return errorResult(fmt.Sprintf("[%s] arguments must be a JSON object: %s", errInvalidRequest, err)), nil
+			}
+		}
+		input, err := binding.request(arguments)
+		if err != nil {
+			return errorResult(fmt.Sprintf("[%s] %s", errInvalidRequest, err)), nil
+		}
+		result, output, err := subscribe(ctx, req, input)
+		if result != nil && output != nil {
+			result.StructuredContent = output
+		}
+		return result, err
+	}
+}
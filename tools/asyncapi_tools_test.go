@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+const generatedAsyncSpec = `{"asyncapi": "2.6.0", "info": {"title": "Orders", "version": "1"},
+	"servers": {"api": {"url": "%s/v1", "protocol": "http"}},
+	"channels": {"orders/{orderId}/events": {"parameters": {"orderId": {"description": "Order ID"}},
+		"subscribe": {"operationId": "orderEvents", "summary": "Order status changes.", "message": {"name": "orderUpdated"}}}}}`
+
+func Test_RegisterAsyncAPITools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: orderUpdated\ndata: {\"path\":%q}\n\n", r.URL.Path)
+	}))
+	defer server.Close()
+	spec, err := openapi.ParseAsyncAPI([]byte(fmt.Sprintf(generatedAsyncSpec, server.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, newTestClient(""), client.Config{}, Settings{AsyncAPI: spec}, nil)
+	session := connectTestSession(t, mcpServer)
+	ctx := context.Background()
+
+	toolList, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tool *mcp.Tool
+	for _, listed := range toolList.Tools {
+		if listed.Name == "subscribe_orderEvents" {
+			tool = listed
+		}
+	}
+	if tool == nil || !strings.Contains(tool.Description, "Order status changes. Messages: orderUpdated.") {
+		t.Fatalf("expected a subscribe_orderEvents tool, got %+v", tool)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "subscribe_orderEvents", Arguments: map[string]any{"orderId": "a/1", "jsonFilter": "path"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := extractText(result); result.IsError || !strings.Contains(text, `orderUpdated: "/v1/orders/a/1/events"`) {
+		t.Errorf("unexpected output: %s", text)
+	}
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "subscribe_orderEvents", Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "[invalid_request] channel parameter orderId is required") {
+		t.Errorf("expected the missing parameter refused, got: %s", text)
+	}
+}
+
+func Test_RegisterAsyncAPITools_OmittedWithoutStreamingToolset(t *testing.T) {
+	spec, err := openapi.ParseAsyncAPI([]byte(fmt.Sprintf(generatedAsyncSpec, "http://localhost")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	toolsets, err := ParseToolsets("core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, newTestClient(""), client.Config{}, Settings{AsyncAPI: spec, Toolsets: toolsets}, nil)
+	toolList, err := connectTestSession(t, mcpServer).ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range toolList.Tools {
+		if strings.HasPrefix(tool.Name, "subscribe") {
+			t.Errorf("expected no subscribe tools with the core toolset, got %s", tool.Name)
+		}
+	}
+}
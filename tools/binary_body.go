@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// maxBinaryDepth bounds nesting in MessagePack and CBOR bodies, so a hostile
+// body cannot exhaust the stack.
+const maxBinaryDepth = 100
+
+// binaryBodyFormat names the binary format of a media type that can be shown
+// as JSON, or returns "".
+func binaryBodyFormat(contentType string) string {
+	mediaType := mediaTypeOf(contentType)
+	switch {
+	case mediaType == "application/msgpack" || mediaType == "application/x-msgpack" ||
+		mediaType == "application/vnd.msgpack" || strings.HasSuffix(mediaType, "+msgpack"):
+		return "msgpack"
+	case mediaType == "application/cbor" || strings.HasSuffix(mediaType, "+cbor"):
+		return "cbor"
+	}
+	return ""
+}
+
+// decodeBinaryBody replaces a MessagePack or CBOR body with its JSON form
+// (--decode-binary-bodies). Byte strings show as base64 and timestamps as
+// RFC 3339 strings. It returns a note for the output when the body cannot be
+// decoded and leaves the body as it was.
+func decodeBinaryBody(resp *client.Response) string {
+	format := binaryBodyFormat(resp.ContentType)
+	if format == "" || len(resp.Body) == 0 || resp.SavedPath != "" {
+		return ""
+	}
+	if resp.Truncated {
+		return fmt.Sprintf("[%s: the body is truncated, not decoded to JSON]\n", format)
+	}
+	reader := &binaryReader{data: resp.Body}
+	var value any
+	var err error
+	if format == "msgpack" {
+		value, err = reader.msgpackValue(0)
+	} else {
+		value, err = reader.cborValue(0)
+	}
+	if err == nil && reader.pos != len(reader.data) {
+		err = fmt.Errorf("%d trailing bytes after the value", len(reader.data)-reader.pos)
+	}
+	var decoded []byte
+	if err == nil {
+		decoded, err = json.Marshal(value)
+	}
+	if err != nil {
+		return fmt.Sprintf("[%s: cannot decode to JSON: %s]\n", format, err)
+	}
+	resp.Body = decoded
+	resp.ContentType = "application/json"
+	return ""
+}
+
+// binaryReader walks a MessagePack or CBOR body.
+type binaryReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *binaryReader) next(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("unexpected end of data at byte %d", r.pos)
+	}
+	chunk := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return chunk, nil
+}
+
+func (r *binaryReader) byte() (byte, error) {
+	chunk, err := r.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return chunk[0], nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes (1, 2, 4 or 8).
+func (r *binaryReader) uint(size int) (uint64, error) {
+	chunk, err := r.next(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	for _, b := range chunk {
+		value = value<<8 | uint64(b)
+	}
+	return value, nil
+}
+
+func (r *binaryReader) float(size int) (float64, error) {
+	bits, err := r.uint(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 2:
+		return float16(uint16(bits)), nil
+	case 4:
+		return float64(math.Float32frombits(uint32(bits))), nil
+	}
+	return math.Float64frombits(bits), nil
+}
+
+// capacity bounds a declared element count by the bytes left, so a forged
+// length cannot allocate more than the body could hold.
+func (r *binaryReader) capacity(count uint64) int {
+	return int(min(count, uint64(len(r.data)-r.pos)))
+}
+
+func float16(bits uint16) float64 {
+	exponent, mantissa := int(bits>>10&0x1f), float64(bits&0x3ff)
+	var value float64
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		value = math.Inf(1)
+		if mantissa != 0 {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if bits&0x8000 != 0 {
+		value = -value
+	}
+	return value
+}
+
+// jsonFloat keeps values JSON cannot hold (NaN, ±Inf) as strings.
+func jsonFloat(value float64) any {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Sprint(value)
+	}
+	return value
+}
+
+// jsonKey turns a map key into a JSON object name: strings as they are,
+// other keys in their JSON form.
+func jsonKey(key any) string {
+	if name, ok := key.(string); ok {
+		return name
+	}
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Sprint(key)
+	}
+	return string(encoded)
+}
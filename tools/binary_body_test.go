@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_decodeBinaryBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+		wantNote    string
+	}{
+		// MessagePack: {"id":7,"tags":["a"],"ok":true,"n":-3,"f":1.5,"raw":bin(0x01 0x02)}
+		{"msgpack map", "application/msgpack", "\x86\xa2id\x07\xa4tags\x91\xa1a\xa2ok\xc3\xa1n\xfd\xa1f\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00\xa3raw\xc4\x02\x01\x02",
+			`{"f":1.5,"id":7,"n":-3,"ok":true,"raw":"AQI=","tags":["a"]}`, ""},
+		{"msgpack wide ints", "application/x-msgpack", "\x92\xcf\xff\xff\xff\xff\xff\xff\xff\xff\xd1\xff\x38", `[18446744073709551615,-200]`, ""},
+		{"msgpack timestamp", "application/vnd.msgpack", "\xd6\xff\x00\x00\x00\x00", `"1970-01-01T00:00:00Z"`, ""},
+		{"msgpack nil and str8", "application/msgpack", "\x92\xc0\xd9\x03abc", `[null,"abc"]`, ""},
+		// CBOR examples from RFC 8949 Appendix A.
+		{"cbor map", "application/cbor", "\xa2\x61a\x01\x61b\x82\x02\x03", `{"a":1,"b":[2,3]}`, ""},
+		{"cbor negative and half float", "application/cbor", "\x83\x38\x63\xf9\x3e\x00\xf5", `[-100,1.5,true]`, ""},
+		{"cbor bignum", "application/cbor", "\xc2\x49\x01\x00\x00\x00\x00\x00\x00\x00\x00", `18446744073709551616`, ""},
+		{"cbor epoch time", "application/cbor", "\xc1\x1a\x51\x4b\x67\xb0", `"2013-03-21T20:04:00Z"`, ""},
+		{"cbor indefinite", "application/cbor", "\xbf\x63Fun\xf5\x63Amt\x21\xff", `{"Amt":-2,"Fun":true}`, ""},
+		{"cbor infinity", "application/cbor", "\xf9\x7c\x00", `"+Inf"`, ""},
+		{"cbor suffix type", "application/senml+cbor", "\x80", `[]`, ""},
+		{"truncated data", "application/cbor", "\x82\x01", "\x82\x01", "cbor: cannot decode to JSON: unexpected end of data"},
+		{"trailing bytes", "application/msgpack", "\x01\x02", "\x01\x02", "1 trailing bytes"},
+		{"deep nesting", "application/msgpack", strings.Repeat("\x91", maxBinaryDepth+2) + "\xc0", strings.Repeat("\x91", maxBinaryDepth+2) + "\xc0", "nesting deeper"},
+		{"other type untouched", "application/octet-stream", "\x80", "\x80", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &client.Response{ContentType: tt.contentType, Body: []byte(tt.body)}
+			note := decodeBinaryBody(resp)
+			if string(resp.Body) != tt.want {
+				t.Errorf("body = %q, want %q", resp.Body, tt.want)
+			}
+			if tt.wantNote == "" && note != "" || !strings.Contains(note, tt.wantNote) {
+				t.Errorf("note = %q, want %q", note, tt.wantNote)
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_DecodeBinaryBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/cbor")
+		w.Write([]byte("\xa1\x62id\x18\x2a")) // {"id":42}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		settings Settings
+		want     string
+	}{
+		{"decoded", Settings{DecodeBinaryBodies: true}, `{"id":42}`},
+		{"disabled", Settings{}, "[binary: application/cbor, 6 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeHandler(newTestClient(server.URL), tt.settings, NewHistory(10), NewStats(), NewVariables())
+			result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+			if text := extractText(result); !strings.Contains(text, tt.want) {
+				t.Errorf("result = %s, want it to contain %s", text, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// cborBreak ends an indefinite-length CBOR item.
+const cborBreak = 0xff
+
+var errCBORBreak = errors.New("unexpected CBOR break")
+
+// cborValue decodes one CBOR data item (RFC 8949) into JSON-ready Go values.
+func (r *binaryReader) cborValue(depth int) (any, error) {
+	if depth > maxBinaryDepth {
+		return nil, fmt.Errorf("nesting deeper than %d levels", maxBinaryDepth)
+	}
+	b, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	major, info := b>>5, b&0x1f
+	if b == cborBreak {
+		return nil, errCBORBreak
+	}
+	if major == 7 {
+		return r.cborSimple(info)
+	}
+	indefinite := info == 31
+	var argument uint64
+	if !indefinite {
+		if argument, err = r.cborArgument(info); err != nil {
+			return nil, err
+		}
+	} else if major < 2 || major == 6 {
+		return nil, fmt.Errorf("invalid indefinite length for CBOR major type %d", major)
+	}
+
+	switch major {
+	case 0:
+		return argument, nil
+	case 1:
+		if argument <= math.MaxInt64 {
+			return -1 - int64(argument), nil
+		}
+		negative := new(big.Int).Not(new(big.Int).SetUint64(argument)) // -1 - n
+		return json.Number(negative.String()), nil
+	case 2, 3:
+		data, err := r.cborString(major, argument, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == 2 {
+			return data, nil
+		}
+		return string(data), nil
+	case 4:
+		array := make([]any, 0, r.capacity(argument))
+		for i := uint64(0); indefinite || i < argument; i++ {
+			item, err := r.cborValue(depth + 1)
+			if indefinite && err == errCBORBreak {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		return array, nil
+	case 5:
+		object := make(map[string]any, r.capacity(argument))
+		for i := uint64(0); indefinite || i < argument; i++ {
+			key, err := r.cborValue(depth + 1)
+			if indefinite && err == errCBORBreak {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.cborValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			object[jsonKey(key)] = value
+		}
+		return object, nil
+	}
+	return r.cborTag(argument, depth)
+}
+
+// cborArgument reads the count or value that follows the initial byte.
+func (r *binaryReader) cborArgument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info <= 27:
+		return r.uint(1 << (info - 24))
+	}
+	return 0, fmt.Errorf("invalid CBOR additional information %d at byte %d", info, r.pos-1)
+}
+
+// cborString reads a byte or text string, joining the chunks of an
+// indefinite-length one.
+func (r *binaryReader) cborString(major byte, size uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return r.next(size)
+	}
+	var joined []byte
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if b == cborBreak {
+			return joined, nil
+		}
+		if b>>5 != major || b&0x1f == 31 {
+			return nil, fmt.Errorf("invalid chunk in an indefinite-length CBOR string at byte %d", r.pos-1)
+		}
+		chunkSize, err := r.cborArgument(b & 0x1f)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := r.next(chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		joined = append(joined, chunk...)
+	}
+}
+
+func (r *binaryReader) cborSimple(info byte) (any, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 24:
+		value, err := r.byte()
+		return fmt.Sprintf("simple(%d)", value), err
+	case 25, 26, 27:
+		value, err := r.float(1 << (info - 24))
+		return jsonFloat(value), err
+	}
+	if info < 20 {
+		return fmt.Sprintf("simple(%d)", info), nil
+	}
+	return nil, fmt.Errorf("invalid CBOR simple value %d at byte %d", info, r.pos-1)
+}
+
+// cborTag decodes the tagged item: epoch times become RFC 3339 strings and
+// bignums exact numbers; other tags are dropped in favor of their content.
+func (r *binaryReader) cborTag(tag uint64, depth int) (any, error) {
+	content, err := r.cborValue(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case 1: // epoch-based date/time
+		switch seconds := content.(type) {
+		case uint64:
+			return time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339), nil
+		case int64:
+			return time.Unix(seconds, 0).UTC().Format(time.RFC3339), nil
+		case float64:
+			whole, fraction := math.Modf(seconds)
+			return time.Unix(int64(whole), int64(fraction*1e9)).UTC().Format(time.RFC3339Nano), nil
+		}
+	case 2, 3: // unsigned and negative bignum, carried as a byte string
+		data, ok := content.([]byte)
+		if !ok {
+			break
+		}
+		number := new(big.Int).SetBytes(data)
+		if tag == 3 {
+			number.Not(number) // -1 - n
+		}
+		return json.Number(number.String()), nil
+	}
+	return content, nil
+}
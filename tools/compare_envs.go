@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type CompareEnvsInput struct {
+	Profiles     []string                `json:"profiles,omitempty" jsonschema:"Profiles to compare, the first being the baseline (default: all, in configuration order)"`
+	Method       string                  `json:"method" jsonschema:"HTTP method; each profile's method policy applies, so read-only profiles refuse writes"`
+	URL          string                  `json:"url" jsonschema:"Path relative to each profile's base URL, e.g. /users/42 (or a full URL, sent unchanged to every profile)"`
+	Headers      map[string]StringValues `json:"headers,omitempty" jsonschema:"Request headers, added to each profile's default headers"`
+	QueryParams  map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters"`
+	Body         string                  `json:"body,omitempty" jsonschema:"Request body"`
+	JSONFilter   string                  `json:"jsonFilter,omitempty" jsonschema:"GJSON path to compare instead of the whole JSON body"`
+	IgnoreFields []string                `json:"ignoreFields,omitempty" jsonschema:"GJSON paths left out of the body comparison, with everything below them, e.g. updatedAt or meta.requestId"`
+	AllowWrites  bool                    `json:"allowWrites,omitempty" jsonschema:"Required to send a method other than GET, HEAD, OPTIONS, PROPFIND or REPORT: it is sent to every selected profile at once"`
+}
+
+const compareEnvsDescription = "Send the same request to several configuration profiles (e.g. dev, staging, prod) at once and compare them side by side: " +
+	"status, latency and size per profile, then each body's differences from the first profile's, field by field for JSON. " +
+	"Use to check a deployment or find why an environment behaves differently."
+
+// CompareResult is one profile's outcome in the structured output of http_compare_envs.
+type CompareResult struct {
+	Profile     string   `json:"profile"`
+	Status      int      `json:"status,omitempty"`
+	LatencyMs   int64    `json:"latencyMs,omitempty"`
+	Size        int      `json:"size,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Differences []string `json:"differences,omitempty"` // from the baseline profile's body
+	Identical   bool     `json:"identical,omitempty"`   // same status and body as the baseline
+	DryRun      bool     `json:"dryRun,omitempty"`      // rendered, not sent, under the profile's --dry-run
+
+	statusText string
+	body       []byte
+	rendered   string // the dry-run rendering
+	headerNote string // headers the profile's --allowed-request-headers dropped
+}
+
+// CompareOutput is the structured output of http_compare_envs.
+type CompareOutput struct {
+	Baseline string          `json:"baseline"`
+	Results  []CompareResult `json:"results"`
+}
+
+// RegisterCompareEnvs adds http_compare_envs over profiles.
+func RegisterCompareEnvs(mcpServer *mcp.Server, profiles []Profile) {
+	openWorld := true
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "http_compare_envs",
+		Description: compareEnvsDescription + " Profiles: " + strings.Join(profileNames(profiles), ", ") + ".",
+		Annotations: &mcp.ToolAnnotations{OpenWorldHint: &openWorld},
+	}, makeCompareEnvsHandler(profiles))
+}
+
+func profileNames(profiles []Profile) []string {
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+func makeCompareEnvsHandler(profiles []Profile) func(context.Context, *mcp.CallToolRequest, CompareEnvsInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input CompareEnvsInput) (*mcp.CallToolResult, any, error) {
+		method := strings.ToUpper(input.Method)
+		if !validMethods[method] || input.URL == "" {
+			return errorResult("method (GET, POST, ...) and url are required"), nil, nil
+		}
+		if !readOnlyMethods[method] && !input.AllowWrites {
+			return errorResult(fmt.Sprintf("%s changes data and would be sent to every profile at once; set allowWrites to send it", method)), nil, nil
+		}
+		selected, err := selectProfiles(profiles, input.Profiles)
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+
+		request := HttpRequestInput{Method: method, URL: input.URL, Headers: input.Headers, QueryParams: input.QueryParams, Body: input.Body}
+		results := make([]CompareResult, len(selected))
+		var wg sync.WaitGroup
+		for i, profile := range selected {
+			wg.Go(func() {
+				results[i] = sendToProfile(ctx, profile, request)
+			})
+		}
+		wg.Wait()
+
+		output := CompareOutput{Baseline: selected[0].Name, Results: compareResults(results, input.JSONFilter, input.IgnoreFields)}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: formatComparison(method, input.URL, output)}},
+		}, output, nil
+	}
+}
+
+// selectProfiles returns the named profiles in the given order, or all of them.
+func selectProfiles(profiles []Profile, names []string) ([]Profile, error) {
+	if len(names) == 0 {
+		return profiles, nil
+	}
+	var selected []Profile
+	for _, name := range names {
+		index := slices.IndexFunc(profiles, func(profile Profile) bool { return profile.Name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("unknown profile %q (profiles: %s)", name, strings.Join(profileNames(profiles), ", "))
+		}
+		selected = append(selected, profiles[index])
+	}
+	return selected, nil
+}
+
+// sendToProfile runs the checks http_request runs — method policy, request
+// limits, header allowlist, URL policy and dry run — with the profile's
+// settings, then sends the request with its client.
+func sendToProfile(ctx context.Context, profile Profile, input HttpRequestInput) CompareResult {
+	result := CompareResult{Profile: profile.Name}
+	settings := profile.Settings
+	if policyError := settings.Methods.check(input.Method); policyError != "" {
+		result.Error = policyError
+		return result
+	}
+	if limitError := settings.Limits.check(input); limitError != "" {
+		result.Error = limitError
+		return result
+	}
+	headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+	result.headerNote = headerNote
+	params := client.RequestParams{
+		Method:          input.Method,
+		URL:             input.URL,
+		Headers:         headers,
+		Body:            input.Body,
+		QueryParams:     queryParams(input),
+		FollowRedirects: settings.FollowRedirects,
+		CheckRedirect:   settings.URLs.redirectCheck(),
+	}
+	requestURL, err := profile.Client.ResolveURL(params)
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid URL: %s", err)
+		return result
+	}
+	if policyError := settings.URLs.check(params.Method, requestURL); policyError != "" {
+		result.Error = policyError
+		return result
+	}
+	if settings.DryRun {
+		rendered, err := profile.Client.RenderRequest(ctx, params)
+		if err != nil {
+			result.Error = fmt.Sprintf("dry run failed: %s", err)
+			return result
+		}
+		result.DryRun, result.rendered = true, formatDryRun(rendered, input)
+		return result
+	}
+	started := time.Now()
+	resp, err := profile.Client.ExecuteRequest(ctx, params)
+	if err != nil {
+		result.Error = fmt.Sprintf("[%s] %s", classifyError(err), err)
+		return result
+	}
+	result.Status, result.statusText, result.body = resp.StatusCode, resp.StatusText, resp.Body
+	result.LatencyMs, result.Size = time.Since(started).Milliseconds(), int(totalBodySize(resp))
+	return result
+}
+
+// compareResults diffs every body against the first profile's.
+func compareResults(results []CompareResult, jsonFilter string, ignore []string) []CompareResult {
+	if jsonFilter != "" {
+		for i := range results {
+			if gjson.ValidBytes(results[i].body) {
+				results[i].body = []byte(gjson.GetBytes(results[i].body, jsonFilter).Raw)
+			}
+		}
+	}
+	baseline := results[0]
+	for i := range results[1:] {
+		result := &results[i+1]
+		if result.Error != "" || baseline.Error != "" || result.DryRun || baseline.DryRun {
+			continue
+		}
+		result.Differences = diffBodies(baseline.body, result.body, ignore)
+		result.Identical = result.Status == baseline.Status && len(result.Differences) == 0
+	}
+	return results
+}
+
+func formatComparison(method, url string, output CompareOutput) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s %s on %d profiles\n\n", method, url, len(output.Results))
+	builder.WriteString("| profile | status | latency | size |\n|---|---|---|---|\n")
+	for _, result := range output.Results {
+		if result.Error != "" {
+			fmt.Fprintf(&builder, "| %s | failed: %s | | |\n", result.Profile, strings.ReplaceAll(result.Error, "|", "\\|"))
+			continue
+		}
+		if result.DryRun {
+			fmt.Fprintf(&builder, "| %s | dry run, not sent | | |\n", result.Profile)
+			continue
+		}
+		fmt.Fprintf(&builder, "| %s | %d %s | %dms | %d bytes |\n", result.Profile, result.Status, result.statusText, result.LatencyMs, result.Size)
+	}
+
+	if len(output.Results) > 1 {
+		fmt.Fprintf(&builder, "\nCompared with %s:", output.Baseline)
+	}
+	for _, result := range output.Results[1:] {
+		switch {
+		case result.Error != "" || output.Results[0].Error != "":
+			fmt.Fprintf(&builder, "\n- %s: not compared (a request failed)", result.Profile)
+		case result.DryRun || output.Results[0].DryRun:
+			fmt.Fprintf(&builder, "\n- %s: not compared (dry run)", result.Profile)
+		case result.Identical:
+			fmt.Fprintf(&builder, "\n- %s: identical", result.Profile)
+		case len(result.Differences) == 0:
+			fmt.Fprintf(&builder, "\n- %s: same body, status %d instead of %d", result.Profile, result.Status, output.Results[0].Status)
+		default:
+			fmt.Fprintf(&builder, "\n- %s:", result.Profile)
+			if result.Status != output.Results[0].Status {
+				fmt.Fprintf(&builder, " status %d instead of %d;", result.Status, output.Results[0].Status)
+			}
+			for _, difference := range result.Differences {
+				builder.WriteString("\n  " + difference)
+			}
+		}
+	}
+	for _, result := range output.Results {
+		if result.headerNote != "" {
+			fmt.Fprintf(&builder, "\n\n%s: %s", result.Profile, strings.TrimSpace(result.headerNote))
+		}
+		if result.rendered != "" {
+			fmt.Fprintf(&builder, "\n\n%s: %s", result.Profile, result.rendered)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func testProfile(name, baseURL string, settings Settings) Profile {
+	cfg := client.Config{BaseURL: baseURL, Timeout: 5 * time.Second, MaxResponseSize: 4096}
+	return Profile{Name: name, Client: client.NewClient(cfg), Config: cfg, Settings: settings}
+}
+
+func Test_CompareEnvsHandler(t *testing.T) {
+	env := func(body string, status int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/users/1" {
+				t.Errorf("path = %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	dev := env(`{"id":1,"name":"Alice","tags":["a"],"updatedAt":"1"}`, 200)
+	staging := env(`{"id":1,"name":"Alice","tags":["a"],"updatedAt":"2"}`, 200)
+	prod := env(`{"id":1,"name":"Alicia","tags":["a","b"],"updatedAt":"3"}`, 200)
+	profiles := []Profile{
+		testProfile("dev", dev.URL, Settings{}),
+		testProfile("staging", staging.URL, Settings{}),
+		testProfile("prod", prod.URL, Settings{Methods: MethodPolicy{ReadOnly: true}}),
+	}
+	handler := makeCompareEnvsHandler(profiles)
+
+	result, out, _ := handler(context.Background(), nil, CompareEnvsInput{Method: "get", URL: "/users/1", IgnoreFields: []string{"updatedAt"}})
+	text := extractText(result)
+	for _, want := range []string{"| dev | 200 OK |", "- staging: identical", "name: \"Alice\" → \"Alicia\"", "tags.1: (missing) → \"b\""} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "updatedAt") {
+		t.Errorf("ignored field compared:\n%s", text)
+	}
+	output := out.(CompareOutput)
+	if output.Baseline != "dev" || len(output.Results) != 3 || !output.Results[1].Identical || len(output.Results[2].Differences) != 2 {
+		t.Errorf("structured output = %+v", output)
+	}
+
+	result, _, _ = handler(context.Background(), nil, CompareEnvsInput{Method: "DELETE", URL: "/users/1"})
+	if !result.IsError || !strings.Contains(extractText(result), "set allowWrites") {
+		t.Errorf("expected DELETE to need allowWrites, got %s", extractText(result))
+	}
+
+	result, _, _ = handler(context.Background(), nil, CompareEnvsInput{Method: "DELETE", URL: "/users/1", Profiles: []string{"prod", "dev"}, AllowWrites: true})
+	if text := extractText(result); !strings.Contains(text, "| prod | failed: ") || !strings.Contains(text, "- dev: not compared") {
+		t.Errorf("expected the read-only prod profile to refuse DELETE:\n%s", text)
+	}
+
+	result, _, _ = handler(context.Background(), nil, CompareEnvsInput{Method: "GET", URL: "/users/1", Profiles: []string{"qa"}})
+	if !result.IsError || !strings.Contains(extractText(result), `unknown profile "qa"`) {
+		t.Errorf("expected an unknown profile error, got %s", extractText(result))
+	}
+}
+
+func Test_CompareEnvsHandler_ProfileDryRunAndLimits(t *testing.T) {
+	var sent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+	}))
+	defer server.Close()
+	handler := makeCompareEnvsHandler([]Profile{
+		testProfile("dev", server.URL, Settings{DryRun: true}),
+		testProfile("prod", server.URL, Settings{Limits: RequestLimits{MaxBodyBytes: 4}}),
+	})
+
+	result, _, _ := handler(context.Background(), nil, CompareEnvsInput{Method: "POST", URL: "/users", Body: `{"name":"x"}`, AllowWrites: true})
+	text := extractText(result)
+	if sent != 0 {
+		t.Errorf("server received %d requests", sent)
+	}
+	for _, want := range []string{"| dev | dry run, not sent |", "dev: [dry run — request not sent]\nPOST " + server.URL + "/users", "| prod | failed: request body is 12 bytes"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+}
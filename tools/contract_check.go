@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+type ContractCheckInput struct {
+	Method           string                  `json:"method,omitempty" jsonschema:"HTTP method (default GET)"`
+	URL              string                  `json:"url" jsonschema:"Full URL or relative path (if base_url configured) of an operation in the OpenAPI description"`
+	Headers          map[string]StringValues `json:"headers,omitempty" jsonschema:"Request headers"`
+	QueryParams      map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters"`
+	Body             string                  `json:"body,omitempty" jsonschema:"Request body"`
+	MaxResponseBytes int64                   `json:"maxResponseBytes,omitempty" jsonschema:"Response size limit in bytes for this call, overriding the server default; the whole body is needed for the check"`
+}
+
+const contractCheckDescription = "Send a request and check the live response against the OpenAPI schema of its operation: " +
+	"undeclared fields, missing required fields, type mismatches and undeclared statuses. A lightweight contract test to run per endpoint."
+
+// ContractCheckOutput is the structured output of contract_check.
+type ContractCheckOutput struct {
+	Operation   string             `json:"operation"`
+	Status      int                `json:"status"`
+	ResponseKey string             `json:"responseKey,omitempty"` // the declared response the status matched, e.g. 200 or default
+	Mismatches  []openapi.Mismatch `json:"mismatches"`
+}
+
+func makeContractCheckHandler(httpClient *client.Client, settings Settings, stats *Stats) func(context.Context, *mcp.CallToolRequest, ContractCheckInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ContractCheckInput) (*mcp.CallToolResult, any, error) {
+		method := strings.ToUpper(input.Method)
+		if method == "" {
+			method = "GET"
+		}
+		if !validMethods[method] || input.URL == "" {
+			return requestError(errInvalidRequest, "a valid method and url are required")
+		}
+		if policyError := settings.Methods.check(method); policyError != "" {
+			return requestError(errPolicyDenied, policyError)
+		}
+		request := HttpRequestInput{Method: method, URL: input.URL, Headers: input.Headers, QueryParams: input.QueryParams, Body: input.Body}
+		if limitError := settings.Limits.check(request); limitError != "" {
+			return requestError(errRequestTooLarge, limitError)
+		}
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		params := client.RequestParams{
+			Method:          method,
+			URL:             input.URL,
+			Headers:         headers,
+			Body:            input.Body,
+			QueryParams:     queryParams(request),
+			FollowRedirects: settings.FollowRedirects,
+			CheckRedirect:   settings.URLs.redirectCheck(),
+			MaxResponseSize: input.MaxResponseBytes,
+		}
+		requestURL, err := httpClient.ResolveURL(params)
+		if err != nil {
+			return requestError(errInvalidRequest, fmt.Sprintf("Invalid URL: %s", err))
+		}
+		if policyError := settings.URLs.check(method, requestURL); policyError != "" {
+			return requestError(errPolicyDenied, policyError)
+		}
+		spec := settings.OpenAPI.Spec()
+		operation, ok := spec.Find(method, requestURL)
+		if !ok {
+			return requestError(errInvalidRequest, fmt.Sprintf("no operation in the OpenAPI description matches %s %s", method, requestURL))
+		}
+		if settings.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, params)
+			if err != nil {
+				return requestError(errInvalidRequest, fmt.Sprintf("Dry run failed: %s", err))
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatDryRun(rendered, request) + "\n\n[no response to check against " + operation.Name() + "]"}},
+			}, nil, nil
+		}
+
+		started := time.Now()
+		resp, err := httpClient.ExecuteRequest(ctx, params)
+		if ctx.Err() == nil {
+			stats.Record(requestURL, int64(len(input.Body)), resp, time.Since(started))
+		}
+		if err != nil {
+			return failedRequest(err, "", "")
+		}
+		if resp.Truncated {
+			return requestError(errResponseTooLarge, fmt.Sprintf("the response was truncated at %d bytes, too little to check; raise maxResponseBytes", len(resp.Body)))
+		}
+		mismatches, key := spec.Check(operation, resp.StatusCode, resp.Body)
+		output := ContractCheckOutput{Operation: operation.Name(), Status: resp.StatusCode, ResponseKey: key, Mismatches: mismatches}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatContractCheck(method, requestURL, resp.StatusText, output)}},
+		}, output, nil
+	}
+}
+
+func formatContractCheck(method, requestURL, statusText string, output ContractCheckOutput) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s %s → %s: %d %s", method, requestURL, output.Operation, output.Status, statusText)
+	if output.ResponseKey != "" && output.ResponseKey != fmt.Sprint(output.Status) {
+		fmt.Fprintf(&builder, " (declared as %s)", output.ResponseKey)
+	}
+	if len(output.Mismatches) == 0 {
+		builder.WriteString("\nMatches the declared schema.")
+		return builder.String()
+	}
+	noun := "mismatches"
+	if len(output.Mismatches) == 1 {
+		noun = "mismatch"
+	}
+	fmt.Fprintf(&builder, "\n%d %s with the declared schema:", len(output.Mismatches), noun)
+	for _, mismatch := range output.Mismatches {
+		builder.WriteString("\n- " + strings.ReplaceAll(mismatch.Kind, "_", " ") + ": ")
+		if mismatch.Path != "" {
+			builder.WriteString(mismatch.Path + " ")
+		}
+		builder.WriteString(mismatch.Detail)
+		if mismatch.Count > 1 {
+			fmt.Fprintf(&builder, " (×%d)", mismatch.Count)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const contractSpec = `{"openapi": "3.0.3", "paths": {"/users/{id}": {"get": {"operationId": "getUser", "responses": {
+	"200": {"content": {"application/json": {"schema": {"type": "object", "required": ["id", "name"],
+		"properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "roles": {"type": "array", "items": {"type": "string"}}}}}}},
+	"404": {"description": "Not found"}}}}}}`
+
+func Test_ContractCheckHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"id":1,"name":"Ann","roles":["admin"]}`))
+		case "/users/2":
+			w.Write([]byte(`{"id":"2","email":"b@example.com","roles":["a",7]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	handler := makeContractCheckHandler(newTestClient(server.URL), Settings{OpenAPI: openAPISource(t, contractSpec)}, NewStats())
+	tests := []struct {
+		name    string
+		url     string
+		want    []string
+		isError bool
+	}{
+		{"matches", "/users/1", []string{"→ getUser: 200 OK", "Matches the declared schema."}, false},
+		{"drift", "/users/2", []string{
+			"4 mismatches with the declared schema:",
+			"- missing required: name required field is missing",
+			"- type mismatch: id is string, declared integer",
+			"- undeclared field: email string field is not in the schema",
+			"- type mismatch: roles.# is integer, declared string",
+		}, false},
+		{"undeclared status", "/users/3", []string{"1 mismatch with", "- undeclared status: status 500 is not declared (declared: 200, 404)"}, false},
+		{"unknown operation", "/orders/1", []string{"[invalid_request] no operation in the OpenAPI description matches GET"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, _ := handler(context.Background(), nil, ContractCheckInput{URL: server.URL + tt.url})
+			text := extractText(result)
+			if result.IsError != tt.isError {
+				t.Errorf("IsError = %v: %s", result.IsError, text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("output lacks %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func Test_ContractCheckHandler_DryRunAndLimits(t *testing.T) {
+	var sent bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer server.Close()
+	settings := Settings{OpenAPI: openAPISource(t, contractSpec), DryRun: true, Limits: RequestLimits{MaxHeaders: 1}}
+	handler := makeContractCheckHandler(newTestClient(server.URL), settings, NewStats())
+
+	result, _, _ := handler(context.Background(), nil, ContractCheckInput{URL: server.URL + "/users/1"})
+	if text := extractText(result); sent || !strings.Contains(text, "[dry run — request not sent]\nGET "+server.URL+"/users/1") || !strings.Contains(text, "[no response to check against getUser]") {
+		t.Errorf("expected a rendered request, got: %s", text)
+	}
+
+	result, _, _ = handler(context.Background(), nil, ContractCheckInput{URL: server.URL + "/users/1", Headers: map[string]StringValues{"A": {"1"}, "B": {"2"}}})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "--max-request-headers") {
+		t.Errorf("expected the header limit to apply, got: %s", text)
+	}
+}
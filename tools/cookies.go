@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// formatCookieSummary lists the cookies set by the redirects followed and by
+// the final response, one line each with their attributes. Values are shown
+// as *** unless showValues is set.
+func formatCookieSummary(resp *client.Response, showValues bool) string {
+	var lines []string
+	for _, hop := range resp.Redirects {
+		lines = append(lines, describeCookies(http.Header{"Set-Cookie": hop.SetCookies}, showValues, fmt.Sprintf("[set-cookie from %s]", hop.URL))...)
+	}
+	lines = append(lines, describeCookies(resp.Headers, showValues, "[set-cookie]")...)
+	return strings.Join(lines, "\n")
+}
+
+func describeCookies(headers http.Header, showValues bool, prefix string) []string {
+	cookies := (&http.Response{Header: headers}).Cookies()
+	lines := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		value := "***"
+		if showValues || cookie.Value == "" {
+			value = cookie.Value
+		}
+		parts := []string{fmt.Sprintf("%s=%s", cookie.Name, value)}
+		if cookie.Domain != "" {
+			parts = append(parts, "domain="+cookie.Domain)
+		}
+		if cookie.Path != "" {
+			parts = append(parts, "path="+cookie.Path)
+		}
+		switch {
+		case cookie.MaxAge < 0:
+			parts = append(parts, "deleted")
+		case cookie.MaxAge > 0:
+			parts = append(parts, fmt.Sprintf("max-age=%s", time.Duration(cookie.MaxAge)*time.Second))
+		case !cookie.Expires.IsZero():
+			parts = append(parts, "expires="+cookie.Expires.UTC().Format(time.RFC3339))
+		default:
+			parts = append(parts, "session")
+		}
+		if cookie.Secure {
+			parts = append(parts, "Secure")
+		}
+		if cookie.HttpOnly {
+			parts = append(parts, "HttpOnly")
+		}
+		if sameSite := sameSiteName(cookie.SameSite); sameSite != "" {
+			parts = append(parts, "SameSite="+sameSite)
+		}
+		if cookie.Partitioned {
+			parts = append(parts, "Partitioned")
+		}
+		lines = append(lines, prefix+" "+strings.Join(parts, " "))
+	}
+	return lines
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_FormatCookieSummary(t *testing.T) {
+	resp := &client.Response{
+		Headers: http.Header{"Set-Cookie": {
+			"session=abc123; Domain=example.com; Path=/; Secure; HttpOnly; SameSite=Lax",
+			"theme=dark; Expires=Wed, 21 Oct 2026 07:28:00 GMT",
+			"old=; Max-Age=0",
+			"csrf=xyz; Max-Age=3600; SameSite=Strict",
+		}},
+		Redirects: []client.RedirectHop{{URL: "https://sso.example.com/auth", StatusCode: 302, SetCookies: []string{"sso=t0ken; Path=/"}}},
+	}
+
+	tests := []struct {
+		name       string
+		showValues bool
+		want       []string
+	}{
+		{"redacted", false, []string{
+			"[set-cookie from https://sso.example.com/auth] sso=*** path=/ session",
+			"[set-cookie] session=*** domain=example.com path=/ session Secure HttpOnly SameSite=Lax",
+			"[set-cookie] theme=*** expires=2026-10-21T07:28:00Z",
+			"[set-cookie] old= deleted",
+			"[set-cookie] csrf=*** max-age=1h0m0s SameSite=Strict",
+		}},
+		{"values shown", true, []string{"sso=t0ken", "session=abc123", "csrf=xyz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatCookieSummary(resp, tt.showValues)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in:\n%s", want, got)
+				}
+			}
+			if !tt.showValues && strings.Contains(got, "abc123") {
+				t.Errorf("expected values redacted, got:\n%s", got)
+			}
+		})
+	}
+
+	if got := formatCookieSummary(&client.Response{Headers: http.Header{}}, false); got != "" {
+		t.Errorf("expected no summary without cookies, got %q", got)
+	}
+}
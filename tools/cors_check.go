@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type CORSCheckInput struct {
+	URL            string   `json:"url" jsonschema:"Full URL or relative path (if base_url configured) of the endpoint the browser will call"`
+	Origin         string   `json:"origin" jsonschema:"Origin of the calling page, e.g. https://app.example.com"`
+	Method         string   `json:"method,omitempty" jsonschema:"Method of the actual request (default GET)"`
+	RequestHeaders []string `json:"requestHeaders,omitempty" jsonschema:"Non-simple headers the actual request sends, e.g. Content-Type, Authorization"`
+	Credentials    bool     `json:"credentials,omitempty" jsonschema:"Whether the browser request includes credentials (cookies, fetch credentials: include)"`
+}
+
+const corsCheckDescription = "Debug CORS: send the browser's preflight OPTIONS request for an origin/method/headers combination, " +
+	"summarize the Access-Control-* response headers, and flag misconfigurations that would make the browser block the call."
+
+// corsSimpleMethods never need to be listed in Access-Control-Allow-Methods.
+var corsSimpleMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true}
+
+var corsResponseHeaders = []string{
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Headers",
+	"Access-Control-Allow-Credentials",
+	"Access-Control-Max-Age",
+	"Access-Control-Expose-Headers",
+	"Vary",
+}
+
+type corsFinding struct {
+	Blocking bool
+	Message  string
+}
+
+func makeCORSCheckHandler(httpClient *client.Client, settings Settings) func(context.Context, *mcp.CallToolRequest, CORSCheckInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input CORSCheckInput) (*mcp.CallToolResult, any, error) {
+		if input.URL == "" {
+			return errorResult("url is required"), nil, nil
+		}
+		if input.Origin == "" {
+			return errorResult("origin is required"), nil, nil
+		}
+		method := strings.ToUpper(input.Method)
+		if method == "" {
+			method = "GET"
+		}
+		if policyError := settings.Methods.check("OPTIONS"); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+
+		headers := map[string]string{
+			"Origin":                        input.Origin,
+			"Access-Control-Request-Method": method,
+		}
+		if len(input.RequestHeaders) > 0 {
+			headers["Access-Control-Request-Headers"] = strings.ToLower(strings.Join(input.RequestHeaders, ","))
+		}
+		headers, headerNote := settings.Headers.filter(headers)
+
+		// Browsers never follow redirects on a preflight, so neither do we.
+		params := client.RequestParams{
+			Method:          "OPTIONS",
+			URL:             input.URL,
+			Headers:         headers,
+			FollowRedirects: false,
+		}
+		requestURL, err := httpClient.ResolveURL(params)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Invalid URL: %s", err)), nil, nil
+		}
+		if policyError := settings.URLs.check("OPTIONS", requestURL); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if settings.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, params)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Dry run failed: %s", err)), nil, nil
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatDryRun(rendered, HttpRequestInput{})}},
+			}, nil, nil
+		}
+
+		resp, err := httpClient.ExecuteRequest(ctx, params)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Preflight failed: %s", err)), nil, nil
+		}
+
+		findings := analyzeCORSPreflight(resp, input.Origin, method, input.RequestHeaders, input.Credentials)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatCORSReport(resp, input.Origin, method, input.RequestHeaders, findings)}},
+		}, nil, nil
+	}
+}
+
+// analyzeCORSPreflight applies the browser's preflight checks (Fetch standard)
+// to the response and returns blocking problems and non-blocking warnings.
+func analyzeCORSPreflight(resp *client.Response, origin, method string, requestHeaders []string, credentials bool) []corsFinding {
+	var findings []corsFinding
+	blocking := func(format string, args ...any) {
+		findings = append(findings, corsFinding{Blocking: true, Message: fmt.Sprintf(format, args...)})
+	}
+	warning := func(format string, args ...any) {
+		findings = append(findings, corsFinding{Message: fmt.Sprintf(format, args...)})
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		blocking("preflight returned %d; browsers require a 2xx status (is OPTIONS routed and unauthenticated?)", resp.StatusCode)
+	}
+
+	allowOrigin := resp.Headers.Get("Access-Control-Allow-Origin")
+	switch {
+	case allowOrigin == "":
+		blocking("Access-Control-Allow-Origin is missing")
+	case allowOrigin == "*" && credentials:
+		blocking("Access-Control-Allow-Origin is * but credentialed requests require the exact origin")
+	case allowOrigin != "*" && allowOrigin != origin:
+		blocking("Access-Control-Allow-Origin is %q, which does not match origin %q", allowOrigin, origin)
+	}
+	if allowOrigin == origin && !headerListContains(resp.Headers.Values("Vary"), "Origin") {
+		warning("origin is reflected without Vary: Origin; shared caches may serve this response to other origins")
+	}
+
+	if credentials && resp.Headers.Get("Access-Control-Allow-Credentials") != "true" {
+		blocking("credentialed request requires Access-Control-Allow-Credentials: true")
+	}
+
+	allowMethods := splitHeaderList(resp.Headers.Values("Access-Control-Allow-Methods"))
+	wildcardMethods := containsFold(allowMethods, "*") && !credentials
+	if !corsSimpleMethods[method] && !wildcardMethods && !containsFold(allowMethods, method) {
+		blocking("method %s is not listed in Access-Control-Allow-Methods", method)
+	}
+
+	allowHeaders := splitHeaderList(resp.Headers.Values("Access-Control-Allow-Headers"))
+	wildcardHeaders := containsFold(allowHeaders, "*") && !credentials
+	for _, header := range requestHeaders {
+		// Authorization is never covered by the * wildcard.
+		coveredByWildcard := wildcardHeaders && !strings.EqualFold(header, "Authorization")
+		if !coveredByWildcard && !containsFold(allowHeaders, header) {
+			blocking("request header %q is not listed in Access-Control-Allow-Headers", header)
+		}
+	}
+
+	if resp.Headers.Get("Access-Control-Max-Age") == "" {
+		warning("no Access-Control-Max-Age; browsers will repeat the preflight frequently (default cache is 5s)")
+	}
+	return findings
+}
+
+func formatCORSReport(resp *client.Response, origin, method string, requestHeaders []string, findings []corsFinding) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Preflight OPTIONS %s → %d %s\n", resp.RequestURL, resp.StatusCode, resp.StatusText)
+	fmt.Fprintf(&builder, "Origin: %s, method: %s", origin, method)
+	if len(requestHeaders) > 0 {
+		fmt.Fprintf(&builder, ", headers: %s", strings.Join(requestHeaders, ", "))
+	}
+	builder.WriteString("\n")
+
+	for _, name := range corsResponseHeaders {
+		value := strings.Join(resp.Headers.Values(name), ", ")
+		if value == "" {
+			value = "(absent)"
+		}
+		fmt.Fprintf(&builder, "\n%s: %s", name, value)
+	}
+
+	verdict := "ALLOWED"
+	for _, finding := range findings {
+		if finding.Blocking {
+			verdict = "BLOCKED"
+			break
+		}
+	}
+	fmt.Fprintf(&builder, "\n\nVerdict: %s", verdict)
+	for _, finding := range findings {
+		label := "warning"
+		if finding.Blocking {
+			label = "error"
+		}
+		fmt.Fprintf(&builder, "\n- %s: %s", label, finding.Message)
+	}
+	return builder.String()
+}
+
+// splitHeaderList flattens comma-separated header values into trimmed items.
+func splitHeaderList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if trimmed := strings.TrimSpace(item); trimmed != "" {
+				items = append(items, trimmed)
+			}
+		}
+	}
+	return items
+}
+
+func headerListContains(values []string, target string) bool {
+	return containsFold(splitHeaderList(values), target)
+}
+
+func containsFold(items []string, target string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_CORSCheckHandler_AllowedPreflight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "OPTIONS" || r.Header.Get("Access-Control-Request-Method") != "PUT" {
+			w.WriteHeader(400)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.Header().Set("Vary", "Origin")
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+
+	handler := makeCORSCheckHandler(newTestClient(server.URL), Settings{})
+	result, _, _ := handler(context.Background(), nil, CORSCheckInput{
+		URL:            server.URL + "/items",
+		Origin:         "https://app.example.com",
+		Method:         "put",
+		RequestHeaders: []string{"content-type"},
+	})
+
+	text := extractText(result)
+	if !strings.Contains(text, "Verdict: ALLOWED") {
+		t.Errorf("expected ALLOWED verdict, got: %s", text)
+	}
+	if !strings.Contains(text, "Access-Control-Allow-Methods: GET, PUT") {
+		t.Errorf("expected header summary, got: %s", text)
+	}
+}
+
+func Test_CORSCheckHandler_MissingInput(t *testing.T) {
+	handler := makeCORSCheckHandler(newTestClient(""), Settings{})
+
+	result, _, _ := handler(context.Background(), nil, CORSCheckInput{URL: "http://x.test"})
+	if !result.IsError || !strings.Contains(extractText(result), "origin is required") {
+		t.Errorf("expected origin validation error, got: %s", extractText(result))
+	}
+}
+
+func Test_CORSCheckHandler_AppliesPolicy(t *testing.T) {
+	var sent bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer server.Close()
+	policy, err := ParseURLPolicy("default deny")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := CORSCheckInput{URL: server.URL + "/items", Origin: "https://app.example.com"}
+
+	result, _, _ := makeCORSCheckHandler(newTestClient(""), Settings{URLs: policy})(context.Background(), nil, input)
+	if !result.IsError || sent {
+		t.Errorf("expected the policy to block the preflight, got: %s", extractText(result))
+	}
+
+	result, _, _ = makeCORSCheckHandler(newTestClient(""), Settings{DryRun: true})(context.Background(), nil, input)
+	if text := extractText(result); sent || !strings.Contains(text, "[dry run — request not sent]\nOPTIONS "+server.URL+"/items") || !strings.Contains(text, "Origin: https://app.example.com") {
+		t.Errorf("expected a rendered preflight, got: %s", text)
+	}
+}
+
+func Test_AnalyzeCORSPreflight_Misconfigurations(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		headers        map[string]string
+		method         string
+		requestHeaders []string
+		credentials    bool
+		wantMessage    string
+		wantBlocking   bool
+	}{
+		{"missing allow origin", 204, map[string]string{}, "GET", nil, false, "Access-Control-Allow-Origin is missing", true},
+		{"wildcard with credentials", 204, map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": "true"}, "GET", nil, true, "credentialed requests require the exact origin", true},
+		{"origin mismatch", 204, map[string]string{"Access-Control-Allow-Origin": "https://other.test"}, "GET", nil, false, "does not match origin", true},
+		{"method not allowed", 204, map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Methods": "GET"}, "DELETE", nil, false, "method DELETE is not listed", true},
+		{"authorization not covered by wildcard", 204, map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Headers": "*"}, "GET", []string{"Authorization"}, false, `"Authorization" is not listed`, true},
+		{"non-2xx preflight", 401, map[string]string{"Access-Control-Allow-Origin": "*"}, "GET", nil, false, "preflight returned 401", true},
+		{"reflected origin without vary", 204, map[string]string{"Access-Control-Allow-Origin": "https://app.test"}, "GET", nil, false, "without Vary: Origin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			for key, value := range tt.headers {
+				headers.Set(key, value)
+			}
+			resp := &client.Response{StatusCode: tt.status, Headers: headers}
+
+			findings := analyzeCORSPreflight(resp, "https://app.test", tt.method, tt.requestHeaders, tt.credentials)
+			for _, finding := range findings {
+				if strings.Contains(finding.Message, tt.wantMessage) {
+					if finding.Blocking != tt.wantBlocking {
+						t.Errorf("finding %q blocking = %v, want %v", finding.Message, finding.Blocking, tt.wantBlocking)
+					}
+					return
+				}
+			}
+			t.Errorf("expected finding containing %q, got %+v", tt.wantMessage, findings)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// formatDryRun renders a request that was resolved but not sent. Sensitive
+// header and query values are censored as in the tool description; multipart bodies
+// are summarized instead of dumped.
+func formatDryRun(rendered *client.RenderedRequest, input HttpRequestInput) string {
+	var builder strings.Builder
+	builder.WriteString("[dry run — request not sent]\n")
+	fmt.Fprintf(&builder, "%s %s", rendered.Method, client.RedactURL(rendered.URL))
+
+	names := make([]string, 0, len(rendered.Headers))
+	for name := range rendered.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range rendered.Headers[name] {
+			fmt.Fprintf(&builder, "\n%s: %s", name, censorHeaderValue(name, value))
+		}
+	}
+	if len(rendered.Body) > 0 {
+		fmt.Fprintf(&builder, "\nContent-Length: %d", len(rendered.Body))
+	}
+
+	switch {
+	case len(input.Files) > 0 || len(input.FormFields) > 0:
+		fmt.Fprintf(&builder, "\n\n[multipart/form-data body: %d bytes, files: %s; fields: %s]",
+			len(rendered.Body), describeKeys(input.Files), describeKeys(input.FormFields))
+	case len(rendered.Body) > 0 && sniffBody(rendered.Body) == sniffText:
+		builder.WriteString("\n\n")
+		builder.Write(rendered.Body)
+	case len(rendered.Body) > 0:
+		fmt.Fprintf(&builder, "\n\n[binary body: %d bytes]", len(rendered.Body))
+	}
+	return builder.String()
+}
+
+// describeKeys lists the sorted keys of a map, or "none".
+func describeKeys(values map[string]string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
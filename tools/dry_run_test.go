@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_HttpRequestHandler_DryRun(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	c := client.NewClient(client.Config{
+		BaseURL:        server.URL,
+		Timeout:        5 * time.Second,
+		DefaultHeaders: map[string]string{"Authorization": "Bearer secret"},
+	})
+
+	tests := []struct {
+		name     string
+		settings Settings
+		input    HttpRequestInput
+	}{
+		{"input", Settings{}, HttpRequestInput{Method: "POST", URL: "/orders?token=secret", Body: `{"id":1}`, DryRun: true}},
+		{"global flag", Settings{DryRun: true}, HttpRequestInput{Method: "POST", URL: "/orders?token=secret", Body: `{"id":1}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeHandler(c, tt.settings, NewHistory(10), NewStats(), NewVariables())
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			text := extractText(result)
+			for _, want := range []string{"[dry run — request not sent]", "POST " + server.URL + "/orders?token=***", "Authorization: ***", "Content-Length: 8", `{"id":1}`} {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in output, got:\n%s", want, text)
+				}
+			}
+			if strings.Contains(text, "secret") {
+				t.Errorf("expected sensitive values censored, got:\n%s", text)
+			}
+		})
+	}
+	if hits != 0 {
+		t.Errorf("dry run must not send requests, server saw %d", hits)
+	}
+}
+
+func Test_HttpRequestHandler_DryRunStillAppliesPolicy(t *testing.T) {
+	handler := makeHandler(newTestClient(""), Settings{Methods: MethodPolicy{ReadOnly: true}}, NewHistory(10), NewStats(), NewVariables())
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "DELETE", URL: "http://example.com/x", DryRun: true})
+	if !result.IsError {
+		t.Errorf("expected policy rejection in dry run, got: %s", extractText(result))
+	}
+}
+
+func Test_FormatDryRun_MultipartSummary(t *testing.T) {
+	rendered := &client.RenderedRequest{Method: "POST", URL: "http://example.com/upload", Headers: http.Header{}, Body: []byte("--boundary binary data")}
+	text := formatDryRun(rendered, HttpRequestInput{Files: map[string]string{"doc": "/tmp/a.pdf"}, FormFields: map[string]string{"note": "x"}})
+	if !strings.Contains(text, "[multipart/form-data body: 22 bytes, files: doc; fields: note]") {
+		t.Errorf("expected multipart summary, got:\n%s", text)
+	}
+	if strings.Contains(text, "binary data") {
+		t.Errorf("expected multipart body not dumped, got:\n%s", text)
+	}
+}
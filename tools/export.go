@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type ExportSessionInput struct {
+	Format  string `json:"format,omitempty" jsonschema:"go (httptest-based Go test file, default), postman (Postman v2.1 collection), or list (show recorded entries and their IDs)"`
+	Entries []int  `json:"entries,omitempty" jsonschema:"History entry IDs to export (default: all recorded entries, in session order)"`
+	Name    string `json:"name,omitempty" jsonschema:"Go test function name or Postman collection name"`
+	SaveTo  string `json:"saveTo,omitempty" jsonschema:"Write the artifact to this file path instead of returning it inline"`
+}
+
+const exportSessionDescription = "Turn http_request calls from this session into a runnable regression test: " +
+	"a Go httptest-based test file or a Postman collection, with status, Content-Type and JSON field assertions taken from the recorded responses. " +
+	"Use format=list first to see entry IDs. Sensitive header values are replaced by environment variables / collection variables."
+
+func makeExportSessionHandler(history *History) func(context.Context, *mcp.CallToolRequest, ExportSessionInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input ExportSessionInput) (*mcp.CallToolResult, any, error) {
+		entries, missing := history.Select(input.Entries)
+		if len(missing) > 0 {
+			return errorResult(fmt.Sprintf("unknown history entries: %v (use format=list to see recorded IDs)", missing)), nil, nil
+		}
+		if len(entries) == 0 {
+			return errorResult("no http_request calls recorded in this session yet"), nil, nil
+		}
+
+		var artifact string
+		var err error
+		switch strings.ToLower(input.Format) {
+		case "list":
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: formatHistoryList(entries)}},
+			}, nil, nil
+		case "", "go":
+			artifact, err = renderGoTest(entries, input.Name)
+		case "postman":
+			artifact, err = renderPostmanCollection(entries, input.Name)
+		default:
+			return errorResult(fmt.Sprintf("unsupported format: %s (expected go, postman, or list)", input.Format)), nil, nil
+		}
+		if err != nil {
+			return errorResult(fmt.Sprintf("Export failed: %s", err)), nil, nil
+		}
+
+		output := artifact
+		if input.SaveTo != "" {
+			if input.SaveTo, err = clientWorkspace(ctx, req).resolve(input.SaveTo); err != nil {
+				return errorResult(fmt.Sprintf("Export failed: saveTo: %s", err)), nil, nil
+			}
+			if err := os.WriteFile(input.SaveTo, []byte(artifact), 0o644); err != nil {
+				return errorResult(fmt.Sprintf("Export failed: writing %s: %s", input.SaveTo, err)), nil, nil
+			}
+			output = fmt.Sprintf("[saved to %s: %d bytes, %d requests]", input.SaveTo, len(artifact), len(entries))
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: output}},
+		}, nil, nil
+	}
+}
+
+func formatHistoryList(entries []HistoryEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%d %s %s → %d", entry.ID, entry.Method, client.RedactURL(entry.URL), entry.StatusCode))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// requestPath returns the path and query of a recorded URL, which is what the
+// exported tests send relative to their own server or base URL.
+func requestPath(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsedURL.RequestURI()
+}
+
+// secretVariableName maps a sensitive header to the variable that supplies its
+// value in exported artifacts, e.g. X-Api-Key -> API_X_API_KEY.
+func secretVariableName(headerName string) string {
+	return "API_" + strings.ToUpper(strings.ReplaceAll(headerName, "-", "_"))
+}
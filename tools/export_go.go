@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// renderGoTest renders entries as a Go test file that replays them, in order,
+// against an httptest.Server wrapping the application's handler.
+func renderGoTest(entries []HistoryEntry, testName string) (string, error) {
+	if testName == "" {
+		testName = "Test_RecordedSession"
+	}
+
+	imports := map[string]bool{"net/http": true, "net/http/httptest": true, "testing": true}
+	var body strings.Builder
+	for i, entry := range entries {
+		writeGoSubtest(&body, i+1, entry, imports)
+	}
+
+	importNames := make([]string, 0, len(imports))
+	for name := range imports {
+		importNames = append(importNames, name)
+	}
+	sort.Strings(importNames)
+
+	var source strings.Builder
+	source.WriteString("package apitest\n\nimport (\n")
+	for _, name := range importNames {
+		fmt.Fprintf(&source, "\t%q\n", name)
+	}
+	source.WriteString(")\n\n")
+	source.WriteString("// newAPIHandler returns the handler under test. Wire it to your application's router.\n")
+	source.WriteString("func newAPIHandler() http.Handler {\n\tpanic(\"TODO: return your application's http.Handler\")\n}\n\n")
+	fmt.Fprintf(&source, "func %s(t *testing.T) {\n\tserver := httptest.NewServer(newAPIHandler())\n\tdefer server.Close()\n", testName)
+	source.WriteString(body.String())
+	source.WriteString("}\n")
+
+	formatted, err := format.Source([]byte(source.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting generated Go test: %w", err)
+	}
+	return string(formatted), nil
+}
+
+func writeGoSubtest(builder *strings.Builder, index int, entry HistoryEntry, imports map[string]bool) {
+	path := requestPath(entry.URL)
+	fmt.Fprintf(builder, "\n\tt.Run(%q, func(t *testing.T) {\n", fmt.Sprintf("%02d %s %s", index, entry.Method, path))
+
+	bodyExpression := "nil"
+	if entry.Body != "" {
+		bodyExpression = fmt.Sprintf("strings.NewReader(%q)", entry.Body)
+		imports["strings"] = true
+	}
+	fmt.Fprintf(builder, "\t\treq, err := http.NewRequest(%q, server.URL+%q, %s)\n", entry.Method, path, bodyExpression)
+	builder.WriteString("\t\tif err != nil {\n\t\t\tt.Fatalf(\"creating request: %v\", err)\n\t\t}\n")
+
+	headerNames := make([]string, 0, len(entry.Headers))
+	for name := range entry.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		if client.IsSensitiveHeader(name) {
+			fmt.Fprintf(builder, "\t\treq.Header.Set(%q, os.Getenv(%q))\n", name, secretVariableName(name))
+			imports["os"] = true
+		} else {
+			fmt.Fprintf(builder, "\t\treq.Header.Set(%q, %q)\n", name, entry.Headers[name])
+		}
+	}
+
+	builder.WriteString("\t\tresp, err := http.DefaultClient.Do(req)\n")
+	builder.WriteString("\t\tif err != nil {\n\t\t\tt.Fatalf(\"sending request: %v\", err)\n\t\t}\n")
+	builder.WriteString("\t\tdefer resp.Body.Close()\n\n")
+	fmt.Fprintf(builder, "\t\tif resp.StatusCode != %d {\n\t\t\tt.Errorf(\"status = %%d, want %d\", resp.StatusCode)\n\t\t}\n", entry.StatusCode, entry.StatusCode)
+
+	if mediaType := mediaTypeOf(entry.ContentType); mediaType != "" {
+		imports["strings"] = true
+		fmt.Fprintf(builder, "\t\tif got := resp.Header.Get(\"Content-Type\"); !strings.HasPrefix(got, %q) {\n", mediaType)
+		fmt.Fprintf(builder, "\t\t\tt.Errorf(\"Content-Type = %%q, want %s\", got)\n\t\t}\n", mediaType)
+	}
+
+	if len(entry.JSONKeys) > 0 {
+		imports["encoding/json"] = true
+		quotedKeys := make([]string, 0, len(entry.JSONKeys))
+		for _, key := range entry.JSONKeys {
+			quotedKeys = append(quotedKeys, fmt.Sprintf("%q", key))
+		}
+		builder.WriteString("\n\t\tvar body map[string]any\n")
+		builder.WriteString("\t\tif err := json.NewDecoder(resp.Body).Decode(&body); err != nil {\n\t\t\tt.Fatalf(\"decoding JSON body: %v\", err)\n\t\t}\n")
+		fmt.Fprintf(builder, "\t\tfor _, key := range []string{%s} {\n", strings.Join(quotedKeys, ", "))
+		builder.WriteString("\t\t\tif _, ok := body[key]; !ok {\n\t\t\t\tt.Errorf(\"response is missing JSON field %q\", key)\n\t\t\t}\n\t\t}\n")
+	}
+
+	builder.WriteString("\t})\n")
+}
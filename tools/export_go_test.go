@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func Test_RenderGoTest_ParsesAndAsserts(t *testing.T) {
+	source, err := renderGoTest([]HistoryEntry{
+		{
+			Method:      "POST",
+			URL:         "http://localhost:8080/api/users?notify=true",
+			Headers:     map[string]string{"Content-Type": "application/json", "Authorization": "Bearer secret-token"},
+			Body:        `{"name":"Ann \"A\""}`,
+			StatusCode:  201,
+			ContentType: "application/json; charset=utf-8",
+			JSONKeys:    []string{"id", "name"},
+		},
+		{Method: "DELETE", URL: "http://localhost:8080/api/users/1", StatusCode: 204},
+	}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "session_test.go", source, 0); err != nil {
+		t.Fatalf("generated test does not parse: %v\n%s", err, source)
+	}
+	for _, want := range []string{
+		"func Test_RecordedSession(t *testing.T)",
+		`server.URL+"/api/users?notify=true"`,
+		`req.Header.Set("Authorization", os.Getenv("API_AUTHORIZATION"))`,
+		"if resp.StatusCode != 201",
+		`strings.HasPrefix(got, "application/json")`,
+		`[]string{"id", "name"}`,
+		`http.NewRequest("DELETE", server.URL+"/api/users/1", nil)`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Errorf("expected %q in generated test, got:\n%s", want, source)
+		}
+	}
+	if strings.Contains(source, "secret-token") {
+		t.Errorf("sensitive header value must not be exported, got:\n%s", source)
+	}
+}
+
+func Test_RenderGoTest_MinimalImports(t *testing.T) {
+	source, err := renderGoTest([]HistoryEntry{{Method: "GET", URL: "http://x.test/health", StatusCode: 200}}, "Test_Health")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(source, `"encoding/json"`) || strings.Contains(source, `"os"`) || strings.Contains(source, `"strings"`) {
+		t.Errorf("expected only required imports, got:\n%s", source)
+	}
+	if !strings.Contains(source, "func Test_Health(t *testing.T)") {
+		t.Errorf("expected custom test name, got:\n%s", source)
+	}
+}
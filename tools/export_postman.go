@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+const postmanSchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+	Event   []postmanEvent `json:"event,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	URL    string          `json:"url"`
+	Body   *postmanBody    `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode string `json:"mode"`
+	Raw  string `json:"raw"`
+}
+
+type postmanEvent struct {
+	Listen string        `json:"listen"`
+	Script postmanScript `json:"script"`
+}
+
+type postmanScript struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// renderPostmanCollection renders entries as a Postman v2.1 collection. URLs on
+// the origin of the first entry are expressed relative to a {{baseUrl}} variable.
+func renderPostmanCollection(entries []HistoryEntry, name string) (string, error) {
+	if name == "" {
+		name = "Recorded session"
+	}
+	baseURL := originOf(entries[0].URL)
+	collection := postmanCollection{
+		Info:     postmanInfo{Name: name, Schema: postmanSchemaURL},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: baseURL}},
+	}
+	secretVariables := map[string]bool{}
+
+	for i, entry := range entries {
+		requestURL := entry.URL
+		if baseURL != "" && originOf(entry.URL) == baseURL {
+			requestURL = "{{baseUrl}}" + requestPath(entry.URL)
+		}
+		item := postmanItem{
+			Name:    fmt.Sprintf("%02d %s %s", i+1, entry.Method, requestPath(entry.URL)),
+			Request: postmanRequest{Method: entry.Method, Header: []postmanHeader{}, URL: requestURL},
+			Event:   []postmanEvent{{Listen: "test", Script: postmanScript{Type: "text/javascript", Exec: postmanAssertions(entry)}}},
+		}
+
+		headerNames := make([]string, 0, len(entry.Headers))
+		for headerName := range entry.Headers {
+			headerNames = append(headerNames, headerName)
+		}
+		sort.Strings(headerNames)
+		for _, headerName := range headerNames {
+			value := entry.Headers[headerName]
+			if client.IsSensitiveHeader(headerName) {
+				variable := secretVariableName(headerName)
+				value = "{{" + variable + "}}"
+				secretVariables[variable] = true
+			}
+			item.Request.Header = append(item.Request.Header, postmanHeader{Key: headerName, Value: value})
+		}
+		if entry.Body != "" {
+			item.Request.Body = &postmanBody{Mode: "raw", Raw: entry.Body}
+		}
+		collection.Item = append(collection.Item, item)
+	}
+
+	variableNames := make([]string, 0, len(secretVariables))
+	for variable := range secretVariables {
+		variableNames = append(variableNames, variable)
+	}
+	sort.Strings(variableNames)
+	for _, variable := range variableNames {
+		collection.Variable = append(collection.Variable, postmanVariable{Key: variable, Value: ""})
+	}
+
+	output, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling Postman collection: %w", err)
+	}
+	return string(output) + "\n", nil
+}
+
+func postmanAssertions(entry HistoryEntry) []string {
+	lines := []string{
+		fmt.Sprintf("pm.test(\"status is %d\", function () { pm.response.to.have.status(%d); });", entry.StatusCode, entry.StatusCode),
+	}
+	if mediaType := mediaTypeOf(entry.ContentType); mediaType != "" {
+		lines = append(lines, fmt.Sprintf("pm.test(\"Content-Type is %s\", function () { pm.expect(pm.response.headers.get(\"Content-Type\")).to.include(%q); });", mediaType, mediaType))
+	}
+	if len(entry.JSONKeys) > 0 {
+		keys, _ := json.Marshal(entry.JSONKeys)
+		lines = append(lines, fmt.Sprintf("pm.test(\"has JSON fields\", function () { const body = pm.response.json(); %s.forEach(function (key) { pm.expect(body).to.have.property(key); }); });", keys))
+	}
+	return lines
+}
+
+func originOf(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+	return parsedURL.Scheme + "://" + parsedURL.Host
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_RenderPostmanCollection_Structure(t *testing.T) {
+	output, err := renderPostmanCollection([]HistoryEntry{
+		{
+			Method:      "GET",
+			URL:         "https://api.example.com/v1/items?page=2",
+			Headers:     map[string]string{"X-Api-Key": "sk-secret", "Accept": "application/json"},
+			StatusCode:  200,
+			ContentType: "application/json",
+			JSONKeys:    []string{"items"},
+		},
+		{Method: "POST", URL: "https://other.example.com/hook", Body: `{"a":1}`, StatusCode: 202},
+	}, "Items API")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal([]byte(output), &collection); err != nil {
+		t.Fatalf("collection is not valid JSON: %v", err)
+	}
+	if collection.Info.Name != "Items API" || collection.Info.Schema != postmanSchemaURL {
+		t.Errorf("unexpected info: %+v", collection.Info)
+	}
+	if len(collection.Item) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(collection.Item))
+	}
+	if collection.Item[0].Request.URL != "{{baseUrl}}/v1/items?page=2" {
+		t.Errorf("expected URL relative to baseUrl, got %s", collection.Item[0].Request.URL)
+	}
+	if collection.Item[1].Request.URL != "https://other.example.com/hook" {
+		t.Errorf("expected other-origin URL kept absolute, got %s", collection.Item[1].Request.URL)
+	}
+	if collection.Item[1].Request.Body == nil || collection.Item[1].Request.Body.Raw != `{"a":1}` {
+		t.Errorf("expected raw body on POST item, got %+v", collection.Item[1].Request.Body)
+	}
+	if strings.Contains(output, "sk-secret") || !strings.Contains(output, "{{API_X_API_KEY}}") {
+		t.Errorf("expected sensitive header replaced by variable, got: %s", output)
+	}
+	if !strings.Contains(strings.Join(collection.Item[0].Event[0].Script.Exec, "\n"), "pm.response.to.have.status(200)") {
+		t.Errorf("expected status assertion, got %v", collection.Item[0].Event[0].Script.Exec)
+	}
+}
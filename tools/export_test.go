@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_ExportSessionHandler_RecordsHttpRequestCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"widget"}`))
+	}))
+	defer server.Close()
+
+	history := NewHistory(10)
+	request := makeHandler(newTestClient(server.URL), Settings{FollowRedirects: true}, history, NewStats(), NewVariables())
+	export := makeExportSessionHandler(history)
+
+	request(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL + "/widgets/1"})
+
+	result, _, _ := export(context.Background(), nil, ExportSessionInput{Format: "list"})
+	if !strings.Contains(extractText(result), "1 GET "+server.URL+"/widgets/1 → 200") {
+		t.Errorf("expected recorded entry in list, got: %s", extractText(result))
+	}
+
+	result, _, _ = export(context.Background(), nil, ExportSessionInput{})
+	text := extractText(result)
+	if !strings.Contains(text, `server.URL+"/widgets/1"`) || !strings.Contains(text, `"id", "name"`) {
+		t.Errorf("expected Go test with path and JSON field assertions, got: %s", text)
+	}
+}
+
+func Test_ExportSessionHandler_SaveTo(t *testing.T) {
+	history := NewHistory(10)
+	history.Add(HistoryEntry{Method: "GET", URL: "http://x.test/a", StatusCode: 200})
+	export := makeExportSessionHandler(history)
+	savePath := filepath.Join(t.TempDir(), "session.postman_collection.json")
+
+	result, _, _ := export(context.Background(), nil, ExportSessionInput{Format: "postman", SaveTo: savePath})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", extractText(result))
+	}
+	if !strings.Contains(extractText(result), "[saved to "+savePath) {
+		t.Errorf("expected save summary, got: %s", extractText(result))
+	}
+	data, err := os.ReadFile(savePath)
+	if err != nil {
+		t.Fatalf("reading saved artifact: %v", err)
+	}
+	if !strings.Contains(string(data), postmanSchemaURL) {
+		t.Errorf("expected Postman collection on disk, got: %s", data)
+	}
+}
+
+func Test_ExportSessionHandler_Errors(t *testing.T) {
+	history := NewHistory(10)
+	export := makeExportSessionHandler(history)
+
+	result, _, _ := export(context.Background(), nil, ExportSessionInput{})
+	if !result.IsError || !strings.Contains(extractText(result), "no http_request calls recorded") {
+		t.Errorf("expected empty-history error, got: %s", extractText(result))
+	}
+
+	history.Add(HistoryEntry{Method: "GET", URL: "http://x.test/a", StatusCode: 200})
+	result, _, _ = export(context.Background(), nil, ExportSessionInput{Entries: []int{5}})
+	if !result.IsError || !strings.Contains(extractText(result), "unknown history entries: [5]") {
+		t.Errorf("expected unknown-entry error, got: %s", extractText(result))
+	}
+
+	result, _, _ = export(context.Background(), nil, ExportSessionInput{Format: "har"})
+	if !result.IsError || !strings.Contains(extractText(result), "unsupported format") {
+		t.Errorf("expected unsupported-format error, got: %s", extractText(result))
+	}
+}
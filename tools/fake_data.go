@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Word lists for fake data. Emails use the reserved example.* domains and
+// phone numbers the fictional 555-01xx range, so generated entities never
+// reach a real person.
+var (
+	fakeFirstNames  = []string{"Ava", "Liam", "Olivia", "Noah", "Emma", "Mateo", "Sofia", "Lucas", "Mia", "Amara", "Kenji", "Priya", "Elena", "Omar", "Chloe", "Jonas", "Aisha", "Diego", "Hana", "Viktor"}
+	fakeLastNames   = []string{"Smith", "Garcia", "Müller", "Nakamura", "Okafor", "Rossi", "Kowalski", "Silva", "Johansson", "Patel", "Dubois", "Novak", "Kim", "Hernández", "Brown", "Ivanova", "Cohen", "Andersen", "Tanaka", "Walker"}
+	fakeStreets     = []string{"Maple", "Oak", "Cedar", "Elm", "Willow", "Highland", "Lakeview", "Sunset", "Park", "River", "Church", "Mill", "Station", "Orchard", "Harbor"}
+	fakeStreetTypes = []string{"Street", "Avenue", "Road", "Lane", "Drive", "Way", "Boulevard", "Court"}
+	fakeCities      = []string{"Springfield", "Riverton", "Lakewood", "Fairview", "Greenville", "Brookside", "Ashford", "Milton", "Oakridge", "Clearwater", "Westbury", "Northfield"}
+	fakeCountries   = []string{"United States", "Germany", "Japan", "Brazil", "Canada", "France", "India", "Nigeria", "Sweden", "Australia", "Mexico", "Poland"}
+	fakeCompanyEnds = []string{"Inc.", "LLC", "Group", "Labs", "Systems", "Partners", "Holdings", "Co."}
+	fakeDomains     = []string{"example.com", "example.org", "example.net"}
+	fakeWords       = []string{"alpha", "bright", "cloud", "delta", "ember", "field", "grain", "harbor", "island", "jade", "kernel", "lumen", "meadow", "north", "orbit", "prism", "quartz", "ridge", "signal", "timber", "union", "vector", "willow", "yonder", "zephyr"}
+)
+
+// faker generates realistic-looking test data from one random source, so a
+// seeded faker repeats its output.
+type faker struct {
+	rng *rand.Rand
+}
+
+func newFaker(seed uint64) faker {
+	return faker{rng: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+func (f faker) pick(list []string) string { return list[f.rng.IntN(len(list))] }
+
+func (f faker) firstName() string { return f.pick(fakeFirstNames) }
+func (f faker) lastName() string  { return f.pick(fakeLastNames) }
+func (f faker) name() string      { return f.firstName() + " " + f.lastName() }
+func (f faker) city() string      { return f.pick(fakeCities) }
+func (f faker) country() string   { return f.pick(fakeCountries) }
+func (f faker) company() string   { return f.lastName() + " " + f.pick(fakeCompanyEnds) }
+
+func (f faker) username() string {
+	return asciiLower(f.firstName()) + fmt.Sprint(f.rng.IntN(1000))
+}
+
+func (f faker) email() string {
+	return asciiLower(f.firstName()) + "." + asciiLower(f.lastName()) + fmt.Sprint(f.rng.IntN(100)) + "@" + f.pick(fakeDomains)
+}
+
+func (f faker) phone() string {
+	return fmt.Sprintf("+1-%03d-555-01%02d", 200+f.rng.IntN(800), f.rng.IntN(100))
+}
+
+func (f faker) street() string {
+	return fmt.Sprintf("%d %s %s", 1+f.rng.IntN(9999), f.pick(fakeStreets), f.pick(fakeStreetTypes))
+}
+
+func (f faker) postalCode() string { return fmt.Sprintf("%05d", f.rng.IntN(100000)) }
+func (f faker) address() string    { return f.street() + ", " + f.city() + " " + f.postalCode() }
+
+func (f faker) sentence() string {
+	words := make([]string, 4+f.rng.IntN(6))
+	for i := range words {
+		words[i] = f.pick(fakeWords)
+	}
+	sentence := strings.Join(words, " ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// date returns a moment within the past three years.
+func (f faker) date() time.Time {
+	return time.Now().UTC().Add(-time.Duration(f.rng.Int64N(int64(3 * 365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func (f faker) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(f.rng.UintN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// asciiLower lower-cases a name for use in emails and usernames, dropping
+// letters outside a-z.
+func asciiLower(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, name)
+}
+
+// fakeFuncs are the template functions for fake data, drawing from f.
+func fakeFuncs(f faker) template.FuncMap {
+	return template.FuncMap{
+		"fakeFirstName":  f.firstName,
+		"fakeLastName":   f.lastName,
+		"fakeName":       f.name,
+		"fakeUsername":   f.username,
+		"fakeEmail":      f.email,
+		"fakePhone":      f.phone,
+		"fakeStreet":     f.street,
+		"fakeCity":       f.city,
+		"fakePostalCode": f.postalCode,
+		"fakeCountry":    f.country,
+		"fakeAddress":    f.address,
+		"fakeCompany":    f.company,
+		"fakeSentence":   f.sentence,
+		"fakeDate":       func() string { return f.date().Format(time.DateOnly) },
+	}
+}
+
+// string generates a string by format, else by property name, then fits it
+// to minLength and maxLength. pattern is not honored.
+func (g payloadGenerator) string(schema map[string]any, name string) string {
+	value := g.stringFor(schema, strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name)))
+	runes := []rune(value)
+	if maxLength := int(schemaNumber(schema, "maxLength", -1)); maxLength >= 0 && len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	for minLength := int(schemaNumber(schema, "minLength", 0)); len(runes) < minLength; {
+		runes = append(runes, rune('a'+g.fake.rng.IntN(26)))
+	}
+	return string(runes)
+}
+
+func (g payloadGenerator) stringFor(schema map[string]any, name string) string {
+	f := g.fake
+	switch format, _ := schema["format"].(string); format {
+	case "email":
+		return f.email()
+	case "uuid":
+		return f.uuid()
+	case "date":
+		return f.date().Format(time.DateOnly)
+	case "date-time":
+		return f.date().Format(time.RFC3339)
+	case "time":
+		return f.date().Format(time.TimeOnly)
+	case "uri", "url":
+		return "https://www." + f.pick(fakeDomains) + "/" + f.pick(fakeWords)
+	case "hostname":
+		return f.pick(fakeWords) + "." + f.pick(fakeDomains)
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+f.rng.IntN(254)) // TEST-NET-1
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+f.rng.IntN(0xffff)) // documentation prefix
+	}
+	switch {
+	case strings.Contains(name, "email"):
+		return f.email()
+	case strings.Contains(name, "firstname") || strings.Contains(name, "givenname"):
+		return f.firstName()
+	case strings.Contains(name, "lastname") || strings.Contains(name, "surname") || strings.Contains(name, "familyname"):
+		return f.lastName()
+	case strings.Contains(name, "username") || name == "login" || name == "handle":
+		return f.username()
+	case strings.Contains(name, "company") || strings.Contains(name, "organization"):
+		return f.company()
+	case name == "name" || strings.Contains(name, "fullname") || strings.Contains(name, "displayname"):
+		return f.name()
+	case strings.Contains(name, "phone") || strings.Contains(name, "mobile"):
+		return f.phone()
+	case strings.Contains(name, "street") || name == "address" || name == "addressline1":
+		return f.street()
+	case strings.Contains(name, "city") || strings.Contains(name, "town"):
+		return f.city()
+	case strings.Contains(name, "country"):
+		return f.country()
+	case strings.Contains(name, "zip") || strings.Contains(name, "postal"):
+		return f.postalCode()
+	case strings.Contains(name, "url") || strings.Contains(name, "website"):
+		return "https://www." + f.pick(fakeDomains) + "/" + f.pick(fakeWords)
+	case strings.HasSuffix(name, "date") || strings.HasSuffix(name, "edat"):
+		return f.date().Format(time.RFC3339)
+	case name == "id" || strings.HasSuffix(name, "id") || strings.Contains(name, "uuid"):
+		return f.uuid()
+	case strings.Contains(name, "description") || strings.Contains(name, "comment") || strings.Contains(name, "note") || name == "bio" || name == "summary":
+		return f.sentence()
+	}
+	return f.pick(fakeWords) + "-" + f.pick(fakeWords)
+}
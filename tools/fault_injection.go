@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type SimulateAuthExpiryInput struct {
+	Count int  `json:"count,omitempty" jsonschema:"Number of upcoming http_request calls that receive a simulated 401 (default 1)"`
+	Reset bool `json:"reset,omitempty" jsonschema:"Cancel any pending simulated failures instead of arming new ones"`
+}
+
+const simulateAuthExpiryDescription = "Test mode: make the next http_request call(s) fail with a simulated 401 Unauthorized " +
+	"(expired token) without contacting the API. Use to verify re-auth and retry behavior before relying on it."
+
+func makeSimulateAuthExpiryHandler(httpClient *client.Client) func(context.Context, *mcp.CallToolRequest, SimulateAuthExpiryInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input SimulateAuthExpiryInput) (*mcp.CallToolResult, any, error) {
+		if input.Count < 0 {
+			return errorResult("count must not be negative"), nil, nil
+		}
+
+		if input.Reset {
+			httpClient.SimulateAuthExpiry(0)
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "Simulated auth expiry disarmed."}},
+			}, nil, nil
+		}
+
+		count := input.Count
+		if count == 0 {
+			count = 1
+		}
+		httpClient.SimulateAuthExpiry(count)
+
+		message := fmt.Sprintf("Armed: the next %d request attempt(s) will receive a simulated 401 Unauthorized (expired token).", count)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		}, nil, nil
+	}
+}
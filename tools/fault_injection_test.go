@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_SimulateAuthExpiryHandler_NextRequestGets401(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	simulate := makeSimulateAuthExpiryHandler(c)
+	request := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
+
+	result, _, _ := simulate(context.Background(), nil, SimulateAuthExpiryInput{})
+	if !strings.Contains(extractText(result), "next 1 request") {
+		t.Errorf("expected arming confirmation, got: %s", extractText(result))
+	}
+
+	result, _, _ = request(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+	if !strings.Contains(extractText(result), "401 Unauthorized") {
+		t.Errorf("expected simulated 401, got: %s", extractText(result))
+	}
+
+	result, _, _ = request(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+	if !strings.Contains(extractText(result), "200 OK") {
+		t.Errorf("expected real response after simulated failure, got: %s", extractText(result))
+	}
+}
+
+func Test_SimulateAuthExpiryHandler_Reset(t *testing.T) {
+	c := newTestClient("")
+	simulate := makeSimulateAuthExpiryHandler(c)
+
+	simulate(context.Background(), nil, SimulateAuthExpiryInput{Count: 2})
+	result, _, _ := simulate(context.Background(), nil, SimulateAuthExpiryInput{Reset: true})
+
+	if !strings.Contains(extractText(result), "disarmed") {
+		t.Errorf("expected disarm confirmation, got: %s", extractText(result))
+	}
+	if c.PendingSimulatedAuthFailures() != 0 {
+		t.Errorf("expected no pending failures, got %d", c.PendingSimulatedAuthFailures())
+	}
+}
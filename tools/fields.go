@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// fieldPaths are parsed fields or omitFields paths, one segment list per
+// path. A segment is an object key, an array index or * for any key or
+// element; arrays are otherwise transparent, so "items.id" reaches the id of
+// every item.
+type fieldPaths [][]string
+
+// parseFieldPaths splits dot paths into segments; "\." is a literal dot.
+func parseFieldPaths(paths []string) fieldPaths {
+	parsed := make(fieldPaths, 0, len(paths))
+	for _, path := range paths {
+		var segments []string
+		var segment strings.Builder
+		for i := 0; i < len(path); i++ {
+			switch {
+			case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+				segment.WriteByte('.')
+				i++
+			case path[i] == '.':
+				segments = append(segments, segment.String())
+				segment.Reset()
+			default:
+				segment.WriteByte(path[i])
+			}
+		}
+		if path != "" {
+			parsed = append(parsed, append(segments, segment.String()))
+		}
+	}
+	return parsed
+}
+
+// step descends the paths into the member key of an object, or the element
+// at index key of an array: the paths that go on below it, and whether one
+// of them ends at it.
+func (p fieldPaths) step(key string, inArray bool) (rest fieldPaths, done bool) {
+	for _, path := range p {
+		segment := path[0]
+		matched := segment == "*" || segment == key
+		if inArray && !matched && !isArrayIndex(segment) {
+			rest = append(rest, path)
+			continue
+		}
+		switch {
+		case !matched:
+		case len(path) == 1:
+			done = true
+		default:
+			rest = append(rest, path[1:])
+		}
+	}
+	return rest, done
+}
+
+func isArrayIndex(segment string) bool {
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
+
+// projectFields narrows a JSON body, after jsonFilter, to the fields paths and
+// then drops the omitFields paths, keeping key order. It returns nil when
+// there is nothing to project: a body that is not JSON, with a note why, or a
+// jsonFilter that matched nothing. Fields that match nothing also get a note.
+func projectFields(body []byte, jsonFilter string, fields, omitFields []string) ([]byte, string) {
+	if !gjson.ValidBytes(body) {
+		return nil, "[fields and omitFields apply to JSON bodies only — the body is shown as is]"
+	}
+	value := gjson.ParseBytes(body)
+	if jsonFilter != "" {
+		if value = gjson.GetBytes(body, jsonFilter); !value.Exists() {
+			return nil, ""
+		}
+	}
+	projected := value.Raw
+	if len(fields) > 0 {
+		picked, found := pickFields(value, parseFieldPaths(fields))
+		if !found {
+			return []byte(picked), fmt.Sprintf("[fields %s matched nothing — retry without fields to inspect the body]", strings.Join(fields, ", "))
+		}
+		projected = picked
+	}
+	if len(omitFields) > 0 {
+		projected = omitPaths(gjson.Parse(projected), parseFieldPaths(omitFields))
+	}
+	return []byte(projected), ""
+}
+
+// pickFields keeps what the paths reach, dropping array elements they do not
+// reach at all.
+func pickFields(value gjson.Result, paths fieldPaths) (string, bool) {
+	var parts []string
+	switch {
+	case value.IsObject():
+		value.ForEach(func(key, member gjson.Result) bool {
+			rest, done := paths.step(key.String(), false)
+			if done {
+				parts = append(parts, key.Raw+":"+member.Raw)
+			} else if picked, found := pickFields(member, rest); found {
+				parts = append(parts, key.Raw+":"+picked)
+			}
+			return true
+		})
+		return "{" + strings.Join(parts, ",") + "}", len(parts) > 0
+	case value.IsArray():
+		for i, element := range value.Array() {
+			rest, done := paths.step(strconv.Itoa(i), true)
+			if done {
+				parts = append(parts, element.Raw)
+			} else if picked, found := pickFields(element, rest); found {
+				parts = append(parts, picked)
+			}
+		}
+		return "[" + strings.Join(parts, ",") + "]", len(parts) > 0
+	}
+	return "", false
+}
+
+// omitPaths drops what the paths reach and keeps everything else.
+func omitPaths(value gjson.Result, paths fieldPaths) string {
+	var parts []string
+	switch {
+	case len(paths) == 0:
+		return value.Raw
+	case value.IsObject():
+		value.ForEach(func(key, member gjson.Result) bool {
+			if rest, done := paths.step(key.String(), false); !done {
+				parts = append(parts, key.Raw+":"+omitPaths(member, rest))
+			}
+			return true
+		})
+		return "{" + strings.Join(parts, ",") + "}"
+	case value.IsArray():
+		for i, element := range value.Array() {
+			if rest, done := paths.step(strconv.Itoa(i), true); !done {
+				parts = append(parts, omitPaths(element, rest))
+			}
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	}
+	return value.Raw
+}
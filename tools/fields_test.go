@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_projectFields_Paths(t *testing.T) {
+	body := `{"id":7,"status":"open","owner":{"login":"ana","id":3},"items":[{"id":1,"status":"done","meta":{"a":1}},{"id":2,"meta":{"a":2}}],"a.b":true}`
+	tests := []struct {
+		name       string
+		jsonFilter string
+		fields     []string
+		omitFields []string
+		want       string
+	}{
+		{"top-level keys in body order", "", []string{"status", "id"}, nil, `{"id":7,"status":"open"}`},
+		{"nested path", "", []string{"owner.login"}, nil, `{"owner":{"login":"ana"}}`},
+		{"arrays pass through", "", []string{"items.id"}, nil, `{"items":[{"id":1},{"id":2}]}`},
+		{"wildcard key", "", []string{"*.id"}, nil, `{"owner":{"id":3},"items":[{"id":1},{"id":2}]}`},
+		{"array index", "", []string{"items.1.id"}, nil, `{"items":[{"id":2}]}`},
+		{"escaped dot", "", []string{`a\.b`}, nil, `{"a.b":true}`},
+		{"after jsonFilter", "items", []string{"id", "status"}, nil, `[{"id":1,"status":"done"},{"id":2}]`},
+		{"omit", "items", nil, []string{"meta"}, `[{"id":1,"status":"done"},{"id":2}]`},
+		{"fields then omit", "", []string{"owner"}, []string{"owner.id"}, `{"owner":{"login":"ana"}}`},
+		{"omit wildcard", "", nil, []string{"*.meta", "owner", "a\\.b"}, `{"id":7,"status":"open","items":[{"id":1,"status":"done"},{"id":2}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projected, note := projectFields([]byte(body), tt.jsonFilter, tt.fields, tt.omitFields)
+			if got := string(minifyJSON(projected)); got != tt.want || note != "" {
+				t.Errorf("projectFields = %s (%q), want %s", got, note, tt.want)
+			}
+		})
+	}
+}
+
+func Test_projectFields_NothingToProject(t *testing.T) {
+	if projected, note := projectFields([]byte("plain text"), "", []string{"id"}, nil); projected != nil || !strings.Contains(note, "JSON bodies only") {
+		t.Errorf("text body: %q, %q", projected, note)
+	}
+	if projected, note := projectFields([]byte(`{"id":1}`), "missing", []string{"id"}, nil); projected != nil || note != "" {
+		t.Errorf("missed jsonFilter: %q, %q", projected, note)
+	}
+	if projected, note := projectFields([]byte(`{"id":1}`), "", []string{"name"}, nil); string(projected) != "{}" || !strings.Contains(note, "fields name matched nothing") {
+		t.Errorf("missed fields: %q, %q", projected, note)
+	}
+}
+
+func Test_FormatResponse_Fields(t *testing.T) {
+	resp := &client.Response{StatusCode: 200, StatusText: "OK", ContentType: "application/json", Body: []byte(`{"items":[{"id":1,"name":"a","status":"ok"},{"id":2,"name":"b","status":"failed"}]}`)}
+
+	text := FormatResponse(resp, FormatOptions{JSONFilter: "items", Fields: []string{"id", "status"}})
+	if !strings.HasSuffix(text, `[{"id":1,"status":"ok"},{"id":2,"status":"failed"}]`) {
+		t.Errorf("unexpected text output: %s", text)
+	}
+	table := FormatResponse(resp, FormatOptions{Format: formatTable, JSONFilter: "items", OmitFields: []string{"name"}})
+	if !strings.Contains(table, "| id | status |") {
+		t.Errorf("unexpected table output: %s", table)
+	}
+}
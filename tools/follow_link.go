@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// maxLinkHops bounds followLinkHops.
+const maxLinkHops = 10
+
+// linkFollower dereferences hypermedia links (followLink) with GET requests
+// that pass the same method and URL policies as the request that led to them.
+type linkFollower struct {
+	httpClient *client.Client
+	settings   Settings
+	history    *History
+	stats      *Stats
+}
+
+// follow fetches the rel link of resp, then of each fetched resource, hops
+// times. It stops early at a missing link, a non-2xx response, a failed request
+// or a URL already visited, and returns the last response with one note line
+// per hop. Each fetched resource is added to history.
+func (f linkFollower) follow(ctx context.Context, params client.RequestParams, resp *client.Response, rel string, hops int) (*client.Response, string) {
+	var notes strings.Builder
+	visited := map[string]bool{resp.RequestURL: true, resp.FinalURL: true}
+	for range hops {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			fmt.Fprintf(&notes, "[followLink: not followed from a %d response]\n", resp.StatusCode)
+			break
+		}
+		href, found := findLink(resp, rel)
+		if !found {
+			fmt.Fprintf(&notes, "[followLink: no %q link in the response]\n", rel)
+			break
+		}
+		target, err := resolveLink(resp, href)
+		if err != nil {
+			fmt.Fprintf(&notes, "[followLink: invalid %q link %q: %s]\n", rel, href, err)
+			break
+		}
+		if visited[target] {
+			fmt.Fprintf(&notes, "[followLink: stopped, %s was already visited]\n", target)
+			break
+		}
+		visited[target] = true
+		if denied := f.settings.Methods.check("GET"); denied != "" {
+			fmt.Fprintf(&notes, "[followLink: %s]\n", denied)
+			break
+		}
+		if denied := f.settings.URLs.check("GET", target); denied != "" {
+			fmt.Fprintf(&notes, "[followLink: %s]\n", denied)
+			break
+		}
+
+		next := linkRequest(params, resp.RequestURL, target)
+		started := time.Now()
+		nextResp, err := f.httpClient.ExecuteRequest(ctx, next)
+		if ctx.Err() == nil {
+			f.stats.Record(target, 0, nextResp, time.Since(started))
+		}
+		if err != nil {
+			fmt.Fprintf(&notes, "[followLink: GET %s failed: %s]\n", target, err)
+			break
+		}
+		fmt.Fprintf(&notes, "[followed %s: GET %s]\n", rel, target)
+		if f.settings.DecodeBinaryBodies {
+			notes.WriteString(decodeBinaryBody(nextResp))
+		}
+		f.history.Add(newHistoryEntry(next, nextResp))
+		params, resp = next, nextResp
+	}
+	return resp, notes.String()
+}
+
+// linkRequest builds the GET for a link target from the request to from that
+// led to it. Headers are kept only on the same origin, so credentials meant
+// for one API are not sent to a host a response points at.
+func linkRequest(params client.RequestParams, from, target string) client.RequestParams {
+	var headers map[string]string
+	if sameOrigin(from, target) {
+		headers = make(map[string]string, len(params.Headers))
+		for name, value := range params.Headers {
+			if name != "Content-Type" {
+				headers[name] = value
+			}
+		}
+	}
+	return client.RequestParams{
+		Method:          "GET",
+		URL:             target,
+		Headers:         headers,
+		Timeout:         params.Timeout,
+		FollowRedirects: params.FollowRedirects,
+		CheckRedirect:   params.CheckRedirect,
+		MaxResponseSize: params.MaxResponseSize,
+		Progress:        params.Progress,
+		Verbose:         params.Verbose,
+		RedactResponse:  params.RedactResponse,
+		InsecureTLS:     params.InsecureTLS,
+		CACert:          params.CACert,
+		ServerName:      params.ServerName,
+		HTTPVersion:     params.HTTPVersion,
+		MaxRedirects:    params.MaxRedirects,
+		Cache:           params.Cache,
+	}
+}
+
+// findLink looks up rel as a HAL link (_links.rel.href), a JSON:API link
+// (links.rel or links.rel.href), an entry of a links array ({rel, href}, as in
+// Siren), a Link header relation, and finally as a GJSON path to a URL or an
+// object with an href.
+func findLink(resp *client.Response, rel string) (string, bool) {
+	if gjson.ValidBytes(resp.Body) {
+		escaped := gjson.Escape(rel)
+		for _, path := range []string{"_links." + escaped, "links." + escaped} {
+			if href, ok := linkHref(gjson.GetBytes(resp.Body, path)); ok {
+				return href, true
+			}
+		}
+		for _, link := range gjson.GetBytes(resp.Body, "links").Array() {
+			for _, linkRel := range link.Get("rel").Array() {
+				if linkRel.String() == rel {
+					if href, ok := linkHref(link); ok {
+						return href, true
+					}
+				}
+			}
+		}
+	}
+	if href, ok := linkHeader(resp.Headers.Values("Link"), rel); ok {
+		return href, true
+	}
+	if gjson.ValidBytes(resp.Body) {
+		return linkHref(gjson.GetBytes(resp.Body, rel))
+	}
+	return "", false
+}
+
+// linkHref reads a link given as a URL string, an object with an href, or an
+// array of those (the first wins).
+func linkHref(link gjson.Result) (string, bool) {
+	if link.IsArray() {
+		links := link.Array()
+		if len(links) == 0 {
+			return "", false
+		}
+		link = links[0]
+	}
+	if link.IsObject() {
+		link = link.Get("href")
+	}
+	if link.Type != gjson.String || link.String() == "" {
+		return "", false
+	}
+	return link.String(), true
+}
+
+// linkHeader finds rel in Link header values (RFC 8288), such as
+// <https://api.example.com/items?page=2>; rel="next".
+func linkHeader(values []string, rel string) (string, bool) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, found := strings.Cut(strings.TrimSpace(link), ";")
+			if !found || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				name, relations, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, relation := range strings.Fields(strings.Trim(relations, `"`)) {
+					if strings.EqualFold(relation, rel) {
+						return strings.Trim(target, "<>"), true
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// resolveLink makes href absolute against the URL the response came from.
+func resolveLink(resp *client.Response, href string) (string, error) {
+	base := resp.FinalURL
+	if base == "" {
+		base = resp.RequestURL
+	}
+	return resolveReference(base, href)
+}
+
+// resolveReference resolves a possibly relative URL reference against base.
+func resolveReference(base, reference string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	referenceURL, err := url.Parse(reference)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(referenceURL).String(), nil
+}
+
+func sameOrigin(from, to string) bool {
+	fromURL, err := url.Parse(from)
+	if err != nil {
+		return false
+	}
+	toURL, err := url.Parse(to)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(fromURL.Scheme, toURL.Scheme) && strings.EqualFold(fromURL.Host, toURL.Host)
+}
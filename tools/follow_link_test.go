@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_findLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		header   string
+		rel      string
+		wantHref string
+	}{
+		{"HAL", `{"_links":{"next":{"href":"/items?page=2"}}}`, "", "next", "/items?page=2"},
+		{"HAL array", `{"_links":{"item":[{"href":"/items/1"},{"href":"/items/2"}]}}`, "", "item", "/items/1"},
+		{"HAL curie", `{"_links":{"ea:basket":{"href":"/baskets/9"}}}`, "", "ea:basket", "/baskets/9"},
+		{"JSON:API string", `{"links":{"self":"https://api.example.com/a"}}`, "", "self", "https://api.example.com/a"},
+		{"JSON:API object", `{"links":{"related":{"href":"/b","meta":{}}}}`, "", "related", "/b"},
+		{"links array", `{"links":[{"rel":["self"],"href":"/s"},{"rel":["next"],"href":"/n"}]}`, "", "next", "/n"},
+		{"Link header", `[]`, `</p/1>; rel="prev", </p/3>; rel="next last"`, "next", "/p/3"},
+		{"GJSON path", `{"data":{"relationships":{"author":{"links":{"related":"/people/9"}}}}}`, "", "data.relationships.author.links.related", "/people/9"},
+		{"missing", `{"_links":{"self":{"href":"/x"}}}`, "", "next", ""},
+		{"not a URL", `{"count":3}`, "", "count", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &client.Response{Body: []byte(tt.body), Headers: http.Header{}}
+			if tt.header != "" {
+				resp.Headers.Set("Link", tt.header)
+			}
+			href, found := findLink(resp, tt.rel)
+			if href != tt.wantHref || found != (tt.wantHref != "") {
+				t.Errorf("findLink = %q, %v, want %q", href, found, tt.wantHref)
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_FollowLink(t *testing.T) {
+	var gotAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		page := r.URL.Query().Get("page")
+		switch page {
+		case "", "1":
+			fmt.Fprint(w, `{"page":1,"_links":{"next":{"href":"/items?page=2"}}}`)
+		case "2":
+			fmt.Fprint(w, `{"page":2,"_links":{"next":{"href":"/items?page=1"}}}`) // loops back
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		input HttpRequestInput
+		want  []string
+	}{
+		{"one hop", HttpRequestInput{FollowLink: "next"}, []string{"[followed next: GET " + server.URL + "/items?page=2]", `{"page":2`}},
+		{"loop protection", HttpRequestInput{FollowLink: "next", FollowLinkHops: 5}, []string{"already visited", `{"page":2`}},
+		{"missing link", HttpRequestInput{FollowLink: "author"}, []string{`[followLink: no "author" link in the response]`, `{"page":1`}},
+		{"too many hops", HttpRequestInput{FollowLink: "next", FollowLinkHops: 11}, []string{"followLinkHops must be between 1 and 10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth = nil
+			handler := makeHandler(newTestClient(server.URL), Settings{}, NewHistory(10), NewStats(), NewVariables())
+			tt.input.Method, tt.input.URL = "GET", server.URL+"/items?page=1"
+			tt.input.Headers = map[string]StringValues{"Authorization": {"Bearer t"}}
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			text := extractText(result)
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("result = %s, want it to contain %s", text, want)
+				}
+			}
+			for _, auth := range gotAuth {
+				if auth != "Bearer t" {
+					t.Errorf("same-origin link requests must keep headers, got Authorization %q", auth)
+				}
+			}
+		})
+	}
+}
+
+func Test_linkRequest_DropsHeadersAcrossOrigins(t *testing.T) {
+	params := client.RequestParams{Method: "POST", Headers: map[string]string{"Authorization": "Bearer t", "Content-Type": "application/json"}}
+	if next := linkRequest(params, "https://api.example.com/a", "https://cdn.example.net/b"); next.Headers != nil {
+		t.Errorf("cross-origin headers = %v, want none", next.Headers)
+	}
+	next := linkRequest(params, "https://api.example.com/a", "https://api.example.com/b")
+	if next.Method != "GET" || next.Headers["Authorization"] != "Bearer t" || next.Headers["Content-Type"] != "" {
+		t.Errorf("same-origin request = %s %v", next.Method, next.Headers)
+	}
+}
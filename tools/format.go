@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
-	"unicode/utf8"
+	"time"
 
 	"github.com/tidwall/gjson"
 
@@ -32,14 +33,63 @@ var noiseHeaders = map[string]bool{
 
 // FormatOptions controls how a response is rendered for the model.
 type FormatOptions struct {
-	IncludeHeaders bool
-	JSONFilter     string
+	IncludeHeaders   bool
+	JSONFilter       string
+	ShowCookieValues bool   // print Set-Cookie values in the cookie summary instead of ***
+	RequestIDHeader  string // with RequestID, shown under the status line
+	RequestID        string
+	Timing           *RequestTiming // retries, and with IncludeTiming the duration, under the status line
+	IncludeTiming    bool
+	Verbose          bool     // prepend the response's wire transcript
+	EchoRequest      bool     // prepend the resolved method, URL and sent headers
+	Format           string   // text (default), raw or table; raw ignores all but the redaction
+	Columns          []string // format=table column selection
+	Fields           []string // keep only these JSON paths, after JSONFilter
+	OmitFields       []string // drop these JSON paths, after Fields
+	Redaction        ResponseRedaction
 }
 
 func FormatResponse(resp *client.Response, opts FormatOptions) string {
+	if opts.Format == formatRaw {
+		return formatRawResponse(resp, opts)
+	}
 	var builder strings.Builder
 
+	if opts.EchoRequest {
+		builder.WriteString(formatRequestEcho(resp))
+		builder.WriteString("\n\n")
+	}
+	if opts.Verbose && resp.WireLog != "" {
+		builder.WriteString(resp.WireLog)
+		builder.WriteString("\n\n")
+	}
 	fmt.Fprintf(&builder, "%d %s", resp.StatusCode, resp.StatusText)
+	if opts.RequestID != "" {
+		fmt.Fprintf(&builder, "\n[%s: %s]", opts.RequestIDHeader, opts.RequestID)
+	}
+	if opts.Timing != nil {
+		if summary := opts.Timing.summary(resp.StatusCode, opts.IncludeTiming); summary != "" {
+			builder.WriteString("\n" + summary)
+		}
+	}
+	if resp.QueueWait > 0 {
+		fmt.Fprintf(&builder, "\n[queued %s for a free request slot]", resp.QueueWait.Round(time.Millisecond))
+	}
+	if resp.PaceWait > 0 {
+		fmt.Fprintf(&builder, "\n[paused %s for the host's rate limit]", resp.PaceWait.Round(time.Millisecond))
+	}
+	if resp.Coalesced {
+		builder.WriteString("\n[shared the response of an identical request — no new upstream call]")
+	}
+	if resp.Cached {
+		fmt.Fprintf(&builder, "\n[cached %s ago — pass cache: refresh for current data]", resp.CacheAge.Round(time.Second))
+	}
+	if chain := formatRedirectChain(resp); chain != "" {
+		builder.WriteString("\n" + chain)
+	}
+	if cookies := formatCookieSummary(resp, opts.ShowCookieValues || opts.Redaction.Disabled); cookies != "" {
+		builder.WriteString("\n" + cookies)
+	}
 
 	if opts.IncludeHeaders && len(resp.Headers) > 0 {
 		builder.WriteString("\n")
@@ -52,26 +102,53 @@ func FormatResponse(resp *client.Response, opts FormatOptions) string {
 		sort.Strings(keys)
 		for _, key := range keys {
 			for _, value := range resp.Headers[key] {
-				fmt.Fprintf(&builder, "\n%s: %s", key, value)
+				fmt.Fprintf(&builder, "\n%s: %s", key, opts.Redaction.value(key, value, opts.ShowCookieValues))
 			}
 		}
+		builder.WriteString(formatTrailers(resp.Trailers, opts.Redaction))
 	}
 
-	if resp.SavedPath != "" {
-		fmt.Fprintf(&builder, "\n\n[saved to %s: %d bytes, %s]", resp.SavedPath, resp.SavedSize, displayContentType(resp.ContentType))
+	if resp.SavedPath != "" && !resp.Spilled {
+		fmt.Fprintf(&builder, "\n\n[saved to %s: %d bytes, %s, sha256 %s]", resp.SavedPath, resp.SavedSize, displayContentType(resp.ContentType), resp.SavedSHA256)
 		return builder.String()
 	}
 
 	if len(resp.Body) > 0 {
 		if !isTextContent(resp.ContentType, resp.Body) {
+			if resp.Spilled {
+				fmt.Fprintf(&builder, "\n\n[binary: %s, %d bytes, saved to %s, sha256 %s]", displayContentType(resp.ContentType), resp.SavedSize, resp.SavedPath, resp.SavedSHA256)
+				return builder.String()
+			}
 			fmt.Fprintf(&builder, "\n\n[binary: %s, %d bytes — pass saveTo to write it to a file]", displayContentType(resp.ContentType), totalBodySize(resp))
 			return builder.String()
 		}
 		builder.WriteString("\n\n")
-		builder.WriteString(renderTextBody(resp.Body, opts.JSONFilter))
+		body, jsonFilter := resp.Body, opts.JSONFilter
+		if len(opts.Fields) > 0 || len(opts.OmitFields) > 0 {
+			projected, note := projectFields(body, jsonFilter, opts.Fields, opts.OmitFields)
+			if note != "" {
+				builder.WriteString(note + "\n")
+			}
+			if projected != nil {
+				body, jsonFilter = projected, ""
+			}
+		}
+		listing, isMultistatus := "", false
+		if resp.StatusCode == http.StatusMultiStatus && opts.JSONFilter == "" && !resp.Truncated {
+			listing, isMultistatus = formatMultistatus(resp.Body)
+		}
+		if isMultistatus {
+			builder.WriteString(listing)
+		} else if opts.Format == formatTable {
+			builder.WriteString(renderTableBody(body, jsonFilter, opts.Columns))
+		} else {
+			builder.WriteString(renderTextBody(body, jsonFilter))
+		}
 	}
 
-	if resp.Truncated {
+	if resp.Spilled {
+		fmt.Fprintf(&builder, "\n[truncated: %d/%d bytes — the full body is saved to %s (sha256 %s)]", len(resp.Body), resp.SavedSize, resp.SavedPath, resp.SavedSHA256)
+	} else if resp.Truncated {
 		shown := len(resp.Body)
 		if resp.OriginalSize > 0 {
 			fmt.Fprintf(&builder, "\n[truncated: %d/%d bytes — pass saveTo to fetch the full body to a file]", shown, resp.OriginalSize)
@@ -117,58 +194,85 @@ func minifyJSON(body []byte) []byte {
 	return compact.Bytes()
 }
 
-var textContentTypes = map[string]bool{
-	"application/json":                  true,
-	"application/xml":                   true,
-	"application/javascript":            true,
-	"application/x-www-form-urlencoded": true,
-	"application/x-ndjson":              true,
-	"application/yaml":                  true,
-	"application/x-yaml":                true,
-	"application/toml":                  true,
-	"application/graphql":               true,
-	"application/sql":                   true,
-	"image/svg+xml":                     true,
+func mediaTypeOf(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
 }
 
-func isTextContent(contentType string, body []byte) bool {
+func displayContentType(contentType string) string {
 	mediaType := mediaTypeOf(contentType)
-	if strings.HasPrefix(mediaType, "text/") || textContentTypes[mediaType] ||
-		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
-		return true
+	if mediaType == "" {
+		return "unknown content type"
 	}
-	return looksLikeText(body)
+	return mediaType
 }
 
-// looksLikeText sniffs up to 512 bytes: NUL bytes or invalid UTF-8 mean binary.
-func looksLikeText(body []byte) bool {
-	sample := body
-	if len(sample) > 512 {
-		sample = sample[:512]
+// formatRedirectChain shows every followed redirect, the credentials a hop to
+// another origin did not carry along, and where the request ended up, or ""
+// when no redirect was followed.
+func formatRedirectChain(resp *client.Response) string {
+	if len(resp.Redirects) == 0 && !resp.RedirectLimitReached {
+		return ""
 	}
-	if bytes.IndexByte(sample, 0) >= 0 {
-		return false
+	hops := make([]string, 0, len(resp.Redirects)+1)
+	for _, hop := range resp.Redirects {
+		hops = append(hops, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
 	}
-	if utf8.Valid(sample) {
-		return true
-	}
-	// The 512-byte cut may have split a multi-byte rune; trim up to 3 trailing bytes.
-	for i := 1; i <= 3 && i < len(sample); i++ {
-		if utf8.Valid(sample[:len(sample)-i]) {
-			return true
+	hops = append(hops, resp.FinalURL)
+	chain := fmt.Sprintf("[redirects: %s]", strings.Join(hops, " → "))
+	for i, hop := range resp.Redirects {
+		if len(hop.DroppedHeaders) == 0 {
+			continue
+		}
+		target := resp.FinalURL
+		if i+1 < len(resp.Redirects) {
+			target = resp.Redirects[i+1].URL
 		}
+		chain += fmt.Sprintf("\n[dropped on the redirect to another origin (%s): %s]", target, strings.Join(hop.DroppedHeaders, ", "))
+	}
+	if resp.RedirectLimitReached {
+		chain += fmt.Sprintf("\n[redirect limit reached after %d hops; not followed: Location %s]", len(resp.Redirects), resp.Headers.Get("Location"))
 	}
-	return false
+	return chain
 }
 
-func mediaTypeOf(contentType string) string {
-	return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+// formatRequestEcho shows what was actually sent: method, resolved URL (base
+// URL joined, query merged) and headers, sensitive header and query values
+// censored.
+func formatRequestEcho(resp *client.Response) string {
+	names := make([]string, 0, len(resp.RequestHeaders))
+	for name := range resp.RequestHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headers := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range resp.RequestHeaders[name] {
+			headers = append(headers, fmt.Sprintf("%s: %s", name, censorHeaderValue(name, value)))
+		}
+	}
+
+	echo := fmt.Sprintf("[request: %s %s]", resp.RequestMethod, client.RedactURL(resp.RequestURL))
+	if len(headers) > 0 {
+		echo += fmt.Sprintf("\n[sent headers: %s]", strings.Join(headers, ", "))
+	}
+	return echo
 }
 
-func displayContentType(contentType string) string {
-	mediaType := mediaTypeOf(contentType)
-	if mediaType == "" {
-		return "unknown content type"
+// formatTrailers lists the trailers that arrived after the body, marked so they
+// are not mistaken for headers. Announced trailers without a value are skipped.
+func formatTrailers(trailers http.Header, redaction ResponseRedaction) string {
+	names := make([]string, 0, len(trailers))
+	for name, values := range trailers {
+		if len(values) > 0 {
+			names = append(names, name)
+		}
 	}
-	return mediaType
+	sort.Strings(names)
+	var builder strings.Builder
+	for _, name := range names {
+		for _, value := range trailers[name] {
+			fmt.Fprintf(&builder, "\n%s: %s (trailer)", name, redaction.value(name, value, false))
+		}
+	}
+	return builder.String()
 }
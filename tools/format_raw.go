@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Output formats of http_request.
+const (
+	formatText  = "text"
+	formatRaw   = "raw"
+	formatTable = "table"
+)
+
+// validFormats are the accepted values of the format input and --default-format.
+var validFormats = map[string]bool{formatText: true, formatRaw: true, formatTable: true}
+
+// ValidateFormat checks a --default-format value.
+func ValidateFormat(format string) error {
+	if !validFormats[format] {
+		return fmt.Errorf("unknown output format %q (expected text, raw or table)", format)
+	}
+	return nil
+}
+
+// formatRawResponse renders the response as a literal HTTP/1.1 message: status
+// line, every header, blank line and the body bytes as received — no
+// minification, filtering or annotations. Secret header values are still
+// censored. A body that was not kept (saveTo, binary) is replaced by a one-line
+// note; a truncated body is cut at the limit.
+func formatRawResponse(resp *client.Response, opts FormatOptions) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "HTTP/1.1 %d %s\r\n", resp.StatusCode, resp.StatusText)
+
+	names := make([]string, 0, len(resp.Headers))
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range resp.Headers[name] {
+			fmt.Fprintf(&builder, "%s: %s\r\n", name, opts.Redaction.value(name, value, opts.ShowCookieValues))
+		}
+	}
+	builder.WriteString("\r\n")
+
+	switch {
+	case resp.SavedPath != "":
+		fmt.Fprintf(&builder, "[saved to %s: %d bytes, sha256 %s]", resp.SavedPath, resp.SavedSize, resp.SavedSHA256)
+	case len(resp.Body) > 0 && !isTextContent(resp.ContentType, resp.Body):
+		fmt.Fprintf(&builder, "[binary: %d bytes — pass saveTo to write it to a file]", totalBodySize(resp))
+	default:
+		builder.Write(resp.Body)
+	}
+	return builder.String()
+}
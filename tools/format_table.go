@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// renderTableBody renders a JSON array of objects (after jsonFilter) as a
+// markdown table with one row per element. columns selects and orders the
+// columns and may be GJSON paths such as owner.login; by default every key is
+// a column, in first-seen order. Anything else falls back to the text
+// rendering with a note.
+func renderTableBody(body []byte, jsonFilter string, columns []string) string {
+	data := gjson.ParseBytes(body)
+	if jsonFilter != "" {
+		data = gjson.GetBytes(body, jsonFilter)
+		if !data.Exists() {
+			return fmt.Sprintf("[jsonFilter %q matched nothing — retry without jsonFilter to inspect the body]", jsonFilter)
+		}
+	}
+
+	rows := data.Array()
+	if !data.IsArray() || len(rows) == 0 || !allObjects(rows) {
+		return "[format=table needs a non-empty JSON array of objects — use jsonFilter to select one, e.g. items]\n\n" + renderTextBody(body, jsonFilter)
+	}
+	paths := columns
+	if len(columns) == 0 {
+		columns = objectKeys(rows)
+		paths = make([]string, len(columns))
+		for i, key := range columns {
+			paths[i] = gjson.Escape(key)
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("| " + strings.Join(escapeCells(columns), " | ") + " |\n")
+	builder.WriteString("|" + strings.Repeat(" --- |", len(columns)))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, path := range paths {
+			cells[i] = tableCell(row.Get(path))
+		}
+		builder.WriteString("\n| " + strings.Join(escapeCells(cells), " | ") + " |")
+	}
+	return builder.String()
+}
+
+func allObjects(values []gjson.Result) bool {
+	for _, value := range values {
+		if !value.IsObject() {
+			return false
+		}
+	}
+	return true
+}
+
+// objectKeys returns the union of the objects' keys in first-seen order.
+func objectKeys(objects []gjson.Result) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, object := range objects {
+		object.ForEach(func(key, _ gjson.Result) bool {
+			if !seen[key.String()] {
+				seen[key.String()] = true
+				keys = append(keys, key.String())
+			}
+			return true
+		})
+	}
+	return keys
+}
+
+// tableCell renders scalars as text and nested values as compact JSON.
+func tableCell(value gjson.Result) string {
+	switch {
+	case !value.Exists() || value.Type == gjson.Null:
+		return ""
+	case value.IsObject() || value.IsArray():
+		return string(minifyJSON([]byte(value.Raw)))
+	}
+	return value.String()
+}
+
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(cell)
+	}
+	return escaped
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_RenderTableBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		jsonFilter string
+		columns    []string
+		want       string
+	}{
+		{
+			name: "all keys in first-seen order",
+			body: `[{"id":1,"name":"a|b"},{"id":2,"tags":["x"],"name":null}]`,
+			want: "| id | name | tags |\n| --- | --- | --- |\n| 1 | a\\|b |  |\n| 2 |  | [\"x\"] |",
+		},
+		{
+			name:       "filtered array with selected nested columns",
+			body:       `{"items":[{"id":1,"owner":{"login":"ann"},"x":1}]}`,
+			jsonFilter: "items",
+			columns:    []string{"owner.login", "id"},
+			want:       "| owner.login | id |\n| --- | --- |\n| ann | 1 |",
+		},
+		{
+			name: "multi-line values",
+			body: `[{"text":"line1\nline2"}]`,
+			want: "| text |\n| --- |\n| line1 line2 |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTableBody([]byte(tt.body), tt.jsonFilter, tt.columns); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RenderTableBody_FallsBackForNonArrays(t *testing.T) {
+	got := renderTableBody([]byte(`{"items": [1]}`), "", nil)
+	if !strings.HasPrefix(got, "[format=table needs a non-empty JSON array of objects") || !strings.HasSuffix(got, `{"items":[1]}`) {
+		t.Errorf("expected note and text fallback, got:\n%s", got)
+	}
+}
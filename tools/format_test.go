@@ -278,11 +278,151 @@ func Test_FormatResponse_SavedToFile(t *testing.T) {
 		ContentType: "application/json; charset=utf-8",
 		SavedPath:   "C:\\temp\\out.json",
 		SavedSize:   245891,
+		SavedSHA256: "9f86d081",
 	}
 
 	result := FormatResponse(resp, FormatOptions{})
 
-	if !strings.Contains(result, "[saved to C:\\temp\\out.json: 245891 bytes, application/json]") {
+	if !strings.Contains(result, "[saved to C:\\temp\\out.json: 245891 bytes, application/json, sha256 9f86d081]") {
 		t.Errorf("expected saved-file summary, got: %s", result)
 	}
 }
+
+func Test_FormatResponse_SpilledBody(t *testing.T) {
+	resp := &client.Response{
+		StatusCode:  200,
+		StatusText:  "OK",
+		ContentType: "text/plain",
+		Body:        []byte("first bytes"),
+		Truncated:   true,
+		SavedPath:   "/tmp/rest-api-mcp-response-1",
+		SavedSize:   5000000,
+		SavedSHA256: "9f86d081",
+		Spilled:     true,
+	}
+
+	result := FormatResponse(resp, FormatOptions{})
+
+	if !strings.Contains(result, "first bytes\n[truncated: 11/5000000 bytes — the full body is saved to /tmp/rest-api-mcp-response-1 (sha256 9f86d081)]") {
+		t.Errorf("expected preview and spill note, got: %s", result)
+	}
+}
+
+func Test_FormatResponse_Cached(t *testing.T) {
+	resp := &client.Response{StatusCode: 200, StatusText: "OK", Body: []byte("ok"), Cached: true, CacheAge: 95*time.Second + 300*time.Millisecond}
+
+	got := FormatResponse(resp, FormatOptions{})
+	if !strings.HasPrefix(got, "200 OK\n[cached 1m35s ago — pass cache: refresh for current data]\n\nok") {
+		t.Errorf("expected cache note under the status line, got:\n%s", got)
+	}
+}
+
+func Test_FormatResponse_QueueWait(t *testing.T) {
+	resp := &client.Response{StatusCode: 200, StatusText: "OK", Body: []byte("ok"), QueueWait: 1234567 * time.Microsecond}
+
+	got := FormatResponse(resp, FormatOptions{})
+	if !strings.HasPrefix(got, "200 OK\n[queued 1.235s for a free request slot]") {
+		t.Errorf("expected queue wait under the status line, got:\n%s", got)
+	}
+}
+
+func Test_FormatResponse_Trailers(t *testing.T) {
+	resp := &client.Response{
+		StatusCode: 200,
+		StatusText: "OK",
+		Headers:    http.Header{"Content-Type": {"text/plain"}},
+		Trailers:   http.Header{"Grpc-Status": {"0"}, "X-Announced": nil},
+		Body:       []byte("ok"),
+	}
+
+	if got := FormatResponse(resp, FormatOptions{}); strings.Contains(got, "Grpc-Status") {
+		t.Errorf("expected no trailers without IncludeHeaders, got:\n%s", got)
+	}
+	got := FormatResponse(resp, FormatOptions{IncludeHeaders: true})
+	if !strings.Contains(got, "Content-Type: text/plain\nGrpc-Status: 0 (trailer)") || strings.Contains(got, "X-Announced") {
+		t.Errorf("expected the trailer after the headers, got:\n%s", got)
+	}
+}
+
+func Test_FormatResponse_Verbose(t *testing.T) {
+	resp := &client.Response{StatusCode: 200, StatusText: "OK", Body: []byte("ok"), WireLog: "> GET / HTTP/1.1\n>\n< HTTP/1.1 200 OK\n<"}
+
+	if got := FormatResponse(resp, FormatOptions{}); strings.Contains(got, "> GET") {
+		t.Errorf("expected no wire log without Verbose, got:\n%s", got)
+	}
+	got := FormatResponse(resp, FormatOptions{Verbose: true})
+	if !strings.HasPrefix(got, "> GET / HTTP/1.1") || !strings.Contains(got, "<\n\n200 OK") {
+		t.Errorf("expected wire log before the status line, got:\n%s", got)
+	}
+}
+
+func Test_FormatResponse_RedirectChain(t *testing.T) {
+	resp := &client.Response{
+		StatusCode: 200, StatusText: "OK",
+		FinalURL:  "https://app.example.com/home",
+		Redirects: []client.RedirectHop{{URL: "https://app.example.com/login", StatusCode: 302}, {URL: "https://sso.example.com/auth", StatusCode: 303}},
+	}
+	got := FormatResponse(resp, FormatOptions{})
+	want := "200 OK\n[redirects: https://app.example.com/login (302) → https://sso.example.com/auth (303) → https://app.example.com/home]"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	limited := &client.Response{
+		StatusCode: 302, StatusText: "Found", FinalURL: "https://b.example.com/", RedirectLimitReached: true,
+		Redirects: []client.RedirectHop{{URL: "https://a.example.com/", StatusCode: 302}},
+		Headers:   http.Header{"Location": {"https://c.example.com/"}},
+	}
+	if got := FormatResponse(limited, FormatOptions{}); !strings.Contains(got, "[redirect limit reached after 1 hops; not followed: Location https://c.example.com/]") {
+		t.Errorf("expected limit note, got:\n%s", got)
+	}
+
+	if got := FormatResponse(&client.Response{StatusCode: 200, StatusText: "OK", FinalURL: "https://x/"}, FormatOptions{}); got != "200 OK" {
+		t.Errorf("expected no chain without redirects, got:\n%s", got)
+	}
+
+	crossOrigin := &client.Response{
+		StatusCode: 200, StatusText: "OK", FinalURL: "https://cdn.example.com/file",
+		Redirects: []client.RedirectHop{{URL: "https://api.example.com/file", StatusCode: 302, DroppedHeaders: []string{"Authorization", "X-Api-Key"}}},
+	}
+	if got := FormatResponse(crossOrigin, FormatOptions{}); !strings.Contains(got, "[dropped on the redirect to another origin (https://cdn.example.com/file): Authorization, X-Api-Key]") {
+		t.Errorf("expected the dropped headers, got:\n%s", got)
+	}
+}
+
+func Test_FormatResponse_EchoRequest(t *testing.T) {
+	resp := &client.Response{
+		StatusCode:     201,
+		StatusText:     "Created",
+		RequestMethod:  "POST",
+		RequestURL:     "https://api.example.com/v1/orders?api_version=2&access_token=secret",
+		RequestHeaders: http.Header{"Authorization": {"Bearer secret"}, "Content-Type": {"application/json"}},
+	}
+	got := FormatResponse(resp, FormatOptions{EchoRequest: true})
+	want := "[request: POST https://api.example.com/v1/orders?api_version=2&access_token=***]\n[sent headers: Authorization: ***, Content-Type: application/json]\n\n201 Created"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_FormatResponse_Raw(t *testing.T) {
+	resp := &client.Response{
+		StatusCode:  200,
+		StatusText:  "OK",
+		Headers:     http.Header{"Content-Type": {"application/json"}, "Date": {"Mon, 01 Jan 2024 00:00:00 GMT"}},
+		ContentType: "application/json",
+		Body:        []byte("{\n  \"a\": 1\n}"),
+		FinalURL:    "https://x/",
+		Redirects:   []client.RedirectHop{{URL: "https://y/", StatusCode: 302}},
+	}
+	got := FormatResponse(resp, FormatOptions{Format: "raw", JSONFilter: "a", EchoRequest: true})
+	want := "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nDate: Mon, 01 Jan 2024 00:00:00 GMT\r\n\r\n{\n  \"a\": 1\n}"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+
+	binary := &client.Response{StatusCode: 200, StatusText: "OK", ContentType: "image/png", Body: []byte{0x89, 'P', 'N', 'G', 0}}
+	if got := FormatResponse(binary, FormatOptions{Format: "raw"}); !strings.HasSuffix(got, "\r\n\r\n[binary: 5 bytes — pass saveTo to write it to a file]") {
+		t.Errorf("expected binary note, got %q", got)
+	}
+}
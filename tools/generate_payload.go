@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxSchemaDepth stops generation for schemas that nest (or $ref themselves) too deeply.
+const maxSchemaDepth = 12
+
+type GeneratePayloadInput struct {
+	Schema map[string]any `json:"schema" jsonschema:"JSON Schema the payload must match (type, properties, required, items, enum, const, format, minimum/maximum, minLength/maxLength, minItems/maxItems, oneOf/anyOf/allOf, local $ref)"`
+	Count  int            `json:"count,omitempty" jsonschema:"Number of payloads to generate, returned as a JSON array (default 1, at most 100)"`
+	Seed   uint64         `json:"seed,omitempty" jsonschema:"Seed for reproducible output (default: random)"`
+}
+
+const generatePayloadDescription = "Generate realistic fake JSON matching a JSON Schema: names, emails, addresses, phone numbers, UUIDs, dates and numbers within the schema's bounds, chosen by format and property name. " +
+	"Use to create varied test entities for POST/PUT bodies instead of inventing data. Emails use example.com domains. The same seed gives the same output."
+
+func makeGeneratePayloadHandler() func(context.Context, *mcp.CallToolRequest, GeneratePayloadInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input GeneratePayloadInput) (*mcp.CallToolResult, any, error) {
+		if len(input.Schema) == 0 {
+			return errorResult("schema is required"), nil, nil
+		}
+		count := input.Count
+		if count == 0 {
+			count = 1
+		}
+		if count < 0 || count > 100 {
+			return errorResult("count must be between 1 and 100"), nil, nil
+		}
+		seed := input.Seed
+		if seed == 0 {
+			seed = rand.Uint64()
+		}
+		generator := payloadGenerator{fake: newFaker(seed), root: input.Schema}
+
+		payloads := make([]any, count)
+		for i := range payloads {
+			payload, err := generator.value(input.Schema, "", 0)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Cannot generate a payload: %s", err)), nil, nil
+			}
+			payloads[i] = payload
+		}
+		var result any = payloads
+		if input.Count == 0 {
+			result = payloads[0]
+		}
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Cannot encode the payload: %s", err)), nil, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(encoded)}}}, nil, nil
+	}
+}
+
+// payloadGenerator builds values for a JSON Schema; root resolves local $refs.
+type payloadGenerator struct {
+	fake faker
+	root map[string]any
+}
+
+// value generates one value for schema. name is the property holding it, used
+// to pick realistic strings and numbers (email, city, age, ...).
+func (g payloadGenerator) value(schema map[string]any, name string, depth int) (any, error) {
+	if depth > maxSchemaDepth {
+		return nil, fmt.Errorf("schema nests deeper than %d levels (a recursive $ref?)", maxSchemaDepth)
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := g.resolveRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		return g.value(resolved, name, depth+1)
+	}
+	if constant, ok := schema["const"]; ok {
+		return constant, nil
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[g.fake.rng.IntN(len(enum))], nil
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		return g.value(mergeAllOf(schema, allOf), name, depth+1)
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if branches, ok := schema[keyword].([]any); ok && len(branches) > 0 {
+			branch, _ := branches[g.fake.rng.IntN(len(branches))].(map[string]any)
+			return g.value(branch, name, depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		properties, _ := schema["properties"].(map[string]any)
+		object := make(map[string]any, len(properties))
+		for _, property := range slices.Sorted(maps.Keys(properties)) {
+			propertySchema, _ := properties[property].(map[string]any)
+			propertyValue, err := g.value(propertySchema, property, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", property, err)
+			}
+			object[property] = propertyValue
+		}
+		return object, nil
+	case "array":
+		low := int(schemaNumber(schema, "minItems", 1))
+		high := int(schemaNumber(schema, "maxItems", float64(max(low, 3))))
+		if high < low {
+			return nil, fmt.Errorf("maxItems %d is below minItems %d", high, low)
+		}
+		items, _ := schema["items"].(map[string]any)
+		array := make([]any, low+g.fake.rng.IntN(high-low+1))
+		for i := range array {
+			item, err := g.value(items, strings.TrimSuffix(name, "s"), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = item
+		}
+		return array, nil
+	case "integer", "number":
+		return g.number(schema, name)
+	case "boolean":
+		return g.fake.rng.IntN(2) == 1, nil
+	case "null":
+		return nil, nil
+	}
+	return g.string(schema, name), nil
+}
+
+func (g payloadGenerator) resolveRef(ref string) (map[string]any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("only local $ref values (#/...) are supported, got %q", ref)
+	}
+	var current any = g.root
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, _ := current.(map[string]any)
+		if current = object[token]; current == nil {
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+	}
+	resolved, ok := current.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$ref %q is not a schema", ref)
+	}
+	return resolved, nil
+}
+
+// mergeAllOf folds the allOf branches into one schema: properties are
+// combined, other keywords of later branches win.
+func mergeAllOf(schema map[string]any, allOf []any) map[string]any {
+	merged := maps.Clone(schema)
+	delete(merged, "allOf")
+	properties := map[string]any{}
+	if own, ok := schema["properties"].(map[string]any); ok {
+		maps.Copy(properties, own)
+	}
+	for _, branch := range allOf {
+		branchSchema, _ := branch.(map[string]any)
+		for keyword, value := range branchSchema {
+			if branchProperties, ok := value.(map[string]any); ok && keyword == "properties" {
+				maps.Copy(properties, branchProperties)
+				continue
+			}
+			merged[keyword] = value
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+		merged["type"] = "object"
+	}
+	return merged
+}
+
+// schemaType returns the schema's type, preferring a non-null one from a
+// type list and inferring it from other keywords when missing.
+func schemaType(schema map[string]any) string {
+	switch typed := schema["type"].(type) {
+	case string:
+		return typed
+	case []any:
+		for _, candidate := range typed {
+			if name, ok := candidate.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return "string"
+}
+
+func schemaNumber(schema map[string]any, keyword string, fallback float64) float64 {
+	if value, ok := schema[keyword].(float64); ok {
+		return value
+	}
+	return fallback
+}
+
+func (g payloadGenerator) number(schema map[string]any, name string) (any, error) {
+	lowName := strings.ToLower(name)
+	low, high := 0.0, 1000.0
+	switch {
+	case lowName == "age":
+		low, high = 18, 90
+	case strings.Contains(lowName, "year"):
+		low, high = 1990, float64(time.Now().Year())
+	case strings.Contains(lowName, "price") || strings.Contains(lowName, "amount"):
+		low, high = 1, 500
+	}
+	integer := schemaType(schema) == "integer"
+	_, hasMinimum := schema["minimum"].(float64)
+	low = schemaNumber(schema, "minimum", low)
+	if exclusive, ok := schema["exclusiveMinimum"].(float64); ok {
+		low, hasMinimum = exclusive+0.01, true
+		if integer {
+			low = math.Floor(exclusive) + 1
+		}
+	}
+	if maximum, ok := schema["maximum"].(float64); ok {
+		high = maximum
+	} else if exclusive, ok := schema["exclusiveMaximum"].(float64); ok {
+		high = exclusive - 0.01
+		if integer {
+			high = math.Ceil(exclusive) - 1
+		}
+	} else if hasMinimum && high < low {
+		high = low + 1000
+	}
+	if integer {
+		low, high = math.Ceil(low), math.Floor(high)
+	}
+	if high < low {
+		return nil, fmt.Errorf("%s: no %s between minimum and maximum", name, schemaType(schema))
+	}
+	if integer {
+		return int64(low) + g.fake.rng.Int64N(int64(high-low)+1), nil
+	}
+	return math.Round((low+g.fake.rng.Float64()*(high-low))*100) / 100, nil
+}
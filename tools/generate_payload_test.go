@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_GeneratePayload_MatchesSchema(t *testing.T) {
+	var schema map[string]any
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"$defs": {"address": {"type": "object", "properties": {"city": {"type": "string"}, "zip": {"type": "string"}}}},
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"email": {"type": "string"},
+			"firstName": {"type": "string"},
+			"age": {"type": "integer"},
+			"score": {"type": "number", "minimum": 1, "maximum": 2},
+			"role": {"enum": ["admin", "viewer"]},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 4}, "minItems": 2, "maxItems": 2},
+			"address": {"$ref": "#/$defs/address"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"active": {"type": ["boolean", "null"]}
+		}
+	}`), &schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := makeGeneratePayloadHandler()
+	result, _, _ := handler(context.Background(), nil, GeneratePayloadInput{Schema: schema, Count: 5})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", extractText(result))
+	}
+	var payloads []struct {
+		ID        string         `json:"id"`
+		Email     string         `json:"email"`
+		FirstName string         `json:"firstName"`
+		Age       int            `json:"age"`
+		Score     float64        `json:"score"`
+		Role      string         `json:"role"`
+		Tags      []string       `json:"tags"`
+		Address   map[string]any `json:"address"`
+		CreatedAt string         `json:"createdAt"`
+		Active    *bool          `json:"active"`
+	}
+	if err := json.Unmarshal([]byte(extractText(result)), &payloads); err != nil {
+		t.Fatalf("output is not the expected JSON: %v\n%s", err, extractText(result))
+	}
+	if len(payloads) != 5 {
+		t.Fatalf("got %d payloads, want 5", len(payloads))
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for _, p := range payloads {
+		if !uuid.MatchString(p.ID) || !strings.HasSuffix(p.Email, ".com") && !strings.HasSuffix(p.Email, ".org") && !strings.HasSuffix(p.Email, ".net") {
+			t.Errorf("id %q / email %q not realistic", p.ID, p.Email)
+		}
+		if p.FirstName == "" || p.Age < 18 || p.Age > 90 || p.Score < 1 || p.Score > 2 {
+			t.Errorf("firstName %q, age %d, score %v out of range", p.FirstName, p.Age, p.Score)
+		}
+		if (p.Role != "admin" && p.Role != "viewer") || len(p.Tags) != 2 || len(p.Tags[0]) > 4 {
+			t.Errorf("role %q, tags %v do not match the schema", p.Role, p.Tags)
+		}
+		if p.Address["city"] == "" || p.Address["zip"] == "" || p.Active == nil || !strings.Contains(p.CreatedAt, "T") {
+			t.Errorf("address %v, active %v, createdAt %q do not match the schema", p.Address, p.Active, p.CreatedAt)
+		}
+	}
+}
+
+func Test_GeneratePayload_SeedRepeats(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	handler := makeGeneratePayloadHandler()
+	first, _, _ := handler(context.Background(), nil, GeneratePayloadInput{Schema: schema, Seed: 7})
+	second, _, _ := handler(context.Background(), nil, GeneratePayloadInput{Schema: schema, Seed: 7})
+	if extractText(first) != extractText(second) {
+		t.Errorf("same seed gave %s and %s", extractText(first), extractText(second))
+	}
+}
+
+func Test_GeneratePayload_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"recursive ref", `{"$ref": "#"}`, "only local"},
+		{"self reference", `{"$defs": {"node": {"$ref": "#/$defs/node"}}, "$ref": "#/$defs/node"}`, "deeper than"},
+		{"impossible range", `{"type": "integer", "minimum": 5, "maximum": 1}`, "no integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]any
+			json.Unmarshal([]byte(tt.schema), &schema)
+			result, _, _ := makeGeneratePayloadHandler()(context.Background(), nil, GeneratePayloadInput{Schema: schema})
+			if !result.IsError || !strings.Contains(extractText(result), tt.wantErr) {
+				t.Errorf("expected error mentioning %q, got: %s", tt.wantErr, extractText(result))
+			}
+		})
+	}
+}
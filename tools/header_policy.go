@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HeaderPolicy restricts which request headers the agent may set. Headers
+// outside it are dropped, not rejected, so a call still goes out without
+// them. The zero value allows every header; server defaults are never
+// filtered.
+type HeaderPolicy struct {
+	Allowed []string // canonical names; a trailing * allows every name with that prefix
+}
+
+// ParseHeaderList parses a comma-separated header list such as
+// "accept, x-request-*" into canonical header names.
+func ParseHeaderList(value string) ([]string, error) {
+	var names []string
+	for _, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		prefix, wildcard := strings.CutSuffix(name, "*")
+		if prefix == "" || strings.ContainsAny(prefix, " \t:*\"(),/;<=>?@[\\]{}") {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		if wildcard {
+			names = append(names, http.CanonicalHeaderKey(prefix)+"*")
+		} else {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+	}
+	return names, nil
+}
+
+// allows reports whether the policy lets the agent set the header name.
+func (p HeaderPolicy) allows(name string) bool {
+	if len(p.Allowed) == 0 {
+		return true
+	}
+	return matchesHeaderName(p.Allowed, name)
+}
+
+// matchesHeaderName reports whether name is one of the canonical names from
+// ParseHeaderList, or starts with the prefix of one ending in *.
+func matchesHeaderName(names []string, name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, listed := range names {
+		if prefix, wildcard := strings.CutSuffix(listed, "*"); listed == name || (wildcard && strings.HasPrefix(name, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops the headers the policy does not allow and logs them. The note
+// tells the agent which were dropped; it is "" when none were.
+func (p HeaderPolicy) filter(headers map[string]string) (map[string]string, string) {
+	if len(p.Allowed) == 0 || len(headers) == 0 {
+		return headers, ""
+	}
+	kept := make(map[string]string, len(headers))
+	var dropped []string
+	for name, value := range headers {
+		if p.allows(name) {
+			kept[name] = value
+		} else {
+			dropped = append(dropped, http.CanonicalHeaderKey(name))
+		}
+	}
+	if len(dropped) == 0 {
+		return headers, ""
+	}
+	sort.Strings(dropped)
+	log.Printf("dropped request headers not in --allowed-request-headers: %s", strings.Join(dropped, ", "))
+	return kept, fmt.Sprintf("[dropped request headers not allowed by server policy (--allowed-request-headers): %s]\n\n", strings.Join(dropped, ", "))
+}
+
+// describe lists the allowed headers for the tool description; "" without a policy.
+func (p HeaderPolicy) describe() string {
+	if len(p.Allowed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Allowed request headers: %s — other headers are dropped by server policy.", strings.Join(p.Allowed, ", "))
+}
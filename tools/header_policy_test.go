@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_ParseHeaderList_Canonical(t *testing.T) {
+	names, err := ParseHeaderList(" accept, content-type,,x-trace-* ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Accept", "Content-Type", "X-Trace-*"}; !slices.Equal(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	for _, invalid := range []string{"*", "x-a*b", "bad header", "host:"} {
+		if _, err := ParseHeaderList(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func Test_HeaderPolicy_Filter(t *testing.T) {
+	policy := HeaderPolicy{Allowed: []string{"Accept", "X-Trace-*"}}
+	headers := map[string]string{"accept": "application/json", "X-Trace-Id": "1", "Host": "internal", "X-Forwarded-For": "10.0.0.1"}
+
+	kept, note := policy.filter(headers)
+
+	if len(kept) != 2 || kept["accept"] != "application/json" || kept["X-Trace-Id"] != "1" {
+		t.Errorf("kept = %v", kept)
+	}
+	if !strings.Contains(note, "--allowed-request-headers): Host, X-Forwarded-For]") {
+		t.Errorf("unexpected note: %q", note)
+	}
+	if kept, note := (HeaderPolicy{}).filter(headers); len(kept) != len(headers) || note != "" {
+		t.Errorf("expected no policy to keep every header, got %v, %q", kept, note)
+	}
+}
+
+func Test_HttpRequestHandler_DropsDisallowedHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	settings := Settings{Headers: HeaderPolicy{Allowed: []string{"Accept"}}}
+	handler := makeHandler(newTestClient(""), settings, NewHistory(10), NewStats(), NewVariables())
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, Headers: map[string]StringValues{
+		"Accept":          {"application/json"},
+		"X-Forwarded-For": {"127.0.0.1"},
+	}})
+
+	if received.Get("Accept") != "application/json" || received.Get("X-Forwarded-For") != "" {
+		t.Errorf("server received %v", received)
+	}
+	if text := extractText(result); !strings.HasPrefix(text, "[dropped request headers not allowed by server policy (--allowed-request-headers): X-Forwarded-For]") {
+		t.Errorf("expected the dropped header to be reported, got: %s", text)
+	}
+}
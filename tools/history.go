@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// historyCapacity bounds the in-memory session history; older entries are dropped.
+const historyCapacity = 100
+
+// HistoryEntry is one completed http_request call of the current session.
+type HistoryEntry struct {
+	ID          int
+	Method      string
+	URL         string            // resolved request URL, including query
+	Headers     map[string]string // per-request headers the agent sent (server defaults are not recorded)
+	Body        string
+	StatusCode  int
+	ContentType string
+	JSONKeys    []string // sorted top-level keys of a JSON object response
+	Duration    time.Duration
+	Time        time.Time
+	RequestID   string // value of the --request-id-header sent with the call, if any
+}
+
+// History keeps the most recent http_request calls of the session in memory.
+type History struct {
+	mu       sync.Mutex
+	entries  []HistoryEntry
+	nextID   int
+	capacity int
+	onAdd    func()      // called after every Add, outside the lock; may be nil
+	pages    pageCache   // whole bodies of recent paged GETs, for offset/limit
+	bodies   savedBodies // oversized bodies summarize saved, served as history://{id}/body
+}
+
+func NewHistory(capacity int) *History {
+	return &History{capacity: capacity, nextID: 1}
+}
+
+// Add stores the entry under the next sequential ID and returns that ID.
+func (h *History) Add(entry HistoryEntry) int {
+	h.mu.Lock()
+	entry.ID = h.nextID
+	h.nextID++
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.capacity {
+		h.entries = h.entries[len(h.entries)-h.capacity:]
+	}
+	onAdd := h.onAdd
+	h.mu.Unlock()
+
+	if onAdd != nil {
+		onAdd()
+	}
+	return entry.ID
+}
+
+// notifyAdd sets the function Add calls after storing an entry.
+func (h *History) notifyAdd(onAdd func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onAdd = onAdd
+}
+
+// Select returns the entries with the given IDs in session order, or all
+// entries when ids is empty. Unknown IDs are reported in missing.
+func (h *History) Select(ids []int) (selected []HistoryEntry, missing []int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(ids) == 0 {
+		return append([]HistoryEntry{}, h.entries...), nil
+	}
+	wanted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for _, entry := range h.entries {
+		if wanted[entry.ID] {
+			selected = append(selected, entry)
+			delete(wanted, entry.ID)
+		}
+	}
+	for id := range wanted {
+		missing = append(missing, id)
+	}
+	sort.Ints(missing)
+	return selected, missing
+}
+
+func newHistoryEntry(params client.RequestParams, resp *client.Response) HistoryEntry {
+	return HistoryEntry{
+		Method:      params.Method,
+		URL:         resp.RequestURL,
+		Headers:     params.Headers,
+		Body:        params.Body,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.ContentType,
+		JSONKeys:    topLevelJSONKeys(resp.Body),
+		Duration:    resp.Duration,
+		Time:        time.Now(),
+	}
+}
+
+// topLevelJSONKeys returns the sorted keys of a JSON object body, or nil for
+// anything else (arrays, non-JSON, truncated JSON).
+func topLevelJSONKeys(body []byte) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
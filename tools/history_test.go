@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_History_AddAndSelect(t *testing.T) {
+	history := NewHistory(10)
+	firstID := history.Add(HistoryEntry{Method: "GET", URL: "http://x.test/a"})
+	secondID := history.Add(HistoryEntry{Method: "POST", URL: "http://x.test/b"})
+
+	if firstID != 1 || secondID != 2 {
+		t.Fatalf("expected sequential IDs 1, 2, got %d, %d", firstID, secondID)
+	}
+
+	all, missing := history.Select(nil)
+	if len(all) != 2 || len(missing) != 0 {
+		t.Fatalf("expected 2 entries and no missing, got %d / %v", len(all), missing)
+	}
+
+	selected, missing := history.Select([]int{2, 7})
+	if len(selected) != 1 || selected[0].Method != "POST" {
+		t.Errorf("expected only entry 2, got %+v", selected)
+	}
+	if !reflect.DeepEqual(missing, []int{7}) {
+		t.Errorf("expected missing [7], got %v", missing)
+	}
+}
+
+func Test_History_DropsOldestBeyondCapacity(t *testing.T) {
+	history := NewHistory(2)
+	history.Add(HistoryEntry{URL: "1"})
+	history.Add(HistoryEntry{URL: "2"})
+	history.Add(HistoryEntry{URL: "3"})
+
+	entries, _ := history.Select(nil)
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].ID != 3 {
+		t.Errorf("expected entries 2 and 3 to remain, got %+v", entries)
+	}
+}
+
+func Test_TopLevelJSONKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"object", `{"name":"a","id":1}`, []string{"id", "name"}},
+		{"array", `[{"id":1}]`, nil},
+		{"not json", `hello`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := topLevelJSONKeys([]byte(tt.body)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("topLevelJSONKeys(%s) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
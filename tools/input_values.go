@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// StringValues is one header or query parameter in the tool input: a string,
+// or an array of strings for several values (Accept, Cookie, ?id=1&id=2).
+type StringValues []string
+
+func (v *StringValues) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*v = StringValues{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("value must be a string or an array of strings")
+	}
+	*v = multiple
+	return nil
+}
+
+// requestInputSchema is the request tool's input schema: inferred from
+// HttpRequestInput, with header and query values accepting a string or an
+// array.
+func requestInputSchema() (*jsonschema.Schema, error) {
+	schema, err := jsonschema.For[HttpRequestInput](nil)
+	if err != nil {
+		return nil, fmt.Errorf("request input schema: %w", err)
+	}
+	for _, name := range []string{"headers", "queryParams"} {
+		schema.Properties[name].AdditionalProperties = &jsonschema.Schema{AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		}}
+	}
+	return schema, nil
+}
+
+// joinHeaders merges the agent's headers into one value per canonical name, so
+// "authorization" and "Authorization" cannot both reach the client. Values are
+// combined in name order, then array order: Cookie pairs with "; ", every other
+// header as a comma-separated list (RFC 9110). A header whose values are all
+// empty stays "", which removes a default header.
+func joinHeaders(headers map[string]StringValues) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	grouped := make(map[string][]string, len(headers))
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		canonical := http.CanonicalHeaderKey(name)
+		grouped[canonical] = append(grouped[canonical], headers[name]...)
+	}
+	joined := make(map[string]string, len(grouped))
+	for name, values := range grouped {
+		separator := ", "
+		if name == "Cookie" {
+			separator = "; "
+		}
+		joined[name] = strings.Join(slices.DeleteFunc(values, func(value string) bool { return value == "" }), separator)
+	}
+	return joined
+}
+
+// QueryParam is one entry of the ordered query parameter list.
+type QueryParam struct {
+	Name  string `json:"name" jsonschema:"Parameter name"`
+	Value string `json:"value,omitempty" jsonschema:"Parameter value"`
+}
+
+// queryParams flattens the agent's query parameters into the order they are
+// sent: queryParams by name, each array in order, then orderedQueryParams.
+func queryParams(input HttpRequestInput) []client.QueryParam {
+	var params []client.QueryParam
+	for _, name := range slices.Sorted(maps.Keys(input.QueryParams)) {
+		for _, value := range input.QueryParams[name] {
+			params = append(params, client.QueryParam{Name: name, Value: value})
+		}
+	}
+	for _, param := range input.OrderedQueryParams {
+		params = append(params, client.QueryParam{Name: param.Name, Value: param.Value})
+	}
+	return params
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func Test_joinHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		want    map[string]string
+	}{
+		{"single value", `{"accept":"application/json"}`, map[string]string{"Accept": "application/json"}},
+		{"array value", `{"Accept":["application/json","text/plain"]}`, map[string]string{"Accept": "application/json, text/plain"}},
+		{"cookies joined with semicolons", `{"Cookie":["a=1","b=2"]}`, map[string]string{"Cookie": "a=1; b=2"}},
+		{"case duplicates merged", `{"X-Tag":"b","x-tag":"c"}`, map[string]string{"X-Tag": "b, c"}},
+		{"empty value removes", `{"authorization":""}`, map[string]string{"Authorization": ""}},
+		{"empty duplicate ignored", `{"Authorization":"Bearer x","authorization":""}`, map[string]string{"Authorization": "Bearer x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers map[string]StringValues
+			if err := json.Unmarshal([]byte(tt.headers), &headers); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got := joinHeaders(headers)
+			if len(got) != len(tt.want) {
+				t.Fatalf("joinHeaders = %v, want %v", got, tt.want)
+			}
+			for name, value := range tt.want {
+				if got[name] != value {
+					t.Errorf("%s = %q, want %q", name, got[name], value)
+				}
+			}
+		})
+	}
+}
+
+func Test_StringValues_RejectsObjects(t *testing.T) {
+	var headers map[string]StringValues
+	if err := json.Unmarshal([]byte(`{"Accept":{"type":"json"}}`), &headers); err == nil {
+		t.Error("expected an error for an object header value")
+	}
+}
+
+func Test_Register_HeadersAcceptStringsAndArrays(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, newTestClient(""), client.Config{}, Settings{}, nil)
+	session := connectTestSession(t, mcpServer)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "http_request",
+		Arguments: map[string]any{"method": "GET", "url": server.URL, "headers": map[string]any{
+			"x-trace": "abc",
+			"Accept":  []string{"application/json", "text/plain"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("calling http_request: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got: %s", extractText(result))
+	}
+	if got.Get("X-Trace") != "abc" || got.Get("Accept") != "application/json, text/plain" {
+		t.Errorf("server saw X-Trace %q, Accept %q", got.Get("X-Trace"), got.Get("Accept"))
+	}
+}
+
+func Test_requestInputSchema_StringOrArrayValues(t *testing.T) {
+	schema, err := requestInputSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"headers", "queryParams"} {
+		if values := schema.Properties[name].AdditionalProperties; values == nil || len(values.AnyOf) != 2 {
+			t.Errorf("%s values = %+v, want a string or an array", name, values)
+		}
+	}
+}
+
+func Test_queryParams(t *testing.T) {
+	var input HttpRequestInput
+	err := json.Unmarshal([]byte(`{
+		"queryParams": {"tag": ["a", "b"], "limit": "10"},
+		"orderedQueryParams": [{"name": "sort", "value": "name"}, {"name": "sort", "value": "-date"}]
+	}`), &input)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got := queryParams(input)
+	want := []client.QueryParam{{Name: "limit", Value: "10"}, {Name: "tag", Value: "a"}, {Name: "tag", Value: "b"}, {Name: "sort", Value: "name"}, {Name: "sort", Value: "-date"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("queryParams = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxDiffLines caps the differences listed per compared body.
+const maxDiffLines = 20
+
+// diffBodies describes how body differs from baseline: nil when they are
+// equal. JSON bodies are compared field by field (GJSON paths), ignoring the
+// paths in ignore and anything below them; other bodies byte for byte.
+func diffBodies(baseline, body []byte, ignore []string) []string {
+	if gjson.ValidBytes(baseline) && gjson.ValidBytes(body) && len(bytes.TrimSpace(baseline)) > 0 && len(bytes.TrimSpace(body)) > 0 {
+		return diffJSON(flattenJSON(gjson.ParseBytes(baseline)), flattenJSON(gjson.ParseBytes(body)), ignore)
+	}
+	if bytes.Equal(baseline, body) {
+		return nil
+	}
+	baseLines, lines := strings.Split(string(baseline), "\n"), strings.Split(string(body), "\n")
+	line := 0
+	for line < len(baseLines) && line < len(lines) && baseLines[line] == lines[line] {
+		line++
+	}
+	return []string{fmt.Sprintf("body differs (%d vs %d bytes), first on line %d", len(baseline), len(body), line+1)}
+}
+
+func diffJSON(baseline, other map[string]string, ignore []string) []string {
+	paths := make([]string, 0, len(baseline)+len(other))
+	for path := range baseline {
+		paths = append(paths, path)
+	}
+	for path := range other {
+		if _, ok := baseline[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	var differences []string
+	for _, path := range paths {
+		if ignored(path, ignore) {
+			continue
+		}
+		before, inBaseline := baseline[path]
+		after, inOther := other[path]
+		if inBaseline && inOther && before == after {
+			continue
+		}
+		if !inBaseline {
+			before = "(missing)"
+		}
+		if !inOther {
+			after = "(missing)"
+		}
+		differences = append(differences, fmt.Sprintf("%s: %s → %s", displayPath(path), clip(before), clip(after)))
+	}
+	if len(differences) > maxDiffLines {
+		more := len(differences) - maxDiffLines
+		differences = append(differences[:maxDiffLines], fmt.Sprintf("... and %d more", more))
+	}
+	return differences
+}
+
+// flattenJSON maps the GJSON path of every scalar, empty object and empty
+// array in value to its compacted JSON.
+func flattenJSON(value gjson.Result) map[string]string {
+	leaves := make(map[string]string)
+	var walk func(path string, value gjson.Result)
+	walk = func(path string, value gjson.Result) {
+		if !value.IsObject() && !value.IsArray() {
+			leaves[path] = value.Raw
+			return
+		}
+		empty := true
+		index := 0
+		value.ForEach(func(key, child gjson.Result) bool {
+			empty = false
+			name := gjson.Escape(key.String())
+			if value.IsArray() {
+				name = fmt.Sprint(index)
+				index++
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			walk(name, child)
+			return true
+		})
+		if empty {
+			leaves[path] = value.Raw
+		}
+	}
+	walk("", value)
+	return leaves
+}
+
+func ignored(path string, ignore []string) bool {
+	for _, prefix := range ignore {
+		if path == prefix || strings.HasPrefix(path, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(body)"
+	}
+	return path
+}
+
+func clip(value string) string {
+	const maxValue = 60
+	if len(value) <= maxValue {
+		return value
+	}
+	end := maxValue
+	for !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end] + "…"
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_DiffBodies(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline string
+		body     string
+		ignore   []string
+		want     []string
+	}{
+		{"equal JSON, other formatting", `{"a":1,"b":[1,2]}`, "{\n  \"b\": [1, 2],\n  \"a\": 1\n}", nil, nil},
+		{"changed and added", `{"a":1,"b":{"c":true}}`, `{"a":2,"b":{"c":true,"d":null}}`, nil, []string{"a: 1 → 2", "b.d: (missing) → null"}},
+		{"ignored subtree", `{"meta":{"id":"x","at":1},"v":1}`, `{"meta":{"id":"y","at":2},"v":1}`, []string{"meta"}, nil},
+		{"escaped key", `{"a.b":1}`, `{"a.b":2}`, nil, []string{`a\.b: 1 → 2`}},
+		{"empty array", `{"items":[]}`, `{"items":[1]}`, nil, []string{"items: [] → (missing)", "items.0: (missing) → 1"}},
+		{"top-level scalar", `1`, `2`, nil, []string{"(body): 1 → 2"}},
+		{"text", "line 1\nline 2", "line 1\nline two", nil, []string{"body differs (13 vs 15 bytes), first on line 2"}},
+		{"equal text", "same", "same", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffBodies([]byte(tt.baseline), []byte(tt.body), tt.ignore)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("diffBodies = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DiffBodies_CapsDifferences(t *testing.T) {
+	var before, after strings.Builder
+	before.WriteString("[")
+	after.WriteString("[")
+	for i := range 30 {
+		if i > 0 {
+			before.WriteString(",")
+			after.WriteString(",")
+		}
+		before.WriteString("0")
+		after.WriteString("1")
+	}
+	before.WriteString("]")
+	after.WriteString("]")
+	got := diffBodies([]byte(before.String()), []byte(after.String()), nil)
+	if len(got) != maxDiffLines+1 || got[maxDiffLines] != "... and 10 more" {
+		t.Errorf("diffBodies returned %d lines, last %q", len(got), got[len(got)-1])
+	}
+}
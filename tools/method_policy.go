@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// readOnlyMethods are the methods permitted in read-only mode: the safe
+// methods, including WebDAV's PROPFIND and REPORT.
+var readOnlyMethods = map[string]bool{"GET": true, "HEAD": true, "OPTIONS": true, "PROPFIND": true, "REPORT": true}
+
+// MethodPolicy restricts which HTTP methods the agent may send through http_request.
+// The zero value allows every supported method.
+type MethodPolicy struct {
+	ReadOnly bool     // allow only the safe methods: GET, HEAD, OPTIONS, PROPFIND and REPORT
+	Allow    []string // if non-empty, only these methods are allowed
+	Deny     []string // these methods are always rejected
+}
+
+// ParseMethodList parses a comma-separated method list such as "get, post"
+// into uppercase method names, rejecting methods http_request does not support.
+func ParseMethodList(value string) ([]string, error) {
+	var methods []string
+	for _, part := range strings.Split(value, ",") {
+		method := strings.ToUpper(strings.TrimSpace(part))
+		if method == "" {
+			continue
+		}
+		if !validMethods[method] {
+			return nil, fmt.Errorf("unsupported method %q", strings.TrimSpace(part))
+		}
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// Validate reports a policy that leaves no method usable.
+func (p MethodPolicy) Validate() error {
+	if len(p.allowedMethods()) == 0 {
+		return fmt.Errorf("method policy allows no methods (read-only=%t, allow=%s, deny=%s)", p.ReadOnly, strings.Join(p.Allow, ","), strings.Join(p.Deny, ","))
+	}
+	return nil
+}
+
+// check returns an explanation if the policy rejects method, or "" if it is allowed.
+func (p MethodPolicy) check(method string) string {
+	if p.ReadOnly && !readOnlyMethods[method] {
+		return fmt.Sprintf("method %s is not allowed: the server runs in read-only mode (--read-only), which permits only GET, HEAD, OPTIONS, PROPFIND, REPORT", method)
+	}
+	for _, denied := range p.Deny {
+		if denied == method {
+			return fmt.Sprintf("method %s is not allowed: the server operator denied it (--deny-methods %s)", method, strings.Join(p.Deny, ","))
+		}
+	}
+	if len(p.Allow) > 0 && !containsString(p.Allow, method) {
+		return fmt.Sprintf("method %s is not allowed: the server operator permits only %s (--allow-methods)", method, strings.Join(p.Allow, ", "))
+	}
+	return ""
+}
+
+// allowedMethods lists the supported methods the policy permits, sorted.
+func (p MethodPolicy) allowedMethods() []string {
+	var allowed []string
+	for method := range validMethods {
+		if p.check(method) == "" {
+			allowed = append(allowed, method)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// isRestricted reports whether the policy rejects any supported method.
+func (p MethodPolicy) isRestricted() bool {
+	return len(p.allowedMethods()) < len(validMethods)
+}
+
+// isReadOnly reports whether the policy permits only read-only methods.
+func (p MethodPolicy) isReadOnly() bool {
+	for _, method := range p.allowedMethods() {
+		if !readOnlyMethods[method] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
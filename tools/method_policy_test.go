@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_ParseMethodList(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"normalizes case and spaces", "get, Post ,", []string{"GET", "POST"}, false},
+		{"unsupported method", "GET,TRACE", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMethodList(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMethodList(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMethodList(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MethodPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      MethodPolicy
+		method      string
+		wantMessage string
+	}{
+		{"zero value allows all", MethodPolicy{}, "DELETE", ""},
+		{"read-only allows GET", MethodPolicy{ReadOnly: true}, "GET", ""},
+		{"read-only rejects POST", MethodPolicy{ReadOnly: true}, "POST", "read-only mode"},
+		{"deny list", MethodPolicy{Deny: []string{"DELETE"}}, "DELETE", "--deny-methods DELETE"},
+		{"allow list", MethodPolicy{Allow: []string{"GET", "POST"}}, "PUT", "permits only GET, POST"},
+		{"deny wins over allow", MethodPolicy{Allow: []string{"GET", "POST"}, Deny: []string{"POST"}}, "POST", "denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.check(tt.method)
+			if tt.wantMessage == "" && got != "" {
+				t.Errorf("expected %s to be allowed, got: %s", tt.method, got)
+			}
+			if tt.wantMessage != "" && !strings.Contains(got, tt.wantMessage) {
+				t.Errorf("expected message containing %q, got: %q", tt.wantMessage, got)
+			}
+		})
+	}
+}
+
+func Test_MethodPolicy_Validate_NoMethodsLeft(t *testing.T) {
+	policy := MethodPolicy{ReadOnly: true, Allow: []string{"POST"}}
+	if err := policy.Validate(); err == nil {
+		t.Error("expected error for a policy that allows no methods")
+	}
+	if err := (MethodPolicy{ReadOnly: true}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func Test_MethodPolicy_IsReadOnly(t *testing.T) {
+	if (MethodPolicy{}).isReadOnly() {
+		t.Error("zero policy must not be read-only")
+	}
+	if !(MethodPolicy{Allow: []string{"GET", "HEAD"}}).isReadOnly() {
+		t.Error("GET/HEAD allow list should count as read-only")
+	}
+}
+
+func Test_HttpRequestHandler_MethodPolicyRejectsWithoutSending(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	handler := makeHandler(newTestClient(server.URL), Settings{Methods: MethodPolicy{ReadOnly: true}}, NewHistory(10), NewStats(), NewVariables())
+	result, _, err := handler(context.Background(), nil, HttpRequestInput{Method: "delete", URL: "/items/1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(extractText(result), "read-only mode") {
+		t.Errorf("expected read-only policy error, got: %s", extractText(result))
+	}
+	if requestCount != 0 {
+		t.Errorf("expected no request to reach the server, got %d", requestCount)
+	}
+}
+
+func Test_BuildToolDescription_WithMethodPolicy(t *testing.T) {
+	desc := buildToolDescription(client.Config{}, Settings{Methods: MethodPolicy{ReadOnly: true}})
+	if !strings.Contains(desc, "Allowed methods: GET, HEAD, OPTIONS") {
+		t.Errorf("expected allowed methods in description, got: %s", desc)
+	}
+	if strings.Contains(buildToolDescription(client.Config{}, Settings{}), "Allowed methods") {
+		t.Error("expected no method policy section without restrictions")
+	}
+}
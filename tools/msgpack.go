@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// msgpackTimestamp is the extension type of the MessagePack timestamp.
+const msgpackTimestamp = -1
+
+// msgpackValue decodes one MessagePack value into JSON-ready Go values.
+func (r *binaryReader) msgpackValue(depth int) (any, error) {
+	if depth > maxBinaryDepth {
+		return nil, fmt.Errorf("nesting deeper than %d levels", maxBinaryDepth)
+	}
+	b, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b >= 0x80 && b <= 0x8f:
+		return r.msgpackMap(uint64(b&0x0f), depth)
+	case b >= 0x90 && b <= 0x9f:
+		return r.msgpackArray(uint64(b&0x0f), depth)
+	case b >= 0xa0 && b <= 0xbf:
+		return r.msgpackString(uint64(b & 0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		size, err := r.uint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return r.next(size)
+	case 0xc7, 0xc8, 0xc9: // ext 8/16/32
+		size, err := r.uint(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackExt(size)
+	case 0xca:
+		value, err := r.float(4)
+		return jsonFloat(value), err
+	case 0xcb:
+		value, err := r.float(8)
+		return jsonFloat(value), err
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8/16/32/64
+		return r.uint(1 << (b - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8/16/32/64
+		size := 1 << (b - 0xd0)
+		value, err := r.uint(size)
+		shift := 64 - 8*size
+		return int64(value<<shift) >> shift, err // sign-extend
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8: // fixext 1/2/4/8/16
+		return r.msgpackExt(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb: // str 8/16/32
+		size, err := r.uint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackString(size)
+	case 0xdc, 0xdd: // array 16/32
+		count, err := r.uint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackArray(count, depth)
+	case 0xde, 0xdf: // map 16/32
+		count, err := r.uint(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackMap(count, depth)
+	}
+	return nil, fmt.Errorf("invalid MessagePack type byte 0x%02x at byte %d", b, r.pos-1)
+}
+
+func (r *binaryReader) msgpackString(size uint64) (any, error) {
+	data, err := r.next(size)
+	return string(data), err
+}
+
+func (r *binaryReader) msgpackArray(count uint64, depth int) (any, error) {
+	array := make([]any, 0, r.capacity(count))
+	for range count {
+		item, err := r.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+	return array, nil
+}
+
+func (r *binaryReader) msgpackMap(count uint64, depth int) (any, error) {
+	object := make(map[string]any, r.capacity(count))
+	for range count {
+		key, err := r.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.msgpackValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		object[jsonKey(key)] = value
+	}
+	return object, nil
+}
+
+// msgpackExt decodes timestamps; other extension types are shown as their
+// type and data.
+func (r *binaryReader) msgpackExt(size uint64) (any, error) {
+	extType, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.next(size)
+	if err != nil {
+		return nil, err
+	}
+	if int8(extType) == msgpackTimestamp {
+		var seconds, nanos int64
+		switch len(data) {
+		case 4:
+			seconds = int64(binary.BigEndian.Uint32(data))
+		case 8:
+			packed := binary.BigEndian.Uint64(data)
+			seconds, nanos = int64(packed&(1<<34-1)), int64(packed>>34)
+		case 12:
+			seconds, nanos = int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data))
+		default:
+			return nil, fmt.Errorf("invalid MessagePack timestamp of %d bytes", len(data))
+		}
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+	}
+	return map[string]any{"extType": int8(extType), "data": data}, nil
+}
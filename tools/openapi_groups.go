@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+// maxGroupSummary bounds an operation's summary in a group tool description.
+const maxGroupSummary = 80
+
+// OperationFilter selects the OpenAPI operations that become tools, and caps
+// their number: above MaxTools, operations are grouped into one tool per tag.
+type OperationFilter struct {
+	IncludeTags []string // only operations with one of these tags; empty means all
+	ExcludeOps  []string // operationId or "METHOD /path" patterns, "*" within a segment, "**" across
+	MaxTools    int      // 0 means no cap
+}
+
+func (f OperationFilter) selectOperations(operations []openapi.Operation) []openapi.Operation {
+	var selected []openapi.Operation
+	for _, operation := range operations {
+		if len(f.IncludeTags) > 0 && !slices.ContainsFunc(operation.Tags, func(tag string) bool {
+			return slices.ContainsFunc(f.IncludeTags, func(include string) bool { return strings.EqualFold(include, tag) })
+		}) {
+			continue
+		}
+		if slices.ContainsFunc(f.ExcludeOps, func(pattern string) bool { return excludesOperation(pattern, operation) }) {
+			continue
+		}
+		selected = append(selected, operation)
+	}
+	return selected
+}
+
+// excludesOperation matches pattern against the operationId, or against
+// "METHOD /path" when it starts with a method.
+func excludesOperation(pattern string, operation openapi.Operation) bool {
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		return (method == "*" || strings.EqualFold(method, operation.Method)) && matchURLPattern(path, operation.Path)
+	}
+	return operation.ID != "" && matchGlob(pattern, operation.ID)
+}
+
+// operationGroup is one tool standing for several operations.
+type operationGroup struct {
+	name       string
+	label      string   // the tag, or the first path segment of untagged operations
+	operations []string // tool names, in order
+	bindings   map[string]operationBinding
+}
+
+// groupOperations groups operations by their first tag, or their first path
+// segment when untagged, into at most maxTools groups; the smallest groups
+// are merged into a last "more" group when there are too many.
+func groupOperations(spec *openapi.Spec, operations []openapi.Operation, baseURL string, maxTools int) []operationGroup {
+	var groups []*operationGroup
+	byLabel := make(map[string]*operationGroup)
+	for _, operation := range operations {
+		label := groupLabel(operation)
+		group, ok := byLabel[label]
+		if !ok {
+			group = &operationGroup{label: label, bindings: make(map[string]operationBinding)}
+			byLabel[label] = group
+			groups = append(groups, group)
+		}
+		group.add(newOperationBinding(spec, operation, baseURL))
+	}
+	if len(groups) > maxTools {
+		slices.SortStableFunc(groups, func(a, b *operationGroup) int { return len(b.operations) - len(a.operations) })
+		more := &operationGroup{label: "more", bindings: make(map[string]operationBinding)}
+		for _, group := range groups[maxTools-1:] {
+			for _, name := range group.operations {
+				more.add(group.bindings[name])
+			}
+		}
+		groups = append(groups[:maxTools-1], more)
+	}
+	result := make([]operationGroup, 0, len(groups))
+	for _, group := range groups {
+		group.name = toolNameFragment(group.label) + "_operations"
+		result = append(result, *group)
+	}
+	return result
+}
+
+func groupLabel(operation openapi.Operation) string {
+	if len(operation.Tags) > 0 && operation.Tags[0] != "" {
+		return operation.Tags[0]
+	}
+	for _, segment := range strings.Split(operation.Path, "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+	return "default"
+}
+
+func (g *operationGroup) add(binding operationBinding) {
+	name := operationToolName(binding.operation)
+	if _, taken := g.bindings[name]; !taken {
+		g.bindings[name] = binding
+		g.operations = append(g.operations, name)
+	}
+}
+
+// toolNameFragment reduces text to the characters MCP tool names allow.
+func toolNameFragment(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, text)
+	return text[:min(len(text), maxToolNameLength-len("_operations"))]
+}
+
+func (g operationGroup) tool() *mcp.Tool {
+	readOnly := true
+	for _, binding := range g.bindings {
+		readOnly = readOnly && (binding.operation.Method == "GET" || binding.operation.Method == "HEAD")
+	}
+	openWorld := true
+	return &mcp.Tool{
+		Name:        g.name,
+		Description: g.description(),
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"operation":  map[string]any{"type": "string", "enum": g.operations, "description": "Operation to call, from the list in the description"},
+				"arguments":  map[string]any{"type": "object", "description": "The operation's parameters by name, and body for its request body"},
+				"jsonFilter": map[string]any{"type": "string", "description": "GJSON path to extract from the JSON response, e.g. data.#.id"},
+			},
+			"required": []string{"operation"},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: readOnly, OpenWorldHint: &openWorld},
+	}
+}
+
+// description lists the group's operations with their parameters; required
+// ones are marked with *.
+func (g operationGroup) description() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Calls the %s operations of the OpenAPI description; the response is formatted like http_request's. Operations:", g.label)
+	for _, name := range g.operations {
+		binding := g.bindings[name]
+		fmt.Fprintf(&builder, "\n- %s: %s %s", name, binding.operation.Method, binding.operation.Path)
+		if summary := strings.TrimRight(binding.operation.Summary, "."); summary != "" {
+			if len(summary) > maxGroupSummary {
+				summary = summary[:maxGroupSummary] + "…"
+			}
+			builder.WriteString(" — " + summary)
+		}
+		var arguments []string
+		for _, parameter := range binding.order {
+			if binding.parameters[parameter].Required {
+				parameter += "*"
+			}
+			arguments = append(arguments, parameter)
+		}
+		if binding.bodyType != "" && binding.bodyNeeded {
+			arguments = append(arguments, "body*")
+		} else if binding.bodyType != "" {
+			arguments = append(arguments, "body")
+		}
+		if len(arguments) > 0 {
+			builder.WriteString(" (" + strings.Join(arguments, ", ") + ")")
+		}
+	}
+	return builder.String()
+}
+
+func makeGroupHandler(group operationGroup, request requestHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var input struct {
+			Operation  string                     `json:"operation"`
+			Arguments  map[string]json.RawMessage `json:"arguments"`
+			JSONFilter json.RawMessage            `json:"jsonFilter"`
+		}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &input); err != nil {
+				return errorResult(fmt.Sprintf("[%s] arguments must be a JSON object: %s", errInvalidRequest, err)), nil
+			}
+		}
+		binding, ok := group.bindings[input.Operation]
+		if !ok {
+			return errorResult(fmt.Sprintf("[%s] unknown operation %q; one of %s", errInvalidRequest, input.Operation, strings.Join(group.operations, ", "))), nil
+		}
+		arguments := input.Arguments
+		if arguments == nil {
+			arguments = make(map[string]json.RawMessage)
+		}
+		if gjson.ParseBytes(input.JSONFilter).String() != "" {
+			arguments["jsonFilter"] = input.JSONFilter
+		}
+		httpInput, err := binding.request(arguments)
+		if err != nil {
+			return errorResult(fmt.Sprintf("[%s] %s", errInvalidRequest, err)), nil
+		}
+		result, output, err := request(ctx, req, httpInput)
+		if result != nil && output != nil {
+			result.StructuredContent = output
+		}
+		return result, err
+	}
+}
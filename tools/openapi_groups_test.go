@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+const groupedSpec = `{"openapi": "3.0.3", "servers": [{"url": "%s"}], "paths": {
+	"/pets": {"get": {"operationId": "listPets", "tags": ["pets"]}, "post": {"operationId": "createPet", "tags": ["pets"]}},
+	"/pets/{id}": {"get": {"operationId": "getPet", "tags": ["pets"], "summary": "Get a pet.",
+		"parameters": [{"name": "id", "in": "path", "schema": {"type": "integer"}}]}},
+	"/stores": {"get": {"operationId": "listStores", "tags": ["stores"]}},
+	"/admin/users": {"delete": {"operationId": "purgeUsers"}},
+	"/admin/stats": {"get": {}}}}`
+
+func Test_OperationFilter_SelectOperations(t *testing.T) {
+	spec, err := openapi.Parse([]byte(fmt.Sprintf(groupedSpec, "http://localhost")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		filter OperationFilter
+		want   []string
+	}{
+		{"no filter", OperationFilter{}, []string{"listPets", "createPet", "getPet", "listStores", "purgeUsers", "GET /admin/stats"}},
+		{"include tag", OperationFilter{IncludeTags: []string{"Stores"}}, []string{"listStores"}},
+		{"exclude operationId glob", OperationFilter{ExcludeOps: []string{"list*", "purgeUsers"}}, []string{"createPet", "getPet", "GET /admin/stats"}},
+		{"exclude method and path", OperationFilter{ExcludeOps: []string{"* /admin/**", "POST /pets"}}, []string{"listPets", "getPet", "listStores"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, operation := range tt.filter.selectOperations(spec.Operations) {
+				got = append(got, operation.Name())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GroupOperations_MergesSmallestGroups(t *testing.T) {
+	spec, err := openapi.Parse([]byte(fmt.Sprintf(groupedSpec, "http://localhost")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := groupOperations(spec, spec.Operations, "", 2)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if groups[0].name != "pets_operations" || !slices.Equal(groups[0].operations, []string{"listPets", "createPet", "getPet"}) {
+		t.Errorf("first group = %s %q", groups[0].name, groups[0].operations)
+	}
+	if groups[1].name != "more_operations" || len(groups[1].operations) != 3 {
+		t.Errorf("second group = %s %q", groups[1].name, groups[1].operations)
+	}
+	if description := groups[0].description(); !strings.Contains(description, "- getPet: GET /pets/{id} — Get a pet (id*)") {
+		t.Errorf("description = %s", description)
+	}
+}
+
+func Test_RegisterOpenAPITools_GroupsAboveMaxTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"request": "%s %s"}`, r.Method, r.URL.RequestURI())
+	}))
+	defer server.Close()
+	source := openAPISource(t, fmt.Sprintf(groupedSpec, server.URL))
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	settings := Settings{OpenAPI: source, OpenAPITools: true, OpenAPIFilter: OperationFilter{ExcludeOps: []string{"purgeUsers"}, MaxTools: 3}}
+	Register(mcpServer, newTestClient(""), client.Config{}, settings, nil)
+	session := connectTestSession(t, mcpServer)
+	ctx := context.Background()
+
+	toolList, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range toolList.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, want := range []string{"pets_operations", "stores_operations", "admin_operations"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("tools %q lack %s", names, want)
+		}
+	}
+	if slices.Contains(names, "getPet") {
+		t.Errorf("tools %q include an ungrouped operation", names)
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "pets_operations", Arguments: map[string]any{
+		"operation": "getPet", "arguments": map[string]any{"id": 7}, "jsonFilter": "request",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := extractText(result); !strings.Contains(text, "GET /pets/7") {
+		t.Errorf("pets_operations getPet = %s", text)
+	}
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "pets_operations", Arguments: map[string]any{"operation": "listStores"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := extractText(result); !result.IsError || !strings.Contains(text, `unknown operation "listStores"`) {
+		t.Errorf("pets_operations listStores = %s", text)
+	}
+}
@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+// openAPINote summarizes a response from its declared schema when the request
+// matches an operation of the --openapi description, so the structure shows
+// even when size limits cut the body. Empty for undescribed requests.
+func openAPINote(spec *openapi.Spec, method string, resp *client.Response) string {
+	operation, ok := spec.Find(method, resp.RequestURL)
+	if !ok {
+		return ""
+	}
+	body := resp.Body
+	if resp.Truncated {
+		body = nil // a cut body would not count right
+	}
+	return fmt.Sprintf("[OpenAPI %s: %s]\n", operation.Name(), spec.Summarize(operation, resp.StatusCode, resp.StatusText, body))
+}
+
+// describeOpenAPI notes the loaded description in the http_request description.
+func describeOpenAPI(spec *openapi.Spec) string {
+	title := spec.Title
+	if title == "" {
+		title = "API"
+	}
+	return fmt.Sprintf(" Responses of the %d operations in the %s OpenAPI description start with a one-line summary of their declared schema.", len(spec.Operations), title)
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+const noteSpec = `{"openapi": "3.0.3", "info": {"title": "Pets"}, "paths": {"/pets": {"get": {"operationId": "listPets", "responses": {
+	"200": {"content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}}}}},
+	"components": {"schemas": {"Pet": {"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}}}}`
+
+// openAPISource opens an OpenAPI description written to a temporary file.
+func openAPISource(t *testing.T, document string) *openapi.Source {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(document), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := openapi.Open(context.Background(), path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source
+}
+
+func Test_HttpRequestHandler_OpenAPISummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"name":"Rex"},{"id":2,"name":"Tom"}]`))
+	}))
+	defer server.Close()
+
+	handler := makeHandler(newTestClient(server.URL), Settings{OpenAPI: openAPISource(t, noteSpec)}, NewHistory(10), NewStats(), NewVariables())
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/api/pets", "[OpenAPI listPets: 200 OK; 2 items of Pet; fields: id, name]\n200 OK"},
+		{"/api/owners", "200 OK"},
+	}
+	for _, tt := range tests {
+		result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL + tt.url})
+		if text := extractText(result); !strings.HasPrefix(text, tt.want) {
+			t.Errorf("GET %s = %s, want prefix %q", tt.url, text, tt.want)
+		}
+	}
+}
+
+func Test_OpenAPINote_TruncatedBody(t *testing.T) {
+	spec, err := openapi.Parse([]byte(noteSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &client.Response{RequestURL: "http://example.com/pets", StatusCode: 200, StatusText: "OK", Body: []byte(`[{"id":1},{"id":2}]`), Truncated: true}
+	if note := openAPINote(spec, "GET", resp); note != "[OpenAPI listPets: 200 OK; array of Pet; fields: id, name]\n" {
+		t.Errorf("note = %q", note)
+	}
+}
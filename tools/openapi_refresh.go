@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+type OpenAPIRefreshInput struct{}
+
+const openAPIRefreshDescription = "Reload the OpenAPI description (a remote one is revalidated with its ETag, so an unchanged one costs a 304) " +
+	"and update the tools generated from it. Use after the API was redeployed or its description changed."
+
+func makeOpenAPIRefreshHandler(source *openapi.Source, reregister func()) func(context.Context, *mcp.CallToolRequest, OpenAPIRefreshInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input OpenAPIRefreshInput) (*mcp.CallToolResult, any, error) {
+		before := len(source.Spec().Operations)
+		changed, err := source.Refresh(ctx)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Refresh failed, still using the loaded description: %s", err)), nil, nil
+		}
+		after := len(source.Spec().Operations)
+		if !changed {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s is unchanged (%d operations).", source.Location, after)}},
+			}, nil, nil
+		}
+		reregister()
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s changed: %d operations (was %d); the tools were updated.", source.Location, after, before)}},
+		}, nil, nil
+	}
+}
+
+// revalidateInBackground refreshes a description served from the disk cache,
+// so startup does not wait for the download, and re-registers the tools if it
+// changed.
+func revalidateInBackground(source *openapi.Source, reregister func()) {
+	go func() {
+		changed, err := source.Refresh(context.Background())
+		if err != nil {
+			log.Printf("revalidating OpenAPI description, using the cached copy: %s", err)
+			return
+		}
+		if changed {
+			log.Printf("OpenAPI description %s changed, updating the tools", source.Location)
+			reregister()
+		}
+	}()
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+func Test_OpenAPIRefreshHandler_KeepsDescriptionOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(noteSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := openapi.Open(context.Background(), path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("not: [an openapi"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var reregistered atomic.Bool
+	handler := makeOpenAPIRefreshHandler(source, func() { reregistered.Store(true) })
+	result, _, _ := handler(context.Background(), nil, OpenAPIRefreshInput{})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "still using the loaded description") {
+		t.Errorf("refresh = %s", text)
+	}
+	if reregistered.Load() || len(source.Spec().Operations) != 1 {
+		t.Error("a failed refresh replaced the description")
+	}
+}
+
+func Test_RevalidateInBackground(t *testing.T) {
+	var document atomic.Value
+	document.Store(noteSpec)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(document.Load().(string)))
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+	if _, err := openapi.Open(context.Background(), server.URL, cacheDir); err != nil {
+		t.Fatal(err)
+	}
+	document.Store(strings.Replace(noteSpec, "listPets", "listAllPets", 1))
+
+	cached, err := openapi.Open(context.Background(), server.URL, cacheDir)
+	if err != nil || !cached.Stale() || cached.Spec().Operations[0].ID != "listPets" {
+		t.Fatalf("cached Open = %v", err)
+	}
+	reregistered := make(chan struct{})
+	revalidateInBackground(cached, func() { close(reregistered) })
+	select {
+	case <-reregistered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the tools were not re-registered")
+	}
+	if cached.Stale() || cached.Spec().Operations[0].ID != "listAllPets" {
+		t.Errorf("revalidated description = %q", cached.Spec().Operations[0].ID)
+	}
+}
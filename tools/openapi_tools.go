@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+const (
+	// schemaInlineDepth bounds how deep $refs are expanded in generated input
+	// schemas; deeper values accept anything.
+	schemaInlineDepth = 4
+	maxToolNameLength = 64
+)
+
+// builtinToolNames are never taken by generated operation tools.
+var builtinToolNames = []string{
+	"http_request", "poll", "subscribe", "upload", "tls_inspect", "url_tool", "cors_check", "api_discover", "export_session", "stats",
+	"generate_payload", "set_variables", "presign", "contract_check", "openapi_refresh", "simulate_auth_expiry",
+	"use_profile", "http_compare_envs",
+}
+
+type requestHandler func(context.Context, *mcp.CallToolRequest, HttpRequestInput) (*mcp.CallToolResult, any, error)
+
+// generatedTools tracks the tools generated from an OpenAPI or AsyncAPI
+// description, so registering again after a refresh or profile switch removes
+// the ones that are gone. The MCP server notifies clients with
+// tools/list_changed.
+type generatedTools struct {
+	mu       sync.Mutex
+	names    []string
+	channels []string // tools generated from an AsyncAPI description
+}
+
+// sync registers the tools for the operations of spec that filter selects,
+// or none when spec is nil, and removes previously generated tools that no
+// longer exist. Tools whose name is taken (a built-in tool, an API tool, an
+// earlier operation) are skipped.
+func (g *generatedTools) sync(mcpServer *mcp.Server, spec *openapi.Spec, filter OperationFilter, baseURL string, taken []string, request requestHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var names []string
+	add := func(tool *mcp.Tool, handler mcp.ToolHandler) {
+		if slices.Contains(builtinToolNames, tool.Name) || slices.Contains(taken, tool.Name) || slices.Contains(names, tool.Name) {
+			return
+		}
+		mcpServer.AddTool(tool, handler)
+		names = append(names, tool.Name)
+	}
+	if spec != nil {
+		operations := filter.selectOperations(spec.Operations)
+		if filter.MaxTools > 0 && len(operations) > filter.MaxTools {
+			for _, group := range groupOperations(spec, operations, baseURL, filter.MaxTools) {
+				add(group.tool(), makeGroupHandler(group, request))
+			}
+		} else {
+			for _, operation := range operations {
+				binding := newOperationBinding(spec, operation, baseURL)
+				add(binding.tool(), makeOperationHandler(binding, request))
+			}
+		}
+	}
+	var gone []string
+	for _, name := range g.names {
+		if !slices.Contains(names, name) {
+			gone = append(gone, name)
+		}
+	}
+	if len(gone) > 0 {
+		mcpServer.RemoveTools(gone...)
+	}
+	g.names = names
+}
+
+// operationToolName is the operationId, or method and path, reduced to the
+// characters MCP tool names allow.
+func operationToolName(operation openapi.Operation) string {
+	name := operation.ID
+	if name == "" {
+		name = strings.ToLower(operation.Method) + "_" + strings.NewReplacer("{", "", "}", "").Replace(strings.Trim(operation.Path, "/"))
+	}
+	return sanitizeToolName(name)
+}
+
+// sanitizeToolName replaces the characters MCP tool names do not allow and
+// cuts the name to their maximum length.
+func sanitizeToolName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+func operationToolDescription(operation openapi.Operation) string {
+	description := operation.Method + " " + operation.Path
+	if operation.Summary != "" {
+		description += ": " + strings.TrimRight(operation.Summary, ".") + "."
+	}
+	return description + " Generated from the OpenAPI description; the response is formatted like http_request's."
+}
+
+// operationBinding maps the input properties of a generated tool to the
+// parameters and body of its operation.
+type operationBinding struct {
+	operation  openapi.Operation
+	baseURL    string                       // --base-url, or the description's server URL
+	parameters map[string]openapi.Parameter // by input property
+	order      []string
+	body       openapi.Schema
+	bodyType   string // media type; empty when the operation takes no JSON body
+	bodyNeeded bool
+}
+
+func newOperationBinding(spec *openapi.Spec, operation openapi.Operation, baseURL string) operationBinding {
+	if baseURL == "" {
+		baseURL = spec.ServerURL()
+	}
+	binding := operationBinding{operation: operation, baseURL: strings.TrimRight(baseURL, "/"), parameters: make(map[string]openapi.Parameter)}
+	for _, parameter := range spec.Parameters(operation) {
+		if _, ok := binding.parameters[parameter.Name]; !ok {
+			binding.parameters[parameter.Name] = parameter
+			binding.order = append(binding.order, parameter.Name)
+		}
+	}
+	if schema, mediaType, required, ok := spec.RequestBody(operation); ok {
+		binding.body, binding.bodyType, binding.bodyNeeded = schema, mediaType, required
+	}
+	return binding
+}
+
+func (b operationBinding) tool() *mcp.Tool {
+	readOnly := b.operation.Method == "GET" || b.operation.Method == "HEAD"
+	openWorld := true
+	return &mcp.Tool{
+		Name:        operationToolName(b.operation),
+		Description: operationToolDescription(b.operation),
+		InputSchema: b.inputSchema(),
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: readOnly, OpenWorldHint: &openWorld},
+	}
+}
+
+func (b operationBinding) inputSchema() map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for _, name := range b.order {
+		parameter := b.parameters[name]
+		property := parameter.Schema.Inline(schemaInlineDepth)
+		if _, ok := property["description"]; !ok && parameter.Description != "" {
+			property["description"] = parameter.Description
+		}
+		properties[name] = property
+		if parameter.Required {
+			required = append(required, name)
+		}
+	}
+	if _, taken := properties["body"]; !taken && b.bodyType != "" {
+		property := b.body.Inline(schemaInlineDepth)
+		property["description"] = "Request body, sent as " + b.bodyType
+		properties["body"] = property
+		if b.bodyNeeded {
+			required = append(required, "body")
+		}
+	}
+	if _, taken := properties["jsonFilter"]; !taken {
+		properties["jsonFilter"] = map[string]any{"type": "string", "description": "GJSON path to extract from the JSON response, e.g. data.#.id"}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// request builds the http_request input for the tool's arguments.
+func (b operationBinding) request(arguments map[string]json.RawMessage) (HttpRequestInput, error) {
+	if b.baseURL == "" {
+		return HttpRequestInput{}, fmt.Errorf("no base URL: set --base-url or declare servers in the OpenAPI description")
+	}
+	input := HttpRequestInput{Method: b.operation.Method, Headers: make(map[string]StringValues), QueryParams: make(map[string]StringValues)}
+	path := b.operation.Path
+	for _, name := range b.order {
+		parameter := b.parameters[name]
+		value, ok := arguments[name]
+		if !ok {
+			if parameter.Required {
+				return HttpRequestInput{}, fmt.Errorf("%s parameter %s is required", parameter.In, name)
+			}
+			continue
+		}
+		values := argumentValues(value)
+		switch parameter.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			input.QueryParams[name] = values
+		case "header":
+			input.Headers[name] = StringValues{strings.Join(values, ",")}
+		}
+	}
+	input.URL = b.baseURL + path
+	if body, ok := arguments["body"]; ok && b.bodyType != "" && b.parameters["body"].Name == "" {
+		input.Body = string(body)
+		if text := gjson.ParseBytes(body); text.Type == gjson.String && gjson.Valid(text.String()) {
+			input.Body = text.String() // JSON passed as a string
+		}
+		input.Headers["Content-Type"] = StringValues{b.bodyType}
+	} else if b.bodyNeeded && b.bodyType != "" {
+		return HttpRequestInput{}, fmt.Errorf("body is required")
+	}
+	if filter, ok := arguments["jsonFilter"]; ok && b.parameters["jsonFilter"].Name == "" {
+		input.JSONFilter = gjson.ParseBytes(filter).String()
+	}
+	return input, nil
+}
+
+// argumentValues renders a parameter argument as strings: arrays give one
+// value per element, everything else its JSON text without string quotes.
+func argumentValues(value json.RawMessage) []string {
+	parsed := gjson.ParseBytes(value)
+	if !parsed.IsArray() {
+		return []string{parsed.String()}
+	}
+	var values []string
+	for _, element := range parsed.Array() {
+		values = append(values, element.String())
+	}
+	return values
+}
+
+func makeOperationHandler(binding operationBinding, request requestHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var arguments map[string]json.RawMessage
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &arguments); err != nil {
+				return errorResult(fmt.Sprintf("[%s] arguments must be a JSON object: %s", errInvalidRequest, err)), nil
+			}
+		}
+		input, err := binding.request(arguments)
+		if err != nil {
+			return errorResult(fmt.Sprintf("[%s] %s", errInvalidRequest, err)), nil
+		}
+		result, output, err := request(ctx, req, input)
+		if result != nil && output != nil {
+			result.StructuredContent = output
+		}
+		return result, err
+	}
+}
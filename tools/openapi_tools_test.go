@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+)
+
+const generatedSpec = `{"openapi": "3.0.3", "servers": [{"url": "%s/v1"}], "paths": {
+	"/pets/{id}": {"get": {"operationId": "getPet", "summary": "Get a pet.", "parameters": [
+		{"name": "id", "in": "path", "schema": {"type": "integer"}},
+		{"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}}]}},
+	"/pets": {"post": {"operationId": "createPet", "requestBody": {"required": true, "content": {"application/json": {"schema": {"type": "object"}}}}}},
+	"/stats": {"get": {"operationId": "stats"}}}}`
+
+func Test_RegisterOpenAPITools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s %s %s", r.Method, r.URL.RequestURI(), r.Header.Get("Content-Type"), body)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(generatedSpec, server.URL)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := openapi.Open(context.Background(), path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, newTestClient(""), client.Config{}, Settings{OpenAPI: source, OpenAPITools: true}, nil)
+	session := connectTestSession(t, mcpServer)
+	ctx := context.Background()
+	toolNames := func() []string {
+		t.Helper()
+		toolList, err := session.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, tool := range toolList.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	callText := func(name string, arguments map[string]any) (string, bool) {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: arguments})
+		if err != nil {
+			t.Fatalf("calling %s: %v", name, err)
+		}
+		return extractText(result), result.IsError
+	}
+
+	names := toolNames()
+	for _, want := range []string{"getPet", "createPet", "openapi_refresh", "contract_check"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("tools %q lack %s", names, want)
+		}
+	}
+	if text, _ := callText("getPet", map[string]any{"id": 42, "fields": []string{"name", "tag"}}); !strings.Contains(text, "GET /v1/pets/42?fields=name&fields=tag") {
+		t.Errorf("getPet = %s", text)
+	}
+	if text, _ := callText("createPet", map[string]any{"body": map[string]any{"name": "Rex"}}); !strings.Contains(text, `POST /v1/pets application/json {"name":"Rex"}`) {
+		t.Errorf("createPet = %s", text)
+	}
+	if text, isError := callText("createPet", map[string]any{}); !isError || !strings.Contains(text, "[invalid_request] body is required") {
+		t.Errorf("createPet without body = %s", text)
+	}
+
+	changed := strings.Replace(fmt.Sprintf(generatedSpec, server.URL), `"operationId": "createPet"`, `"operationId": "addPet"`, 1)
+	if err := os.WriteFile(path, []byte(changed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := callText("openapi_refresh", nil); !strings.Contains(text, "changed: 3 operations (was 3)") {
+		t.Errorf("openapi_refresh = %s", text)
+	}
+	names = toolNames()
+	if !slices.Contains(names, "addPet") || slices.Contains(names, "createPet") {
+		t.Errorf("tools after refresh = %q", names)
+	}
+	if text, _ := callText("openapi_refresh", nil); !strings.Contains(text, "is unchanged (3 operations)") {
+		t.Errorf("second openapi_refresh = %s", text)
+	}
+}
+
+func Test_OperationToolName(t *testing.T) {
+	tests := []struct {
+		operation openapi.Operation
+		want      string
+	}{
+		{openapi.Operation{ID: "listPets"}, "listPets"},
+		{openapi.Operation{ID: "pets.list v2"}, "pets_list_v2"},
+		{openapi.Operation{Method: "GET", Path: "/users/{id}/orders"}, "get_users_id_orders"},
+		{openapi.Operation{ID: strings.Repeat("a", 80)}, strings.Repeat("a", 64)},
+	}
+	for _, tt := range tests {
+		if got := operationToolName(tt.operation); got != tt.want {
+			t.Errorf("operationToolName(%+v) = %q, want %q", tt.operation, got, tt.want)
+		}
+	}
+}
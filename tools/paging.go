@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Paging bounds: a paged GET reads its whole body, up to maxPagedBodySize,
+// and the last pagedBodiesKept such bodies are reused for pagedBodyTTL.
+const (
+	maxPagedBodySize = 32 << 20
+	pagedBodyTTL     = 10 * time.Minute
+	pagedBodiesKept  = 4
+	defaultPageLimit = 50
+)
+
+// pageCache keeps the whole bodies of recent paged GETs, so the next page of
+// the same request is cut from memory instead of fetched again.
+type pageCache struct {
+	mu     sync.Mutex
+	bodies []pagedBody // oldest first
+}
+
+type pagedBody struct {
+	key     string
+	resp    client.Response
+	fetched time.Time
+}
+
+func (c *pageCache) get(key string, now time.Time) (client.Response, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, body := range c.bodies {
+		if body.key == key && now.Sub(body.fetched) < pagedBodyTTL {
+			return body.resp, body.fetched, true
+		}
+	}
+	return client.Response{}, time.Time{}, false
+}
+
+func (c *pageCache) put(key string, resp client.Response, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bodies = slices.DeleteFunc(c.bodies, func(body pagedBody) bool { return body.key == key })
+	c.bodies = append(c.bodies, pagedBody{key: key, resp: resp, fetched: now})
+	if len(c.bodies) > pagedBodiesKept {
+		c.bodies = c.bodies[len(c.bodies)-pagedBodiesKept:]
+	}
+}
+
+// responsePager serves one page of a JSON array in a response: offset/limit
+// elements of the top-level array, or of the array at arrayPath.
+type responsePager struct {
+	cache         *pageCache
+	key           string // method, URL, headers and body of the request
+	read, write   bool   // may use and fill the cache: GETs, as the cache mode allows
+	arrayPath     string
+	offset, limit int
+	reused        bool // the body came from the cache
+}
+
+// newResponsePager is nil unless the input asks for a page. headers are the
+// agent's, without a generated request ID, so repeating a call finds its body.
+func newResponsePager(history *History, input HttpRequestInput, method, requestURL string, headers map[string]string, body string) *responsePager {
+	if input.Offset == 0 && input.Limit == 0 {
+		return nil
+	}
+	var key strings.Builder
+	fmt.Fprintf(&key, "%s %s\n", method, requestURL)
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		fmt.Fprintf(&key, "%s: %s\n", name, headers[name])
+	}
+	key.WriteString("\n" + body)
+	get := method == http.MethodGet
+	return &responsePager{
+		cache:     &history.pages,
+		key:       key.String(),
+		read:      get && (input.Cache == "" || input.Cache == client.CacheOnly),
+		write:     get && input.Cache != client.CacheBypass,
+		arrayPath: input.ArrayPath,
+		offset:    input.Offset,
+		limit:     cmp.Or(input.Limit, defaultPageLimit),
+	}
+}
+
+// execute sends the request, reading its whole body, unless a recent one of
+// the same request is kept; without a pager it just sends it.
+func (p *responsePager) execute(ctx context.Context, httpClient *client.Client, params client.RequestParams) (*client.Response, error) {
+	if p == nil {
+		return httpClient.ExecuteRequest(ctx, params)
+	}
+	now := time.Now()
+	if p.read {
+		if resp, fetched, ok := p.cache.get(p.key, now); ok {
+			p.reused = true
+			resp.Cached, resp.CacheAge = true, now.Sub(fetched)
+			return &resp, nil
+		}
+	}
+	params.MaxResponseSize = max(params.MaxResponseSize, maxPagedBodySize)
+	resp, err := httpClient.ExecuteRequest(ctx, params)
+	if err == nil && p.write && resp.StatusCode < 300 && pageable(resp) {
+		p.cache.put(p.key, *resp, now)
+	}
+	return resp, err
+}
+
+// served reports whether the response came from the cache, not the network.
+func (p *responsePager) served() bool {
+	return p != nil && p.reused
+}
+
+func pageable(resp *client.Response) bool {
+	return !resp.Truncated && !resp.Spilled && resp.SavedPath == ""
+}
+
+// slice cuts resp's body down to the page and describes it. With arrayPath
+// the page replaces the array in place, so the rest of the body stays.
+func (p *responsePager) slice(resp *client.Response) string {
+	if p == nil || len(resp.Body) == 0 {
+		return ""
+	}
+	if !pageable(resp) {
+		return fmt.Sprintf("\n[offset/limit: the body is over %d bytes, so it cannot be paged — pass saveTo to fetch it to a file]", maxPagedBodySize)
+	}
+	where := "the top-level array"
+	array := gjson.ParseBytes(resp.Body)
+	if p.arrayPath != "" {
+		where, array = p.arrayPath, gjson.GetBytes(resp.Body, p.arrayPath)
+	}
+	if !array.IsArray() {
+		return fmt.Sprintf("\n[offset/limit: %s is not a JSON array — set arrayPath to the list, e.g. items]", where)
+	}
+
+	elements := array.Array()
+	start := min(p.offset, len(elements))
+	end := min(start+p.limit, len(elements))
+	raw := make([]string, 0, end-start)
+	for _, element := range elements[start:end] {
+		raw = append(raw, element.Raw)
+	}
+	page := "[" + strings.Join(raw, ",") + "]"
+	switch {
+	case p.arrayPath == "":
+		resp.Body = []byte(page)
+	case array.Index > 0:
+		resp.Body = []byte(string(resp.Body[:array.Index]) + page + string(resp.Body[array.Index+len(array.Raw):]))
+	default:
+		resp.Body = []byte(page) // a path gjson cannot locate in the body, e.g. a query
+		where += " (only the page is shown)"
+	}
+
+	switch {
+	case len(elements) == 0:
+		return fmt.Sprintf("\n[page: %s is empty]", where)
+	case start == len(elements):
+		return fmt.Sprintf("\n[page: offset %d is past the end of the %d elements of %s]", p.offset, len(elements), where)
+	case end == len(elements):
+		return fmt.Sprintf("\n[page: elements %d–%d of %d in %s — the last page]", start, end-1, len(elements), where)
+	}
+	return fmt.Sprintf("\n[page: elements %d–%d of %d in %s; next page: offset %d]", start, end-1, len(elements), where, end)
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func pagedServer(t *testing.T, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func Test_HttpRequestHandler_PagesFromKeptBody(t *testing.T) {
+	items := make([]string, 120)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":%d}`, i)
+	}
+	server, calls := pagedServer(t, `{"total":120,"items":[`+strings.Join(items, ",")+`],"next":null}`)
+	handler := makeHandler(newTestClient(""), Settings{}, NewHistory(10), NewStats(), NewVariables())
+
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, ArrayPath: "items", Limit: 2, MaxResponseBytes: 100})
+	text := extractText(result)
+	if !strings.Contains(text, `{"total":120,"items":[{"id":0},{"id":1}],"next":null}`) || !strings.Contains(text, "[page: elements 0–1 of 120 in items; next page: offset 2]") {
+		t.Errorf("unexpected first page: %s", text)
+	}
+
+	result, _, _ = handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, ArrayPath: "items", Offset: 100})
+	text = extractText(result)
+	if !strings.Contains(text, `{"id":100}`) || !strings.Contains(text, "[page: elements 100–119 of 120 in items — the last page]") || !strings.Contains(text, "[cached ") {
+		t.Errorf("unexpected last page: %s", text)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want the second page served from the kept body", calls.Load())
+	}
+
+	handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, ArrayPath: "items", Offset: 2, Cache: client.CacheRefresh})
+	if calls.Load() != 2 {
+		t.Errorf("server called %d times, want cache: refresh to fetch again", calls.Load())
+	}
+}
+
+func Test_HttpRequestHandler_PagesTopLevelArray(t *testing.T) {
+	server, _ := pagedServer(t, `[1,2,3]`)
+	handler := makeHandler(newTestClient(""), Settings{}, NewHistory(10), NewStats(), NewVariables())
+	tests := []struct {
+		name  string
+		input HttpRequestInput
+		want  string
+	}{
+		{"page", HttpRequestInput{Offset: 1, Limit: 1}, "[2]\n[page: elements 1–1 of 3 in the top-level array; next page: offset 2]"},
+		{"past the end", HttpRequestInput{Offset: 5}, "[page: offset 5 is past the end of the 3 elements of the top-level array]"},
+		{"not an array", HttpRequestInput{Limit: 1, ArrayPath: "items"}, "[offset/limit: items is not a JSON array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Method, tt.input.URL = "GET", server.URL
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			if text := extractText(result); !strings.Contains(text, tt.want) {
+				t.Errorf("output lacks %q: %s", tt.want, text)
+			}
+		})
+	}
+}
+
+func Test_validateInput_Paging(t *testing.T) {
+	tests := []struct {
+		name  string
+		input HttpRequestInput
+		want  string
+	}{
+		{"negative", HttpRequestInput{Offset: -1}, "must not be negative"},
+		{"with saveTo", HttpRequestInput{Limit: 5, SaveTo: "/tmp/out"}, "cannot be combined with saveTo"},
+		{"arrayPath alone", HttpRequestInput{ArrayPath: "items"}, "arrayPath needs offset or limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Method, tt.input.URL = "GET", "http://example.com"
+			if _, _, message := validateInput(tt.input); !strings.Contains(message, tt.want) {
+				t.Errorf("validateInput = %q, want %q", message, tt.want)
+			}
+		})
+	}
+}
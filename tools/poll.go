@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Poll bounds: sampling faster or longer belongs in a monitoring system.
+const (
+	minPollInterval     = time.Second
+	maxPollDuration     = 15 * time.Minute
+	defaultPollInterval = 5 * time.Second
+	defaultPollDuration = time.Minute
+	maxPollRowsShown    = 30
+)
+
+type PollInput struct {
+	Method      string                  `json:"method,omitempty" jsonschema:"GET (default) or HEAD"`
+	URL         string                  `json:"url" jsonschema:"Full URL or relative path (if base_url configured)"`
+	Headers     map[string]StringValues `json:"headers,omitempty" jsonschema:"Request headers"`
+	QueryParams map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters"`
+	Interval    string                  `json:"interval,omitempty" jsonschema:"Time between samples, e.g. 5s (default 5s, at least 1s)"`
+	Duration    string                  `json:"duration,omitempty" jsonschema:"How long to poll, e.g. 2m (default 1m, at most 15m)"`
+	JSONFilter  string                  `json:"jsonFilter,omitempty" jsonschema:"GJSON path of the value to record per sample, e.g. status or metrics.queueDepth; numbers get min/max/avg"`
+	Until       string                  `json:"until,omitempty" jsonschema:"Stop early once the jsonFilter value equals this, e.g. ready"`
+	UntilStatus int                     `json:"untilStatus,omitempty" jsonschema:"Stop early once the response has this status, e.g. 200"`
+}
+
+const pollDescription = "Send the same GET request every interval for a bounded duration and return a compact time series (status, latency and an optional jsonFilter value per sample) with min/max/avg. " +
+	"Use to watch a deployment, job or metric converge in one call instead of many; until/untilStatus stop as soon as it does."
+
+// PollSample is one request of a poll.
+type PollSample struct {
+	OffsetMs  int64  `json:"offsetMs"` // since the first sample
+	Status    int    `json:"status,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+	Value     string `json:"value,omitempty"` // the jsonFilter value as JSON
+	Error     string `json:"error,omitempty"`
+}
+
+// PollOutput is the structured output of poll.
+type PollOutput struct {
+	Samples []PollSample `json:"samples"`
+	Stopped string       `json:"stopped"` // why polling ended
+}
+
+func makePollHandler(httpClient *client.Client, settings Settings, stats *Stats) func(context.Context, *mcp.CallToolRequest, PollInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input PollInput) (*mcp.CallToolResult, any, error) {
+		method := strings.ToUpper(input.Method)
+		if method == "" {
+			method = "GET"
+		}
+		if method != "GET" && method != "HEAD" {
+			return errorResult("poll sends only GET or HEAD requests"), nil, nil
+		}
+		if input.URL == "" {
+			return errorResult("url is required"), nil, nil
+		}
+		interval, duration, err := pollTiming(input)
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+		if policyError := settings.Methods.check(method); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if limitError := settings.Limits.check(HttpRequestInput{Method: method, Headers: input.Headers}); limitError != "" {
+			return errorResult(limitError), nil, nil
+		}
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		params := client.RequestParams{
+			Method:          method,
+			URL:             input.URL,
+			Headers:         headers,
+			QueryParams:     queryParams(HttpRequestInput{QueryParams: input.QueryParams}),
+			FollowRedirects: settings.FollowRedirects,
+			CheckRedirect:   settings.URLs.redirectCheck(),
+		}
+		requestURL, err := httpClient.ResolveURL(params)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Invalid URL: %s", err)), nil, nil
+		}
+		if policyError := settings.URLs.check(method, requestURL); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if settings.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, params)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Dry run failed: %s", err)), nil, nil
+			}
+			text := headerNote + formatDryRun(rendered, HttpRequestInput{})
+			text += fmt.Sprintf("\n\n[not polled; would sample every %s for %s]", interval, duration)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		}
+
+		progress := newProgressReporter(ctx, req)
+		output := PollOutput{Stopped: "duration elapsed"}
+		started := time.Now()
+		deadline := started.Add(duration)
+		for next := started; ; next = next.Add(interval) {
+			sample, done := pollOnce(ctx, httpClient, params, input, stats, requestURL)
+			sample.OffsetMs = time.Since(started).Milliseconds() - sample.LatencyMs
+			output.Samples = append(output.Samples, sample)
+			if progress != nil {
+				progress(fmt.Sprintf("sample %d: %s", len(output.Samples), sampleOutcome(sample)))
+			}
+			if done != "" {
+				output.Stopped = done
+				break
+			}
+			if next.Add(interval).After(deadline) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				output.Stopped = "cancelled"
+			case <-time.After(time.Until(next.Add(interval))):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatPoll(method, requestURL, interval, output)}},
+		}, output, nil
+	}
+}
+
+func pollTiming(input PollInput) (time.Duration, time.Duration, error) {
+	interval, duration := defaultPollInterval, defaultPollDuration
+	var err error
+	if input.Interval != "" {
+		if interval, err = time.ParseDuration(input.Interval); err != nil {
+			return 0, 0, fmt.Errorf("invalid interval: %w", err)
+		}
+	}
+	if input.Duration != "" {
+		if duration, err = time.ParseDuration(input.Duration); err != nil {
+			return 0, 0, fmt.Errorf("invalid duration: %w", err)
+		}
+	}
+	if interval < minPollInterval || duration <= 0 || duration > maxPollDuration {
+		return 0, 0, fmt.Errorf("interval must be at least %s and duration between 0 and %s", minPollInterval, maxPollDuration)
+	}
+	return interval, duration, nil
+}
+
+// pollOnce takes one sample and reports why polling should stop, if it should.
+func pollOnce(ctx context.Context, httpClient *client.Client, params client.RequestParams, input PollInput, stats *Stats, requestURL string) (PollSample, string) {
+	started := time.Now()
+	resp, err := httpClient.ExecuteRequest(ctx, params)
+	sample := PollSample{LatencyMs: time.Since(started).Milliseconds()}
+	if ctx.Err() != nil {
+		sample.Error = "cancelled"
+		return sample, "cancelled"
+	}
+	stats.Record(requestURL, 0, resp, time.Since(started))
+	if err != nil {
+		sample.Error = classifyError(err)
+		return sample, ""
+	}
+	sample.Status = resp.StatusCode
+	if input.JSONFilter != "" {
+		if value := gjson.GetBytes(resp.Body, input.JSONFilter); value.Exists() {
+			sample.Value = value.Raw
+			if input.Until != "" && value.String() == input.Until {
+				return sample, fmt.Sprintf("%s reached %s", input.JSONFilter, value.Raw)
+			}
+		}
+	}
+	if input.UntilStatus != 0 && resp.StatusCode == input.UntilStatus {
+		return sample, fmt.Sprintf("status reached %d", resp.StatusCode)
+	}
+	return sample, ""
+}
+
+func sampleOutcome(sample PollSample) string {
+	if sample.Error != "" {
+		return "failed: " + sample.Error
+	}
+	outcome := fmt.Sprintf("%d in %dms", sample.Status, sample.LatencyMs)
+	if sample.Value != "" {
+		outcome += ", value " + clip(sample.Value)
+	}
+	return outcome
+}
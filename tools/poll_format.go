@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatPoll renders the samples as a table (the last maxPollRowsShown) with
+// a summary over all of them.
+func formatPoll(method, requestURL string, interval time.Duration, output PollOutput) string {
+	samples := output.Samples
+	var builder strings.Builder
+	last := samples[len(samples)-1]
+	span := time.Duration(last.OffsetMs) * time.Millisecond
+	fmt.Fprintf(&builder, "Polled %s %s %d times over %s, every %s — stopped: %s\n\n", method, requestURL, len(samples), span.Round(time.Second), interval, output.Stopped)
+
+	shown := samples
+	if len(samples) > maxPollRowsShown {
+		shown = samples[len(samples)-maxPollRowsShown:]
+		fmt.Fprintf(&builder, "(first %d samples omitted; the summary covers all)\n", len(samples)-maxPollRowsShown)
+	}
+	builder.WriteString("| # | at | status | latency | value |\n|---|---|---|---|---|\n")
+	first := len(samples) - len(shown)
+	for i, sample := range shown {
+		status := strconv.Itoa(sample.Status)
+		if sample.Error != "" {
+			status = "failed: " + sample.Error
+		}
+		at := (time.Duration(sample.OffsetMs) * time.Millisecond).Round(100 * time.Millisecond)
+		fmt.Fprintf(&builder, "| %d | %s | %s | %dms | %s |\n", first+i+1, at, status, sample.LatencyMs, strings.ReplaceAll(clip(sample.Value), "|", "\\|"))
+	}
+
+	statuses := make(map[string]int)
+	var latencies, numbers []float64
+	values, valued := make(map[string]int), 0
+	for _, sample := range samples {
+		if sample.Error != "" {
+			statuses[sample.Error]++
+			continue
+		}
+		statuses[strconv.Itoa(sample.Status)]++
+		latencies = append(latencies, float64(sample.LatencyMs))
+		if sample.Value == "" {
+			continue
+		}
+		if number, err := strconv.ParseFloat(sample.Value, 64); err == nil {
+			numbers = append(numbers, number)
+		}
+		values[sample.Value]++
+		valued++
+	}
+	builder.WriteString("\nStatus: " + countList(statuses))
+	if len(latencies) > 0 {
+		low, high, avg := summarize(latencies)
+		fmt.Fprintf(&builder, "\nLatency: min %.0fms, max %.0fms, avg %.0fms", low, high, avg)
+	}
+	switch {
+	case len(numbers) > 0 && len(numbers) == valued:
+		low, high, avg := summarize(numbers)
+		fmt.Fprintf(&builder, "\nValue: min %s, max %s, avg %s, last %s", formatNumber(low), formatNumber(high), formatNumber(avg), formatNumber(numbers[len(numbers)-1]))
+	case len(values) > 0:
+		builder.WriteString("\nValues: " + countList(values))
+	}
+	return builder.String()
+}
+
+// countList renders counts as "200 ×11, 503 ×1", most frequent first.
+func countList(counts map[string]int) string {
+	keys := slices.Sorted(maps.Keys(counts))
+	slices.SortStableFunc(keys, func(a, b string) int { return counts[b] - counts[a] })
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s ×%d", clip(key), counts[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func summarize(values []float64) (low, high, avg float64) {
+	low, high = values[0], values[0]
+	sum := 0.0
+	for _, value := range values {
+		low, high = min(low, value), max(high, value)
+		sum += value
+	}
+	return low, high, sum / float64(len(values))
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
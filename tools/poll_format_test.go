@@ -0,0 +1,26 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_FormatPoll(t *testing.T) {
+	var samples []PollSample
+	for i := range 35 {
+		sample := PollSample{OffsetMs: int64(i) * 1000, Status: 200, LatencyMs: 10, Value: `"pending"`}
+		if i == 34 {
+			sample.Value = `"ready"`
+		}
+		if i == 3 {
+			sample = PollSample{OffsetMs: 3000, LatencyMs: 5000, Error: "request_timeout"}
+		}
+		samples = append(samples, sample)
+	}
+	text := formatPoll("GET", "http://example.com/job", 1e9, PollOutput{Samples: samples, Stopped: "duration elapsed"})
+	for _, want := range []string{"35 times over 34s", "(first 5 samples omitted", "| 6 | 5s | 200 |", "Status: 200 ×34, request_timeout ×1", "Latency: min 10ms, max 10ms, avg 10ms", `Values: "pending" ×33, "ready" ×1`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_PollHandler_StopsWhenValueReached(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"depth":%d}`, calls.Add(1))
+	}))
+	defer server.Close()
+
+	handler := makePollHandler(newTestClient(server.URL), Settings{}, NewStats())
+	result, out, _ := handler(context.Background(), nil, PollInput{URL: server.URL, Interval: "1s", Duration: "1m", JSONFilter: "depth", Until: "2"})
+	text := extractText(result)
+	output := out.(PollOutput)
+	if len(output.Samples) != 2 || output.Stopped != "depth reached 2" {
+		t.Fatalf("output = %+v", output)
+	}
+	for _, want := range []string{"Polled GET " + server.URL + " 2 times", "stopped: depth reached 2", "| 2 | 1s | 200 |", "Status: 200 ×2", "Value: min 1, max 2, avg 1.5, last 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func Test_PollHandler_RejectsInvalidInput(t *testing.T) {
+	handler := makePollHandler(newTestClient(""), Settings{Methods: MethodPolicy{Deny: []string{"HEAD"}}}, NewStats())
+	tests := []struct {
+		name  string
+		input PollInput
+		want  string
+	}{
+		{"write method", PollInput{Method: "POST", URL: "http://example.com"}, "only GET or HEAD"},
+		{"missing url", PollInput{}, "url is required"},
+		{"fast interval", PollInput{URL: "http://example.com", Interval: "100ms"}, "interval must be at least 1s"},
+		{"long duration", PollInput{URL: "http://example.com", Duration: "1h"}, "between 0 and 15m0s"},
+		{"method policy", PollInput{Method: "HEAD", URL: "http://example.com"}, "HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			if !result.IsError || !strings.Contains(extractText(result), tt.want) {
+				t.Errorf("result = %s, want an error containing %q", extractText(result), tt.want)
+			}
+		})
+	}
+}
+
+func Test_PollHandler_DryRunAndLimits(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	result, _, _ := makePollHandler(newTestClient(""), Settings{DryRun: true}, NewStats())(context.Background(), nil, PollInput{URL: server.URL, Interval: "1s", Duration: "1m"})
+	if text := extractText(result); result.IsError || !strings.Contains(text, "[dry run — request not sent]") || !strings.Contains(text, "[not polled; would sample every 1s for 1m0s]") {
+		t.Errorf("expected a dry run, got: %s", text)
+	}
+	limited := Settings{Limits: RequestLimits{MaxHeaders: 1}}
+	result, _, _ = makePollHandler(newTestClient(""), limited, NewStats())(context.Background(), nil, PollInput{URL: server.URL, Headers: map[string]StringValues{"A": {"1"}, "B": {"2"}}})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "--max-request-headers") {
+		t.Errorf("expected the header limit enforced, got: %s", text)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no request sent, server saw %d", calls.Load())
+	}
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/s3"
+)
+
+// defaultPresignExpiry is the validity of a presigned URL when the agent sets none.
+const defaultPresignExpiry = 15 * time.Minute
+
+type PresignInput struct {
+	Method  string `json:"method,omitempty" jsonschema:"GET to download or PUT to upload (default GET)"`
+	Bucket  string `json:"bucket" jsonschema:"Bucket name"`
+	Key     string `json:"key" jsonschema:"Object key, e.g. exports/2024/report.csv"`
+	Expires string `json:"expires,omitempty" jsonschema:"How long the URL stays valid, e.g. 15m or 24h (default 15m, at most 168h)"`
+}
+
+const presignDescription = "Generate a presigned URL for an object in the configured S3-compatible storage: anyone holding it can GET (download) or PUT (upload) that object until it expires, without credentials. " +
+	"Use to hand large transfers to another process (curl -T file URL) instead of streaming them through this server. Nothing is sent; the URL is only signed."
+
+func makePresignHandler(cfg s3.Config) func(context.Context, *mcp.CallToolRequest, PresignInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input PresignInput) (*mcp.CallToolResult, any, error) {
+		method := strings.ToUpper(input.Method)
+		if method == "" {
+			method = "GET"
+		}
+		expiry := defaultPresignExpiry
+		if input.Expires != "" {
+			var err error
+			if expiry, err = time.ParseDuration(input.Expires); err != nil {
+				return errorResult(fmt.Sprintf("invalid expires: %s", err)), nil, nil
+			}
+		}
+		now := time.Now()
+		presigned, err := s3.Presign(cfg, method, input.Bucket, input.Key, expiry, now)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Cannot presign: %s", err)), nil, nil
+		}
+		text := fmt.Sprintf("%s %s\n\nValid until %s (%s).", method, presigned, now.Add(expiry).UTC().Format(time.RFC3339), expiry)
+		if method == "PUT" {
+			text += " Upload with: curl -T FILE '" + presigned + "'"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+	}
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/s3"
+)
+
+func Test_PresignHandler(t *testing.T) {
+	handler := makePresignHandler(s3.Config{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	tests := []struct {
+		name    string
+		input   PresignInput
+		want    string
+		wantErr bool
+	}{
+		{"default GET", PresignInput{Bucket: "photos", Key: "a.jpg"}, "GET https://photos.s3.eu-west-1.amazonaws.com/a.jpg?X-Amz-Algorithm=AWS4-HMAC-SHA256", false},
+		{"PUT with expiry", PresignInput{Method: "put", Bucket: "photos", Key: "a.jpg", Expires: "2h"}, "X-Amz-Expires=7200", false},
+		{"PUT shows upload command", PresignInput{Method: "PUT", Bucket: "photos", Key: "a.jpg"}, "curl -T FILE", false},
+		{"invalid expiry", PresignInput{Bucket: "photos", Key: "a.jpg", Expires: "soon"}, "invalid expires", true},
+		{"missing key", PresignInput{Bucket: "photos"}, "bucket and key are required", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			text := extractText(result)
+			if result.IsError != tt.wantErr || !strings.Contains(text, tt.want) {
+				t.Errorf("result (error %v) = %s, want it to contain %s", result.IsError, text, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Profile is one named configuration profile: the client and settings the tools use while it is active.
+type Profile struct {
+	Name     string
+	Client   *client.Client
+	Config   client.Config
+	Settings Settings
+}
+
+type UseProfileInput struct {
+	Name string `json:"name,omitempty" jsonschema:"Profile to switch to; omit to list the profiles and see which one is active"`
+}
+
+const useProfileDescription = "Switch the active configuration profile (e.g. dev, staging, prod). " +
+	"All tools then use that profile's base URL, default headers, auth and limits. Omit name to list profiles."
+
+// profileSwitcher re-registers the tools when the agent switches profile.
+// Request history, stats and variables are kept across switches so export_session and
+// stats see every call.
+type profileSwitcher struct {
+	mcpServer *mcp.Server
+	profiles  []Profile
+	apis      []API
+	history   *History
+	stats     *Stats
+	variables *Variables
+	generated *generatedTools
+	resources *sessionResources
+
+	mu     sync.Mutex
+	active string
+}
+
+// RegisterProfiles registers the tools for the active profile plus use_profile,
+// which switches the generic tools to another profile at runtime. The named API
+// tools do not change with the profile.
+func RegisterProfiles(mcpServer *mcp.Server, profiles []Profile, active string, apis []API) error {
+	switcher := &profileSwitcher{mcpServer: mcpServer, profiles: profiles, apis: apis, history: NewHistory(historyCapacity), stats: NewStats(), variables: NewVariables(), generated: &generatedTools{}, resources: &sessionResources{}}
+	if err := switcher.activate(active); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "use_profile",
+		Description: fmt.Sprintf("%s Profiles: %s.", useProfileDescription, strings.Join(names, ", ")),
+	}, makeUseProfileHandler(switcher))
+	return nil
+}
+
+func (s *profileSwitcher) activate(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, profile := range s.profiles {
+		if profile.Name == name {
+			settings := profile.Settings
+			settings.Profile = profile.Name
+			if err := registerTools(s.mcpServer, profile.Client, profile.Config, settings, s.apis, s.history, s.stats, s.variables, s.generated, s.resources); err != nil {
+				return err
+			}
+			s.active = name
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown profile %q", name)
+}
+
+func (s *profileSwitcher) describe() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lines []string
+	for _, profile := range s.profiles {
+		marker := " "
+		if profile.Name == s.active {
+			marker = "*"
+		}
+		baseURL := profile.Config.BaseURL
+		if baseURL == "" {
+			baseURL = "(no base URL)"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s — %s", marker, profile.Name, baseURL))
+	}
+	return "Profiles (* = active):\n" + strings.Join(lines, "\n")
+}
+
+func makeUseProfileHandler(switcher *profileSwitcher) func(context.Context, *mcp.CallToolRequest, UseProfileInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input UseProfileInput) (*mcp.CallToolResult, any, error) {
+		if input.Name == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: switcher.describe()}},
+			}, nil, nil
+		}
+		if err := switcher.activate(input.Name); err != nil {
+			return errorResult(fmt.Sprintf("%s\n\n%s", err, switcher.describe())), nil, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Switched to profile %s; all tools now use it.\n\n%s", input.Name, switcher.describe())}},
+		}, nil, nil
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func newProfileTestServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+}
+
+func newTestProfile(name, baseURL string) Profile {
+	cfg := client.Config{BaseURL: baseURL, Timeout: 5 * time.Second}
+	return Profile{Name: name, Client: client.NewClient(cfg), Config: cfg, Settings: Settings{FollowRedirects: true}}
+}
+
+func Test_RegisterProfiles_UseProfileSwitchesTools(t *testing.T) {
+	dev := newProfileTestServer("from dev")
+	defer dev.Close()
+	prod := newProfileTestServer("from prod")
+	defer prod.Close()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	profiles := []Profile{newTestProfile("dev", dev.URL), newTestProfile("prod", prod.URL)}
+	if err := RegisterProfiles(mcpServer, profiles, "dev", nil); err != nil {
+		t.Fatalf("RegisterProfiles: %v", err)
+	}
+
+	session := connectTestSession(t, mcpServer)
+	ctx := context.Background()
+
+	callText := func(name string, arguments map[string]any) string {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: arguments})
+		if err != nil {
+			t.Fatalf("calling %s: %v", name, err)
+		}
+		return extractText(result)
+	}
+	get := map[string]any{"method": "GET", "url": "/"}
+
+	if text := callText("http_request", get); !strings.Contains(text, "from dev") {
+		t.Errorf("expected dev response, got: %s", text)
+	}
+	if text := callText("use_profile", map[string]any{"name": "prod"}); !strings.Contains(text, "Switched to profile prod") {
+		t.Errorf("unexpected switch result: %s", text)
+	}
+	if text := callText("http_request", get); !strings.Contains(text, "from prod") {
+		t.Errorf("expected prod response after switch, got: %s", text)
+	}
+	if text := callText("use_profile", map[string]any{"name": "qa"}); !strings.Contains(text, `unknown profile "qa"`) {
+		t.Errorf("expected unknown profile error, got: %s", text)
+	}
+	if text := callText("use_profile", map[string]any{}); !strings.Contains(text, "* prod — "+prod.URL) {
+		t.Errorf("expected prod marked active, got: %s", text)
+	}
+
+	toolList, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("listing tools: %v", err)
+	}
+	for _, tool := range toolList.Tools {
+		if tool.Name == "http_request" && !strings.Contains(tool.Description, "Active profile: prod") {
+			t.Errorf("expected http_request description to name the active profile, got: %s", tool.Description)
+		}
+	}
+}
+
+func Test_RegisterProfiles_UnknownActiveProfile(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	if err := RegisterProfiles(mcpServer, []Profile{newTestProfile("dev", "http://localhost")}, "prod", nil); err == nil {
+		t.Error("expected error for unknown active profile")
+	}
+}
@@ -0,0 +1,34 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// newProgressReporter returns a client.ProgressFunc that forwards messages to
+// the MCP client as progress notifications, or nil when the tool call carried
+// no progress token. Progress is a running notification count because the
+// messages mix units (attempts, bytes); the message carries the detail.
+func newProgressReporter(ctx context.Context, req *mcp.CallToolRequest) client.ProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	notificationCount := 0
+	return func(message string) {
+		notificationCount++
+		// Progress is best-effort liveness: a failed notification must not fail the request.
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(notificationCount),
+			Message:       message,
+		})
+	}
+}
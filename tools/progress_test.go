@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_NewProgressReporter_NoTokenMeansNil(t *testing.T) {
+	if newProgressReporter(context.Background(), nil) != nil {
+		t.Error("expected nil reporter for nil request")
+	}
+	if newProgressReporter(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}}) != nil {
+		t.Error("expected nil reporter without session and progress token")
+	}
+}
+
+func Test_HttpRequestHandler_ReportsRetryProgress(t *testing.T) {
+	var callCount atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if callCount.Add(1) == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer upstream.Close()
+
+	httpClient := client.NewClient(client.Config{
+		Timeout:         5 * time.Second,
+		MaxResponseSize: 1024,
+		RetryCount:      1,
+		RetryDelay:      10 * time.Millisecond,
+	})
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	Register(mcpServer, httpClient, client.Config{}, Settings{FollowRedirects: true}, nil)
+
+	var mu sync.Mutex
+	var messages []string
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, req.Params.Message)
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("connecting server: %v", err)
+	}
+	session, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connecting client: %v", err)
+	}
+	defer session.Close()
+
+	params := &mcp.CallToolParams{
+		Name:      "http_request",
+		Arguments: map[string]any{"method": "GET", "url": upstream.URL},
+	}
+	params.SetProgressToken("progress-1")
+	result, err := session.CallTool(ctx, params)
+	if err != nil {
+		t.Fatalf("calling tool: %v", err)
+	}
+	if !strings.Contains(extractText(result), "200 OK") {
+		t.Errorf("expected success after retry, got: %s", extractText(result))
+	}
+
+	// Notifications are delivered asynchronously; give the handler a moment.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		received := len(messages)
+		mu.Unlock()
+		if received > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(messages) == 0 || !strings.Contains(messages[0], "attempt 1/2 failed (503 Service Unavailable)") {
+		t.Errorf("expected retry progress notification, got: %v", messages)
+	}
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// apiPrompt is a built-in MCP prompt: a multi-step workflow over the tools,
+// rendered with the active configuration.
+type apiPrompt struct {
+	name        string
+	title       string
+	description string
+	arguments   []*mcp.PromptArgument
+	steps       func(arguments map[string]string, available promptTools) []string
+}
+
+// promptTools tells prompts which tools they may suggest.
+type promptTools struct {
+	toolsets Toolsets
+	openAPI  bool
+}
+
+func (t promptTools) has(name string) bool {
+	if name == "contract_check" && !t.openAPI {
+		return false
+	}
+	return t.toolsets.Enables(name)
+}
+
+func apiPrompts() []apiPrompt {
+	return []apiPrompt{
+		{
+			name:        "debug-failing-endpoint",
+			title:       "Debug a failing endpoint",
+			description: "Find out why a request fails: reproduce it, read the error, and narrow down the cause.",
+			arguments: []*mcp.PromptArgument{
+				{Name: "url", Description: "URL or path of the failing endpoint", Required: true},
+				{Name: "method", Description: "HTTP method (default GET)"},
+				{Name: "expected", Description: "What the endpoint should do instead"},
+			},
+			steps: debugSteps,
+		},
+		{
+			name:        "explore-new-api",
+			title:       "Explore a new API",
+			description: "Map an unfamiliar API: discover its description and endpoints, then try the main resources.",
+			arguments: []*mcp.PromptArgument{
+				{Name: "url", Description: "Base URL of the API (default: the configured base URL)"},
+				{Name: "goal", Description: "What you want to do with the API"},
+			},
+			steps: exploreSteps,
+		},
+		{
+			name:        "write-api-test",
+			title:       "Write an API test",
+			description: "Exercise an endpoint and turn the calls into a repeatable test.",
+			arguments: []*mcp.PromptArgument{
+				{Name: "url", Description: "URL or path of the endpoint to test", Required: true},
+				{Name: "method", Description: "HTTP method (default GET)"},
+				{Name: "format", Description: "go (default) or postman"},
+			},
+			steps: testSteps,
+		},
+	}
+}
+
+func debugSteps(arguments map[string]string, available promptTools) []string {
+	request := requestLine(arguments)
+	steps := []string{fmt.Sprintf("Send %s with http_request, includeResponseHeaders and includeTiming set, and failOnHttpError false, so the error body is visible.", request)}
+	if expected := arguments["expected"]; expected != "" {
+		steps = append(steps, fmt.Sprintf("Compare the response with what should happen: %s.", expected))
+	}
+	steps = append(steps,
+		"Read the status and error body: 4xx points at the request (auth, parameters, body), 5xx at the server. A connection or TLS error means the request never got an answer.",
+		"Repeat the call with verbose: true to see the headers as sent on the wire, and echoRequest: true to check the resolved URL, query and body.",
+	)
+	if available.has("tls_inspect") {
+		steps = append(steps, "For certificate or handshake errors, check the server's certificate chain with tls_inspect.")
+	}
+	if available.has("contract_check") {
+		steps = append(steps, "When the response looks wrong rather than failing, check it against the OpenAPI description with contract_check.")
+	}
+	if available.has("stats") {
+		steps = append(steps, "If the failure is intermittent, repeat the call a few times and read stats for the host's error rate, 429s and latency.")
+	}
+	return append(steps, "Change one thing per call and stop when the cause is clear; then summarize the cause, the evidence and the fix.")
+}
+
+func exploreSteps(arguments map[string]string, available promptTools) []string {
+	target := "the configured base URL"
+	if arguments["url"] != "" {
+		target = arguments["url"]
+	}
+	var steps []string
+	if goal := arguments["goal"]; goal != "" {
+		steps = append(steps, fmt.Sprintf("Keep the goal in mind: %s.", goal))
+	}
+	if available.has("api_discover") {
+		steps = append(steps, fmt.Sprintf("Run api_discover on %s to find its OpenAPI description and OAuth/OIDC metadata.", target))
+	} else {
+		steps = append(steps, fmt.Sprintf("GET %s and common description paths such as /openapi.json and /swagger.json with http_request.", target))
+	}
+	steps = append(steps,
+		"List the main resources and how they are authenticated, paginated and versioned before calling more endpoints.",
+		"Try a read-only call per resource with http_request; use jsonFilter (e.g. items.#.id) and maxTokens to keep large responses small.",
+		"Follow links and IDs from one response into the next call instead of guessing URLs.",
+	)
+	if available.has("set_variables") {
+		steps = append(steps, "Store IDs and tokens you need again with set_variables and use them as {{name}} in later requests.")
+	}
+	return append(steps, "Finish with a short map of the API: resources, their endpoints and anything surprising.")
+}
+
+func testSteps(arguments map[string]string, available promptTools) []string {
+	format := arguments["format"]
+	if format == "" {
+		format = "go"
+	}
+	steps := []string{fmt.Sprintf("Send %s with http_request and check that it succeeds.", requestLine(arguments))}
+	if available.has("generate_payload") {
+		steps = append(steps, "For request bodies, generate realistic and varied payloads from the body's JSON Schema with generate_payload.")
+	}
+	steps = append(steps, "Cover the edge cases too: a missing or invalid parameter, an empty or malformed body, and a call without credentials, each in its own http_request.")
+	if available.has("contract_check") {
+		steps = append(steps, "Check each response against the OpenAPI description with contract_check.")
+	}
+	if available.has("export_session") {
+		steps = append(steps,
+			"List the recorded calls with export_session format: list and pick the ones that belong in the test.",
+			fmt.Sprintf("Export them with export_session format: %s and review the generated assertions.", format),
+		)
+	} else {
+		steps = append(steps, fmt.Sprintf("Write the calls up as a %s test, asserting the status and the fields that matter.", format))
+	}
+	return steps
+}
+
+func requestLine(arguments map[string]string) string {
+	method := strings.ToUpper(arguments["method"])
+	if method == "" {
+		method = "GET"
+	}
+	return method + " " + arguments["url"]
+}
+
+// promptContext describes the configuration the steps run against: base URL,
+// credentials, profile and OpenAPI description.
+func promptContext(cfg client.Config, settings Settings) string {
+	var lines []string
+	if cfg.BaseURL != "" {
+		lines = append(lines, fmt.Sprintf("Base URL: %s (relative paths are resolved against it).", cfg.BaseURL))
+	} else {
+		lines = append(lines, "No base URL is configured: use full URLs.")
+	}
+	if credentials := configuredCredentials(cfg); len(credentials) > 0 {
+		lines = append(lines, fmt.Sprintf("Credentials are configured (%s) and added to requests automatically; do not send them yourself.", strings.Join(credentials, ", ")))
+	} else {
+		lines = append(lines, "No credentials are configured: if the API needs them, ask the user instead of guessing.")
+	}
+	if settings.Profile != "" {
+		lines = append(lines, fmt.Sprintf("Active profile: %s.", settings.Profile))
+	}
+	if settings.OpenAPI != nil {
+		lines = append(lines, "An OpenAPI description is loaded: responses to its operations start with a summary of their declared schema.")
+	}
+	if settings.DryRun {
+		lines = append(lines, "The server runs in dry-run mode: requests are shown, not sent.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func configuredCredentials(cfg client.Config) []string {
+	var credentials []string
+	for name := range cfg.DefaultHeaders {
+		if client.IsSensitiveHeader(name) {
+			credentials = append(credentials, name+" header")
+		}
+	}
+	sort.Strings(credentials)
+	if cfg.TokenSource != nil {
+		credentials = append(credentials, "OAuth2 bearer token")
+	}
+	if len(cfg.HostTokenSources) > 0 {
+		credentials = append(credentials, "per-host bearer tokens")
+	}
+	return credentials
+}
+
+// registerPrompts adds the built-in prompts, rendered with cfg and settings;
+// registering again after a profile switch replaces them.
+func registerPrompts(mcpServer *mcp.Server, cfg client.Config, settings Settings) {
+	available := promptTools{toolsets: settings.Toolsets, openAPI: settings.OpenAPI != nil}
+	for _, prompt := range apiPrompts() {
+		mcpServer.AddPrompt(&mcp.Prompt{
+			Name:        prompt.name,
+			Title:       prompt.title,
+			Description: prompt.description,
+			Arguments:   prompt.arguments,
+		}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			for _, argument := range prompt.arguments {
+				if argument.Required && req.Params.Arguments[argument.Name] == "" {
+					return nil, fmt.Errorf("prompt %s needs the %s argument", prompt.name, argument.Name)
+				}
+			}
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "%s\n\n%s\n\nSteps:", prompt.description, promptContext(cfg, settings))
+			for i, step := range prompt.steps(req.Params.Arguments, available) {
+				fmt.Fprintf(&builder, "\n%d. %s", i+1, step)
+			}
+			return &mcp.GetPromptResult{
+				Description: prompt.description,
+				Messages:    []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: builder.String()}}},
+			}, nil
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_RegisterPrompts_EmbedsConfiguration(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	cfg := client.Config{BaseURL: "https://api.example.com", DefaultHeaders: map[string]string{"Authorization": "Bearer secret"}}
+	registerPrompts(mcpServer, cfg, Settings{Profile: "staging"})
+	session := connectTestSession(t, mcpServer)
+	ctx := context.Background()
+
+	list, err := session.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("listing prompts: %v", err)
+	}
+	if len(list.Prompts) != 3 {
+		t.Errorf("expected 3 prompts, got %d", len(list.Prompts))
+	}
+
+	result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "debug-failing-endpoint", Arguments: map[string]string{"url": "/orders", "method": "post"}})
+	if err != nil {
+		t.Fatalf("getting prompt: %v", err)
+	}
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	for _, want := range []string{"Base URL: https://api.example.com", "Credentials are configured (Authorization header)", "Active profile: staging", "Send POST /orders with http_request", "tls_inspect"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in prompt, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "secret") {
+		t.Errorf("prompt leaks the credential:\n%s", text)
+	}
+	if strings.Contains(text, "contract_check") {
+		t.Errorf("contract_check needs an OpenAPI description, got:\n%s", text)
+	}
+}
+
+func Test_RegisterPrompts_RespectsToolsets(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registerPrompts(mcpServer, client.Config{}, Settings{Toolsets: Toolsets{"core"}})
+	session := connectTestSession(t, mcpServer)
+
+	result, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{Name: "write-api-test", Arguments: map[string]string{"url": "https://api.example.com/items"}})
+	if err != nil {
+		t.Fatalf("getting prompt: %v", err)
+	}
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	for _, want := range []string{"No base URL is configured", "No credentials are configured", "as a go test"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in prompt, got:\n%s", want, text)
+		}
+	}
+	for _, tool := range []string{"export_session", "generate_payload"} {
+		if strings.Contains(text, tool) {
+			t.Errorf("expected no %s outside the enabled toolsets, got:\n%s", tool, text)
+		}
+	}
+}
+
+func Test_RegisterPrompts_RequiredArgument(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registerPrompts(mcpServer, client.Config{}, Settings{})
+	session := connectTestSession(t, mcpServer)
+
+	_, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{Name: "write-api-test"})
+	if err == nil || !strings.Contains(err.Error(), "needs the url argument") {
+		t.Errorf("expected a missing argument error, got %v", err)
+	}
+}
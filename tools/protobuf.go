@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// protobufContentType is sent with encoded request bodies unless the agent sets Content-Type.
+const protobufContentType = "application/x-protobuf"
+
+// ProtoTypes holds the message types of a FileDescriptorSet, for converting
+// between JSON and binary protobuf bodies.
+type ProtoTypes struct {
+	files *protoregistry.Files
+	types *dynamicpb.Types
+}
+
+// LoadProtoTypes reads a binary FileDescriptorSet, as written by
+// protoc --descriptor_set_out=FILE --include_imports.
+func LoadProtoTypes(path string) (*ProtoTypes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading descriptor set: %w", err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing descriptor set %s: %w", path, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("loading descriptor set %s (built with --include_imports?): %w", path, err)
+	}
+	return &ProtoTypes{files: files, types: dynamicpb.NewTypes(files)}, nil
+}
+
+// MessageNames returns the full names of every message type, sorted.
+func (p *ProtoTypes) MessageNames() []string {
+	var names []string
+	p.files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		names = appendMessageNames(names, file.Messages())
+		return true
+	})
+	slices.Sort(names)
+	return names
+}
+
+// describe lists the message types for the tool description, capped so a
+// large descriptor set does not crowd it.
+func (p *ProtoTypes) describe() string {
+	const maxListed = 20
+	names := p.MessageNames()
+	listed := strings.Join(names[:min(len(names), maxListed)], ", ")
+	if len(names) > maxListed {
+		listed += fmt.Sprintf(" and %d more", len(names)-maxListed)
+	}
+	return fmt.Sprintf(" Protobuf message types for protoRequestType/protoResponseType: %s.", listed)
+}
+
+func appendMessageNames(names []string, messages protoreflect.MessageDescriptors) []string {
+	for i := range messages.Len() {
+		message := messages.Get(i)
+		if !message.IsMapEntry() {
+			names = append(names, string(message.FullName()))
+		}
+		names = appendMessageNames(names, message.Messages())
+	}
+	return names
+}
+
+func (p *ProtoTypes) message(name string) (*dynamicpb.Message, error) {
+	if p == nil {
+		return nil, fmt.Errorf("protobuf message types need --proto-descriptors")
+	}
+	descriptor, err := p.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown protobuf message type %q (not in --proto-descriptors)", name)
+	}
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a protobuf message type", name)
+	}
+	return dynamicpb.NewMessage(messageDescriptor), nil
+}
+
+// Encode converts a JSON body (protobuf JSON mapping) into the binary encoding
+// of message type name.
+func (p *ProtoTypes) Encode(name, jsonBody string) (string, error) {
+	message, err := p.message(name)
+	if err != nil {
+		return "", err
+	}
+	if err := (protojson.UnmarshalOptions{Resolver: p.types}).Unmarshal([]byte(jsonBody), message); err != nil {
+		return "", fmt.Errorf("body is not a valid %s in JSON: %w", name, err)
+	}
+	encoded, err := proto.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s: %w", name, err)
+	}
+	return string(encoded), nil
+}
+
+// Decode converts a binary body of message type name to compact JSON.
+func (p *ProtoTypes) Decode(name string, body []byte) ([]byte, error) {
+	message, err := p.message(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := (proto.UnmarshalOptions{Resolver: p.types}).Unmarshal(body, message); err != nil {
+		return nil, fmt.Errorf("response body is not a valid %s: %w", name, err)
+	}
+	return protojson.MarshalOptions{Resolver: p.types}.Marshal(message)
+}
+
+// decodeProtoResponse replaces a binary response body with its JSON form so
+// jsonFilter and the table format work on it. It returns a note for the output
+// when the body cannot be decoded — an error response often carries another
+// message type — and leaves the body as it was.
+func decodeProtoResponse(types *ProtoTypes, name string, resp *client.Response) string {
+	if resp.SavedPath != "" || resp.Truncated {
+		return fmt.Sprintf("[protobuf: the body is not complete in memory (saveTo or size limit), not decoded as %s]\n", name)
+	}
+	decoded, err := types.Decode(name, resp.Body)
+	if err != nil {
+		return fmt.Sprintf("[protobuf: %s]\n", err)
+	}
+	resp.Body = decoded
+	resp.ContentType = "application/json"
+	return ""
+}
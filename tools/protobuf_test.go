@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeTestDescriptors writes a descriptor set with acme.Order {string id = 1; int32 quantity = 2;}.
+func writeTestDescriptors(t *testing.T) string {
+	t.Helper()
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("acme/order.proto"),
+		Package: proto.String("acme"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("quantity"), JsonName: proto.String("quantity"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	}}}
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal descriptor set: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "order.pb")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write descriptor set: %v", err)
+	}
+	return path
+}
+
+func Test_HttpRequestHandler_ProtobufRoundTrip(t *testing.T) {
+	types, err := LoadProtoTypes(writeTestDescriptors(t))
+	if err != nil {
+		t.Fatalf("LoadProtoTypes: %v", err)
+	}
+	var gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", protobufContentType)
+		w.Write(body) // echo the binary message back
+	}))
+	defer server.Close()
+	handler := makeHandler(newTestClient(server.URL), Settings{Proto: types}, NewHistory(10), NewStats(), NewVariables())
+
+	tests := []struct {
+		name  string
+		input HttpRequestInput
+		want  string
+	}{
+		{"encode and decode", HttpRequestInput{Body: `{"id":"o-1","quantity":3}`, ProtoRequestType: "acme.Order", ProtoResponseType: "acme.Order"}, `{"id":"o-1","quantity":3}`},
+		{"unknown type", HttpRequestInput{Body: `{}`, ProtoRequestType: "acme.Missing"}, `unknown protobuf message type "acme.Missing"`},
+		{"body does not match", HttpRequestInput{Body: `{"price":1}`, ProtoRequestType: "acme.Order"}, "not a valid acme.Order"},
+		{"undecodable response", HttpRequestInput{Body: "\xff\xff", ProtoResponseType: "acme.Order"}, "[protobuf: response body is not a valid acme.Order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Method, tt.input.URL = "POST", server.URL
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			if text := extractText(result); !strings.Contains(text, tt.want) {
+				t.Errorf("result = %s, want it to contain %s", text, tt.want)
+			}
+			if tt.input.ProtoRequestType != "" && !result.IsError && gotContentType != protobufContentType {
+				t.Errorf("Content-Type = %q, want %s", gotContentType, protobufContentType)
+			}
+		})
+	}
+}
+
+func Test_ProtoTypes_WithoutDescriptors(t *testing.T) {
+	var types *ProtoTypes
+	if _, err := types.Encode("acme.Order", "{}"); err == nil || !strings.Contains(err.Error(), "--proto-descriptors") {
+		t.Errorf("err = %v, want one naming --proto-descriptors", err)
+	}
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type RateLimitStatusInput struct {
+	Host string `json:"host,omitempty" jsonschema:"Only report this host (e.g. api.github.com); default: every host that sent rate-limit headers"`
+}
+
+const rateLimitStatusDescription = "The rate limits upstream APIs reported in this session, per host: requests left of the limit, when the window resets and any Retry-After, from the latest X-RateLimit-*, RateLimit-* or Retry-After headers, plus the 429s received. " +
+	"Check before a burst of calls and pace them so the quota lasts instead of running into 429s."
+
+// observedRateLimit is the latest rate-limit state a host reported.
+type observedRateLimit struct {
+	client.RateLimit
+	seen time.Time
+}
+
+// RateLimits renders the rate limits of one host, or of every host that sent
+// rate-limit headers when host is empty, as they stand at now.
+func (s *Stats) RateLimits(host string, now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var names []string
+	for name, stats := range s.hosts {
+		if (host == "" || name == host) && (stats.rateLimit != nil || stats.rateLimited > 0) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		if host != "" {
+			return fmt.Sprintf("No rate-limit headers or 429s from %s yet.", host)
+		}
+		return "No rate-limit headers or 429s seen yet."
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, describeRateLimit(name, s.hosts[name].rateLimit, s.hosts[name].rateLimited, now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeRateLimit(host string, limit *observedRateLimit, rateLimited int, now time.Time) string {
+	var parts []string
+	if limit == nil {
+		parts = append(parts, "no rate-limit headers")
+	} else {
+		switch {
+		case limit.Remaining >= 0 && limit.Limit >= 0:
+			parts = append(parts, fmt.Sprintf("%d/%d left", limit.Remaining, limit.Limit))
+		case limit.Remaining >= 0:
+			parts = append(parts, fmt.Sprintf("%d left", limit.Remaining))
+		case limit.Limit >= 0:
+			parts = append(parts, fmt.Sprintf("limit %d", limit.Limit))
+		}
+		if !limit.Reset.IsZero() {
+			if limit.Reset.After(now) {
+				parts = append(parts, fmt.Sprintf("resets in %s (%s)", limit.Reset.Sub(now).Round(time.Second), limit.Reset.Local().Format(time.TimeOnly)))
+			} else {
+				parts = append(parts, fmt.Sprintf("reset at %s, since refilled", limit.Reset.Local().Format(time.TimeOnly)))
+			}
+		}
+		if limit.RetryAfter.After(now) {
+			parts = append(parts, fmt.Sprintf("retry after %s (in %s)", limit.RetryAfter.Local().Format(time.TimeOnly), limit.RetryAfter.Sub(now).Round(time.Second)))
+		}
+		parts = append(parts, fmt.Sprintf("as of %s ago", now.Sub(limit.seen).Round(time.Second)))
+	}
+	if rateLimited > 0 {
+		parts = append(parts, fmt.Sprintf("%d × 429", rateLimited))
+	}
+	line := fmt.Sprintf("%s: %s", host, strings.Join(parts, ", "))
+	if limit != nil && limit.Remaining == 0 && limit.Reset.After(now) {
+		line += fmt.Sprintf(" — exhausted: wait until %s before calling it again", limit.Reset.Local().Format(time.TimeOnly))
+	}
+	return line
+}
+
+func makeRateLimitStatusHandler(stats *Stats) func(context.Context, *mcp.CallToolRequest, RateLimitStatusInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input RateLimitStatusInput) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: stats.RateLimits(input.Host, time.Now())}},
+		}, nil, nil
+	}
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_Stats_RateLimits(t *testing.T) {
+	stats := NewStats()
+	now := time.Now()
+	stats.Record("https://api.github.com/repos", 0, &client.Response{StatusCode: 200, Headers: http.Header{
+		"X-Ratelimit-Limit":     {"5000"},
+		"X-Ratelimit-Remaining": {"4987"},
+		"X-Ratelimit-Reset":     {"30"},
+	}}, time.Millisecond)
+	stats.Record("https://slow.example.com/", 0, &client.Response{StatusCode: 429, Headers: http.Header{"Retry-After": {"60"}}}, time.Millisecond)
+	stats.Record("https://plain.example.com/", 0, &client.Response{StatusCode: 200}, time.Millisecond)
+
+	report := stats.RateLimits("", now)
+	for _, want := range []string{"api.github.com: 4987/5000 left, resets in 30s", "slow.example.com: retry after ", "(in 1m0s)", "1 × 429"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected %q in:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "plain.example.com") {
+		t.Errorf("expected hosts without rate-limit headers left out:\n%s", report)
+	}
+	if report := stats.RateLimits("plain.example.com", now); !strings.Contains(report, "No rate-limit headers or 429s from plain.example.com") {
+		t.Errorf("unexpected report for a host without limits: %s", report)
+	}
+	if report := stats.RateLimits("", now.Add(time.Minute)); !strings.Contains(report, "since refilled") {
+		t.Errorf("expected a passed reset to be reported as refilled:\n%s", report)
+	}
+}
+
+func Test_Stats_RateLimitsExhausted(t *testing.T) {
+	stats := NewStats()
+	stats.Record("https://api.example.com/", 0, &client.Response{StatusCode: 200, Headers: http.Header{
+		"Ratelimit-Remaining": {"0"},
+		"Ratelimit-Reset":     {"90"},
+	}}, time.Millisecond)
+
+	report := stats.RateLimits("api.example.com", time.Now())
+	if !strings.Contains(report, "0 left") || !strings.Contains(report, "exhausted: wait until") {
+		t.Errorf("expected an exhausted quota, got: %s", report)
+	}
+}
+
+func Test_RateLimitStatusHandler_NoLimits(t *testing.T) {
+	result, _, err := makeRateLimitStatusHandler(NewStats())(context.Background(), nil, RateLimitStatusInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := extractText(result); text != "No rate-limit headers or 429s seen yet." {
+		t.Errorf("unexpected output: %s", text)
+	}
+}
+
+func Test_HttpRequestHandler_RecordsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	stats := NewStats()
+	handler := makeHandler(newTestClient(""), Settings{}, NewHistory(10), stats, NewVariables())
+	if _, _, err := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if report := stats.RateLimits("", time.Now()); !strings.Contains(report, "59/60 left") {
+		t.Errorf("expected the request's rate limit recorded, got: %s", report)
+	}
+}
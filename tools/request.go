@@ -12,59 +12,31 @@ import (
 	"github.com/lexandro/rest-api-mcp/client"
 )
 
-type HttpRequestInput struct {
-	Method                 string            `json:"method" jsonschema:"HTTP method: GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS"`
-	URL                    string            `json:"url" jsonschema:"Full URL or relative path (if base_url configured)"`
-	Headers                map[string]string `json:"headers,omitempty" jsonschema:"Request headers as key-value pairs"`
-	Body                   string            `json:"body,omitempty" jsonschema:"Request body (typically JSON)"`
-	QueryParams            map[string]string `json:"queryParams,omitempty" jsonschema:"Query parameters as key-value pairs"`
-	Timeout                string            `json:"timeout,omitempty" jsonschema:"Per-request timeout (e.g. 10s, 500ms)"`
-	FollowRedirects        *bool             `json:"followRedirects,omitempty" jsonschema:"Follow HTTP redirects (default: true)"`
-	IncludeResponseHeaders *bool             `json:"includeResponseHeaders,omitempty" jsonschema:"Include response headers in output (default: false)"`
-	JSONFilter             string            `json:"jsonFilter,omitempty" jsonschema:"GJSON path to extract from a JSON response body (JSON responses only), e.g. name, items.#.id, or {name,id} for multiple fields — use on large payloads to save tokens"`
-	SaveTo                 string            `json:"saveTo,omitempty" jsonschema:"Write the response body to this file path instead of returning it inline — use for binary or large responses"`
-	MaxResponseBytes       int64             `json:"maxResponseBytes,omitempty" jsonschema:"Per-request response size limit in bytes (overrides server default)"`
-	Files                  map[string]string `json:"files,omitempty" jsonschema:"Send multipart/form-data: form field name -> local file path (mutually exclusive with body)"`
-	FormFields             map[string]string `json:"formFields,omitempty" jsonschema:"Text fields for multipart/form-data (mutually exclusive with body)"`
-}
-
-var validMethods = map[string]bool{
-	"GET": true, "POST": true, "PUT": true, "DELETE": true,
-	"PATCH": true, "HEAD": true, "OPTIONS": true,
-}
-
-func Register(mcpServer *mcp.Server, httpClient *client.Client, cfg client.Config) {
-	openWorld := true
-	mcp.AddTool(mcpServer, &mcp.Tool{
-		Name:        "http_request",
-		Description: buildToolDescription(cfg),
-		Annotations: &mcp.ToolAnnotations{
-			OpenWorldHint: &openWorld,
-		},
-	}, makeHandler(httpClient))
-}
-
-// sensitiveHeaderNames contains lowercase header names whose values must be censored in the tool description.
-var sensitiveHeaderNames = map[string]bool{
-	"authorization":       true,
-	"proxy-authorization": true,
-	"x-api-key":           true,
-	"x-auth-token":        true,
+func censorHeaderValue(name, value string) string {
+	if client.IsSensitiveHeader(name) {
+		return "***"
+	}
+	return value
 }
 
-func censorHeaderValue(name, value string) string {
-	if sensitiveHeaderNames[strings.ToLower(name)] {
+func censorQueryParamValue(name, value string) string {
+	if client.IsSensitiveQueryParam(name) {
 		return "***"
 	}
 	return value
 }
 
-func buildToolDescription(cfg client.Config) string {
+func buildToolDescription(cfg client.Config, settings Settings) string {
 	desc := "Make HTTP requests. Use instead of curl for reliable cross-platform HTTP calls. " +
 		"Supports all methods, headers, body, query params, redirects, timeout, and multipart file upload (files/formFields). " +
 		"JSON responses are minified automatically. " +
 		"Token savers: jsonFilter extracts only the fields you need from JSON; saveTo writes large or binary bodies to a file instead of returning them."
 
+	if settings.Profile != "" {
+		desc += fmt.Sprintf(" Active profile: %s.", settings.Profile)
+	}
+	desc += settings.Toolsets.note()
+
 	if cfg.BaseURL != "" {
 		desc += fmt.Sprintf(" Base URL: %s — use relative paths like /api/endpoint.", cfg.BaseURL)
 	}
@@ -83,85 +55,240 @@ func buildToolDescription(cfg client.Config) string {
 		desc += fmt.Sprintf(" Default headers: %s.", strings.Join(headerParts, ", "))
 	}
 
-	return desc
-}
+	if len(cfg.DefaultQueryParams) > 0 {
+		keys := make([]string, 0, len(cfg.DefaultQueryParams))
+		for k := range cfg.DefaultQueryParams {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
 
-func errorResult(message string) *mcp.CallToolResult {
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: message}},
-		IsError: true,
+		queryParts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			queryParts = append(queryParts, fmt.Sprintf("%s=%s", k, censorQueryParamValue(k, cfg.DefaultQueryParams[k])))
+		}
+		desc += fmt.Sprintf(" Default query params: %s.", strings.Join(queryParts, ", "))
 	}
-}
 
-// validateInput checks the request input and returns the normalized method and
-// parsed per-request timeout. A non-empty error message means invalid input.
-func validateInput(input HttpRequestInput) (string, time.Duration, string) {
-	if input.Method == "" {
-		return "", 0, "method is required"
+	var scopedHeaders []string
+	for _, rule := range cfg.HostRules {
+		if len(rule.DefaultHeaders) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(rule.DefaultHeaders))
+		for name := range rule.DefaultHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		scopedHeaders = append(scopedHeaders, fmt.Sprintf("%s (%s)", rule.Match, strings.Join(names, ", ")))
 	}
-	upperMethod := strings.ToUpper(input.Method)
-	if !validMethods[upperMethod] {
-		return "", 0, fmt.Sprintf("unsupported method: %s", input.Method)
+	if len(scopedHeaders) > 0 {
+		desc += fmt.Sprintf(" Host-scoped default headers, sent only to matching URLs: %s.", strings.Join(scopedHeaders, "; "))
 	}
-	if input.URL == "" {
-		return "", 0, "url is required"
+
+	if cfg.CacheDir != "" {
+		desc += " GET responses are cached while fresh; pass cache: refresh for current data."
 	}
-	if input.Body != "" && (len(input.Files) > 0 || len(input.FormFields) > 0) {
-		return "", 0, "body and files/formFields are mutually exclusive"
+
+	if settings.Proto != nil {
+		desc += settings.Proto.describe()
 	}
 
-	var timeout time.Duration
-	if input.Timeout != "" {
-		var err error
-		timeout, err = time.ParseDuration(input.Timeout)
-		if err != nil {
-			return "", 0, fmt.Sprintf("invalid timeout: %s", err)
-		}
+	if settings.OpenAPI != nil {
+		desc += describeOpenAPI(settings.OpenAPI.Spec())
+	}
+
+	if settings.DryRun {
+		desc += " Dry-run mode: requests are resolved and shown, never sent."
+	}
+
+	if settings.Methods.isRestricted() {
+		desc += fmt.Sprintf(" Allowed methods: %s — other methods are rejected by server policy.", strings.Join(settings.Methods.allowedMethods(), ", "))
 	}
-	return upperMethod, timeout, ""
+	desc += settings.Headers.describe()
+	if rules := settings.URLs.describe(); rules != "" {
+		desc += fmt.Sprintf(" URL access rules (first match wins): %s.", rules)
+	}
+
+	return desc
 }
 
-func makeHandler(httpClient *client.Client) func(context.Context, *mcp.CallToolRequest, HttpRequestInput) (*mcp.CallToolResult, any, error) {
+func errorResult(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		IsError: true,
+	}
+}
+
+func makeHandler(httpClient *client.Client, settings Settings, history *History, stats *Stats, variables *Variables) func(context.Context, *mcp.CallToolRequest, HttpRequestInput) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, input HttpRequestInput) (*mcp.CallToolResult, any, error) {
 		method, timeout, validationError := validateInput(input)
 		if validationError != "" {
-			return errorResult(validationError), nil, nil
+			return requestError(errInvalidRequest, validationError)
+		}
+		if input.Template {
+			if err := renderTemplates(&input, variables.Snapshot(), settings.TemplateEnv); err != nil {
+				return requestError(errInvalidRequest, fmt.Sprintf("Template error: %s", err))
+			}
+		}
+		if err := resolveInputPaths(ctx, req, &input); err != nil {
+			return requestError(errPolicyDenied, err.Error())
+		}
+		if policyError := settings.Methods.check(method); policyError != "" {
+			return requestError(errPolicyDenied, policyError)
+		}
+		if limitError := settings.Limits.check(input); limitError != "" {
+			return requestError(errRequestTooLarge, limitError)
 		}
 
-		followRedirects := true
+		followRedirects := settings.FollowRedirects
 		if input.FollowRedirects != nil {
 			followRedirects = *input.FollowRedirects
 		}
-		includeHeaders := false
+		includeHeaders := settings.IncludeResponseHeaders
 		if input.IncludeResponseHeaders != nil {
 			includeHeaders = *input.IncludeResponseHeaders
 		}
 
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		headers, err := withWebDAVHeaders(httpClient, input, method, headers)
+		if err != nil {
+			return requestError(errInvalidRequest, fmt.Sprintf("Invalid destination: %s", err))
+		}
+		body := input.Body
+		if input.ProtoRequestType != "" {
+			encoded, err := settings.Proto.Encode(input.ProtoRequestType, input.Body)
+			if err != nil {
+				return requestError(errInvalidRequest, fmt.Sprintf("Protobuf: %s", err))
+			}
+			body = encoded
+			if _, ok := headers["Content-Type"]; !ok {
+				if headers == nil {
+					headers = make(map[string]string)
+				}
+				headers["Content-Type"] = protobufContentType
+			}
+		}
 		params := client.RequestParams{
 			Method:          method,
 			URL:             input.URL,
-			Headers:         input.Headers,
-			Body:            input.Body,
-			QueryParams:     input.QueryParams,
+			Headers:         headers,
+			Body:            body,
+			QueryParams:     queryParams(input),
+			QueryMerge:      input.QueryMerge,
+			ReplaceQuery:    input.ReplaceQuery,
 			Timeout:         timeout,
 			FollowRedirects: followRedirects,
+			CheckRedirect:   settings.URLs.redirectCheck(),
 			SaveTo:          input.SaveTo,
 			MaxResponseSize: input.MaxResponseBytes,
 			Files:           input.Files,
 			FormFields:      input.FormFields,
+			Progress:        newProgressReporter(ctx, req),
+			Verbose:         input.Verbose,
+			RedactResponse:  settings.Redaction.wireRedactor(input.ShowCookieValues),
+			InsecureTLS:     input.InsecureTLS,
+			CACert:          input.CACert,
+			ServerName:      input.ServerName,
+			HTTPVersion:     input.HTTPVersion,
+			MaxRedirects:    input.MaxRedirects,
+			Cache:           input.Cache,
+			ValidateJSON:    input.ValidateJSONBody,
+			MinifyJSON:      input.MinifyJSONBody,
+			MaxBandwidth:    input.MaxBandwidth,
 		}
 
-		resp, err := httpClient.ExecuteRequest(ctx, params)
+		var requestID string
+		if settings.RequestIDHeader != "" {
+			params.Headers, requestID = withRequestID(headers, settings.RequestIDHeader)
+		}
+
+		requestURL, err := httpClient.ResolveURL(params)
 		if err != nil {
-			return errorResult(fmt.Sprintf("Request failed: %s", err)), nil, nil
+			return requestError(errInvalidRequest, fmt.Sprintf("Invalid URL: %s", err))
+		}
+		if policyError := settings.URLs.check(method, requestURL); policyError != "" {
+			return requestError(errPolicyDenied, policyError)
+		}
+
+		if settings.DryRun || input.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, params)
+			if err != nil {
+				return requestError(errInvalidRequest, fmt.Sprintf("Dry run failed: %s", err))
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: formatDryRun(rendered, input)}},
+			}, nil, nil
 		}
 
+		summary := newSummaryFile(&params, settings, input.Summarize)
+		pager := newResponsePager(history, input, method, requestURL, headers, body)
+		started := time.Now()
+		resp, err := pager.execute(ctx, httpClient, params)
+		summary.settle(resp)
+		if resp != nil && resp.Coalesced && requestID != "" {
+			requestID = resp.RequestHeaders.Get(settings.RequestIDHeader) // the ID upstream saw
+		}
+		if ctx.Err() == nil && !pager.served() {
+			stats.Record(requestURL, int64(len(input.Body)), resp, time.Since(started))
+		}
+		if requestID != "" {
+			logRequestOutcome(method, requestURL, settings.RequestIDHeader, requestID, resp, err)
+		}
+		if err != nil {
+			return failedRequest(err, settings.RequestIDHeader, requestID)
+		}
+		summaryNote := summary.finish(ctx, req, resp)
+		var bodyNote string
+		if input.ProtoResponseType != "" {
+			bodyNote = decodeProtoResponse(settings.Proto, input.ProtoResponseType, resp)
+		} else if settings.DecodeBinaryBodies {
+			bodyNote = decodeBinaryBody(resp)
+		}
+		entry := newHistoryEntry(params, resp)
+		entry.Headers = headers // replayable without the generated request ID
+		entry.RequestID = requestID
+		summaryLink := summary.keep(history, history.Add(entry), resp)
+		if input.FollowLink != "" {
+			var linkNotes string
+			follower := linkFollower{httpClient: httpClient, settings: settings, history: history, stats: stats}
+			resp, linkNotes = follower.follow(ctx, params, resp, input.FollowLink, max(input.FollowLinkHops, 1))
+			bodyNote += linkNotes
+		}
+
+		if settings.OpenAPI != nil {
+			bodyNote = openAPINote(settings.OpenAPI.Spec(), method, resp) + bodyNote
+		}
+
+		pageNote := pager.slice(resp)
+		timing := newRequestTiming(resp, time.Since(started))
+		format := settings.DefaultFormat
+		if input.Format != "" {
+			format = input.Format
+		}
 		formatted := FormatResponse(resp, FormatOptions{
-			IncludeHeaders: includeHeaders,
-			JSONFilter:     input.JSONFilter,
+			Format:           format,
+			Columns:          input.Columns,
+			Fields:           input.Fields,
+			OmitFields:       input.OmitFields,
+			IncludeHeaders:   includeHeaders,
+			JSONFilter:       input.JSONFilter,
+			Verbose:          input.Verbose,
+			EchoRequest:      input.EchoRequest,
+			ShowCookieValues: input.ShowCookieValues,
+			Redaction:        settings.Redaction,
+			RequestIDHeader:  settings.RequestIDHeader,
+			RequestID:        requestID,
+			Timing:           &timing,
+			IncludeTiming:    includeTiming(settings, input),
 		})
+		text, tokens := fitTokens(headerNote+bodyNote+formatted+pageNote+summaryNote, input.MaxTokens, settings.TokenBudget)
+		content := []mcp.Content{&mcp.TextContent{Text: text}}
+		if summaryLink != nil {
+			content = append(content, summaryLink)
+		}
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: formatted}},
-		}, nil, nil
+			Content: content,
+			IsError: input.FailOnHTTPError && resp.StatusCode >= 400,
+		}, RequestOutput{Status: resp.StatusCode, Timing: timing, EstimatedTokens: tokens}, nil
 	}
 }
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Error codes of a failed http_request, for agents to branch on. Nothing was
+// sent for invalid_request, policy_denied and request_too_large.
+const (
+	errInvalidRequest   = "invalid_request"    // bad input, template, URL or body
+	errPolicyDenied     = "policy_denied"      // method or URL policy, --block-private-networks
+	errRequestTooLarge  = "request_too_large"  // --max-request-size and header limits
+	errDNS              = "dns_error"          // the host name does not resolve
+	errConnectTimeout   = "connect_timeout"    // no connection within the timeout
+	errConnection       = "connection_error"   // refused, reset or closed
+	errTLS              = "tls_error"          // handshake or certificate verification
+	errRequestTimeout   = "request_timeout"    // connected, but no complete response within the timeout
+	errTooManyRedirects = "too_many_redirects" // more than 10 redirects
+	errResponseTooLarge = "response_too_large" // response headers over the transport's limit
+	errNotAvailable     = "not_available"      // cache: only miss, or no cassette in replay mode
+	errCancelled        = "cancelled"          // the MCP client cancelled the call
+	errRequestFailed    = "request_failed"     // anything else, e.g. saveTo could not be written
+)
+
+// ErrorOutput is the structured output of a failed http_request.
+type ErrorOutput struct {
+	Error RequestError `json:"error"`
+}
+
+// RequestError is a failure's code and the message also shown as text.
+type RequestError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// requestError returns a failed http_request result: the text starts with the
+// code in brackets, and the structured output carries code and message.
+func requestError(code, message string) (*mcp.CallToolResult, any, error) {
+	return errorResult(fmt.Sprintf("[%s] %s", code, message)), ErrorOutput{RequestError{Code: code, Message: message}}, nil
+}
+
+// failedRequest classifies an ExecuteRequest error and adds a hint for the
+// agent's next step.
+func failedRequest(err error, requestIDHeader, requestID string) (*mcp.CallToolResult, any, error) {
+	code := classifyError(err)
+	var message string
+	switch code {
+	case errCancelled:
+		message = fmt.Sprintf("Request cancelled by the client: %s", err)
+	case errInvalidRequest:
+		message = fmt.Sprintf("Not sent: %s", err)
+	default:
+		message = fmt.Sprintf("Request failed: %s", err)
+	}
+	switch {
+	case code == errTLS:
+		message += " (use tls_inspect to see the certificate chain; caCert or insecureTLS trust it for one request)"
+	case errors.Is(err, client.ErrBlockedAddress):
+		message += " (private and internal networks are blocked by --block-private-networks)"
+	case errors.Is(err, client.ErrNotCached):
+		message += " (cache: only never touches the network; fetch it once without it)"
+	case errors.Is(err, client.ErrNoCassette):
+		message += " (replay mode never touches the network; record this request first with --record)"
+	case code == errTooManyRedirects:
+		message += " (set maxRedirects to return the last redirect response instead)"
+	}
+	if requestID != "" {
+		message += fmt.Sprintf(" [%s: %s]", requestIDHeader, requestID)
+	}
+	return requestError(code, message)
+}
+
+// classifyError maps an ExecuteRequest error to an error code.
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordErr tls.RecordHeaderError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return errCancelled
+	case errors.Is(err, client.ErrInvalidJSONBody):
+		return errInvalidRequest
+	case errors.Is(err, client.ErrBlockedAddress):
+		return errPolicyDenied
+	case errors.Is(err, client.ErrNotCached), errors.Is(err, client.ErrNoCassette):
+		return errNotAvailable
+	case errors.Is(err, client.ErrTooManyRedirects):
+		return errTooManyRedirects
+	case errors.As(err, &dnsErr):
+		return errDNS
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr), errors.As(err, &recordErr),
+		strings.Contains(err.Error(), "tls: "), strings.Contains(err.Error(), "x509: "):
+		return errTLS
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return errConnectTimeout
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return errRequestTimeout
+	case strings.Contains(err.Error(), "response headers exceeded"), strings.Contains(err.Error(), "header list larger than"):
+		return errResponseTooLarge
+	case errors.As(err, &opErr), errors.Is(err, client.ErrChaosInjected),
+		strings.Contains(err.Error(), "EOF"), strings.Contains(err.Error(), "connection reset"):
+		return errConnection
+	}
+	return errRequestFailed
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_ClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"cancelled", fmt.Errorf("cancelled after 1 attempt(s): %w", context.Canceled), errCancelled},
+		{"invalid JSON body", fmt.Errorf("%w: line 1", client.ErrInvalidJSONBody), errInvalidRequest},
+		{"blocked address", &url.Error{Op: "Get", URL: "http://10.0.0.1", Err: client.ErrBlockedAddress}, errPolicyDenied},
+		{"not cached", client.ErrNotCached, errNotAvailable},
+		{"no cassette", client.ErrNoCassette, errNotAvailable},
+		{"redirects", &url.Error{Op: "Get", URL: "http://a", Err: client.ErrTooManyRedirects}, errTooManyRedirects},
+		{"dns", &url.Error{Op: "Get", URL: "http://nope.invalid", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid"}}}, errDNS},
+		{"connect timeout", &url.Error{Op: "Get", URL: "http://a", Err: &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}}, errConnectTimeout},
+		{"refused", &url.Error{Op: "Get", URL: "http://a", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, errConnection},
+		{"tls", &url.Error{Op: "Get", URL: "https://a", Err: errors.New("tls: failed to verify certificate: x509: certificate signed by unknown authority")}, errTLS},
+		{"header limit", errors.New("net/http: server response headers exceeded 1048576 bytes; aborted"), errResponseTooLarge},
+		{"other", errors.New("creating saveTo file: permission denied"), errRequestFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_ErrorCodes(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+	}))
+	defer loop.Close()
+
+	tests := []struct {
+		name     string
+		settings Settings
+		input    HttpRequestInput
+		wantCode string
+	}{
+		{"invalid input", Settings{}, HttpRequestInput{Method: "GET"}, errInvalidRequest},
+		{"method policy", Settings{Methods: MethodPolicy{ReadOnly: true}}, HttpRequestInput{Method: "DELETE", URL: slow.URL}, errPolicyDenied},
+		{"request timeout", Settings{}, HttpRequestInput{Method: "GET", URL: slow.URL, Timeout: "50ms"}, errRequestTimeout},
+		{"too many redirects", Settings{FollowRedirects: true}, HttpRequestInput{Method: "GET", URL: loop.URL + "/"}, errTooManyRedirects},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeHandler(newTestClient(""), tt.settings, NewHistory(10), NewStats(), NewVariables())
+			result, out, _ := handler(context.Background(), nil, tt.input)
+			if !result.IsError || !strings.HasPrefix(extractText(result), "["+tt.wantCode+"] ") {
+				t.Errorf("text = %s, want the %s code", extractText(result), tt.wantCode)
+			}
+			output, ok := out.(ErrorOutput)
+			if !ok || output.Error.Code != tt.wantCode || output.Error.Message == "" {
+				t.Errorf("structured output = %+v", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// withRequestID returns headers plus headerName set to a fresh request ID, and
+// that ID. An ID the agent already set under headerName (any case) is kept, so
+// it can correlate several calls itself. headers is not modified.
+func withRequestID(headers map[string]string, headerName string) (map[string]string, string) {
+	for name, value := range headers {
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(headerName) {
+			return headers, value
+		}
+	}
+	requestID := client.NewUUID()
+	merged := make(map[string]string, len(headers)+1)
+	for name, value := range headers {
+		merged[name] = value
+	}
+	merged[headerName] = requestID
+	return merged, requestID
+}
+
+// logRequestOutcome writes one log line per request carrying its ID, so an
+// upstream log entry can be traced back to the agent's call.
+func logRequestOutcome(method, requestURL, headerName, requestID string, resp *client.Response, err error) {
+	if err != nil {
+		log.Printf("%s %s %s=%s failed: %s", method, client.RedactURL(requestURL), headerName, requestID, err)
+		return
+	}
+	log.Printf("%s %s %s=%s -> %d (%s)", method, client.RedactURL(requestURL), headerName, requestID, resp.StatusCode, resp.Duration.Round(time.Millisecond))
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func Test_WithRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantID  string // empty: expect a generated UUID
+	}{
+		{name: "no headers", headers: nil},
+		{name: "other headers", headers: map[string]string{"Accept": "application/json"}},
+		{name: "agent supplied ID kept", headers: map[string]string{"x-request-id": "mine"}, wantID: "mine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, requestID := withRequestID(tt.headers, "X-Request-Id")
+			if tt.wantID != "" && requestID != tt.wantID {
+				t.Errorf("expected ID %q, got %q", tt.wantID, requestID)
+			}
+			if tt.wantID == "" && !uuidPattern.MatchString(requestID) {
+				t.Errorf("expected generated UUID, got %q", requestID)
+			}
+			if tt.wantID == "" && merged["X-Request-Id"] != requestID {
+				t.Errorf("expected header set to the ID, got %v", merged)
+			}
+			if tt.wantID == "" && len(tt.headers) > 0 && len(tt.headers) == len(merged) {
+				t.Errorf("input headers must not be modified")
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_RequestIDHeader(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("X-Request-Id")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	var logged strings.Builder
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	history := NewHistory(10)
+	handler := makeHandler(newTestClient(server.URL), Settings{RequestIDHeader: "X-Request-Id"}, history, NewStats(), NewVariables())
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+
+	if !uuidPattern.MatchString(received) {
+		t.Fatalf("expected a UUID in X-Request-Id, got %q", received)
+	}
+	if !strings.Contains(extractText(result), "[X-Request-Id: "+received+"]") {
+		t.Errorf("expected request ID in output, got: %s", extractText(result))
+	}
+	if !strings.Contains(logged.String(), received) {
+		t.Errorf("expected request ID in log, got: %s", logged.String())
+	}
+	entries, _ := history.Select(nil)
+	if len(entries) != 1 || entries[0].RequestID != received || entries[0].Headers["X-Request-Id"] != "" {
+		t.Errorf("expected request ID recorded apart from the agent's headers, got %+v", entries)
+	}
+}
+
+func Test_HttpRequestHandler_RequestIDHeaderWithCoalescing(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-Id"))
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	httpClient := client.NewClient(client.Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, CoalesceWindow: time.Minute, RequestIDHeader: "X-Request-Id"})
+	handler := makeHandler(httpClient, Settings{RequestIDHeader: "X-Request-Id"}, NewHistory(10), NewStats(), NewVariables())
+	handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+
+	text := extractText(result)
+	if len(received) != 1 || !strings.Contains(text, "no new upstream call") {
+		t.Fatalf("expected the second GET to share the first's response, server saw %q: %s", received, text)
+	}
+	if !strings.Contains(text, "[X-Request-Id: "+received[0]+"]") {
+		t.Errorf("expected the ID that was sent upstream, got: %s", text)
+	}
+}
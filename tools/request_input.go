@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type HttpRequestInput struct {
+	Method                 string                  `json:"method" jsonschema:"HTTP method: GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS, or WebDAV PROPFIND, PROPPATCH, MKCOL, COPY, MOVE, REPORT"`
+	URL                    string                  `json:"url" jsonschema:"Full URL or relative path (if base_url configured)"`
+	Headers                map[string]StringValues `json:"headers,omitempty" jsonschema:"Request headers as key-value pairs, names case-insensitive; an array value sends several values (Accept, Cookie); an empty value removes a default header (e.g. Authorization for a third-party URL); Host overrides the Host header sent to the URL's address"`
+	Body                   string                  `json:"body,omitempty" jsonschema:"Request body (typically JSON)"`
+	ValidateJSONBody       *bool                   `json:"validateJsonBody,omitempty" jsonschema:"Check the body is valid JSON before sending and report syntax errors with line and column (default: on when Content-Type is JSON)"`
+	MinifyJSONBody         bool                    `json:"minifyJsonBody,omitempty" jsonschema:"Send the JSON body with insignificant whitespace removed"`
+	ProtoRequestType       string                  `json:"protoRequestType,omitempty" jsonschema:"Encode the JSON body as this protobuf message type (full name, e.g. acme.v1.CreateOrder) before sending; needs --proto-descriptors"`
+	ProtoResponseType      string                  `json:"protoResponseType,omitempty" jsonschema:"Decode the binary response body from this protobuf message type to JSON; needs --proto-descriptors"`
+	Template               bool                    `json:"template,omitempty" jsonschema:"Render url, header and query values and body as Go templates: {{.name}} session variables (set_variables), {{env \"NAME\"}}, {{uuid}}, {{now}}, {{timestamp}}, {{date \"2006-01-02\"}}, {{randomInt 1 100}}, {{randomString 8}}, {{json .name}}"`
+	QueryParams            map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters as key-value pairs; an array value repeats the parameter (?id=1&id=2)"`
+	OrderedQueryParams     []QueryParam            `json:"orderedQueryParams,omitempty" jsonschema:"Query parameters sent in exactly this order, after queryParams, as {name, value} items; a name may repeat — for APIs that care about parameter order"`
+	QueryMerge             string                  `json:"queryMerge,omitempty" jsonschema:"When a query parameter is already in the URL: override (default: replace the URL's value), append (send both) or error (fail instead); other URL parameters are always kept byte for byte, so signed URLs stay intact"`
+	ReplaceQuery           bool                    `json:"replaceQuery,omitempty" jsonschema:"Drop the URL's own query string and send only queryParams"`
+	Timeout                string                  `json:"timeout,omitempty" jsonschema:"Per-request timeout overriding the server default (e.g. 10s, 500ms)"`
+	FollowRedirects        *bool                   `json:"followRedirects,omitempty" jsonschema:"Follow HTTP redirects (default: server setting, normally true)"`
+	IncludeResponseHeaders *bool                   `json:"includeResponseHeaders,omitempty" jsonschema:"Include response headers and trailers in output (default: server setting, normally false)"`
+	FailOnHTTPError        bool                    `json:"failOnHttpError,omitempty" jsonschema:"Mark responses with status 400 or above as tool errors (body still included), for flows that should stop on HTTP failures; default false treats them as data"`
+	IncludeTiming          *bool                   `json:"includeTiming,omitempty" jsonschema:"Show the response duration under the status line (default: server setting, normally false); retries are always shown, and both are always in the structured output"`
+	JSONFilter             string                  `json:"jsonFilter,omitempty" jsonschema:"GJSON path to extract from a JSON response body (JSON responses only), e.g. name, items.#.id, or {name,id} for multiple fields — use on large payloads to save tokens"`
+	SaveTo                 string                  `json:"saveTo,omitempty" jsonschema:"Write the response body to this file path instead of returning it inline — use for binary or large responses"`
+	MaxTokens              int                     `json:"maxTokens,omitempty" jsonschema:"Cut the output to about this many tokens (estimated at 4 characters per token) instead of filling context with a large response"`
+	Summarize              bool                    `json:"summarize,omitempty" jsonschema:"When the body is over the response size limit, have your MCP client's model summarize all of it (sampling) and return the summary with a link to the raw body, instead of a truncated start; needs a client that supports sampling"`
+	MaxResponseBytes       int64                   `json:"maxResponseBytes,omitempty" jsonschema:"Per-request response size limit in bytes (overrides server default)"`
+	MaxBandwidth           int64                   `json:"maxBandwidth,omitempty" jsonschema:"Limit this request's upload and download to this many bytes per second, e.g. to keep a large transfer from saturating the link; a server-wide --max-bandwidth still applies"`
+	Files                  map[string]string       `json:"files,omitempty" jsonschema:"Send multipart/form-data: form field name -> local file path (mutually exclusive with body)"`
+	FormFields             map[string]string       `json:"formFields,omitempty" jsonschema:"Text fields for multipart/form-data (mutually exclusive with body)"`
+	DryRun                 bool                    `json:"dryRun,omitempty" jsonschema:"Resolve the request (URL, headers, body encoding) and return it without sending"`
+	Format                 string                  `json:"format,omitempty" jsonschema:"Output format: text (compact, default), raw (literal HTTP/1.1 message: status line, all headers, blank line, unmodified body), or table (JSON array of objects as a markdown table — combine with jsonFilter to select the array)"`
+	Fields                 []string                `json:"fields,omitempty" jsonschema:"Keep only these JSON fields (after jsonFilter), as dot paths such as id, owner.login or items.*.status; arrays pass through, so items.id is every item's id — simpler than jsonFilter for 'I only need id and status'"`
+	OmitFields             []string                `json:"omitFields,omitempty" jsonschema:"Drop these JSON fields (after fields), as dot paths with * wildcards, e.g. _links or items.metadata"`
+	Offset                 int                     `json:"offset,omitempty" jsonschema:"Return the JSON array's elements from this index on (0-based); the whole body of a GET is kept for 10 minutes, so the next page comes from memory instead of the API"`
+	Limit                  int                     `json:"limit,omitempty" jsonschema:"Return at most this many array elements (default 50 with offset); the output says the offset of the next page"`
+	ArrayPath              string                  `json:"arrayPath,omitempty" jsonschema:"GJSON path of the array to page with offset/limit, e.g. items (default: the top-level array); the rest of the body is kept"`
+	Columns                []string                `json:"columns,omitempty" jsonschema:"Columns for the table format: keys or GJSON paths such as owner.login, in order (default: all keys)"`
+	EchoRequest            bool                    `json:"echoRequest,omitempty" jsonschema:"Prefix the output with the method, resolved URL (base URL joined, query merged) and headers actually sent, secrets redacted"`
+	ShowCookieValues       bool                    `json:"showCookieValues,omitempty" jsonschema:"Show cookie values in the Set-Cookie summary (default: values are redacted as ***)"`
+	MaxRedirects           int                     `json:"maxRedirects,omitempty" jsonschema:"Redirects to follow before returning the redirect response itself (default 10)"`
+	InsecureTLS            bool                    `json:"insecureTLS,omitempty" jsonschema:"Skip TLS certificate verification for this request only (self-signed dev endpoints)"`
+	CACert                 string                  `json:"caCert,omitempty" jsonschema:"Extra CA certificate to trust for this request: a PEM file path or PEM text"`
+	HTTPVersion            string                  `json:"httpVersion,omitempty" jsonschema:"Use only this HTTP version for the request: 1.1 or 2 (default: negotiated; 2 over http:// is h2c) — for servers and load balancers that mishandle the other"`
+	ServerName             string                  `json:"serverName,omitempty" jsonschema:"TLS server name (SNI) to send and verify the certificate against — with a Host header, reach an HTTPS virtual host or CDN origin by IP"`
+	Verbose                bool                    `json:"verbose,omitempty" jsonschema:"Prepend a curl -v style dump of the request and response lines and headers as sent on the wire (secrets redacted) — use to debug header and encoding issues"`
+	Depth                  string                  `json:"depth,omitempty" jsonschema:"WebDAV Depth header: 0, 1 or infinity — PROPFIND 1 lists a collection's members"`
+	Destination            string                  `json:"destination,omitempty" jsonschema:"COPY/MOVE target; a relative path resolves against the request URL"`
+	Overwrite              *bool                   `json:"overwrite,omitempty" jsonschema:"COPY/MOVE: false fails instead of replacing an existing destination (default: server's, normally true)"`
+	FollowLink             string                  `json:"followLink,omitempty" jsonschema:"After the response, GET the hypermedia link with this relation and return that resource instead: self, next, any rel name (HAL _links, JSON:API links, a links array, Link header) or a GJSON path to a URL"`
+	FollowLinkHops         int                     `json:"followLinkHops,omitempty" jsonschema:"Follow followLink this many times, e.g. through next pages (default 1, at most 10); stops at a missing link or a URL already visited"`
+	Cache                  string                  `json:"cache,omitempty" jsonschema:"Response cache for GET requests when the server has one: bypass (ignore it), refresh (fetch and update it) or only (answer from it, even if stale, without the network); default reuses fresh cached responses"`
+}
+
+var validCacheModes = map[string]bool{client.CacheBypass: true, client.CacheRefresh: true, client.CacheOnly: true}
+
+var validMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true,
+	"PATCH": true, "HEAD": true, "OPTIONS": true,
+	"PROPFIND": true, "PROPPATCH": true, "MKCOL": true, "COPY": true, "MOVE": true, "REPORT": true,
+}
+
+// validateInput checks the request input and returns the normalized method and
+// parsed per-request timeout. A non-empty error message means invalid input.
+func validateInput(input HttpRequestInput) (string, time.Duration, string) {
+	if input.Method == "" {
+		return "", 0, "method is required"
+	}
+	upperMethod := strings.ToUpper(input.Method)
+	if !validMethods[upperMethod] {
+		return "", 0, fmt.Sprintf("unsupported method: %s", input.Method)
+	}
+	if input.URL == "" {
+		return "", 0, "url is required"
+	}
+	if input.Body != "" && (len(input.Files) > 0 || len(input.FormFields) > 0) {
+		return "", 0, "body and files/formFields are mutually exclusive"
+	}
+	if input.MaxRedirects < 0 || input.MaxBandwidth < 0 {
+		return "", 0, "maxRedirects and maxBandwidth must not be negative"
+	}
+	if input.Cache != "" && !validCacheModes[input.Cache] {
+		return "", 0, fmt.Sprintf("unsupported cache mode: %s (expected bypass, refresh or only)", input.Cache)
+	}
+	if err := client.ParseQueryMerge(input.QueryMerge); err != nil {
+		return "", 0, err.Error()
+	}
+	if err := client.ParseHTTPVersion(input.HTTPVersion); err != nil {
+		return "", 0, err.Error()
+	}
+	if message := validateWebDAV(input, upperMethod); message != "" {
+		return "", 0, message
+	}
+	if input.FollowLinkHops < 0 || input.FollowLinkHops > maxLinkHops {
+		return "", 0, fmt.Sprintf("followLinkHops must be between 1 and %d", maxLinkHops)
+	}
+	if input.FollowLinkHops > 0 && input.FollowLink == "" {
+		return "", 0, "followLinkHops needs followLink"
+	}
+	if input.Offset < 0 || input.Limit < 0 {
+		return "", 0, "offset and limit must not be negative"
+	}
+	if (input.Offset > 0 || input.Limit > 0) && (input.SaveTo != "" || input.Summarize || input.FollowLink != "" || input.Format == formatRaw) {
+		return "", 0, "offset and limit page the inline JSON body: they cannot be combined with saveTo, summarize, followLink or format raw"
+	}
+	if input.ArrayPath != "" && input.Offset == 0 && input.Limit == 0 {
+		return "", 0, "arrayPath needs offset or limit"
+	}
+	if input.MaxTokens < 0 {
+		return "", 0, "maxTokens must not be negative"
+	}
+	if input.Format != "" && !validFormats[input.Format] {
+		return "", 0, fmt.Sprintf("unsupported format: %s (expected text, raw or table)", input.Format)
+	}
+
+	var timeout time.Duration
+	if input.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(input.Timeout)
+		if err != nil {
+			return "", 0, fmt.Sprintf("invalid timeout: %s", err)
+		}
+	}
+	return upperMethod, timeout, ""
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+)
+
+// RequestLimits caps what the agent may send. A zero field disables that limit.
+type RequestLimits struct {
+	MaxBodyBytes   int64 // body, or multipart files and fields combined
+	MaxHeaders     int   // headers set by the agent (defaults are not counted)
+	MaxHeaderBytes int   // length of one header name plus value
+}
+
+// check returns an explanation if input exceeds a limit, or "" if it fits.
+func (l RequestLimits) check(input HttpRequestInput) string {
+	headers := joinHeaders(input.Headers)
+	if l.MaxHeaders > 0 && len(headers) > l.MaxHeaders {
+		return fmt.Sprintf("request has %d headers, the server allows at most %d (--max-request-headers)", len(headers), l.MaxHeaders)
+	}
+	if l.MaxHeaderBytes > 0 {
+		for name, value := range headers {
+			if size := len(name) + len(value); size > l.MaxHeaderBytes {
+				return fmt.Sprintf("header %s is %d bytes, the server allows at most %d per header (--max-header-size)", name, size, l.MaxHeaderBytes)
+			}
+		}
+	}
+	if l.MaxBodyBytes > 0 {
+		if size := requestBodySize(input); size > l.MaxBodyBytes {
+			return fmt.Sprintf("request body is %d bytes, the server allows at most %d (--max-request-size)", size, l.MaxBodyBytes)
+		}
+	}
+	return ""
+}
+
+// requestBodySize estimates the bytes the request body will carry. Files that
+// cannot be stat'ed count as zero; the upload itself reports the error.
+func requestBodySize(input HttpRequestInput) int64 {
+	size := int64(len(input.Body))
+	for name, value := range input.FormFields {
+		size += int64(len(name) + len(value))
+	}
+	for _, path := range input.Files {
+		if info, err := os.Stat(path); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_RequestLimits_Check(t *testing.T) {
+	largeFile := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(largeFile, make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	limits := RequestLimits{MaxBodyBytes: 1024, MaxHeaders: 2, MaxHeaderBytes: 32}
+
+	tests := []struct {
+		name        string
+		input       HttpRequestInput
+		wantMessage string
+	}{
+		{"within limits", HttpRequestInput{Body: "{}", Headers: map[string]StringValues{"Accept": {"application/json"}}}, ""},
+		{"body too large", HttpRequestInput{Body: strings.Repeat("x", 1025)}, "--max-request-size"},
+		{"file too large", HttpRequestInput{Files: map[string]string{"file": largeFile}}, "2048 bytes"},
+		{"too many headers", HttpRequestInput{Headers: map[string]StringValues{"A": {"1"}, "B": {"2"}, "C": {"3"}}}, "--max-request-headers"},
+		{"header too large", HttpRequestInput{Headers: map[string]StringValues{"X-Blob": {strings.Repeat("x", 40)}}}, "header X-Blob is 46 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := limits.check(tt.input)
+			if tt.wantMessage == "" && got != "" {
+				t.Errorf("expected no limit error, got: %s", got)
+			}
+			if tt.wantMessage != "" && !strings.Contains(got, tt.wantMessage) {
+				t.Errorf("expected message containing %q, got: %q", tt.wantMessage, got)
+			}
+		})
+	}
+
+	if got := (RequestLimits{}).check(HttpRequestInput{Body: strings.Repeat("x", 1<<20)}); got != "" {
+		t.Errorf("zero limits must not restrict, got: %s", got)
+	}
+}
+
+func Test_HttpRequestHandler_RequestLimitRejectsWithoutSending(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	handler := makeHandler(newTestClient(server.URL), Settings{Limits: RequestLimits{MaxBodyBytes: 10}}, NewHistory(10), NewStats(), NewVariables())
+	result, _, err := handler(context.Background(), nil, HttpRequestInput{Method: "POST", URL: server.URL, Body: strings.Repeat("x", 11)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(extractText(result), "request body is 11 bytes") {
+		t.Errorf("expected body size error, got: %s", extractText(result))
+	}
+	if requestCount != 0 {
+		t.Errorf("expected no request to reach the server, got %d", requestCount)
+	}
+}
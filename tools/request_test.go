@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -24,6 +25,22 @@ func newTestClient(serverURL string) *client.Client {
 	})
 }
 
+// connectTestSession connects an in-memory MCP client to mcpServer.
+func connectTestSession(t *testing.T, mcpServer *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("connecting server: %v", err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connecting client: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
 func Test_HttpRequestHandler_ValidGet(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
@@ -32,7 +49,7 @@ func Test_HttpRequestHandler_ValidGet(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "GET",
@@ -59,7 +76,7 @@ func Test_HttpRequestHandler_MissingMethod(t *testing.T) {
 		Timeout:         5 * time.Second,
 		MaxResponseSize: 1024,
 	})
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		URL: "http://example.com",
@@ -81,7 +98,7 @@ func Test_HttpRequestHandler_MissingURL(t *testing.T) {
 		Timeout:         5 * time.Second,
 		MaxResponseSize: 1024,
 	})
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "GET",
@@ -103,7 +120,7 @@ func Test_HttpRequestHandler_InvalidMethod(t *testing.T) {
 		Timeout:         5 * time.Second,
 		MaxResponseSize: 1024,
 	})
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "INVALID",
@@ -131,12 +148,12 @@ func Test_HttpRequestHandler_PostWithBody(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method:  "POST",
 		URL:     server.URL,
-		Headers: map[string]string{"Content-Type": "application/json"},
+		Headers: map[string]StringValues{"Content-Type": {"application/json"}},
 		Body:    `{"key":"value"}`,
 	})
 	if err != nil {
@@ -155,8 +172,60 @@ func Test_HttpRequestHandler_PostWithBody(t *testing.T) {
 	}
 }
 
+func Test_HttpRequestHandler_SettingsDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.Header().Set("X-Target", "reached")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	handler := makeHandler(c, Settings{FollowRedirects: false, IncludeResponseHeaders: true}, NewHistory(10), NewStats(), NewVariables())
+
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+	text := extractText(result)
+	if !strings.Contains(text, "302 Found") {
+		t.Errorf("expected redirect not followed by default, got: %s", text)
+	}
+	if !strings.Contains(text, "Location: /target") {
+		t.Errorf("expected response headers by default, got: %s", text)
+	}
+
+	followRedirects := true
+	result, _, _ = handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, FollowRedirects: &followRedirects})
+	text = extractText(result)
+	if !strings.Contains(text, "200 OK") || !strings.Contains(text, "X-Target: reached") {
+		t.Errorf("expected explicit input to override settings, got: %s", text)
+	}
+}
+
+func Test_HttpRequestHandler_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	result, _, _ := handler(ctx, nil, HttpRequestInput{Method: "GET", URL: server.URL})
+	if !result.IsError {
+		t.Fatal("expected error result for cancelled request")
+	}
+	if !strings.Contains(extractText(result), "Request cancelled by the client") {
+		t.Errorf("expected cancellation message, got: %s", extractText(result))
+	}
+}
+
 func Test_BuildToolDescription_NoConfig(t *testing.T) {
-	desc := buildToolDescription(client.Config{})
+	desc := buildToolDescription(client.Config{}, Settings{})
 	if !strings.Contains(desc, "Make HTTP requests") {
 		t.Errorf("expected base description, got: %s", desc)
 	}
@@ -171,7 +240,7 @@ func Test_BuildToolDescription_NoConfig(t *testing.T) {
 func Test_BuildToolDescription_WithBaseURL(t *testing.T) {
 	desc := buildToolDescription(client.Config{
 		BaseURL: "http://localhost:8080",
-	})
+	}, Settings{})
 	if !strings.Contains(desc, "Base URL: http://localhost:8080") {
 		t.Errorf("expected base URL in description, got: %s", desc)
 	}
@@ -186,7 +255,7 @@ func Test_BuildToolDescription_WithDefaultHeaders(t *testing.T) {
 			"Content-Type": "application/json",
 			"Accept":       "application/json",
 		},
-	})
+	}, Settings{})
 	if !strings.Contains(desc, "Default headers:") {
 		t.Errorf("expected Default headers section, got: %s", desc)
 	}
@@ -205,7 +274,7 @@ func Test_BuildToolDescription_CensorsSensitiveHeaders(t *testing.T) {
 			"X-Api-Key":     "sk-my-secret-key",
 			"Content-Type":  "application/json",
 		},
-	})
+	}, Settings{})
 	if strings.Contains(desc, "secret-token-123") {
 		t.Errorf("expected Authorization value to be censored, got: %s", desc)
 	}
@@ -223,6 +292,27 @@ func Test_BuildToolDescription_CensorsSensitiveHeaders(t *testing.T) {
 	}
 }
 
+func Test_BuildToolDescription_WithDefaultQueryParams(t *testing.T) {
+	desc := buildToolDescription(client.Config{
+		DefaultQueryParams: map[string]string{
+			"api_version": "2023-10",
+			"api_key":     "sk-query-secret",
+		},
+	}, Settings{})
+	if !strings.Contains(desc, "Default query params:") {
+		t.Errorf("expected Default query params section, got: %s", desc)
+	}
+	if !strings.Contains(desc, "api_version=2023-10") {
+		t.Errorf("expected api_version param in description, got: %s", desc)
+	}
+	if strings.Contains(desc, "sk-query-secret") {
+		t.Errorf("expected api_key value to be censored, got: %s", desc)
+	}
+	if !strings.Contains(desc, "api_key=***") {
+		t.Errorf("expected censored api_key param, got: %s", desc)
+	}
+}
+
 func Test_BuildToolDescription_FullConfig(t *testing.T) {
 	desc := buildToolDescription(client.Config{
 		BaseURL: "https://api.example.com",
@@ -230,7 +320,7 @@ func Test_BuildToolDescription_FullConfig(t *testing.T) {
 			"Authorization": "Bearer token",
 			"Content-Type":  "application/json",
 		},
-	})
+	}, Settings{})
 	if !strings.Contains(desc, "Base URL: https://api.example.com") {
 		t.Errorf("expected base URL, got: %s", desc)
 	}
@@ -244,7 +334,7 @@ func Test_BuildToolDescription_FullConfig(t *testing.T) {
 
 func Test_HttpRequestHandler_BodyAndFilesMutuallyExclusive(t *testing.T) {
 	c := newTestClient("")
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "POST",
@@ -271,7 +361,7 @@ func Test_HttpRequestHandler_JSONFilter(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method:     "GET",
@@ -300,7 +390,7 @@ func Test_HttpRequestHandler_SaveTo(t *testing.T) {
 
 	savePath := filepath.Join(t.TempDir(), "download.bin")
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "GET",
@@ -336,7 +426,7 @@ func Test_HttpRequestHandler_SaveToErrorResponseStaysInline(t *testing.T) {
 
 	savePath := filepath.Join(t.TempDir(), "should-not-exist.bin")
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method: "GET",
@@ -380,7 +470,7 @@ func Test_HttpRequestHandler_MultipartUpload(t *testing.T) {
 	defer server.Close()
 
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method:     "POST",
@@ -414,7 +504,7 @@ func Test_HttpRequestHandler_MaxResponseBytesOverride(t *testing.T) {
 
 	// Client default is 1024 bytes; the per-request override shrinks it to 100.
 	c := newTestClient(server.URL)
-	handler := makeHandler(c)
+	handler := makeHandler(c, Settings{FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
 
 	result, _, err := handler(context.Background(), nil, HttpRequestInput{
 		Method:           "GET",
@@ -442,3 +532,82 @@ func extractText(result *mcp.CallToolResult) string {
 	}
 	return strings.Join(texts, "\n")
 }
+
+func Test_BuildToolDescription_WithHostRules(t *testing.T) {
+	desc := buildToolDescription(client.Config{
+		HostRules: []client.HostRule{
+			{Match: "api.internal.example.com", DefaultHeaders: map[string]string{"Authorization": "Bearer secret"}},
+			{Match: "*.example.com", Timeout: time.Minute},
+		},
+	}, Settings{})
+	if !strings.Contains(desc, "Host-scoped default headers, sent only to matching URLs: api.internal.example.com (Authorization).") {
+		t.Errorf("expected host-scoped header names in description, got: %s", desc)
+	}
+	if strings.Contains(desc, "secret") {
+		t.Errorf("header values must not appear in the description, got: %s", desc)
+	}
+}
+
+func Test_HttpRequestHandler_Format(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{ "a": 1 }`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		settings   Settings
+		format     string
+		wantPrefix string
+		wantError  bool
+	}{
+		{"default text", Settings{}, "", "200 OK", false},
+		{"input raw", Settings{}, "raw", "HTTP/1.1 200 OK\r\n", false},
+		{"server default raw", Settings{DefaultFormat: "raw"}, "", "HTTP/1.1 200 OK\r\n", false},
+		{"input overrides server default", Settings{DefaultFormat: "raw"}, "text", "200 OK", false},
+		{"unknown", Settings{}, "xml", "[invalid_request] unsupported format", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeHandler(newTestClient(server.URL), tt.settings, NewHistory(10), NewStats(), NewVariables())
+			result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, Format: tt.format})
+			if result.IsError != tt.wantError || !strings.HasPrefix(extractText(result), tt.wantPrefix) {
+				t.Errorf("expected prefix %q (error %v), got %q", tt.wantPrefix, tt.wantError, extractText(result))
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_FailOnHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"nope"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		status  string
+		fail    bool
+		wantErr bool
+	}{
+		{"4xx as data", "404", false, false},
+		{"4xx as error", "404", true, true},
+		{"5xx as error", "500", true, true},
+		{"2xx never fails", "200", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeHandler(newTestClient(server.URL), Settings{}, NewHistory(10), NewStats(), NewVariables())
+			result, _, _ := handler(context.Background(), nil, HttpRequestInput{
+				Method: "GET", URL: server.URL + "?status=" + tt.status, FailOnHTTPError: tt.fail,
+			})
+			text := extractText(result)
+			if result.IsError != tt.wantErr || !strings.HasPrefix(text, tt.status) || !strings.Contains(text, `"error":"nope"`) {
+				t.Errorf("IsError = %v, text = %s", result.IsError, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Read-only MCP resources.
+const (
+	configResourceURI  = "config://effective"
+	historyResourceURI = "history://recent"
+	openAPIResourceURI = "openapi://spec"
+)
+
+// sessionResources serves the MCP resources and notifies subscribed clients
+// when they change: history on every recorded call, the configuration on a
+// profile switch and the OpenAPI description on a refresh that changed it.
+type sessionResources struct {
+	mu      sync.Mutex
+	config  string
+	openAPI []byte
+}
+
+// historyResourceEntry is one call in history://recent, without the headers
+// and body the agent sent.
+type historyResourceEntry struct {
+	ID         int       `json:"id"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"` // sensitive query values and passwords redacted
+	Status     int       `json:"status"`
+	DurationMs int64     `json:"durationMs"`
+	Time       time.Time `json:"time"`
+	RequestID  string    `json:"requestId,omitempty"`
+}
+
+// sync registers the resources for settings, replacing earlier ones;
+// openapi://spec is only there with an OpenAPI description.
+func (r *sessionResources) sync(mcpServer *mcp.Server, settings Settings, history *History) {
+	history.notifyAdd(func() {
+		mcpServer.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: historyResourceURI})
+	})
+	mcpServer.AddResource(&mcp.Resource{
+		URI:         historyResourceURI,
+		Name:        "history",
+		Title:       "Recent requests",
+		Description: "The session's recent calls, oldest first: method, URL, status and duration. Updated after every call.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		entries, _ := history.Select(nil)
+		recent := make([]historyResourceEntry, 0, len(entries))
+		for _, entry := range entries {
+			recent = append(recent, historyResourceEntry{
+				ID:         entry.ID,
+				Method:     entry.Method,
+				URL:        client.RedactURL(entry.URL),
+				Status:     entry.StatusCode,
+				DurationMs: entry.Duration.Milliseconds(),
+				Time:       entry.Time,
+				RequestID:  entry.RequestID,
+			})
+		}
+		text, err := json.MarshalIndent(recent, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return textResource(historyResourceURI, "application/json", string(text)), nil
+	})
+
+	mcpServer.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: savedBodyURITemplate,
+		Name:        "body",
+		Title:       "Saved response body",
+		Description: "The raw body of a call whose oversized response summarize summarized, by history ID. The last few are kept.",
+	}, history.bodies.read)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var changed []string
+	if settings.EffectiveConfig != "" {
+		config := settings.EffectiveConfig
+		mcpServer.AddResource(&mcp.Resource{
+			URI:         configResourceURI,
+			Name:        "config",
+			Title:       "Effective configuration",
+			Description: "The server's resolved configuration for the active profile, keyed by flag name, secrets redacted.",
+			MIMEType:    "application/yaml",
+		}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return textResource(configResourceURI, "application/yaml", config), nil
+		})
+		if r.config != "" && r.config != config {
+			changed = append(changed, configResourceURI)
+		}
+		r.config = config
+	}
+
+	var document []byte
+	if settings.OpenAPI != nil {
+		document = settings.OpenAPI.Spec().Document()
+	}
+	if document == nil {
+		mcpServer.RemoveResources(openAPIResourceURI)
+	} else {
+		mcpServer.AddResource(&mcp.Resource{
+			URI:         openAPIResourceURI,
+			Name:        "openapi",
+			Title:       "OpenAPI description",
+			Description: "The OpenAPI description loaded with --openapi, as JSON. Updated when openapi_refresh finds a change.",
+			MIMEType:    "application/json",
+		}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return textResource(openAPIResourceURI, "application/json", string(document)), nil
+		})
+		if r.openAPI != nil && !bytes.Equal(r.openAPI, document) {
+			changed = append(changed, openAPIResourceURI)
+		}
+	}
+	r.openAPI = document
+
+	for _, uri := range changed {
+		mcpServer.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+func textResource(uri, mimeType, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeType, Text: text}}}
+}
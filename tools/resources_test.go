@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func Test_sessionResources_ServeAndNotify(t *testing.T) {
+	subscriptions := func(context.Context, *mcp.SubscribeRequest) error { return nil }
+	unsubscriptions := func(context.Context, *mcp.UnsubscribeRequest) error { return nil }
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, &mcp.ServerOptions{SubscribeHandler: subscriptions, UnsubscribeHandler: unsubscriptions})
+	history := NewHistory(10)
+	resources := &sessionResources{}
+	resources.sync(mcpServer, Settings{EffectiveConfig: "base-url: https://api.example.com\n"}, history)
+
+	updated := make(chan string, 10)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) { updated <- req.Params.URI },
+	}).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	read := func(uri string) string {
+		t.Helper()
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatalf("reading %s: %v", uri, err)
+		}
+		return result.Contents[0].Text
+	}
+	if text := read(configResourceURI); !strings.Contains(text, "base-url: https://api.example.com") {
+		t.Errorf("unexpected configuration: %s", text)
+	}
+	if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: openAPIResourceURI}); err == nil {
+		t.Error("expected no OpenAPI resource without a description")
+	}
+
+	for _, uri := range []string{historyResourceURI, configResourceURI, openAPIResourceURI} {
+		if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			t.Fatalf("subscribing to %s: %v", uri, err)
+		}
+	}
+	history.Add(HistoryEntry{Method: "GET", URL: "https://api.example.com/items?api_key=secret", StatusCode: 200})
+	waitForUpdate(t, updated, historyResourceURI)
+	if text := read(historyResourceURI); !strings.Contains(text, `"status": 200`) || strings.Contains(text, "secret") {
+		t.Errorf("expected the call with its key redacted, got: %s", text)
+	}
+
+	resources.sync(mcpServer, Settings{EffectiveConfig: "base-url: https://staging.example.com\n", OpenAPI: openAPISource(t, `{"openapi":"3.0.0","paths":{}}`)}, history)
+	waitForUpdate(t, updated, configResourceURI)
+	if text := read(openAPIResourceURI); !strings.Contains(text, `"openapi":"3.0.0"`) {
+		t.Errorf("unexpected OpenAPI description: %s", text)
+	}
+}
+
+func waitForUpdate(t *testing.T, updated <-chan string, want string) {
+	t.Helper()
+	for {
+		select {
+		case uri := <-updated:
+			if uri == want {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no update notification for %s", want)
+		}
+	}
+}
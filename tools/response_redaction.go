@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// ResponseRedaction censors secrets in the response headers shown to the
+// agent: Set-Cookie values, credentials in authentication challenges, the
+// headers the tool description censors, and any named in Headers.
+type ResponseRedaction struct {
+	Headers  []string // more canonical names to censor; a trailing * matches every name with that prefix
+	Disabled bool     // --unsafe-show-response-secrets: show every value as received
+}
+
+// token68 is a bare credential in an authentication challenge (RFC 9110 §11.2).
+var token68 = regexp.MustCompile(`^[A-Za-z0-9\-._~+/]+=*$`)
+
+// value returns the header value as it may be shown. Set-Cookie keeps the
+// cookie name and attributes; showCookieValues (the agent's showCookieValues)
+// shows its value, as in the cookie summary.
+func (r ResponseRedaction) value(name, value string, showCookieValues bool) string {
+	if r.Disabled {
+		return value
+	}
+	switch strings.ToLower(name) {
+	case "set-cookie":
+		if showCookieValues {
+			return value
+		}
+		return censorSetCookie(value)
+	case "www-authenticate", "proxy-authenticate":
+		return censorChallenges(value)
+	}
+	if client.IsSensitiveHeader(name) || matchesHeaderName(r.Headers, name) {
+		return "***"
+	}
+	return value
+}
+
+// wireRedactor returns value as a function for the response headers of the
+// verbose wire dump.
+func (r ResponseRedaction) wireRedactor(showCookieValues bool) func(name, value string) string {
+	return func(name, value string) string {
+		return r.value(name, value, showCookieValues)
+	}
+}
+
+// censorSetCookie replaces the cookie value, keeping an empty one (a deletion).
+func censorSetCookie(value string) string {
+	pair, attributes, _ := strings.Cut(value, ";")
+	name, cookieValue, found := strings.Cut(pair, "=")
+	if !found || strings.Trim(strings.TrimSpace(cookieValue), `"`) == "" {
+		return value
+	}
+	censored := name + "=***"
+	if attributes != "" {
+		censored += ";" + attributes
+	}
+	return censored
+}
+
+// censorChallenges hides token68 credentials such as "Negotiate <token>",
+// keeping the scheme and parameters like realm and error that explain a 401.
+func censorChallenges(value string) string {
+	challenges := strings.Split(value, ",")
+	for i, challenge := range challenges {
+		_, credential, found := strings.Cut(strings.TrimSpace(challenge), " ")
+		if credential = strings.TrimSpace(credential); found && token68.MatchString(credential) {
+			challenges[i] = strings.Replace(challenge, credential, "***", 1)
+		}
+	}
+	return strings.Join(challenges, ",")
+}
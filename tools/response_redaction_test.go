@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_ResponseRedaction_Value(t *testing.T) {
+	redaction := ResponseRedaction{Headers: []string{"X-Session-Token", "X-Secret-*"}}
+	tests := []struct {
+		name, header, value, want string
+	}{
+		{"cookie value", "Set-Cookie", "sid=abc123; Path=/; HttpOnly", "sid=***; Path=/; HttpOnly"},
+		{"deleted cookie", "Set-Cookie", "sid=; Max-Age=0", "sid=; Max-Age=0"},
+		{"challenge token", "WWW-Authenticate", "Negotiate YIIBhwYGKwYBBQUC", "Negotiate ***"},
+		{"challenge parameters", "WWW-Authenticate", `Bearer realm="api", error="invalid_token"`, `Bearer realm="api", error="invalid_token"`},
+		{"credential header", "Authorization", "Bearer abc", "***"},
+		{"listed header", "x-session-token", "s3cr3t", "***"},
+		{"listed prefix", "X-Secret-Key", "s3cr3t", "***"},
+		{"other header", "Content-Type", "application/json", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redaction.value(tt.header, tt.value, false); got != tt.want {
+				t.Errorf("value(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+	if got := redaction.value("Set-Cookie", "sid=abc123", true); got != "sid=abc123" {
+		t.Errorf("expected showCookieValues to show the cookie, got %q", got)
+	}
+	if got := (ResponseRedaction{Disabled: true}).value("X-Api-Key", "k", false); got != "k" {
+		t.Errorf("expected the unsafe override to show the value, got %q", got)
+	}
+}
+
+func Test_FormatResponse_RedactsHeaders(t *testing.T) {
+	resp := &client.Response{
+		StatusCode: 200,
+		StatusText: "OK",
+		Headers:    http.Header{"Set-Cookie": {"sid=abc123; Path=/"}, "X-Session-Token": {"s3cr3t"}},
+	}
+	redaction := ResponseRedaction{Headers: []string{"X-Session-Token"}}
+	for _, format := range []string{formatText, formatRaw} {
+		output := FormatResponse(resp, FormatOptions{Format: format, IncludeHeaders: true, Redaction: redaction})
+		if strings.Contains(output, "abc123") || strings.Contains(output, "s3cr3t") {
+			t.Errorf("%s output shows a secret: %s", format, output)
+		}
+		if !strings.Contains(output, "Set-Cookie: sid=***; Path=/") {
+			t.Errorf("%s output lacks the censored cookie: %s", format, output)
+		}
+	}
+}
+
+func Test_HttpRequestHandler_RedactsWireDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Session-Token", "s3cr3t")
+		w.Header().Set("WWW-Authenticate", "Negotiate YIIBhwYGKwYBBQUC")
+		w.Header().Set("Set-Cookie", "sid=abc123; Path=/")
+	}))
+	defer server.Close()
+	input := HttpRequestInput{Method: "GET", URL: server.URL, Verbose: true}
+
+	redacted := Settings{Redaction: ResponseRedaction{Headers: []string{"X-Session-Token"}}}
+	result, _, _ := makeHandler(newTestClient(""), redacted, NewHistory(10), NewStats(), NewVariables())(context.Background(), nil, input)
+	text := extractText(result)
+	for _, want := range []string{"< X-Session-Token: ***", "< Www-Authenticate: Negotiate ***", "< Set-Cookie: sid=***; Path=/"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("wire dump lacks %q: %s", want, text)
+		}
+	}
+
+	unsafe := Settings{Redaction: ResponseRedaction{Disabled: true}}
+	result, _, _ = makeHandler(newTestClient(""), unsafe, NewHistory(10), NewStats(), NewVariables())(context.Background(), nil, input)
+	if text := extractText(result); !strings.Contains(text, "< X-Session-Token: s3cr3t") || !strings.Contains(text, "< Set-Cookie: sid=abc123; Path=/") {
+		t.Errorf("expected --unsafe-show-response-secrets to show the wire values: %s", text)
+	}
+}
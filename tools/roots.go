@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// workspace holds the MCP client's roots: the directories that file paths in
+// tool inputs are resolved against and confined to. A nil workspace leaves
+// paths as given.
+type workspace struct {
+	roots []string // absolute, symlinks resolved; the first resolves relative paths
+}
+
+// clientWorkspace asks the client for its roots. It is nil when the client
+// has none, does not support roots, or the call did not come from a session.
+func clientWorkspace(ctx context.Context, req *mcp.CallToolRequest) *workspace {
+	if req == nil || req.Session == nil {
+		return nil
+	}
+	result, err := req.Session.ListRoots(ctx, nil)
+	if err != nil {
+		return nil
+	}
+	var roots []string
+	for _, root := range result.Roots {
+		if path, ok := rootPath(root.URI); ok {
+			roots = append(roots, realPath(path))
+		}
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+	return &workspace{roots: roots}
+}
+
+// rootPath converts a file:// root URI to a local path, including Windows
+// drive paths such as file:///C:/work.
+func rootPath(uri string) (string, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" || parsed.Path == "" {
+		return "", false
+	}
+	path := parsed.Path
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.Clean(filepath.FromSlash(path)), true
+}
+
+// resolve joins a relative path to the first root and refuses a path that
+// ends up outside every root, symlinks followed.
+func (w *workspace) resolve(path string) (string, error) {
+	if w == nil || path == "" {
+		return path, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(w.roots[0], path)
+	}
+	path = filepath.Clean(path)
+	real := realPath(path)
+	for _, root := range w.roots {
+		if relative, err := filepath.Rel(root, real); err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is outside the workspace roots the MCP client set (%s)", path, strings.Join(w.roots, ", "))
+}
+
+// realPath resolves the symlinks of path, or of its nearest existing parent
+// when it does not exist yet (a file about to be written). A dangling symlink
+// is followed to where a write would create its target. It returns "", which
+// is within no root, for a symlink loop or one it cannot read.
+func realPath(path string) string {
+	return followSymlinks(path, maxSymlinkHops)
+}
+
+// maxSymlinkHops bounds the dangling symlinks followed, against loops.
+const maxSymlinkHops = 40
+
+func followSymlinks(path string, hops int) string {
+	missing := ""
+	for current := path; ; current = filepath.Dir(current) {
+		if resolved, err := filepath.EvalSymlinks(current); err == nil {
+			return filepath.Join(resolved, missing)
+		}
+		if info, err := os.Lstat(current); err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				return path
+			}
+			target, err := os.Readlink(current)
+			if err != nil || hops == 0 {
+				return ""
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(current), target)
+			}
+			return followSymlinks(filepath.Join(target, missing), hops-1)
+		}
+		if filepath.Dir(current) == current {
+			return path
+		}
+		missing = filepath.Join(filepath.Base(current), missing)
+	}
+}
+
+// resolveInputPaths resolves the local file paths of an http_request input
+// against the client's roots: saveTo, files and a caCert file.
+func resolveInputPaths(ctx context.Context, req *mcp.CallToolRequest, input *HttpRequestInput) error {
+	if input.SaveTo == "" && len(input.Files) == 0 && (input.CACert == "" || strings.Contains(input.CACert, "-----BEGIN")) {
+		return nil
+	}
+	roots := clientWorkspace(ctx, req)
+	var err error
+	if input.SaveTo, err = roots.resolve(input.SaveTo); err != nil {
+		return fmt.Errorf("saveTo: %w", err)
+	}
+	if !strings.Contains(input.CACert, "-----BEGIN") {
+		if input.CACert, err = roots.resolve(input.CACert); err != nil {
+			return fmt.Errorf("caCert: %w", err)
+		}
+	}
+	if len(input.Files) > 0 {
+		files := make(map[string]string, len(input.Files))
+		for field, path := range input.Files {
+			if files[field], err = roots.resolve(path); err != nil {
+				return fmt.Errorf("files.%s: %w", field, err)
+			}
+		}
+		input.Files = files
+	}
+	return nil
+}
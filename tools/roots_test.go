@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func Test_workspace_Resolve(t *testing.T) {
+	root := realPath(t.TempDir())
+	outside := realPath(t.TempDir())
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "new.txt"), filepath.Join(root, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("loop", filepath.Join(root, "loop")); err != nil {
+		t.Fatal(err)
+	}
+	roots := &workspace{roots: []string{root}}
+
+	for path, want := range map[string]string{
+		"out/body.json":                     filepath.Join(root, "out", "body.json"),
+		filepath.Join(root, "a.txt"):        filepath.Join(root, "a.txt"),
+		filepath.Join(root, "x", "..", "b"): filepath.Join(root, "b"),
+	} {
+		if got, err := roots.resolve(path); err != nil || got != want {
+			t.Errorf("resolve(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"../secret", filepath.Join(outside, "a.txt"), "escape/new.txt", "dangling", "loop"} {
+		if _, err := roots.resolve(path); err == nil || !strings.Contains(err.Error(), "outside the workspace roots") {
+			t.Errorf("resolve(%q) = %v, want an outside-roots error", path, err)
+		}
+	}
+	if got, err := (*workspace)(nil).resolve("../anywhere"); err != nil || got != "../anywhere" {
+		t.Errorf("nil workspace resolve = %q, %v; want the path unchanged", got, err)
+	}
+}
+
+func Test_rootPath(t *testing.T) {
+	if path, ok := rootPath("file:///home/dev/project"); !ok || path != filepath.FromSlash("/home/dev/project") {
+		t.Errorf("rootPath = %q, %t", path, ok)
+	}
+	if path, ok := rootPath("file:///C:/work"); !ok || path != filepath.Clean(filepath.FromSlash("C:/work")) {
+		t.Errorf("windows rootPath = %q, %t", path, ok)
+	}
+	if _, ok := rootPath("https://example.com/"); ok {
+		t.Error("expected a non-file root to be skipped")
+	}
+}
+
+func Test_HttpRequestHandler_SaveToWithinRoots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	root := realPath(t.TempDir())
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "http_request"}, makeHandler(newTestClient(""), Settings{}, NewHistory(10), NewStats(), NewVariables()))
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	mcpClient.AddRoots(&mcp.Root{URI: "file://" + filepath.ToSlash(root)})
+	session, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	call := func(saveTo string) *mcp.CallToolResult {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "http_request", Arguments: map[string]any{"method": "GET", "url": server.URL, "saveTo": saveTo}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	if result := call("body.txt"); result.IsError {
+		t.Fatalf("unexpected error: %s", extractText(result))
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "body.txt")); err != nil || string(data) != "payload" {
+		t.Errorf("expected the body saved in the root, got %q, %v", data, err)
+	}
+	result := call("../body.txt")
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "outside the workspace roots") {
+		t.Errorf("expected a path outside the roots to be refused, got: %s", text)
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// savedBodiesKept bounds the summarize temp files on disk; keeping another
+// removes the oldest.
+const savedBodiesKept = 8
+
+// savedBodyURITemplate serves the raw bodies summarize saved, by history ID.
+const savedBodyURITemplate = "history://{id}/body"
+
+func savedBodyURI(id int) string {
+	return fmt.Sprintf("history://%d/body", id)
+}
+
+// savedBodies keeps the temp files of the last savedBodiesKept oversized
+// summarize responses, so their resource links can be read.
+type savedBodies struct {
+	mu     sync.Mutex
+	bodies []savedBody // oldest first
+}
+
+type savedBody struct {
+	id       int // of the history entry
+	path     string
+	mimeType string
+}
+
+func (s *savedBodies) add(body savedBody) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bodies = append(s.bodies, body)
+	if len(s.bodies) > savedBodiesKept {
+		for _, dropped := range s.bodies[:len(s.bodies)-savedBodiesKept] {
+			os.Remove(dropped.path)
+		}
+		s.bodies = s.bodies[len(s.bodies)-savedBodiesKept:]
+	}
+}
+
+func (s *savedBodies) find(id int) (savedBody, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, body := range s.bodies {
+		if body.id == id {
+			return body, true
+		}
+	}
+	return savedBody{}, false
+}
+
+// read serves history://{id}/body: text bodies as text, others as a blob.
+func (s *savedBodies) read(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	rawID, ok := strings.CutPrefix(uri, "history://")
+	if rawID, ok = strings.CutSuffix(rawID, "/body"); !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	body, ok := s.find(id)
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	data, err := os.ReadFile(body.path)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if isTextContent(body.mimeType, data) {
+		return textResource(uri, body.mimeType, string(data)), nil
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: body.mimeType, Blob: data}}}, nil
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLength is how much of a body is inspected to classify it.
+const sniffLength = 512
+
+// maxControlRatio is the share of control bytes above which valid UTF-8 is
+// still treated as binary.
+const maxControlRatio = 0.1
+
+type sniffResult int
+
+const (
+	sniffUnsure sniffResult = iota
+	sniffText
+	sniffBinary
+)
+
+var textContentTypes = map[string]bool{
+	"application/json":                  true,
+	"application/xml":                   true,
+	"application/javascript":            true,
+	"application/x-www-form-urlencoded": true,
+	"application/x-ndjson":              true,
+	"application/yaml":                  true,
+	"application/x-yaml":                true,
+	"application/toml":                  true,
+	"application/graphql":               true,
+	"application/sql":                   true,
+	"image/svg+xml":                     true,
+}
+
+// isTextContent reports whether a body can be shown as text. Content-Type is
+// often wrong, so the bytes decide: a file signature (PNG, PDF, gzip, ...) or
+// NUL bytes mean binary even under text/plain, and readable UTF-8 is text
+// even under application/octet-stream. Only bodies that are neither, such as
+// Latin-1 text, go by the declared type.
+func isTextContent(contentType string, body []byte) bool {
+	switch sniffBody(body) {
+	case sniffText:
+		return true
+	case sniffBinary:
+		return false
+	}
+	return declaresText(contentType)
+}
+
+func declaresText(contentType string) bool {
+	mediaType := mediaTypeOf(contentType)
+	return strings.HasPrefix(mediaType, "text/") || textContentTypes[mediaType] ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// sniffBody classifies the first sniffLength bytes of body.
+func sniffBody(body []byte) sniffResult {
+	sample := body[:min(len(body), sniffLength)]
+	if len(sample) == 0 {
+		return sniffText
+	}
+	if hasBinarySignature(sample) || bytes.IndexByte(sample, 0) >= 0 {
+		return sniffBinary
+	}
+	if !validUTF8Prefix(sample, len(body) > len(sample)) {
+		return sniffUnsure
+	}
+	controls := 0
+	for _, b := range sample {
+		if (b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\v' && b != 0x1b) || b == 0x7f {
+			controls++
+		}
+	}
+	if float64(controls) > maxControlRatio*float64(len(sample)) {
+		return sniffBinary
+	}
+	return sniffText
+}
+
+// hasBinarySignature reports whether sample starts like a known binary format,
+// per the WHATWG sniffing rules net/http implements.
+func hasBinarySignature(sample []byte) bool {
+	detected := mediaTypeOf(http.DetectContentType(sample))
+	return detected != "application/octet-stream" && !strings.HasPrefix(detected, "text/")
+}
+
+// validUTF8Prefix reports whether sample is valid UTF-8. When cut, the sample
+// may end in the middle of a multi-byte rune, so up to 3 bytes are trimmed.
+func validUTF8Prefix(sample []byte, cut bool) bool {
+	if utf8.Valid(sample) {
+		return true
+	}
+	for i := 1; cut && i <= 3 && i < len(sample); i++ {
+		if utf8.Valid(sample[:len(sample)-i]) {
+			return true
+		}
+	}
+	return false
+}
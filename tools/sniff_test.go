@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_IsTextContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"empty", "image/png", "", true},
+		{"JSON labeled binary", "application/octet-stream", `{"ok":true}`, true},
+		{"UTF-8 without a type", "", "héllo wörld", true},
+		{"PNG labeled text", "text/plain", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", false},
+		{"gzip labeled JSON", "application/json", "\x1f\x8b\x08\x00\x00\x00\x00\x00", false},
+		{"PDF labeled text", "text/plain", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n", false},
+		{"NUL bytes labeled text", "text/plain", "abc\x00def", false},
+		{"control bytes", "text/plain", "\x01\x02\x03\x04abc", false},
+		{"ANSI colors", "text/plain", "\x1b[32mok\x1b[0m\n", true},
+		{"Latin-1 labeled text", "text/plain; charset=iso-8859-1", "caf\xe9", true},
+		{"Latin-1 labeled binary", "application/octet-stream", "caf\xe9", false},
+		{"rune cut at the sample end", "application/octet-stream", strings.Repeat("a", sniffLength-1) + "é", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTextContent(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("isTextContent(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FormatResponse_BinaryLabeledText(t *testing.T) {
+	resp := &client.Response{
+		StatusCode:  200,
+		StatusText:  "OK",
+		ContentType: "text/plain",
+		Body:        []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+	}
+
+	result := FormatResponse(resp, FormatOptions{})
+
+	if !strings.Contains(result, "[binary: text/plain, 16 bytes") {
+		t.Errorf("expected binary summary, got: %q", result)
+	}
+}
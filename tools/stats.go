@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// latencySamples bounds the per-host latencies kept for percentiles; older samples are dropped.
+const latencySamples = 1000
+
+type StatsInput struct {
+	Host string `json:"host,omitempty" jsonschema:"Only report this host (e.g. api.example.com); default: every host plus a total"`
+}
+
+const statsDescription = "Aggregate metrics for the http_request calls of this session, per host: request count, failures, 4xx/5xx and 429 (rate limited) counts, error rate, p50/p95 latency, bytes sent/received and how often connections were reused. " +
+	"Use during long workflows to check API health and rate-limit posture."
+
+// Stats accumulates per-host request metrics for the session.
+type Stats struct {
+	mu      sync.Mutex
+	started time.Time
+	hosts   map[string]*hostStats
+}
+
+type hostStats struct {
+	requests      int
+	failed        int // no response: connection errors, timeouts, policy refusals in the client
+	clientErrors  int
+	serverErrors  int
+	rateLimited   int
+	bytesSent     int64
+	bytesReceived int64
+	connections   int // attempts that reached a connection
+	reused        int // of them, on a kept-alive connection
+	undrained     int // of them, closed with part of the body unread, which drops the connection
+	latencies     []time.Duration
+	rateLimit     *observedRateLimit // from the latest response with rate-limit headers; nil before one
+}
+
+func NewStats() *Stats {
+	return &Stats{started: time.Now(), hosts: make(map[string]*hostStats)}
+}
+
+// Record adds one request outcome; resp is nil when the request failed without a response.
+// A response shared with an identical request was counted when it was sent.
+func (s *Stats) Record(requestURL string, bytesSent int64, resp *client.Response, duration time.Duration) {
+	if resp != nil && resp.Coalesced {
+		return
+	}
+	host := requestURL
+	if parsed, err := url.Parse(requestURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.hosts[host]
+	if stats == nil {
+		stats = &hostStats{}
+		s.hosts[host] = stats
+	}
+	stats.requests++
+	stats.bytesSent += bytesSent
+	stats.latencies = append(stats.latencies, duration)
+	if len(stats.latencies) > latencySamples {
+		stats.latencies = stats.latencies[len(stats.latencies)-latencySamples:]
+	}
+	if resp == nil {
+		stats.failed++
+		return
+	}
+	stats.bytesReceived += receivedBytes(resp)
+	now := time.Now()
+	if limit, ok := client.ParseRateLimit(resp.Headers, now); ok {
+		stats.rateLimit = &observedRateLimit{RateLimit: limit, seen: now}
+	}
+	for _, connection := range resp.Connections {
+		if connection.Connected {
+			stats.connections++
+			stats.reused += boolCount(connection.Reused)
+			stats.undrained += boolCount(!connection.Drained)
+		}
+	}
+	switch {
+	case resp.StatusCode == 429:
+		stats.rateLimited++
+		stats.clientErrors++
+	case resp.StatusCode >= 500:
+		stats.serverErrors++
+	case resp.StatusCode >= 400:
+		stats.clientErrors++
+	}
+}
+
+// Report renders the metrics of one host, or of every host plus a total when host is empty.
+func (s *Stats) Report(host string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := fmt.Sprintf("Session stats since %s (%s):", s.started.Format(time.TimeOnly), time.Since(s.started).Round(time.Second))
+	if host != "" {
+		stats := s.hosts[host]
+		if stats == nil {
+			return fmt.Sprintf("%s\nno requests to %s yet", header, host)
+		}
+		return fmt.Sprintf("%s\n%s", header, stats.describe(host))
+	}
+	if len(s.hosts) == 0 {
+		return header + "\nno requests yet"
+	}
+
+	names := make([]string, 0, len(s.hosts))
+	total := &hostStats{}
+	for name, stats := range s.hosts {
+		names = append(names, name)
+		total.add(stats)
+	}
+	sort.Strings(names)
+
+	lines := []string{header}
+	for _, name := range names {
+		lines = append(lines, s.hosts[name].describe(name))
+	}
+	if len(names) > 1 {
+		lines = append(lines, total.describe("total"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *hostStats) add(other *hostStats) {
+	h.requests += other.requests
+	h.failed += other.failed
+	h.clientErrors += other.clientErrors
+	h.serverErrors += other.serverErrors
+	h.rateLimited += other.rateLimited
+	h.bytesSent += other.bytesSent
+	h.bytesReceived += other.bytesReceived
+	h.connections += other.connections
+	h.reused += other.reused
+	h.undrained += other.undrained
+	h.latencies = append(h.latencies, other.latencies...)
+}
+
+func (h *hostStats) describe(name string) string {
+	errors := h.failed + h.clientErrors + h.serverErrors
+	var connections string
+	if h.connections > 0 {
+		connections = fmt.Sprintf(", %d/%d connections reused, %d bodies not drained", h.reused, h.connections, h.undrained)
+	}
+	return fmt.Sprintf("%s: %d requests, %d failed, %d 4xx (%d × 429), %d 5xx, error rate %.1f%%, p50 %s, p95 %s, sent %d bytes, received %d bytes%s",
+		name, h.requests, h.failed, h.clientErrors, h.rateLimited, h.serverErrors,
+		100*float64(errors)/float64(h.requests),
+		percentile(h.latencies, 50), percentile(h.latencies, 95),
+		h.bytesSent, h.bytesReceived, connections)
+}
+
+func boolCount(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// percentile returns the nearest-rank percentile of latencies.
+func percentile(latencies []time.Duration, p int) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1].Round(time.Millisecond)
+}
+
+// receivedBytes is the full body size, including parts cut off by truncation or written to a file.
+func receivedBytes(resp *client.Response) int64 {
+	switch {
+	case resp.SavedPath != "":
+		return resp.SavedSize
+	case resp.Truncated && resp.OriginalSize > 0:
+		return resp.OriginalSize
+	}
+	return int64(len(resp.Body))
+}
+
+func makeStatsHandler(stats *Stats) func(context.Context, *mcp.CallToolRequest, StatsInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input StatsInput) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: stats.Report(input.Host)}},
+		}, nil, nil
+	}
+}
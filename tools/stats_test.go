@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_Percentile(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    int
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(latencies, tt.p); got != tt.want {
+			t.Errorf("percentile(%d) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 for no samples, got %s", got)
+	}
+}
+
+func Test_Stats_Report(t *testing.T) {
+	stats := NewStats()
+	stats.Record("https://api.example.com/a", 10, &client.Response{StatusCode: 200, Body: []byte("12345")}, 100*time.Millisecond)
+	stats.Record("https://api.example.com/b", 0, &client.Response{StatusCode: 429}, 200*time.Millisecond)
+	stats.Record("https://api.example.com/c", 0, &client.Response{StatusCode: 503}, 300*time.Millisecond)
+	stats.Record("https://other.example.com/", 0, nil, time.Second)
+
+	report := stats.Report("")
+	for _, want := range []string{
+		"api.example.com: 3 requests, 0 failed, 1 4xx (1 × 429), 1 5xx, error rate 66.7%, p50 200ms, p95 300ms, sent 10 bytes, received 5 bytes",
+		"other.example.com: 1 requests, 1 failed",
+		"total: 4 requests, 1 failed",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected %q in report, got:\n%s", want, report)
+		}
+	}
+
+	if hostReport := stats.Report("other.example.com"); strings.Contains(hostReport, "api.example.com") {
+		t.Errorf("expected host filter, got:\n%s", hostReport)
+	}
+	if empty := NewStats().Report(""); !strings.Contains(empty, "no requests yet") {
+		t.Errorf("expected empty report, got: %s", empty)
+	}
+}
+
+func Test_StatsHandler_CountsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	stats := NewStats()
+	request := makeHandler(newTestClient(server.URL), Settings{}, NewHistory(10), stats, NewVariables())
+	request(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL})
+
+	result, _, _ := makeStatsHandler(stats)(context.Background(), nil, StatsInput{})
+	if text := extractText(result); !strings.Contains(text, "1 requests, 0 failed, 0 4xx (0 × 429), 1 5xx, error rate 100.0%") {
+		t.Errorf("expected the request counted, got:\n%s", text)
+	}
+}
+
+func Test_Stats_Report_ConnectionReuse(t *testing.T) {
+	stats := NewStats()
+	stats.Record("https://api.example.com/a", 0, &client.Response{StatusCode: 200, Connections: []client.ConnectionInfo{
+		{Connected: true, Drained: true}, {Connected: true, Reused: true},
+	}}, time.Millisecond)
+	stats.Record("https://api.example.com/b", 0, &client.Response{StatusCode: 200, Cached: true}, time.Millisecond)
+
+	if report := stats.Report(""); !strings.Contains(report, "1/2 connections reused, 1 bodies not drained") {
+		t.Errorf("expected connection reuse in report, got:\n%s", report)
+	}
+}
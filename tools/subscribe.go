@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Subscription bounds: a stream is sampled, not consumed for good.
+const (
+	defaultSubscribeEvents   = 10
+	maxSubscribeEvents       = 100
+	defaultSubscribeDuration = 30 * time.Second
+	maxSubscribeDuration     = 5 * time.Minute
+	maxSubscribeEventBytes   = 4096
+)
+
+type SubscribeInput struct {
+	URL         string                  `json:"url" jsonschema:"Server-Sent Events endpoint or WebSocket URL (ws:// or wss://); full URL or relative path (if base_url configured)"`
+	Protocol    string                  `json:"protocol,omitempty" jsonschema:"sse or websocket (default: websocket for ws:// and wss:// URLs, sse otherwise)"`
+	Headers     map[string]StringValues `json:"headers,omitempty" jsonschema:"Handshake request headers"`
+	QueryParams map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters"`
+	Send        []string                `json:"send,omitempty" jsonschema:"WebSocket text messages to send once connected, e.g. a subscribe request"`
+	MaxEvents   int                     `json:"maxEvents,omitempty" jsonschema:"Stop after this many events (default 10, at most 100)"`
+	Duration    string                  `json:"duration,omitempty" jsonschema:"How long to listen, e.g. 1m (default 30s, at most 5m)"`
+	JSONFilter  string                  `json:"jsonFilter,omitempty" jsonschema:"GJSON path to extract from each JSON event, e.g. data.status"`
+	Until       string                  `json:"until,omitempty" jsonschema:"Stop early once an event (its jsonFilter value with jsonFilter) contains this text"`
+}
+
+const subscribeDescription = "Subscribe to a Server-Sent Events stream or a WebSocket and return the events received within a bounded time (each with its offset, type and data; large events truncated). " +
+	"Use to watch a live feed, check that an action emits the expected event, or sample an event-driven API; until stops at the first matching event."
+
+// SubscribeEvent is one received event.
+type SubscribeEvent struct {
+	OffsetMs  int64  `json:"offsetMs"` // since the stream opened
+	Type      string `json:"type"`
+	ID        string `json:"id,omitempty"`
+	Data      string `json:"data"` // the jsonFilter value with jsonFilter
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// SubscribeOutput is the structured output of subscribe.
+type SubscribeOutput struct {
+	Status    int              `json:"status"`
+	Events    []SubscribeEvent `json:"events"`
+	ElapsedMs int64            `json:"elapsedMs"`
+	Stopped   string           `json:"stopped"` // why the subscription ended
+}
+
+func makeSubscribeHandler(httpClient *client.Client, settings Settings) func(context.Context, *mcp.CallToolRequest, SubscribeInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input SubscribeInput) (*mcp.CallToolResult, any, error) {
+		if input.URL == "" {
+			return errorResult("url is required"), nil, nil
+		}
+		protocol, err := streamProtocol(input)
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+		maxEvents, duration, err := subscribeLimits(input)
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+		if len(input.Send) > 0 && protocol != client.StreamWebSocket {
+			return errorResult("send applies only to WebSocket subscriptions"), nil, nil
+		}
+		if policyError := settings.Methods.check("GET"); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if len(input.Send) > 0 && settings.Methods.ReadOnly {
+			return errorResult("sending WebSocket messages is not allowed: the server runs in read-only mode (--read-only)"), nil, nil
+		}
+		if limitError := settings.Limits.check(HttpRequestInput{Method: "GET", Headers: input.Headers, Body: strings.Join(input.Send, "")}); limitError != "" {
+			return errorResult(limitError), nil, nil
+		}
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		request := client.RequestParams{
+			Method:      "GET",
+			URL:         client.HandshakeURL(input.URL),
+			Headers:     headers,
+			QueryParams: queryParams(HttpRequestInput{QueryParams: input.QueryParams}),
+		}
+		requestURL, err := httpClient.ResolveURL(request)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Invalid URL: %s", err)), nil, nil
+		}
+		if policyError := settings.URLs.check("GET", requestURL); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if settings.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, request)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Dry run failed: %s", err)), nil, nil
+			}
+			text := headerNote + formatDryRun(rendered, HttpRequestInput{})
+			text += fmt.Sprintf("\n\n[%s subscription not opened; %d messages would be sent]", protocol, len(input.Send))
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		}
+
+		params := client.StreamParams{
+			Protocol:     protocol,
+			URL:          request.URL,
+			Headers:      headers,
+			QueryParams:  request.QueryParams,
+			Send:         input.Send,
+			MaxEvents:    maxEvents,
+			Duration:     duration,
+			MaxEventSize: maxSubscribeEventBytes,
+			Progress:     newProgressReporter(ctx, req),
+		}
+		if input.Until != "" {
+			params.Stop = func(event client.StreamEvent) bool {
+				return strings.Contains(eventValue(event.Data, input.JSONFilter), input.Until)
+			}
+		}
+		result, err := httpClient.Subscribe(ctx, params)
+		if err != nil {
+			return errorResult(fmt.Sprintf("[%s] Subscription failed: %s", classifyError(err), err)), nil, nil
+		}
+		output := subscribeOutput(result, input.JSONFilter)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatSubscription(protocol, result, output)}},
+		}, output, nil
+	}
+}
+
+// streamProtocol picks the protocol from the input, or from the URL scheme.
+func streamProtocol(input SubscribeInput) (string, error) {
+	switch protocol := strings.ToLower(input.Protocol); protocol {
+	case client.StreamSSE, client.StreamWebSocket:
+		return protocol, nil
+	case "":
+		if client.HandshakeURL(input.URL) != input.URL {
+			return client.StreamWebSocket, nil
+		}
+		return client.StreamSSE, nil
+	}
+	return "", fmt.Errorf("unsupported protocol %q (expected %s or %s)", input.Protocol, client.StreamSSE, client.StreamWebSocket)
+}
+
+func subscribeLimits(input SubscribeInput) (int, time.Duration, error) {
+	maxEvents, duration := defaultSubscribeEvents, defaultSubscribeDuration
+	if input.MaxEvents != 0 {
+		maxEvents = input.MaxEvents
+	}
+	if input.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(input.Duration); err != nil {
+			return 0, 0, fmt.Errorf("invalid duration: %w", err)
+		}
+	}
+	if maxEvents < 1 || maxEvents > maxSubscribeEvents || duration <= 0 || duration > maxSubscribeDuration {
+		return 0, 0, fmt.Errorf("maxEvents must be between 1 and %d and duration between 0 and %s", maxSubscribeEvents, maxSubscribeDuration)
+	}
+	return maxEvents, duration, nil
+}
+
+// eventValue is the jsonFilter value of an event's data as JSON, or the data
+// itself without a filter or when the data is not JSON.
+func eventValue(data, jsonFilter string) string {
+	if jsonFilter == "" || !gjson.Valid(data) {
+		return data
+	}
+	return gjson.Get(data, jsonFilter).Raw
+}
+
+func subscribeOutput(result *client.StreamResult, jsonFilter string) SubscribeOutput {
+	output := SubscribeOutput{Status: result.StatusCode, Events: []SubscribeEvent{}, ElapsedMs: result.Duration.Milliseconds(), Stopped: result.Stopped}
+	for _, event := range result.Events {
+		output.Events = append(output.Events, SubscribeEvent{
+			OffsetMs:  event.Offset.Milliseconds(),
+			Type:      event.Type,
+			ID:        event.ID,
+			Data:      eventValue(event.Data, jsonFilter),
+			Size:      event.Size,
+			Truncated: event.Truncated,
+		})
+	}
+	return output
+}
+
+// formatSubscription lists the events one per line under a summary, or shows
+// the response of a server that did not open the stream.
+func formatSubscription(protocol string, result *client.StreamResult, output SubscribeOutput) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[%s %s: %d, %d events in %s — stopped: %s]", protocol, client.RedactURL(result.URL), result.StatusCode,
+		len(output.Events), result.Duration.Round(100*time.Millisecond), result.Stopped)
+	if len(result.Body) > 0 {
+		builder.WriteString("\n\n")
+		builder.Write(result.Body)
+	}
+	for _, event := range output.Events {
+		fmt.Fprintf(&builder, "\n+%s %s", (time.Duration(event.OffsetMs) * time.Millisecond).Round(10*time.Millisecond), event.Type)
+		if event.ID != "" {
+			fmt.Fprintf(&builder, " id=%s", event.ID)
+		}
+		fmt.Fprintf(&builder, ": %s", event.Data)
+		if event.Truncated {
+			fmt.Fprintf(&builder, " [truncated: %d bytes]", event.Size)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_SubscribeHandler_StopsAtUntil(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, state := range []string{"new", "paid", "shipped"} {
+			fmt.Fprintf(w, "id: %d\nevent: order\ndata: {\"state\":%q}\n\n", i+1, state)
+		}
+	}))
+	defer server.Close()
+
+	handler := makeSubscribeHandler(newTestClient(""), Settings{})
+	result, output, _ := handler(context.Background(), nil, SubscribeInput{URL: server.URL + "/events?token=secret", JSONFilter: "state", Until: "paid"})
+
+	text := extractText(result)
+	if result.IsError || !strings.HasPrefix(text, "[sse "+server.URL+"/events?token=***: 200, 2 events in ") || !strings.Contains(text, "stopped: stop condition met]") {
+		t.Fatalf("unexpected output: %s", text)
+	}
+	if !strings.Contains(text, `order id=1: "new"`) || !strings.Contains(text, `order id=2: "paid"`) {
+		t.Errorf("expected the filtered events listed, got: %s", text)
+	}
+	if events := output.(SubscribeOutput).Events; len(events) != 2 || events[1].Data != `"paid"` {
+		t.Errorf("unexpected structured events %+v", events)
+	}
+}
+
+func Test_SubscribeHandler_AppliesPolicies(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+	wsURL := "ws://" + strings.TrimPrefix(server.URL, "http://") + "/feed"
+
+	tests := []struct {
+		name     string
+		settings Settings
+		input    SubscribeInput
+		want     string
+	}{
+		{"dry run", Settings{DryRun: true}, SubscribeInput{URL: wsURL, Send: []string{"hi"}}, "[websocket subscription not opened; 1 messages would be sent]"},
+		{"url policy", Settings{URLs: URLPolicy{Rules: []URLRule{{Method: "*", Pattern: server.URL + "/**", Line: 1}}}}, SubscribeInput{URL: wsURL}, "denied by policy rule on line 1"},
+		{"read-only send", Settings{Methods: MethodPolicy{ReadOnly: true}}, SubscribeInput{URL: wsURL, Send: []string{"hi"}}, "read-only mode"},
+		{"send over sse", Settings{}, SubscribeInput{URL: server.URL, Send: []string{"hi"}}, "only to WebSocket"},
+		{"too many events", Settings{}, SubscribeInput{URL: server.URL, MaxEvents: 1000}, "maxEvents must be between 1 and 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, _ := makeSubscribeHandler(newTestClient(""), tt.settings)(context.Background(), nil, tt.input)
+			if text := extractText(result); !strings.Contains(text, tt.want) {
+				t.Errorf("expected %q, got: %s", tt.want, text)
+			}
+		})
+	}
+	if hits != 0 {
+		t.Errorf("expected no handshake sent, server saw %d", hits)
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Sampling limits for summarize: how much of the body the client's model
+// reads, and how long its summary may be.
+const (
+	summarySourceBytes = 512 << 10
+	summaryMaxTokens   = 1024
+)
+
+const summarySystemPrompt = "You summarize HTTP API responses for an agent that cannot read them whole. " +
+	"Describe the body's structure, the number of items, the fields that matter and notable or unusual values, " +
+	"quoting IDs and errors exactly. Answer in plain text, in at most a few short paragraphs."
+
+// summaryFile is where a summarize request streams its body, so the whole of
+// it can be summarized and linked however large it is.
+type summaryFile struct {
+	path       string
+	limit      int64 // bodies up to this size are returned inline as usual
+	oversized  bool
+	summarized bool
+}
+
+// newSummaryFile prepares a temp file for a summarize request and points
+// params at it; nil without summarize, when the agent saves the body itself
+// or when the file cannot be created, which leaves the request as it was.
+func newSummaryFile(params *client.RequestParams, settings Settings, summarize bool) *summaryFile {
+	if !summarize || params.SaveTo != "" {
+		return nil
+	}
+	file, err := os.CreateTemp("", "rest-api-mcp-response-*")
+	if err != nil {
+		return nil
+	}
+	file.Close()
+	limit := params.MaxResponseSize
+	if limit <= 0 {
+		limit = settings.MaxResponseSize
+	}
+	if limit <= 0 {
+		limit = client.DefaultMaxResponseSize
+	}
+	params.SaveTo = file.Name()
+	return &summaryFile{path: file.Name(), limit: limit}
+}
+
+// settle turns resp back into an inline response unless its body is over the
+// limit. A failed request or an error response never wrote the file; a small
+// body is read back and the file removed.
+func (f *summaryFile) settle(resp *client.Response) {
+	if f == nil {
+		return
+	}
+	if resp == nil || resp.SavedPath != f.path {
+		os.Remove(f.path)
+		return
+	}
+	if resp.SavedSize > f.limit {
+		f.oversized = true
+		return
+	}
+	body, err := os.ReadFile(f.path)
+	if err != nil {
+		f.oversized = true
+		return
+	}
+	os.Remove(f.path)
+	resp.Body = body
+	resp.SavedPath, resp.SavedSize, resp.SavedSHA256 = "", 0, ""
+}
+
+// finish summarizes an oversized body, returning the summary. Without a
+// summary the response shows the start of the body, as a spilled one does,
+// and the note says why.
+func (f *summaryFile) finish(ctx context.Context, req *mcp.CallToolRequest, resp *client.Response) string {
+	if f == nil || !f.oversized {
+		return ""
+	}
+	summary, err := f.summarize(ctx, req, resp)
+	if err != nil {
+		f.spill(resp)
+		return fmt.Sprintf("\n[not summarized: %s]", err)
+	}
+	f.summarized = true
+	return "\n\n" + summary
+}
+
+// keep hands an oversized body's file to the history entry id, which serves
+// it and removes it once newer ones push it out, and returns the link to a
+// summarized one.
+func (f *summaryFile) keep(history *History, id int, resp *client.Response) *mcp.ResourceLink {
+	if f == nil || !f.oversized {
+		return nil
+	}
+	history.bodies.add(savedBody{id: id, path: f.path, mimeType: resp.ContentType})
+	if !f.summarized {
+		return nil
+	}
+	size := resp.SavedSize
+	return &mcp.ResourceLink{
+		URI:         savedBodyURI(id),
+		Name:        fmt.Sprintf("body-%d", id),
+		Title:       "Raw response body",
+		Description: fmt.Sprintf("The full %s body the summary was made from (sha256 %s)", displayContentType(resp.ContentType), resp.SavedSHA256),
+		MIMEType:    resp.ContentType,
+		Size:        &size,
+	}
+}
+
+func (f *summaryFile) spill(resp *client.Response) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	resp.Body, _ = io.ReadAll(io.LimitReader(file, f.limit))
+	resp.Truncated = true
+	resp.OriginalSize = resp.SavedSize
+	resp.Spilled = true
+}
+
+// summarize asks the MCP client's model, through sampling, for a summary of
+// the body in the file.
+func (f *summaryFile) summarize(ctx context.Context, req *mcp.CallToolRequest, resp *client.Response) (string, error) {
+	if req == nil || req.Session == nil {
+		return "", errors.New("the call did not come from an MCP session")
+	}
+	if params := req.Session.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.Sampling == nil {
+		return "", errors.New("the MCP client does not support sampling")
+	}
+	file, err := os.Open(f.path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	body, err := io.ReadAll(io.LimitReader(file, summarySourceBytes))
+	if err != nil {
+		return "", err
+	}
+	if !isTextContent(resp.ContentType, body) {
+		return "", fmt.Errorf("the body is binary (%s)", displayContentType(resp.ContentType))
+	}
+
+	prompt := fmt.Sprintf("Summarize the %s response body of %s %s (status %d, %d bytes)", displayContentType(resp.ContentType), resp.RequestMethod, client.RedactURL(resp.RequestURL), resp.StatusCode, resp.SavedSize)
+	if int64(len(body)) < resp.SavedSize {
+		prompt += fmt.Sprintf("; only its first %d bytes follow", len(body))
+	}
+	result, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+		SystemPrompt: summarySystemPrompt,
+		MaxTokens:    summaryMaxTokens,
+		Messages:     []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: prompt + ":\n\n" + string(body)}}},
+		ModelPreferences: &mcp.ModelPreferences{
+			SpeedPriority: 0.8,
+			CostPriority:  0.8,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("sampling failed: %w", err)
+	}
+	text, ok := result.Content.(*mcp.TextContent)
+	if !ok || text.Text == "" {
+		return "", errors.New("the client's model returned no text")
+	}
+	if result.Model != "" {
+		return fmt.Sprintf("[summary by %s of the %d-byte body]\n%s", result.Model, resp.SavedSize, text.Text), nil
+	}
+	return fmt.Sprintf("[summary of the %d-byte body]\n%s", resp.SavedSize, text.Text), nil
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func summarizeSession(t *testing.T, options *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir()) // where the bodies are saved
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	history := NewHistory(10)
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "http_request"}, makeHandler(newTestClient(""), Settings{}, history, NewStats(), NewVariables()))
+	(&sessionResources{}).sync(mcpServer, Settings{}, history)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, options).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func callSummarize(t *testing.T, session *mcp.ClientSession, target string) *mcp.CallToolResult {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "http_request", Arguments: map[string]any{
+		"method": "GET", "url": target, "summarize": true, "maxResponseBytes": 100,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func Test_HttpRequestHandler_SummarizeOversizedBody(t *testing.T) {
+	body := `[` + strings.Repeat(`{"id":1,"name":"widget"},`, 40) + `{"id":2}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	var sampled string
+	session := summarizeSession(t, &mcp.ClientOptions{
+		CreateMessageHandler: func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			sampled = req.Params.Messages[0].Content.(*mcp.TextContent).Text
+			return &mcp.CreateMessageResult{Model: "test-model", Role: "assistant", Content: &mcp.TextContent{Text: "41 widgets, ids 1 and 2."}}, nil
+		},
+	})
+	result := callSummarize(t, session, server.URL)
+
+	text := extractText(result)
+	if !strings.Contains(text, "[summary by test-model of the "+strconv.Itoa(len(body))+"-byte body]\n41 widgets") {
+		t.Errorf("expected the summary, got: %s", text)
+	}
+	if !strings.HasSuffix(sampled, body) {
+		t.Errorf("expected the whole body to be sampled, got: %s", sampled)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected a resource link after the text, got %d contents", len(result.Content))
+	}
+	link, ok := result.Content[1].(*mcp.ResourceLink)
+	if !ok {
+		t.Fatalf("expected a resource link, got %T", result.Content[1])
+	}
+	if link.URI != "history://1/body" {
+		t.Errorf("expected a link to the history entry's body, got %s", link.URI)
+	}
+	read, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: link.URI})
+	if err != nil {
+		t.Fatalf("reading the linked body: %v", err)
+	}
+	if saved := read.Contents[0].Text; saved != body {
+		t.Errorf("expected the linked resource to hold the body, got %q", saved)
+	}
+	if link.Size == nil || *link.Size != int64(len(body)) || link.MIMEType != "application/json" {
+		t.Errorf("unexpected link: %+v", link)
+	}
+}
+
+func Test_HttpRequestHandler_SummarizeWithoutSampling(t *testing.T) {
+	body := strings.Repeat("line of log output\n", 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	result := callSummarize(t, summarizeSession(t, nil), server.URL)
+
+	text := extractText(result)
+	if !strings.Contains(text, "[not summarized: the MCP client does not support sampling]") {
+		t.Errorf("expected the missing sampling to be reported, got: %s", text)
+	}
+	start := strings.Index(text, "the full body is saved to ")
+	if start < 0 {
+		t.Fatalf("expected a truncated preview pointing at the file, got: %s", text)
+	}
+	path := strings.Fields(text[start+len("the full body is saved to "):])[0]
+	if saved, err := os.ReadFile(path); err != nil || string(saved) != body {
+		t.Errorf("expected the file to hold the body, got %q, %v", saved, err)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("expected no resource link without a summary, got %d contents", len(result.Content))
+	}
+}
+
+func Test_HttpRequestHandler_SummarizeSmallBodyInline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	result := callSummarize(t, summarizeSession(t, nil), server.URL)
+
+	text := extractText(result)
+	if !strings.Contains(text, `{"ok":true}`) || strings.Contains(text, "saved to") || strings.Contains(text, "summar") {
+		t.Errorf("expected a small body inline as usual, got: %s", text)
+	}
+}
+
+func Test_savedBodies_RemovesOldest(t *testing.T) {
+	dir := t.TempDir()
+	var bodies savedBodies
+	for id := 1; id <= savedBodiesKept+1; id++ {
+		path := filepath.Join(dir, strconv.Itoa(id))
+		if err := os.WriteFile(path, []byte("body"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		bodies.add(savedBody{id: id, path: path, mimeType: "text/plain"})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "1")); !os.IsNotExist(err) {
+		t.Errorf("expected the oldest body removed, got %v", err)
+	}
+	if _, ok := bodies.find(1); ok {
+		t.Error("expected the oldest body forgotten")
+	}
+	if _, ok := bodies.find(2); !ok {
+		t.Error("expected the newer bodies kept")
+	}
+}
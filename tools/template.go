@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+const randomStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// renderTemplates renders the URL, header values, query values and body of a
+// template: true request as Go templates. The data is the session variables;
+// env exposes only the allowlisted environment values (--template-env).
+func renderTemplates(input *HttpRequestInput, variables, env map[string]string) error {
+	funcs := templateFuncs(variables, env)
+	render := func(field, text string) (string, error) {
+		if !strings.Contains(text, "{{") {
+			return text, nil
+		}
+		tmpl, err := template.New(field).Funcs(funcs).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("parsing %s: %w", field, err)
+		}
+		var rendered strings.Builder
+		if err := tmpl.Execute(&rendered, variables); err != nil {
+			return "", fmt.Errorf("rendering %s: %w", field, err)
+		}
+		return rendered.String(), nil
+	}
+
+	var err error
+	if input.URL, err = render("url", input.URL); err != nil {
+		return err
+	}
+	if input.Body, err = render("body", input.Body); err != nil {
+		return err
+	}
+	for name, values := range input.Headers {
+		for i, value := range values {
+			if values[i], err = render("header "+name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for name, values := range input.QueryParams {
+		for i, value := range values {
+			if values[i], err = render("query parameter "+name, value); err != nil {
+				return err
+			}
+		}
+	}
+	for i, param := range input.OrderedQueryParams {
+		if input.OrderedQueryParams[i].Value, err = render("query parameter "+param.Name, param.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func templateFuncs(variables, env map[string]string) template.FuncMap {
+	funcs := template.FuncMap{
+		"var": func(name string) (string, error) {
+			value, ok := variables[name]
+			if !ok {
+				return "", fmt.Errorf("variable %q is not set (set_variables)", name)
+			}
+			return value, nil
+		},
+		"env": func(name string) (string, error) {
+			value, ok := env[name]
+			if !ok {
+				return "", fmt.Errorf("environment variable %q is not set or not allowed (--template-env)", name)
+			}
+			return value, nil
+		},
+		"now":             func() string { return time.Now().UTC().Format(time.RFC3339) },
+		"date":            func(layout string) string { return time.Now().UTC().Format(layout) },
+		"timestamp":       func() int64 { return time.Now().Unix() },
+		"timestampMillis": func() int64 { return time.Now().UnixMilli() },
+		"uuid":            client.NewUUID,
+		"randomInt": func(low, high int) (int, error) {
+			if high < low {
+				return 0, fmt.Errorf("randomInt: %d is below %d", high, low)
+			}
+			return low + rand.IntN(high-low+1), nil
+		},
+		"randomString": func(length int) string {
+			random := make([]byte, max(length, 0))
+			for i := range random {
+				random[i] = randomStringAlphabet[rand.IntN(len(randomStringAlphabet))]
+			}
+			return string(random)
+		},
+		"json": func(value any) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+	}
+	maps.Copy(funcs, fakeFuncs(newFaker(rand.Uint64())))
+	return funcs
+}
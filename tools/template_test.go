@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_renderTemplates(t *testing.T) {
+	variables := map[string]string{"userId": "42", "name": `Ann "A"`}
+	env := map[string]string{"API_TOKEN": "s3cret"}
+	tests := []struct {
+		name    string
+		input   HttpRequestInput
+		check   func(HttpRequestInput) bool
+		wantErr string
+	}{
+		{"URL variable", HttpRequestInput{URL: "/users/{{.userId}}"}, func(in HttpRequestInput) bool { return in.URL == "/users/42" }, ""},
+		{"var function", HttpRequestInput{URL: `/users/{{var "userId"}}`}, func(in HttpRequestInput) bool { return in.URL == "/users/42" }, ""},
+		{"json quoting", HttpRequestInput{Body: `{"name": {{json .name}}}`}, func(in HttpRequestInput) bool { return in.Body == `{"name": "Ann \"A\""}` }, ""},
+		{"allowlisted env", HttpRequestInput{Headers: map[string]StringValues{"Authorization": {`Bearer {{env "API_TOKEN"}}`}}}, func(in HttpRequestInput) bool { return in.Headers["Authorization"][0] == "Bearer s3cret" }, ""},
+		{"query values", HttpRequestInput{QueryParams: map[string]StringValues{"id": {"{{.userId}}"}}, OrderedQueryParams: []QueryParam{{Name: "n", Value: "{{randomInt 5 5}}"}}}, func(in HttpRequestInput) bool {
+			return in.QueryParams["id"][0] == "42" && in.OrderedQueryParams[0].Value == "5"
+		}, ""},
+		{"uuid and random string", HttpRequestInput{Body: "{{uuid}} {{randomString 8}}"}, func(in HttpRequestInput) bool {
+			return regexp.MustCompile(`^[0-9a-f-]{36} [A-Za-z0-9]{8}$`).MatchString(in.Body)
+		}, ""},
+		{"fake data", HttpRequestInput{Body: "{{fakeName}} <{{fakeEmail}}>"}, func(in HttpRequestInput) bool {
+			return regexp.MustCompile(`^\S+ \S+ <[a-z]+\.[a-z]+\d*@example\.(com|org|net)>$`).MatchString(in.Body)
+		}, ""},
+		{"missing variable", HttpRequestInput{URL: "/users/{{.missing}}"}, nil, "missing"},
+		{"env not allowlisted", HttpRequestInput{Body: `{{env "HOME"}}`}, nil, "--template-env"},
+		{"syntax error", HttpRequestInput{Body: "{{.userId"}, nil, "parsing body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := renderTemplates(&tt.input, variables, env)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderTemplates: %v", err)
+			}
+			if !tt.check(tt.input) {
+				t.Errorf("unexpected rendering: %+v", tt.input)
+			}
+		})
+	}
+}
+
+func Test_HttpRequestHandler_TemplateUsesSessionVariables(t *testing.T) {
+	var gotPath, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+	}))
+	defer server.Close()
+
+	variables := NewVariables()
+	setVariables := makeSetVariablesHandler(variables)
+	if _, _, err := setVariables(context.Background(), nil, SetVariablesInput{Set: map[string]string{"orderId": "o-7"}}); err != nil {
+		t.Fatalf("set_variables: %v", err)
+	}
+	handler := makeHandler(newTestClient(server.URL), Settings{}, NewHistory(10), NewStats(), variables)
+
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "POST", URL: server.URL + "/orders/{{.orderId}}", Body: "{{.orderId}}", Template: true})
+	if result.IsError || gotPath != "/orders/o-7" || gotBody != "o-7" {
+		t.Errorf("server saw %s %q, result: %s", gotPath, gotBody, extractText(result))
+	}
+	handler(context.Background(), nil, HttpRequestInput{Method: "POST", URL: server.URL + "/raw", Body: "{{.orderId}}"})
+	if gotBody != "{{.orderId}}" {
+		t.Errorf("without template: true the body must be sent as is, got %q", gotBody)
+	}
+}
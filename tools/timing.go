@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// RequestOutput is the structured output of http_request, next to the text.
+type RequestOutput struct {
+	Status          int           `json:"status"`
+	Timing          RequestTiming `json:"timing"`
+	EstimatedTokens int           `json:"estimatedTokens"` // of the text output
+}
+
+// RequestTiming tells how long a call took and how many attempts it needed.
+type RequestTiming struct {
+	DurationMs      int64               `json:"durationMs"`                // the attempt that produced the response
+	TotalMs         int64               `json:"totalMs"`                   // the whole call: retries, backoff, queueing and followed links
+	QueueWaitMs     int64               `json:"queueWaitMs,omitempty"`     // waited for --max-concurrent-requests
+	PaceWaitMs      int64               `json:"paceWaitMs,omitempty"`      // paused for the host's rate limit (--pace-rate-limits)
+	BackoffMs       int64               `json:"backoffMs,omitempty"`       // waited between attempts
+	Attempts        int                 `json:"attempts"`                  // the last attempt produced the response
+	Retries         int                 `json:"retries"`                   // attempts - 1
+	RetriedStatuses []int               `json:"retriedStatuses,omitempty"` // status of each retried attempt; 0 = no response
+	Coalesced       bool                `json:"coalesced,omitempty"`       // shared an identical request's response (--coalesce-window)
+	Cached          bool                `json:"cached,omitempty"`          // served from --cache-dir or a kept paged body
+	Connections     []AttemptConnection `json:"connections,omitempty"`     // per attempt, in order
+}
+
+// AttemptConnection tells whether an attempt reused a kept-alive connection
+// and whether its response body was drained, so the connection could be
+// reused by the next call.
+type AttemptConnection struct {
+	Reused  bool `json:"reused"`
+	Drained bool `json:"drained"`
+}
+
+func newRequestTiming(resp *client.Response, total time.Duration) RequestTiming {
+	attempts := max(resp.Attempts, 1)
+	return RequestTiming{
+		DurationMs:      resp.Duration.Milliseconds(),
+		TotalMs:         total.Milliseconds(),
+		QueueWaitMs:     resp.QueueWait.Milliseconds(),
+		PaceWaitMs:      resp.PaceWait.Milliseconds(),
+		BackoffMs:       resp.Backoff.Milliseconds(),
+		Attempts:        attempts,
+		Retries:         attempts - 1,
+		RetriedStatuses: resp.RetriedStatuses,
+		Coalesced:       resp.Coalesced,
+		Cached:          resp.Cached,
+		Connections:     attemptConnections(resp.Connections),
+	}
+}
+
+// attemptConnections is nil unless an attempt reached a connection, so
+// cached and replayed responses report none.
+func attemptConnections(connections []client.ConnectionInfo) []AttemptConnection {
+	if !slices.ContainsFunc(connections, func(connection client.ConnectionInfo) bool { return connection.Connected }) {
+		return nil
+	}
+	result := make([]AttemptConnection, len(connections))
+	for i, connection := range connections {
+		result[i] = AttemptConnection{Reused: connection.Reused, Drained: connection.Drained}
+	}
+	return result
+}
+
+// includeTiming is the agent's includeTiming, else the server default.
+func includeTiming(settings Settings, input HttpRequestInput) bool {
+	if input.IncludeTiming != nil {
+		return *input.IncludeTiming
+	}
+	return settings.IncludeTiming
+}
+
+// summary is the lines under the status line: the duration when showDuration
+// is set, and the attempts whenever the call was retried, e.g.
+// "[succeeded on attempt 3 after 2 retries (503, 503), 4.2s total]".
+func (t RequestTiming) summary(status int, showDuration bool) string {
+	var lines []string
+	if showDuration {
+		took := fmt.Sprintf("[took %s", time.Duration(t.DurationMs)*time.Millisecond)
+		if len(t.Connections) > 0 && t.Connections[len(t.Connections)-1].Reused {
+			took += " on a reused connection"
+		} else if len(t.Connections) > 0 {
+			took += " on a new connection"
+		}
+		lines = append(lines, took+"]")
+	}
+	if t.Retries > 0 {
+		retried := make([]string, len(t.RetriedStatuses))
+		for i, code := range t.RetriedStatuses {
+			retried[i] = strconv.Itoa(code)
+			if code == 0 {
+				retried[i] = "no response"
+			}
+		}
+		outcome := "succeeded"
+		if status >= 500 {
+			outcome = "still failing"
+		}
+		retries := "retries"
+		if t.Retries == 1 {
+			retries = "retry"
+		}
+		total := (time.Duration(t.TotalMs) * time.Millisecond).Round(100 * time.Millisecond)
+		lines = append(lines, fmt.Sprintf("[%s on attempt %d after %d %s (%s), %s total]", outcome, t.Attempts, t.Retries, retries, strings.Join(retried, ", "), total))
+	}
+	return strings.Join(lines, "\n")
+}
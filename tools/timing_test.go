@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// flakyServer fails the first request with 503 and answers the rest with 200.
+func flakyServer(t *testing.T) *httptest.Server {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_HttpRequestHandler_Timing(t *testing.T) {
+	include, exclude := true, false
+	tests := []struct {
+		name          string
+		settings      Settings
+		includeTiming *bool
+		wantShown     bool
+	}{
+		{"hidden by default", Settings{}, nil, false},
+		{"server default", Settings{IncludeTiming: true}, nil, true},
+		{"agent enables", Settings{}, &include, true},
+		{"agent disables", Settings{IncludeTiming: true}, &exclude, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := flakyServer(t)
+			c := client.NewClient(client.Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, RetryCount: 2, RetryDelay: time.Millisecond})
+			handler := makeHandler(c, tt.settings, NewHistory(10), NewStats(), NewVariables())
+
+			result, out, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL, IncludeTiming: tt.includeTiming})
+			text := extractText(result)
+			if shown := strings.Contains(text, "[took "); shown != tt.wantShown {
+				t.Errorf("timing shown = %v, want %v in %s", shown, tt.wantShown, text)
+			}
+			if !strings.Contains(text, "[succeeded on attempt 2 after 1 retry (503), ") {
+				t.Errorf("expected the retry summary in %s", text)
+			}
+			output, ok := out.(RequestOutput)
+			if !ok || output.Status != 200 || output.Timing.Attempts != 2 || output.Timing.Retries != 1 || len(output.Timing.RetriedStatuses) != 1 {
+				t.Errorf("structured output = %+v", out)
+			}
+		})
+	}
+}
+
+func Test_HttpRequest_StructuredTiming(t *testing.T) {
+	server := flakyServer(t)
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	cfg := client.Config{Timeout: 5 * time.Second, MaxResponseSize: 1024, RetryCount: 1, RetryDelay: time.Millisecond}
+	Register(mcpServer, client.NewClient(cfg), cfg, Settings{}, nil)
+	session := connectTestSession(t, mcpServer)
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "http_request",
+		Arguments: map[string]any{"method": "GET", "url": server.URL},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	data, _ := json.Marshal(result.StructuredContent)
+	var output RequestOutput
+	if err := json.Unmarshal(data, &output); err != nil || output.Timing.Attempts != 2 || output.Timing.RetriedStatuses[0] != 503 {
+		t.Errorf("structured content = %s", data)
+	}
+}
+
+func Test_RequestTiming_Summary(t *testing.T) {
+	tests := []struct {
+		name         string
+		timing       RequestTiming
+		status       int
+		showDuration bool
+		want         string
+	}{
+		{"first attempt", RequestTiming{DurationMs: 154, TotalMs: 160, Attempts: 1}, 200, false, ""},
+		{"duration", RequestTiming{DurationMs: 154, TotalMs: 160, Attempts: 1}, 200, true, "[took 154ms]"},
+		{"one retry", RequestTiming{DurationMs: 154, TotalMs: 1204, Attempts: 2, Retries: 1, RetriedStatuses: []int{503}}, 200, false,
+			"[succeeded on attempt 2 after 1 retry (503), 1.2s total]"},
+		{"retries with duration", RequestTiming{DurationMs: 80, TotalMs: 4214, Attempts: 3, Retries: 2, RetriedStatuses: []int{0, 502}}, 201, true,
+			"[took 80ms]\n[succeeded on attempt 3 after 2 retries (no response, 502), 4.2s total]"},
+		{"retries exhausted", RequestTiming{DurationMs: 80, TotalMs: 2100, Attempts: 3, Retries: 2, RetriedStatuses: []int{503, 503}}, 503, false,
+			"[still failing on attempt 3 after 2 retries (503, 503), 2.1s total]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.timing.summary(tt.status, tt.showDuration); got != tt.want {
+				t.Errorf("summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type TLSInspectInput struct {
+	Target     string `json:"target" jsonschema:"Host to inspect: host, host:port, or https URL (default port 443)"`
+	ServerName string `json:"serverName,omitempty" jsonschema:"SNI / verification host name override (default: the target host)"`
+}
+
+const tlsInspectDescription = "Inspect the TLS handshake with a server: negotiated protocol and cipher, " +
+	"and the presented certificate chain (subjects, SANs, issuers, expiry) with a verification verdict. " +
+	"Use when http_request fails with x509 or TLS handshake errors."
+
+// resolveTLSAddress turns a host, host:port, or URL into a dialable host:port.
+// A non-empty error message means the target is invalid.
+func resolveTLSAddress(target string) (string, string) {
+	if target == "" {
+		return "", "target is required"
+	}
+	if strings.Contains(target, "://") {
+		parsedURL, err := url.Parse(target)
+		if err != nil || parsedURL.Hostname() == "" {
+			return "", fmt.Sprintf("invalid target URL: %s", target)
+		}
+		port := parsedURL.Port()
+		if port == "" {
+			port = "443"
+		}
+		return net.JoinHostPort(parsedURL.Hostname(), port), ""
+	}
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target, ""
+	}
+	return net.JoinHostPort(strings.Trim(target, "[]"), "443"), ""
+}
+
+func formatTLSInspection(inspection *client.TLSInspection, now time.Time) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%s, %s\n", inspection.Protocol, inspection.CipherSuite)
+	fmt.Fprintf(&builder, "Server name: %s (%s)\n", inspection.ServerName, inspection.Address)
+	if inspection.VerificationError == "" {
+		builder.WriteString("Verification: OK")
+	} else {
+		fmt.Fprintf(&builder, "Verification failed: %s", inspection.VerificationError)
+	}
+
+	for i, cert := range inspection.Certificates {
+		fmt.Fprintf(&builder, "\n\nCertificate %d: %s", i+1, cert.Subject)
+		sans := append(append([]string{}, cert.DNSNames...), cert.IPAddresses...)
+		if len(sans) > 0 {
+			fmt.Fprintf(&builder, "\n  SANs: %s", strings.Join(sans, ", "))
+		}
+		fmt.Fprintf(&builder, "\n  Issuer: %s", cert.Issuer)
+		fmt.Fprintf(&builder, "\n  Valid: %s to %s (%s)", cert.NotBefore.UTC().Format(time.DateOnly), cert.NotAfter.UTC().Format(time.DateOnly), describeExpiry(cert.NotBefore, cert.NotAfter, now))
+		if cert.IsCA {
+			builder.WriteString("\n  CA: yes")
+		}
+	}
+	return builder.String()
+}
+
+func describeExpiry(notBefore, notAfter, now time.Time) string {
+	switch {
+	case now.Before(notBefore):
+		return "NOT YET VALID"
+	case now.After(notAfter):
+		return fmt.Sprintf("EXPIRED %d days ago", int(now.Sub(notAfter).Hours()/24))
+	default:
+		return fmt.Sprintf("expires in %d days", int(notAfter.Sub(now).Hours()/24))
+	}
+}
+
+func makeTLSInspectHandler(httpClient *client.Client, settings Settings) func(context.Context, *mcp.CallToolRequest, TLSInspectInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input TLSInspectInput) (*mcp.CallToolResult, any, error) {
+		address, validationError := resolveTLSAddress(input.Target)
+		if validationError != "" {
+			return errorResult(validationError), nil, nil
+		}
+		// The handshake is the start of a GET to the server's root, so the
+		// policy decides as it would for one.
+		if policyError := settings.URLs.check("GET", "https://"+address+"/"); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+
+		inspection, err := httpClient.InspectTLS(ctx, address, input.ServerName)
+		if err != nil {
+			return errorResult(fmt.Sprintf("TLS inspection failed: %s", err)), nil, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: formatTLSInspection(inspection, time.Now())}},
+		}, nil, nil
+	}
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_TLSInspectHandler_SelfSignedServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	handler := makeTLSInspectHandler(newTestClient(server.URL), Settings{})
+
+	result, _, err := handler(context.Background(), nil, TLSInspectInput{Target: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", extractText(result))
+	}
+
+	text := extractText(result)
+	if !strings.Contains(text, "TLS 1.") {
+		t.Errorf("expected negotiated protocol, got: %s", text)
+	}
+	if !strings.Contains(text, "Verification failed:") {
+		t.Errorf("expected verification failure for self-signed cert, got: %s", text)
+	}
+	if !strings.Contains(text, "Certificate 1:") || !strings.Contains(text, "SANs:") {
+		t.Errorf("expected certificate details, got: %s", text)
+	}
+}
+
+func Test_TLSInspectHandler_MissingTarget(t *testing.T) {
+	handler := makeTLSInspectHandler(newTestClient(""), Settings{})
+
+	result, _, _ := handler(context.Background(), nil, TLSInspectInput{})
+	if !result.IsError {
+		t.Fatal("expected error result for missing target")
+	}
+	if !strings.Contains(extractText(result), "target is required") {
+		t.Errorf("expected 'target is required', got: %s", extractText(result))
+	}
+}
+
+func Test_TLSInspectHandler_URLPolicy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	policy, err := ParseURLPolicy("allow GET https://api.example.com/**\ndefault deny")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _, _ := makeTLSInspectHandler(newTestClient(""), Settings{URLs: policy})(context.Background(), nil, TLSInspectInput{Target: server.URL})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "policy defaults to deny") {
+		t.Errorf("expected the handshake refused by the policy, got: %s", text)
+	}
+}
+
+func Test_ResolveTLSAddress(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"example.com", "example.com:443"},
+		{"example.com:8443", "example.com:8443"},
+		{"https://example.com/path", "example.com:443"},
+		{"https://example.com:9443", "example.com:9443"},
+		{"::1", "[::1]:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, validationError := resolveTLSAddress(tt.target)
+			if validationError != "" {
+				t.Fatalf("unexpected validation error: %s", validationError)
+			}
+			if got != tt.want {
+				t.Errorf("resolveTLSAddress(%q) = %q, want %q", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FormatTLSInspection_ExpiredCertificate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	text := formatTLSInspection(&client.TLSInspection{
+		Address:     "example.com:443",
+		ServerName:  "example.com",
+		Protocol:    "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		Certificates: []client.CertificateInfo{{
+			Subject:   "CN=example.com",
+			Issuer:    "CN=Test CA",
+			DNSNames:  []string{"example.com"},
+			NotBefore: now.AddDate(0, -3, 0),
+			NotAfter:  now.AddDate(0, 0, -10),
+		}},
+	}, now)
+
+	if !strings.Contains(text, "Verification: OK") {
+		t.Errorf("expected OK verification, got: %s", text)
+	}
+	if !strings.Contains(text, "EXPIRED 10 days ago") {
+		t.Errorf("expected expiry warning, got: %s", text)
+	}
+}
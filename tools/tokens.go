@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// bytesPerToken is the usual tokenizer ratio for English text, JSON and code.
+// Other scripts take about a token per character.
+const bytesPerToken = 4
+
+// estimateTokens approximates the tokens text takes in model context: one per
+// four ASCII bytes, one per other character.
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+bytesPerToken-1)/bytesPerToken + other
+}
+
+// fitTokens cuts text to about maxTokens (0 = no limit) and warns when the
+// result still exceeds budget (0 = no warning). Returns the text and its
+// estimated tokens.
+func fitTokens(text string, maxTokens, budget int) (string, int) {
+	total := estimateTokens(text)
+	if maxTokens > 0 && total > maxTokens {
+		text = cutToTokens(text, maxTokens)
+		text += fmt.Sprintf("\n[truncated to ~%d of ~%d tokens — narrow it with jsonFilter or fetch it all with saveTo]", estimateTokens(text), total)
+		return text, estimateTokens(text)
+	}
+	if budget > 0 && total > budget {
+		text += fmt.Sprintf("\n[~%d tokens, over the %d-token budget — pass maxTokens, jsonFilter or saveTo to keep responses small]", total, budget)
+	}
+	return text, total
+}
+
+// cutToTokens returns the longest prefix of text estimated at most maxTokens,
+// ending on a line break when one is near.
+func cutToTokens(text string, maxTokens int) string {
+	ascii, other, end := 0, 0, 0
+	for i, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		if (ascii+bytesPerToken-1)/bytesPerToken+other > maxTokens {
+			break
+		}
+		end = i + utf8.RuneLen(r)
+	}
+	prefix := text[:end]
+	for i := len(prefix) - 1; i >= len(prefix)*9/10 && i > 0; i-- {
+		if prefix[i] == '\n' {
+			return prefix[:i]
+		}
+	}
+	return prefix
+}
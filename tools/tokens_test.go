@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_EstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"héllo", 2}, // 4 ASCII bytes, plus é
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func Test_FitTokens(t *testing.T) {
+	long := strings.Repeat("0123456789abcdef\n", 100) // 1700 bytes, 425 tokens
+	tests := []struct {
+		name       string
+		maxTokens  int
+		budget     int
+		wantTokens func(int) bool
+		wantNote   string
+	}{
+		{"fits", 0, 1000, func(n int) bool { return n == 425 }, ""},
+		{"over budget", 0, 100, func(n int) bool { return n == 425 }, "over the 100-token budget"},
+		{"cut by maxTokens", 50, 100, func(n int) bool { return n > 40 && n <= 80 }, "truncated to ~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, tokens := fitTokens(long, tt.maxTokens, tt.budget)
+			if !tt.wantTokens(tokens) {
+				t.Errorf("tokens = %d", tokens)
+			}
+			if tt.wantNote == "" && text != long || tt.wantNote != "" && !strings.Contains(text, tt.wantNote) {
+				t.Errorf("text ends with %q, want %q", text[max(0, len(text)-120):], tt.wantNote)
+			}
+		})
+	}
+}
+
+func Test_CutToTokens_KeepsRunesAndLines(t *testing.T) {
+	if got := cutToTokens("日本語テキスト", 3); got != "日本語" {
+		t.Errorf("cutToTokens = %q", got)
+	}
+	text := strings.Repeat("x", 40) + "\n" + strings.Repeat("y", 10)
+	if got := cutToTokens(text, 11); got != strings.Repeat("x", 40) {
+		t.Errorf("cutToTokens = %q, want the cut at the line break", got)
+	}
+}
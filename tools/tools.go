@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/openapi"
+	"github.com/lexandro/rest-api-mcp/s3"
+)
+
+// Settings holds operator-configured behavior of the tools: defaults that apply
+// when the agent leaves the corresponding input unset, and the policies that
+// constrain what http_request may send.
+type Settings struct {
+	FollowRedirects        bool
+	IncludeResponseHeaders bool
+	IncludeTiming          bool  // show the duration and attempt under the status line
+	MaxResponseSize        int64 // the client's response body limit; summarize summarizes bodies over it
+	TokenBudget            int   // warn when an output is estimated above this many tokens; 0 disables
+	DecodeBinaryBodies     bool  // show MessagePack and CBOR responses as JSON
+	EnableFaultInjection   bool  // registers test-mode tools such as simulate_auth_expiry
+	Methods                MethodPolicy
+	URLs                   URLPolicy
+	Limits                 RequestLimits
+	Headers                HeaderPolicy
+	Redaction              ResponseRedaction  // which response header values are censored in output
+	Profile                string             // active configuration profile, shown in the tool description
+	RequestIDHeader        string             // header that carries a generated UUID per call; empty disables it
+	DryRun                 bool               // render every http_request instead of sending it
+	DefaultFormat          string             // output format when the agent does not set one; empty means text
+	TemplateEnv            map[string]string  // environment values request templates may read with env
+	Proto                  *ProtoTypes        // message types for protoRequestType/protoResponseType; nil without --proto-descriptors
+	OpenAPI                *openapi.Source    // summarizes responses of described operations; nil without --openapi
+	OpenAPITools           bool               // registers one tool per operation of OpenAPI
+	OpenAPIFilter          OperationFilter    // which operations OpenAPITools registers, and how many tools at most
+	AsyncAPI               *openapi.AsyncSpec // registers one subscribe tool per channel; nil without --asyncapi
+	S3                     *s3.Config         // registers the presign tool; nil without S3 credentials
+	EffectiveConfig        string             // served as config://effective: the resolved configuration, secrets redacted; empty omits it
+	Toolsets               Toolsets           // the tools to register; nil registers all
+}
+
+// Register adds every tool, the built-in prompts and the resources to
+// mcpServer, plus one request tool per named API. The generic tools use
+// httpClient; all tools share one request history, stats and template
+// variables.
+func Register(mcpServer *mcp.Server, httpClient *client.Client, cfg client.Config, settings Settings, apis []API) error {
+	return registerTools(mcpServer, httpClient, cfg, settings, apis, NewHistory(historyCapacity), NewStats(), NewVariables(), &generatedTools{}, &sessionResources{})
+}
+
+// registerTools adds or replaces every tool, prompt and resource.
+// Re-registering with another client (use_profile) or OpenAPI description
+// (openapi_refresh) swaps them in place and notifies the MCP client.
+func registerTools(mcpServer *mcp.Server, httpClient *client.Client, cfg client.Config, settings Settings, apis []API, history *History, stats *Stats, variables *Variables, generated *generatedTools, resources *sessionResources) error {
+	inputSchema, err := requestInputSchema()
+	if err != nil {
+		return err
+	}
+	openWorld := true
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "http_request",
+		Description: buildToolDescription(cfg, settings),
+		InputSchema: inputSchema,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  settings.Methods.isReadOnly(),
+			OpenWorldHint: &openWorld,
+		},
+	}, makeHandler(httpClient, settings, history, stats, variables))
+	registerPrompts(mcpServer, cfg, settings)
+	resources.sync(mcpServer, settings, history)
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "poll",
+		Description: pollDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  true,
+			OpenWorldHint: &openWorld,
+		},
+	}, makePollHandler(httpClient, settings, stats))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "watch_resource",
+		Description: watchResourceDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  true,
+			OpenWorldHint: &openWorld,
+		},
+	}, makeWatchResourceHandler(httpClient, settings, stats))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "subscribe",
+		Description: subscribeDescription,
+		Annotations: &mcp.ToolAnnotations{
+			OpenWorldHint: &openWorld,
+		},
+	}, makeSubscribeHandler(httpClient, settings))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "upload",
+		Description: uploadDescription,
+		Annotations: &mcp.ToolAnnotations{
+			OpenWorldHint: &openWorld,
+		},
+	}, makeUploadHandler(httpClient, settings, stats))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "tls_inspect",
+		Description: tlsInspectDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  true,
+			OpenWorldHint: &openWorld,
+		},
+	}, makeTLSInspectHandler(httpClient, settings))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "url_tool",
+		Description: urlToolDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, makeURLToolHandler())
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "cors_check",
+		Description: corsCheckDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  true,
+			OpenWorldHint: &openWorld,
+		},
+	}, makeCORSCheckHandler(httpClient, settings))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "api_discover",
+		Description: apiDiscoverDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint:  true,
+			OpenWorldHint: &openWorld,
+		},
+	}, makeAPIDiscoverHandler(httpClient, settings, cfg.BaseURL))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "export_session",
+		Description: exportSessionDescription,
+	}, makeExportSessionHandler(history))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "stats",
+		Description: statsDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, makeStatsHandler(stats))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "rate_limit_status",
+		Description: rateLimitStatusDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, makeRateLimitStatusHandler(stats))
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "generate_payload",
+		Description: generatePayloadDescription,
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, makeGeneratePayloadHandler())
+
+	addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+		Name:        "set_variables",
+		Description: setVariablesDescription,
+	}, makeSetVariablesHandler(variables))
+
+	if settings.S3 != nil {
+		addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+			Name:        "presign",
+			Description: presignDescription,
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true,
+			},
+		}, makePresignHandler(*settings.S3))
+	} else {
+		mcpServer.RemoveTools("presign")
+	}
+
+	var operations *openapi.Spec
+	if settings.OpenAPI != nil {
+		addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+			Name:        "contract_check",
+			Description: contractCheckDescription,
+			Annotations: &mcp.ToolAnnotations{
+				OpenWorldHint: &openWorld,
+			},
+		}, makeContractCheckHandler(httpClient, settings, stats))
+
+		reregister := func() {
+			if err := registerTools(mcpServer, httpClient, cfg, settings, apis, history, stats, variables, generated, resources); err != nil {
+				log.Printf("updating the tools: %s", err)
+			}
+		}
+		addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+			Name:        "openapi_refresh",
+			Description: openAPIRefreshDescription,
+		}, makeOpenAPIRefreshHandler(settings.OpenAPI, reregister))
+		if settings.OpenAPI.Stale() {
+			revalidateInBackground(settings.OpenAPI, reregister)
+		}
+		if settings.OpenAPITools && settings.Toolsets.Enables("http_request") {
+			operations = settings.OpenAPI.Spec()
+		}
+	} else {
+		mcpServer.RemoveTools("contract_check", "openapi_refresh")
+	}
+	apiToolNames := make([]string, 0, len(apis))
+	for _, api := range apis {
+		apiToolNames = append(apiToolNames, api.ToolName())
+	}
+	generated.sync(mcpServer, operations, settings.OpenAPIFilter, cfg.BaseURL, apiToolNames, makeHandler(httpClient, settings, history, stats, variables))
+	var channels *openapi.AsyncSpec
+	if settings.Toolsets.Enables("subscribe") {
+		channels = settings.AsyncAPI
+	}
+	generated.syncChannels(mcpServer, channels, apiToolNames, makeSubscribeHandler(httpClient, settings))
+
+	if settings.EnableFaultInjection {
+		addTool(mcpServer, settings.Toolsets, &mcp.Tool{
+			Name:        "simulate_auth_expiry",
+			Description: simulateAuthExpiryDescription,
+		}, makeSimulateAuthExpiryHandler(httpClient))
+	} else {
+		mcpServer.RemoveTools("simulate_auth_expiry")
+	}
+
+	if !settings.Toolsets.Enables("http_request") {
+		return nil // the named API tools belong to core
+	}
+	for _, api := range apis {
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name:        api.ToolName(),
+			Description: api.toolDescription(),
+			InputSchema: inputSchema,
+			Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint:  api.Settings.Methods.isReadOnly(),
+				OpenWorldHint: &openWorld,
+			},
+		}, makeHandler(api.Client, api.Settings, history, stats, variables))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolsetTools are the built-in tools of each toolset. The named API tools
+// and the tools generated from an OpenAPI description belong to core.
+var toolsetTools = map[string][]string{
+	"core":      {"http_request", "use_profile"},
+	"testing":   {"contract_check", "http_compare_envs", "cors_check", "generate_payload", "simulate_auth_expiry", "stats", "rate_limit_status", "export_session"},
+	"streaming": {"poll", "watch_resource", "subscribe", "upload"},
+	"utilities": {"url_tool", "tls_inspect", "api_discover", "set_variables", "presign", "openapi_refresh"},
+}
+
+// Toolsets lists the enabled toolsets; nil enables every tool.
+type Toolsets []string
+
+// ParseToolsets parses a comma-separated toolset list such as "core,testing";
+// "all" or an empty list enables every tool.
+func ParseToolsets(value string) (Toolsets, error) {
+	var toolsets Toolsets
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		switch _, known := toolsetTools[name]; {
+		case name == "":
+			continue
+		case name == "all":
+			return nil, nil
+		case !known:
+			return nil, fmt.Errorf("unknown toolset %q (expected %s or all)", strings.TrimSpace(part), strings.Join(toolsetNames(), ", "))
+		}
+		if !slices.Contains(toolsets, name) {
+			toolsets = append(toolsets, name)
+		}
+	}
+	return toolsets, nil
+}
+
+func toolsetNames() []string {
+	names := make([]string, 0, len(toolsetTools))
+	for name := range toolsetTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enables reports whether the tool named name is registered.
+func (t Toolsets) Enables(name string) bool {
+	if len(t) == 0 {
+		return true
+	}
+	for _, toolset := range t {
+		if slices.Contains(toolsetTools[toolset], name) {
+			return true
+		}
+	}
+	return false
+}
+
+// note tells the agent which tools it cannot expect, for tool descriptions;
+// "" when every tool is enabled.
+func (t Toolsets) note() string {
+	var disabled []string
+	for _, name := range toolsetNames() {
+		if len(t) > 0 && !slices.Contains(t, name) {
+			disabled = append(disabled, name)
+		}
+	}
+	if len(disabled) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Enabled toolsets: %s; the server operator left out %s.", strings.Join(t, ", "), strings.Join(disabled, ", "))
+}
+
+// addTool registers tool unless its toolset is disabled, in which case a tool
+// of that name from an earlier registration is removed.
+func addTool[In any](mcpServer *mcp.Server, toolsets Toolsets, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, any]) {
+	if !toolsets.Enables(tool.Name) {
+		mcpServer.RemoveTools(tool.Name)
+		return
+	}
+	mcp.AddTool(mcpServer, tool, handler)
+}
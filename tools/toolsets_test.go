@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_ParseToolsets(t *testing.T) {
+	toolsets, err := ParseToolsets(" Core, testing,core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(toolsets, Toolsets{"core", "testing"}) {
+		t.Errorf("toolsets = %q, want core and testing", toolsets)
+	}
+	for _, value := range []string{"", "all", "core,all"} {
+		if toolsets, err := ParseToolsets(value); err != nil || toolsets != nil {
+			t.Errorf("ParseToolsets(%q) = %q, %v; want every tool", value, toolsets, err)
+		}
+	}
+	if _, err := ParseToolsets("core,admin"); err == nil || !strings.Contains(err.Error(), `unknown toolset "admin"`) {
+		t.Errorf("expected an unknown toolset error, got %v", err)
+	}
+}
+
+func Test_Toolsets_Enables(t *testing.T) {
+	if !Toolsets(nil).Enables("upload") {
+		t.Error("no toolsets should enable every tool")
+	}
+	core := Toolsets{"core"}
+	if !core.Enables("http_request") || core.Enables("upload") || core.Enables("stats") {
+		t.Error("core should enable http_request only")
+	}
+}
+
+func Test_Register_OnlyEnabledToolsets(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	c := client.NewClient(client.Config{})
+	api := API{Name: "billing", Client: c}
+	Register(mcpServer, c, client.Config{}, Settings{Toolsets: Toolsets{"core", "streaming"}}, []API{api})
+
+	toolList, err := connectTestSession(t, mcpServer).ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("listing tools: %v", err)
+	}
+	var names []string
+	for _, tool := range toolList.Tools {
+		names = append(names, tool.Name)
+		if tool.Name == "http_request" && !strings.Contains(tool.Description, "Enabled toolsets: core, streaming; the server operator left out testing, utilities.") {
+			t.Errorf("expected the toolsets in the http_request description, got: %s", tool.Description)
+		}
+	}
+	slices.Sort(names)
+	if want := []string{api.ToolName(), "http_request", "poll", "subscribe", "upload", "watch_resource"}; !slices.Equal(names, want) {
+		t.Errorf("tools = %q, want %q", names, want)
+	}
+}
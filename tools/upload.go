@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+type UploadInput struct {
+	URL       string                  `json:"url" jsonschema:"tus: the creation endpoint, or the upload URL with resume; content-range: the upload URL the chunks are PUT to"`
+	File      string                  `json:"file" jsonschema:"Local file to upload"`
+	Protocol  string                  `json:"protocol,omitempty" jsonschema:"tus (default) or content-range (PUT chunks with Content-Range, as Google and S3-style resumable sessions expect)"`
+	ChunkSize int64                   `json:"chunkSize,omitempty" jsonschema:"Bytes per request (default 8 MiB)"`
+	Resume    bool                    `json:"resume,omitempty" jsonschema:"url is an upload started earlier (the uploadUrl of a failed call): continue from the bytes the server already has"`
+	Headers   map[string]StringValues `json:"headers,omitempty" jsonschema:"Headers sent with every request of the upload"`
+	Metadata  map[string]string       `json:"metadata,omitempty" jsonschema:"tus Upload-Metadata; filename defaults to the file's name"`
+}
+
+const uploadDescription = "Upload a large local file in chunks with a resumable protocol (tus, or Content-Range PUTs), reporting progress per chunk. " +
+	"A failed chunk does not restart the upload: the server is asked how much it has and the upload continues from there; " +
+	"if the call still fails, call again with resume: true and the returned uploadUrl. Use http_request with files for small multipart uploads."
+
+// UploadOutput is the structured output of upload.
+type UploadOutput struct {
+	UploadURL string `json:"uploadUrl"`
+	Size      int64  `json:"size"`
+	Uploaded  int64  `json:"uploaded"` // bytes the server confirmed
+	Requests  int    `json:"requests"`
+	Resumes   int    `json:"resumes"`
+	Status    int    `json:"status,omitempty"` // of the last response
+	Complete  bool   `json:"complete"`
+}
+
+func makeUploadHandler(httpClient *client.Client, settings Settings, stats *Stats) func(context.Context, *mcp.CallToolRequest, UploadInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input UploadInput) (*mcp.CallToolResult, any, error) {
+		protocol := strings.ToLower(input.Protocol)
+		if protocol == "" {
+			protocol = client.UploadTus
+		}
+		if protocol != client.UploadTus && protocol != client.UploadContentRange {
+			return requestError(errInvalidRequest, fmt.Sprintf("unknown protocol %q (expected tus or content-range)", input.Protocol))
+		}
+		if input.URL == "" || input.File == "" {
+			return requestError(errInvalidRequest, "url and file are required")
+		}
+		if input.ChunkSize < 0 {
+			return requestError(errInvalidRequest, "chunkSize must not be negative")
+		}
+		file, err := clientWorkspace(ctx, req).resolve(input.File)
+		if err != nil {
+			return requestError(errPolicyDenied, fmt.Sprintf("file: %s", err))
+		}
+		input.File = file
+		info, err := os.Stat(input.File)
+		if err != nil {
+			return requestError(errInvalidRequest, fmt.Sprintf("cannot read file: %s", err))
+		}
+		if limitError := settings.Limits.check(HttpRequestInput{Headers: input.Headers, Files: map[string]string{"file": input.File}}); limitError != "" {
+			return requestError(errPolicyDenied, limitError)
+		}
+		for _, method := range uploadMethods(protocol, input.Resume) {
+			if policyError := settings.Methods.check(method); policyError != "" {
+				return requestError(errPolicyDenied, policyError)
+			}
+		}
+		if settings.DryRun {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+				"[dry run — upload not sent]\n%s upload of %s (%d bytes) to %s", protocol, input.File, info.Size(), input.URL)}}}, nil, nil
+		}
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		params := client.UploadParams{
+			Protocol:  protocol,
+			URL:       input.URL,
+			File:      input.File,
+			ChunkSize: input.ChunkSize,
+			Resume:    input.Resume,
+			Headers:   headers,
+			Metadata:  input.Metadata,
+			Progress:  newProgressReporter(ctx, req),
+			CheckRequest: func(method, requestURL string) error {
+				resolved, err := httpClient.ResolveURL(client.RequestParams{URL: requestURL})
+				if err != nil {
+					return fmt.Errorf("invalid URL: %w", err)
+				}
+				if policyError := settings.URLs.check(method, resolved); policyError != "" {
+					return errors.New(policyError)
+				}
+				return nil
+			},
+		}
+
+		started := time.Now()
+		result, err := httpClient.Upload(ctx, params)
+		if result == nil {
+			return failedRequest(err, "", "")
+		}
+		output := UploadOutput{
+			UploadURL: result.UploadURL,
+			Size:      result.Size,
+			Uploaded:  result.Offset,
+			Requests:  result.Requests,
+			Resumes:   result.Resumes,
+			Complete:  err == nil,
+		}
+		if result.Response != nil {
+			output.Status = result.Response.StatusCode
+			if ctx.Err() == nil {
+				stats.Record(result.UploadURL, result.Offset, result.Response, time.Since(started))
+			}
+		}
+		text := headerNote + formatUpload(input.File, output, result.Response, settings)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Upload failed: %s\n%s", err, text)), output, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, output, nil
+	}
+}
+
+// uploadMethods are the methods an upload sends, checked before it starts so
+// a denied one does not strand a half-finished upload.
+func uploadMethods(protocol string, resume bool) []string {
+	switch {
+	case protocol == client.UploadContentRange:
+		return []string{"PUT"}
+	case resume:
+		return []string{"HEAD", "PATCH"}
+	}
+	return []string{"POST", "HEAD", "PATCH"}
+}
+
+func formatUpload(file string, output UploadOutput, last *client.Response, settings Settings) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Uploaded %d/%d bytes of %s in %d requests", output.Uploaded, output.Size, file, output.Requests)
+	if output.Resumes > 0 {
+		fmt.Fprintf(&builder, " (resumed %d times)", output.Resumes)
+	}
+	fmt.Fprintf(&builder, "\nUpload URL: %s", output.UploadURL)
+	if !output.Complete && output.UploadURL != "" {
+		builder.WriteString("\nCall upload again with resume: true and this url to continue.")
+	}
+	if last != nil {
+		builder.WriteString("\n\n" + FormatResponse(last, FormatOptions{IncludeHeaders: settings.IncludeResponseHeaders, Redaction: settings.Redaction}))
+	}
+	return builder.String()
+}
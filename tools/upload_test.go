@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func Test_UploadHandler_Tus(t *testing.T) {
+	var mu sync.Mutex
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Location", "/files/1")
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPatch:
+			chunk, _ := io.ReadAll(r.Body)
+			received = append(received, chunk...)
+			w.Header().Set("Upload-Offset", strconv.Itoa(len(received)))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 300)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := makeUploadHandler(newTestClient(""), Settings{}, NewStats())
+	result, output, err := handler(context.Background(), &mcp.CallToolRequest{}, UploadInput{URL: server.URL + "/files", File: path, ChunkSize: 128})
+	if err != nil || result.IsError {
+		t.Fatalf("upload failed: %v %s", err, extractText(result))
+	}
+	text := extractText(result)
+	if !strings.Contains(text, "Uploaded 300/300 bytes of "+path+" in 4 requests") || !strings.Contains(text, "Upload URL: "+server.URL+"/files/1") {
+		t.Errorf("text = %s", text)
+	}
+	if got := output.(UploadOutput); !got.Complete || got.Uploaded != 300 || len(received) != 300 {
+		t.Errorf("output = %+v, server has %d bytes", got, len(received))
+	}
+}
+
+func Test_UploadHandler_RejectsInvalidInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		settings Settings
+		input    UploadInput
+		want     string
+	}{
+		{"unknown protocol", Settings{}, UploadInput{URL: "http://example.com", File: path, Protocol: "ftp"}, `unknown protocol "ftp"`},
+		{"missing file", Settings{}, UploadInput{URL: "http://example.com", File: path + ".missing"}, "cannot read file"},
+		{"over the size limit", Settings{Limits: RequestLimits{MaxBodyBytes: 1024}}, UploadInput{URL: "http://example.com", File: path}, "--max-request-size"},
+		{"method denied", Settings{Methods: MethodPolicy{Deny: []string{"PATCH"}}}, UploadInput{URL: "http://127.0.0.1:1/files/1", File: path, Resume: true}, "PATCH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := makeUploadHandler(newTestClient(""), tt.settings, NewStats())
+			result, _, _ := handler(context.Background(), &mcp.CallToolRequest{}, tt.input)
+			if text := extractText(result); !result.IsError || !strings.Contains(text, tt.want) {
+				t.Errorf("result = %s, want an error containing %q", text, tt.want)
+			}
+		})
+	}
+}
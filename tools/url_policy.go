@@ -0,0 +1,249 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// URLRule is one line of a policy file: "allow|deny METHOD|* URL-PATTERN".
+type URLRule struct {
+	Allow   bool
+	Method  string // uppercase method or "*"
+	Pattern string
+	Line    int
+}
+
+func (r URLRule) String() string {
+	action := "deny"
+	if r.Allow {
+		action = "allow"
+	}
+	return fmt.Sprintf("%s %s %s", action, r.Method, r.Pattern)
+}
+
+// URLPolicy is an ordered rule list; the first matching rule decides.
+// A request matching no rule is allowed unless DefaultDeny is set.
+// The zero value allows everything.
+type URLPolicy struct {
+	Rules       []URLRule
+	DefaultDeny bool
+}
+
+// LoadURLPolicy reads a policy file. An empty path returns the zero policy.
+func LoadURLPolicy(path string) (URLPolicy, error) {
+	if path == "" {
+		return URLPolicy{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return URLPolicy{}, fmt.Errorf("reading policy file: %w", err)
+	}
+	policy, err := ParseURLPolicy(string(data))
+	if err != nil {
+		return URLPolicy{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// ParseURLPolicy parses policy text. Blank lines and lines starting with # are
+// ignored; "default allow" or "default deny" sets the fallback decision.
+func ParseURLPolicy(text string) (URLPolicy, error) {
+	var policy URLPolicy
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "default" && (fields[1] == "allow" || fields[1] == "deny") {
+			policy.DefaultDeny = fields[1] == "deny"
+			continue
+		}
+		if len(fields) != 3 || (fields[0] != "allow" && fields[0] != "deny") {
+			return URLPolicy{}, fmt.Errorf("line %d: expected \"allow|deny METHOD URL-PATTERN\" or \"default allow|deny\", got %q", lineNumber, line)
+		}
+		method := strings.ToUpper(fields[1])
+		if method != "*" && !validMethods[method] {
+			return URLPolicy{}, fmt.Errorf("line %d: unsupported method %q", lineNumber, fields[1])
+		}
+		policy.Rules = append(policy.Rules, URLRule{
+			Allow:   fields[0] == "allow",
+			Method:  method,
+			Pattern: fields[2],
+			Line:    lineNumber,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return URLPolicy{}, fmt.Errorf("reading policy: %w", err)
+	}
+	return policy, nil
+}
+
+// check returns a deny reason for method and the resolved requestURL, or "" if allowed.
+func (p URLPolicy) check(method, requestURL string) string {
+	if len(p.Rules) == 0 && !p.DefaultDeny {
+		return ""
+	}
+	target, path := policyTarget(requestURL)
+	for _, rule := range p.Rules {
+		if rule.Method != "*" && rule.Method != method {
+			continue
+		}
+		pattern, subject := rule.Pattern, target
+		if !strings.Contains(pattern, "://") {
+			// Scheme-less patterns match the path; a leading "*" stands for any
+			// prefix so "*/admin/**" catches admin paths at any depth.
+			subject = path
+			if strings.HasPrefix(pattern, "*/") {
+				pattern = "*" + pattern
+			}
+		}
+		if !matchURLPattern(pattern, subject) {
+			continue
+		}
+		if rule.Allow {
+			return ""
+		}
+		return fmt.Sprintf("%s %s is denied by policy rule on line %d (%s)", method, target, rule.Line, rule)
+	}
+	if p.DefaultDeny {
+		return fmt.Sprintf("%s %s matches no allow rule and the policy defaults to deny", method, target)
+	}
+	return ""
+}
+
+// describe summarizes the rules for the tool description.
+func (p URLPolicy) describe() string {
+	parts := make([]string, 0, len(p.Rules)+1)
+	for _, rule := range p.Rules {
+		parts = append(parts, rule.String())
+	}
+	if p.DefaultDeny {
+		parts = append(parts, "default deny")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// redirectCheck returns the check a client runs on every redirect target
+// before following it, or nil when the policy allows everything.
+func (p URLPolicy) redirectCheck() func(method, requestURL string) error {
+	if len(p.Rules) == 0 && !p.DefaultDeny {
+		return nil
+	}
+	return func(method, requestURL string) error {
+		if reason := p.check(method, requestURL); reason != "" {
+			return fmt.Errorf("redirect refused: %s", reason)
+		}
+		return nil
+	}
+}
+
+// policyTarget returns the URL rules match against — scheme and host lowercased,
+// path in canonical form, query and fragment dropped — and its path alone, for
+// patterns without a scheme.
+func policyTarget(requestURL string) (string, string) {
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL, requestURL
+	}
+	path := canonicalPath(parsedURL.EscapedPath())
+	return strings.ToLower(parsedURL.Scheme) + "://" + strings.ToLower(parsedURL.Host) + path, path
+}
+
+// canonicalPath is the path a server resolves an escaped path to, so a rule
+// cannot be sidestepped by spelling it differently: escaped unreserved
+// characters (%61dmin) are decoded, other escapes uppercased, and "." and ".."
+// segments removed (RFC 3986 section 6.2.2).
+func canonicalPath(escapedPath string) string {
+	var decoded strings.Builder
+	for i := 0; i < len(escapedPath); i++ {
+		if escapedPath[i] != '%' || i+2 >= len(escapedPath) {
+			decoded.WriteByte(escapedPath[i])
+			continue
+		}
+		value, err := strconv.ParseUint(escapedPath[i+1:i+3], 16, 8)
+		switch {
+		case err != nil:
+			decoded.WriteByte('%')
+			continue
+		case isUnreserved(byte(value)):
+			decoded.WriteByte(byte(value))
+		default:
+			decoded.WriteString(strings.ToUpper(escapedPath[i : i+3]))
+		}
+		i += 2
+	}
+	segments := strings.Split(decoded.String(), "/")
+	var resolved []string
+	for i, segment := range segments {
+		switch segment {
+		case ".", "..":
+			if segment == ".." && len(resolved) > 1 {
+				resolved = resolved[:len(resolved)-1]
+			}
+			if i == len(segments)-1 {
+				resolved = append(resolved, "")
+			}
+		default:
+			resolved = append(resolved, segment)
+		}
+	}
+	path := strings.Join(resolved, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// isUnreserved reports whether c may appear in a URL unescaped with the same meaning.
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// matchURLPattern matches a glob where "*" matches within one path segment and
+// "**" matches across segments. A trailing "/**" also matches the bare prefix,
+// so "/admin/**" covers "/admin" itself.
+func matchURLPattern(pattern, subject string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok && matchGlob(prefix, subject) {
+		return true
+	}
+	return matchGlob(pattern, subject)
+}
+
+func matchGlob(pattern, subject string) bool {
+	if pattern == "" {
+		return subject == ""
+	}
+	if strings.HasPrefix(pattern, "**") {
+		rest := pattern[2:]
+		for i := 0; i <= len(subject); i++ {
+			if matchGlob(rest, subject[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if pattern[0] == '*' {
+		rest := pattern[1:]
+		for i := 0; i <= len(subject); i++ {
+			if matchGlob(rest, subject[i:]) {
+				return true
+			}
+			if i < len(subject) && subject[i] == '/' {
+				return false
+			}
+		}
+		return false
+	}
+	if subject == "" || pattern[0] != subject[0] {
+		return false
+	}
+	return matchGlob(pattern[1:], subject[1:])
+}
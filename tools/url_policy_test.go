@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+func Test_MatchURLPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		subject string
+		want    bool
+	}{
+		{"https://api.example.com/v1/**", "https://api.example.com/v1/users/42", true},
+		{"https://api.example.com/v1/**", "https://api.example.com/v1", true},
+		{"https://api.example.com/v1/**", "https://api.example.com/v2/users", false},
+		{"https://*.example.com/**", "https://eu.example.com/x", true},
+		{"https://*.example.com/**", "https://evil.com/.example.com/x", false},
+		{"/admin/**", "/admin/users", true},
+		{"/admin/**", "/admin", true},
+		{"**/admin/**", "/tenants/7/admin/users", true},
+		{"/users/*", "/users/42", true},
+		{"/users/*", "/users/42/orders", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.subject, func(t *testing.T) {
+			if got := matchURLPattern(tt.pattern, tt.subject); got != tt.want {
+				t.Errorf("matchURLPattern(%q, %q) = %v, want %v", tt.pattern, tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseURLPolicy_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"missing pattern", "allow GET"},
+		{"unknown action", "permit GET /x"},
+		{"unknown method", "allow FETCH /x"},
+		{"bad default", "default maybe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseURLPolicy(tt.text); err == nil {
+				t.Errorf("expected error for %q", tt.text)
+			}
+		})
+	}
+}
+
+func Test_URLPolicy_Check(t *testing.T) {
+	policy, err := ParseURLPolicy(`
+# tenant API
+deny * */admin/**
+allow GET https://api.example.com/v1/**
+allow POST https://api.example.com/v1/orders
+default deny
+`)
+	if err != nil {
+		t.Fatalf("ParseURLPolicy: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		wantMessage string
+	}{
+		{"allowed GET", "GET", "https://API.example.com/v1/users?page=2", ""},
+		{"allowed POST", "POST", "https://api.example.com/v1/orders", ""},
+		{"nested admin denied", "GET", "https://api.example.com/tenants/7/admin", "rule on line 3"},
+		{"admin denied first", "GET", "https://api.example.com/v1/admin/users", "rule on line 3 (deny * */admin/**)"},
+		{"default deny", "DELETE", "https://api.example.com/v1/users/1", "defaults to deny"},
+		{"other host", "GET", "https://other.example.com/v1/users", "defaults to deny"},
+		{"dot segments resolved", "GET", "https://api.example.com/v1/../admin/users", "rule on line 3"},
+		{"escaped dots resolved", "GET", "https://api.example.com/v1/%2e%2E/internal", "defaults to deny"},
+		{"escaped letters decoded", "GET", "https://api.example.com/v1/%61dmin/x", "rule on line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.check(tt.method, tt.url)
+			if tt.wantMessage == "" && got != "" {
+				t.Errorf("expected allowed, got: %s", got)
+			}
+			if tt.wantMessage != "" && !strings.Contains(got, tt.wantMessage) {
+				t.Errorf("expected message containing %q, got: %q", tt.wantMessage, got)
+			}
+		})
+	}
+}
+
+func Test_canonicalPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"":                 "/",
+		"/v1/./users/":     "/v1/users/",
+		"/v1/../../admin":  "/admin",
+		"/v1/users/..":     "/v1/",
+		"/%7Euser/%2fetc":  "/~user/%2Fetc",
+		"/100%/done":       "/100%/done",
+		"/a%2Fb/%2E%2E/c":  "/c",
+		"/files/na%20me/.": "/files/na%20me/",
+	} {
+		if got := canonicalPath(path); got != want {
+			t.Errorf("canonicalPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func Test_URLPolicy_RedirectCheck(t *testing.T) {
+	if (URLPolicy{}).redirectCheck() != nil {
+		t.Error("expected no redirect check without rules")
+	}
+	check := URLPolicy{Rules: []URLRule{{Method: "*", Pattern: "*/admin/**", Line: 1}}}.redirectCheck()
+	if err := check("GET", "https://api.example.com/admin/users"); err == nil || !strings.Contains(err.Error(), "redirect refused") {
+		t.Errorf("expected the admin redirect refused, got %v", err)
+	}
+	if err := check("GET", "https://api.example.com/users"); err != nil {
+		t.Errorf("expected other redirects followed, got %v", err)
+	}
+}
+
+func Test_LoadURLPolicy(t *testing.T) {
+	policy, err := LoadURLPolicy("")
+	if err != nil || len(policy.Rules) != 0 || policy.DefaultDeny {
+		t.Errorf("expected zero policy for empty path, got %+v, %v", policy, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.txt")
+	if err := os.WriteFile(path, []byte("deny DELETE **\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = LoadURLPolicy(path)
+	if err != nil || len(policy.Rules) != 1 {
+		t.Errorf("expected one rule, got %+v, %v", policy, err)
+	}
+
+	if _, err := LoadURLPolicy(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func Test_HttpRequestHandler_URLPolicyUsesResolvedURL(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	policy, err := ParseURLPolicy("deny * /internal/**")
+	if err != nil {
+		t.Fatalf("ParseURLPolicy: %v", err)
+	}
+	httpClient := client.NewClient(client.Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	handler := makeHandler(httpClient, Settings{URLs: policy}, NewHistory(10), NewStats(), NewVariables())
+
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: "internal/metrics"})
+	if !result.IsError || !strings.Contains(extractText(result), "denied by policy rule on line 1") {
+		t.Errorf("expected policy denial, got: %s", extractText(result))
+	}
+	result, _, _ = handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: "/public"})
+	if result.IsError {
+		t.Errorf("expected allowed request, got: %s", extractText(result))
+	}
+	if requestCount != 1 {
+		t.Errorf("expected exactly one request to reach the server, got %d", requestCount)
+	}
+}
+
+func Test_HttpRequestHandler_URLPolicyChecksRedirects(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/public" {
+			http.Redirect(w, r, "/internal/metrics", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	policy, err := ParseURLPolicy("deny * /internal/**")
+	if err != nil {
+		t.Fatalf("ParseURLPolicy: %v", err)
+	}
+	handler := makeHandler(newTestClient(""), Settings{URLs: policy, FollowRedirects: true}, NewHistory(10), NewStats(), NewVariables())
+
+	result, _, _ := handler(context.Background(), nil, HttpRequestInput{Method: "GET", URL: server.URL + "/public"})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "redirect refused") || !strings.Contains(text, "denied by policy rule on line 1") {
+		t.Errorf("expected the redirect refused, got: %s", text)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expected the denied redirect target never requested, server saw %q", paths)
+	}
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type URLToolInput struct {
+	Operation string            `json:"operation" jsonschema:"encode, decode, parse, or build_query"`
+	Value     string            `json:"value,omitempty" jsonschema:"Input string: text to encode/decode, URL to parse, or optional base URL for build_query"`
+	Mode      string            `json:"mode,omitempty" jsonschema:"encode/decode mode: query (space as +, default) or path (space as %20)"`
+	Params    map[string]string `json:"params,omitempty" jsonschema:"build_query: key-value pairs to encode as a query string"`
+}
+
+const urlToolDescription = "Deterministic URL utility: percent-encode or decode a string (query or path mode), " +
+	"parse a URL into components and decoded query parameters, or build a correctly encoded query string from key-value pairs. " +
+	"Use instead of hand-encoding URLs."
+
+func makeURLToolHandler() func(context.Context, *mcp.CallToolRequest, URLToolInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input URLToolInput) (*mcp.CallToolResult, any, error) {
+		var output string
+		var err error
+
+		switch strings.ToLower(input.Operation) {
+		case "encode":
+			output, err = encodeURLComponent(input.Value, input.Mode)
+		case "decode":
+			output, err = decodeURLComponent(input.Value, input.Mode)
+		case "parse":
+			output, err = describeURL(input.Value)
+		case "build_query":
+			output, err = buildQueryString(input.Value, input.Params)
+		case "":
+			return errorResult("operation is required"), nil, nil
+		default:
+			return errorResult(fmt.Sprintf("unsupported operation: %s (expected encode, decode, parse, or build_query)", input.Operation)), nil, nil
+		}
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: output}},
+		}, nil, nil
+	}
+}
+
+func encodeURLComponent(value, mode string) (string, error) {
+	switch mode {
+	case "", "query":
+		return url.QueryEscape(value), nil
+	case "path":
+		return url.PathEscape(value), nil
+	default:
+		return "", fmt.Errorf("unsupported mode: %s (expected query or path)", mode)
+	}
+}
+
+func decodeURLComponent(value, mode string) (string, error) {
+	var decoded string
+	var err error
+	switch mode {
+	case "", "query":
+		decoded, err = url.QueryUnescape(value)
+	case "path":
+		decoded, err = url.PathUnescape(value)
+	default:
+		return "", fmt.Errorf("unsupported mode: %s (expected query or path)", mode)
+	}
+	if err != nil {
+		return "", fmt.Errorf("decoding %q: %w", value, err)
+	}
+	return decoded, nil
+}
+
+func describeURL(value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("value is required for parse")
+	}
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL: %w", err)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "scheme: %s", parsedURL.Scheme)
+	if parsedURL.User != nil {
+		_, hasPassword := parsedURL.User.Password()
+		fmt.Fprintf(&builder, "\nuser: %s", parsedURL.User.Username())
+		if hasPassword {
+			builder.WriteString(" (password set)")
+		}
+	}
+	fmt.Fprintf(&builder, "\nhost: %s", parsedURL.Hostname())
+	if parsedURL.Port() != "" {
+		fmt.Fprintf(&builder, "\nport: %s", parsedURL.Port())
+	}
+	fmt.Fprintf(&builder, "\npath: %s", parsedURL.Path)
+	if parsedURL.RawPath != "" {
+		fmt.Fprintf(&builder, "\nraw path: %s", parsedURL.RawPath)
+	}
+
+	if parsedURL.RawQuery != "" {
+		query, err := url.ParseQuery(parsedURL.RawQuery)
+		if err != nil {
+			return "", fmt.Errorf("parsing query %q: %w", parsedURL.RawQuery, err)
+		}
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		builder.WriteString("\nquery:")
+		for _, key := range keys {
+			for _, queryValue := range query[key] {
+				fmt.Fprintf(&builder, "\n  %s = %s", key, queryValue)
+			}
+		}
+	}
+	if parsedURL.Fragment != "" {
+		fmt.Fprintf(&builder, "\nfragment: %s", parsedURL.Fragment)
+	}
+	return builder.String(), nil
+}
+
+// buildQueryString encodes params (sorted by key) and appends them to baseURL
+// when one is given, merging with any query the base URL already has.
+func buildQueryString(baseURL string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return "", fmt.Errorf("params is required for build_query")
+	}
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	if baseURL == "" {
+		return query.Encode(), nil
+	}
+
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing URL: %w", err)
+	}
+	merged := parsedURL.Query()
+	for key, value := range params {
+		merged.Set(key, value)
+	}
+	parsedURL.RawQuery = merged.Encode()
+	return parsedURL.String(), nil
+}
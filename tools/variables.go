@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type SetVariablesInput struct {
+	Set   map[string]string `json:"set,omitempty" jsonschema:"Variables to set or overwrite, name -> value"`
+	Unset []string          `json:"unset,omitempty" jsonschema:"Variables to remove"`
+}
+
+const setVariablesDescription = "Set session variables for request templates: http_request with template: true renders {{.name}} (or {{var \"name\"}}) in its URL, headers, query parameters and body. " +
+	"Use to carry IDs and tokens from one response into later requests. Call without arguments to list the variables."
+
+// Variables holds the session's template variables, shared by every request tool.
+type Variables struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func NewVariables() *Variables {
+	return &Variables{values: make(map[string]string)}
+}
+
+// Snapshot returns a copy of the variables for rendering one request.
+func (v *Variables) Snapshot() map[string]string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return maps.Clone(v.values)
+}
+
+func (v *Variables) update(set map[string]string, unset []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, name := range unset {
+		delete(v.values, name)
+	}
+	maps.Copy(v.values, set)
+}
+
+func makeSetVariablesHandler(variables *Variables) func(context.Context, *mcp.CallToolRequest, SetVariablesInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input SetVariablesInput) (*mcp.CallToolResult, any, error) {
+		for name := range input.Set {
+			if name == "" {
+				return errorResult("variable names must not be empty"), nil, nil
+			}
+		}
+		variables.update(input.Set, input.Unset)
+
+		values := variables.Snapshot()
+		if len(values) == 0 {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "No variables set."}}}, nil, nil
+		}
+		var text strings.Builder
+		fmt.Fprintf(&text, "%d variables:\n", len(values))
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			fmt.Fprintf(&text, "%s = %s\n", name, values[name])
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: strings.TrimRight(text.String(), "\n")}}}, nil, nil
+	}
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tidwall/gjson"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// Watch defaults: a resource usually changes on a human time scale, so it is
+// checked less often and for longer than poll samples.
+const (
+	defaultWatchInterval = "10s"
+	defaultWatchDuration = "5m"
+)
+
+type WatchResourceInput struct {
+	URL         string                  `json:"url" jsonschema:"Full URL or relative path (if base_url configured)"`
+	Headers     map[string]StringValues `json:"headers,omitempty" jsonschema:"Request headers"`
+	QueryParams map[string]StringValues `json:"queryParams,omitempty" jsonschema:"Query parameters"`
+	Interval    string                  `json:"interval,omitempty" jsonschema:"Time between checks, e.g. 30s (default 10s, at least 1s)"`
+	Duration    string                  `json:"duration,omitempty" jsonschema:"How long to wait for a change, e.g. 10m (default 5m, at most 15m)"`
+	JSONFilter  string                  `json:"jsonFilter,omitempty" jsonschema:"GJSON path of the part to watch, e.g. status or items.#.id; changes elsewhere are ignored"`
+	Ignore      []string                `json:"ignore,omitempty" jsonschema:"GJSON paths whose changes do not count, e.g. updatedAt"`
+}
+
+const watchResourceDescription = "Wait until a resource changes: GET it once, then re-check it every interval with conditional requests (If-None-Match/If-Modified-Since, so an unchanged resource costs a 304) " +
+	"and return as soon as its status or body differs, with the differences and the new body, or when the duration passes. " +
+	"Use instead of repeating http_request to wait for a job, deployment or document to update."
+
+// WatchResourceOutput is the structured output of watch_resource.
+type WatchResourceOutput struct {
+	Changed     bool     `json:"changed"`
+	Checks      int      `json:"checks"`      // requests after the first
+	NotModified int      `json:"notModified"` // checks answered 304
+	Failed      int      `json:"failed,omitempty"`
+	ElapsedMs   int64    `json:"elapsedMs"`
+	Status      int      `json:"status"`                // of the first response, or of the changed one
+	Differences []string `json:"differences,omitempty"` // from the first response's body
+	Stopped     string   `json:"stopped"`               // why watching ended
+}
+
+// watchedState is what the watched resource looked like, and the validators
+// that let the next check be conditional.
+type watchedState struct {
+	status       int
+	body         []byte // the jsonFilter value with jsonFilter
+	etag         string
+	lastModified string
+}
+
+func makeWatchResourceHandler(httpClient *client.Client, settings Settings, stats *Stats) func(context.Context, *mcp.CallToolRequest, WatchResourceInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input WatchResourceInput) (*mcp.CallToolResult, any, error) {
+		if input.URL == "" {
+			return errorResult("url is required"), nil, nil
+		}
+		interval, duration, err := pollTiming(PollInput{Interval: cmp.Or(input.Interval, defaultWatchInterval), Duration: cmp.Or(input.Duration, defaultWatchDuration)})
+		if err != nil {
+			return errorResult(err.Error()), nil, nil
+		}
+		if policyError := settings.Methods.check("GET"); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if limitError := settings.Limits.check(HttpRequestInput{Method: "GET", Headers: input.Headers}); limitError != "" {
+			return errorResult(limitError), nil, nil
+		}
+		headers, headerNote := settings.Headers.filter(joinHeaders(input.Headers))
+		params := client.RequestParams{
+			Method:          "GET",
+			URL:             input.URL,
+			Headers:         headers,
+			QueryParams:     queryParams(HttpRequestInput{QueryParams: input.QueryParams}),
+			FollowRedirects: settings.FollowRedirects,
+			CheckRedirect:   settings.URLs.redirectCheck(),
+			Cache:           client.CacheBypass,
+		}
+		requestURL, err := httpClient.ResolveURL(params)
+		if err != nil {
+			return errorResult(fmt.Sprintf("Invalid URL: %s", err)), nil, nil
+		}
+		if policyError := settings.URLs.check("GET", requestURL); policyError != "" {
+			return errorResult(policyError), nil, nil
+		}
+		if settings.DryRun {
+			rendered, err := httpClient.RenderRequest(ctx, params)
+			if err != nil {
+				return errorResult(fmt.Sprintf("Dry run failed: %s", err)), nil, nil
+			}
+			text := headerNote + formatDryRun(rendered, HttpRequestInput{})
+			text += fmt.Sprintf("\n\n[not watched; would re-check every %s for %s]", interval, duration)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		}
+
+		started := time.Now()
+		resp, err := watchCheck(ctx, httpClient, params, stats, requestURL)
+		if err != nil {
+			return errorResult(fmt.Sprintf("GET %s failed: %s", requestURL, classifyError(err))), nil, nil
+		}
+		baseline := newWatchedState(resp, input.JSONFilter)
+		output := WatchResourceOutput{Status: baseline.status, Stopped: "duration elapsed"}
+		progress := newProgressReporter(ctx, req)
+		deadline := started.Add(duration)
+		current := baseline
+		var changed *client.Response
+		for next := started.Add(interval); !next.After(deadline); next = next.Add(interval) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Until(next)):
+			}
+			if ctx.Err() != nil {
+				output.Stopped = "cancelled"
+				break
+			}
+			resp, err := watchCheck(ctx, httpClient, current.conditional(params), stats, requestURL)
+			output.Checks++
+			outcome := ""
+			switch {
+			case err != nil:
+				output.Failed++
+				outcome = "failed: " + classifyError(err)
+			case resp.StatusCode == http.StatusNotModified:
+				output.NotModified++
+				outcome = "not modified"
+			default:
+				current = newWatchedState(resp, input.JSONFilter)
+				output.Differences = current.differences(baseline, input.Ignore)
+				outcome = "unchanged"
+				if output.Differences != nil {
+					changed = resp
+					outcome = "changed"
+				}
+			}
+			if progress != nil {
+				progress(fmt.Sprintf("check %d: %s", output.Checks, outcome))
+			}
+			if changed != nil {
+				output.Changed, output.Status, output.Stopped = true, changed.StatusCode, "changed"
+				break
+			}
+		}
+		output.ElapsedMs = time.Since(started).Milliseconds()
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: headerNote + formatWatch(requestURL, baseline, changed, input.JSONFilter, output)}},
+		}, output, nil
+	}
+}
+
+// watchCheck sends one request of a watch and records it in stats.
+func watchCheck(ctx context.Context, httpClient *client.Client, params client.RequestParams, stats *Stats, requestURL string) (*client.Response, error) {
+	started := time.Now()
+	resp, err := httpClient.ExecuteRequest(ctx, params)
+	stats.Record(requestURL, 0, resp, time.Since(started))
+	return resp, err
+}
+
+func newWatchedState(resp *client.Response, jsonFilter string) watchedState {
+	state := watchedState{status: resp.StatusCode, body: resp.Body, etag: resp.Headers.Get("ETag"), lastModified: resp.Headers.Get("Last-Modified")}
+	if jsonFilter != "" {
+		state.body = []byte(gjson.GetBytes(resp.Body, jsonFilter).Raw)
+	}
+	return state
+}
+
+// conditional adds the state's validators to params, unless the agent set
+// its own.
+func (s watchedState) conditional(params client.RequestParams) client.RequestParams {
+	headers := make(map[string]string, len(params.Headers)+2)
+	for name, value := range params.Headers {
+		headers[name] = value
+	}
+	if _, set := headers["If-None-Match"]; !set && s.etag != "" {
+		headers["If-None-Match"] = s.etag
+	}
+	if _, set := headers["If-Modified-Since"]; !set && s.lastModified != "" {
+		headers["If-Modified-Since"] = s.lastModified
+	}
+	params.Headers = headers
+	return params
+}
+
+// differences describes how s differs from baseline: nil when it does not.
+func (s watchedState) differences(baseline watchedState, ignore []string) []string {
+	differences := diffBodies(baseline.body, s.body, ignore)
+	if s.status != baseline.status {
+		differences = append([]string{fmt.Sprintf("status: %d → %d", baseline.status, s.status)}, differences...)
+	}
+	return differences
+}
+
+func formatWatch(requestURL string, baseline watchedState, changed *client.Response, jsonFilter string, output WatchResourceOutput) string {
+	var builder strings.Builder
+	elapsed := (time.Duration(output.ElapsedMs) * time.Millisecond).Round(time.Second)
+	verdict := "unchanged"
+	if output.Changed {
+		verdict = "changed"
+	}
+	fmt.Fprintf(&builder, "Watched GET %s for %s: %s after %d checks (%d answered 304 Not Modified", requestURL, elapsed, verdict, output.Checks, output.NotModified)
+	if output.Failed > 0 {
+		fmt.Fprintf(&builder, ", %d failed", output.Failed)
+	}
+	fmt.Fprintf(&builder, ") — stopped: %s", output.Stopped)
+	if changed == nil {
+		fmt.Fprintf(&builder, "\nStatus: %d", baseline.status)
+		return builder.String()
+	}
+
+	builder.WriteString("\n\nDifferences:")
+	for _, difference := range output.Differences {
+		builder.WriteString("\n  " + difference)
+	}
+	body := changed.Body
+	label := "New body"
+	if jsonFilter != "" {
+		body, label = []byte(gjson.GetBytes(changed.Body, jsonFilter).Raw), "New "+jsonFilter
+	}
+	switch {
+	case len(body) == 0:
+	case isTextContent(changed.ContentType, body):
+		fmt.Fprintf(&builder, "\n\n%s:\n%s", label, body)
+		if changed.Truncated && jsonFilter == "" {
+			fmt.Fprintf(&builder, "\n[truncated: %d of %d bytes shown]", len(body), totalBodySize(changed))
+		}
+	default:
+		fmt.Fprintf(&builder, "\n\n%s: [%s, %d bytes]", label, displayContentType(changed.ContentType), len(body))
+	}
+	return builder.String()
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_WatchResourceHandler_ReturnsOnChange(t *testing.T) {
+	var calls, conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := 1
+		if calls.Add(1) >= 3 {
+			version = 2
+		}
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"state":"v%d","updatedAt":%d}`, version, calls.Load())
+	}))
+	defer server.Close()
+
+	handler := makeWatchResourceHandler(newTestClient(server.URL), Settings{}, NewStats())
+	result, out, _ := handler(context.Background(), nil, WatchResourceInput{URL: server.URL, Interval: "1s", Duration: "1m", Ignore: []string{"updatedAt"}})
+
+	output := out.(WatchResourceOutput)
+	if !output.Changed || output.Checks != 2 || output.NotModified != 1 || conditional.Load() != 1 {
+		t.Fatalf("output = %+v, conditional hits = %d", output, conditional.Load())
+	}
+	text := extractText(result)
+	for _, want := range []string{"changed after 2 checks (1 answered 304 Not Modified)", `state: "v1" → "v2"`, `New body:` + "\n" + `{"state":"v2"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "updatedAt:") {
+		t.Errorf("expected ignored paths left out of the differences:\n%s", text)
+	}
+}
+
+func Test_WatchResourceHandler_JSONFilterIgnoresOtherChanges(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":"running","progress":%d}`, calls.Add(1))
+	}))
+	defer server.Close()
+
+	handler := makeWatchResourceHandler(newTestClient(server.URL), Settings{}, NewStats())
+	result, out, _ := handler(context.Background(), nil, WatchResourceInput{URL: server.URL, Interval: "1s", Duration: "1s", JSONFilter: "status"})
+
+	output := out.(WatchResourceOutput)
+	if output.Changed || output.Checks != 1 || output.Stopped != "duration elapsed" {
+		t.Fatalf("output = %+v", output)
+	}
+	if text := extractText(result); !strings.Contains(text, "unchanged after 1 checks") || !strings.Contains(text, "Status: 200") {
+		t.Errorf("unexpected output:\n%s", text)
+	}
+}
+
+func Test_WatchResourceHandler_RejectsInvalidInput(t *testing.T) {
+	handler := makeWatchResourceHandler(newTestClient(""), Settings{Methods: MethodPolicy{Deny: []string{"GET"}}}, NewStats())
+	tests := []struct {
+		name  string
+		input WatchResourceInput
+		want  string
+	}{
+		{"missing url", WatchResourceInput{}, "url is required"},
+		{"fast interval", WatchResourceInput{URL: "http://example.com", Interval: "10ms"}, "interval must be at least 1s"},
+		{"long duration", WatchResourceInput{URL: "http://example.com", Duration: "2h"}, "between 0 and 15m0s"},
+		{"method policy", WatchResourceInput{URL: "http://example.com"}, "GET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			if !result.IsError || !strings.Contains(extractText(result), tt.want) {
+				t.Errorf("result = %s, want an error containing %q", extractText(result), tt.want)
+			}
+		})
+	}
+}
+
+func Test_WatchResourceHandler_DryRunAndLimits(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	result, _, _ := makeWatchResourceHandler(newTestClient(""), Settings{DryRun: true}, NewStats())(context.Background(), nil, WatchResourceInput{URL: server.URL})
+	if text := extractText(result); result.IsError || !strings.Contains(text, "[dry run — request not sent]") || !strings.Contains(text, "[not watched; would re-check every 10s for 5m0s]") {
+		t.Errorf("expected a dry run, got: %s", text)
+	}
+	limited := Settings{Limits: RequestLimits{MaxHeaderBytes: 8}}
+	result, _, _ = makeWatchResourceHandler(newTestClient(""), limited, NewStats())(context.Background(), nil, WatchResourceInput{URL: server.URL, Headers: map[string]StringValues{"X-Long": {"0123456789"}}})
+	if text := extractText(result); !result.IsError || !strings.Contains(text, "--max-header-size") {
+		t.Errorf("expected the header size limit enforced, got: %s", text)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no request sent, server saw %d", calls.Load())
+	}
+}
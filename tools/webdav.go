@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+)
+
+// webdavMethods are the WebDAV (RFC 4918) methods, plus REPORT for CalDAV and
+// CardDAV queries.
+var webdavMethods = map[string]bool{"PROPFIND": true, "PROPPATCH": true, "MKCOL": true, "COPY": true, "MOVE": true, "REPORT": true}
+
+// validDepths are the values of the WebDAV Depth header.
+var validDepths = map[string]bool{"0": true, "1": true, "infinity": true}
+
+// validateWebDAV checks the depth, destination and overwrite inputs; method
+// is the normalized method.
+func validateWebDAV(input HttpRequestInput, method string) string {
+	if input.Depth != "" && !validDepths[strings.ToLower(input.Depth)] {
+		return fmt.Sprintf("unsupported depth: %s (expected 0, 1 or infinity)", input.Depth)
+	}
+	if (input.Destination != "" || input.Overwrite != nil) && method != "COPY" && method != "MOVE" {
+		return "destination and overwrite apply only to COPY and MOVE"
+	}
+	return ""
+}
+
+// withWebDAVHeaders sets the Depth, Destination and Overwrite headers from the
+// inputs and labels an XML body of a WebDAV method. The Destination header
+// must be an absolute URL, so a relative destination resolves against the
+// request URL.
+func withWebDAVHeaders(httpClient *client.Client, input HttpRequestInput, method string, headers map[string]string) (map[string]string, error) {
+	set := func(name, value string) {
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[name] = value
+	}
+	if input.Depth != "" {
+		set("Depth", strings.ToLower(input.Depth))
+	}
+	if input.Destination != "" {
+		requestURL, err := httpClient.ResolveURL(client.RequestParams{URL: input.URL, ReplaceQuery: true})
+		if err != nil {
+			return nil, err
+		}
+		destination, err := resolveReference(requestURL, input.Destination)
+		if err != nil {
+			return nil, err
+		}
+		set("Destination", destination)
+	}
+	if input.Overwrite != nil {
+		overwrite := "T"
+		if !*input.Overwrite {
+			overwrite = "F"
+		}
+		set("Overwrite", overwrite)
+	}
+	if _, hasType := headers["Content-Type"]; !hasType && input.Body != "" && webdavMethods[method] {
+		set("Content-Type", "application/xml; charset=utf-8")
+	}
+	return headers, nil
+}
+
+// davMultistatus is the body of a 207 Multi-Status response (RFC 4918 §13).
+type davMultistatus struct {
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Hrefs       []string      `xml:"DAV: href"`
+	Status      string        `xml:"DAV: status"`
+	Propstats   []davPropstat `xml:"DAV: propstat"`
+	Description string        `xml:"DAV: responsedescription"`
+}
+
+type davPropstat struct {
+	Prop   davProp `xml:"DAV: prop"`
+	Status string  `xml:"DAV: status"`
+}
+
+type davProp struct {
+	Properties []davProperty `xml:",any"`
+}
+
+type davProperty struct {
+	XMLName  xml.Name
+	Text     string `xml:",chardata"`
+	Children []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// value shows a property's text, or the names of its child elements for
+// properties such as resourcetype (<collection/>).
+func (p davProperty) value() string {
+	if text := strings.TrimSpace(p.Text); text != "" {
+		return text
+	}
+	names := make([]string, len(p.Children))
+	for i, child := range p.Children {
+		names[i] = child.XMLName.Local
+	}
+	return strings.Join(names, ",")
+}
+
+// formatMultistatus renders a multistatus body as one line per resource: its
+// href, status and found properties as name=value (quoted when the value has
+// spaces), then the properties that failed, by status. It reports false for a body that is not a multistatus document.
+func formatMultistatus(body []byte) (string, bool) {
+	var multistatus davMultistatus
+	if err := xml.Unmarshal(body, &multistatus); err != nil || len(multistatus.Responses) == 0 {
+		return "", false
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d resources:", len(multistatus.Responses))
+	for _, response := range multistatus.Responses {
+		builder.WriteString("\n" + strings.Join(response.Hrefs, " "))
+		if response.Status != "" {
+			builder.WriteString(" " + davStatusCode(response.Status))
+		}
+		var failed []string
+		for _, propstat := range response.Propstats {
+			code := davStatusCode(propstat.Status)
+			if code != "200" {
+				names := make([]string, len(propstat.Prop.Properties))
+				for i, property := range propstat.Prop.Properties {
+					names[i] = property.XMLName.Local
+				}
+				failed = append(failed, fmt.Sprintf("%s: %s", code, strings.Join(names, ", ")))
+				continue
+			}
+			for _, property := range propstat.Prop.Properties {
+				value := property.value()
+				if strings.ContainsAny(value, " \t\r\n\"") {
+					value = strconv.Quote(value)
+				}
+				if value != "" {
+					fmt.Fprintf(&builder, " %s=%s", property.XMLName.Local, value)
+				}
+			}
+		}
+		if description := strings.TrimSpace(response.Description); description != "" {
+			fmt.Fprintf(&builder, " (%s)", description)
+		}
+		for _, line := range failed {
+			builder.WriteString("\n  " + line)
+		}
+	}
+	return builder.String(), true
+}
+
+// davStatusCode extracts the code from a status line such as "HTTP/1.1 404 Not Found".
+func davStatusCode(status string) string {
+	if fields := strings.Fields(status); len(fields) >= 2 {
+		return fields[1]
+	}
+	return status
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testMultistatus = `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+  <d:response>
+    <d:href>/dav/docs/</d:href>
+    <d:propstat>
+      <d:prop><d:displayname>Docs</d:displayname><d:resourcetype><d:collection/></d:resourcetype></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/dav/docs/report.pdf</d:href>
+    <d:propstat>
+      <d:prop><d:getcontentlength>1024</d:getcontentlength><d:getlastmodified>Mon, 12 Jan 1998 09:25:56 GMT</d:getlastmodified><d:resourcetype/></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+    <d:propstat>
+      <d:prop><cs:getctag/></d:prop>
+      <d:status>HTTP/1.1 404 Not Found</d:status>
+    </d:propstat>
+  </d:response>
+</d:multistatus>`
+
+func Test_formatMultistatus(t *testing.T) {
+	listing, ok := formatMultistatus([]byte(testMultistatus))
+	want := "2 resources:\n" +
+		"/dav/docs/ displayname=Docs resourcetype=collection\n" +
+		`/dav/docs/report.pdf getcontentlength=1024 getlastmodified="Mon, 12 Jan 1998 09:25:56 GMT"` + "\n" +
+		"  404: getctag"
+	if !ok || listing != want {
+		t.Errorf("formatMultistatus =\n%s\nwant\n%s", listing, want)
+	}
+	if _, ok := formatMultistatus([]byte(`{"items":[]}`)); ok {
+		t.Error("a JSON body is not a multistatus document")
+	}
+}
+
+func Test_HttpRequestHandler_WebDAV(t *testing.T) {
+	var gotMethod string
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotHeaders = r.Method, r.Header.Clone()
+		if r.Method == "PROPFIND" {
+			w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(testMultistatus))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	overwrite := false
+
+	tests := []struct {
+		name        string
+		input       HttpRequestInput
+		settings    Settings
+		want        string
+		wantHeaders map[string]string
+	}{
+		{"propfind listing in read-only mode", HttpRequestInput{Method: "propfind", URL: server.URL + "/dav/docs/", Depth: "1", Body: `<d:propfind xmlns:d="DAV:"><d:allprop/></d:propfind>`},
+			Settings{Methods: MethodPolicy{ReadOnly: true}}, "2 resources:\n/dav/docs/ displayname=Docs",
+			map[string]string{"Depth": "1", "Content-Type": "application/xml; charset=utf-8"}},
+		{"move with relative destination", HttpRequestInput{Method: "MOVE", URL: server.URL + "/dav/a.txt?v=1", Destination: "archive/a.txt", Overwrite: &overwrite},
+			Settings{}, "201 Created", map[string]string{"Destination": server.URL + "/dav/archive/a.txt", "Overwrite": "F"}},
+		{"mkcol", HttpRequestInput{Method: "MKCOL", URL: server.URL + "/dav/new/"}, Settings{}, "201 Created", nil},
+		{"destination needs copy or move", HttpRequestInput{Method: "PUT", URL: server.URL + "/a", Destination: "/b"}, Settings{}, "apply only to COPY and MOVE", nil},
+		{"invalid depth", HttpRequestInput{Method: "PROPFIND", URL: server.URL + "/a", Depth: "2"}, Settings{}, "unsupported depth: 2", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHeaders = nil
+			handler := makeHandler(newTestClient(server.URL), tt.settings, NewHistory(10), NewStats(), NewVariables())
+			result, _, _ := handler(context.Background(), nil, tt.input)
+			if text := extractText(result); !strings.Contains(text, tt.want) {
+				t.Fatalf("result = %s, want it to contain %q", text, tt.want)
+			}
+			for name, want := range tt.wantHeaders {
+				if got := gotHeaders.Get(name); got != want {
+					t.Errorf("%s %s = %q, want %q", gotMethod, name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/lexandro/rest-api-mcp/client"
+	"github.com/lexandro/rest-api-mcp/tools"
+)
+
+// problems checks the resolved options for mistakes that would otherwise only
+// show up as confusing request failures, e.g. a header missing its ": ".
+func (o *options) problems() []string {
+	var problems []string
+
+	if o.baseURL != "" {
+		if parsed, err := url.Parse(o.baseURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("--base-url %q is not an absolute URL (expected e.g. http://localhost:8080)", o.baseURL))
+		}
+	}
+	if o.proxy != "" {
+		parsed, err := url.Parse(o.proxy)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "socks5") {
+			problems = append(problems, fmt.Sprintf("--proxy %q is not a valid proxy URL (expected http://, https:// or socks5://host:port)", redactURL(o.proxy)))
+		}
+	}
+	for _, header := range o.defaultHeaders {
+		if idx := strings.Index(header, ": "); idx <= 0 {
+			problems = append(problems, fmt.Sprintf("--default-header %q is not in \"Key: Value\" format and would be ignored", header))
+		}
+	}
+	for _, query := range o.defaultQuery {
+		if idx := strings.Index(query, "="); idx <= 0 {
+			problems = append(problems, fmt.Sprintf("--default-query %q is not in \"key=value\" format and would be ignored", query))
+		}
+	}
+	if o.timeout < 0 || o.retryDelay < 0 {
+		problems = append(problems, "--timeout and --retry-delay must not be negative")
+	}
+	if o.retry < 0 {
+		problems = append(problems, "--retry must not be negative")
+	}
+	if o.spillThreshold < 0 {
+		problems = append(problems, "--spill-threshold must not be negative")
+	}
+	if o.spillDir != "" {
+		if info, err := os.Stat(o.spillDir); err != nil || !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("--spill-dir %q is not a directory", o.spillDir))
+		}
+	}
+	if err := client.ParseHTTPVersion(o.httpVersion); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid --http-version: %s", err))
+	}
+	if o.maxConcurrent < 0 {
+		problems = append(problems, "--max-concurrent-requests must not be negative")
+	}
+	if o.maxBandwidth < 0 {
+		problems = append(problems, "--max-bandwidth must not be negative")
+	}
+	if o.rateLimitReserve < 0 {
+		problems = append(problems, "--rate-limit-reserve must not be negative")
+	}
+	if o.coalesceWindow < 0 {
+		problems = append(problems, "--coalesce-window must not be negative")
+	}
+	if o.maxPaceWait <= 0 {
+		problems = append(problems, "--max-pace-wait must be positive")
+	}
+	if o.cacheTTL < 0 {
+		problems = append(problems, "--cache-ttl must not be negative")
+	}
+	if o.cacheTTL > 0 && o.cacheDir == "" {
+		problems = append(problems, "--cache-ttl has no effect without --cache-dir")
+	}
+	if o.redactResponseHeaders != "" && o.showResponseSecrets {
+		problems = append(problems, "--redact-response-headers has no effect with --unsafe-show-response-secrets")
+	}
+	if o.tokenBudget < 0 {
+		problems = append(problems, "--token-budget must not be negative")
+	}
+	if o.dnsCacheTTL < 0 || o.dnsCacheSize < 1 {
+		problems = append(problems, "--dns-cache-ttl must not be negative and --dns-cache-size must be at least 1")
+	}
+	if strings.ContainsAny(o.requestIDHeader, ": \t") {
+		problems = append(problems, fmt.Sprintf("--request-id-header %q must be a bare header name such as X-Request-Id", o.requestIDHeader))
+	}
+	if strings.ContainsAny(o.idempotencyKey, ": \t") {
+		problems = append(problems, fmt.Sprintf("--idempotency-key-header %q must be a bare header name such as Idempotency-Key", o.idempotencyKey))
+	}
+	if (o.s3AccessKeyID == "") != (o.s3SecretAccessKey == "") {
+		problems = append(problems, "--s3-access-key-id and --s3-secret-access-key must be set together")
+	}
+	if o.s3Endpoint != "" {
+		if parsed, err := url.Parse(o.s3Endpoint); err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			problems = append(problems, fmt.Sprintf("--s3-endpoint %q is not an http:// or https:// URL", o.s3Endpoint))
+		}
+	}
+	if o.recordDir != "" && o.replayDir != "" {
+		problems = append(problems, "--record and --replay are mutually exclusive")
+	}
+	if o.replayDir != "" {
+		if info, err := os.Stat(o.replayDir); err != nil || !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("--replay %q is not a directory of recorded cassettes", o.replayDir))
+		}
+	}
+	if _, err := client.ParseReplayMatch(o.replayMatch); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid --replay-match: %s", err))
+	}
+	if o.chaosLatency < 0 || o.chaosErrorRate < 0 || o.chaosErrorRate > 1 {
+		problems = append(problems, "--chaos-latency must not be negative and --chaos-error-rate must be between 0 and 1")
+	}
+	if o.chaosStatus != 0 && (o.chaosStatus < 400 || o.chaosStatus > 599) {
+		problems = append(problems, fmt.Sprintf("--chaos-error-status %d is not 0 or an error status (400-599)", o.chaosStatus))
+	}
+	if toolsets, err := tools.ParseToolsets(o.toolsets); err == nil {
+		if o.allowProfileSwitch && !toolsets.Enables("use_profile") {
+			problems = append(problems, "--allow-profile-switch has no effect without the core toolset")
+		}
+		if o.faultInjection && !toolsets.Enables("simulate_auth_expiry") {
+			problems = append(problems, "--enable-fault-injection has no effect without the testing toolset")
+		}
+	}
+	problems = append(problems, o.oauthProblems()...)
+	problems = append(problems, o.azureProblems()...)
+	problems = append(problems, o.googleProblems()...)
+	problems = append(problems, o.openAPIProblems()...)
+	for _, rule := range o.hostRules {
+		for name := range rule.DefaultHeaders {
+			if strings.TrimSpace(name) == "" {
+				problems = append(problems, fmt.Sprintf("hosts rule %q has a header with an empty name", rule.Match))
+			}
+		}
+	}
+
+	switch o.transport {
+	case "stdio", "http", "sse":
+	default:
+		problems = append(problems, fmt.Sprintf("--transport %q is not one of stdio, http, sse", o.transport))
+	}
+	if (o.tlsCertFile == "") != (o.tlsKeyFile == "") {
+		problems = append(problems, "--tls-cert and --tls-key must be given together")
+	}
+	for _, file := range []string{o.tlsCertFile, o.tlsKeyFile} {
+		if _, err := os.Stat(file); file != "" && err != nil {
+			problems = append(problems, fmt.Sprintf("TLS file: %s", err))
+		}
+	}
+	if o.transport == "stdio" && (o.authToken != "" || o.tlsCertFile != "" || o.allowedOrigins != "") {
+		problems = append(problems, "--auth-token, --tls-cert and --allowed-origins only apply to the http and sse transports")
+	}
+	for _, origin := range splitList(o.allowedOrigins) {
+		if parsed, err := url.Parse(origin); err != nil || parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+			problems = append(problems, fmt.Sprintf("--allowed-origins entry %q must be a scheme and host such as https://app.example.com", origin))
+		}
+	}
+
+	if _, err := o.toolSettings(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// redactURL hides the password in a URL's user info.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "(unparsable URL)"
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+
+	"github.com/lexandro/rest-api-mcp/selfupdate"
+)
+
+// runVersionCommand implements "version": the build's version, commit, Go
+// version and platform. Returns the process exit code.
+func runVersionCommand() int {
+	fmt.Print(selfupdate.CurrentBuild().String())
+	return 0
+}
+
+// runUpdateCommand implements "update": it looks up the latest GitHub
+// release and, when it is newer than this build, replaces the binary in
+// place, so client configurations that point at its absolute path pick it up
+// on their next start. args is os.Args[2:]. Returns the process exit code.
+func runUpdateCommand(args []string) int {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	check := fs.Bool("check", false, "Only report whether a newer release exists")
+	force := fs.Bool("force", false, "Install the latest release even if it is not newer, e.g. over a development build")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n  rest-api-mcp update [--check] [--force]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	build := selfupdate.CurrentBuild()
+	updater := selfupdate.Updater{}
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	switch {
+	case *force:
+	case !build.Released():
+		fmt.Printf("This is a development build (%s); the latest release is %s (%s).\nRun \"rest-api-mcp update --force\" to replace it with the release.\n", build.Version, release.Tag, release.URL)
+		return 0
+	case !selfupdate.Newer(release.Tag, build.Version):
+		fmt.Printf("rest-api-mcp %s is up to date.\n", build.Version)
+		return 0
+	}
+	if *check {
+		fmt.Printf("Update available: %s -> %s (%s)\nRun \"rest-api-mcp update\" to install it.\n", build.Version, release.Tag, release.URL)
+		return 0
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: locating the running binary: %s\n", err)
+		return 1
+	}
+	binary, err := updater.Download(ctx, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	if err := selfupdate.Replace(executable, binary); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	fmt.Printf("Updated %s from %s to %s.\nRestart your MCP clients to use the new version.\n", executable, build.Version, release.Tag)
+	return 0
+}